@@ -41,10 +41,15 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/flags"
 	queryApp "github.com/jaegertracing/jaeger/cmd/query/app"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/cmd/query/app/redaction"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
+	memoryConfig "github.com/jaegertracing/jaeger/pkg/memory/config"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	ss "github.com/jaegertracing/jaeger/plugin/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/plugin/storage"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/ports"
 	istorage "github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
@@ -120,14 +125,48 @@ by default uses only in-memory database.`,
 			cOpts := new(collectorApp.CollectorOptions).InitFromViper(v)
 			qOpts := new(queryApp.QueryOptions).InitFromViper(v, logger)
 
+			// the query service subscribes directly to the spans processed by the collector
+			var liveTail *livetail.Bus
+			if cOpts.LiveTailEnabled {
+				liveTail = livetail.NewBus(qOpts.LiveTailBufferSize)
+			}
+
+			var dependencyWriter dependencystore.Writer
+			if cOpts.DependenciesEnabled {
+				dependencyWriter, err = storageFactory.CreateDependencyWriter()
+				if err != nil {
+					logger.Fatal("Failed to create dependency writer", zap.Error(err))
+				}
+			}
+
+			// the query service reads the catalog tracked by the span writers of the collector
+			catalogReader, err := storageFactory.CreateCatalogReader()
+			if err == istorage.ErrCatalogNotSupported {
+				logger.Info("Service catalog not created", zap.String("reason", err.Error()))
+			} else if err != nil {
+				logger.Fatal("Failed to create service catalog reader", zap.Error(err))
+			}
+
+			// the query service reads the span metrics computed by the collector
+			var spanMetrics *spanmetrics.Aggregator
+			if cOpts.SpanMetricsEnabled {
+				spanMetrics = spanmetrics.NewAggregator(
+					metricsFactory.Namespace(metrics.NSOptions{Name: "span_metrics"}),
+					cOpts.SpanMetrics)
+			}
+
 			// collector
 			c := collectorApp.New(&collectorApp.CollectorParams{
-				ServiceName:    "jaeger-collector",
-				Logger:         logger,
-				MetricsFactory: metricsFactory,
-				SpanWriter:     spanWriter,
-				StrategyStore:  strategyStore,
-				HealthCheck:    svc.HC(),
+				ServiceName:      "jaeger-collector",
+				Logger:           logger,
+				MetricsFactory:   metricsFactory,
+				SpanWriter:       spanWriter,
+				DependencyWriter: dependencyWriter,
+				StrategyStore:    strategyStore,
+				HealthCheck:      svc.HC(),
+				LiveTail:         liveTail,
+				Catalog:          catalogReader,
+				SpanMetrics:      spanMetrics,
 			})
 			c.Start(cOpts)
 
@@ -141,8 +180,20 @@ by default uses only in-memory database.`,
 			agent := startAgent(cp, aOpts, logger, metricsFactory)
 
 			// query
+			queryServiceOptions := archiveOptions(storageFactory, logger)
+			importOptions(queryServiceOptions, qOpts, storageFactory, logger)
+			redactionOptions(queryServiceOptions, qOpts, logger)
+			adjusterOptions(queryServiceOptions, qOpts, logger)
+			queryServiceOptions.TraceLimits = qOpts.TraceLimits
+			if liveTail != nil {
+				queryServiceOptions.LiveTail = liveTail
+			}
+			queryServiceOptions.Catalog = catalogReader
+			if spanMetrics != nil {
+				queryServiceOptions.SpanMetrics = spanMetrics
+			}
 			querySrv := startQuery(
-				svc, qOpts, archiveOptions(storageFactory, logger),
+				svc, qOpts, queryServiceOptions,
 				spanReader, dependencyReader,
 				rootMetricsFactory, metricsFactory,
 			)
@@ -234,6 +285,38 @@ func archiveOptions(storageFactory istorage.Factory, logger *zap.Logger) *querys
 	return opts
 }
 
+func importOptions(opts *querysvc.QueryServiceOptions, qOpts *queryApp.QueryOptions, storageFactory istorage.Factory, logger *zap.Logger) {
+	if !qOpts.ImportEnabled {
+		return
+	}
+	uploadStore := memory.WithConfiguration(memoryConfig.Configuration{MaxTraces: qOpts.ImportMaxTraces})
+	if !opts.InitImport(storageFactory, uploadStore, qOpts.ImportStorageWrites, logger) {
+		logger.Info("Trace import not initialized")
+	}
+}
+
+func redactionOptions(opts *querysvc.QueryServiceOptions, qOpts *queryApp.QueryOptions, logger *zap.Logger) {
+	if qOpts.RedactionRules == "" {
+		return
+	}
+	options := redaction.Options{
+		IdentityHeader:    qOpts.RedactionIdentityHeader,
+		TrustGRPCIdentity: qOpts.RedactionTrustGRPCIdentity,
+	}
+	if err := opts.InitRedaction(qOpts.RedactionRules, qOpts.RedactionHashKeyFile, options); err != nil {
+		logger.Fatal("Failed to initialize redaction", zap.Error(err))
+	}
+}
+
+func adjusterOptions(opts *querysvc.QueryServiceOptions, qOpts *queryApp.QueryOptions, logger *zap.Logger) {
+	if qOpts.Adjusters == nil {
+		return
+	}
+	if err := opts.InitAdjusters(qOpts.Adjusters); err != nil {
+		logger.Fatal("Failed to initialize adjusters", zap.Error(err))
+	}
+}
+
 func initTracer(metricsFactory metrics.Factory, logger *zap.Logger) io.Closer {
 	traceCfg := &jaegerClientConfig.Configuration{
 		ServiceName: "jaeger-query",
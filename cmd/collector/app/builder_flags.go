@@ -17,11 +17,15 @@ package app
 
 import (
 	"flag"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
 	"github.com/jaegertracing/jaeger/cmd/flags"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
 	"github.com/jaegertracing/jaeger/ports"
 )
 
@@ -36,6 +40,15 @@ const (
 	collectorZipkinHTTPort        = "collector.zipkin.http-port"
 	collectorZipkinAllowedOrigins = "collector.zipkin.allowed-origins"
 	collectorZipkinAllowedHeaders = "collector.zipkin.allowed-headers"
+	collectorDependencies         = "collector.dependencies.enabled"
+	collectorDependenciesFlush    = "collector.dependencies.flush-interval"
+	collectorDependenciesWindow   = "collector.dependencies.match-window"
+	collectorLiveTail             = "collector.live-tail.enabled"
+	collectorSpanMetrics          = "collector.span-metrics.enabled"
+	collectorSpanMetricsMaxOps    = "collector.span-metrics.max-operations"
+	collectorSpanMetricsMaxSeries = "collector.span-metrics.max-series"
+	collectorSpanMetricsDims      = "collector.span-metrics.dimensions"
+	collectorSpanMetricsMaxValues = "collector.span-metrics.max-dimension-values"
 )
 
 var tlsFlagsConfig = tlscfg.ServerFlagsConfig{
@@ -68,6 +81,18 @@ type CollectorOptions struct {
 	CollectorZipkinAllowedOrigins string
 	// CollectorZipkinAllowedHeaders is a list of headers that the Zipkin collector service allowes the client to use with cross-domain requests
 	CollectorZipkinAllowedHeaders string
+	// DependenciesEnabled activates the aggregation of the dependencies between services from the spans
+	DependenciesEnabled bool
+	// DependenciesFlushInterval is the period at which the aggregated dependencies are written
+	DependenciesFlushInterval time.Duration
+	// DependenciesMatchWindow is how long a span waits for its parent or children to be matched
+	DependenciesMatchWindow time.Duration
+	// LiveTailEnabled activates the publication of the received spans to the subscribers of the gRPC SpanTailService
+	LiveTailEnabled bool
+	// SpanMetricsEnabled activates the computation of the RED metrics of the received spans
+	SpanMetricsEnabled bool
+	// SpanMetrics configures the computation of the RED metrics of the received spans
+	SpanMetrics spanmetrics.Options
 }
 
 // AddFlags adds flags for CollectorOptions
@@ -82,6 +107,15 @@ func AddFlags(flags *flag.FlagSet) {
 	flags.Int(collectorZipkinHTTPort, 0, "The HTTP port for the Zipkin collector service e.g. 9411")
 	flags.String(collectorZipkinAllowedOrigins, "*", "Comma separated list of allowed origins for the Zipkin collector service, default accepts all")
 	flags.String(collectorZipkinAllowedHeaders, "content-type", "Comma separated list of allowed headers for the Zipkin collector service, default content-type")
+	flags.Bool(collectorDependencies, false, "Derive the dependencies between services from the spans and write them to the dependency storage, instead of running an external job")
+	flags.Duration(collectorDependenciesFlush, dependencies.DefaultFlushInterval, "The period at which the aggregated dependencies are written, i.e. the time bucket of their call counts")
+	flags.Duration(collectorDependenciesWindow, dependencies.DefaultMatchWindow, "How long a span is kept to be matched with its parent or children; calls whose spans arrive further apart are not counted")
+	flags.Bool(collectorLiveTail, false, "Serve the received spans to the live tail subscribers of the gRPC server, e.g. the query service; any client of the gRPC port can then read all the spans")
+	flags.Bool(collectorSpanMetrics, false, "Compute the request rate, error rate and latency of the received spans per service, operation and span kind, and report them as metrics")
+	flags.Int(collectorSpanMetricsMaxOps, spanmetrics.DefaultMaxOperations, "The number of distinct operations per service with their own span metrics; the others are reported as "+spanmetrics.OtherOperations)
+	flags.Int(collectorSpanMetricsMaxSeries, spanmetrics.DefaultMaxSeries, "The number of distinct span metrics series per service; the spans of the others are reported as "+spanmetrics.OtherOperations)
+	flags.String(collectorSpanMetricsDims, "", "Comma separated list of span or process tag keys whose values are added as tags to the span metrics")
+	flags.Int(collectorSpanMetricsMaxValues, spanmetrics.DefaultMaxDimensionValues, "The number of distinct values per dimension and service of the span metrics; the others are reported as "+spanmetrics.OtherValue)
 	tlsFlagsConfig.AddFlags(flags)
 }
 
@@ -97,6 +131,26 @@ func (cOpts *CollectorOptions) InitFromViper(v *viper.Viper) *CollectorOptions {
 	cOpts.CollectorZipkinHTTPPort = v.GetInt(collectorZipkinHTTPort)
 	cOpts.CollectorZipkinAllowedOrigins = v.GetString(collectorZipkinAllowedOrigins)
 	cOpts.CollectorZipkinAllowedHeaders = v.GetString(collectorZipkinAllowedHeaders)
+	cOpts.DependenciesEnabled = v.GetBool(collectorDependencies)
+	cOpts.DependenciesFlushInterval = v.GetDuration(collectorDependenciesFlush)
+	cOpts.DependenciesMatchWindow = v.GetDuration(collectorDependenciesWindow)
+	cOpts.LiveTailEnabled = v.GetBool(collectorLiveTail)
+	cOpts.SpanMetricsEnabled = v.GetBool(collectorSpanMetrics)
+	cOpts.SpanMetrics.MaxOperations = v.GetInt(collectorSpanMetricsMaxOps)
+	cOpts.SpanMetrics.MaxSeries = v.GetInt(collectorSpanMetricsMaxSeries)
+	cOpts.SpanMetrics.Dimensions = splitNonEmpty(v.GetString(collectorSpanMetricsDims))
+	cOpts.SpanMetrics.MaxDimensionValues = v.GetInt(collectorSpanMetricsMaxValues)
 	cOpts.TLS = tlsFlagsConfig.InitFromViper(v)
 	return cOpts
 }
+
+// splitNonEmpty splits a comma separated list, ignoring blank elements.
+func splitNonEmpty(list string) []string {
+	var values []string
+	for _, v := range strings.Split(list, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
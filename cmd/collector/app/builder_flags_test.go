@@ -0,0 +1,61 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
+)
+
+func TestCollectorLiveTailFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{})
+	assert.False(t, new(CollectorOptions).InitFromViper(v).LiveTailEnabled)
+
+	command.ParseFlags([]string{"--collector.live-tail.enabled=true"})
+	assert.True(t, new(CollectorOptions).InitFromViper(v).LiveTailEnabled)
+}
+
+func TestCollectorSpanMetricsFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{})
+	cOpts := new(CollectorOptions).InitFromViper(v)
+	assert.False(t, cOpts.SpanMetricsEnabled)
+	assert.Equal(t, spanmetrics.Options{
+		MaxOperations:      spanmetrics.DefaultMaxOperations,
+		MaxSeries:          spanmetrics.DefaultMaxSeries,
+		MaxDimensionValues: spanmetrics.DefaultMaxDimensionValues,
+	}, cOpts.SpanMetrics)
+
+	command.ParseFlags([]string{
+		"--collector.span-metrics.enabled=true",
+		"--collector.span-metrics.max-operations=10",
+		"--collector.span-metrics.dimensions=http.method, ,region",
+		"--collector.span-metrics.max-series=100",
+		"--collector.span-metrics.max-dimension-values=20",
+	})
+	cOpts = new(CollectorOptions).InitFromViper(v)
+	assert.True(t, cOpts.SpanMetricsEnabled)
+	assert.Equal(t, spanmetrics.Options{
+		MaxOperations:      10,
+		MaxSeries:          100,
+		Dimensions:         []string{"http.method", "region"},
+		MaxDimensionValues: 20,
+	}, cOpts.SpanMetrics)
+}
@@ -25,11 +25,15 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/server"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -40,10 +44,15 @@ type Collector struct {
 	logger         *zap.Logger
 	metricsFactory metrics.Factory
 	spanWriter     spanstore.Writer
+	depWriter      dependencystore.Writer
 	strategyStore  strategystore.StrategyStore
 	hCheck         *healthcheck.HealthCheck
 	spanProcessor  processor.SpanProcessor
 	spanHandlers   *SpanHandlers
+	liveTail       *livetail.Bus
+	dependencies   *dependencies.Aggregator
+	catalog        spanstore.CatalogReader
+	spanMetrics    *spanmetrics.Aggregator
 
 	// state, read only
 	hServer    *http.Server
@@ -60,6 +69,19 @@ type CollectorParams struct {
 	SpanWriter     spanstore.Writer
 	StrategyStore  strategystore.StrategyStore
 	HealthCheck    *healthcheck.HealthCheck
+	// DependencyWriter stores the dependencies between services derived from the spans,
+	// if enabled by CollectorOptions.DependenciesEnabled.
+	DependencyWriter dependencystore.Writer
+	// LiveTail is the bus onto which the processed spans are published for live tail subscribers,
+	// if enabled by CollectorOptions.LiveTailEnabled.
+	// If nil, the collector creates one, which is only available through its gRPC server.
+	LiveTail *livetail.Bus
+	// Catalog returns the activity of the services whose spans were written by SpanWriter.
+	// If not nil, it is served by the gRPC server.
+	Catalog spanstore.CatalogReader
+	// SpanMetrics computes the RED metrics of the received spans, if enabled by CollectorOptions.SpanMetricsEnabled.
+	// If nil, the collector creates one, which is only available through its gRPC server.
+	SpanMetrics *spanmetrics.Aggregator
 }
 
 // New constructs a new collector component, ready to be started
@@ -69,18 +91,53 @@ func New(params *CollectorParams) *Collector {
 		logger:         params.Logger,
 		metricsFactory: params.MetricsFactory,
 		spanWriter:     params.SpanWriter,
+		depWriter:      params.DependencyWriter,
 		strategyStore:  params.StrategyStore,
 		hCheck:         params.HealthCheck,
+		liveTail:       params.LiveTail,
+		catalog:        params.Catalog,
+		spanMetrics:    params.SpanMetrics,
 	}
 }
 
 // Start the component and underlying dependencies
 func (c *Collector) Start(builderOpts *CollectorOptions) error {
+	var liveTailSource livetail.Source
+	if builderOpts.LiveTailEnabled {
+		if c.liveTail == nil {
+			c.liveTail = livetail.NewBus(livetail.DefaultBufferSize)
+		}
+		liveTailSource = c.liveTail
+	} else {
+		c.liveTail = nil
+	}
+	if builderOpts.DependenciesEnabled && c.depWriter != nil {
+		c.dependencies = dependencies.NewAggregator(
+			c.depWriter,
+			c.logger,
+			builderOpts.DependenciesMatchWindow,
+			builderOpts.DependenciesFlushInterval)
+		c.dependencies.Start()
+	}
+	var spanMetricsReader spanmetrics.Reader
+	if builderOpts.SpanMetricsEnabled {
+		if c.spanMetrics == nil {
+			c.spanMetrics = spanmetrics.NewAggregator(
+				c.metricsFactory.Namespace(metrics.NSOptions{Name: "span_metrics"}),
+				builderOpts.SpanMetrics)
+		}
+		spanMetricsReader = c.spanMetrics
+	} else {
+		c.spanMetrics = nil
+	}
 	handlerBuilder := &SpanHandlerBuilder{
 		SpanWriter:     c.spanWriter,
 		CollectorOpts:  *builderOpts,
 		Logger:         c.logger,
 		MetricsFactory: c.metricsFactory,
+		LiveTail:       c.liveTail,
+		Dependencies:   c.dependencies,
+		SpanMetrics:    c.spanMetrics,
 	}
 
 	c.spanProcessor = handlerBuilder.BuildSpanProcessor()
@@ -105,6 +162,9 @@ func (c *Collector) Start(builderOpts *CollectorOptions) error {
 		Handler:       c.spanHandlers.GRPCHandler,
 		TLSConfig:     builderOpts.TLS,
 		SamplingStore: c.strategyStore,
+		LiveTail:      liveTailSource,
+		Catalog:       c.catalog,
+		SpanMetrics:   spanMetricsReader,
 		Logger:        c.logger,
 	}); err != nil {
 		c.logger.Fatal("could not start gRPC collector", zap.Error(err))
@@ -178,6 +238,13 @@ func (c *Collector) Close() error {
 		c.logger.Error("failed to close span processor.", zap.Error(err))
 	}
 
+	// the last call counts are written once the span processor is closed
+	if c.dependencies != nil {
+		if err := c.dependencies.Close(); err != nil {
+			c.logger.Error("failed to close dependency aggregator", zap.Error(err))
+		}
+	}
+
 	// the span processor is closed
 	if c.spanWriter != nil {
 		if closer, ok := c.spanWriter.(io.Closer); ok {
@@ -15,6 +15,7 @@
 package app
 
 import (
+	"context"
 	"io"
 	"testing"
 	"time"
@@ -23,7 +24,10 @@ import (
 	"github.com/uber/jaeger-lib/metrics/metricstest"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
 )
 
@@ -54,6 +58,95 @@ func TestNewCollector(t *testing.T) {
 	assert.NoError(t, c.Close())
 }
 
+func TestCollectorDependencies(t *testing.T) {
+	store := memory.NewStore()
+	c := New(&CollectorParams{
+		ServiceName:      "collector",
+		Logger:           zap.NewNop(),
+		MetricsFactory:   metricstest.NewFactory(time.Hour),
+		SpanWriter:       store,
+		DependencyWriter: store,
+		StrategyStore:    &mockStrategyStore{},
+		HealthCheck:      healthcheck.New(),
+	})
+	c.Start(&CollectorOptions{QueueSize: 10, NumWorkers: 1, DependenciesEnabled: true})
+	assert.NotNil(t, c.dependencies)
+
+	traceID := model.NewTraceID(0, 1)
+	_, err := c.spanProcessor.ProcessSpans([]*model.Span{
+		{TraceID: traceID, SpanID: 1, Process: model.NewProcess("frontend", nil), StartTime: time.Now()},
+		{TraceID: traceID, SpanID: 2, Process: model.NewProcess("backend", nil), StartTime: time.Now(),
+			References: []model.SpanRef{model.NewChildOfRef(traceID, 1)}},
+	}, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	assert.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		if trace, _ := store.GetTrace(context.Background(), traceID); trace != nil && len(trace.Spans) == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// closing the collector writes the aggregated dependencies
+	assert.NoError(t, c.Close())
+	links, err := store.GetDependencies(time.Now(), time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.DependencyLink{{Parent: "frontend", Child: "backend", CallCount: 1}}, links)
+}
+
+func TestCollectorLiveTail(t *testing.T) {
+	newCollector := func() *Collector {
+		return New(&CollectorParams{
+			ServiceName:    "collector",
+			Logger:         zap.NewNop(),
+			MetricsFactory: metricstest.NewFactory(time.Hour),
+			SpanWriter:     &fakeSpanWriter{},
+			StrategyStore:  &mockStrategyStore{},
+			HealthCheck:    healthcheck.New(),
+		})
+	}
+	c := newCollector()
+	c.Start(&CollectorOptions{QueueSize: 10, NumWorkers: 1})
+	assert.Nil(t, c.liveTail)
+	assert.NoError(t, c.Close())
+
+	c = newCollector()
+	c.Start(&CollectorOptions{QueueSize: 10, NumWorkers: 1, LiveTailEnabled: true})
+	assert.NotNil(t, c.liveTail)
+	assert.NoError(t, c.Close())
+}
+
+func TestCollectorSpanMetrics(t *testing.T) {
+	baseMetrics := metricstest.NewFactory(time.Hour)
+	c := New(&CollectorParams{
+		ServiceName:    "collector",
+		Logger:         zap.NewNop(),
+		MetricsFactory: baseMetrics,
+		SpanWriter:     &fakeSpanWriter{},
+		StrategyStore:  &mockStrategyStore{},
+		HealthCheck:    healthcheck.New(),
+	})
+	c.Start(&CollectorOptions{QueueSize: 10, NumWorkers: 1, SpanMetricsEnabled: true})
+	defer c.Close()
+	assert.NotNil(t, c.spanMetrics)
+
+	_, err := c.spanProcessor.ProcessSpans([]*model.Span{
+		{OperationName: "GET /", Process: model.NewProcess("frontend", nil)},
+	}, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	assert.NoError(t, err)
+	// the spans are counted when they are processed from the queue
+	for i := 0; i < 100; i++ {
+		if counters, _ := baseMetrics.Snapshot(); counters["span_metrics.calls|operation=GET /|service=frontend|span_kind="] > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	baseMetrics.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name:  "span_metrics.calls",
+		Tags:  map[string]string{"service": "frontend", "operation": "GET /", "span_kind": ""},
+		Value: 1,
+	})
+}
+
 type mockStrategyStore struct {
 }
 
@@ -0,0 +1,232 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencies
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+)
+
+const (
+	// DefaultFlushInterval is the default time bucket of the aggregated call counts.
+	DefaultFlushInterval = time.Minute
+	// DefaultMatchWindow is the default time during which a span waits for its parent or children.
+	DefaultMatchWindow = 30 * time.Second
+)
+
+// spanKey identifies a span across traces.
+type spanKey struct {
+	traceID model.TraceID
+	spanID  model.SpanID
+}
+
+// linkKey identifies a dependency link.
+type linkKey struct {
+	parent string
+	child  string
+}
+
+// generation holds the spans seen during a match window.
+type generation struct {
+	// services are the service names of the seen spans
+	services map[spanKey]string
+	// orphans are the service names of the seen spans whose parent was not seen yet, by parent
+	orphans map[spanKey][]string
+}
+
+func newGeneration() generation {
+	return generation{
+		services: make(map[spanKey]string),
+		orphans:  make(map[spanKey][]string),
+	}
+}
+
+// Aggregator derives the dependency links between services from the spans passing through the collector.
+// A call from a parent service to a child service is counted when a span and its parent, in different
+// services, are both seen within the match window, in either order. The call counts are aggregated
+// per time bucket of one flush interval and written at the end of each bucket with the bucket start
+// as timestamp.
+//
+// Spans are remembered for one to two match windows, so a collector keeps in memory the IDs of the spans
+// it received during that time. Calls whose parent and child spans are sent to different collectors
+// are not counted.
+type Aggregator struct {
+	writer        dependencystore.Writer
+	logger        *zap.Logger
+	matchWindow   time.Duration
+	flushInterval time.Duration
+	now           func() time.Time
+
+	mux sync.Mutex
+	// generations are the current and the previous generation of seen spans
+	generations [2]generation
+	rotatedAt   time.Time
+	bucketStart time.Time
+	counts      map[linkKey]uint64
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewAggregator creates an Aggregator that writes the dependency links to the writer.
+// Non-positive durations are replaced by their defaults.
+func NewAggregator(writer dependencystore.Writer, logger *zap.Logger, matchWindow, flushInterval time.Duration) *Aggregator {
+	if matchWindow <= 0 {
+		matchWindow = DefaultMatchWindow
+	}
+	if flushInterval <= 0 {
+		flushInterval = DefaultFlushInterval
+	}
+	return newAggregator(writer, logger, matchWindow, flushInterval, time.Now)
+}
+
+func newAggregator(writer dependencystore.Writer, logger *zap.Logger, matchWindow, flushInterval time.Duration, now func() time.Time) *Aggregator {
+	start := now()
+	return &Aggregator{
+		writer:        writer,
+		logger:        logger,
+		matchWindow:   matchWindow,
+		flushInterval: flushInterval,
+		now:           now,
+		generations:   [2]generation{newGeneration(), newGeneration()},
+		rotatedAt:     start,
+		bucketStart:   start,
+		counts:        make(map[linkKey]uint64),
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+}
+
+// Start writes the aggregated dependency links every flush interval, until Close is called.
+func (a *Aggregator) Start() {
+	go func() {
+		defer close(a.doneCh)
+		ticker := time.NewTicker(a.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				a.flush()
+			case <-a.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the periodic writes and writes the call counts of the current bucket.
+func (a *Aggregator) Close() error {
+	close(a.stopCh)
+	<-a.doneCh
+	a.flush()
+	return nil
+}
+
+// ProcessSpan matches the span with its parent and with its children seen within the match window.
+func (a *Aggregator) ProcessSpan(span *model.Span) {
+	if span.Process == nil {
+		return
+	}
+	service := span.Process.ServiceName
+	key := spanKey{traceID: span.TraceID, spanID: span.SpanID}
+	parentKey := spanKey{traceID: span.TraceID, spanID: span.ParentSpanID()}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.rotate()
+	current := &a.generations[0]
+	current.services[key] = service
+	if parentKey.spanID != 0 {
+		if parentService, ok := a.lookup(parentKey); ok {
+			a.count(parentService, service)
+		} else {
+			current.orphans[parentKey] = append(current.orphans[parentKey], service)
+		}
+	}
+	for i := range a.generations {
+		orphans := a.generations[i].orphans
+		for _, childService := range orphans[key] {
+			a.count(service, childService)
+		}
+		delete(orphans, key)
+	}
+}
+
+// rotate starts a new generation of seen spans once the current one is older than the match window.
+func (a *Aggregator) rotate() {
+	now := a.now()
+	if now.Sub(a.rotatedAt) < a.matchWindow {
+		return
+	}
+	a.generations[1] = a.generations[0]
+	if now.Sub(a.rotatedAt) >= 2*a.matchWindow {
+		// the current generation is also too old
+		a.generations[1] = newGeneration()
+	}
+	a.generations[0] = newGeneration()
+	a.rotatedAt = now
+}
+
+func (a *Aggregator) lookup(key spanKey) (string, bool) {
+	for i := range a.generations {
+		if service, ok := a.generations[i].services[key]; ok {
+			return service, true
+		}
+	}
+	return "", false
+}
+
+func (a *Aggregator) count(parent, child string) {
+	if parent == child {
+		return
+	}
+	a.counts[linkKey{parent: parent, child: child}]++
+}
+
+// flush writes the call counts of the current bucket and starts a new one.
+func (a *Aggregator) flush() {
+	a.mux.Lock()
+	counts, ts := a.counts, a.bucketStart
+	a.counts = make(map[linkKey]uint64)
+	a.bucketStart = a.now()
+	a.mux.Unlock()
+
+	if len(counts) == 0 {
+		return
+	}
+	links := make([]model.DependencyLink, 0, len(counts))
+	for key, callCount := range counts {
+		links = append(links, model.DependencyLink{
+			Parent:    key.parent,
+			Child:     key.child,
+			CallCount: callCount,
+		})
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Parent != links[j].Parent {
+			return links[i].Parent < links[j].Parent
+		}
+		return links[i].Child < links[j].Child
+	})
+	if err := a.writer.WriteDependencies(ts, links); err != nil {
+		a.logger.Error("Failed to write dependencies", zap.Time("ts", ts), zap.Error(err))
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencies
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+type fakeWriter struct {
+	sync.Mutex
+	ts    []time.Time
+	links [][]model.DependencyLink
+	err   error
+}
+
+func (w *fakeWriter) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
+	w.Lock()
+	defer w.Unlock()
+	w.ts = append(w.ts, ts)
+	w.links = append(w.links, dependencies)
+	return w.err
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func span(traceID uint64, spanID, parentID uint64, service string) *model.Span {
+	s := &model.Span{
+		TraceID: model.NewTraceID(0, traceID),
+		SpanID:  model.NewSpanID(spanID),
+		Process: model.NewProcess(service, nil),
+	}
+	if parentID != 0 {
+		s.References = []model.SpanRef{model.NewChildOfRef(s.TraceID, model.NewSpanID(parentID))}
+	}
+	return s
+}
+
+func TestAggregatorMatchesParentsAndChildren(t *testing.T) {
+	writer := &fakeWriter{}
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	a := newAggregator(writer, zap.NewNop(), time.Second, time.Minute, clock.Now)
+
+	// parent before child
+	a.ProcessSpan(span(1, 1, 0, "frontend"))
+	a.ProcessSpan(span(1, 2, 1, "backend"))
+	// children before parent
+	a.ProcessSpan(span(2, 2, 1, "backend"))
+	a.ProcessSpan(span(2, 3, 2, "db"))
+	a.ProcessSpan(span(2, 1, 0, "frontend"))
+	// calls within a service are not dependencies
+	a.ProcessSpan(span(1, 3, 2, "backend"))
+	// a span without process is ignored
+	a.ProcessSpan(&model.Span{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(4)})
+
+	a.flush()
+	assert.Equal(t, []time.Time{time.Unix(1000, 0)}, writer.ts)
+	assert.Equal(t, [][]model.DependencyLink{{
+		{Parent: "backend", Child: "db", CallCount: 1},
+		{Parent: "frontend", Child: "backend", CallCount: 2},
+	}}, writer.links)
+
+	// each flush starts a new bucket, and empty buckets are not written
+	clock.Add(time.Minute)
+	a.flush()
+	assert.Len(t, writer.ts, 1)
+	a.ProcessSpan(span(3, 1, 0, "backend"))
+	a.ProcessSpan(span(3, 2, 1, "db"))
+	clock.Add(time.Minute)
+	a.flush()
+	assert.Equal(t, []time.Time{time.Unix(1000, 0), time.Unix(1060, 0)}, writer.ts)
+	assert.Equal(t, []model.DependencyLink{{Parent: "backend", Child: "db", CallCount: 1}}, writer.links[1])
+}
+
+func TestAggregatorMatchWindow(t *testing.T) {
+	writer := &fakeWriter{}
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	a := newAggregator(writer, zap.NewNop(), time.Second, time.Minute, clock.Now)
+
+	// the parent is still remembered in the previous generation
+	a.ProcessSpan(span(1, 1, 0, "frontend"))
+	clock.Add(1500 * time.Millisecond)
+	a.ProcessSpan(span(1, 2, 1, "backend"))
+	// the orphan is still remembered in the previous generation
+	a.ProcessSpan(span(2, 2, 1, "backend"))
+	clock.Add(time.Second)
+	a.ProcessSpan(span(2, 1, 0, "frontend"))
+	// the parent is forgotten after two windows
+	a.ProcessSpan(span(3, 1, 0, "frontend"))
+	clock.Add(3 * time.Second)
+	a.ProcessSpan(span(3, 2, 1, "backend"))
+
+	a.flush()
+	assert.Equal(t, [][]model.DependencyLink{{
+		{Parent: "frontend", Child: "backend", CallCount: 2},
+	}}, writer.links)
+}
+
+func TestAggregatorStartClose(t *testing.T) {
+	writer := &fakeWriter{err: errors.New("write error")}
+	a := NewAggregator(writer, zap.NewNop(), 0, time.Millisecond)
+	assert.Equal(t, DefaultMatchWindow, a.matchWindow)
+	a.Start()
+	a.ProcessSpan(span(1, 1, 0, "frontend"))
+	a.ProcessSpan(span(1, 2, 1, "backend"))
+	for i := 0; i < 100; i++ {
+		writer.Lock()
+		written := len(writer.links)
+		writer.Unlock()
+		if written > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	a.ProcessSpan(span(2, 1, 0, "frontend"))
+	a.ProcessSpan(span(2, 2, 1, "backend"))
+	assert.NoError(t, a.Close())
+
+	// the second call is written either by the loop or by Close
+	writer.Lock()
+	defer writer.Unlock()
+	var calls uint64
+	for _, links := range writer.links {
+		for _, link := range links {
+			calls += link.CallCount
+		}
+	}
+	assert.EqualValues(t, 2, calls)
+}
+
+func TestNewAggregatorDefaults(t *testing.T) {
+	a := NewAggregator(&fakeWriter{}, zap.NewNop(), 0, 0)
+	assert.Equal(t, DefaultMatchWindow, a.matchWindow)
+	assert.Equal(t, DefaultFlushInterval, a.flushInterval)
+}
@@ -19,9 +19,12 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
 )
 
 const (
@@ -47,6 +50,9 @@ type options struct {
 	reportBusy         bool
 	extraFormatTypes   []processor.SpanFormat
 	collectorTags      map[string]string
+	liveTail           *livetail.Bus
+	dependencies       *dependencies.Aggregator
+	spanMetrics        *spanmetrics.Aggregator
 }
 
 // Option is a function that sets some option on StorageBuilder.
@@ -160,6 +166,27 @@ func (options) CollectorTags(extraTags map[string]string) Option {
 	}
 }
 
+// LiveTail creates an Option that initializes the bus onto which the saved spans are published
+func (options) LiveTail(liveTail *livetail.Bus) Option {
+	return func(b *options) {
+		b.liveTail = liveTail
+	}
+}
+
+// Dependencies creates an Option that initializes the aggregator of the dependencies between services
+func (options) Dependencies(aggregator *dependencies.Aggregator) Option {
+	return func(b *options) {
+		b.dependencies = aggregator
+	}
+}
+
+// SpanMetrics creates an Option that initializes the aggregator of the RED metrics of the received spans
+func (options) SpanMetrics(aggregator *spanmetrics.Aggregator) Option {
+	return func(b *options) {
+		b.spanMetrics = aggregator
+	}
+}
+
 func (o options) apply(opts ...Option) options {
 	ret := options{}
 	for _, opt := range opts {
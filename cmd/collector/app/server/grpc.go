@@ -29,8 +29,12 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/pkg/catalog"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 // GRPCServerParams to construct a new Jaeger Collector gRPC Server
@@ -39,6 +43,9 @@ type GRPCServerParams struct {
 	Port          int
 	Handler       *handler.GRPCHandler
 	SamplingStore strategystore.StrategyStore
+	LiveTail      livetail.Source
+	Catalog       spanstore.CatalogReader
+	SpanMetrics   spanmetrics.Reader
 	Logger        *zap.Logger
 	OnError       func(error)
 }
@@ -78,6 +85,15 @@ func StartGRPCServer(params *GRPCServerParams) (*grpc.Server, error) {
 func serveGRPC(server *grpc.Server, listener net.Listener, params *GRPCServerParams) error {
 	api_v2.RegisterCollectorServiceServer(server, params.Handler)
 	api_v2.RegisterSamplingManagerServer(server, sampling.NewGRPCHandler(params.SamplingStore))
+	if params.LiveTail != nil {
+		api_v2.RegisterSpanTailServiceServer(server, livetail.NewGRPCHandler(params.LiveTail))
+	}
+	if params.Catalog != nil {
+		api_v2.RegisterServiceCatalogServiceServer(server, catalog.NewGRPCHandler(params.Catalog))
+	}
+	if params.SpanMetrics != nil {
+		api_v2.RegisterSpanMetricsServiceServer(server, spanmetrics.NewGRPCHandler(params.SpanMetrics))
+	}
 
 	params.Logger.Info("Starting jaeger-collector gRPC server", zap.Int("grpc-port", params.Port))
 	go func(server *grpc.Server) {
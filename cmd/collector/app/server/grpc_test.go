@@ -22,6 +22,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
@@ -29,6 +30,9 @@ import (
 	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 )
 
@@ -92,3 +96,66 @@ func TestSpanCollector(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, response)
 }
+
+func TestServiceCatalog(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	store := memory.NewStore()
+	require.NoError(t, store.WriteSpan(&model.Span{OperationName: "GET /", Process: model.NewProcess("frontend", nil)}))
+	params := &GRPCServerParams{
+		Handler:       handler.NewGRPCHandler(logger, &mockSpanProcessor{}),
+		SamplingStore: &mockSamplingStore{},
+		Catalog:       store,
+		Logger:        logger,
+	}
+
+	server := grpc.NewServer()
+	defer server.Stop()
+
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	serveGRPC(server, listener, params)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	c := api_v2.NewServiceCatalogServiceClient(conn)
+	response, err := c.GetServiceCatalog(context.Background(), &api_v2.GetServiceCatalogRequest{})
+	require.NoError(t, err)
+	require.Len(t, response.Services, 1)
+	assert.Equal(t, "frontend", response.Services[0].ServiceName)
+}
+
+func TestSpanMetrics(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	aggregator := spanmetrics.NewAggregator(metrics.NullFactory, spanmetrics.Options{})
+	aggregator.ProcessSpan(&model.Span{OperationName: "GET /", Process: model.NewProcess("frontend", nil)})
+	params := &GRPCServerParams{
+		Handler:       handler.NewGRPCHandler(logger, &mockSpanProcessor{}),
+		SamplingStore: &mockSamplingStore{},
+		SpanMetrics:   aggregator,
+		Logger:        logger,
+	}
+
+	server := grpc.NewServer()
+	defer server.Stop()
+
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	serveGRPC(server, listener, params)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	c := api_v2.NewSpanMetricsServiceClient(conn)
+	response, err := c.GetSpanMetrics(context.Background(), &api_v2.GetSpanMetricsRequest{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, response.Metrics, 1)
+	assert.Equal(t, "GET /", response.Metrics[0].OperationName)
+	assert.EqualValues(t, 1, response.Metrics[0].Calls)
+}
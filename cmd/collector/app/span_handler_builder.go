@@ -21,10 +21,13 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	zs "github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/zipkin"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -34,6 +37,9 @@ type SpanHandlerBuilder struct {
 	CollectorOpts  CollectorOptions
 	Logger         *zap.Logger
 	MetricsFactory metrics.Factory
+	LiveTail       *livetail.Bus
+	Dependencies   *dependencies.Aggregator
+	SpanMetrics    *spanmetrics.Aggregator
 }
 
 // SpanHandlers holds instances to the span handlers built by the SpanHandlerBuilder
@@ -60,6 +66,9 @@ func (b *SpanHandlerBuilder) BuildSpanProcessor() processor.SpanProcessor {
 		Options.CollectorTags(b.CollectorOpts.CollectorTags),
 		Options.DynQueueSizeWarmup(uint(b.CollectorOpts.QueueSize)), // same as queue size for now
 		Options.DynQueueSizeMemory(b.CollectorOpts.DynQueueSizeMemory),
+		Options.LiveTail(b.LiveTail),
+		Options.Dependencies(b.Dependencies),
+		Options.SpanMetrics(b.SpanMetrics),
 	)
 
 }
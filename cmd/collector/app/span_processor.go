@@ -114,6 +114,15 @@ func newSpanProcessor(spanWriter spanstore.Writer, opts ...Option) *spanProcesso
 	}
 
 	processSpanFuncs := []ProcessSpan{options.preSave, sp.saveSpan}
+	if options.liveTail != nil {
+		processSpanFuncs = append(processSpanFuncs, options.liveTail.Publish)
+	}
+	if options.dependencies != nil {
+		processSpanFuncs = append(processSpanFuncs, options.dependencies.ProcessSpan)
+	}
+	if options.spanMetrics != nil {
+		processSpanFuncs = append(processSpanFuncs, options.spanMetrics.ProcessSpan)
+	}
 	if options.dynQueueSizeMemory > 0 {
 		// add to processSpanFuncs
 		options.logger.Info("Dynamically adjusting the queue size at runtime.",
@@ -32,6 +32,8 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	zipkinSanitizer "github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/zipkin"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 	zc "github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
@@ -360,6 +362,53 @@ func TestSpanProcessorWithCollectorTags(t *testing.T) {
 	}
 }
 
+func TestSpanProcessorLiveTail(t *testing.T) {
+	bus := livetail.NewBus(10)
+	sub, err := bus.Subscribe(livetail.Filter{ServiceName: "x"})
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	w := &fakeSpanWriter{}
+	p := NewSpanProcessor(w, Options.LiveTail(bus)).(*spanProcessor)
+	defer assert.NoError(t, p.Close())
+
+	span := &model.Span{Process: model.NewProcess("x", nil)}
+	p.processSpan(span)
+	p.processSpan(&model.Span{Process: model.NewProcess("y", nil)})
+
+	select {
+	case received := <-sub.Spans():
+		assert.Equal(t, span, received)
+	default:
+		assert.Fail(t, "the processed span was not published")
+	}
+	assert.Len(t, sub.Spans(), 0)
+}
+
+func TestSpanProcessorSpanMetrics(t *testing.T) {
+	mb := metricstest.NewFactory(time.Hour)
+	aggregator := spanmetrics.NewAggregator(mb, spanmetrics.Options{})
+
+	w := &fakeSpanWriter{}
+	renameService := func(span *model.Span) *model.Span {
+		span.Process = model.NewProcess("sanitized", nil)
+		return span
+	}
+	p := NewSpanProcessor(w, Options.Sanitizer(renameService), Options.SpanMetrics(aggregator)).(*spanProcessor)
+	defer assert.NoError(t, p.Close())
+
+	// the spans are counted after they are sanitized
+	p.processItemFromQueue(&queueItem{
+		queuedTime: time.Now(),
+		span:       &model.Span{OperationName: "GET /", Process: model.NewProcess("x", nil)},
+	})
+	mb.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name:  "calls",
+		Tags:  map[string]string{"service": "sanitized", "operation": "GET /", "span_kind": ""},
+		Value: 1,
+	})
+}
+
 func TestSpanProcessorCountSpan(t *testing.T) {
 	mb := metricstest.NewFactory(time.Hour)
 	m := mb.Namespace(metrics.NSOptions{})
@@ -162,6 +162,12 @@ func (aH *APIHandler) saveSpansV2(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(operations.PostSpansAcceptedCode)
 }
 
+// DeserializeJSONV2 deserializes zipkin v2 json spans into zipkin thrift
+func DeserializeJSONV2(body []byte) ([]*zipkincore.Span, error) {
+	swaggerSpec, _ := loads.Analyzed(restapi.SwaggerJSON, "")
+	return jsonToThriftSpansV2(body, operations.NewZipkinAPI(swaggerSpec).Formats())
+}
+
 func jsonToThriftSpansV2(bodyBytes []byte, zipkinV2Formats strfmt.Registry) ([]*zipkincore.Span, error) {
 	var spans models.ListOfSpans
 	if err := swag.ReadJSON(bodyBytes, &spans); err != nil {
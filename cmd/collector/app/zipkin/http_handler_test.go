@@ -247,6 +247,16 @@ func TestCannotReadBodyFromRequest(t *testing.T) {
 	}
 }
 
+func TestDeserializeJSONV2(t *testing.T) {
+	spans, err := DeserializeJSONV2([]byte(`[{"id":"1111111111111111", "traceId":"2222222222222222", "name":"foo"}]`))
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+	assert.Equal(t, "foo", spans[0].Name)
+
+	_, err = DeserializeJSONV2([]byte("[{}]"))
+	assert.EqualError(t, err, "validation failure list:\nid in body is required\ntraceId in body is required")
+}
+
 func TestSaveSpansV2(t *testing.T) {
 	server, handler := initializeTestServer(nil)
 	defer server.Close()
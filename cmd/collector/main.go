@@ -35,6 +35,8 @@ import (
 	ss "github.com/jaegertracing/jaeger/plugin/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 	"github.com/jaegertracing/jaeger/ports"
+	istorage "github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 )
 
 const serviceName = "jaeger-collector"
@@ -83,15 +85,32 @@ func main() {
 				logger.Fatal("Failed to create sampling strategy store", zap.Error(err))
 			}
 
+			collectorOpts := new(app.CollectorOptions).InitFromViper(v)
+			var dependencyWriter dependencystore.Writer
+			if collectorOpts.DependenciesEnabled {
+				dependencyWriter, err = storageFactory.CreateDependencyWriter()
+				if err != nil {
+					logger.Fatal("Failed to create dependency writer", zap.Error(err))
+				}
+			}
+
+			catalogReader, err := storageFactory.CreateCatalogReader()
+			if err == istorage.ErrCatalogNotSupported {
+				logger.Info("Service catalog not created", zap.String("reason", err.Error()))
+			} else if err != nil {
+				logger.Fatal("Failed to create service catalog reader", zap.Error(err))
+			}
+
 			c := app.New(&app.CollectorParams{
-				ServiceName:    serviceName,
-				Logger:         logger,
-				MetricsFactory: metricsFactory,
-				SpanWriter:     spanWriter,
-				StrategyStore:  strategyStore,
-				HealthCheck:    svc.HC(),
+				ServiceName:      serviceName,
+				Logger:           logger,
+				MetricsFactory:   metricsFactory,
+				SpanWriter:       spanWriter,
+				DependencyWriter: dependencyWriter,
+				Catalog:          catalogReader,
+				StrategyStore:    strategyStore,
+				HealthCheck:      svc.HC(),
 			})
-			collectorOpts := new(app.CollectorOptions).InitFromViper(v)
 			c.Start(collectorOpts)
 
 			svc.RunAndThen(func() {
@@ -0,0 +1,74 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	dependenciesDate      = "dependencies.date"
+	dependenciesWindow    = "dependencies.window"
+	dependenciesMaxTraces = "dependencies.max-traces"
+
+	dateLayout = "2006-01-02"
+
+	// DefaultWindow is the default time window of the span queries.
+	DefaultWindow = time.Hour
+	// DefaultMaxTraces is the default maximum number of traces read per service and time window.
+	DefaultMaxTraces = 10000
+)
+
+// Options holds the configuration of the dependencies job.
+type Options struct {
+	// Day is the UTC day whose dependencies are computed
+	Day time.Time
+	// Window is the time window of each span query, the day is read window by window
+	Window time.Duration
+	// MaxTraces is the maximum number of traces read per service and time window
+	MaxTraces int
+}
+
+// AddFlags adds flags for Options.
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.String(dependenciesDate, "", "The UTC day whose dependencies are computed, as YYYY-MM-DD; defaults to yesterday")
+	flagSet.Duration(dependenciesWindow, DefaultWindow, "The time window of each span query, the day is read window by window")
+	flagSet.Int(dependenciesMaxTraces, DefaultMaxTraces, "The maximum number of traces read per service and time window; the dependencies of the other traces are not counted")
+}
+
+// InitFromViper initializes Options with properties from viper.
+func (o *Options) InitFromViper(v *viper.Viper, now time.Time) (*Options, error) {
+	if date := v.GetString(dependenciesDate); date != "" {
+		day, err := time.Parse(dateLayout, date)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", dependenciesDate, err)
+		}
+		o.Day = day
+	} else {
+		o.Day = StartOfDay(now).Add(-24 * time.Hour)
+	}
+	o.Window = v.GetDuration(dependenciesWindow)
+	o.MaxTraces = v.GetInt(dependenciesMaxTraces)
+	return o, nil
+}
+
+// StartOfDay returns the beginning of the UTC day of t.
+func StartOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsWithFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--dependencies.date=2020-05-01",
+		"--dependencies.window=10m",
+		"--dependencies.max-traces=50",
+	})
+	opts, err := new(Options).InitFromViper(v, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC), opts.Day)
+	assert.Equal(t, 10*time.Minute, opts.Window)
+	assert.Equal(t, 50, opts.MaxTraces)
+}
+
+func TestFlagDefaults(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{})
+	opts, err := new(Options).InitFromViper(v, time.Date(2020, 5, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC), opts.Day)
+	assert.Equal(t, DefaultWindow, opts.Window)
+	assert.Equal(t, DefaultMaxTraces, opts.MaxTraces)
+}
+
+func TestInvalidDate(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{"--dependencies.date=yesterday"})
+	_, err := new(Options).InitFromViper(v, time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// Job computes the dependencies between services of a day from the stored spans.
+//
+// The traces are read window by window for each service, and each trace is counted in the window
+// where it starts, so it is counted once even if it spans several windows or services. The links of
+// the day are written at once with the start of the day as timestamp, so that running the job again
+// for the same day replaces its previous result.
+type Job struct {
+	reader  spanstore.Reader
+	writer  dependencystore.Writer
+	logger  *zap.Logger
+	deduper adjuster.Adjuster
+}
+
+// NewJob creates a Job that reads the spans from the reader and writes the dependencies to the writer.
+func NewJob(reader spanstore.Reader, writer dependencystore.Writer, logger *zap.Logger) *Job {
+	return &Job{
+		reader:  reader,
+		writer:  writer,
+		logger:  logger,
+		deduper: adjuster.SpanIDDeduper(),
+	}
+}
+
+// Run computes and writes the dependencies of the day starting at opts.Day.
+func (j *Job) Run(ctx context.Context, opts *Options) ([]model.DependencyLink, error) {
+	window := opts.Window
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	services, err := j.reader.GetServices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read services: %w", err)
+	}
+
+	day := StartOfDay(opts.Day)
+	end := day.Add(24 * time.Hour)
+	deps := make(map[linkKey]uint64)
+	for start := day; start.Before(end); start = start.Add(window) {
+		windowEnd := start.Add(window)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+		if err := j.processWindow(ctx, services, start, windowEnd, opts.MaxTraces, deps); err != nil {
+			return nil, err
+		}
+	}
+
+	links := dependencyLinks(deps)
+	if err := j.writer.WriteDependencies(day, links); err != nil {
+		return nil, fmt.Errorf("cannot write dependencies: %w", err)
+	}
+	j.logger.Info("Dependencies written", zap.String("day", day.Format(dateLayout)), zap.Int("links", len(links)))
+	return links, nil
+}
+
+// processWindow counts the links of the traces starting in [start, end).
+func (j *Job) processWindow(ctx context.Context, services []string, start, end time.Time, maxTraces int, deps map[linkKey]uint64) error {
+	seen := make(map[model.TraceID]struct{})
+	for _, service := range services {
+		traces, err := j.reader.FindTraces(ctx, &spanstore.TraceQueryParameters{
+			ServiceName:  service,
+			StartTimeMin: start,
+			StartTimeMax: end,
+			NumTraces:    maxTraces,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot read traces of service %s: %w", service, err)
+		}
+		if maxTraces > 0 && len(traces) >= maxTraces {
+			j.logger.Warn("Maximum number of traces read, some dependencies are not counted",
+				zap.String("service", service), zap.Time("start", start), zap.Int("max-traces", maxTraces))
+		}
+		for _, trace := range traces {
+			if len(trace.Spans) == 0 {
+				continue
+			}
+			traceID := trace.Spans[0].TraceID
+			if _, ok := seen[traceID]; ok {
+				continue
+			}
+			seen[traceID] = struct{}{}
+			if traceStart := startTime(trace); traceStart.Before(start) || !traceStart.Before(end) {
+				continue
+			}
+			// SpanIDDeduper never returns an err
+			trace, _ = j.deduper.Adjust(trace)
+			addTraceDependencies(trace, deps)
+		}
+	}
+	return nil
+}
+
+// linkKey identifies a dependency link.
+type linkKey struct {
+	parent string
+	child  string
+}
+
+// addTraceDependencies counts the calls between the spans of the trace and their parent in another service.
+func addTraceDependencies(trace *model.Trace, deps map[linkKey]uint64) {
+	services := make(map[model.SpanID]string, len(trace.Spans))
+	for _, span := range trace.Spans {
+		if span.Process != nil {
+			services[span.SpanID] = span.Process.ServiceName
+		}
+	}
+	for _, span := range trace.Spans {
+		if span.Process == nil {
+			continue
+		}
+		parent, ok := services[span.ParentSpanID()]
+		if !ok || parent == span.Process.ServiceName {
+			continue
+		}
+		deps[linkKey{parent: parent, child: span.Process.ServiceName}]++
+	}
+}
+
+func startTime(trace *model.Trace) time.Time {
+	start := trace.Spans[0].StartTime
+	for _, span := range trace.Spans[1:] {
+		if span.StartTime.Before(start) {
+			start = span.StartTime
+		}
+	}
+	return start
+}
+
+func dependencyLinks(deps map[linkKey]uint64) []model.DependencyLink {
+	links := make([]model.DependencyLink, 0, len(deps))
+	for key, callCount := range deps {
+		links = append(links, model.DependencyLink{
+			Parent:    key.parent,
+			Child:     key.child,
+			CallCount: callCount,
+		})
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Parent != links[j].Parent {
+			return links[i].Parent < links[j].Parent
+		}
+		return links[i].Child < links[j].Child
+	})
+	return links
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+var day = time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func writeSpan(t *testing.T, store *memory.Store, traceID, spanID, parentID uint64, service string, start time.Time) {
+	span := &model.Span{
+		TraceID:       model.NewTraceID(0, traceID),
+		SpanID:        model.NewSpanID(spanID),
+		OperationName: "op",
+		Process:       model.NewProcess(service, nil),
+		StartTime:     start,
+		Duration:      time.Millisecond,
+	}
+	if parentID != 0 {
+		span.References = []model.SpanRef{model.NewChildOfRef(span.TraceID, model.NewSpanID(parentID))}
+	}
+	require.NoError(t, store.WriteSpan(span))
+}
+
+func TestJobRun(t *testing.T) {
+	store := memory.NewStore()
+	// frontend -> backend -> db, spanning two windows
+	writeSpan(t, store, 1, 1, 0, "frontend", day.Add(59*time.Minute))
+	writeSpan(t, store, 1, 2, 1, "backend", day.Add(61*time.Minute))
+	writeSpan(t, store, 1, 3, 2, "db", day.Add(62*time.Minute))
+	writeSpan(t, store, 1, 4, 3, "db", day.Add(62*time.Minute))
+	// frontend -> backend
+	writeSpan(t, store, 2, 1, 0, "frontend", day.Add(23*time.Hour))
+	writeSpan(t, store, 2, 2, 1, "backend", day.Add(23*time.Hour))
+	// starts the day before
+	writeSpan(t, store, 3, 1, 0, "frontend", day.Add(-time.Minute))
+	writeSpan(t, store, 3, 2, 1, "backend", day.Add(time.Minute))
+	// starts the day after
+	writeSpan(t, store, 4, 1, 0, "frontend", day.Add(24*time.Hour))
+	writeSpan(t, store, 4, 2, 1, "backend", day.Add(24*time.Hour))
+
+	job := NewJob(store, store, zap.NewNop())
+	opts := &Options{Day: day.Add(5 * time.Hour), Window: time.Hour, MaxTraces: 100}
+	expected := []model.DependencyLink{
+		{Parent: "backend", Child: "db", CallCount: 1},
+		{Parent: "frontend", Child: "backend", CallCount: 2},
+	}
+	links, err := job.Run(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, expected, links)
+
+	// running the job again replaces the written dependencies
+	_, err = job.Run(context.Background(), opts)
+	require.NoError(t, err)
+	written, err := store.GetDependencies(day.Add(time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expected, written)
+}
+
+func TestJobRunErrors(t *testing.T) {
+	reader := &spanStoreMocks.Reader{}
+	job := NewJob(reader, memory.NewStore(), zap.NewNop())
+	reader.On("GetServices", mock.Anything).Return(nil, errors.New("services error")).Once()
+	_, err := job.Run(context.Background(), &Options{Day: day})
+	assert.EqualError(t, err, "cannot read services: services error")
+
+	reader.On("GetServices", mock.Anything).Return([]string{"svc"}, nil)
+	reader.On("FindTraces", mock.Anything, mock.Anything).Return(nil, errors.New("traces error"))
+	_, err = job.Run(context.Background(), &Options{Day: day})
+	assert.EqualError(t, err, "cannot read traces of service svc: traces error")
+}
+
+func TestJobRunWriteError(t *testing.T) {
+	job := NewJob(memory.NewStore(), failingWriter{}, zap.NewNop())
+	_, err := job.Run(context.Background(), &Options{Day: day})
+	assert.EqualError(t, err, "cannot write dependencies: write error")
+}
+
+type failingWriter struct{}
+
+func (failingWriter) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
+	return errors.New("write error")
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/dependencies/app"
+	"github.com/jaegertracing/jaeger/cmd/docs"
+	"github.com/jaegertracing/jaeger/cmd/env"
+	"github.com/jaegertracing/jaeger/cmd/flags"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/version"
+	"github.com/jaegertracing/jaeger/plugin/storage"
+)
+
+func main() {
+	storageFactory, err := storage.NewFactory(storage.FactoryConfigFromEnvAndCLI(os.Args, os.Stderr))
+	if err != nil {
+		log.Fatalf("Cannot initialize storage factory: %v", err)
+	}
+
+	v := viper.New()
+	var command = &cobra.Command{
+		Use:   "jaeger-dependencies",
+		Short: "Jaeger dependencies computes the dependencies between services of a day from the stored spans.",
+		Long: `Jaeger dependencies reads the spans of a UTC day from the span storage, computes the dependencies
+between services and writes them to the dependency storage. Running it again for the same day replaces
+its previous result, so it can be scheduled daily, e.g. by cron, as an alternative to the Spark job.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.TryLoadConfigFile(v); err != nil {
+				return err
+			}
+			sFlags := new(flags.SharedFlags).InitFromViper(v)
+			logger, err := sFlags.NewLogger(zap.NewProductionConfig())
+			if err != nil {
+				return err
+			}
+			opts, err := new(app.Options).InitFromViper(v, time.Now())
+			if err != nil {
+				return err
+			}
+
+			storageFactory.InitFromViper(v)
+			if err := storageFactory.Initialize(metrics.NullFactory, logger); err != nil {
+				logger.Fatal("Failed to init storage factory", zap.Error(err))
+			}
+			spanReader, err := storageFactory.CreateSpanReader()
+			if err != nil {
+				logger.Fatal("Failed to create span reader", zap.Error(err))
+			}
+			dependencyWriter, err := storageFactory.CreateDependencyWriter()
+			if err != nil {
+				logger.Fatal("Failed to create dependency writer", zap.Error(err))
+			}
+
+			job := app.NewJob(spanReader, dependencyWriter, logger)
+			_, err = job.Run(context.Background(), opts)
+			// some writers buffer the writes until they are closed
+			if closer, ok := dependencyWriter.(io.Closer); ok {
+				if closeErr := closer.Close(); closeErr != nil && err == nil {
+					err = closeErr
+				}
+			}
+			return err
+		},
+	}
+
+	command.AddCommand(version.Command())
+	command.AddCommand(env.Command())
+	command.AddCommand(docs.Command(v))
+
+	config.AddFlags(
+		v,
+		command,
+		flags.AddConfigFileFlag,
+		flags.AddFlags,
+		storageFactory.AddFlags,
+		app.AddFlags,
+	)
+
+	if err := command.Execute(); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/importer"
+)
+
+var logger, _ = zap.NewDevelopment()
+
+// importResponse is the part of the response of the query service import API that is reported.
+type importResponse struct {
+	Data   []string `json:"data"`
+	Errors []struct {
+		Msg string `json:"msg"`
+	} `json:"errors"`
+}
+
+// main uploads trace files to the import API of jaeger-query, e.g.
+//
+//	jaeger-importer -query-url http://localhost:16686 trace-1.json trace-2.json
+func main() {
+	queryURL := flag.String("query-url", "http://localhost:16686", "The base URL of jaeger-query, including the base path if any")
+	format := flag.String("format", "", fmt.Sprintf("The format of the trace files, one of %v; detected from the content if empty", importer.Formats()))
+	target := flag.String("target", "uploaded", "Where to import the spans: 'uploaded' for the in-memory store of jaeger-query, 'storage' for its span storage")
+	timeout := flag.Duration("timeout", time.Minute, "The timeout of the upload")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		logger.Fatal("no trace files given")
+	}
+	if _, err := importer.ParseFormat(*format); err != nil {
+		logger.Fatal("invalid format", zap.Error(err))
+	}
+
+	body, contentType, err := multipartFiles(flag.Args())
+	if err != nil {
+		logger.Fatal("failed to read trace files", zap.Error(err))
+	}
+	params := url.Values{}
+	params.Set("format", *format)
+	params.Set("target", *target)
+	client := &http.Client{Timeout: *timeout}
+	resp, err := client.Post(*queryURL+"/api/import?"+params.Encode(), contentType, body)
+	if err != nil {
+		logger.Fatal("failed to upload trace files", zap.Error(err))
+	}
+	defer resp.Body.Close()
+
+	var response importResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		logger.Fatal("failed to read the response", zap.Int("status", resp.StatusCode), zap.Error(err))
+	}
+	for _, e := range response.Errors {
+		logger.Error("import failed", zap.String("error", e.Msg))
+	}
+	if resp.StatusCode != http.StatusOK {
+		os.Exit(1)
+	}
+	for _, traceID := range response.Data {
+		fmt.Println(traceID)
+	}
+}
+
+func multipartFiles(paths []string) (io.Reader, string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, "", err
+		}
+		part, err := writer.CreateFormFile("file", filepath.Base(path))
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		file.Close()
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return &body, writer.FormDataContentType(), nil
+}
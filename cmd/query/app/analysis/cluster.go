@@ -0,0 +1,143 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/percentile"
+)
+
+// Cluster groups traces with the same structural signature.
+type Cluster struct {
+	Signature string
+	// RootService and RootOperation are those of the first root span of the representative trace.
+	RootService   string
+	RootOperation string
+	// Count is the number of traces in the cluster.
+	Count int
+	// Errors is the number of traces with at least one error span.
+	Errors int
+	// The distribution of the durations of the traces.
+	Min time.Duration
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	Max time.Duration
+	// Representative is the trace whose duration is the median of the cluster.
+	Representative model.TraceID
+	// TraceIDs are the traces of the cluster, by increasing duration.
+	TraceIDs []model.TraceID
+}
+
+// Signature returns a digest of the shape of the call tree of the trace, that is of the services and
+// operations of its spans and their parent-child relations, ignoring timings, tags and logs.
+// Sibling spans with the same subtree, e.g. the queries in a loop, count once, so that traces
+// that only differ by the number of iterations have the same signature.
+func Signature(trace *model.Trace) string {
+	h := fnv.New64a()
+	h.Write([]byte(shape(buildTree(trace))))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// shape returns the canonical representation of the subtrees of the nodes, sorted and without duplicates.
+func shape(nodes []*node) string {
+	shapes := make([]string, 0, len(nodes))
+	seen := make(map[string]struct{}, len(nodes))
+	for _, n := range nodes {
+		s := fmt.Sprintf("%q:%q(%s)", n.span.Process.ServiceName, n.span.OperationName, shape(n.children))
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		shapes = append(shapes, s)
+	}
+	sort.Strings(shapes)
+	return strings.Join(shapes, ",")
+}
+
+type clusteredTrace struct {
+	trace    *model.Trace
+	duration time.Duration
+}
+
+// ClusterTraces groups the traces by Signature. The clusters are sorted by decreasing count, then by signature.
+func ClusterTraces(traces []*model.Trace) []*Cluster {
+	bySignature := make(map[string][]clusteredTrace)
+	for _, trace := range traces {
+		if len(trace.Spans) == 0 {
+			continue
+		}
+		signature := Signature(trace)
+		bySignature[signature] = append(bySignature[signature], clusteredTrace{trace: trace, duration: traceDuration(trace)})
+	}
+	clusters := make([]*Cluster, 0, len(bySignature))
+	for signature, traces := range bySignature {
+		clusters = append(clusters, newCluster(signature, traces))
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		return clusters[i].Signature < clusters[j].Signature
+	})
+	return clusters
+}
+
+func newCluster(signature string, traces []clusteredTrace) *Cluster {
+	sort.SliceStable(traces, func(i, j int) bool {
+		return traces[i].duration < traces[j].duration
+	})
+	durations := make([]time.Duration, len(traces))
+	cluster := &Cluster{
+		Signature: signature,
+		Count:     len(traces),
+		TraceIDs:  make([]model.TraceID, len(traces)),
+	}
+	for i, t := range traces {
+		durations[i] = t.duration
+		cluster.TraceIDs[i] = t.trace.Spans[0].TraceID
+		if hasError(t.trace) {
+			cluster.Errors++
+		}
+	}
+	cluster.Min = durations[0]
+	cluster.P50 = percentile.Durations(durations, 50)
+	cluster.P95 = percentile.Durations(durations, 95)
+	cluster.P99 = percentile.Durations(durations, 99)
+	cluster.Max = durations[len(durations)-1]
+
+	representative := traces[(len(traces)+1)/2-1].trace
+	cluster.Representative = representative.Spans[0].TraceID
+	if roots := buildTree(representative); len(roots) > 0 {
+		cluster.RootService = roots[0].span.Process.ServiceName
+		cluster.RootOperation = roots[0].span.OperationName
+	}
+	return cluster
+}
+
+func hasError(trace *model.Trace) bool {
+	for _, span := range trace.Spans {
+		if span.IsError() {
+			return true
+		}
+	}
+	return false
+}
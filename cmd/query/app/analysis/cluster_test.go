@@ -0,0 +1,93 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// clusterTrace returns a trace of a frontend call to api, which queries db the given number of times
+// and calls cache if withCache is true, that takes duration milliseconds.
+func clusterTrace(traceID uint64, duration int, queries int, withCache bool) *model.Trace {
+	spans := []*model.Span{
+		testSpan(1, "frontend", "GET", 0, duration),
+		testSpan(2, "api", "GET", 1, duration-1, childOf(1)),
+	}
+	for i := 0; i < queries; i++ {
+		spans = append(spans, testSpan(uint64(10+i), "db", "SELECT", 2+i, 3+i, childOf(2)))
+	}
+	if withCache {
+		spans = append(spans, testSpan(3, "cache", "GET", 2, 3, childOf(2)))
+	}
+	for _, span := range spans {
+		span.TraceID = model.NewTraceID(0, traceID)
+		for i := range span.References {
+			span.References[i].TraceID = span.TraceID
+		}
+	}
+	return &model.Trace{Spans: spans}
+}
+
+func TestSignature(t *testing.T) {
+	base := Signature(clusterTrace(1, 100, 1, false))
+	assert.Len(t, base, 16)
+	// timings and the number of identical sibling calls do not matter
+	assert.Equal(t, base, Signature(clusterTrace(2, 500, 3, false)))
+	// the order of the spans does not matter
+	reordered := clusterTrace(3, 100, 1, true)
+	reordered.Spans[2], reordered.Spans[3] = reordered.Spans[3], reordered.Spans[2]
+	assert.Equal(t, Signature(clusterTrace(4, 100, 1, true)), Signature(reordered))
+	// a different call tree does
+	assert.NotEqual(t, base, Signature(clusterTrace(5, 100, 1, true)))
+	assert.NotEqual(t, base, Signature(clusterTrace(6, 100, 0, false)))
+}
+
+func TestClusterTraces(t *testing.T) {
+	failing := clusterTrace(4, 900, 1, true)
+	failing.Spans[3].Tags = model.KeyValues{model.Bool("error", true)}
+	clusters := ClusterTraces([]*model.Trace{
+		clusterTrace(1, 30, 1, false),
+		clusterTrace(2, 10, 2, false),
+		clusterTrace(3, 20, 1, false),
+		failing,
+		{},
+	})
+	require.Len(t, clusters, 2)
+
+	assert.Equal(t, &Cluster{
+		Signature:      Signature(clusterTrace(1, 30, 1, false)),
+		RootService:    "frontend",
+		RootOperation:  "GET",
+		Count:          3,
+		Min:            10 * time.Millisecond,
+		P50:            20 * time.Millisecond,
+		P95:            30 * time.Millisecond,
+		P99:            30 * time.Millisecond,
+		Max:            30 * time.Millisecond,
+		Representative: model.NewTraceID(0, 3),
+		TraceIDs:       []model.TraceID{model.NewTraceID(0, 2), model.NewTraceID(0, 3), model.NewTraceID(0, 1)},
+	}, clusters[0])
+
+	assert.Equal(t, 1, clusters[1].Count)
+	assert.Equal(t, 1, clusters[1].Errors)
+	assert.Equal(t, 900*time.Millisecond, clusters[1].P99)
+	assert.Equal(t, model.NewTraceID(0, 4), clusters[1].Representative)
+}
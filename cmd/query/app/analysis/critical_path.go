@@ -0,0 +1,194 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// Segment is an interval of the critical path during which a span was doing its own work,
+// that is none of its children were on the critical path.
+type Segment struct {
+	Span  *model.Span
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the segment.
+func (s Segment) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// CriticalTime is the time that a service, or an operation of a service, spent on the critical path.
+type CriticalTime struct {
+	Service string
+	// Operation is empty for the totals of a service.
+	Operation string
+	Duration  time.Duration
+}
+
+// CriticalPath is the chain of span segments that determines the end-to-end latency of a trace.
+type CriticalPath struct {
+	// Segments are in chronological order.
+	Segments []Segment
+	// Services are the critical times of the services, longest first.
+	Services []CriticalTime
+	// Operations are the critical times of the operations, longest first.
+	Operations []CriticalTime
+}
+
+// ComputeCriticalPath returns the critical path of the trace. The trace is expected to be adjusted
+// by the query service, in particular by adjuster.ClockSkew, so that children do not start before
+// or end after their parents.
+//
+// The path is found backwards from the end of the trace: the time of a span is on the path unless
+// one of its children, the one that finished last before that time, is. Children that finish after
+// their parent are cut at the end of the parent, unless they are linked by a FOLLOWS_FROM reference
+// or they themselves started such asynchronous work, in which case they extend the path past
+// the end of the parent. The time that a parent waits for asynchronous work after its own end
+// is not attributed to it, so the path can have gaps.
+//
+// If the trace has several roots, the path is that of the root whose work ends last.
+func ComputeCriticalPath(trace *model.Trace) *CriticalPath {
+	w := &pathWalker{extents: make(map[*node]time.Time)}
+	var root *node
+	for _, n := range buildTree(trace) {
+		if extent := w.extent(n); root == nil || extent.After(w.extents[root]) {
+			root = n
+		}
+	}
+	if root != nil {
+		w.walk(root, root.start(), w.extents[root])
+	}
+	path := &CriticalPath{Segments: make([]Segment, 0, len(w.segments))}
+	for i := len(w.segments) - 1; i >= 0; i-- {
+		path.Segments = append(path.Segments, w.segments[i])
+	}
+	path.Services, path.Operations = criticalTimes(path.Segments)
+	return path
+}
+
+type pathWalker struct {
+	// extents are the times until which spans or the asynchronous work they started run
+	extents map[*node]time.Time
+	// segments are in reverse chronological order
+	segments []Segment
+}
+
+func (w *pathWalker) extent(n *node) time.Time {
+	extent := n.end()
+	for _, child := range n.children {
+		childExtent := w.extent(child)
+		if w.isAsync(child) && childExtent.After(extent) {
+			extent = childExtent
+		}
+	}
+	w.extents[n] = extent
+	return extent
+}
+
+// isAsync returns true if the work of the child is not bounded by the end of its parent.
+func (w *pathWalker) isAsync(child *node) bool {
+	return child.followsFrom || w.extents[child].After(child.end())
+}
+
+// finish returns the time at which the child stops contributing to the latency of its parent.
+func (w *pathWalker) finish(parent, child *node) time.Time {
+	if w.isAsync(child) {
+		return w.extents[child]
+	}
+	return minTime(child.end(), parent.end())
+}
+
+// walk adds the segments of the span and of its descendants between from and to.
+func (w *pathWalker) walk(n *node, from, to time.Time) {
+	children := make([]*node, len(n.children))
+	copy(children, n.children)
+	sort.SliceStable(children, func(i, j int) bool {
+		return w.finish(n, children[i]).After(w.finish(n, children[j]))
+	})
+	cursor := to
+	for _, child := range children {
+		start := maxTime(child.start(), from)
+		finish := w.finish(n, child)
+		if finish.After(cursor) || !start.Before(finish) {
+			// the child runs concurrently with a child already on the path
+			continue
+		}
+		w.addSegment(n, finish, cursor)
+		w.walk(child, start, finish)
+		cursor = start
+	}
+	w.addSegment(n, from, cursor)
+}
+
+func (w *pathWalker) addSegment(n *node, start, end time.Time) {
+	// past its end the span only waits for asynchronous work
+	end = minTime(end, n.end())
+	if start.Before(end) {
+		w.segments = append(w.segments, Segment{Span: n.span, Start: start, End: end})
+	}
+}
+
+func criticalTimes(segments []Segment) ([]CriticalTime, []CriticalTime) {
+	services := make(map[string]time.Duration)
+	operations := make(map[CriticalTime]time.Duration)
+	for _, segment := range segments {
+		service := segment.Span.Process.ServiceName
+		services[service] += segment.Duration()
+		operations[CriticalTime{Service: service, Operation: segment.Span.OperationName}] += segment.Duration()
+	}
+	serviceTimes := make([]CriticalTime, 0, len(services))
+	for service, duration := range services {
+		serviceTimes = append(serviceTimes, CriticalTime{Service: service, Duration: duration})
+	}
+	operationTimes := make([]CriticalTime, 0, len(operations))
+	for key, duration := range operations {
+		key.Duration = duration
+		operationTimes = append(operationTimes, key)
+	}
+	sortCriticalTimes(serviceTimes)
+	sortCriticalTimes(operationTimes)
+	return serviceTimes, operationTimes
+}
+
+func sortCriticalTimes(times []CriticalTime) {
+	sort.Slice(times, func(i, j int) bool {
+		if times[i].Duration != times[j].Duration {
+			return times[i].Duration > times[j].Duration
+		}
+		if times[i].Service != times[j].Service {
+			return times[i].Service < times[j].Service
+		}
+		return times[i].Operation < times[j].Operation
+	})
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var (
+	testTraceID   = model.NewTraceID(0, 1)
+	testStartTime = time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+)
+
+func ms(n int) time.Time {
+	return testStartTime.Add(time.Duration(n) * time.Millisecond)
+}
+
+// testSpan returns a span of the service running from start to end, in milliseconds since testStartTime.
+func testSpan(id uint64, service, operation string, start, end int, refs ...model.SpanRef) *model.Span {
+	return &model.Span{
+		TraceID:       testTraceID,
+		SpanID:        model.NewSpanID(id),
+		OperationName: operation,
+		References:    refs,
+		StartTime:     ms(start),
+		Duration:      time.Duration(end-start) * time.Millisecond,
+		Process:       &model.Process{ServiceName: service},
+	}
+}
+
+func childOf(id uint64) model.SpanRef {
+	return model.NewChildOfRef(testTraceID, model.NewSpanID(id))
+}
+
+func followsFrom(id uint64) model.SpanRef {
+	return model.NewFollowsFromRef(testTraceID, model.NewSpanID(id))
+}
+
+type testSegment struct {
+	spanID     uint64
+	start, end int
+}
+
+func assertSegments(t *testing.T, expected []testSegment, actual []Segment) {
+	segments := make([]testSegment, len(actual))
+	for i, s := range actual {
+		segments[i] = testSegment{
+			spanID: uint64(s.Span.SpanID),
+			start:  int(s.Start.Sub(testStartTime) / time.Millisecond),
+			end:    int(s.End.Sub(testStartTime) / time.Millisecond),
+		}
+	}
+	assert.Equal(t, expected, segments)
+}
+
+func TestComputeCriticalPath(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{
+		testSpan(1, "frontend", "GET", 0, 100),
+		testSpan(2, "api", "/users", 10, 40, childOf(1)),
+		testSpan(3, "api", "/orders", 20, 90, childOf(1)),
+		testSpan(4, "db", "SELECT", 30, 60, childOf(3)),
+		testSpan(5, "worker", "process", 95, 130, followsFrom(1)),
+	}}
+	path := ComputeCriticalPath(trace)
+	assertSegments(t, []testSegment{
+		{1, 0, 20},
+		{3, 20, 30},
+		{4, 30, 60},
+		{3, 60, 90},
+		{1, 90, 95},
+		{5, 95, 130},
+	}, path.Segments)
+	assert.Equal(t, []CriticalTime{
+		{Service: "api", Duration: 40 * time.Millisecond},
+		{Service: "worker", Duration: 35 * time.Millisecond},
+		{Service: "db", Duration: 30 * time.Millisecond},
+		{Service: "frontend", Duration: 25 * time.Millisecond},
+	}, path.Services)
+	assert.Equal(t, []CriticalTime{
+		{Service: "api", Operation: "/orders", Duration: 40 * time.Millisecond},
+		{Service: "worker", Operation: "process", Duration: 35 * time.Millisecond},
+		{Service: "db", Operation: "SELECT", Duration: 30 * time.Millisecond},
+		{Service: "frontend", Operation: "GET", Duration: 25 * time.Millisecond},
+	}, path.Operations)
+}
+
+func TestComputeCriticalPathNestedAsyncWork(t *testing.T) {
+	// the producer ends before the parent, but the work it triggers outlives the parent
+	trace := &model.Trace{Spans: []*model.Span{
+		testSpan(1, "frontend", "POST", 0, 50),
+		testSpan(2, "frontend", "publish", 10, 20, childOf(1)),
+		testSpan(3, "consumer", "consume", 60, 80, followsFrom(2)),
+	}}
+	path := ComputeCriticalPath(trace)
+	assertSegments(t, []testSegment{
+		{1, 0, 10},
+		{2, 10, 20},
+		{3, 60, 80},
+	}, path.Segments)
+}
+
+func TestComputeCriticalPathClipsChildren(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{
+		testSpan(1, "frontend", "GET", 10, 100),
+		testSpan(2, "api", "GET", 0, 120, childOf(1)),
+		testSpan(3, "api", "GET", 130, 140, childOf(1)),
+	}}
+	path := ComputeCriticalPath(trace)
+	assertSegments(t, []testSegment{
+		{2, 10, 100},
+	}, path.Segments)
+}
+
+func TestComputeCriticalPathRoots(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{
+		testSpan(1, "frontend", "GET", 0, 100),
+		// orphan whose parent is missing from the trace
+		testSpan(2, "api", "GET", 10, 150, childOf(42)),
+		// spans in a reference cycle are not reachable from any root
+		testSpan(3, "api", "GET", 0, 200, childOf(4)),
+		testSpan(4, "api", "GET", 0, 200, childOf(3)),
+	}}
+	path := ComputeCriticalPath(trace)
+	assertSegments(t, []testSegment{
+		{2, 10, 150},
+	}, path.Segments)
+
+	path = ComputeCriticalPath(&model.Trace{})
+	assert.Empty(t, path.Segments)
+	assert.Empty(t, path.Services)
+	assert.Empty(t, path.Operations)
+}
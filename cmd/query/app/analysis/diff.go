@@ -0,0 +1,251 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// CallTree aggregates the spans of one or more traces by call path, that is by the services
+// and operations of a span and of its ancestors. Sibling spans with the same service and operation,
+// e.g. the queries in a loop, share a node, and so do the spans of different traces.
+type CallTree struct {
+	// Traces is the number of aggregated traces.
+	Traces int
+	// Spans is the number of spans in the traces.
+	Spans int
+	// Duration is the sum of the durations of the traces.
+	Duration time.Duration
+	// Roots are sorted by service and operation.
+	Roots []*CallNode
+}
+
+// CallNode is a call path in a CallTree.
+type CallNode struct {
+	Service   string
+	Operation string
+	// Count is the number of spans at the call path.
+	Count int
+	// Duration is the sum of the durations of the spans at the call path.
+	Duration time.Duration
+	// SelfTime is the sum of the times during which the spans at the call path had no running children.
+	SelfTime time.Duration
+	// Children are sorted by service and operation.
+	Children []*CallNode
+
+	index map[callKey]*CallNode
+}
+
+type callKey struct {
+	service   string
+	operation string
+}
+
+// NewCallTree returns the call tree of the traces.
+func NewCallTree(traces ...*model.Trace) *CallTree {
+	tree := &CallTree{Traces: len(traces)}
+	root := &CallNode{}
+	for _, trace := range traces {
+		tree.Spans += len(trace.Spans)
+		tree.Duration += traceDuration(trace)
+		for _, n := range buildTree(trace) {
+			root.add(n)
+		}
+	}
+	root.sort()
+	tree.Roots = root.Children
+	return tree
+}
+
+func (c *CallNode) add(n *node) {
+	key := callKey{service: n.span.Process.ServiceName, operation: n.span.OperationName}
+	child, ok := c.index[key]
+	if !ok {
+		if c.index == nil {
+			c.index = make(map[callKey]*CallNode)
+		}
+		child = &CallNode{Service: key.service, Operation: key.operation}
+		c.index[key] = child
+		c.Children = append(c.Children, child)
+	}
+	child.Count++
+	child.Duration += n.span.Duration
+	child.SelfTime += selfTime(n)
+	for _, grandchild := range n.children {
+		child.add(grandchild)
+	}
+}
+
+func (c *CallNode) sort() {
+	sort.Slice(c.Children, func(i, j int) bool {
+		return c.Children[i].key().less(c.Children[j].key())
+	})
+	for _, child := range c.Children {
+		child.sort()
+	}
+}
+
+func (c *CallNode) key() callKey {
+	return callKey{service: c.Service, operation: c.Operation}
+}
+
+func (k callKey) less(other callKey) bool {
+	if k.service != other.service {
+		return k.service < other.service
+	}
+	return k.operation < other.operation
+}
+
+// traceDuration returns the time from the start of the first span to the end of the last span.
+func traceDuration(trace *model.Trace) time.Duration {
+	var start, end time.Time
+	for i, span := range trace.Spans {
+		spanEnd := span.StartTime.Add(span.Duration)
+		if i == 0 || span.StartTime.Before(start) {
+			start = span.StartTime
+		}
+		if i == 0 || spanEnd.After(end) {
+			end = spanEnd
+		}
+	}
+	return end.Sub(start)
+}
+
+// DiffStatus tells whether a call path is in both compared call trees or only in one of them.
+type DiffStatus string
+
+const (
+	// DiffCommon is the status of call paths in both call trees.
+	DiffCommon DiffStatus = "common"
+	// DiffAdded is the status of call paths only in the compared call tree B.
+	DiffAdded DiffStatus = "added"
+	// DiffMissing is the status of call paths only in the baseline call tree A.
+	DiffMissing DiffStatus = "missing"
+)
+
+// DiffSummary describes one side of a TraceDiff.
+type DiffSummary struct {
+	Traces int
+	// Spans is the mean number of spans per trace.
+	Spans float64
+	// Duration is the mean duration of the traces.
+	Duration time.Duration
+}
+
+// DiffNode compares a call path of two call trees. The counts and durations of a side are zero
+// if the call path is not in its call tree.
+type DiffNode struct {
+	Service   string
+	Operation string
+	Status    DiffStatus
+	// CountA and CountB are the mean numbers of spans per trace at the call path.
+	CountA float64
+	CountB float64
+	// DurationA and DurationB are the mean durations of the spans at the call path.
+	DurationA time.Duration
+	DurationB time.Duration
+	// Children are sorted by service and operation.
+	Children []*DiffNode
+}
+
+// CountDelta returns the change of the mean number of spans from A to B.
+func (n *DiffNode) CountDelta() float64 {
+	return n.CountB - n.CountA
+}
+
+// DurationDelta returns the change of the mean span duration from A to B.
+func (n *DiffNode) DurationDelta() time.Duration {
+	return n.DurationB - n.DurationA
+}
+
+// TraceDiff is the comparison of a baseline call tree A with a call tree B.
+type TraceDiff struct {
+	A     DiffSummary
+	B     DiffSummary
+	Roots []*DiffNode
+}
+
+// Diff aligns the call paths of the call trees and compares them. Each side can aggregate
+// several traces, e.g. to compare a slow trace with a set of normal ones, so the counts
+// and durations are compared as means per trace and per span.
+func Diff(a, b *CallTree) *TraceDiff {
+	d := &differ{tracesA: a.Traces, tracesB: b.Traces}
+	return &TraceDiff{
+		A:     diffSummary(a),
+		B:     diffSummary(b),
+		Roots: d.diffNodes(a.Roots, b.Roots),
+	}
+}
+
+func diffSummary(tree *CallTree) DiffSummary {
+	summary := DiffSummary{Traces: tree.Traces}
+	if tree.Traces > 0 {
+		summary.Spans = float64(tree.Spans) / float64(tree.Traces)
+		summary.Duration = tree.Duration / time.Duration(tree.Traces)
+	}
+	return summary
+}
+
+type differ struct {
+	tracesA int
+	tracesB int
+}
+
+// diffNodes merges the sorted nodes of A and B by their service and operation.
+func (d *differ) diffNodes(a, b []*CallNode) []*DiffNode {
+	var nodes []*DiffNode
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i].key().less(b[j].key())):
+			nodes = append(nodes, d.diffNode(a[i], nil))
+			i++
+		case i == len(a) || b[j].key().less(a[i].key()):
+			nodes = append(nodes, d.diffNode(nil, b[j]))
+			j++
+		default:
+			nodes = append(nodes, d.diffNode(a[i], b[j]))
+			i++
+			j++
+		}
+	}
+	return nodes
+}
+
+func (d *differ) diffNode(a, b *CallNode) *DiffNode {
+	n := &DiffNode{Status: DiffCommon}
+	var childrenA, childrenB []*CallNode
+	if a != nil {
+		n.Service, n.Operation = a.Service, a.Operation
+		n.CountA = float64(a.Count) / float64(d.tracesA)
+		n.DurationA = a.Duration / time.Duration(a.Count)
+		childrenA = a.Children
+	} else {
+		n.Status = DiffAdded
+	}
+	if b != nil {
+		n.Service, n.Operation = b.Service, b.Operation
+		n.CountB = float64(b.Count) / float64(d.tracesB)
+		n.DurationB = b.Duration / time.Duration(b.Count)
+		childrenB = b.Children
+	} else {
+		n.Status = DiffMissing
+	}
+	n.Children = d.diffNodes(childrenA, childrenB)
+	return n
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestNewCallTree(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{
+		testSpan(1, "frontend", "GET", 0, 100),
+		testSpan(2, "db", "SELECT", 10, 20, childOf(1)),
+		testSpan(3, "db", "SELECT", 20, 40, childOf(1)),
+		testSpan(4, "api", "GET", 40, 90, childOf(1)),
+	}}
+	tree := NewCallTree(trace, trace)
+	assert.Equal(t, 2, tree.Traces)
+	assert.Equal(t, 8, tree.Spans)
+	assert.Equal(t, 200*time.Millisecond, tree.Duration)
+	require.Len(t, tree.Roots, 1)
+	root := tree.Roots[0]
+	assert.Equal(t, "frontend", root.Service)
+	assert.Equal(t, 2, root.Count)
+	assert.Equal(t, 40*time.Millisecond, root.SelfTime)
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, "api", root.Children[0].Service)
+	assert.Equal(t, 2, root.Children[0].Count)
+	assert.Equal(t, "db", root.Children[1].Service)
+	assert.Equal(t, 4, root.Children[1].Count)
+	assert.Equal(t, 60*time.Millisecond, root.Children[1].Duration)
+}
+
+func TestDiff(t *testing.T) {
+	good := &model.Trace{Spans: []*model.Span{
+		testSpan(1, "frontend", "GET", 0, 100),
+		testSpan(2, "db", "SELECT", 10, 20, childOf(1)),
+		testSpan(3, "cache", "GET", 20, 30, childOf(1)),
+	}}
+	bad := &model.Trace{Spans: []*model.Span{
+		testSpan(1, "frontend", "GET", 0, 300),
+		testSpan(2, "db", "SELECT", 10, 40, childOf(1)),
+		testSpan(3, "db", "SELECT", 40, 70, childOf(1)),
+		testSpan(4, "api", "GET", 70, 200, childOf(1)),
+		testSpan(5, "db", "UPDATE", 80, 90, childOf(4)),
+	}}
+	diff := Diff(NewCallTree(good), NewCallTree(bad))
+
+	assert.Equal(t, DiffSummary{Traces: 1, Spans: 3, Duration: 100 * time.Millisecond}, diff.A)
+	assert.Equal(t, DiffSummary{Traces: 1, Spans: 5, Duration: 300 * time.Millisecond}, diff.B)
+	require.Len(t, diff.Roots, 1)
+	root := diff.Roots[0]
+	assert.Equal(t, DiffCommon, root.Status)
+	assert.Equal(t, 200*time.Millisecond, root.DurationDelta())
+	assert.Equal(t, 0.0, root.CountDelta())
+	require.Len(t, root.Children, 3)
+
+	api := root.Children[0]
+	assert.Equal(t, "api", api.Service)
+	assert.Equal(t, DiffAdded, api.Status)
+	assert.Equal(t, 0.0, api.CountA)
+	assert.Equal(t, 1.0, api.CountB)
+	require.Len(t, api.Children, 1)
+	assert.Equal(t, DiffAdded, api.Children[0].Status)
+	assert.Equal(t, "UPDATE", api.Children[0].Operation)
+
+	cache := root.Children[1]
+	assert.Equal(t, "cache", cache.Service)
+	assert.Equal(t, DiffMissing, cache.Status)
+	assert.Equal(t, -10*time.Millisecond, cache.DurationDelta())
+
+	db := root.Children[2]
+	assert.Equal(t, "db", db.Service)
+	assert.Equal(t, DiffCommon, db.Status)
+	assert.Equal(t, 1.0, db.CountDelta())
+	assert.Equal(t, 10*time.Millisecond, db.DurationA)
+	assert.Equal(t, 30*time.Millisecond, db.DurationB)
+}
+
+func TestDiffWithAggregate(t *testing.T) {
+	fast := &model.Trace{Spans: []*model.Span{
+		testSpan(1, "frontend", "GET", 0, 100),
+	}}
+	slow := &model.Trace{Spans: []*model.Span{
+		testSpan(1, "frontend", "GET", 0, 200),
+		testSpan(2, "db", "SELECT", 10, 20, childOf(1)),
+	}}
+	diff := Diff(NewCallTree(fast, slow), NewCallTree(slow))
+	assert.Equal(t, DiffSummary{Traces: 2, Spans: 1.5, Duration: 150 * time.Millisecond}, diff.A)
+	require.Len(t, diff.Roots, 1)
+	assert.Equal(t, 150*time.Millisecond, diff.Roots[0].DurationA)
+	assert.Equal(t, 50*time.Millisecond, diff.Roots[0].DurationDelta())
+	require.Len(t, diff.Roots[0].Children, 1)
+	assert.Equal(t, 0.5, diff.Roots[0].Children[0].CountA)
+	assert.Equal(t, 0.5, diff.Roots[0].Children[0].CountDelta())
+
+	diff = Diff(NewCallTree(), NewCallTree(fast))
+	assert.Equal(t, DiffSummary{}, diff.A)
+	require.Len(t, diff.Roots, 1)
+	assert.Equal(t, DiffAdded, diff.Roots[0].Status)
+}
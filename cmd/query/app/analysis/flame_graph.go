@@ -0,0 +1,99 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// FlameGraphRoot is the name of the root frame of a flame graph, which holds the roots of the traces.
+const FlameGraphRoot = "all"
+
+// FlameNode is a frame of a flame graph, in the JSON format of d3-flame-graph and compatible tools.
+// The values are self times in microseconds, summed over the call stacks of the folded traces.
+type FlameNode struct {
+	// Name is "service:operation", or FlameGraphRoot.
+	Name string `json:"name"`
+	// Value is the self time of the frame plus the values of its children.
+	Value uint64 `json:"value"`
+	// Self is the self time of the frame.
+	Self     uint64       `json:"self"`
+	Children []*FlameNode `json:"children"`
+}
+
+// NewFlameGraph folds the spans of the call tree into call stacks of "service:operation" frames.
+func NewFlameGraph(tree *CallTree) *FlameNode {
+	root := &FlameNode{Name: FlameGraphRoot, Children: make([]*FlameNode, 0, len(tree.Roots))}
+	for _, callNode := range tree.Roots {
+		child := newFlameNode(callNode)
+		root.Value += child.Value
+		root.Children = append(root.Children, child)
+	}
+	return root
+}
+
+func newFlameNode(callNode *CallNode) *FlameNode {
+	n := &FlameNode{
+		Name:     frameName(callNode),
+		Self:     model.DurationAsMicroseconds(callNode.SelfTime),
+		Children: make([]*FlameNode, 0, len(callNode.Children)),
+	}
+	n.Value = n.Self
+	for _, c := range callNode.Children {
+		child := newFlameNode(c)
+		n.Value += child.Value
+		n.Children = append(n.Children, child)
+	}
+	return n
+}
+
+// frameName returns the name of the frame of the call path. Semicolons separate the frames
+// of collapsed stacks and line breaks separate the stacks, so they are replaced with underscores.
+func frameName(callNode *CallNode) string {
+	return frameNameReplacer.Replace(callNode.Service + ":" + callNode.Operation)
+}
+
+var frameNameReplacer = strings.NewReplacer(";", "_", "\n", "_", "\r", "_")
+
+// WriteCollapsedStacks writes the call stacks of the call tree in the collapsed format of
+// the flamegraph.pl tools: one line per stack with its frames separated by semicolons,
+// followed by a space and the self time of the last frame in microseconds.
+// Stacks without self time are left out.
+func WriteCollapsedStacks(w io.Writer, tree *CallTree) error {
+	writer := bufio.NewWriter(w)
+	for _, root := range tree.Roots {
+		writeCollapsedStacks(writer, nil, root)
+	}
+	return writer.Flush()
+}
+
+func writeCollapsedStacks(w *bufio.Writer, stack []string, callNode *CallNode) {
+	stack = append(stack, frameName(callNode))
+	if self := callNode.SelfTime; self >= time.Microsecond {
+		w.WriteString(strings.Join(stack, ";"))
+		w.WriteByte(' ')
+		w.WriteString(strconv.FormatUint(model.DurationAsMicroseconds(self), 10))
+		w.WriteByte('\n')
+	}
+	for _, child := range callNode.Children {
+		writeCollapsedStacks(w, stack, child)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func flameGraphTraces() []*model.Trace {
+	return []*model.Trace{
+		{Spans: []*model.Span{
+			testSpan(1, "frontend", "GET", 0, 100),
+			testSpan(2, "db", "SELECT", 10, 30, childOf(1)),
+			testSpan(3, "api", "GET;v2", 40, 90, childOf(1)),
+			testSpan(4, "db", "SELECT", 50, 60, childOf(3)),
+		}},
+		{Spans: []*model.Span{
+			testSpan(1, "frontend", "GET", 0, 50),
+			testSpan(2, "db", "SELECT", 10, 50, childOf(1)),
+		}},
+	}
+}
+
+func TestNewFlameGraph(t *testing.T) {
+	graph := NewFlameGraph(NewCallTree(flameGraphTraces()...))
+	assert.Equal(t, &FlameNode{
+		Name:  "all",
+		Value: 150000,
+		Children: []*FlameNode{{
+			Name:  "frontend:GET",
+			Value: 150000,
+			Self:  40000,
+			Children: []*FlameNode{
+				{
+					Name:  "api:GET_v2",
+					Value: 50000,
+					Self:  40000,
+					Children: []*FlameNode{
+						{Name: "db:SELECT", Value: 10000, Self: 10000, Children: []*FlameNode{}},
+					},
+				},
+				{Name: "db:SELECT", Value: 60000, Self: 60000, Children: []*FlameNode{}},
+			},
+		}},
+	}, graph)
+
+	assert.Equal(t, &FlameNode{Name: "all", Children: []*FlameNode{}}, NewFlameGraph(NewCallTree()))
+}
+
+func TestWriteCollapsedStacks(t *testing.T) {
+	traces := flameGraphTraces()
+	// a span without self time
+	traces[1].Spans[1].StartTime = traces[1].Spans[0].StartTime
+	traces[1].Spans[1].Duration = traces[1].Spans[0].Duration
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCollapsedStacks(&buf, NewCallTree(traces...)))
+	assert.Equal(t, `frontend:GET 30000
+frontend:GET;api:GET_v2 40000
+frontend:GET;api:GET_v2;db:SELECT 10000
+frontend:GET;db:SELECT 70000
+`, buf.String())
+}
@@ -0,0 +1,326 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/pkg/catalog"
+)
+
+// The instrumentation issues checked by the quality report.
+const (
+	// IssueUnmatchedClientSpan is a client span without a server span among its children,
+	// e.g. because the called service is not instrumented or does not propagate the context.
+	IssueUnmatchedClientSpan = "unmatchedClientSpan"
+	// IssueOrphanSpan is a span whose parent never arrived.
+	IssueOrphanSpan = "orphanSpan"
+	// IssueClockSkew is a span whose timestamps were adjusted by adjuster.ClockSkew.
+	IssueClockSkew = "clockSkew"
+	// IssueMissingSpanKind is a span without span.kind calling or called by another service.
+	IssueMissingSpanKind = "missingSpanKind"
+	// IssueMissingClientVersion is a span whose process does not have the jaeger.version tag.
+	IssueMissingClientVersion = "missingClientVersion"
+	// IssueOutdatedClient is a span reported by a client library older than the minimum version of its language.
+	IssueOutdatedClient = "outdatedClient"
+)
+
+// DefaultMinClientVersions are the default minimum versions of the Jaeger client libraries, by language.
+var DefaultMinClientVersions = map[string]string{
+	"Go":     "2.21.0",
+	"Java":   "1.0.0",
+	"Node":   "3.17.0",
+	"Python": "4.0.0",
+	"CSharp": "0.3.0",
+	"C++":    "0.5.0",
+}
+
+// DefaultQualityOptions are the default options of the quality report.
+var DefaultQualityOptions = QualityOptions{
+	MinClientVersions: DefaultMinClientVersions,
+	MaxExamples:       3,
+}
+
+// QualityOptions configure the quality report.
+type QualityOptions struct {
+	// MinClientVersions are the minimum versions of the client libraries by language, as reported in
+	// the jaeger.version process tag, e.g. Go-2.22.1. The clients of other languages are not checked.
+	MinClientVersions map[string]string
+	// MaxExamples is the number of example traces kept per service and issue.
+	MaxExamples int
+}
+
+// IssueCount is the number of spans of a service with an issue.
+type IssueCount struct {
+	Issue string
+	Spans int
+	// Examples are traces with the issue, in the order they were checked.
+	Examples []model.TraceID
+}
+
+// ServiceQuality is the instrumentation quality of a service in a sample of traces.
+type ServiceQuality struct {
+	Service string
+	Traces  int
+	Spans   int
+	// SpansWithIssues is the number of spans with at least one issue.
+	SpansWithIssues int
+	// Issues are sorted by decreasing count.
+	Issues []*IssueCount
+}
+
+// Score returns the percentage of the spans of the service without issues.
+func (q *ServiceQuality) Score() float64 {
+	if q.Spans == 0 {
+		return 100
+	}
+	return 100 * float64(q.Spans-q.SpansWithIssues) / float64(q.Spans)
+}
+
+// ComputeQuality checks the spans of the traces for instrumentation issues and returns the quality
+// of each service, lowest score first. The traces are expected to be adjusted by the query service,
+// so that the references removed by adjuster.SpanReferences are not mistaken for parents that never
+// arrived and the clock skew corrections are recorded in the span warnings.
+//
+// Client spans with db.* tags are not expected to have a matching server span, and neither are
+// internal spans, which are only expected to have a span.kind if their parent or one of their
+// children belongs to another service. The synthetic root spans added by adjuster.SyntheticRoot
+// are neither checked nor considered as parents.
+func ComputeQuality(traces []*model.Trace, opts QualityOptions) []*ServiceQuality {
+	minVersions := make(map[string][]int, len(opts.MinClientVersions))
+	for language, version := range opts.MinClientVersions {
+		if v, ok := parseVersion(version); ok {
+			minVersions[language] = v
+		}
+	}
+	services := make(map[string]*serviceIssues)
+	for _, trace := range traces {
+		checkTrace(trace, services, minVersions, opts.MaxExamples)
+	}
+	result := make([]*ServiceQuality, 0, len(services))
+	for _, s := range services {
+		result = append(result, s.quality())
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if a, b := result[i].Score(), result[j].Score(); a != b {
+			return a < b
+		}
+		return result[i].Service < result[j].Service
+	})
+	return result
+}
+
+type serviceIssues struct {
+	ServiceQuality
+	issues map[string]*IssueCount
+}
+
+func (s *serviceIssues) add(issue string, traceID model.TraceID, maxExamples int) {
+	c, ok := s.issues[issue]
+	if !ok {
+		c = &IssueCount{Issue: issue}
+		s.issues[issue] = c
+	}
+	c.Spans++
+	for _, example := range c.Examples {
+		if example == traceID {
+			return
+		}
+	}
+	if len(c.Examples) < maxExamples {
+		c.Examples = append(c.Examples, traceID)
+	}
+}
+
+func (s *serviceIssues) quality() *ServiceQuality {
+	q := s.ServiceQuality
+	for _, c := range s.issues {
+		q.Issues = append(q.Issues, c)
+	}
+	sort.Slice(q.Issues, func(i, j int) bool {
+		if q.Issues[i].Spans != q.Issues[j].Spans {
+			return q.Issues[i].Spans > q.Issues[j].Spans
+		}
+		return q.Issues[i].Issue < q.Issues[j].Issue
+	})
+	return &q
+}
+
+func checkTrace(trace *model.Trace, services map[string]*serviceIssues, minVersions map[string][]int, maxExamples int) {
+	spans := make(map[model.SpanID]*model.Span, len(trace.Spans))
+	for _, span := range trace.Spans {
+		spans[span.SpanID] = span
+	}
+	children := make(map[model.SpanID][]*model.Span)
+	for _, span := range trace.Spans {
+		for _, ref := range span.References {
+			if ref.TraceID == span.TraceID && ref.SpanID != span.SpanID {
+				children[ref.SpanID] = append(children[ref.SpanID], span)
+			}
+		}
+	}
+	seen := make(map[string]struct{})
+	for _, span := range trace.Spans {
+		if span.Process == nil || isSyntheticRoot(span) {
+			continue
+		}
+		service := span.Process.ServiceName
+		s, ok := services[service]
+		if !ok {
+			s = &serviceIssues{ServiceQuality: ServiceQuality{Service: service}, issues: make(map[string]*IssueCount)}
+			services[service] = s
+		}
+		if _, ok := seen[service]; !ok {
+			seen[service] = struct{}{}
+			s.Traces++
+		}
+		s.Spans++
+		issues := spanIssues(span, spans, children[span.SpanID], minVersions)
+		if len(issues) > 0 {
+			s.SpansWithIssues++
+		}
+		for _, issue := range issues {
+			s.add(issue, span.TraceID, maxExamples)
+		}
+	}
+}
+
+func spanIssues(span *model.Span, spans map[model.SpanID]*model.Span, children []*model.Span, minVersions map[string][]int) []string {
+	var issues []string
+	kind, _ := span.GetSpanKind()
+	if kind == "client" && !hasDBTag(span) && !hasChildOfKind(children, "server") {
+		issues = append(issues, IssueUnmatchedClientSpan)
+	}
+	var parent *model.Span
+	for _, ref := range span.References {
+		if ref.TraceID != span.TraceID || ref.SpanID == span.SpanID {
+			continue
+		}
+		p, ok := spans[ref.SpanID]
+		if !ok {
+			issues = append(issues, IssueOrphanSpan)
+			break
+		}
+		if isSyntheticRoot(p) {
+			continue
+		}
+		if parent == nil {
+			parent = p
+		}
+	}
+	for _, warning := range span.Warnings {
+		if strings.HasPrefix(warning, adjuster.ClockSkewWarningPrefix) {
+			issues = append(issues, IssueClockSkew)
+			break
+		}
+	}
+	if kind == "" && crossesServices(span, parent, children) {
+		issues = append(issues, IssueMissingSpanKind)
+	}
+	if version, ok := model.KeyValues(span.Process.Tags).FindByKey(catalog.ClientVersionTag); !ok {
+		issues = append(issues, IssueMissingClientVersion)
+	} else if isOutdated(version.AsString(), minVersions) {
+		issues = append(issues, IssueOutdatedClient)
+	}
+	return issues
+}
+
+func isSyntheticRoot(span *model.Span) bool {
+	tag, ok := model.KeyValues(span.Tags).FindByKey(adjuster.SyntheticRootTag)
+	return ok && tag.Bool()
+}
+
+func hasDBTag(span *model.Span) bool {
+	for _, tag := range span.Tags {
+		if strings.HasPrefix(tag.Key, "db.") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasChildOfKind(children []*model.Span, kind string) bool {
+	for _, child := range children {
+		if k, _ := child.GetSpanKind(); k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func crossesServices(span, parent *model.Span, children []*model.Span) bool {
+	if parent != nil && parent.Process != nil && parent.Process.ServiceName != span.Process.ServiceName {
+		return true
+	}
+	for _, child := range children {
+		if child.Process != nil && child.Process.ServiceName != span.Process.ServiceName {
+			return true
+		}
+	}
+	return false
+}
+
+// isOutdated returns true if the client version, e.g. Go-2.22.1, is older than the minimum version of its language.
+func isOutdated(clientVersion string, minVersions map[string][]int) bool {
+	i := strings.Index(clientVersion, "-")
+	if i < 0 {
+		return false
+	}
+	min, ok := minVersions[clientVersion[:i]]
+	if !ok {
+		return false
+	}
+	version, ok := parseVersion(clientVersion[i+1:])
+	if !ok {
+		return false
+	}
+	for j := range min {
+		var v int
+		if j < len(version) {
+			v = version[j]
+		}
+		if v != min[j] {
+			return v < min[j]
+		}
+	}
+	return false
+}
+
+// parseVersion parses the numeric components of a version such as 1.2.3, ignoring any suffix such as -SNAPSHOT.
+func parseVersion(version string) ([]int, bool) {
+	var components []int
+	for _, part := range strings.Split(version, ".") {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			break
+		}
+		n, err := strconv.Atoi(part[:end])
+		if err != nil {
+			break
+		}
+		components = append(components, n)
+		if end < len(part) {
+			break
+		}
+	}
+	return components, len(components) > 0
+}
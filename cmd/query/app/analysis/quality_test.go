@@ -0,0 +1,179 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/adjuster"
+)
+
+func withKind(span *model.Span, kind string) *model.Span {
+	span.Tags = append(span.Tags, model.String("span.kind", kind))
+	return span
+}
+
+func withClient(span *model.Span, version string) *model.Span {
+	span.Process.Tags = append(span.Process.Tags, model.String("jaeger.version", version))
+	return span
+}
+
+func qualityOf(t *testing.T, quality []*ServiceQuality, service string) *ServiceQuality {
+	for _, q := range quality {
+		if q.Service == service {
+			return q
+		}
+	}
+	require.Failf(t, "service not found", service)
+	return nil
+}
+
+func issueCounts(q *ServiceQuality) map[string]int {
+	counts := make(map[string]int)
+	for _, c := range q.Issues {
+		counts[c.Issue] = c.Spans
+	}
+	return counts
+}
+
+func TestComputeQuality(t *testing.T) {
+	skewed := withClient(withKind(testSpan(4, "api", "GET", 2, 8, childOf(2)), "server"), "Go-2.22.1")
+	skewed.Warnings = []string{adjuster.ClockSkewWarningPrefix + " 5ms"}
+	trace := &model.Trace{Spans: []*model.Span{
+		withClient(withKind(testSpan(1, "frontend", "GET", 0, 10), "server"), "Go-2.22.1"),
+		// matched by span 4
+		withClient(withKind(testSpan(2, "frontend", "GET api", 1, 9, childOf(1)), "client"), "Go-2.22.1"),
+		// calls an uninstrumented service
+		withClient(withKind(testSpan(3, "frontend", "GET auth", 1, 2, childOf(1)), "client"), "Go-2.22.1"),
+		skewed,
+		// a database call needs no server span
+		withClient(withKind(testSpan(5, "api", "SELECT", 3, 4, childOf(4)), "client"), "Go-2.22.1"),
+		// an internal span needs no span.kind
+		withClient(testSpan(6, "api", "compute", 4, 5, childOf(4)), "Go-2.22.1"),
+		// called by api without span.kind, by an outdated client
+		withClient(testSpan(7, "billing", "charge", 5, 6, childOf(4)), "Java-0.35.0"),
+		// the parent never arrived, no client version
+		testSpan(8, "billing", "refund", 6, 7, childOf(99)),
+	}}
+	trace.Spans[4].Tags = append(trace.Spans[4].Tags, model.String("db.type", "sql"))
+
+	quality := ComputeQuality([]*model.Trace{trace}, DefaultQualityOptions)
+	require.Len(t, quality, 3)
+	assert.Equal(t, "billing", quality[0].Service)
+	// same score, by name
+	assert.Equal(t, "api", quality[1].Service)
+	assert.Equal(t, "frontend", quality[2].Service)
+
+	frontend := qualityOf(t, quality, "frontend")
+	assert.Equal(t, 1, frontend.Traces)
+	assert.Equal(t, 3, frontend.Spans)
+	assert.Equal(t, 1, frontend.SpansWithIssues)
+	assert.InDelta(t, 66.67, frontend.Score(), 0.01)
+	assert.Equal(t, map[string]int{IssueUnmatchedClientSpan: 1}, issueCounts(frontend))
+	assert.Equal(t, []model.TraceID{testTraceID}, frontend.Issues[0].Examples)
+
+	api := qualityOf(t, quality, "api")
+	assert.Equal(t, 3, api.Spans)
+	assert.Equal(t, map[string]int{IssueClockSkew: 1}, issueCounts(api))
+
+	billing := qualityOf(t, quality, "billing")
+	assert.Equal(t, 2, billing.SpansWithIssues)
+	assert.Equal(t, 0.0, billing.Score())
+	assert.Equal(t, map[string]int{
+		IssueMissingSpanKind:      1,
+		IssueOutdatedClient:       1,
+		IssueOrphanSpan:           1,
+		IssueMissingClientVersion: 1,
+	}, issueCounts(billing))
+}
+
+func TestComputeQualitySyntheticRoot(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{
+		withClient(testSpan(1, "frontend", "GET", 0, 10), "Go-2.22.1"),
+		withClient(testSpan(2, "billing", "charge", 5, 6, childOf(99)), "Go-2.22.1"),
+	}}
+	trace, err := adjuster.SyntheticRoot().Adjust(trace)
+	require.NoError(t, err)
+	require.Len(t, trace.Spans, 3)
+
+	quality := ComputeQuality([]*model.Trace{trace}, DefaultQualityOptions)
+	require.Len(t, quality, 2)
+	assert.Empty(t, qualityOf(t, quality, "frontend").Issues)
+	assert.Equal(t, map[string]int{IssueOrphanSpan: 1}, issueCounts(qualityOf(t, quality, "billing")))
+}
+
+func TestComputeQualityExamples(t *testing.T) {
+	var traces []*model.Trace
+	for i := uint64(1); i <= 5; i++ {
+		trace := &model.Trace{Spans: []*model.Span{
+			testSpan(1, "frontend", "GET", 0, 10),
+			testSpan(2, "frontend", "GET", 0, 10),
+		}}
+		for _, span := range trace.Spans {
+			span.TraceID = model.NewTraceID(0, i)
+		}
+		traces = append(traces, trace)
+	}
+	quality := ComputeQuality(traces, QualityOptions{MaxExamples: 2})
+	require.Len(t, quality, 1)
+	assert.Equal(t, 5, quality[0].Traces)
+	assert.Equal(t, 10, quality[0].Spans)
+	require.Len(t, quality[0].Issues, 1)
+	assert.Equal(t, IssueMissingClientVersion, quality[0].Issues[0].Issue)
+	assert.Equal(t, 10, quality[0].Issues[0].Spans)
+	assert.Equal(t, []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 2)}, quality[0].Issues[0].Examples)
+}
+
+func TestIsOutdated(t *testing.T) {
+	minVersions := map[string][]int{"Go": {2, 21, 0}, "Java": {1, 0}}
+	testCases := []struct {
+		version  string
+		outdated bool
+	}{
+		{version: "Go-2.20.9", outdated: true},
+		{version: "Go-2.21", outdated: false},
+		{version: "Go-2.21.0", outdated: false},
+		{version: "Go-3.0.0", outdated: false},
+		{version: "Go-2", outdated: true},
+		{version: "Java-0.35.0-SNAPSHOT", outdated: true},
+		{version: "Java-1.1.0-SNAPSHOT", outdated: false},
+		{version: "Python-3.0.0", outdated: false},
+		{version: "Go-unknown", outdated: false},
+		{version: "Go", outdated: false},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.outdated, isOutdated(testCase.version, minVersions), testCase.version)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	v, ok := parseVersion("1.22.3-rc1")
+	assert.True(t, ok)
+	assert.Equal(t, []int{1, 22, 3}, v)
+	v, ok = parseVersion("4.x")
+	assert.True(t, ok)
+	assert.Equal(t, []int{4}, v)
+	_, ok = parseVersion("dev")
+	assert.False(t, ok)
+}
+
+func TestServiceQualityScore(t *testing.T) {
+	assert.Equal(t, 100.0, (&ServiceQuality{}).Score())
+	assert.Equal(t, 75.0, (&ServiceQuality{Spans: 4, SpansWithIssues: 1}).Score())
+}
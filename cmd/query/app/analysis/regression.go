@@ -0,0 +1,254 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/percentile"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
+)
+
+// The metrics compared between two windows.
+const (
+	MetricP50       = "p50"
+	MetricP95       = "p95"
+	MetricP99       = "p99"
+	MetricErrorRate = "errorRate"
+)
+
+// DefaultRegressionOptions are the default thresholds of a significant change.
+var DefaultRegressionOptions = RegressionOptions{
+	LatencyRatio:   1.2,
+	MinLatency:     time.Millisecond,
+	ErrorRateDelta: 0.05,
+	MinCalls:       5,
+	MaxExamples:    3,
+}
+
+// RegressionOptions are the thresholds above which a change between two windows is significant.
+type RegressionOptions struct {
+	// LatencyRatio is the factor by which a latency percentile must grow or shrink.
+	LatencyRatio float64
+	// MinLatency is the smallest absolute change of a latency percentile.
+	MinLatency time.Duration
+	// ErrorRateDelta is the smallest absolute change of the error rate, between 0 and 1.
+	ErrorRateDelta float64
+	// MinCalls is the number of calls an operation needs in both windows to be compared.
+	MinCalls int
+	// MaxExamples is the number of example traces kept per operation and window.
+	MaxExamples int
+}
+
+// OperationStats are the latency percentiles and error count of an operation in a time window.
+type OperationStats struct {
+	Operation string
+	Calls     int
+	Errors    int
+	P50       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+	// Examples are traces with the slowest spans of the operation, slowest first.
+	Examples []model.TraceID
+}
+
+// ErrorRate returns the ratio of calls that failed.
+func (s *OperationStats) ErrorRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Calls)
+}
+
+// Change is a significant change of a metric of an operation between two windows.
+type Change struct {
+	Metric string
+	Before float64
+	After  float64
+	// Score is how many times the threshold the change is.
+	Score float64
+}
+
+// Regression holds the significant changes of an operation between two windows.
+type Regression struct {
+	Operation string
+	Before    *OperationStats
+	After     *OperationStats
+	// Changes are sorted by decreasing score.
+	Changes []Change
+	// Score is the highest score of the changes.
+	Score float64
+}
+
+type exampleSpan struct {
+	traceID  model.TraceID
+	duration time.Duration
+}
+
+// NewOperationStats computes the stats of the operations of the service from the given traces.
+// The percentiles are those of the durations of the spans of the traces.
+func NewOperationStats(service string, traces []*model.Trace, maxExamples int) map[string]*OperationStats {
+	durations := make(map[string][]time.Duration)
+	examples := make(map[string][]exampleSpan)
+	stats := make(map[string]*OperationStats)
+	for _, trace := range traces {
+		for _, span := range trace.Spans {
+			if span.Process == nil || span.Process.ServiceName != service {
+				continue
+			}
+			s, ok := stats[span.OperationName]
+			if !ok {
+				s = &OperationStats{Operation: span.OperationName}
+				stats[span.OperationName] = s
+			}
+			s.Calls++
+			if span.IsError() {
+				s.Errors++
+			}
+			durations[span.OperationName] = append(durations[span.OperationName], span.Duration)
+			examples[span.OperationName] = append(examples[span.OperationName], exampleSpan{traceID: span.TraceID, duration: span.Duration})
+		}
+	}
+	for operation, s := range stats {
+		d := durations[operation]
+		sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+		s.P50 = percentile.Durations(d, 50)
+		s.P95 = percentile.Durations(d, 95)
+		s.P99 = percentile.Durations(d, 99)
+		s.Examples = slowestTraces(examples[operation], maxExamples)
+	}
+	return stats
+}
+
+// NewOperationStatsFromSpanMetrics computes the stats of the operations from their span metrics, adding up the
+// series of the span kinds and dimension values of an operation. The percentiles are upper bounds of latency buckets.
+// The examples, which span metrics do not have, are taken from fromTraces, if not nil.
+func NewOperationStatsFromSpanMetrics(series []spanmetrics.Series, fromTraces map[string]*OperationStats) map[string]*OperationStats {
+	byOperation := make(map[string][]spanmetrics.Series)
+	for _, s := range series {
+		s.SpanKind = ""
+		s.Dimensions = nil
+		byOperation[s.OperationName] = append(byOperation[s.OperationName], s)
+	}
+	stats := make(map[string]*OperationStats)
+	for operation, series := range byOperation {
+		merged := spanmetrics.Merge(series)[0]
+		s := &OperationStats{
+			Operation: operation,
+			Calls:     int(merged.Calls),
+			Errors:    int(merged.Errors),
+			P50:       merged.Percentile(50),
+			P95:       merged.Percentile(95),
+			P99:       merged.Percentile(99),
+		}
+		if traceStats, ok := fromTraces[operation]; ok {
+			s.Examples = traceStats.Examples
+		}
+		stats[operation] = s
+	}
+	return stats
+}
+
+// DetectRegressions compares the stats of the operations in two windows and returns those that changed
+// significantly, for better or worse, ranked by decreasing score. Operations with fewer than opts.MinCalls
+// calls in either window are skipped.
+func DetectRegressions(before, after map[string]*OperationStats, opts RegressionOptions) []*Regression {
+	var regressions []*Regression
+	for operation, b := range before {
+		a, ok := after[operation]
+		if !ok || b.Calls < opts.MinCalls || a.Calls < opts.MinCalls {
+			continue
+		}
+		r := &Regression{Operation: operation, Before: b, After: a}
+		r.addLatencyChange(MetricP50, b.P50, a.P50, opts)
+		r.addLatencyChange(MetricP95, b.P95, a.P95, opts)
+		r.addLatencyChange(MetricP99, b.P99, a.P99, opts)
+		if delta := a.ErrorRate() - b.ErrorRate(); delta >= opts.ErrorRateDelta || -delta >= opts.ErrorRateDelta {
+			r.addChange(Change{Metric: MetricErrorRate, Before: b.ErrorRate(), After: a.ErrorRate(), Score: abs(delta) / opts.ErrorRateDelta})
+		}
+		if len(r.Changes) > 0 {
+			regressions = append(regressions, r)
+		}
+	}
+	sort.Slice(regressions, func(i, j int) bool {
+		if regressions[i].Score != regressions[j].Score {
+			return regressions[i].Score > regressions[j].Score
+		}
+		return regressions[i].Operation < regressions[j].Operation
+	})
+	return regressions
+}
+
+func (r *Regression) addLatencyChange(metric string, before, after time.Duration, opts RegressionOptions) {
+	if before <= 0 || after <= 0 {
+		return
+	}
+	delta := after - before
+	if delta < 0 {
+		delta = -delta
+	}
+	ratio := float64(after) / float64(before)
+	if ratio < 1 {
+		ratio = 1 / ratio
+	}
+	if delta < opts.MinLatency || ratio < opts.LatencyRatio {
+		return
+	}
+	r.addChange(Change{
+		Metric: metric,
+		Before: float64(before),
+		After:  float64(after),
+		Score:  (ratio - 1) / (opts.LatencyRatio - 1),
+	})
+}
+
+func (r *Regression) addChange(change Change) {
+	r.Changes = append(r.Changes, change)
+	sort.SliceStable(r.Changes, func(i, j int) bool {
+		return r.Changes[i].Score > r.Changes[j].Score
+	})
+	if change.Score > r.Score {
+		r.Score = change.Score
+	}
+}
+
+// slowestTraces returns the distinct traces of the slowest spans, slowest first.
+func slowestTraces(spans []exampleSpan, max int) []model.TraceID {
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].duration > spans[j].duration
+	})
+	var traceIDs []model.TraceID
+	seen := make(map[model.TraceID]struct{})
+	for _, span := range spans {
+		if len(traceIDs) == max {
+			break
+		}
+		if _, ok := seen[span.traceID]; ok {
+			continue
+		}
+		seen[span.traceID] = struct{}{}
+		traceIDs = append(traceIDs, span.traceID)
+	}
+	return traceIDs
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
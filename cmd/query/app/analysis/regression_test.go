@@ -0,0 +1,121 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
+)
+
+// regressionTraces returns one trace per duration, each with a span of the operation taking that many milliseconds.
+func regressionTraces(firstTraceID uint64, operation string, durations ...int) []*model.Trace {
+	traces := make([]*model.Trace, len(durations))
+	for i, d := range durations {
+		span := testSpan(1, "frontend", operation, 0, d)
+		span.TraceID = model.NewTraceID(0, firstTraceID+uint64(i))
+		traces[i] = &model.Trace{Spans: []*model.Span{span, testSpan(2, "db", operation, 0, 1000, childOf(1))}}
+	}
+	return traces
+}
+
+func TestNewOperationStats(t *testing.T) {
+	traces := regressionTraces(1, "GET", 10, 20, 30, 40, 50, 60, 70, 80, 90, 100)
+	traces[0].Spans[0].Tags = model.KeyValues{model.Bool("error", true)}
+
+	stats := NewOperationStats("frontend", traces, 2)
+	require.Len(t, stats, 1)
+	assert.Equal(t, &OperationStats{
+		Operation: "GET",
+		Calls:     10,
+		Errors:    1,
+		P50:       50 * time.Millisecond,
+		P95:       100 * time.Millisecond,
+		P99:       100 * time.Millisecond,
+		Examples:  []model.TraceID{model.NewTraceID(0, 10), model.NewTraceID(0, 9)},
+	}, stats["GET"])
+	assert.Equal(t, 0.1, stats["GET"].ErrorRate())
+	assert.Equal(t, 0.0, (&OperationStats{}).ErrorRate())
+}
+
+func TestNewOperationStatsFromSpanMetrics(t *testing.T) {
+	buckets := []time.Duration{10 * time.Millisecond, 100 * time.Millisecond}
+	fromTraces := map[string]*OperationStats{"GET": {Examples: []model.TraceID{model.NewTraceID(0, 1)}}}
+	stats := NewOperationStatsFromSpanMetrics([]spanmetrics.Series{
+		{OperationName: "GET", SpanKind: "server", Calls: 8, Errors: 2, LatencyBuckets: buckets, LatencyCounts: []uint64{8, 0, 0}},
+		{OperationName: "GET", SpanKind: "client", Calls: 2, LatencyBuckets: buckets, LatencyCounts: []uint64{0, 1, 1}},
+		{OperationName: "POST", Calls: 1, LatencyBuckets: buckets, LatencyCounts: []uint64{0, 1, 0}},
+	}, fromTraces)
+	require.Len(t, stats, 2)
+	assert.Equal(t, &OperationStats{
+		Operation: "GET",
+		Calls:     10,
+		Errors:    2,
+		P50:       10 * time.Millisecond,
+		P95:       100 * time.Millisecond,
+		P99:       100 * time.Millisecond,
+		Examples:  []model.TraceID{model.NewTraceID(0, 1)},
+	}, stats["GET"])
+	assert.Nil(t, stats["POST"].Examples)
+}
+
+func TestDetectRegressions(t *testing.T) {
+	before := map[string]*OperationStats{
+		"slower":   {Operation: "slower", Calls: 10, P50: 10 * time.Millisecond, P95: 20 * time.Millisecond, P99: 30 * time.Millisecond},
+		"faster":   {Operation: "faster", Calls: 10, P50: 10 * time.Millisecond, P95: 20 * time.Millisecond, P99: 30 * time.Millisecond},
+		"failing":  {Operation: "failing", Calls: 10, P50: 10 * time.Millisecond, P95: 20 * time.Millisecond, P99: 30 * time.Millisecond},
+		"stable":   {Operation: "stable", Calls: 10, P50: 10 * time.Millisecond, P95: 20 * time.Millisecond, P99: 30 * time.Millisecond},
+		"tiny":     {Operation: "tiny", Calls: 10, P50: 100 * time.Microsecond, P95: 100 * time.Microsecond, P99: 100 * time.Microsecond},
+		"rare":     {Operation: "rare", Calls: 2, P50: 10 * time.Millisecond, P95: 10 * time.Millisecond, P99: 10 * time.Millisecond},
+		"vanished": {Operation: "vanished", Calls: 10, P50: 10 * time.Millisecond},
+	}
+	after := map[string]*OperationStats{
+		"slower":  {Operation: "slower", Calls: 10, P50: 10 * time.Millisecond, P95: 40 * time.Millisecond, P99: 60 * time.Millisecond},
+		"faster":  {Operation: "faster", Calls: 10, P50: 5 * time.Millisecond, P95: 20 * time.Millisecond, P99: 30 * time.Millisecond},
+		"failing": {Operation: "failing", Calls: 10, Errors: 3, P50: 10 * time.Millisecond, P95: 20 * time.Millisecond, P99: 30 * time.Millisecond},
+		"stable":  {Operation: "stable", Calls: 10, P50: 11 * time.Millisecond, P95: 21 * time.Millisecond, P99: 31 * time.Millisecond},
+		"tiny":    {Operation: "tiny", Calls: 10, P50: 300 * time.Microsecond, P95: 300 * time.Microsecond, P99: 300 * time.Microsecond},
+		"rare":    {Operation: "rare", Calls: 2, P50: time.Second, P95: time.Second, P99: time.Second},
+	}
+
+	regressions := DetectRegressions(before, after, DefaultRegressionOptions)
+	require.Len(t, regressions, 3)
+
+	assert.Equal(t, "failing", regressions[0].Operation)
+	assert.InDelta(t, 6, regressions[0].Score, 1e-9)
+	assert.Equal(t, MetricErrorRate, regressions[0].Changes[0].Metric)
+	assert.InDelta(t, 0.3, regressions[0].Changes[0].After, 1e-9)
+
+	assert.Equal(t, "faster", regressions[1].Operation)
+	assert.InDelta(t, 5, regressions[1].Score, 1e-9)
+	assert.Equal(t, []Change{{
+		Metric: MetricP50,
+		Before: float64(10 * time.Millisecond),
+		After:  float64(5 * time.Millisecond),
+		Score:  regressions[1].Score,
+	}}, regressions[1].Changes)
+
+	assert.Equal(t, "slower", regressions[2].Operation)
+	require.Len(t, regressions[2].Changes, 2)
+	assert.Equal(t, MetricP95, regressions[2].Changes[0].Metric)
+	assert.Equal(t, MetricP99, regressions[2].Changes[1].Metric)
+	assert.Equal(t, before["slower"], regressions[2].Before)
+	assert.Equal(t, after["slower"], regressions[2].After)
+}
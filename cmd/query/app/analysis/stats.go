@@ -0,0 +1,174 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// StatsGroup holds the statistics of the spans of a service and operation,
+// and of a value of the group-by tag if any.
+type StatsGroup struct {
+	Service   string
+	Operation string
+	// TagValue is the value of the group-by tag, empty if the spans do not have the tag.
+	TagValue string
+	Count    int
+	Errors   int
+	Total    time.Duration
+	Min      time.Duration
+	Max      time.Duration
+	// SelfTime is the total time of the spans during which none of their children were running.
+	SelfTime time.Duration
+}
+
+// Average returns the average duration of the spans.
+func (g *StatsGroup) Average() time.Duration {
+	if g.Count == 0 {
+		return 0
+	}
+	return g.Total / time.Duration(g.Count)
+}
+
+// TraceStats summarizes a trace.
+type TraceStats struct {
+	// RootService and RootOperation are those of the first root span.
+	RootService   string
+	RootOperation string
+	StartTime     time.Time
+	Spans         int
+	Errors        int
+	Services      int
+	// Depth is the number of spans on the longest path from a root to a leaf.
+	Depth    int
+	Duration time.Duration
+	// GroupByTag is the key of the tag that the groups are split by, if any.
+	GroupByTag string
+	// Groups are sorted by service, operation and tag value.
+	Groups []*StatsGroup
+}
+
+// ComputeStats returns the statistics of the trace. If groupByTag is not empty,
+// the spans of a service and operation are further grouped by the value of that tag,
+// looked up in the span tags and then in the process tags.
+//
+// Spans that are only reachable through a reference cycle are counted in Spans, but not in the groups.
+func ComputeStats(trace *model.Trace, groupByTag string) *TraceStats {
+	stats := &TraceStats{
+		Spans:      len(trace.Spans),
+		Duration:   traceDuration(trace),
+		GroupByTag: groupByTag,
+	}
+	groups := make(map[statsKey]*StatsGroup)
+	services := make(map[string]struct{})
+	roots := buildTree(trace)
+	if len(roots) > 0 {
+		stats.RootService = roots[0].span.Process.ServiceName
+		stats.RootOperation = roots[0].span.OperationName
+		stats.StartTime = roots[0].start()
+	}
+	for _, root := range roots {
+		stats.addNode(root, groups, 1)
+	}
+	for _, group := range groups {
+		services[group.Service] = struct{}{}
+		stats.Groups = append(stats.Groups, group)
+	}
+	stats.Services = len(services)
+	sort.Slice(stats.Groups, func(i, j int) bool {
+		a, b := stats.Groups[i], stats.Groups[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Operation != b.Operation {
+			return a.Operation < b.Operation
+		}
+		return a.TagValue < b.TagValue
+	})
+	return stats
+}
+
+type statsKey struct {
+	service   string
+	operation string
+	tagValue  string
+}
+
+func (stats *TraceStats) addNode(n *node, groups map[statsKey]*StatsGroup, depth int) {
+	if depth > stats.Depth {
+		stats.Depth = depth
+	}
+	span := n.span
+	key := statsKey{service: span.Process.ServiceName, operation: span.OperationName}
+	if stats.GroupByTag != "" {
+		key.tagValue = tagValue(span, stats.GroupByTag)
+	}
+	group, ok := groups[key]
+	if !ok {
+		group = &StatsGroup{
+			Service:   key.service,
+			Operation: key.operation,
+			TagValue:  key.tagValue,
+			Min:       span.Duration,
+			Max:       span.Duration,
+		}
+		groups[key] = group
+	}
+	group.Count++
+	group.Total += span.Duration
+	group.SelfTime += selfTime(n)
+	if span.Duration < group.Min {
+		group.Min = span.Duration
+	}
+	if span.Duration > group.Max {
+		group.Max = span.Duration
+	}
+	if span.IsError() {
+		group.Errors++
+		stats.Errors++
+	}
+	for _, child := range n.children {
+		stats.addNode(child, groups, depth+1)
+	}
+}
+
+// selfTime returns the duration of the span minus the time during which any of its children were running.
+// The children are sorted by start time.
+func selfTime(n *node) time.Duration {
+	self := n.span.Duration
+	covered := n.start()
+	for _, child := range n.children {
+		start := maxTime(child.start(), covered)
+		end := minTime(child.end(), n.end())
+		if start.Before(end) {
+			self -= end.Sub(start)
+			covered = end
+		}
+	}
+	return self
+}
+
+func tagValue(span *model.Span, key string) string {
+	if tag, ok := model.KeyValues(span.Tags).FindByKey(key); ok {
+		return tag.AsString()
+	}
+	if tag, ok := model.KeyValues(span.Process.Tags).FindByKey(key); ok {
+		return tag.AsString()
+	}
+	return ""
+}
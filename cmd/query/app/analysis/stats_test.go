@@ -0,0 +1,75 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestComputeStats(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{
+		testSpan(1, "frontend", "GET", 0, 100),
+		testSpan(2, "db", "SELECT", 10, 30, childOf(1)),
+		testSpan(3, "db", "SELECT", 20, 50, childOf(1)),
+		testSpan(4, "api", "GET", 60, 90, childOf(1)),
+		testSpan(5, "db", "UPDATE", 70, 80, childOf(4)),
+	}}
+	trace.Spans[2].Tags = model.KeyValues{model.Bool("error", true), model.String("db.instance", "orders")}
+	trace.Spans[1].Process.Tags = model.KeyValues{model.String("db.instance", "users")}
+
+	stats := ComputeStats(trace, "")
+	assert.Equal(t, "frontend", stats.RootService)
+	assert.Equal(t, "GET", stats.RootOperation)
+	assert.Equal(t, testStartTime, stats.StartTime)
+	assert.Equal(t, 5, stats.Spans)
+	assert.Equal(t, 1, stats.Errors)
+	assert.Equal(t, 3, stats.Services)
+	assert.Equal(t, 3, stats.Depth)
+	assert.Equal(t, 100*time.Millisecond, stats.Duration)
+	assert.Equal(t, []*StatsGroup{
+		{Service: "api", Operation: "GET", Count: 1, Total: 30 * time.Millisecond,
+			Min: 30 * time.Millisecond, Max: 30 * time.Millisecond, SelfTime: 20 * time.Millisecond},
+		{Service: "db", Operation: "SELECT", Count: 2, Errors: 1, Total: 50 * time.Millisecond,
+			Min: 20 * time.Millisecond, Max: 30 * time.Millisecond, SelfTime: 50 * time.Millisecond},
+		{Service: "db", Operation: "UPDATE", Count: 1, Total: 10 * time.Millisecond,
+			Min: 10 * time.Millisecond, Max: 10 * time.Millisecond, SelfTime: 10 * time.Millisecond},
+		// the children run from 10ms to 50ms and from 60ms to 90ms
+		{Service: "frontend", Operation: "GET", Count: 1, Total: 100 * time.Millisecond,
+			Min: 100 * time.Millisecond, Max: 100 * time.Millisecond, SelfTime: 30 * time.Millisecond},
+	}, stats.Groups)
+	assert.Equal(t, 25*time.Millisecond, stats.Groups[1].Average())
+
+	stats = ComputeStats(trace, "db.instance")
+	assert.Equal(t, "db.instance", stats.GroupByTag)
+	require.Len(t, stats.Groups, 5)
+	assert.Equal(t, "SELECT", stats.Groups[1].Operation)
+	assert.Equal(t, "orders", stats.Groups[1].TagValue)
+	assert.Equal(t, 1, stats.Groups[1].Errors)
+	assert.Equal(t, "SELECT", stats.Groups[2].Operation)
+	assert.Equal(t, "users", stats.Groups[2].TagValue)
+	assert.Equal(t, "", stats.Groups[3].TagValue)
+}
+
+func TestComputeStatsEmptyTrace(t *testing.T) {
+	stats := ComputeStats(&model.Trace{}, "")
+	assert.Equal(t, &TraceStats{}, stats)
+	assert.Equal(t, time.Duration(0), (&StatsGroup{}).Average())
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// node is a span in the tree of a trace.
+type node struct {
+	span *model.Span
+	// followsFrom is true if the span is linked to its parent by a FOLLOWS_FROM reference
+	followsFrom bool
+	children    []*node
+}
+
+func (n *node) start() time.Time {
+	return n.span.StartTime
+}
+
+func (n *node) end() time.Time {
+	return n.span.StartTime.Add(n.span.Duration)
+}
+
+// buildTree links the spans of the trace to their parents, as model.SpanTree does, and returns the roots.
+// The roots and the children of each span are sorted by start time.
+//
+// The spans are expected to have unique IDs, as ensured by adjuster.SpanIDDeduper.
+func buildTree(trace *model.Trace) []*node {
+	tree := model.NewSpanTree(trace.Spans)
+	visited := make(map[*model.Span]struct{}, len(trace.Spans))
+	roots := newNodes(tree, tree.Roots(), visited)
+	queue := append([]*node(nil), roots...)
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		n.children = newNodes(tree, tree.Children(n.span.SpanID), visited)
+		queue = append(queue, n.children...)
+	}
+	return roots
+}
+
+func newNodes(tree *model.SpanTree, spans []*model.Span, visited map[*model.Span]struct{}) []*node {
+	var nodes []*node
+	for _, span := range spans {
+		if _, ok := visited[span]; ok {
+			continue
+		}
+		visited[span] = struct{}{}
+		nodes = append(nodes, &node{span: span, followsFrom: tree.IsFollowsFrom(span)})
+	}
+	sortByStartTime(nodes)
+	return nodes
+}
+
+func sortByStartTime(nodes []*node) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].start().Before(nodes[j].start())
+	})
+}
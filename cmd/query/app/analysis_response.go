@@ -0,0 +1,327 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"time"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/analysis"
+	"github.com/jaegertracing/jaeger/model"
+	ui "github.com/jaegertracing/jaeger/model/json"
+)
+
+// The responses of the trace analysis APIs, with times in microseconds like the UI model.
+
+// traceDiffResponse compares the baseline traces A with the traces B.
+type traceDiffResponse struct {
+	A     traceDiffSummary `json:"a"`
+	B     traceDiffSummary `json:"b"`
+	Roots []*traceDiffNode `json:"roots"`
+}
+
+type traceDiffSummary struct {
+	TraceIDs []ui.TraceID `json:"traceIDs"`
+	Spans    float64      `json:"spans"`
+	Duration uint64       `json:"duration"`
+}
+
+type traceDiffNode struct {
+	ServiceName   string           `json:"serviceName"`
+	OperationName string           `json:"operationName"`
+	Status        string           `json:"status"`
+	CountA        float64          `json:"countA"`
+	CountB        float64          `json:"countB"`
+	CountDelta    float64          `json:"countDelta"`
+	DurationA     uint64           `json:"durationA"`
+	DurationB     uint64           `json:"durationB"`
+	DurationDelta int64            `json:"durationDelta"`
+	Children      []*traceDiffNode `json:"children"`
+}
+
+func newTraceDiffResponse(traceIDsA, traceIDsB []model.TraceID, diff *analysis.TraceDiff) traceDiffResponse {
+	return traceDiffResponse{
+		A:     newTraceDiffSummary(traceIDsA, diff.A),
+		B:     newTraceDiffSummary(traceIDsB, diff.B),
+		Roots: traceDiffNodes(diff.Roots),
+	}
+}
+
+func newTraceDiffSummary(traceIDs []model.TraceID, summary analysis.DiffSummary) traceDiffSummary {
+	uiTraceIDs := make([]ui.TraceID, len(traceIDs))
+	for i, traceID := range traceIDs {
+		uiTraceIDs[i] = ui.TraceID(traceID.String())
+	}
+	return traceDiffSummary{
+		TraceIDs: uiTraceIDs,
+		Spans:    summary.Spans,
+		Duration: model.DurationAsMicroseconds(summary.Duration),
+	}
+}
+
+func traceDiffNodes(nodes []*analysis.DiffNode) []*traceDiffNode {
+	uiNodes := make([]*traceDiffNode, len(nodes))
+	for i, n := range nodes {
+		uiNodes[i] = &traceDiffNode{
+			ServiceName:   n.Service,
+			OperationName: n.Operation,
+			Status:        string(n.Status),
+			CountA:        n.CountA,
+			CountB:        n.CountB,
+			CountDelta:    n.CountDelta(),
+			DurationA:     model.DurationAsMicroseconds(n.DurationA),
+			DurationB:     model.DurationAsMicroseconds(n.DurationB),
+			DurationDelta: int64(n.DurationDelta() / time.Microsecond),
+			Children:      traceDiffNodes(n.Children),
+		}
+	}
+	return uiNodes
+}
+
+// traceStatsResponse summarizes a trace.
+type traceStatsResponse struct {
+	TraceID           ui.TraceID        `json:"traceID"`
+	RootServiceName   string            `json:"rootServiceName"`
+	RootOperationName string            `json:"rootOperationName"`
+	StartTime         uint64            `json:"startTime"`
+	Duration          uint64            `json:"duration"`
+	Spans             int               `json:"spans"`
+	Errors            int               `json:"errors"`
+	Services          int               `json:"services"`
+	Depth             int               `json:"depth"`
+	GroupByTag        string            `json:"groupByTag,omitempty"`
+	Groups            []traceStatsGroup `json:"groups"`
+}
+
+type traceStatsGroup struct {
+	ServiceName   string `json:"serviceName"`
+	OperationName string `json:"operationName"`
+	TagValue      string `json:"tagValue,omitempty"`
+	Count         int    `json:"count"`
+	Errors        int    `json:"errors"`
+	Total         uint64 `json:"total"`
+	Average       uint64 `json:"avg"`
+	Min           uint64 `json:"min"`
+	Max           uint64 `json:"max"`
+	SelfTime      uint64 `json:"selfTime"`
+}
+
+func newTraceStatsResponse(traceID model.TraceID, stats *analysis.TraceStats) *traceStatsResponse {
+	response := &traceStatsResponse{
+		TraceID:           ui.TraceID(traceID.String()),
+		RootServiceName:   stats.RootService,
+		RootOperationName: stats.RootOperation,
+		Duration:          model.DurationAsMicroseconds(stats.Duration),
+		Spans:             stats.Spans,
+		Errors:            stats.Errors,
+		Services:          stats.Services,
+		Depth:             stats.Depth,
+		GroupByTag:        stats.GroupByTag,
+		Groups:            make([]traceStatsGroup, len(stats.Groups)),
+	}
+	if !stats.StartTime.IsZero() {
+		response.StartTime = model.TimeAsEpochMicroseconds(stats.StartTime)
+	}
+	for i, group := range stats.Groups {
+		response.Groups[i] = traceStatsGroup{
+			ServiceName:   group.Service,
+			OperationName: group.Operation,
+			TagValue:      group.TagValue,
+			Count:         group.Count,
+			Errors:        group.Errors,
+			Total:         model.DurationAsMicroseconds(group.Total),
+			Average:       model.DurationAsMicroseconds(group.Average()),
+			Min:           model.DurationAsMicroseconds(group.Min),
+			Max:           model.DurationAsMicroseconds(group.Max),
+			SelfTime:      model.DurationAsMicroseconds(group.SelfTime),
+		}
+	}
+	return response
+}
+
+// regressionResponse lists the operations of a service whose latency or error rate changed significantly
+// between two windows, ranked by decreasing score.
+type regressionResponse struct {
+	ServiceName string                `json:"serviceName"`
+	Before      regressionWindow      `json:"before"`
+	After       regressionWindow      `json:"after"`
+	Operations  []operationRegression `json:"operations"`
+}
+
+type regressionWindow struct {
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+	// Source is where the stats come from, spanMetrics or traces.
+	Source string `json:"source"`
+	// Traces is the number of the newest traces of the window that were read, at most the limit.
+	Traces int `json:"traces"`
+}
+
+type operationRegression struct {
+	OperationName string             `json:"operationName"`
+	Score         float64            `json:"score"`
+	Changes       []regressionChange `json:"changes"`
+	Before        operationStats     `json:"before"`
+	After         operationStats     `json:"after"`
+}
+
+// regressionChange is a significant change of a latency percentile, in microseconds, or of the error rate.
+type regressionChange struct {
+	Metric string  `json:"metric"`
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Score  float64 `json:"score"`
+}
+
+type operationStats struct {
+	Calls     int          `json:"calls"`
+	Errors    int          `json:"errors"`
+	ErrorRate float64      `json:"errorRate"`
+	P50       uint64       `json:"p50"`
+	P95       uint64       `json:"p95"`
+	P99       uint64       `json:"p99"`
+	TraceIDs  []ui.TraceID `json:"traceIDs"`
+}
+
+func newRegressionResponse(service string, before, after window, regressions []*analysis.Regression) regressionResponse {
+	response := regressionResponse{
+		ServiceName: service,
+		Before:      newRegressionWindow(before),
+		After:       newRegressionWindow(after),
+		Operations:  make([]operationRegression, len(regressions)),
+	}
+	for i, r := range regressions {
+		changes := make([]regressionChange, len(r.Changes))
+		for j, c := range r.Changes {
+			changes[j] = regressionChange{Metric: c.Metric, Before: c.Before, After: c.After, Score: c.Score}
+			if c.Metric != analysis.MetricErrorRate {
+				changes[j].Before = c.Before / float64(time.Microsecond)
+				changes[j].After = c.After / float64(time.Microsecond)
+			}
+		}
+		response.Operations[i] = operationRegression{
+			OperationName: r.Operation,
+			Score:         r.Score,
+			Changes:       changes,
+			Before:        newOperationStats(r.Before),
+			After:         newOperationStats(r.After),
+		}
+	}
+	return response
+}
+
+func newRegressionWindow(w window) regressionWindow {
+	return regressionWindow{
+		Start:  model.TimeAsEpochMicroseconds(w.start),
+		End:    model.TimeAsEpochMicroseconds(w.end),
+		Source: w.source,
+		Traces: w.traces,
+	}
+}
+
+func newOperationStats(stats *analysis.OperationStats) operationStats {
+	traceIDs := make([]ui.TraceID, len(stats.Examples))
+	for i, traceID := range stats.Examples {
+		traceIDs[i] = ui.TraceID(traceID.String())
+	}
+	return operationStats{
+		Calls:     stats.Calls,
+		Errors:    stats.Errors,
+		ErrorRate: stats.ErrorRate(),
+		P50:       model.DurationAsMicroseconds(stats.P50),
+		P95:       model.DurationAsMicroseconds(stats.P95),
+		P99:       model.DurationAsMicroseconds(stats.P99),
+		TraceIDs:  traceIDs,
+	}
+}
+
+// traceCluster is a group of traces with the same call tree shape.
+type traceCluster struct {
+	Signature         string       `json:"signature"`
+	RootServiceName   string       `json:"rootServiceName"`
+	RootOperationName string       `json:"rootOperationName"`
+	Count             int          `json:"count"`
+	Errors            int          `json:"errors"`
+	Min               uint64       `json:"min"`
+	P50               uint64       `json:"p50"`
+	P95               uint64       `json:"p95"`
+	P99               uint64       `json:"p99"`
+	Max               uint64       `json:"max"`
+	Representative    ui.TraceID   `json:"representativeTraceID"`
+	TraceIDs          []ui.TraceID `json:"traceIDs"`
+}
+
+func newTraceClustersResponse(clusters []*analysis.Cluster) []traceCluster {
+	response := make([]traceCluster, len(clusters))
+	for i, c := range clusters {
+		traceIDs := make([]ui.TraceID, len(c.TraceIDs))
+		for j, traceID := range c.TraceIDs {
+			traceIDs[j] = ui.TraceID(traceID.String())
+		}
+		response[i] = traceCluster{
+			Signature:         c.Signature,
+			RootServiceName:   c.RootService,
+			RootOperationName: c.RootOperation,
+			Count:             c.Count,
+			Errors:            c.Errors,
+			Min:               model.DurationAsMicroseconds(c.Min),
+			P50:               model.DurationAsMicroseconds(c.P50),
+			P95:               model.DurationAsMicroseconds(c.P95),
+			P99:               model.DurationAsMicroseconds(c.P99),
+			Max:               model.DurationAsMicroseconds(c.Max),
+			Representative:    ui.TraceID(c.Representative.String()),
+			TraceIDs:          traceIDs,
+		}
+	}
+	return response
+}
+
+// serviceQuality is the instrumentation quality of a service, with a score between 0 and 100.
+type serviceQuality struct {
+	ServiceName     string         `json:"serviceName"`
+	Score           float64        `json:"score"`
+	Traces          int            `json:"traces"`
+	Spans           int            `json:"spans"`
+	SpansWithIssues int            `json:"spansWithIssues"`
+	Issues          []qualityIssue `json:"issues"`
+}
+
+type qualityIssue struct {
+	Issue    string       `json:"issue"`
+	Spans    int          `json:"spans"`
+	TraceIDs []ui.TraceID `json:"traceIDs"`
+}
+
+func newServiceQualityResponse(quality []*analysis.ServiceQuality) []serviceQuality {
+	response := make([]serviceQuality, len(quality))
+	for i, q := range quality {
+		issues := make([]qualityIssue, len(q.Issues))
+		for j, issue := range q.Issues {
+			traceIDs := make([]ui.TraceID, len(issue.Examples))
+			for k, traceID := range issue.Examples {
+				traceIDs[k] = ui.TraceID(traceID.String())
+			}
+			issues[j] = qualityIssue{Issue: issue.Issue, Spans: issue.Spans, TraceIDs: traceIDs}
+		}
+		response[i] = serviceQuality{
+			ServiceName:     q.Service,
+			Score:           q.Score(),
+			Traces:          q.Traces,
+			Spans:           q.Spans,
+			SpansWithIssues: q.SpansWithIssues,
+			Issues:          issues,
+		}
+	}
+	return response
+}
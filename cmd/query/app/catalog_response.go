@@ -0,0 +1,66 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// serviceActivity is the activity of a service in the service catalog, with times in microseconds like the UI model.
+type serviceActivity struct {
+	ServiceName    string    `json:"serviceName"`
+	LastSeen       uint64    `json:"lastSeen"`
+	SpanRates      spanRates `json:"spanRates"`
+	OperationCount int       `json:"operationCount"`
+	ClientVersions []string  `json:"clientVersions"`
+	Hostnames      []string  `json:"hostnames"`
+	ClientUUIDs    []string  `json:"clientUUIDs"`
+}
+
+// spanRates are the spans per second averaged over the last 1, 5 and 15 minutes.
+type spanRates struct {
+	OneMinute      float64 `json:"1m"`
+	FiveMinutes    float64 `json:"5m"`
+	FifteenMinutes float64 `json:"15m"`
+}
+
+func newServiceCatalogResponse(catalog []spanstore.ServiceActivity) []serviceActivity {
+	services := make([]serviceActivity, len(catalog))
+	for i, a := range catalog {
+		services[i] = serviceActivity{
+			ServiceName: a.ServiceName,
+			LastSeen:    model.TimeAsEpochMicroseconds(a.LastSeen),
+			SpanRates: spanRates{
+				OneMinute:      a.SpanRate1m,
+				FiveMinutes:    a.SpanRate5m,
+				FifteenMinutes: a.SpanRate15m,
+			},
+			OperationCount: a.OperationCount,
+			ClientVersions: nonNil(a.ClientVersions),
+			Hostnames:      nonNil(a.Hostnames),
+			ClientUUIDs:    nonNil(a.ClientUUIDs),
+		}
+	}
+	return services
+}
+
+// nonNil returns an empty slice for nil, so that it is encoded as an empty JSON array.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// The types below follow the JSON object format of the Chrome trace event format,
+// see https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU.
+type chromeTrace struct {
+	TraceEvents     []chromeEvent `json:"traceEvents"`
+	DisplayTimeUnit string        `json:"displayTimeUnit"`
+}
+
+type chromeEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat,omitempty"`
+	Phase     string            `json:"ph"`
+	Timestamp int64             `json:"ts"`
+	Duration  int64             `json:"dur,omitempty"`
+	ProcessID int               `json:"pid"`
+	ThreadID  int               `json:"tid"`
+	Scope     string            `json:"s,omitempty"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+const (
+	chromePhaseComplete = "X"
+	chromePhaseInstant  = "i"
+	chromePhaseMetadata = "M"
+	// chromeScopeThread draws instant events on the thread track of their span.
+	chromeScopeThread = "t"
+
+	chromeProcessNameEvent = "process_name"
+	chromeLogEventName     = "log"
+)
+
+// chromeFromDomain converts a trace into trace events, with one process per service. Since the complete
+// events of a thread must nest, each service lays its spans out on as few threads as nesting allows.
+func chromeFromDomain(trace *model.Trace) *chromeTrace {
+	result := &chromeTrace{TraceEvents: []chromeEvent{}, DisplayTimeUnit: "ms"}
+	spans := make([]*model.Span, len(trace.Spans))
+	copy(spans, trace.Spans)
+	sort.SliceStable(spans, func(i, j int) bool {
+		if !spans[i].StartTime.Equal(spans[j].StartTime) {
+			return spans[i].StartTime.Before(spans[j].StartTime)
+		}
+		// enclosing spans first
+		return spans[i].Duration > spans[j].Duration
+	})
+
+	processIDs := make(map[string]int)
+	threads := make(map[int][]*chromeThread)
+	for _, span := range spans {
+		service := ""
+		if span.Process != nil {
+			service = span.Process.ServiceName
+		}
+		pid, ok := processIDs[service]
+		if !ok {
+			pid = len(processIDs) + 1
+			processIDs[service] = pid
+			result.TraceEvents = append(result.TraceEvents, chromeEvent{
+				Name:      chromeProcessNameEvent,
+				Phase:     chromePhaseMetadata,
+				ProcessID: pid,
+				Args:      map[string]string{"name": service},
+			})
+		}
+		tid := chromePlaceSpan(threads, pid, span)
+		result.TraceEvents = append(result.TraceEvents, chromeEvent{
+			Name:      span.OperationName,
+			Category:  service,
+			Phase:     chromePhaseComplete,
+			Timestamp: int64(model.TimeAsEpochMicroseconds(span.StartTime)),
+			Duration:  int64(model.DurationAsMicroseconds(span.Duration)),
+			ProcessID: pid,
+			ThreadID:  tid,
+			Args:      chromeArgs(span.Tags, map[string]string{"spanID": span.SpanID.String()}),
+		})
+		for _, log := range span.Logs {
+			result.TraceEvents = append(result.TraceEvents, chromeEvent{
+				Name:      chromeLogEventName,
+				Category:  service,
+				Phase:     chromePhaseInstant,
+				Timestamp: int64(model.TimeAsEpochMicroseconds(log.Timestamp)),
+				ProcessID: pid,
+				ThreadID:  tid,
+				Scope:     chromeScopeThread,
+				Args:      chromeArgs(log.Fields, nil),
+			})
+		}
+	}
+	return result
+}
+
+// chromeThread holds the end times of the spans open on a thread, innermost last.
+type chromeThread struct {
+	open []time.Time
+}
+
+// chromePlaceSpan returns the ID of the first thread of the process where the span nests,
+// i.e. it starts after the other spans of the thread end or it fits into the innermost open one.
+// Spans must be placed in the order of their start time.
+func chromePlaceSpan(threads map[int][]*chromeThread, pid int, span *model.Span) int {
+	end := span.StartTime.Add(span.Duration)
+	for i, thread := range threads[pid] {
+		for len(thread.open) > 0 && !thread.open[len(thread.open)-1].After(span.StartTime) {
+			thread.open = thread.open[:len(thread.open)-1]
+		}
+		if len(thread.open) == 0 || !end.After(thread.open[len(thread.open)-1]) {
+			thread.open = append(thread.open, end)
+			return i + 1
+		}
+	}
+	threads[pid] = append(threads[pid], &chromeThread{open: []time.Time{end}})
+	return len(threads[pid])
+}
+
+func chromeArgs(kvs []model.KeyValue, args map[string]string) map[string]string {
+	if args == nil {
+		args = make(map[string]string, len(kvs))
+	}
+	for _, kv := range kvs {
+		args[kv.Key] = kv.AsString()
+	}
+	return args
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestChromeFromDomain(t *testing.T) {
+	trace := chromeFromDomain(makeTestTrace())
+	assert.Equal(t, "ms", trace.DisplayTimeUnit)
+	start := testStartTime.UnixNano() / 1000
+	assert.Equal(t, []chromeEvent{
+		{Name: "process_name", Phase: "M", ProcessID: 1, Args: map[string]string{"name": "frontend"}},
+		{
+			Name: "GET /api", Category: "frontend", Phase: "X", Timestamp: start, Duration: 10000, ProcessID: 1, ThreadID: 1,
+			Args: map[string]string{"spanID": "0000000000000001", "span.kind": "server", "http.status_code": "503"},
+		},
+		{Name: "process_name", Phase: "M", ProcessID: 2, Args: map[string]string{"name": "db"}},
+		{
+			Name: "SELECT", Category: "db", Phase: "X", Timestamp: start + 1000, Duration: 5000, ProcessID: 2, ThreadID: 1,
+			Args: map[string]string{"spanID": "0000000000000002", "error": "true"},
+		},
+		{
+			Name: "log", Category: "db", Phase: "i", Timestamp: start + 2000, ProcessID: 2, ThreadID: 1, Scope: "t",
+			Args: map[string]string{"event": "retry"},
+		},
+	}, trace.TraceEvents)
+}
+
+func TestChromePlaceSpan(t *testing.T) {
+	makeSpan := func(startMillis, durationMillis int) *model.Span {
+		return &model.Span{
+			StartTime: testStartTime.Add(time.Duration(startMillis) * time.Millisecond),
+			Duration:  time.Duration(durationMillis) * time.Millisecond,
+		}
+	}
+	threads := make(map[int][]*chromeThread)
+	// spans in the order of their start time
+	assert.Equal(t, 1, chromePlaceSpan(threads, 1, makeSpan(0, 100)))  // outer span
+	assert.Equal(t, 1, chromePlaceSpan(threads, 1, makeSpan(10, 20)))  // nested in the outer span
+	assert.Equal(t, 2, chromePlaceSpan(threads, 1, makeSpan(20, 100))) // overlaps the end of the outer span
+	assert.Equal(t, 1, chromePlaceSpan(threads, 1, makeSpan(40, 10)))  // nested again after the first child ended
+	assert.Equal(t, 1, chromePlaceSpan(threads, 1, makeSpan(150, 10))) // after all spans of the first thread
+	assert.Equal(t, 1, chromePlaceSpan(threads, 2, makeSpan(0, 10)))   // threads are per process
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/zipkin"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+// Format is a file format that traces can be exported to.
+type Format string
+
+const (
+	// FormatJaeger is the JSON encoding of the api_v2 SpansResponseChunk proto.
+	FormatJaeger Format = "jaeger"
+	// FormatOTLP is the JSON encoding of an OpenTelemetry ExportTraceServiceRequest.
+	FormatOTLP Format = "otlp"
+	// FormatZipkin is the Zipkin v2 JSON list of spans.
+	FormatZipkin Format = "zipkin"
+	// FormatChrome is the Chrome trace event format, which is also opened by Perfetto.
+	FormatChrome Format = "chrome"
+)
+
+var mediaTypes = map[Format]string{
+	FormatJaeger: "application/vnd.jaegertracing.api-v2+json",
+	FormatOTLP:   "application/vnd.opentelemetry.otlp+json",
+	FormatZipkin: "application/vnd.zipkin.v2+json",
+	FormatChrome: "application/vnd.chrome.trace-event+json",
+}
+
+// Formats returns the supported formats.
+func Formats() []Format {
+	return []Format{FormatJaeger, FormatOTLP, FormatZipkin, FormatChrome}
+}
+
+// MediaType returns the media type of the format, which is a vendor-specific JSON media type.
+func (f Format) MediaType() string {
+	return mediaTypes[f]
+}
+
+// ParseFormat returns the format with the given name.
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(name))
+	if _, ok := mediaTypes[format]; !ok {
+		return "", fmt.Errorf("unsupported export format '%s', expecting one of %v", name, Formats())
+	}
+	return format, nil
+}
+
+// Negotiate returns the format named by the format parameter if it is set, otherwise the first format
+// whose media type is listed in the Accept header. It defaults to FormatJaeger.
+func Negotiate(formatParam string, accept string) (Format, error) {
+	if formatParam != "" {
+		return ParseFormat(formatParam)
+	}
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		for format, formatMediaType := range mediaTypes {
+			if mediaType == formatMediaType {
+				return format, nil
+			}
+		}
+	}
+	return FormatJaeger, nil
+}
+
+// Export writes the trace to w in the given format.
+func Export(w io.Writer, trace *model.Trace, format Format) error {
+	switch format {
+	case FormatJaeger:
+		chunk := &api_v2.SpansResponseChunk{Spans: make([]model.Span, 0, len(trace.Spans))}
+		for _, span := range trace.Spans {
+			chunk.Spans = append(chunk.Spans, *span)
+		}
+		return new(jsonpb.Marshaler).Marshal(w, chunk)
+	case FormatOTLP:
+		return json.NewEncoder(w).Encode(otlpFromDomain(trace))
+	case FormatZipkin:
+		return json.NewEncoder(w).Encode(zipkin.FromDomain(trace))
+	case FormatChrome:
+		return json.NewEncoder(w).Encode(chromeFromDomain(trace))
+	}
+	return fmt.Errorf("unsupported export format '%s', expecting one of %v", format, Formats())
+}
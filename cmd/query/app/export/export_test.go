@@ -0,0 +1,134 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/swagger-gen/models"
+)
+
+var testStartTime = time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+
+func makeTestTrace() *model.Trace {
+	traceID := model.NewTraceID(0, 0xabc)
+	frontend := model.NewProcess("frontend", []model.KeyValue{model.String("hostname", "host-1")})
+	return &model.Trace{Spans: []*model.Span{
+		{
+			TraceID:       traceID,
+			SpanID:        model.NewSpanID(1),
+			OperationName: "GET /api",
+			StartTime:     testStartTime,
+			Duration:      10 * time.Millisecond,
+			Tags:          model.KeyValues{model.String("span.kind", "server"), model.Int64("http.status_code", 503)},
+			Process:       frontend,
+		},
+		{
+			TraceID:       traceID,
+			SpanID:        model.NewSpanID(2),
+			OperationName: "SELECT",
+			References:    []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(1))},
+			StartTime:     testStartTime.Add(time.Millisecond),
+			Duration:      5 * time.Millisecond,
+			Tags:          model.KeyValues{model.Bool("error", true)},
+			Logs: []model.Log{
+				{Timestamp: testStartTime.Add(2 * time.Millisecond), Fields: model.KeyValues{model.String("event", "retry")}},
+			},
+			Process: model.NewProcess("db", nil),
+		},
+	}}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range Formats() {
+		parsed, err := ParseFormat(string(format))
+		require.NoError(t, err)
+		assert.Equal(t, format, parsed)
+		assert.NotEmpty(t, format.MediaType())
+	}
+	format, err := ParseFormat("OTLP")
+	require.NoError(t, err)
+	assert.Equal(t, FormatOTLP, format)
+
+	_, err = ParseFormat("xml")
+	assert.EqualError(t, err, "unsupported export format 'xml', expecting one of [jaeger otlp zipkin chrome]")
+}
+
+func TestNegotiate(t *testing.T) {
+	testCases := []struct {
+		format   string
+		accept   string
+		expected Format
+		err      string
+	}{
+		{expected: FormatJaeger},
+		{accept: "application/json", expected: FormatJaeger},
+		{format: "zipkin", accept: "application/vnd.chrome.trace-event+json", expected: FormatZipkin},
+		{accept: "text/html;q=0.9, application/vnd.opentelemetry.otlp+json;q=0.8", expected: FormatOTLP},
+		{accept: "a/b/c, application/vnd.chrome.trace-event+json", expected: FormatChrome},
+		{format: "xml", err: "unsupported export format 'xml', expecting one of [jaeger otlp zipkin chrome]"},
+	}
+	for _, testCase := range testCases {
+		format, err := Negotiate(testCase.format, testCase.accept)
+		if testCase.err != "" {
+			assert.EqualError(t, err, testCase.err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, format, testCase.accept)
+	}
+}
+
+func TestExportJaeger(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Export(&buf, makeTestTrace(), FormatJaeger))
+	var chunk api_v2.SpansResponseChunk
+	require.NoError(t, jsonpb.Unmarshal(&buf, &chunk))
+	require.Len(t, chunk.Spans, 2)
+	assert.Equal(t, "GET /api", chunk.Spans[0].OperationName)
+	assert.Equal(t, "db", chunk.Spans[1].Process.ServiceName)
+}
+
+func TestExportZipkin(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Export(&buf, makeTestTrace(), FormatZipkin))
+	var spans models.ListOfSpans
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &spans))
+	require.Len(t, spans, 2)
+	assert.Equal(t, "SERVER", spans[0].Kind)
+}
+
+func TestExportOTLPAndChrome(t *testing.T) {
+	for _, format := range []Format{FormatOTLP, FormatChrome} {
+		var buf bytes.Buffer
+		require.NoError(t, Export(&buf, makeTestTrace(), format))
+		assert.True(t, json.Valid(buf.Bytes()), format)
+	}
+}
+
+func TestExportUnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	assert.EqualError(t, Export(&buf, makeTestTrace(), Format("xml")),
+		"unsupported export format 'xml', expecting one of [jaeger otlp zipkin chrome]")
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// The types below follow the OTLP/JSON encoding of ExportTraceServiceRequest,
+// see https://github.com/open-telemetry/opentelemetry-proto/blob/master/docs/specification.md#json-protobuf-encoding.
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Links             []otlpLink     `json:"links,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpLink struct {
+	TraceID string `json:"traceId"`
+	SpanID  string `json:"spanId"`
+}
+
+type otlpStatus struct {
+	Code int `json:"code,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BytesValue  *string  `json:"bytesValue,omitempty"`
+}
+
+// otlpSpanKindInternal and the following values are the OTLP span kinds, after the unspecified kind.
+const (
+	otlpSpanKindInternal = iota + 1
+	otlpSpanKindServer
+	otlpSpanKindClient
+	otlpSpanKindProducer
+	otlpSpanKindConsumer
+)
+
+// otlpStatusCodeError is the OTLP status code of a failed span.
+const otlpStatusCodeError = 2
+
+const (
+	otlpServiceNameKey = "service.name"
+	// otlpEventNameKey is the log field that holds the name of an event.
+	otlpEventNameKey     = "event"
+	otlpDefaultEventName = "log"
+)
+
+var otlpSpanKinds = map[string]int{
+	"internal":                        otlpSpanKindInternal,
+	string(ext.SpanKindRPCServerEnum): otlpSpanKindServer,
+	string(ext.SpanKindRPCClientEnum): otlpSpanKindClient,
+	string(ext.SpanKindProducerEnum):  otlpSpanKindProducer,
+	string(ext.SpanKindConsumerEnum):  otlpSpanKindConsumer,
+}
+
+// otlpFromDomain converts a trace into OTLP, with one resource per distinct process.
+func otlpFromDomain(trace *model.Trace) *otlpTraces {
+	traces := &otlpTraces{ResourceSpans: []otlpResourceSpans{}}
+	resources := make(map[uint64]int)
+	for _, span := range trace.Spans {
+		process := span.Process
+		if process == nil {
+			process = &model.Process{}
+		}
+		hash, _ := model.HashCode(process)
+		i, ok := resources[hash]
+		if !ok {
+			i = len(traces.ResourceSpans)
+			resources[hash] = i
+			attributes := []otlpKeyValue{otlpString(otlpServiceNameKey, process.ServiceName)}
+			traces.ResourceSpans = append(traces.ResourceSpans, otlpResourceSpans{
+				Resource:   otlpResource{Attributes: append(attributes, otlpAttributes(process.Tags)...)},
+				ScopeSpans: []otlpScopeSpans{{}},
+			})
+		}
+		scopeSpans := &traces.ResourceSpans[i].ScopeSpans[0]
+		scopeSpans.Spans = append(scopeSpans.Spans, otlpSpanFromDomain(span))
+	}
+	return traces
+}
+
+func otlpSpanFromDomain(span *model.Span) otlpSpan {
+	oSpan := otlpSpan{
+		TraceID:           otlpTraceID(span.TraceID),
+		SpanID:            span.SpanID.String(),
+		Name:              span.OperationName,
+		StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.StartTime.Add(span.Duration).UnixNano(), 10),
+	}
+	parentID := span.ParentSpanID()
+	if parentID != 0 {
+		oSpan.ParentSpanID = parentID.String()
+	}
+	for _, ref := range span.References {
+		if ref.SpanID == parentID && ref.TraceID == span.TraceID {
+			continue
+		}
+		oSpan.Links = append(oSpan.Links, otlpLink{TraceID: otlpTraceID(ref.TraceID), SpanID: ref.SpanID.String()})
+	}
+	for _, tag := range span.Tags {
+		switch tag.Key {
+		case string(ext.SpanKind):
+			if kind, ok := otlpSpanKinds[tag.AsString()]; ok {
+				oSpan.Kind = kind
+				continue
+			}
+		case string(ext.Error):
+			if tag.AsString() == "true" {
+				oSpan.Status.Code = otlpStatusCodeError
+				continue
+			}
+		}
+		oSpan.Attributes = append(oSpan.Attributes, otlpAttribute(tag))
+	}
+	for _, log := range span.Logs {
+		event := otlpEvent{
+			TimeUnixNano: strconv.FormatInt(log.Timestamp.UnixNano(), 10),
+			Name:         otlpDefaultEventName,
+		}
+		for _, field := range log.Fields {
+			if field.Key == otlpEventNameKey && field.VType == model.StringType {
+				event.Name = field.VStr
+				continue
+			}
+			event.Attributes = append(event.Attributes, otlpAttribute(field))
+		}
+		oSpan.Events = append(oSpan.Events, event)
+	}
+	return oSpan
+}
+
+// otlpTraceID returns the trace ID as 32 hex characters, as OTLP does not allow 64 bit trace IDs.
+func otlpTraceID(traceID model.TraceID) string {
+	return fmt.Sprintf("%016x%016x", traceID.High, traceID.Low)
+}
+
+func otlpAttributes(kvs []model.KeyValue) []otlpKeyValue {
+	attributes := make([]otlpKeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		attributes = append(attributes, otlpAttribute(kv))
+	}
+	return attributes
+}
+
+func otlpString(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: &value}}
+}
+
+func otlpAttribute(kv model.KeyValue) otlpKeyValue {
+	attribute := otlpKeyValue{Key: kv.Key}
+	switch kv.VType {
+	case model.BoolType:
+		v := kv.Bool()
+		attribute.Value.BoolValue = &v
+	case model.Int64Type:
+		// OTLP/JSON encodes 64 bit integers as strings
+		v := strconv.FormatInt(kv.Int64(), 10)
+		attribute.Value.IntValue = &v
+	case model.Float64Type:
+		v := kv.Float64()
+		attribute.Value.DoubleValue = &v
+	case model.BinaryType:
+		v := base64.StdEncoding.EncodeToString(kv.Binary())
+		attribute.Value.BytesValue = &v
+	default:
+		v := kv.AsString()
+		attribute.Value.StringValue = &v
+	}
+	return attribute
+}
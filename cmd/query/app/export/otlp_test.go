@@ -0,0 +1,92 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestOTLPFromDomain(t *testing.T) {
+	trace := makeTestTrace()
+	// a span of the same process in a separate object, e.g. as read from storage
+	trace.Spans = append(trace.Spans, &model.Span{
+		TraceID:    trace.Spans[0].TraceID,
+		SpanID:     model.NewSpanID(3),
+		References: []model.SpanRef{model.NewFollowsFromRef(model.NewTraceID(0, 0xdef), model.NewSpanID(4))},
+		StartTime:  testStartTime,
+		Process:    model.NewProcess("frontend", []model.KeyValue{model.String("hostname", "host-1")}),
+	})
+
+	traces := otlpFromDomain(trace)
+	require.Len(t, traces.ResourceSpans, 2)
+	frontend, db := traces.ResourceSpans[0], traces.ResourceSpans[1]
+	assert.Equal(t, []otlpKeyValue{otlpString("service.name", "frontend"), otlpString("hostname", "host-1")}, frontend.Resource.Attributes)
+	assert.Equal(t, []otlpKeyValue{otlpString("service.name", "db")}, db.Resource.Attributes)
+	require.Len(t, frontend.ScopeSpans[0].Spans, 2)
+	require.Len(t, db.ScopeSpans[0].Spans, 1)
+
+	server := frontend.ScopeSpans[0].Spans[0]
+	assert.Equal(t, "00000000000000000000000000000abc", server.TraceID)
+	assert.Equal(t, "0000000000000001", server.SpanID)
+	assert.Empty(t, server.ParentSpanID)
+	assert.Equal(t, otlpSpanKindServer, server.Kind)
+	assert.Equal(t, "1585735200000000000", server.StartTimeUnixNano)
+	assert.Equal(t, "1585735200010000000", server.EndTimeUnixNano)
+	statusCode := "503"
+	assert.Equal(t, []otlpKeyValue{{Key: "http.status_code", Value: otlpAnyValue{IntValue: &statusCode}}}, server.Attributes)
+	assert.Equal(t, otlpStatus{}, server.Status)
+
+	query := db.ScopeSpans[0].Spans[0]
+	assert.Equal(t, "0000000000000001", query.ParentSpanID)
+	assert.Empty(t, query.Links)
+	assert.Empty(t, query.Attributes)
+	assert.Equal(t, otlpStatus{Code: otlpStatusCodeError}, query.Status)
+	assert.Equal(t, []otlpEvent{{TimeUnixNano: "1585735200002000000", Name: "retry"}}, query.Events)
+
+	follower := frontend.ScopeSpans[0].Spans[1]
+	assert.Equal(t, []otlpLink{{TraceID: "00000000000000000000000000000def", SpanID: "0000000000000004"}}, follower.Links)
+}
+
+func TestOTLPAttribute(t *testing.T) {
+	trueValue, intValue, floatValue, bytesValue, stringValue := true, "42", 1.5, "AQI=", "v"
+	testCases := []struct {
+		kv       model.KeyValue
+		expected otlpAnyValue
+	}{
+		{model.Bool("k", true), otlpAnyValue{BoolValue: &trueValue}},
+		{model.Int64("k", 42), otlpAnyValue{IntValue: &intValue}},
+		{model.Float64("k", 1.5), otlpAnyValue{DoubleValue: &floatValue}},
+		{model.Binary("k", []byte{1, 2}), otlpAnyValue{BytesValue: &bytesValue}},
+		{model.String("k", "v"), otlpAnyValue{StringValue: &stringValue}},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, otlpKeyValue{Key: "k", Value: testCase.expected}, otlpAttribute(testCase.kv))
+	}
+}
+
+func TestOTLPEventWithoutName(t *testing.T) {
+	span := otlpSpanFromDomain(&model.Span{
+		Logs: []model.Log{{Timestamp: time.Unix(0, 1), Fields: model.KeyValues{model.String("level", "info")}}},
+	})
+	require.Len(t, span.Events, 1)
+	assert.Equal(t, "log", span.Events[0].Name)
+	assert.Equal(t, []otlpKeyValue{otlpString("level", "info")}, span.Events[0].Attributes)
+}
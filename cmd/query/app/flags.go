@@ -27,7 +27,9 @@ import (
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/ports"
 )
 
@@ -38,8 +40,29 @@ const (
 	queryUIConfig          = "query.ui-config"
 	queryTokenPropagation  = "query.bearer-token-propagation"
 	queryAdditionalHeaders = "query.additional-headers"
+	queryImportEnabled     = "query.import.enabled"
+	queryImportStorage     = "query.import.storage-writes"
+	queryImportMaxTraces   = "query.import.max-traces"
+	queryLiveTailHostPorts = "query.live-tail.collectors"
+	queryLiveTailBuffer    = "query.live-tail.buffer-size"
+	queryCatalogHostPorts  = "query.catalog.collectors"
+	querySpanMetricsHosts  = "query.span-metrics.collectors"
+	queryMinClientVersions = "query.quality.min-client-versions"
+	queryTraceLinksDepth   = "query.trace-links.max-depth"
+	queryTraceLinksSpans   = "query.trace-links.max-spans"
+	queryTraceLinksFetches = "query.trace-links.max-fetches"
+	queryRedactionRules    = "query.redaction.rules"
+	queryRedactionHeader   = "query.redaction.identity-header"
+	queryRedactionGRPC     = "query.redaction.trust-grpc-identity"
+	queryRedactionHashKey  = "query.redaction.hash-key-file"
+	queryAdjusters         = "query.adjusters"
+	queryTraceMaxSpans     = "query.trace-limits.max-spans"
+	queryTraceMaxReadSpans = "query.trace-limits.max-read-spans"
+	queryTraceMinSiblings  = "query.trace-limits.min-siblings"
 )
 
+const defaultImportMaxTraces = 10000
+
 // QueryOptions holds configuration for query service
 type QueryOptions struct {
 	// Port is the port that the query service listens in on
@@ -54,6 +77,36 @@ type QueryOptions struct {
 	BearerTokenPropagation bool
 	// AdditionalHeaders
 	AdditionalHeaders http.Header
+	// ImportEnabled activates the endpoint that imports trace files into an in-memory upload store
+	ImportEnabled bool
+	// ImportStorageWrites allows the import endpoint to write into the configured span storage
+	ImportStorageWrites bool
+	// ImportMaxTraces is the number of traces kept in the upload store, the oldest being evicted first
+	ImportMaxTraces int
+	// LiveTailCollectors are the host:port of the gRPC servers of the collectors streaming live spans
+	LiveTailCollectors []string
+	// LiveTailBufferSize is the number of spans buffered for a live tail subscriber before it is dropped
+	LiveTailBufferSize int
+	// CatalogCollectors are the host:port of the gRPC servers of the collectors whose service catalogs are merged
+	CatalogCollectors []string
+	// SpanMetricsCollectors are the host:port of the gRPC servers of the collectors whose span metrics are added up
+	SpanMetricsCollectors []string
+	// MinClientVersions are the minimum versions of the client libraries by language, below which the quality report flags them as outdated
+	MinClientVersions map[string]string
+	// TraceLinks limit the traces linked to a requested trace by the references of its spans to other traces
+	TraceLinks querysvc.TraceLinkOptions
+	// RedactionRules is the path to the JSON file of the rules redacting the span tags and log fields served to the callers
+	RedactionRules string
+	// RedactionIdentityHeader is the HTTP header or gRPC metadata identifying the callers to whom the redaction rules apply
+	RedactionIdentityHeader string
+	// RedactionTrustGRPCIdentity takes the identity of the gRPC callers from the metadata, which is not trusted by default
+	RedactionTrustGRPCIdentity bool
+	// RedactionHashKeyFile is the path to the file of the secret key of the hashes of the redaction rules
+	RedactionHashKeyFile string
+	// Adjusters are the names of the adjusters applied to the traces, in order; nil keeps the standard adjusters
+	Adjusters []string
+	// TraceLimits limit the spans of the traces returned by the GetTrace APIs
+	TraceLimits querysvc.TraceLimitOptions
 }
 
 // AddFlags adds flags for QueryOptions
@@ -64,6 +117,25 @@ func AddFlags(flagSet *flag.FlagSet) {
 	flagSet.String(queryStaticFiles, "", "The directory path override for the static assets for the UI")
 	flagSet.String(queryUIConfig, "", "The path to the UI configuration file in JSON format")
 	flagSet.Bool(queryTokenPropagation, false, "Allow propagation of bearer token to be used by storage plugins")
+	flagSet.Bool(queryImportEnabled, false, "Enable the endpoint that imports trace files into an in-memory store, shown under the 'uploaded/' services")
+	flagSet.Bool(queryImportStorage, false, "Allow the import endpoint to write spans into the configured span storage")
+	flagSet.Int(queryImportMaxTraces, defaultImportMaxTraces, "The maximum number of imported traces kept in the in-memory store, the oldest being evicted first")
+	flagSet.String(queryLiveTailHostPorts, "", "Comma-separated list of collectors' gRPC host:port to subscribe to for the live tail of spans, which must have collector.live-tail.enabled; not used by all-in-one")
+	flagSet.Int(queryLiveTailBuffer, livetail.DefaultBufferSize, "The number of spans buffered for a live tail subscriber, which is dropped when the buffer is full")
+	flagSet.String(queryCatalogHostPorts, "", "Comma-separated list of collectors' gRPC host:port whose service catalogs are merged; not used by all-in-one")
+	flagSet.String(querySpanMetricsHosts, "", "Comma-separated list of collectors' gRPC host:port whose span metrics are added up; not used by all-in-one")
+	flagSet.String(queryMinClientVersions, "", `Comma-separated list of minimum client library versions by language for the quality report, e.g. "Go=2.22.0,Java=1.1.0"; replaces the built-in defaults`)
+	flagSet.Int(queryTraceLinksDepth, querysvc.DefaultTraceLinkOptions.MaxDepth, "The maximum number of references followed from a requested trace to the traces it is linked to")
+	flagSet.Int(queryTraceLinksSpans, querysvc.DefaultTraceLinkOptions.MaxSpans, "The maximum number of spans of a requested trace and the traces it is linked to")
+	flagSet.Int(queryTraceLinksFetches, querysvc.DefaultTraceLinkOptions.MaxFetches, "The maximum number of linked traces read from the storage for a requested trace")
+	flagSet.String(queryRedactionRules, "", "The path to a JSON file of rules masking or hashing span tag and log field values by key, value pattern or service, unless the caller is exempt")
+	flagSet.String(queryRedactionHeader, "", "The HTTP header identifying the caller for the redaction rules; it must be set by a trusted authenticating proxy, which must also strip or verify it in the requests it forwards")
+	flagSet.Bool(queryRedactionGRPC, false, "Also identify the gRPC callers for the redaction rules by the identity header metadata, which any caller can set unless a trusted authenticating proxy strips or verifies it")
+	flagSet.String(queryRedactionHashKey, "", "The path to a file holding the secret key of the HMAC of the values hashed by the redaction rules; required by the rules that hash")
+	flagSet.String(queryAdjusters, strings.Join(querysvc.StandardAdjusterNames, ","), fmt.Sprintf("Comma-separated list of the adjusters applied to the traces, in order, among: %s; empty disables them", strings.Join(querysvc.AdjusterNames(), ", ")))
+	flagSet.Int(queryTraceMaxSpans, querysvc.DefaultTraceLimitOptions.MaxSpans, "The number of spans above which the traces returned by the GetTrace APIs are reduced, with a summary of the omitted spans; 0 for no limit. The gRPC GetTrace returns all the spans if requested")
+	flagSet.Int(queryTraceMaxReadSpans, querysvc.DefaultTraceLimitOptions.MaxReadSpans, "The number of spans read from the storage for a trace that is reduced, the rest being left unread, and above which the trace analyses and export fail; 0 for 10 times the max spans")
+	flagSet.Int(queryTraceMinSiblings, querysvc.DefaultTraceLimitOptions.MinSiblings, "The number of sibling spans of the same operation from which they are collapsed into a placeholder span when a trace is reduced; 0 disables collapsing")
 }
 
 // InitFromViper initializes QueryOptions with properties from viper
@@ -73,6 +145,38 @@ func (qOpts *QueryOptions) InitFromViper(v *viper.Viper, logger *zap.Logger) *Qu
 	qOpts.StaticAssets = v.GetString(queryStaticFiles)
 	qOpts.UIConfig = v.GetString(queryUIConfig)
 	qOpts.BearerTokenPropagation = v.GetBool(queryTokenPropagation)
+	qOpts.ImportEnabled = v.GetBool(queryImportEnabled)
+	qOpts.ImportStorageWrites = v.GetBool(queryImportStorage)
+	qOpts.ImportMaxTraces = v.GetInt(queryImportMaxTraces)
+	if hostPorts := v.GetString(queryLiveTailHostPorts); hostPorts != "" {
+		qOpts.LiveTailCollectors = strings.Split(hostPorts, ",")
+	}
+	qOpts.LiveTailBufferSize = v.GetInt(queryLiveTailBuffer)
+	if hostPorts := v.GetString(queryCatalogHostPorts); hostPorts != "" {
+		qOpts.CatalogCollectors = strings.Split(hostPorts, ",")
+	}
+	if hostPorts := v.GetString(querySpanMetricsHosts); hostPorts != "" {
+		qOpts.SpanMetricsCollectors = strings.Split(hostPorts, ",")
+	}
+	qOpts.TraceLinks.MaxDepth = v.GetInt(queryTraceLinksDepth)
+	qOpts.TraceLinks.MaxSpans = v.GetInt(queryTraceLinksSpans)
+	qOpts.TraceLinks.MaxFetches = v.GetInt(queryTraceLinksFetches)
+	qOpts.RedactionRules = v.GetString(queryRedactionRules)
+	qOpts.RedactionIdentityHeader = v.GetString(queryRedactionHeader)
+	qOpts.RedactionTrustGRPCIdentity = v.GetBool(queryRedactionGRPC)
+	qOpts.RedactionHashKeyFile = v.GetString(queryRedactionHashKey)
+	qOpts.Adjusters = parseAdjusterNames(v.GetString(queryAdjusters))
+	qOpts.TraceLimits.MaxSpans = v.GetInt(queryTraceMaxSpans)
+	qOpts.TraceLimits.MaxReadSpans = v.GetInt(queryTraceMaxReadSpans)
+	qOpts.TraceLimits.MinSiblings = v.GetInt(queryTraceMinSiblings)
+	if minVersions := v.GetString(queryMinClientVersions); minVersions != "" {
+		versions, err := parseMinClientVersions(minVersions)
+		if err != nil {
+			logger.Error("Failed to parse minimum client versions", zap.String("versions", minVersions), zap.Error(err))
+		} else {
+			qOpts.MinClientVersions = versions
+		}
+	}
 
 	stringSlice := v.GetStringSlice(queryAdditionalHeaders)
 	headers, err := stringSliceAsHeader(stringSlice)
@@ -84,8 +188,33 @@ func (qOpts *QueryOptions) InitFromViper(v *viper.Viper, logger *zap.Logger) *Qu
 	return qOpts
 }
 
+// parseMinClientVersions parses a comma-separated list of "language=version".
+func parseMinClientVersions(list string) (map[string]string, error) {
+	versions := make(map[string]string)
+	for _, entry := range strings.Split(list, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("malformed client version %q, expecting language=version", entry)
+		}
+		versions[parts[0]] = parts[1]
+	}
+	return versions, nil
+}
+
+// parseAdjusterNames parses a comma-separated list of adjuster names. An empty list is not nil.
+func parseAdjusterNames(list string) []string {
+	names := []string{}
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // stringSliceAsHeader parses a slice of strings and returns a http.Header.
-//  Each string in the slice is expected to be in the format "key: value"
+//
+//	Each string in the slice is expected to be in the format "key: value"
 func stringSliceAsHeader(slice []string) (http.Header, error) {
 	if len(slice) == 0 {
 		return nil, nil
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/pkg/config"
 )
 
@@ -34,6 +35,20 @@ func TestQueryBuilderFlags(t *testing.T) {
 		"--query.port=80",
 		"--query.additional-headers=access-control-allow-origin:blerg",
 		"--query.additional-headers=whatever:thing",
+		"--query.import.enabled=true",
+		"--query.import.storage-writes=true",
+		"--query.import.max-traces=100",
+		"--query.catalog.collectors=collector-1:14250,collector-2:14250",
+		"--query.span-metrics.collectors=collector-3:14250",
+		"--query.quality.min-client-versions=Go=2.22.0, Java=1.1.0",
+		"--query.trace-links.max-depth=5",
+		"--query.redaction.rules=redaction.json",
+		"--query.redaction.identity-header=X-Forwarded-User",
+		"--query.redaction.trust-grpc-identity=true",
+		"--query.redaction.hash-key-file=redaction.key",
+		"--query.adjusters=span-id-deduper, clock-skew",
+		"--query.trace-limits.max-spans=50000",
+		"--query.trace-limits.max-read-spans=200000",
 	})
 	qOpts := new(QueryOptions).InitFromViper(v, zap.NewNop())
 	assert.Equal(t, "/dev/null", qOpts.StaticAssets)
@@ -44,6 +59,40 @@ func TestQueryBuilderFlags(t *testing.T) {
 		"Access-Control-Allow-Origin": []string{"blerg"},
 		"Whatever":                    []string{"thing"},
 	}, qOpts.AdditionalHeaders)
+	assert.True(t, qOpts.ImportEnabled)
+	assert.True(t, qOpts.ImportStorageWrites)
+	assert.Equal(t, 100, qOpts.ImportMaxTraces)
+	assert.Equal(t, []string{"collector-1:14250", "collector-2:14250"}, qOpts.CatalogCollectors)
+	assert.Equal(t, []string{"collector-3:14250"}, qOpts.SpanMetricsCollectors)
+	assert.Equal(t, map[string]string{"Go": "2.22.0", "Java": "1.1.0"}, qOpts.MinClientVersions)
+	assert.Equal(t, querysvc.TraceLinkOptions{
+		MaxDepth:   5,
+		MaxSpans:   querysvc.DefaultTraceLinkOptions.MaxSpans,
+		MaxFetches: querysvc.DefaultTraceLinkOptions.MaxFetches,
+	}, qOpts.TraceLinks)
+	assert.Equal(t, "redaction.json", qOpts.RedactionRules)
+	assert.Equal(t, "X-Forwarded-User", qOpts.RedactionIdentityHeader)
+	assert.True(t, qOpts.RedactionTrustGRPCIdentity)
+	assert.Equal(t, "redaction.key", qOpts.RedactionHashKeyFile)
+	assert.Equal(t, []string{"span-id-deduper", "clock-skew"}, qOpts.Adjusters)
+	assert.Equal(t, querysvc.TraceLimitOptions{
+		MaxSpans:     50000,
+		MaxReadSpans: 200000,
+		MinSiblings:  querysvc.DefaultTraceLimitOptions.MinSiblings,
+	}, qOpts.TraceLimits)
+}
+
+func TestQueryBuilderAdjustersFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{})
+	qOpts := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	assert.Equal(t, querysvc.StandardAdjusterNames, qOpts.Adjusters)
+
+	v, command = config.Viperize(AddFlags)
+	command.ParseFlags([]string{"--query.adjusters="})
+	qOpts = new(QueryOptions).InitFromViper(v, zap.NewNop())
+	assert.NotNil(t, qOpts.Adjusters)
+	assert.Empty(t, qOpts.Adjusters)
 }
 
 func TestQueryBuilderBadHeadersFlags(t *testing.T) {
@@ -55,6 +104,15 @@ func TestQueryBuilderBadHeadersFlags(t *testing.T) {
 	assert.Nil(t, qOpts.AdditionalHeaders)
 }
 
+func TestQueryBuilderBadMinClientVersionsFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--query.quality.min-client-versions=Go=2.22.0,Java",
+	})
+	qOpts := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	assert.Nil(t, qOpts.MinClientVersions)
+}
+
 func TestStringSliceAsHeader(t *testing.T) {
 	headers := []string{
 		"Access-Control-Allow-Origin: https://mozilla.org",
@@ -16,17 +16,31 @@ package app
 
 import (
 	"context"
+	"errors"
 
 	"github.com/opentracing/opentracing-go"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/cmd/query/app/redaction"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/catalog"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
-const maxSpanCountInChunk = 10
+const (
+	maxSpanCountInChunk = 10
+
+	// nextPageTokenHeader is the response header of FindTraces carrying the continuation token
+	nextPageTokenHeader = "next-page-token"
+)
 
 // GRPCHandler implements the GRPC endpoint of the query service.
 type GRPCHandler struct {
@@ -47,8 +61,41 @@ func NewGRPCHandler(queryService *querysvc.QueryService, logger *zap.Logger, tra
 }
 
 // GetTrace is the GRPC handler to fetch traces based on trace-id.
+// A trace exceeding the span limit is reduced, with its summary in the first chunk, unless the request is full,
+// in which case the spans are sent as they are read.
 func (g *GRPCHandler) GetTrace(r *api_v2.GetTraceRequest, stream api_v2.QueryService_GetTraceServer) error {
-	trace, err := g.queryService.GetTrace(stream.Context(), r.TraceID)
+	if r.Full {
+		return g.streamTrace(r.TraceID, stream)
+	}
+	trace, summary, err := g.queryService.GetLimitedTrace(stream.Context(), r.TraceID)
+	if err == spanstore.ErrTraceNotFound {
+		g.logger.Error("trace not found", zap.Error(err))
+		return err
+	}
+	if err != nil {
+		g.logger.Error("Could not fetch spans from backend", zap.Error(err))
+		return err
+	}
+	if summary == nil {
+		return g.sendSpanChunks(trace.Spans, stream.Send)
+	}
+	first := true
+	return g.sendSpanChunks(trace.Spans, func(chunk *api_v2.SpansResponseChunk) error {
+		if first {
+			chunk.Summary = traceSummaryToProto(summary)
+			first = false
+		}
+		return stream.Send(chunk)
+	})
+}
+
+// streamTrace sends the spans of the trace as they are read from the span reader.
+func (g *GRPCHandler) streamTrace(traceID model.TraceID, stream api_v2.QueryService_GetTraceServer) error {
+	var sendErr error
+	err := g.queryService.StreamTrace(stream.Context(), traceID, func(part *model.Trace) bool {
+		sendErr = g.sendSpanChunks(part.Spans, stream.Send)
+		return sendErr == nil
+	})
 	if err == spanstore.ErrTraceNotFound {
 		g.logger.Error("trace not found", zap.Error(err))
 		return err
@@ -57,7 +104,25 @@ func (g *GRPCHandler) GetTrace(r *api_v2.GetTraceRequest, stream api_v2.QuerySer
 		g.logger.Error("Could not fetch spans from backend", zap.Error(err))
 		return err
 	}
-	return g.sendSpanChunks(trace.Spans, stream.Send)
+	return sendErr
+}
+
+func traceSummaryToProto(summary *querysvc.TraceSummary) *api_v2.TraceSummary {
+	result := &api_v2.TraceSummary{
+		TotalSpans:     int64(summary.TotalSpans),
+		ReturnedSpans:  int64(summary.ReturnedSpans),
+		CollapsedSpans: int64(summary.CollapsedSpans),
+		Truncated:      summary.Truncated,
+		Omitted:        make([]api_v2.OmittedSpans, len(summary.Omitted)),
+	}
+	for i, omitted := range summary.Omitted {
+		result.Omitted[i] = api_v2.OmittedSpans{
+			ServiceName:   omitted.ServiceName,
+			OperationName: omitted.OperationName,
+			Spans:         int64(omitted.Spans),
+		}
+	}
+	return result
 }
 
 // ArchiveTrace is the GRPC handler to archive traces.
@@ -79,20 +144,31 @@ func (g *GRPCHandler) ArchiveTrace(ctx context.Context, r *api_v2.ArchiveTraceRe
 func (g *GRPCHandler) FindTraces(r *api_v2.FindTracesRequest, stream api_v2.QueryService_FindTracesServer) error {
 	query := r.GetQuery()
 	queryParams := spanstore.TraceQueryParameters{
-		ServiceName:   query.ServiceName,
-		OperationName: query.OperationName,
-		Tags:          query.Tags,
-		StartTimeMin:  query.StartTimeMin,
-		StartTimeMax:  query.StartTimeMax,
-		DurationMin:   query.DurationMin,
-		DurationMax:   query.DurationMax,
-		NumTraces:     int(query.SearchDepth),
-	}
-	traces, err := g.queryService.FindTraces(stream.Context(), &queryParams)
+		ServiceName:    query.ServiceName,
+		OperationName:  query.OperationName,
+		Tags:           query.Tags,
+		StartTimeMin:   query.StartTimeMin,
+		StartTimeMax:   query.StartTimeMax,
+		DurationMin:    query.DurationMin,
+		DurationMax:    query.DurationMax,
+		NumTraces:      int(query.SearchDepth),
+		PageToken:      query.PageToken,
+		ServiceNames:   query.ServiceNames,
+		OperationNames: query.OperationNames,
+	}
+	traces, nextPageToken, err := g.queryService.FindTracesPage(stream.Context(), &queryParams)
+	if errors.Is(err, redaction.ErrRedactedSearch) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		g.logger.Error("Error fetching traces", zap.Error(err))
 		return err
 	}
+	if nextPageToken != "" {
+		if err := stream.SetHeader(metadata.Pairs(nextPageTokenHeader, nextPageToken)); err != nil {
+			return err
+		}
+	}
 	for _, trace := range traces {
 		if err := g.sendSpanChunks(trace.Spans, stream.Send); err != nil {
 			return err
@@ -101,6 +177,28 @@ func (g *GRPCHandler) FindTraces(r *api_v2.FindTracesRequest, stream api_v2.Quer
 	return nil
 }
 
+// TailSpans is the GRPC handler to stream the live spans matching the request.
+func (g *GRPCHandler) TailSpans(r *api_v2.TailSpansRequest, stream api_v2.QueryService_TailSpansServer) error {
+	sub, err := g.queryService.TailSpans(livetail.FilterFromRequest(r))
+	if err != nil {
+		g.logger.Error("Error subscribing to live spans", zap.Error(err))
+		return err
+	}
+	defer sub.Close()
+	return livetail.Forward(stream.Context(), sub, func(spans []model.Span) error {
+		trace := &model.Trace{Spans: make([]*model.Span, len(spans))}
+		for i := range spans {
+			trace.Spans[i] = &spans[i]
+		}
+		trace = g.queryService.Redact(stream.Context(), trace)
+		chunk := make([]model.Span, len(trace.Spans))
+		for i, span := range trace.Spans {
+			chunk[i] = *span
+		}
+		return stream.Send(&api_v2.SpansResponseChunk{Spans: chunk})
+	})
+}
+
 func (g *GRPCHandler) sendSpanChunks(spans []*model.Span, sendFn func(*api_v2.SpansResponseChunk) error) error {
 	chunk := make([]model.Span, 0, len(spans))
 	for i := 0; i < len(spans); i += maxSpanCountInChunk {
@@ -127,6 +225,34 @@ func (g *GRPCHandler) GetServices(ctx context.Context, r *api_v2.GetServicesRequ
 	return &api_v2.GetServicesResponse{Services: services}, nil
 }
 
+// GetServiceCatalog is the GRPC handler to fetch the recent activity of each service.
+func (g *GRPCHandler) GetServiceCatalog(ctx context.Context, r *api_v2.GetServiceCatalogRequest) (*api_v2.GetServiceCatalogResponse, error) {
+	services, err := g.queryService.GetServiceCatalog(ctx)
+	if err == querysvc.ErrNoCatalog {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil {
+		g.logger.Error("Error fetching service catalog", zap.Error(err))
+		return nil, err
+	}
+
+	return &api_v2.GetServiceCatalogResponse{Services: catalog.FromDomain(services)}, nil
+}
+
+// GetSpanMetrics is the GRPC handler to fetch the RED metrics of a service.
+func (g *GRPCHandler) GetSpanMetrics(ctx context.Context, r *api_v2.GetSpanMetricsRequest) (*api_v2.GetSpanMetricsResponse, error) {
+	series, err := g.queryService.GetSpanMetrics(ctx, spanmetrics.Query{ServiceName: r.ServiceName, Lookback: r.Lookback})
+	if err == querysvc.ErrNoSpanMetrics {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil {
+		g.logger.Error("Error fetching span metrics", zap.Error(err))
+		return nil, err
+	}
+
+	return &api_v2.GetSpanMetricsResponse{Metrics: spanmetrics.FromDomain(series)}, nil
+}
+
 // GetOperations is the GRPC handler to fetch operations.
 func (g *GRPCHandler) GetOperations(
 	ctx context.Context,
@@ -159,7 +285,14 @@ func (g *GRPCHandler) GetOperations(
 func (g *GRPCHandler) GetDependencies(ctx context.Context, r *api_v2.GetDependenciesRequest) (*api_v2.GetDependenciesResponse, error) {
 	startTime := r.StartTime
 	endTime := r.EndTime
-	dependencies, err := g.queryService.GetDependencies(startTime, endTime.Sub(startTime))
+	getDependencies := g.queryService.GetDependencies
+	if r.Detailed {
+		getDependencies = g.queryService.GetDetailedDependencies
+	}
+	dependencies, err := getDependencies(startTime, endTime.Sub(startTime))
+	if err == dependencystore.ErrDetailedDependenciesNotSupported {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
 	if err != nil {
 		g.logger.Error("Error fetching dependencies", zap.Error(err))
 		return nil, err
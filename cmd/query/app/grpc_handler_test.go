@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"testing"
 	"time"
@@ -27,12 +28,19 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/cmd/query/app/redaction"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -218,6 +226,125 @@ func TestGetTraceSuccessGRPC(t *testing.T) {
 	})
 }
 
+func TestGetTraceLimitedGRPC(t *testing.T) {
+	spanReader := &spanstoremocks.Reader{}
+	q := querysvc.NewQueryService(spanReader, &depsmocks.Reader{}, querysvc.QueryServiceOptions{
+		TraceLimits: querysvc.TraceLimitOptions{MaxSpans: 15},
+	})
+	server, addr := newGRPCServer(t, q, zap.NewNop(), opentracing.NoopTracer{})
+	defer server.Stop()
+	client := newGRPCClient(t, addr.String())
+	defer client.conn.Close()
+
+	spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(func(context.Context, model.TraceID) *model.Trace {
+			trace := &model.Trace{}
+			for i := uint64(1); i <= 25; i++ {
+				trace.Spans = append(trace.Spans, &model.Span{
+					TraceID:       mockTraceID,
+					SpanID:        model.NewSpanID(i),
+					OperationName: "op",
+					Process:       model.NewProcess("svc", nil),
+				})
+			}
+			return trace
+		}, nil)
+
+	readChunks := func(full bool) []*api_v2.SpansResponseChunk {
+		res, err := client.GetTrace(context.Background(), &api_v2.GetTraceRequest{TraceID: mockTraceID, Full: full})
+		require.NoError(t, err)
+		var chunks []*api_v2.SpansResponseChunk
+		for {
+			chunk, err := res.Recv()
+			if err == io.EOF {
+				return chunks
+			}
+			require.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	chunks := readChunks(false)
+	require.Len(t, chunks, 2)
+	assert.Len(t, chunks[0].Spans, 10)
+	assert.Len(t, chunks[1].Spans, 5)
+	assert.Equal(t, &api_v2.TraceSummary{
+		TotalSpans:    25,
+		ReturnedSpans: 15,
+		Omitted:       []api_v2.OmittedSpans{{ServiceName: "svc", OperationName: "op", Spans: 10}},
+	}, chunks[0].Summary)
+	assert.Nil(t, chunks[1].Summary)
+
+	chunks = readChunks(true)
+	require.Len(t, chunks, 3)
+	assert.Len(t, chunks[2].Spans, 5)
+	assert.Nil(t, chunks[0].Summary)
+}
+
+// streamingReader streams a trace of 12 spans by parts of 4 spans.
+type streamingReader struct {
+	spanstoremocks.Reader
+}
+
+func (r *streamingReader) StreamTrace(ctx context.Context, traceID model.TraceID, fn func(part *model.Trace) bool) error {
+	for i := uint64(0); i < 3; i++ {
+		part := &model.Trace{}
+		for j := uint64(1); j <= 4; j++ {
+			part.Spans = append(part.Spans, &model.Span{
+				TraceID:       traceID,
+				SpanID:        model.NewSpanID(4*i + j),
+				OperationName: "op",
+				Process:       model.NewProcess("svc", nil),
+			})
+		}
+		if !fn(part) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestGetTraceStreamedGRPC(t *testing.T) {
+	q := querysvc.NewQueryService(&streamingReader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{
+		TraceLimits: querysvc.TraceLimitOptions{MaxSpans: 2, MaxReadSpans: 6},
+	})
+	server, addr := newGRPCServer(t, q, zap.NewNop(), opentracing.NoopTracer{})
+	defer server.Stop()
+	client := newGRPCClient(t, addr.String())
+	defer client.conn.Close()
+
+	readChunks := func(full bool) []*api_v2.SpansResponseChunk {
+		res, err := client.GetTrace(context.Background(), &api_v2.GetTraceRequest{TraceID: mockTraceID, Full: full})
+		require.NoError(t, err)
+		var chunks []*api_v2.SpansResponseChunk
+		for {
+			chunk, err := res.Recv()
+			if err == io.EOF {
+				return chunks
+			}
+			require.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	// the spans are sent as they are read
+	chunks := readChunks(true)
+	require.Len(t, chunks, 3)
+	for _, chunk := range chunks {
+		assert.Len(t, chunk.Spans, 4)
+	}
+
+	chunks = readChunks(false)
+	require.Len(t, chunks, 1)
+	assert.Len(t, chunks[0].Spans, 2)
+	assert.Equal(t, &api_v2.TraceSummary{
+		TotalSpans:    6,
+		ReturnedSpans: 2,
+		Truncated:     true,
+		Omitted:       []api_v2.OmittedSpans{{ServiceName: "svc", OperationName: "op", Spans: 4}},
+	}, chunks[0].Summary)
+}
+
 func TestGetTraceDBFailureGRPC(t *testing.T) {
 	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
 
@@ -365,6 +492,28 @@ func TestSearchSuccess_SpanStreamingGRPC(t *testing.T) {
 	})
 }
 
+func TestSearchNextPageTokenGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		server.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+			Return([]*model.Trace{mockTraceGRPC}, nil).Once()
+
+		queryParams := &api_v2.TraceQueryParameters{
+			ServiceName:  "service",
+			StartTimeMin: time.Now().Add(time.Duration(-10) * time.Minute),
+			StartTimeMax: time.Now(),
+			SearchDepth:  1,
+		}
+		res, err := client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+			Query: queryParams,
+		})
+		require.NoError(t, err)
+
+		header, err := res.Header()
+		require.NoError(t, err)
+		assert.Len(t, header.Get(nextPageTokenHeader), 1)
+	})
+}
+
 func TestSearchFailure_GRPC(t *testing.T) {
 	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
 		mockErrorGRPC := fmt.Errorf("whatsamattayou")
@@ -486,6 +635,199 @@ func TestGetDependenciesFailureGRPC(t *testing.T) {
 	})
 }
 
+func TestGetDetailedDependenciesSuccessGRPC(t *testing.T) {
+	q := querysvc.NewQueryService(&spanstoremocks.Reader{}, detailedDependenciesStore(t), querysvc.QueryServiceOptions{})
+	server, addr := newGRPCServer(t, q, zap.NewNop(), opentracing.NoopTracer{})
+	defer server.Stop()
+	client := newGRPCClient(t, addr.String())
+	defer client.conn.Close()
+
+	endTs := time.Unix(0, 1476374248550*millisToNanosMultiplier)
+	res, err := client.GetDependencies(context.Background(), &api_v2.GetDependenciesRequest{
+		StartTime: endTs,
+		EndTime:   endTs.Add(defaultDependencyLookbackDuration),
+		Detailed:  true,
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Dependencies, 2)
+	assert.Equal(t, "killer", res.Dependencies[0].Parent)
+	assert.Equal(t, "bohemian", res.Dependencies[0].Child)
+	assert.Equal(t, model.RPCDependency, res.Dependencies[0].Kind)
+	assert.Equal(t, "queen", res.Dependencies[1].Child)
+	assert.Equal(t, model.MessagingDependency, res.Dependencies[1].Kind)
+	assert.Equal(t, 3*time.Millisecond, res.Dependencies[1].LatencyP99)
+}
+
+func TestGetDetailedDependenciesNotSupportedGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		endTs := time.Now().UTC()
+		_, err := client.GetDependencies(context.Background(), &api_v2.GetDependenciesRequest{
+			StartTime: endTs.Add(-defaultDependencyLookbackDuration),
+			EndTime:   endTs,
+			Detailed:  true,
+		})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
+func TestTailSpansSuccessGRPC(t *testing.T) {
+	bus := livetail.NewBus(10)
+	q := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{LiveTail: bus})
+	server, addr := newGRPCServer(t, q, zap.NewNop(), opentracing.NoopTracer{})
+	defer server.Stop()
+	client := newGRPCClient(t, addr.String())
+	defer client.conn.Close()
+
+	res, err := client.TailSpans(context.Background(), &api_v2.TailSpansRequest{ServiceName: "frontend"})
+	require.NoError(t, err)
+	for i := 0; i < 100 && bus.Subscribers() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, 1, bus.Subscribers())
+
+	bus.Publish(&model.Span{TraceID: mockTraceID, Process: model.NewProcess("frontend", nil)})
+	spanResChunk, err := res.Recv()
+	require.NoError(t, err)
+	require.Len(t, spanResChunk.Spans, 1)
+	assert.Equal(t, mockTraceID, spanResChunk.Spans[0].TraceID)
+}
+
+func TestTailSpansRedactedGRPC(t *testing.T) {
+	redactor, err := redaction.NewRedactor([]redaction.Rule{
+		{Keys: []string{"user.email"}, Exempt: []string{"admin"}},
+	}, redaction.Options{IdentityHeader: "X-Forwarded-User", TrustGRPCIdentity: true})
+	require.NoError(t, err)
+	bus := livetail.NewBus(10)
+	q := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{LiveTail: bus, Redactor: redactor})
+	server, addr := newGRPCServer(t, q, zap.NewNop(), opentracing.NoopTracer{})
+	defer server.Stop()
+	client := newGRPCClient(t, addr.String())
+	defer client.conn.Close()
+
+	for identity, expected := range map[string]string{"": redaction.Masked, "admin": "alice@example.com"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		ctx = metadata.AppendToOutgoingContext(ctx, "X-Forwarded-User", identity)
+		res, err := client.TailSpans(ctx, &api_v2.TailSpansRequest{ServiceName: "frontend"})
+		require.NoError(t, err)
+		for i := 0; i < 100 && bus.Subscribers() == 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		require.Equal(t, 1, bus.Subscribers())
+
+		bus.Publish(&model.Span{
+			TraceID: mockTraceID,
+			Tags:    []model.KeyValue{model.String("user.email", "alice@example.com")},
+			Process: model.NewProcess("frontend", nil),
+		})
+		spanResChunk, err := res.Recv()
+		require.NoError(t, err)
+		require.Len(t, spanResChunk.Spans, 1)
+		assert.Equal(t, model.String("user.email", expected), spanResChunk.Spans[0].Tags[0], identity)
+		cancel()
+		for i := 0; i < 100 && bus.Subscribers() > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestSearchRedactedGRPC(t *testing.T) {
+	redactor, err := redaction.NewRedactor([]redaction.Rule{{Keys: []string{"user.email"}}}, redaction.Options{})
+	require.NoError(t, err)
+	q := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{Redactor: redactor})
+	server, addr := newGRPCServer(t, q, zap.NewNop(), opentracing.NoopTracer{})
+	defer server.Stop()
+	client := newGRPCClient(t, addr.String())
+	defer client.conn.Close()
+
+	res, err := client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+		Query: &api_v2.TraceQueryParameters{
+			ServiceName: "frontend",
+			Tags:        map[string]string{"user.email": "alice@example.com"},
+		},
+	})
+	require.NoError(t, err)
+	_, err = res.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTailSpansNotConfiguredGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		res, err := client.TailSpans(context.Background(), &api_v2.TailSpansRequest{ServiceName: "frontend"})
+		require.NoError(t, err)
+
+		_, err = res.Recv()
+		assert.EqualError(t, err, status.Error(2, querysvc.ErrNoLiveTail.Error()).Error())
+	})
+}
+
+func TestGetServiceCatalogSuccessGRPC(t *testing.T) {
+	store := memory.NewStore()
+	require.NoError(t, store.WriteSpan(&model.Span{OperationName: "GET /", Process: model.NewProcess("frontend", nil)}))
+	q := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{Catalog: store})
+	server, addr := newGRPCServer(t, q, zap.NewNop(), opentracing.NoopTracer{})
+	defer server.Stop()
+	client := newGRPCClient(t, addr.String())
+	defer client.conn.Close()
+
+	res, err := client.GetServiceCatalog(context.Background(), &api_v2.GetServiceCatalogRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Services, 1)
+	assert.Equal(t, "frontend", res.Services[0].ServiceName)
+	assert.EqualValues(t, 1, res.Services[0].OperationCount)
+}
+
+func TestGetServiceCatalogFailureGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		_, err := client.GetServiceCatalog(context.Background(), &api_v2.GetServiceCatalogRequest{})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	reader := &spanstoremocks.CatalogReader{}
+	reader.On("GetServiceCatalog", mock.Anything).Return(nil, errStorageGRPC)
+	q := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{Catalog: reader})
+	server, addr := newGRPCServer(t, q, zap.NewNop(), opentracing.NoopTracer{})
+	defer server.Stop()
+	client := newGRPCClient(t, addr.String())
+	defer client.conn.Close()
+
+	_, err := client.GetServiceCatalog(context.Background(), &api_v2.GetServiceCatalogRequest{})
+	assert.EqualError(t, err, "rpc error: code = Unknown desc = "+errStorageGRPC.Error())
+}
+
+func TestGetSpanMetricsSuccessGRPC(t *testing.T) {
+	aggregator := spanmetrics.NewAggregator(metrics.NullFactory, spanmetrics.Options{})
+	aggregator.ProcessSpan(&model.Span{OperationName: "GET /", Process: model.NewProcess("frontend", nil)})
+	q := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{SpanMetrics: aggregator})
+	server, addr := newGRPCServer(t, q, zap.NewNop(), opentracing.NoopTracer{})
+	defer server.Stop()
+	client := newGRPCClient(t, addr.String())
+	defer client.conn.Close()
+
+	res, err := client.GetSpanMetrics(context.Background(), &api_v2.GetSpanMetricsRequest{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, res.Metrics, 1)
+	assert.Equal(t, "GET /", res.Metrics[0].OperationName)
+	assert.EqualValues(t, 1, res.Metrics[0].Calls)
+}
+
+func TestGetSpanMetricsFailureGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		_, err := client.GetSpanMetrics(context.Background(), &api_v2.GetSpanMetricsRequest{ServiceName: "frontend"})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	q := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{
+		SpanMetrics: failingSpanMetricsReader{err: errStorageGRPC},
+	})
+	server, addr := newGRPCServer(t, q, zap.NewNop(), opentracing.NoopTracer{})
+	defer server.Stop()
+	client := newGRPCClient(t, addr.String())
+	defer client.conn.Close()
+
+	_, err := client.GetSpanMetrics(context.Background(), &api_v2.GetSpanMetricsRequest{ServiceName: "frontend"})
+	assert.EqualError(t, err, "rpc error: code = Unknown desc = "+errStorageGRPC.Error())
+}
+
 func TestSendSpanChunksError(t *testing.T) {
 	g := &GRPCHandler{
 		logger: zap.NewNop(),
@@ -16,14 +16,19 @@
 package app
 
 import (
+	"net/http/httptest"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model"
 	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
 
 func TestDeduplicateDependencies(t *testing.T) {
@@ -353,3 +358,59 @@ func TestGetDependenciesLookbackParsingFailure(t *testing.T) {
 	err := getJSON(server.URL+"/api/dependencies?endTs=1476374248550&service=testing&lookback=shazbot", &response)
 	assert.Error(t, err)
 }
+
+func detailedDependenciesStore(t *testing.T) *memory.Store {
+	store := memory.NewStore()
+	traceID := model.NewTraceID(0, 1)
+	start := time.Unix(0, 1476374248550*millisToNanosMultiplier).Add(-time.Hour)
+	spans := []*model.Span{
+		{TraceID: traceID, SpanID: 1, OperationName: "send", StartTime: start, Duration: 2 * time.Millisecond,
+			Process: model.NewProcess("killer", nil), Tags: []model.KeyValue{model.String("span.kind", "producer")}},
+		{TraceID: traceID, SpanID: 2, OperationName: "receive", StartTime: start, Duration: 3 * time.Millisecond,
+			Process: model.NewProcess("queen", nil), References: []model.SpanRef{model.NewChildOfRef(traceID, 1)},
+			Tags: []model.KeyValue{model.Bool("error", true)}},
+		{TraceID: traceID, SpanID: 3, OperationName: "GET", StartTime: start, Duration: time.Millisecond,
+			Process: model.NewProcess("killer", nil), References: []model.SpanRef{model.NewChildOfRef(traceID, 1)}},
+		{TraceID: traceID, SpanID: 4, OperationName: "/", StartTime: start, Duration: time.Millisecond,
+			Process: model.NewProcess("bohemian", nil), References: []model.SpanRef{model.NewChildOfRef(traceID, 3)}},
+	}
+	for _, span := range spans {
+		require.NoError(t, store.WriteSpan(span))
+	}
+	return store
+}
+
+func TestGetDetailedDependenciesSuccess(t *testing.T) {
+	qs := querysvc.NewQueryService(&spanstoremocks.Reader{}, detailedDependenciesStore(t), querysvc.QueryServiceOptions{})
+	r := NewRouter()
+	NewAPIHandler(qs).RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	var response struct {
+		Data []ui.DependencyLink `json:"data"`
+	}
+	err := getJSON(server.URL+"/api/dependencies?endTs=1476374248550&service=queen&detailed=true", &response)
+	require.NoError(t, err)
+	assert.Equal(t, []ui.DependencyLink{{
+		Parent:          "killer",
+		Child:           "queen",
+		CallCount:       1,
+		ParentOperation: "send",
+		ChildOperation:  "receive",
+		Kind:            "messaging",
+		ErrorCount:      1,
+		LatencyP50:      3000,
+		LatencyP95:      3000,
+		LatencyP99:      3000,
+	}}, response.Data)
+}
+
+func TestGetDetailedDependenciesNotSupported(t *testing.T) {
+	server, _, _ := initializeTestServer()
+	defer server.Close()
+
+	var response structuredResponse
+	err := getJSON(server.URL+"/api/dependencies?endTs=1476374248550&detailed=true", &response)
+	assert.EqualError(t, err, "501 error from server: {\"data\":null,\"total\":0,\"limit\":0,\"offset\":0,\"errors\":[{\"code\":501,\"msg\":\"detailed dependencies not supported\"}]}\n")
+}
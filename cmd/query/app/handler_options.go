@@ -20,6 +20,9 @@ import (
 
 	"github.com/opentracing/opentracing-go"
 	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/analysis"
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 )
 
 // HandlerOption is a function that sets some option on the APIHandler
@@ -65,3 +68,21 @@ func (handlerOptions) Tracer(tracer opentracing.Tracer) HandlerOption {
 		apiHandler.tracer = tracer
 	}
 }
+
+// MinClientVersions creates a HandlerOption that initializes the minimum versions of the client libraries
+// by language below which the quality report flags them as outdated
+func (handlerOptions) MinClientVersions(minClientVersions map[string]string) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.qualityOptions = analysis.QualityOptions{
+			MinClientVersions: minClientVersions,
+			MaxExamples:       analysis.DefaultQualityOptions.MaxExamples,
+		}
+	}
+}
+
+// TraceLinks creates a HandlerOption that initializes the limits of the traces linked to a requested trace
+func (handlerOptions) TraceLinks(traceLinks querysvc.TraceLinkOptions) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.traceLinks = traceLinks
+	}
+}
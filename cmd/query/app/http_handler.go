@@ -16,12 +16,17 @@
 package app
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -29,22 +34,53 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/analysis"
+	"github.com/jaegertracing/jaeger/cmd/query/app/export"
+	"github.com/jaegertracing/jaeger/cmd/query/app/importer"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/cmd/query/app/redaction"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/adjuster"
 	uiconv "github.com/jaegertracing/jaeger/model/converter/json"
 	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/pkg/multierror"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 const (
 	traceIDParam  = "traceID"
+	formatParam   = "format"
+	targetParam   = "target"
+	diffAParam    = "a"
+	diffBParam    = "b"
+	groupByParam  = "groupBy"
+	summaryParam  = "summary"
+	clusterParam  = "cluster"
+	detailedParam = "detailed"
 	endTsParam    = "endTs"
 	lookbackParam = "lookback"
 
+	linkDepthParam = "linkDepth"
+	adjustersParam = "adjusters"
+
+	beforeStartParam = "beforeStart"
+	beforeEndParam   = "beforeEnd"
+	afterStartParam  = "afterStart"
+	afterEndParam    = "afterEnd"
+
 	defaultDependencyLookbackDuration = time.Hour * 24
 	defaultTraceQueryLookbackDuration = time.Hour * 24 * 2
 	defaultAPIPrefix                  = "api"
+	defaultRegressionTraces           = 200
+	maxRegressionTraces               = 1000
+	maxImportBytes                    = 64 << 20
+	maxTraceIDs                       = 20
+
+	flameGraphFormatJSON      = "json"
+	flameGraphFormatCollapsed = "collapsed"
 )
 
 // HTTPHandler handles http requests
@@ -53,11 +89,36 @@ type HTTPHandler interface {
 }
 
 type structuredResponse struct {
-	Data   interface{}       `json:"data"`
-	Total  int               `json:"total"`
-	Limit  int               `json:"limit"`
-	Offset int               `json:"offset"`
-	Errors []structuredError `json:"errors"`
+	Data          interface{}       `json:"data"`
+	Total         int               `json:"total"`
+	Limit         int               `json:"limit"`
+	Offset        int               `json:"offset"`
+	NextPageToken string            `json:"nextPageToken,omitempty"`
+	TraceLinks    *traceLinks       `json:"traceLinks,omitempty"`
+	TraceSummary  *traceSummary     `json:"traceSummary,omitempty"`
+	Errors        []structuredError `json:"errors"`
+}
+
+// criticalPathResponse is the critical path of a trace, with times in microseconds like the UI model.
+type criticalPathResponse struct {
+	TraceID    ui.TraceID             `json:"traceID"`
+	Segments   []criticalPathSegment  `json:"segments"`
+	Services   []criticalPathDuration `json:"services"`
+	Operations []criticalPathDuration `json:"operations"`
+}
+
+type criticalPathSegment struct {
+	SpanID        ui.SpanID `json:"spanID"`
+	ServiceName   string    `json:"serviceName"`
+	OperationName string    `json:"operationName"`
+	StartTime     uint64    `json:"startTime"`
+	Duration      uint64    `json:"duration"`
+}
+
+type criticalPathDuration struct {
+	ServiceName   string `json:"serviceName"`
+	OperationName string `json:"operationName,omitempty"`
+	Duration      uint64 `json:"duration"`
 }
 
 type structuredError struct {
@@ -73,12 +134,14 @@ func NewRouter() *mux.Router {
 
 // APIHandler implements the query service public API by registering routes at httpPrefix
 type APIHandler struct {
-	queryService *querysvc.QueryService
-	queryParser  queryParser
-	basePath     string
-	apiPrefix    string
-	logger       *zap.Logger
-	tracer       opentracing.Tracer
+	queryService   *querysvc.QueryService
+	queryParser    queryParser
+	basePath       string
+	apiPrefix      string
+	logger         *zap.Logger
+	tracer         opentracing.Tracer
+	qualityOptions analysis.QualityOptions
+	traceLinks     querysvc.TraceLinkOptions
 }
 
 // NewAPIHandler returns an APIHandler
@@ -89,6 +152,8 @@ func NewAPIHandler(queryService *querysvc.QueryService, options ...HandlerOption
 			traceQueryLookbackDuration: defaultTraceQueryLookbackDuration,
 			timeNow:                    time.Now,
 		},
+		qualityOptions: analysis.DefaultQualityOptions,
+		traceLinks:     querysvc.DefaultTraceLinkOptions,
 	}
 
 	for _, option := range options {
@@ -109,9 +174,20 @@ func NewAPIHandler(queryService *querysvc.QueryService, options ...HandlerOption
 // RegisterRoutes registers routes for this handler on the given router
 func (aH *APIHandler) RegisterRoutes(router *mux.Router) {
 	aH.handleFunc(router, aH.getTrace, "/traces/{%s}", traceIDParam).Methods(http.MethodGet)
+	aH.handleFunc(router, aH.exportTrace, "/traces/{%s}/export", traceIDParam).Methods(http.MethodGet)
+	aH.handleFunc(router, aH.criticalPath, "/traces/{%s}/critical-path", traceIDParam).Methods(http.MethodGet)
+	aH.handleFunc(router, aH.traceStats, "/traces/{%s}/stats", traceIDParam).Methods(http.MethodGet)
 	aH.handleFunc(router, aH.archiveTrace, "/archive/{%s}", traceIDParam).Methods(http.MethodPost)
+	aH.handleFunc(router, aH.importTraces, "/import").Methods(http.MethodPost)
 	aH.handleFunc(router, aH.search, "/traces").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.diff, "/diff").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.flameGraph, "/flamegraph").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.regressions, "/regressions").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.quality, "/quality").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.tailSpans, "/tail").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.getServices, "/services").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.getServiceCatalog, "/services/catalog").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.getSpanMetrics, "/services/{%s}/metrics", serviceParam).Methods(http.MethodGet)
 	// TODO change the UI to use this endpoint. Requires ?service= parameter.
 	aH.handleFunc(router, aH.getOperations, "/operations").Methods(http.MethodGet)
 	// TODO - remove this when UI catches up
@@ -152,6 +228,51 @@ func (aH *APIHandler) getServices(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+func (aH *APIHandler) getServiceCatalog(w http.ResponseWriter, r *http.Request) {
+	catalog, err := aH.queryService.GetServiceCatalog(r.Context())
+	if err == querysvc.ErrNoCatalog {
+		aH.handleError(w, err, http.StatusNotImplemented)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	structuredRes := structuredResponse{
+		Data:  newServiceCatalogResponse(catalog),
+		Total: len(catalog),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func (aH *APIHandler) getSpanMetrics(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	// given how getSpanMetrics is bound to URL route, serviceParam cannot be empty
+	service, _ := url.QueryUnescape(vars[serviceParam])
+	var lookback time.Duration
+	if formValue := r.FormValue(lookbackParam); len(formValue) > 0 {
+		var err error
+		lookback, err = time.ParseDuration(formValue + "ms")
+		if err != nil {
+			err = fmt.Errorf("unable to parse %s: %w", lookbackParam, err)
+			aH.handleError(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+	series, err := aH.queryService.GetSpanMetrics(r.Context(), spanmetrics.Query{ServiceName: service, Lookback: lookback})
+	if err == querysvc.ErrNoSpanMetrics {
+		aH.handleError(w, err, http.StatusNotImplemented)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	structuredRes := structuredResponse{
+		Data:  newSpanMetricsResponse(series, spanmetrics.EffectiveLookback(lookback)),
+		Total: len(series),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
 func (aH *APIHandler) getOperationsLegacy(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	// given how getOperationsLegacy is bound to URL route, serviceParam cannot be empty
@@ -205,40 +326,116 @@ func (aH *APIHandler) getOperations(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+// search implements the REST API /traces, see queryParser.parse for the query syntax.
+// With summary=true it responds with the statistics of the traces instead of their spans,
+// grouped by the tag given by the groupBy parameter if any.
 func (aH *APIHandler) search(w http.ResponseWriter, r *http.Request) {
 	tQuery, err := aH.queryParser.parse(r)
 	if aH.handleError(w, err, http.StatusBadRequest) {
 		return
 	}
+	adj, err := aH.parseAdjuster(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
 
-	var uiErrors []structuredError
-	var tracesFromStorage []*model.Trace
-	if len(tQuery.traceIDs) > 0 {
-		tracesFromStorage, uiErrors, err = aH.tracesByIDs(r.Context(), tQuery.traceIDs)
-		if aH.handleError(w, err, http.StatusInternalServerError) {
-			return
+	tracesFromStorage, nextPageToken, uiErrors, err := aH.findTraces(r.Context(), tQuery)
+	if aH.handleError(w, err, findTracesStatus(err)) {
+		return
+	}
+
+	var data interface{}
+	if cluster, _ := strconv.ParseBool(r.FormValue(clusterParam)); cluster {
+		adjusted := make([]*model.Trace, len(tracesFromStorage))
+		for i, v := range tracesFromStorage {
+			trace, uiErr := aH.adjust(v, adj)
+			if uiErr != nil {
+				uiErrors = append(uiErrors, *uiErr)
+			}
+			adjusted[i] = trace
+		}
+		data = newTraceClustersResponse(analysis.ClusterTraces(adjusted))
+	} else if summary, _ := strconv.ParseBool(r.FormValue(summaryParam)); summary {
+		summaries := make([]*traceStatsResponse, len(tracesFromStorage))
+		for i, v := range tracesFromStorage {
+			stats, uiErr := aH.traceStatsOf(v, adj, r.FormValue(groupByParam))
+			if uiErr != nil {
+				uiErrors = append(uiErrors, *uiErr)
+			}
+			summaries[i] = stats
 		}
+		data = summaries
 	} else {
-		tracesFromStorage, err = aH.queryService.FindTraces(r.Context(), &tQuery.TraceQueryParameters)
-		if aH.handleError(w, err, http.StatusInternalServerError) {
-			return
+		uiTraces := make([]*ui.Trace, len(tracesFromStorage))
+		for i, v := range tracesFromStorage {
+			uiTrace, uiErr := aH.convertModelToUI(v, adj)
+			if uiErr != nil {
+				uiErrors = append(uiErrors, *uiErr)
+			}
+			uiTraces[i] = uiTrace
 		}
+		data = uiTraces
 	}
 
-	uiTraces := make([]*ui.Trace, len(tracesFromStorage))
-	for i, v := range tracesFromStorage {
-		uiTrace, uiErr := aH.convertModelToUI(v, true)
-		if uiErr != nil {
-			uiErrors = append(uiErrors, *uiErr)
+	structuredRes := structuredResponse{
+		Data:          data,
+		NextPageToken: nextPageToken,
+		Errors:        uiErrors,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// traceStatsOf adjusts the trace with the adjuster and returns its statistics.
+func (aH *APIHandler) traceStatsOf(trace *model.Trace, adj adjuster.Adjuster, groupByTag string) (*traceStatsResponse, *structuredError) {
+	var traceID model.TraceID
+	if len(trace.Spans) > 0 {
+		traceID = trace.Spans[0].TraceID
+	}
+	trace, uiError := aH.adjust(trace, adj)
+	return newTraceStatsResponse(traceID, aH.queryService.TraceStats(trace, groupByTag)), uiError
+}
+
+// adjust adjusts the trace with the adjuster, returning the error as a structuredError of the trace.
+func (aH *APIHandler) adjust(trace *model.Trace, adj adjuster.Adjuster) (*model.Trace, *structuredError) {
+	adjusted, err := adj.Adjust(trace)
+	if err != nil {
+		var traceID model.TraceID
+		if len(trace.Spans) > 0 {
+			traceID = trace.Spans[0].TraceID
 		}
-		uiTraces[i] = uiTrace
+		return adjusted, &structuredError{Msg: err.Error(), TraceID: ui.TraceID(traceID.String())}
 	}
+	return adjusted, nil
+}
 
-	structuredRes := structuredResponse{
-		Data:   uiTraces,
-		Errors: uiErrors,
+// findTraces returns the traces with the IDs of the query if any, or else the page of traces that match the query.
+func (aH *APIHandler) findTraces(ctx context.Context, tQuery *traceQueryParameters) ([]*model.Trace, string, []structuredError, error) {
+	if len(tQuery.traceIDs) > 0 {
+		traces, uiErrors, err := aH.tracesByIDs(ctx, tQuery.traceIDs)
+		return traces, "", uiErrors, err
 	}
-	aH.writeJSON(w, r, &structuredRes)
+	traces, nextPageToken, err := aH.queryService.FindTracesPage(ctx, &tQuery.TraceQueryParameters)
+	return traces, nextPageToken, nil, err
+}
+
+// boundedTraceStatus is the HTTP status of an error of QueryService.GetBoundedTrace.
+func boundedTraceStatus(err error) int {
+	switch err {
+	case spanstore.ErrTraceNotFound:
+		return http.StatusNotFound
+	case querysvc.ErrTraceTooLarge:
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// findTracesStatus is the HTTP status of an error of findTraces.
+func findTracesStatus(err error) int {
+	if errors.Is(err, redaction.ErrRedactedSearch) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
 }
 
 func (aH *APIHandler) tracesByIDs(ctx context.Context, traceIDs []model.TraceID) ([]*model.Trace, []structuredError, error) {
@@ -285,6 +482,22 @@ func (aH *APIHandler) dependencies(w http.ResponseWriter, r *http.Request) {
 	}
 	endTs := time.Unix(0, 0).Add(time.Duration(endTsMillis) * time.Millisecond)
 
+	if detailed, _ := strconv.ParseBool(r.FormValue(detailedParam)); detailed {
+		dependencies, err := aH.queryService.GetDetailedDependencies(endTs, lookback)
+		if err == dependencystore.ErrDetailedDependenciesNotSupported {
+			aH.handleError(w, err, http.StatusNotImplemented)
+			return
+		}
+		if aH.handleError(w, err, http.StatusInternalServerError) {
+			return
+		}
+		structuredRes := structuredResponse{
+			Data: aH.convertDetailedDependencies(aH.filterDependenciesByService(dependencies, service)),
+		}
+		aH.writeJSON(w, r, &structuredRes)
+		return
+	}
+
 	dependencies, err := aH.queryService.GetDependencies(endTs, lookback)
 	if aH.handleError(w, err, http.StatusInternalServerError) {
 		return
@@ -297,11 +510,12 @@ func (aH *APIHandler) dependencies(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
-func (aH *APIHandler) convertModelToUI(trace *model.Trace, adjust bool) (*ui.Trace, *structuredError) {
+// convertModelToUI adjusts the trace with the adjuster, unless it is nil, and converts it to the UI model.
+func (aH *APIHandler) convertModelToUI(trace *model.Trace, adj adjuster.Adjuster) (*ui.Trace, *structuredError) {
 	var errors []error
-	if adjust {
+	if adj != nil {
 		var err error
-		trace, err = aH.queryService.Adjust(trace)
+		trace, err = adj.Adjust(trace)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -336,6 +550,25 @@ func (aH *APIHandler) deduplicateDependencies(dependencies []model.DependencyLin
 	return result
 }
 
+func (aH *APIHandler) convertDetailedDependencies(dependencies []model.DependencyLink) []ui.DependencyLink {
+	result := make([]ui.DependencyLink, len(dependencies))
+	for i, l := range dependencies {
+		result[i] = ui.DependencyLink{
+			Parent:          l.Parent,
+			Child:           l.Child,
+			CallCount:       l.CallCount,
+			ParentOperation: l.ParentOperation,
+			ChildOperation:  l.ChildOperation,
+			Kind:            strings.ToLower(l.Kind.String()),
+			ErrorCount:      l.ErrorCount,
+			LatencyP50:      model.DurationAsMicroseconds(l.LatencyP50),
+			LatencyP95:      model.DurationAsMicroseconds(l.LatencyP95),
+			LatencyP99:      model.DurationAsMicroseconds(l.LatencyP99),
+		}
+	}
+	return result
+}
+
 func (aH *APIHandler) filterDependenciesByService(
 	dependencies []model.DependencyLink,
 	service string,
@@ -367,12 +600,25 @@ func (aH *APIHandler) parseTraceID(w http.ResponseWriter, r *http.Request) (mode
 // getTrace implements the REST API /traces/{trace-id}
 // It parses trace ID from the path, fetches the trace from QueryService,
 // formats it in the UI JSON format, and responds to the client.
+// With the linkDepth parameter, the response also contains the traces linked
+// by the references of the spans to other traces, see getLinkedTraces.
+// A trace exceeding the span limit is reduced, see QueryService.GetLimitedTrace, and the response
+// contains the traceSummary; the full trace is returned by the gRPC GetTrace API, and by the export API
+// unless it exceeds the read limit, see QueryService.GetBoundedTrace.
 func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
 	traceID, ok := aH.parseTraceID(w, r)
 	if !ok {
 		return
 	}
-	trace, err := aH.queryService.GetTrace(r.Context(), traceID)
+	adj, err := aH.optionalAdjuster(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	if r.FormValue(linkDepthParam) != "" {
+		aH.getLinkedTraces(w, r, traceID, adj)
+		return
+	}
+	trace, summary, err := aH.queryService.GetLimitedTrace(r.Context(), traceID)
 	if err == spanstore.ErrTraceNotFound {
 		aH.handleError(w, err, http.StatusNotFound)
 		return
@@ -382,7 +628,7 @@ func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var uiErrors []structuredError
-	uiTrace, uiErr := aH.convertModelToUI(trace, shouldAdjust(r))
+	uiTrace, uiErr := aH.convertModelToUI(trace, adj)
 	if uiErr != nil {
 		uiErrors = append(uiErrors, *uiErr)
 	}
@@ -391,11 +637,465 @@ func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
 		Data: []*ui.Trace{
 			uiTrace,
 		},
+		TraceSummary: newTraceSummary(summary),
+		Errors:       uiErrors,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// getLinkedTraces responds with the trace followed by the traces it is linked to, up to the depth
+// given by the linkDepth parameter, at most the configured one, and within the configured span and fetch budgets.
+// The references linking the traces are listed in traceLinks.
+func (aH *APIHandler) getLinkedTraces(w http.ResponseWriter, r *http.Request, traceID model.TraceID, adj adjuster.Adjuster) {
+	depth, err := strconv.Atoi(r.FormValue(linkDepthParam))
+	if err != nil || depth < 0 {
+		aH.handleError(w, fmt.Errorf("malformed '%s' parameter, expecting a non-negative integer: %s", linkDepthParam, r.FormValue(linkDepthParam)), http.StatusBadRequest)
+		return
+	}
+	opts := aH.traceLinks
+	if depth < opts.MaxDepth {
+		opts.MaxDepth = depth
+	}
+	linked, err := aH.queryService.GetLinkedTraces(r.Context(), traceID, opts)
+	if err == spanstore.ErrTraceNotFound {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	var uiErrors []structuredError
+	uiTraces := make([]*ui.Trace, len(linked.Traces))
+	for i, trace := range linked.Traces {
+		uiTrace, uiErr := aH.convertModelToUI(trace, adj)
+		if uiErr != nil {
+			uiErrors = append(uiErrors, *uiErr)
+		}
+		uiTraces[i] = uiTrace
+	}
+	structuredRes := structuredResponse{
+		Data:       uiTraces,
+		TraceLinks: newTraceLinks(linked),
+		Errors:     uiErrors,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// exportTrace implements the REST API /traces/{trace-id}/export
+// It responds with the trace as a file in the format given by the format parameter
+// or negotiated through the Accept header, see export.Negotiate.
+func (aH *APIHandler) exportTrace(w http.ResponseWriter, r *http.Request) {
+	format, err := export.Negotiate(r.FormValue(formatParam), r.Header.Get("Accept"))
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	traceID, ok := aH.parseTraceID(w, r)
+	if !ok {
+		return
+	}
+	adj, err := aH.optionalAdjuster(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	trace, err := aH.queryService.GetBoundedTrace(r.Context(), traceID)
+	if aH.handleError(w, err, boundedTraceStatus(err)) {
+		return
+	}
+	if adj != nil {
+		if trace, err = adj.Adjust(trace); err != nil {
+			aH.logger.Debug("Failed to adjust exported trace", zap.Stringer("trace_id", traceID), zap.Error(err))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := export.Export(&buf, trace, format); aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	w.Header().Set("Content-Type", format.MediaType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s.json"`, traceID, format))
+	w.Write(buf.Bytes())
+}
+
+// criticalPath implements the REST API /traces/{trace-id}/critical-path
+// It responds with the critical path of the trace, see analysis.ComputeCriticalPath.
+// The trace is always adjusted, since the path relies on the clock skew adjustment.
+func (aH *APIHandler) criticalPath(w http.ResponseWriter, r *http.Request) {
+	traceID, ok := aH.parseTraceID(w, r)
+	if !ok {
+		return
+	}
+	adj, err := aH.parseAdjuster(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	trace, err := aH.queryService.GetBoundedTrace(r.Context(), traceID)
+	if aH.handleError(w, err, boundedTraceStatus(err)) {
+		return
+	}
+	var uiErrors []structuredError
+	if trace, err = adj.Adjust(trace); err != nil {
+		uiErrors = append(uiErrors, structuredError{Msg: err.Error(), TraceID: ui.TraceID(traceID.String())})
+	}
+
+	path := analysis.ComputeCriticalPath(trace)
+	response := criticalPathResponse{
+		TraceID:    ui.TraceID(traceID.String()),
+		Segments:   make([]criticalPathSegment, len(path.Segments)),
+		Services:   criticalPathDurations(path.Services),
+		Operations: criticalPathDurations(path.Operations),
+	}
+	for i, segment := range path.Segments {
+		response.Segments[i] = criticalPathSegment{
+			SpanID:        ui.SpanID(segment.Span.SpanID.String()),
+			ServiceName:   segment.Span.Process.ServiceName,
+			OperationName: segment.Span.OperationName,
+			StartTime:     model.TimeAsEpochMicroseconds(segment.Start),
+			Duration:      model.DurationAsMicroseconds(segment.Duration()),
+		}
+	}
+	structuredRes := structuredResponse{
+		Data:   response,
 		Errors: uiErrors,
 	}
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+func criticalPathDurations(times []analysis.CriticalTime) []criticalPathDuration {
+	durations := make([]criticalPathDuration, len(times))
+	for i, t := range times {
+		durations[i] = criticalPathDuration{
+			ServiceName:   t.Service,
+			OperationName: t.Operation,
+			Duration:      model.DurationAsMicroseconds(t.Duration),
+		}
+	}
+	return durations
+}
+
+// traceStats implements the REST API /traces/{trace-id}/stats
+// It responds with the statistics of the trace, grouped by service and operation,
+// and also by the value of the tag given by the groupBy parameter if any.
+func (aH *APIHandler) traceStats(w http.ResponseWriter, r *http.Request) {
+	traceID, ok := aH.parseTraceID(w, r)
+	if !ok {
+		return
+	}
+	adj, err := aH.parseAdjuster(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	trace, err := aH.queryService.GetBoundedTrace(r.Context(), traceID)
+	if aH.handleError(w, err, boundedTraceStatus(err)) {
+		return
+	}
+	stats, uiErr := aH.traceStatsOf(trace, adj, r.FormValue(groupByParam))
+	var uiErrors []structuredError
+	if uiErr != nil {
+		uiErrors = append(uiErrors, *uiErr)
+	}
+	structuredRes := structuredResponse{
+		Data:   stats,
+		Errors: uiErrors,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// flameGraph implements the REST API /flamegraph
+// It folds the traces found by the query, see queryParser.parse, into an aggregate flame graph.
+// It responds with the JSON tree of analysis.NewFlameGraph, or with format=collapsed
+// with the collapsed stacks of analysis.WriteCollapsedStacks.
+func (aH *APIHandler) flameGraph(w http.ResponseWriter, r *http.Request) {
+	format := r.FormValue(formatParam)
+	if format != "" && format != flameGraphFormatJSON && format != flameGraphFormatCollapsed {
+		aH.handleError(w, fmt.Errorf("unsupported flame graph format '%s', expecting '%s' or '%s'",
+			format, flameGraphFormatJSON, flameGraphFormatCollapsed), http.StatusBadRequest)
+		return
+	}
+	tQuery, err := aH.queryParser.parse(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	adj, err := aH.optionalAdjuster(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	traces, _, _, err := aH.findTraces(r.Context(), tQuery)
+	if aH.handleError(w, err, findTracesStatus(err)) {
+		return
+	}
+	if adj != nil {
+		for i, trace := range traces {
+			// the adjusters return the trace even if they fail
+			traces[i], _ = adj.Adjust(trace)
+		}
+	}
+
+	tree := analysis.NewCallTree(traces...)
+	if format == flameGraphFormatCollapsed {
+		var buf bytes.Buffer
+		if err := analysis.WriteCollapsedStacks(&buf, tree); aH.handleError(w, err, http.StatusInternalServerError) {
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write(buf.Bytes())
+		return
+	}
+	aH.writeJSON(w, r, analysis.NewFlameGraph(tree))
+}
+
+// diff implements the REST API /diff?a={trace-id}&b={trace-id}
+// It compares the baseline traces given by the a parameters with the traces given by the b parameters,
+// see analysis.Diff. Either parameter can be repeated to compare with an aggregate of the traces.
+func (aH *APIHandler) diff(w http.ResponseWriter, r *http.Request) {
+	traceIDsA, err := parseTraceIDs(r, diffAParam)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	traceIDsB, err := parseTraceIDs(r, diffBParam)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	adj, err := aH.optionalAdjuster(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	tracesA, uiErrorsA, err := aH.adjustedTraces(r.Context(), adj, traceIDsA)
+	if aH.handleError(w, err, boundedTraceStatus(err)) {
+		return
+	}
+	tracesB, uiErrorsB, err := aH.adjustedTraces(r.Context(), adj, traceIDsB)
+	if aH.handleError(w, err, boundedTraceStatus(err)) {
+		return
+	}
+
+	diff := analysis.Diff(analysis.NewCallTree(tracesA...), analysis.NewCallTree(tracesB...))
+	structuredRes := structuredResponse{
+		Data:   newTraceDiffResponse(traceIDsA, traceIDsB, diff),
+		Errors: append(uiErrorsA, uiErrorsB...),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// regressions implements the REST API /regressions, which compares the operations of a service
+// between a window before and a window after, e.g. a deploy.
+func (aH *APIHandler) regressions(w http.ResponseWriter, r *http.Request) {
+	service := r.FormValue(serviceParam)
+	if service == "" {
+		aH.handleError(w, ErrServiceParameterRequired, http.StatusBadRequest)
+		return
+	}
+	before, err := parseWindow(r, beforeStartParam, beforeEndParam)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	after, err := parseWindow(r, afterStartParam, afterEndParam)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	numTraces := defaultRegressionTraces
+	if limit := r.FormValue(limitParam); limit != "" {
+		if numTraces, err = strconv.Atoi(limit); err != nil || numTraces <= 0 || numTraces > maxRegressionTraces {
+			aH.handleError(w, fmt.Errorf("malformed '%s' parameter, expecting a positive integer up to %d: %s", limitParam, maxRegressionTraces, limit), http.StatusBadRequest)
+			return
+		}
+	}
+	opts := analysis.DefaultRegressionOptions
+	for _, win := range []*window{&before, &after} {
+		if err := aH.windowTraceStats(r.Context(), service, win, numTraces, opts); aH.handleError(w, err, http.StatusInternalServerError) {
+			return
+		}
+	}
+	if err := aH.windowSpanMetricsStats(r.Context(), service, []*window{&before, &after}); aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	regressions := analysis.DetectRegressions(before.stats, after.stats, opts)
+	structuredRes := structuredResponse{
+		Data:  newRegressionResponse(service, before, after, regressions),
+		Total: len(regressions),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// window is a time window compared by the regressions API.
+type window struct {
+	start time.Time
+	end   time.Time
+	// source is where the stats come from, either span metrics or the newest traces of the window.
+	source string
+	traces int
+	stats  map[string]*analysis.OperationStats
+}
+
+const (
+	windowSourceSpanMetrics = "spanMetrics"
+	windowSourceTraces      = "traces"
+)
+
+// parseWindow parses the start and end parameters of a window, in microseconds since epoch.
+func parseWindow(r *http.Request, startParam, endParam string) (window, error) {
+	var times [2]time.Time
+	for i, param := range []string{startParam, endParam} {
+		value := r.FormValue(param)
+		if value == "" {
+			return window{}, fmt.Errorf("parameter '%s' is required", param)
+		}
+		micros, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return window{}, fmt.Errorf("unable to parse %s: %w", param, err)
+		}
+		times[i] = model.EpochMicrosecondsAsTime(uint64(micros))
+	}
+	if !times[0].Before(times[1]) {
+		return window{}, fmt.Errorf("'%s' should be greater than '%s'", endParam, startParam)
+	}
+	return window{start: times[0], end: times[1]}, nil
+}
+
+// windowTraceStats computes the stats of the operations of the service in the window from its newest
+// traces, which also provide the example traces.
+func (aH *APIHandler) windowTraceStats(ctx context.Context, service string, win *window, numTraces int, opts analysis.RegressionOptions) error {
+	traces, err := aH.queryService.FindTraces(ctx, &spanstore.TraceQueryParameters{
+		ServiceName:  service,
+		StartTimeMin: win.start,
+		StartTimeMax: win.end,
+		NumTraces:    numTraces,
+	})
+	if err != nil {
+		return err
+	}
+	win.traces = len(traces)
+	win.source = windowSourceTraces
+	win.stats = analysis.NewOperationStats(service, traces, opts.MaxExamples)
+	return nil
+}
+
+// windowSpanMetricsStats replaces the stats of the windows with the ones of the span metrics if they
+// still cover all the windows, so that the windows are always compared from the same source.
+func (aH *APIHandler) windowSpanMetricsStats(ctx context.Context, service string, windows []*window) error {
+	oldest := aH.queryParser.timeNow().Add(-spanmetrics.Window)
+	for _, win := range windows {
+		if win.start.Before(oldest) {
+			return nil
+		}
+	}
+	stats := make([]map[string]*analysis.OperationStats, len(windows))
+	for i, win := range windows {
+		series, err := aH.queryService.GetSpanMetrics(ctx, spanmetrics.Query{
+			ServiceName: service,
+			EndTime:     win.end,
+			Lookback:    win.end.Sub(win.start),
+		})
+		if err == querysvc.ErrNoSpanMetrics {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		stats[i] = analysis.NewOperationStatsFromSpanMetrics(series, win.stats)
+	}
+	for i, win := range windows {
+		win.source = windowSourceSpanMetrics
+		win.stats = stats[i]
+	}
+	return nil
+}
+
+// quality reports the instrumentation issues of the services found in the traces matching the search parameters.
+func (aH *APIHandler) quality(w http.ResponseWriter, r *http.Request) {
+	tQuery, err := aH.queryParser.parse(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	adj, err := aH.parseAdjuster(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	traces, _, uiErrors, err := aH.findTraces(r.Context(), tQuery)
+	if aH.handleError(w, err, findTracesStatus(err)) {
+		return
+	}
+	adjusted := make([]*model.Trace, len(traces))
+	for i, v := range traces {
+		trace, uiErr := aH.adjust(v, adj)
+		if uiErr != nil {
+			uiErrors = append(uiErrors, *uiErr)
+		}
+		adjusted[i] = trace
+	}
+	quality := analysis.ComputeQuality(adjusted, aH.qualityOptions)
+	structuredRes := structuredResponse{
+		Data:   newServiceQualityResponse(quality),
+		Total:  len(quality),
+		Errors: uiErrors,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// parseTraceIDs returns the trace IDs given by the repeated parameter, of which there must be at least one
+// and at most maxTraceIDs.
+func parseTraceIDs(r *http.Request, param string) ([]model.TraceID, error) {
+	values := r.URL.Query()[param]
+	if len(values) == 0 {
+		return nil, fmt.Errorf("parameter '%s' is required", param)
+	}
+	if len(values) > maxTraceIDs {
+		return nil, fmt.Errorf("parameter '%s' cannot be repeated more than %d times", param, maxTraceIDs)
+	}
+	traceIDs := make([]model.TraceID, len(values))
+	for i, value := range values {
+		traceID, err := model.TraceIDFromString(value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse traceID param: %w", err)
+		}
+		traceIDs[i] = traceID
+	}
+	return traceIDs, nil
+}
+
+// adjustedTraces loads the traces, adjusted with the adjuster unless it is nil. It fails if a trace is not found
+// or too large, see QueryService.GetBoundedTrace.
+func (aH *APIHandler) adjustedTraces(ctx context.Context, adj adjuster.Adjuster, traceIDs []model.TraceID) ([]*model.Trace, []structuredError, error) {
+	var traces []*model.Trace
+	var uiErrors []structuredError
+	for _, traceID := range traceIDs {
+		trace, err := aH.queryService.GetBoundedTrace(ctx, traceID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if adj != nil {
+			if trace, err = adj.Adjust(trace); err != nil {
+				uiErrors = append(uiErrors, structuredError{Msg: err.Error(), TraceID: ui.TraceID(traceID.String())})
+			}
+		}
+		traces = append(traces, trace)
+	}
+	return traces, uiErrors, nil
+}
+
+// parseAdjuster returns the adjusters given by the adjusters parameter, a comma-separated list of adjuster
+// names applied in order, see querysvc.AdjusterNames, or else the adjusters configured in the query service.
+func (aH *APIHandler) parseAdjuster(r *http.Request) (adjuster.Adjuster, error) {
+	names := r.FormValue(adjustersParam)
+	if names == "" {
+		return adjuster.Func(aH.queryService.Adjust), nil
+	}
+	adjusters, err := querysvc.AdjustersByName(parseAdjusterNames(names))
+	if err != nil {
+		return nil, fmt.Errorf("malformed '%s' parameter: %w", adjustersParam, err)
+	}
+	return adjuster.Sequence(adjusters...), nil
+}
+
+// optionalAdjuster returns nil if the raw parameter is set, or else the adjuster of parseAdjuster.
+func (aH *APIHandler) optionalAdjuster(r *http.Request) (adjuster.Adjuster, error) {
+	if !shouldAdjust(r) {
+		return nil, nil
+	}
+	return aH.parseAdjuster(r)
+}
+
 func shouldAdjust(r *http.Request) bool {
 	raw := r.FormValue("raw")
 	isRaw, _ := strconv.ParseBool(raw)
@@ -427,6 +1127,153 @@ func (aH *APIHandler) archiveTrace(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+// importTraces implements the REST API POST:/import.
+// It accepts trace files either as the files of a multipart form or as the request body,
+// in the format given by the format parameter or detected from their content, see importer.Parse.
+// The spans are written to the target given by the target parameter, the upload store by default,
+// and the response holds the IDs of the imported traces.
+func (aH *APIHandler) importTraces(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	files, err := importFiles(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	format, err := importer.ParseFormat(r.FormValue(formatParam))
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	target := querysvc.ImportTarget(r.FormValue(targetParam))
+	if target == "" {
+		target = querysvc.ImportTargetUploaded
+	}
+
+	var spans []*model.Span
+	for _, file := range files {
+		fileSpans, err := importer.Parse(file, format)
+		if aH.handleError(w, err, http.StatusBadRequest) {
+			return
+		}
+		spans = append(spans, fileSpans...)
+	}
+	traceIDs, err := aH.queryService.ImportSpans(r.Context(), spans, target)
+	if err == querysvc.ErrNoUploadStore || err == querysvc.ErrNoImportSpanStorage {
+		aH.handleError(w, err, http.StatusForbidden)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	uiTraceIDs := make([]ui.TraceID, len(traceIDs))
+	for i, traceID := range traceIDs {
+		uiTraceIDs[i] = ui.TraceID(traceID.String())
+	}
+	structuredRes := structuredResponse{
+		Data:   uiTraceIDs,
+		Total:  len(uiTraceIDs),
+		Errors: []structuredError{},
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// importFiles returns the contents of the files of a multipart form, or the request body.
+func importFiles(r *http.Request) ([][]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{body}, nil
+	}
+	if err := r.ParseMultipartForm(maxImportBytes); err != nil {
+		return nil, err
+	}
+	var files [][]byte
+	for _, headers := range r.MultipartForm.File {
+		for _, header := range headers {
+			file, err := header.Open()
+			if err != nil {
+				return nil, err
+			}
+			content, err := ioutil.ReadAll(file)
+			file.Close()
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, content)
+		}
+	}
+	return files, nil
+}
+
+// tailSpans streams the live spans matching the service, operation, tag and tags parameters
+// as server-sent events. The data of an event is a trace in the UI format holding a single span.
+// If the subscriber is dropped because it does not keep up with the spans, an "error" event
+// is sent before the stream ends.
+func (aH *APIHandler) tailSpans(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	tags, err := aH.queryParser.parseTags(r.Form[tagParam], r.Form[tagsParam])
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		aH.handleError(w, errors.New("streaming is not supported by the connection"), http.StatusInternalServerError)
+		return
+	}
+	sub, err := aH.queryService.TailSpans(livetail.Filter{
+		ServiceName:   r.Form.Get(serviceParam),
+		OperationName: r.Form.Get(operationParam),
+		Tags:          tags,
+	})
+	if err == querysvc.ErrNoLiveTail {
+		aH.handleError(w, err, http.StatusNotImplemented)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	err = livetail.Forward(r.Context(), sub, func(spans []model.Span) error {
+		for i := range spans {
+			uiTrace := uiconv.FromDomain(aH.queryService.Redact(r.Context(), &model.Trace{Spans: []*model.Span{&spans[i]}}))
+			if err := writeEvent(w, "", uiTrace); err != nil {
+				return err
+			}
+		}
+		flusher.Flush()
+		return nil
+	})
+	if subErr := sub.Err(); subErr != nil {
+		writeEvent(w, "error", structuredError{Msg: subErr.Error()})
+		flusher.Flush()
+	} else if err != nil {
+		aH.logger.Debug("Live tail stream ended", zap.Error(err))
+	}
+}
+
+// writeEvent writes a server-sent event with the JSON of data, of the default type if eventType is empty.
+func writeEvent(w io.Writer, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if eventType != "" {
+		if _, err := fmt.Fprintf(w, "event: %s\n", eventType); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", payload)
+	return err
+}
+
 func (aH *APIHandler) handleError(w http.ResponseWriter, err error, statusCode int) bool {
 	if err == nil {
 		return false
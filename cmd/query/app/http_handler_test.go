@@ -16,14 +16,18 @@
 package app
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -33,13 +37,20 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-client-go"
+	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/analysis"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/cmd/query/app/redaction"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
+	uiconv "github.com/jaegertracing/jaeger/model/converter/json"
 	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
@@ -226,6 +237,9 @@ func TestGetTrace(t *testing.T) {
 		{suffix: "", numSpanRefs: 0},
 		{suffix: "?raw=true", numSpanRefs: 1}, // bad span reference is not filtered out
 		{suffix: "?raw=false", numSpanRefs: 0},
+		{suffix: "?adjusters=sort-log-fields", numSpanRefs: 1}, // span references are not adjusted
+		{suffix: "?adjusters=sort-log-fields,span-references", numSpanRefs: 0},
+		{suffix: "?raw=true&adjusters=span-references", numSpanRefs: 1},
 	}
 
 	makeMockTrace := func(t *testing.T) *model.Trace {
@@ -318,6 +332,55 @@ func TestGetTraceAdjustmentFailure(t *testing.T) {
 	assert.EqualValues(t, errAdjustment.Error(), response.Errors[0].Msg)
 }
 
+func TestGetTraceLimited(t *testing.T) {
+	server, readMock, _, _ := initializeTestServerWithHandler(querysvc.QueryServiceOptions{
+		TraceLimits: querysvc.TraceLimitOptions{MaxSpans: 1},
+	})
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(&model.Trace{Spans: []*model.Span{
+			{TraceID: mockTraceID, SpanID: model.NewSpanID(1), OperationName: "job", Process: model.NewProcess("batch", nil)},
+			{
+				TraceID:       mockTraceID,
+				SpanID:        model.NewSpanID(2),
+				OperationName: "query",
+				References:    []model.SpanRef{model.NewChildOfRef(mockTraceID, model.NewSpanID(1))},
+				Process:       model.NewProcess("batch", nil),
+			},
+		}}, nil).Once()
+
+	var response struct {
+		Traces       []*ui.Trace   `json:"data"`
+		TraceSummary *traceSummary `json:"traceSummary"`
+	}
+	err := getJSON(server.URL+"/api/traces/"+mockTraceID.String(), &response)
+	require.NoError(t, err)
+	require.Len(t, response.Traces, 1)
+	require.Len(t, response.Traces[0].Spans, 1)
+	assert.Equal(t, "job", response.Traces[0].Spans[0].OperationName)
+	assert.Equal(t, &traceSummary{
+		TotalSpans:    2,
+		ReturnedSpans: 1,
+		Omitted:       []omittedSpans{{ServiceName: "batch", OperationName: "query", Spans: 1}},
+	}, response.TraceSummary)
+}
+
+func TestGetTraceUnknownAdjuster(t *testing.T) {
+	server, _, _ := initializeTestServer()
+	defer server.Close()
+
+	for _, path := range []string{
+		`/api/traces/123456?adjusters=clock-skew,unknown`,
+		`/api/traces/123456/critical-path?adjusters=clock-skew,unknown`,
+		`/api/traces?service=svc&adjusters=clock-skew,unknown`,
+	} {
+		var response structuredResponse
+		err := getJSON(server.URL+path, &response)
+		assert.Error(t, err, path)
+		assert.Contains(t, err.Error(), "unknown adjuster 'unknown'", path)
+	}
+}
+
 func TestGetTraceBadTraceID(t *testing.T) {
 	server, _, _ := initializeTestServer()
 	defer server.Close()
@@ -327,6 +390,900 @@ func TestGetTraceBadTraceID(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGetTraceRedacted(t *testing.T) {
+	redactor, err := redaction.NewRedactor([]redaction.Rule{{ValuePattern: `@example\.com$`}}, redaction.Options{})
+	require.NoError(t, err)
+	server, readMock, _, _ := initializeTestServerWithHandler(querysvc.QueryServiceOptions{Redactor: redactor})
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(&model.Trace{Spans: []*model.Span{
+			{
+				TraceID: mockTraceID,
+				SpanID:  model.NewSpanID(1),
+				Logs: []model.Log{
+					{Fields: []model.KeyValue{model.String("user", "alice@example.com")}},
+				},
+				Process: &model.Process{ServiceName: "frontend"},
+			},
+		}}, nil).Once()
+
+	var response structuredTraceResponse
+	err = getJSON(server.URL+"/api/traces/"+mockTraceID.String(), &response)
+	require.NoError(t, err)
+	require.Len(t, response.Traces, 1)
+	assert.Equal(t, redaction.Masked, response.Traces[0].Spans[0].Logs[0].Fields[0].Value)
+}
+
+func TestSearchRedacted(t *testing.T) {
+	redactor, err := redaction.NewRedactor([]redaction.Rule{{Keys: []string{"user.email"}}}, redaction.Options{})
+	require.NoError(t, err)
+	server, _, _, _ := initializeTestServerWithHandler(querysvc.QueryServiceOptions{Redactor: redactor})
+	defer server.Close()
+
+	err = getJSON(server.URL+`/api/traces?service=frontend&tag=user.email:alice@example.com`, nil)
+	assert.EqualError(t, err, parsedError(400, "cannot search the tag values redacted for the caller: user.email"))
+}
+
+func TestGetTraceLinked(t *testing.T) {
+	server, readMock, _ := initializeTestServer(HandlerOptions.TraceLinks(querysvc.TraceLinkOptions{MaxDepth: 1, MaxSpans: 10, MaxFetches: 10}))
+	defer server.Close()
+	producerTraceID := model.NewTraceID(0, 0xabc)
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(&model.Trace{Spans: []*model.Span{
+			{
+				TraceID:    mockTraceID,
+				SpanID:     model.NewSpanID(1),
+				References: []model.SpanRef{model.NewFollowsFromRef(producerTraceID, model.NewSpanID(2))},
+				Process:    &model.Process{ServiceName: "consumer"},
+			},
+		}}, nil).Once()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), producerTraceID).
+		Return(&model.Trace{Spans: []*model.Span{
+			{
+				TraceID:    producerTraceID,
+				SpanID:     model.NewSpanID(2),
+				References: []model.SpanRef{model.NewChildOfRef(model.NewTraceID(0, 0xdef), model.NewSpanID(3))},
+				Process:    &model.Process{ServiceName: "producer"},
+			},
+		}}, nil).Once()
+
+	var response struct {
+		Traces     []*ui.Trace       `json:"data"`
+		TraceLinks *traceLinks       `json:"traceLinks"`
+		Errors     []structuredError `json:"errors"`
+	}
+	// the depth is capped by the handler option
+	err := getJSON(server.URL+"/api/traces/"+mockTraceID.String()+"?linkDepth=5", &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+	require.Len(t, response.Traces, 2)
+	assert.Equal(t, ui.TraceID(mockTraceID.String()), response.Traces[0].TraceID)
+	assert.Equal(t, ui.TraceID(producerTraceID.String()), response.Traces[1].TraceID)
+	assert.Equal(t, &traceLinks{
+		Links: []traceLink{
+			{
+				TraceID:       ui.TraceID(mockTraceID.String()),
+				SpanID:        ui.SpanID(model.NewSpanID(1).String()),
+				RefType:       ui.FollowsFrom,
+				TargetTraceID: ui.TraceID(producerTraceID.String()),
+				TargetSpanID:  ui.SpanID(model.NewSpanID(2).String()),
+				Loaded:        true,
+			},
+			{
+				TraceID:       ui.TraceID(producerTraceID.String()),
+				SpanID:        ui.SpanID(model.NewSpanID(2).String()),
+				RefType:       ui.ChildOf,
+				TargetTraceID: ui.TraceID(model.NewTraceID(0, 0xdef).String()),
+				TargetSpanID:  ui.SpanID(model.NewSpanID(3).String()),
+				Loaded:        false,
+			},
+		},
+		Truncated: true,
+	}, response.TraceLinks)
+}
+
+func TestGetTraceLinkedFailures(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+
+	err := getJSON(server.URL+"/api/traces/123456?linkDepth=-1", nil)
+	assert.EqualError(t, err, parsedError(400, "malformed 'linkDepth' parameter, expecting a non-negative integer: -1"))
+
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	err = getJSON(server.URL+"/api/traces/123456?linkDepth=1", nil)
+	assert.EqualError(t, err, parsedError(404, "trace not found"))
+
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(nil, errStorage).Once()
+	err = getJSON(server.URL+"/api/traces/123456?linkDepth=1", nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+}
+
+func TestExportTrace(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 0x123456)).
+		Return(mockTrace, nil).Twice()
+
+	resp, err := http.Get(server.URL + `/api/traces/123456/export?format=zipkin`)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/vnd.zipkin.v2+json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="0000000000123456.zipkin.json"`, resp.Header.Get("Content-Disposition"))
+	var spans []map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&spans))
+	assert.Len(t, spans, 2)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+`/api/traces/123456/export?raw=true`, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/vnd.jaegertracing.api-v2+json")
+	resp, err = httpClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/vnd.jaegertracing.api-v2+json", resp.Header.Get("Content-Type"))
+}
+
+func TestExportTraceFailures(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 1)).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 2)).
+		Return(nil, errStorage).Once()
+
+	err := getJSON(server.URL+`/api/traces/1/export?format=xml`, nil)
+	assert.EqualError(t, err, parsedError(400, "unsupported export format 'xml', expecting one of [jaeger otlp zipkin chrome]"))
+	err = getJSON(server.URL+`/api/traces/chumbawumba/export`, nil)
+	assert.Error(t, err)
+	err = getJSON(server.URL+`/api/traces/1/export`, nil)
+	assert.EqualError(t, err, parsedError(404, "trace not found"))
+	err = getJSON(server.URL+`/api/traces/2/export`, nil)
+	assert.Error(t, err)
+}
+
+func TestCriticalPath(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	start := time.Unix(1585735200, 0)
+	trace := &model.Trace{Spans: []*model.Span{
+		{
+			TraceID:       mockTraceID,
+			SpanID:        model.NewSpanID(1),
+			OperationName: "GET",
+			StartTime:     start,
+			Duration:      100 * time.Millisecond,
+			Process:       &model.Process{ServiceName: "frontend"},
+		},
+		{
+			TraceID:       mockTraceID,
+			SpanID:        model.NewSpanID(2),
+			OperationName: "SELECT",
+			References:    []model.SpanRef{model.NewChildOfRef(mockTraceID, model.NewSpanID(1))},
+			StartTime:     start.Add(10 * time.Millisecond),
+			Duration:      60 * time.Millisecond,
+			Process:       &model.Process{ServiceName: "db"},
+		},
+	}}
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(trace, nil).Once()
+
+	var response struct {
+		Data   criticalPathResponse `json:"data"`
+		Errors []structuredError    `json:"errors"`
+	}
+	err := getJSON(server.URL+"/api/traces/"+mockTraceID.String()+"/critical-path", &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, ui.TraceID("000000000001e240"), response.Data.TraceID)
+	assert.Equal(t, []criticalPathSegment{
+		{SpanID: "0000000000000001", ServiceName: "frontend", OperationName: "GET", StartTime: 1585735200000000, Duration: 10000},
+		{SpanID: "0000000000000002", ServiceName: "db", OperationName: "SELECT", StartTime: 1585735200010000, Duration: 60000},
+		{SpanID: "0000000000000001", ServiceName: "frontend", OperationName: "GET", StartTime: 1585735200070000, Duration: 30000},
+	}, response.Data.Segments)
+	assert.Equal(t, []criticalPathDuration{
+		{ServiceName: "db", Duration: 60000},
+		{ServiceName: "frontend", Duration: 40000},
+	}, response.Data.Services)
+	assert.Equal(t, []criticalPathDuration{
+		{ServiceName: "db", OperationName: "SELECT", Duration: 60000},
+		{ServiceName: "frontend", OperationName: "GET", Duration: 40000},
+	}, response.Data.Operations)
+}
+
+func TestTraceTooLarge(t *testing.T) {
+	server, readMock, _, _ := initializeTestServerWithHandler(querysvc.QueryServiceOptions{
+		TraceLimits: querysvc.TraceLimitOptions{MaxSpans: 1, MaxReadSpans: 1},
+	})
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(&model.Trace{Spans: []*model.Span{
+			{TraceID: mockTraceID, SpanID: model.NewSpanID(1), Process: model.NewProcess("batch", nil)},
+			{TraceID: mockTraceID, SpanID: model.NewSpanID(2), Process: model.NewProcess("batch", nil)},
+		}}, nil)
+
+	// the analyses and the export need the whole trace, which is not read beyond the read limit
+	for _, path := range []string{
+		"/api/traces/" + mockTraceID.String() + "/export",
+		"/api/traces/" + mockTraceID.String() + "/critical-path",
+		"/api/traces/" + mockTraceID.String() + "/stats",
+		"/api/diff?a=" + mockTraceID.String() + "&b=" + mockTraceID.String(),
+	} {
+		err := getJSON(server.URL+path, nil)
+		assert.EqualError(t, err, parsedError(413, querysvc.ErrTraceTooLarge.Error()), path)
+	}
+}
+
+func TestCriticalPathFailures(t *testing.T) {
+	server, readMock, _, _ := initializeTestServerWithHandler(
+		querysvc.QueryServiceOptions{
+			Adjuster: adjuster.Func(func(trace *model.Trace) (*model.Trace, error) {
+				return trace, errAdjustment
+			}),
+		},
+	)
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 1)).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 2)).
+		Return(nil, errStorage).Once()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(mockTrace, nil).Once()
+
+	err := getJSON(server.URL+`/api/traces/chumbawumba/critical-path`, nil)
+	assert.Error(t, err)
+	err = getJSON(server.URL+`/api/traces/1/critical-path`, nil)
+	assert.EqualError(t, err, parsedError(404, "trace not found"))
+	err = getJSON(server.URL+`/api/traces/2/critical-path`, nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+
+	var response structuredResponse
+	err = getJSON(server.URL+"/api/traces/"+mockTraceID.String()+"/critical-path", &response)
+	require.NoError(t, err)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, errAdjustment.Error(), response.Errors[0].Msg)
+}
+
+func TestDiff(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	start := time.Unix(1585735200, 0)
+	trace := func(traceID model.TraceID, duration time.Duration) *model.Trace {
+		return &model.Trace{Spans: []*model.Span{{
+			TraceID:       traceID,
+			SpanID:        model.NewSpanID(1),
+			OperationName: "GET",
+			StartTime:     start,
+			Duration:      duration,
+			Process:       &model.Process{ServiceName: "frontend"},
+		}}}
+	}
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 1)).
+		Return(trace(model.NewTraceID(0, 1), 10*time.Millisecond), nil).Once()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 2)).
+		Return(trace(model.NewTraceID(0, 2), 20*time.Millisecond), nil).Once()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 3)).
+		Return(trace(model.NewTraceID(0, 3), 45*time.Millisecond), nil).Once()
+
+	var response struct {
+		Data   traceDiffResponse `json:"data"`
+		Errors []structuredError `json:"errors"`
+	}
+	err := getJSON(server.URL+`/api/diff?a=1&a=2&b=3`, &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, traceDiffSummary{
+		TraceIDs: []ui.TraceID{"0000000000000001", "0000000000000002"},
+		Spans:    1,
+		Duration: 15000,
+	}, response.Data.A)
+	assert.Equal(t, []ui.TraceID{"0000000000000003"}, response.Data.B.TraceIDs)
+	assert.Equal(t, []*traceDiffNode{{
+		ServiceName:   "frontend",
+		OperationName: "GET",
+		Status:        "common",
+		CountA:        1,
+		CountB:        1,
+		DurationA:     15000,
+		DurationB:     45000,
+		DurationDelta: 30000,
+		Children:      []*traceDiffNode{},
+	}}, response.Data.Roots)
+}
+
+func TestDiffFailures(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 1)).
+		Return(mockTrace, nil)
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 2)).
+		Return(nil, spanstore.ErrTraceNotFound)
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 3)).
+		Return(nil, errStorage)
+
+	err := getJSON(server.URL+`/api/diff?b=1`, nil)
+	assert.EqualError(t, err, parsedError(400, "parameter 'a' is required"))
+	err = getJSON(server.URL+`/api/diff?a=1`, nil)
+	assert.EqualError(t, err, parsedError(400, "parameter 'b' is required"))
+	err = getJSON(server.URL+`/api/diff?a=1&b=x`, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot parse traceID param")
+	err = getJSON(server.URL+`/api/diff?b=1`+strings.Repeat("&a=1", maxTraceIDs+1), nil)
+	assert.EqualError(t, err, parsedError(400, "parameter 'a' cannot be repeated more than 20 times"))
+	err = getJSON(server.URL+`/api/diff?a=2&b=1`, nil)
+	assert.EqualError(t, err, parsedError(404, "trace not found"))
+	err = getJSON(server.URL+`/api/diff?a=1&b=2`, nil)
+	assert.EqualError(t, err, parsedError(404, "trace not found"))
+	err = getJSON(server.URL+`/api/diff?a=3&b=1`, nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+	err = getJSON(server.URL+`/api/diff?a=1&b=3`, nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+}
+
+func TestTraceStats(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	start := time.Unix(1585735200, 0)
+	trace := &model.Trace{Spans: []*model.Span{
+		{
+			TraceID:       mockTraceID,
+			SpanID:        model.NewSpanID(1),
+			OperationName: "GET",
+			StartTime:     start,
+			Duration:      100 * time.Millisecond,
+			Process:       &model.Process{ServiceName: "frontend"},
+		},
+		{
+			TraceID:       mockTraceID,
+			SpanID:        model.NewSpanID(2),
+			OperationName: "SELECT",
+			References:    []model.SpanRef{model.NewChildOfRef(mockTraceID, model.NewSpanID(1))},
+			StartTime:     start.Add(10 * time.Millisecond),
+			Duration:      60 * time.Millisecond,
+			Tags:          model.KeyValues{model.Bool("error", true), model.String("db.instance", "users")},
+			Process:       &model.Process{ServiceName: "db"},
+		},
+	}}
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(trace, nil).Once()
+	readMock.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{trace}, nil).Once()
+
+	expected := &traceStatsResponse{
+		TraceID:           "000000000001e240",
+		RootServiceName:   "frontend",
+		RootOperationName: "GET",
+		StartTime:         1585735200000000,
+		Duration:          100000,
+		Spans:             2,
+		Errors:            1,
+		Services:          2,
+		Depth:             2,
+		GroupByTag:        "db.instance",
+		Groups: []traceStatsGroup{
+			{ServiceName: "db", OperationName: "SELECT", TagValue: "users", Count: 1, Errors: 1,
+				Total: 60000, Average: 60000, Min: 60000, Max: 60000, SelfTime: 60000},
+			{ServiceName: "frontend", OperationName: "GET", Count: 1,
+				Total: 100000, Average: 100000, Min: 100000, Max: 100000, SelfTime: 40000},
+		},
+	}
+	var response struct {
+		Data   *traceStatsResponse `json:"data"`
+		Errors []structuredError   `json:"errors"`
+	}
+	err := getJSON(server.URL+"/api/traces/"+mockTraceID.String()+"/stats?groupBy=db.instance", &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, expected, response.Data)
+
+	var searchResponse struct {
+		Data   []*traceStatsResponse `json:"data"`
+		Errors []structuredError     `json:"errors"`
+	}
+	err = getJSON(server.URL+"/api/traces?service=frontend&summary=true&groupBy=db.instance", &searchResponse)
+	require.NoError(t, err)
+	assert.Empty(t, searchResponse.Errors)
+	assert.Equal(t, []*traceStatsResponse{expected}, searchResponse.Data)
+}
+
+func TestTraceStatsFailures(t *testing.T) {
+	server, readMock, _, _ := initializeTestServerWithHandler(
+		querysvc.QueryServiceOptions{
+			Adjuster: adjuster.Func(func(trace *model.Trace) (*model.Trace, error) {
+				return trace, errAdjustment
+			}),
+		},
+	)
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 1)).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 2)).
+		Return(nil, errStorage).Once()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(mockTrace, nil).Once()
+	readMock.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{mockTrace}, nil).Once()
+
+	err := getJSON(server.URL+`/api/traces/chumbawumba/stats`, nil)
+	assert.Error(t, err)
+	err = getJSON(server.URL+`/api/traces/1/stats`, nil)
+	assert.EqualError(t, err, parsedError(404, "trace not found"))
+	err = getJSON(server.URL+`/api/traces/2/stats`, nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+
+	var response structuredResponse
+	err = getJSON(server.URL+"/api/traces/"+mockTraceID.String()+"/stats", &response)
+	require.NoError(t, err)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, errAdjustment.Error(), response.Errors[0].Msg)
+
+	err = getJSON(server.URL+"/api/traces?service=frontend&summary=true", &response)
+	require.NoError(t, err)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, errAdjustment.Error(), response.Errors[0].Msg)
+}
+
+func TestSearchClusters(t *testing.T) {
+	server, readMock, _, _ := initializeTestServerWithHandler(
+		querysvc.QueryServiceOptions{
+			Adjuster: adjuster.Func(func(trace *model.Trace) (*model.Trace, error) {
+				return trace, errAdjustment
+			}),
+		},
+	)
+	defer server.Close()
+	readMock.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{mockTrace}, nil).Once()
+
+	var response struct {
+		Data   []traceCluster    `json:"data"`
+		Errors []structuredError `json:"errors"`
+	}
+	err := getJSON(server.URL+"/api/traces?service=frontend&cluster=true", &response)
+	require.NoError(t, err)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, errAdjustment.Error(), response.Errors[0].Msg)
+	require.Len(t, response.Data, 1)
+	cluster := response.Data[0]
+	assert.Len(t, cluster.Signature, 16)
+	assert.Equal(t, 1, cluster.Count)
+	assert.Equal(t, ui.TraceID(mockTraceID.String()), cluster.Representative)
+	assert.Equal(t, []ui.TraceID{ui.TraceID(mockTraceID.String())}, cluster.TraceIDs)
+}
+
+func TestFlameGraph(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	start := time.Unix(1585735200, 0)
+	trace := &model.Trace{Spans: []*model.Span{
+		{
+			TraceID:       mockTraceID,
+			SpanID:        model.NewSpanID(1),
+			OperationName: "GET",
+			StartTime:     start,
+			Duration:      100 * time.Millisecond,
+			Process:       &model.Process{ServiceName: "frontend"},
+		},
+		{
+			TraceID:       mockTraceID,
+			SpanID:        model.NewSpanID(2),
+			OperationName: "SELECT",
+			References:    []model.SpanRef{model.NewChildOfRef(mockTraceID, model.NewSpanID(1))},
+			StartTime:     start.Add(10 * time.Millisecond),
+			Duration:      60 * time.Millisecond,
+			Process:       &model.Process{ServiceName: "db"},
+		},
+	}}
+	readMock.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{trace, trace}, nil).Once()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(trace, nil).Once()
+
+	var graph analysis.FlameNode
+	err := getJSON(server.URL+"/api/flamegraph?service=frontend", &graph)
+	require.NoError(t, err)
+	assert.Equal(t, analysis.FlameNode{
+		Name:  "all",
+		Value: 200000,
+		Children: []*analysis.FlameNode{{
+			Name:  "frontend:GET",
+			Value: 200000,
+			Self:  80000,
+			Children: []*analysis.FlameNode{
+				{Name: "db:SELECT", Value: 120000, Self: 120000, Children: []*analysis.FlameNode{}},
+			},
+		}},
+	}, graph)
+
+	resp, err := http.Get(server.URL + "/api/flamegraph?format=collapsed&traceID=" + mockTraceID.String())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "frontend:GET 40000\nfrontend:GET;db:SELECT 60000\n", string(body))
+}
+
+func TestFlameGraphFailures(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return(nil, errStorage).Once()
+
+	err := getJSON(server.URL+"/api/flamegraph?service=frontend&format=svg", nil)
+	assert.EqualError(t, err, parsedError(400, "unsupported flame graph format 'svg', expecting 'json' or 'collapsed'"))
+	err = getJSON(server.URL+"/api/flamegraph", nil)
+	assert.EqualError(t, err, parsedError(400, "parameter 'service' is required"))
+	err = getJSON(server.URL+"/api/flamegraph?service=frontend", nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+}
+
+func TestTailSpans(t *testing.T) {
+	bus := livetail.NewBus(10)
+	server, _, _, _ := initializeTestServerWithHandler(querysvc.QueryServiceOptions{LiveTail: bus})
+	defer server.Close()
+
+	resp, err := httpClient.Get(server.URL + "/api/tail?service=frontend&tag=http.method:GET")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	// the response headers are sent once subscribed
+	require.Equal(t, 1, bus.Subscribers())
+
+	span := &model.Span{
+		TraceID:       mockTraceID,
+		SpanID:        model.NewSpanID(1),
+		OperationName: "GET /",
+		Tags:          []model.KeyValue{model.String("http.method", "GET")},
+		Process:       model.NewProcess("frontend", nil),
+	}
+	bus.Publish(&model.Span{Process: model.NewProcess("frontend", nil)})
+	bus.Publish(span)
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(line, "data: "), line)
+	var trace ui.Trace
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &trace))
+	assert.Equal(t, ui.TraceID(mockTraceID.String()), trace.TraceID)
+	require.Len(t, trace.Spans, 1)
+	assert.Equal(t, ui.SpanID("0000000000000001"), trace.Spans[0].SpanID)
+	assert.Equal(t, "frontend", trace.Processes[trace.Spans[0].ProcessID].ServiceName)
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "\n", line)
+}
+
+func TestTailSpansFailures(t *testing.T) {
+	server, _, _ := initializeTestServer()
+	defer server.Close()
+	err := getJSON(server.URL+"/api/tail?service=frontend", nil)
+	assert.EqualError(t, err, parsedError(501, "live tail is not configured"))
+
+	server, _, _, _ = initializeTestServerWithHandler(querysvc.QueryServiceOptions{LiveTail: livetail.NewBus(10)})
+	defer server.Close()
+	err = getJSON(server.URL+"/api/tail?tag=http.method", nil)
+	assert.EqualError(t, err, parsedError(400, "malformed 'tag' parameter, expecting key:value, received: http.method"))
+}
+
+func TestGetServiceCatalog(t *testing.T) {
+	store := memory.NewStore()
+	require.NoError(t, store.WriteSpan(&model.Span{
+		OperationName: "GET /",
+		Process: model.NewProcess("frontend", []model.KeyValue{
+			model.String("hostname", "host-1"),
+			model.String("jaeger.version", "Go-2.22.1"),
+		}),
+	}))
+	server, _, _, _ := initializeTestServerWithHandler(querysvc.QueryServiceOptions{Catalog: store})
+	defer server.Close()
+
+	var response struct {
+		Data  []serviceActivity `json:"data"`
+		Total int               `json:"total"`
+	}
+	err := getJSON(server.URL+"/api/services/catalog", &response)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Total)
+	require.Len(t, response.Data, 1)
+	service := response.Data[0]
+	assert.Equal(t, "frontend", service.ServiceName)
+	assert.NotZero(t, service.LastSeen)
+	assert.Equal(t, 1.0/60, service.SpanRates.OneMinute)
+	assert.Equal(t, 1, service.OperationCount)
+	assert.Equal(t, []string{"Go-2.22.1"}, service.ClientVersions)
+	assert.Equal(t, []string{"host-1"}, service.Hostnames)
+	assert.Equal(t, []string{}, service.ClientUUIDs)
+}
+
+func TestGetServiceCatalogFailures(t *testing.T) {
+	server, _, _ := initializeTestServer()
+	defer server.Close()
+	err := getJSON(server.URL+"/api/services/catalog", nil)
+	assert.EqualError(t, err, parsedError(501, "service catalog is not configured"))
+
+	reader := &spanstoremocks.CatalogReader{}
+	reader.On("GetServiceCatalog", mock.Anything).Return(nil, errStorage)
+	server, _, _, _ = initializeTestServerWithHandler(querysvc.QueryServiceOptions{Catalog: reader})
+	defer server.Close()
+	err = getJSON(server.URL+"/api/services/catalog", nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+}
+
+type failingSpanMetricsReader struct {
+	err error
+}
+
+func (r failingSpanMetricsReader) GetSpanMetrics(ctx context.Context, query spanmetrics.Query) ([]spanmetrics.Series, error) {
+	return nil, r.err
+}
+
+func TestGetSpanMetrics(t *testing.T) {
+	aggregator := spanmetrics.NewAggregator(metrics.NullFactory, spanmetrics.Options{})
+	for i := 0; i < 100; i++ {
+		span := &model.Span{
+			OperationName: "GET /",
+			Duration:      time.Duration(i+1) * time.Millisecond,
+			Process:       model.NewProcess("frontend", nil),
+		}
+		if i%4 == 0 {
+			span.Tags = []model.KeyValue{model.Bool("error", true)}
+		}
+		aggregator.ProcessSpan(span)
+	}
+	aggregator.ProcessSpan(&model.Span{OperationName: "GET /", Process: model.NewProcess("backend", nil)})
+	server, _, _, _ := initializeTestServerWithHandler(querysvc.QueryServiceOptions{SpanMetrics: aggregator})
+	defer server.Close()
+
+	var response struct {
+		Data  []spanMetrics `json:"data"`
+		Total int           `json:"total"`
+	}
+	err := getJSON(server.URL+"/api/services/frontend/metrics?lookback=60000", &response)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Total)
+	require.Len(t, response.Data, 1)
+	m := response.Data[0]
+	assert.Equal(t, "GET /", m.OperationName)
+	assert.EqualValues(t, 100, m.Calls)
+	assert.EqualValues(t, 25, m.Errors)
+	assert.Equal(t, 100.0/60, m.RequestRate)
+	assert.Equal(t, 0.25, m.ErrorRate)
+	assert.EqualValues(t, 50000, m.P50)
+	assert.EqualValues(t, 100000, m.P95)
+	assert.EqualValues(t, 100000, m.P99)
+	require.Len(t, m.Latency, len(spanmetrics.DefaultLatencyBuckets)+1)
+	assert.Equal(t, latencyBucket{LessOrEqual: 2000, Count: 2}, m.Latency[0])
+	assert.Equal(t, latencyBucket{Count: 0}, m.Latency[len(m.Latency)-1])
+}
+
+func TestGetSpanMetricsFailures(t *testing.T) {
+	server, _, _ := initializeTestServer()
+	defer server.Close()
+	err := getJSON(server.URL+"/api/services/frontend/metrics", nil)
+	assert.EqualError(t, err, parsedError(501, "span metrics are not configured"))
+
+	reader := failingSpanMetricsReader{err: errStorage}
+	server, _, _, _ = initializeTestServerWithHandler(querysvc.QueryServiceOptions{SpanMetrics: reader})
+	defer server.Close()
+	err = getJSON(server.URL+"/api/services/frontend/metrics?lookback=x", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400 error from server")
+	assert.Contains(t, err.Error(), "unable to parse lookback")
+	err = getJSON(server.URL+"/api/services/frontend/metrics", nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+}
+
+func regressionTrace(traceID uint64, duration time.Duration, tags ...model.KeyValue) *model.Trace {
+	return &model.Trace{Spans: []*model.Span{{
+		TraceID:       model.NewTraceID(0, traceID),
+		SpanID:        model.NewSpanID(1),
+		OperationName: "GET /",
+		Duration:      duration,
+		Tags:          tags,
+		Process:       model.NewProcess("frontend", nil),
+	}}}
+}
+
+func TestRegressions(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	var beforeTraces, afterTraces []*model.Trace
+	for i := 0; i < 10; i++ {
+		beforeTraces = append(beforeTraces, regressionTrace(uint64(i+1), 10*time.Millisecond))
+		afterTraces = append(afterTraces, regressionTrace(uint64(i+11), 30*time.Millisecond))
+	}
+	readMock.On("FindTraces", mock.Anything, mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+		return q.StartTimeMin.Equal(time.Unix(1000, 0)) && q.NumTraces == 50 && q.ServiceName == "frontend"
+	})).Return(beforeTraces, nil).Once()
+	readMock.On("FindTraces", mock.Anything, mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+		return q.StartTimeMin.Equal(time.Unix(3000, 0))
+	})).Return(afterTraces, nil).Once()
+
+	var response struct {
+		Data  regressionResponse `json:"data"`
+		Total int                `json:"total"`
+	}
+	err := getJSON(server.URL+"/api/regressions?service=frontend&limit=50"+
+		"&beforeStart=1000000000&beforeEnd=2000000000&afterStart=3000000000&afterEnd=4000000000", &response)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Total)
+	assert.Equal(t, regressionWindow{Start: 1000000000, End: 2000000000, Source: "traces", Traces: 10}, response.Data.Before)
+	assert.Equal(t, "traces", response.Data.After.Source)
+	require.Len(t, response.Data.Operations, 1)
+	op := response.Data.Operations[0]
+	assert.Equal(t, "GET /", op.OperationName)
+	require.Len(t, op.Changes, 3)
+	assert.Equal(t, regressionChange{Metric: "p50", Before: 10000, After: 30000, Score: op.Score}, op.Changes[0])
+	assert.EqualValues(t, 10000, op.Before.P95)
+	assert.EqualValues(t, 30000, op.After.P99)
+	assert.Len(t, op.Before.TraceIDs, 3)
+	assert.Len(t, op.After.TraceIDs, 3)
+}
+
+func TestRegressionsFromSpanMetrics(t *testing.T) {
+	aggregator := spanmetrics.NewAggregator(metrics.NullFactory, spanmetrics.Options{})
+	for i := 0; i < 10; i++ {
+		aggregator.ProcessSpan(regressionTrace(uint64(i+1), time.Millisecond, model.Bool("error", true)).Spans[0])
+	}
+	server, readMock, _, _ := initializeTestServerWithHandler(querysvc.QueryServiceOptions{SpanMetrics: aggregator})
+	defer server.Close()
+	readMock.On("FindTraces", mock.Anything, mock.Anything).Return([]*model.Trace{regressionTrace(1, time.Millisecond)}, nil)
+
+	now := time.Now()
+	query := func(beforeStart, beforeEnd, afterStart, afterEnd time.Duration) regressionResponse {
+		var response struct {
+			Data regressionResponse `json:"data"`
+		}
+		err := getJSON(fmt.Sprintf("%s/api/regressions?service=frontend&beforeStart=%d&beforeEnd=%d&afterStart=%d&afterEnd=%d", server.URL,
+			model.TimeAsEpochMicroseconds(now.Add(beforeStart)), model.TimeAsEpochMicroseconds(now.Add(beforeEnd)),
+			model.TimeAsEpochMicroseconds(now.Add(afterStart)), model.TimeAsEpochMicroseconds(now.Add(afterEnd))), &response)
+		require.NoError(t, err)
+		return response.Data
+	}
+	// the span metrics no longer cover the before window, so both windows are computed from traces
+	response := query(-2*time.Hour, -time.Hour, -5*time.Minute, time.Minute)
+	assert.Equal(t, "traces", response.Before.Source)
+	assert.Equal(t, "traces", response.After.Source)
+
+	response = query(-10*time.Minute, -5*time.Minute, -5*time.Minute, time.Minute)
+	assert.Equal(t, "spanMetrics", response.Before.Source)
+	assert.Equal(t, "spanMetrics", response.After.Source)
+	require.Len(t, response.Operations, 0)
+}
+
+func TestRegressionsFailures(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	windows := "&beforeStart=1000&beforeEnd=2000&afterStart=3000&afterEnd=4000"
+
+	err := getJSON(server.URL+"/api/regressions?"+windows[1:], nil)
+	assert.EqualError(t, err, parsedError(400, "parameter 'service' is required"))
+	err = getJSON(server.URL+"/api/regressions?service=frontend&beforeStart=1000", nil)
+	assert.EqualError(t, err, parsedError(400, "parameter 'beforeEnd' is required"))
+	err = getJSON(server.URL+"/api/regressions?service=frontend&beforeStart=1000&beforeEnd=2000&afterStart=x&afterEnd=1", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to parse afterStart")
+	err = getJSON(server.URL+"/api/regressions?service=frontend&beforeStart=2000&beforeEnd=1000", nil)
+	assert.EqualError(t, err, parsedError(400, "'beforeEnd' should be greater than 'beforeStart'"))
+	err = getJSON(server.URL+"/api/regressions?service=frontend&limit=0"+windows, nil)
+	assert.EqualError(t, err, parsedError(400, "malformed 'limit' parameter, expecting a positive integer up to 1000: 0"))
+	err = getJSON(server.URL+"/api/regressions?service=frontend&limit=1001"+windows, nil)
+	assert.EqualError(t, err, parsedError(400, "malformed 'limit' parameter, expecting a positive integer up to 1000: 1001"))
+
+	readMock.On("FindTraces", mock.Anything, mock.Anything).Return(nil, errStorage).Once()
+	err = getJSON(server.URL+"/api/regressions?service=frontend"+windows, nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+}
+
+func TestQuality(t *testing.T) {
+	server, readMock, _ := initializeTestServer(HandlerOptions.MinClientVersions(map[string]string{"Go": "2.0.0"}))
+	defer server.Close()
+	process := &model.Process{
+		ServiceName: "frontend",
+		Tags:        []model.KeyValue{model.String("jaeger.version", "Go-1.0.0")},
+	}
+	trace := &model.Trace{Spans: []*model.Span{
+		{TraceID: mockTraceID, SpanID: model.NewSpanID(1), Process: process},
+		{TraceID: mockTraceID, SpanID: model.NewSpanID(2), Process: &model.Process{ServiceName: "backend"}},
+	}}
+	readMock.On("FindTraces", mock.Anything, mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{trace}, nil).Once()
+
+	var response struct {
+		Data   []serviceQuality  `json:"data"`
+		Total  int               `json:"total"`
+		Errors []structuredError `json:"errors"`
+	}
+	err := getJSON(server.URL+"/api/quality?service=frontend&lookback=1h", &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, 2, response.Total)
+	assert.Equal(t, []serviceQuality{
+		{
+			ServiceName:     "backend",
+			Score:           0,
+			Traces:          1,
+			Spans:           1,
+			SpansWithIssues: 1,
+			Issues: []qualityIssue{
+				{Issue: analysis.IssueMissingClientVersion, Spans: 1, TraceIDs: []ui.TraceID{ui.TraceID(mockTraceID.String())}},
+			},
+		},
+		{
+			ServiceName:     "frontend",
+			Score:           0,
+			Traces:          1,
+			Spans:           1,
+			SpansWithIssues: 1,
+			Issues: []qualityIssue{
+				{Issue: analysis.IssueOutdatedClient, Spans: 1, TraceIDs: []ui.TraceID{ui.TraceID(mockTraceID.String())}},
+			},
+		},
+	}, response.Data)
+}
+
+func TestQualityFailures(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+
+	err := getJSON(server.URL+"/api/quality", nil)
+	assert.EqualError(t, err, parsedError(400, "parameter 'service' is required"))
+
+	readMock.On("FindTraces", mock.Anything, mock.Anything).Return(nil, errStorage).Once()
+	err = getJSON(server.URL+"/api/quality?service=frontend", nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+}
+
+func TestImportTraces(t *testing.T) {
+	writer := &spanstoremocks.Writer{}
+	writer.On("WriteSpan", mock.AnythingOfType("*model.Span")).Return(nil).Twice()
+	server, _, _, _ := initializeTestServerWithOptions(querysvc.QueryServiceOptions{
+		UploadStore:      memory.NewStore(),
+		ImportSpanWriter: writer,
+	})
+	defer server.Close()
+
+	var response structuredResponse
+	err := postJSON(server.URL+"/api/import", uiconv.FromDomain(mockTrace), &response)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"000000000001e240"}, response.Data)
+	assert.Equal(t, 1, response.Total)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "trace.json")
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(part).Encode(uiconv.FromDomain(mockTrace)))
+	require.NoError(t, form.Close())
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/import?format=ui&target=storage", &body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	err = execJSON(req, &response)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"000000000001e240"}, response.Data)
+	writer.AssertExpectations(t)
+}
+
+func TestImportTracesFailures(t *testing.T) {
+	server, _, _ := initializeTestServer()
+	defer server.Close()
+
+	err := postJSON(server.URL+"/api/import?format=xml", []string{}, nil)
+	assert.EqualError(t, err, parsedError(400, "unsupported import format 'xml', expecting one of [ui jaeger zipkin thrift]"))
+	err = postJSON(server.URL+"/api/import", map[string]string{"foo": "bar"}, nil)
+	assert.EqualError(t, err, parsedError(400, "cannot detect the format of the trace file"))
+	err = postJSON(server.URL+"/api/import", []string{}, nil)
+	assert.EqualError(t, err, parsedError(403, "the upload store was not configured"))
+	err = postJSON(server.URL+"/api/import?target=storage", []string{}, nil)
+	assert.EqualError(t, err, parsedError(403, "importing spans into the span storage is not enabled"))
+}
+
 func TestSearchSuccess(t *testing.T) {
 	server, readMock, _ := initializeTestServer()
 	defer server.Close()
@@ -339,6 +1296,22 @@ func TestSearchSuccess(t *testing.T) {
 	assert.Len(t, response.Errors, 0)
 }
 
+func TestSearchNextPageToken(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{mockTrace}, nil).Once()
+
+	var response structuredResponse
+	err := getJSON(server.URL+`/api/traces?service=service&start=0&end=0&limit=1`, &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Errors, 0)
+	pageToken, err := spanstore.ParsePageToken(response.NextPageToken)
+	require.NoError(t, err)
+	require.NotNil(t, pageToken)
+	assert.Equal(t, mockTraceID, pageToken.TraceID)
+}
+
 func TestSearchByTraceIDSuccess(t *testing.T) {
 	server, readMock, _ := initializeTestServer()
 	defer server.Close()
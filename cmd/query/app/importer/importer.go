@@ -0,0 +1,233 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/gogo/protobuf/jsonpb"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/zipkin"
+	"github.com/jaegertracing/jaeger/model"
+	uiconv "github.com/jaegertracing/jaeger/model/converter/json"
+	jConv "github.com/jaegertracing/jaeger/model/converter/thrift/jaeger"
+	zipkinConv "github.com/jaegertracing/jaeger/model/converter/thrift/zipkin"
+	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+)
+
+// Format is a file format that traces can be imported from.
+type Format string
+
+const (
+	// FormatAuto detects the format of each JSON document in a file, or reads a Thrift batch.
+	FormatAuto Format = ""
+	// FormatUI is the JSON format of the UI (model/json): a trace, a list of traces,
+	// or a response of the query service API with the traces under "data".
+	FormatUI Format = "ui"
+	// FormatJaeger is the JSON encoding of the api_v2 SpansResponseChunk proto,
+	// optionally under "result" as streamed by the gRPC gateway.
+	FormatJaeger Format = "jaeger"
+	// FormatZipkin is the Zipkin v2 JSON list of spans.
+	FormatZipkin Format = "zipkin"
+	// FormatThrift is a Jaeger Thrift Batch in the binary protocol, as submitted to the collector.
+	FormatThrift Format = "thrift"
+)
+
+// Formats returns the supported formats.
+func Formats() []Format {
+	return []Format{FormatUI, FormatJaeger, FormatZipkin, FormatThrift}
+}
+
+// ParseFormat returns the format with the given name, or FormatAuto for an empty name.
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(name))
+	if format == FormatAuto {
+		return format, nil
+	}
+	for _, f := range Formats() {
+		if format == f {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported import format '%s', expecting one of %v", name, Formats())
+}
+
+var errUnknownFormat = errors.New("cannot detect the format of the trace file")
+
+// Parse converts the spans of a trace file into the domain model. A JSON file may hold
+// a sequence of JSON documents, e.g. the chunks streamed by the gRPC gateway.
+func Parse(data []byte, format Format) ([]*model.Span, error) {
+	if format == FormatThrift || (format == FormatAuto && !isJSON(data)) {
+		return parseThrift(data)
+	}
+	var spans []*model.Span
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	for {
+		var document json.RawMessage
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			return spans, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		documentFormat := format
+		if documentFormat == FormatAuto {
+			if documentFormat, err = detectFormat(document); err != nil {
+				return nil, err
+			}
+		}
+		documentSpans, err := parseJSON(document, documentFormat)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, documentSpans...)
+	}
+}
+
+func isJSON(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// detectFormat tells the JSON formats apart by their top level keys and the keys of their spans.
+func detectFormat(document json.RawMessage) (Format, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(document, &object); err == nil {
+		if _, ok := object["data"]; ok {
+			return FormatUI, nil
+		}
+		if _, ok := object["result"]; ok {
+			return FormatJaeger, nil
+		}
+		var spans []map[string]json.RawMessage
+		if err := json.Unmarshal(object["spans"], &spans); err == nil {
+			if len(spans) > 0 {
+				if _, ok := spans[0]["spanID"]; ok {
+					return FormatUI, nil
+				}
+			}
+			return FormatJaeger, nil
+		}
+		return "", errUnknownFormat
+	}
+	var list []map[string]json.RawMessage
+	if err := json.Unmarshal(document, &list); err == nil {
+		if len(list) == 0 {
+			return FormatUI, nil
+		}
+		if _, ok := list[0]["spans"]; ok {
+			return FormatUI, nil
+		}
+		if _, ok := list[0]["traceId"]; ok {
+			return FormatZipkin, nil
+		}
+	}
+	return "", errUnknownFormat
+}
+
+func parseJSON(document json.RawMessage, format Format) ([]*model.Span, error) {
+	switch format {
+	case FormatUI:
+		return parseUI(document)
+	case FormatJaeger:
+		return parseJaeger(document)
+	case FormatZipkin:
+		return parseZipkin(document)
+	}
+	return nil, fmt.Errorf("format '%s' is not a JSON format", format)
+}
+
+func parseUI(document json.RawMessage) ([]*model.Span, error) {
+	var traces []*ui.Trace
+	var object map[string]json.RawMessage
+	if err := unmarshalJSON(document, &object); err == nil {
+		if data, ok := object["data"]; ok {
+			document = data
+		} else {
+			document = append(append([]byte{'['}, document...), ']')
+		}
+	}
+	if err := unmarshalJSON(document, &traces); err != nil {
+		return nil, err
+	}
+	var spans []*model.Span
+	for _, trace := range traces {
+		traceSpans, err := uiconv.ToDomain(trace)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, traceSpans...)
+	}
+	return spans, nil
+}
+
+func parseJaeger(document json.RawMessage) ([]*model.Span, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(document, &object); err != nil {
+		return nil, err
+	}
+	if result, ok := object["result"]; ok {
+		document = result
+	}
+	var chunk api_v2.SpansResponseChunk
+	if err := jsonpb.Unmarshal(bytes.NewReader(document), &chunk); err != nil {
+		return nil, err
+	}
+	spans := make([]*model.Span, 0, len(chunk.Spans))
+	for i := range chunk.Spans {
+		spans = append(spans, &chunk.Spans[i])
+	}
+	return spans, nil
+}
+
+func parseZipkin(document json.RawMessage) ([]*model.Span, error) {
+	zSpans, err := zipkin.DeserializeJSONV2(document)
+	if err != nil {
+		return nil, err
+	}
+	var spans []*model.Span
+	for _, zSpan := range zSpans {
+		converted, err := zipkinConv.ToDomainSpan(zSpan)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, converted...)
+	}
+	return spans, nil
+}
+
+func parseThrift(data []byte) ([]*model.Span, error) {
+	batch := &jaeger.Batch{}
+	if err := thrift.NewTDeserializer().Read(batch, data); err != nil {
+		return nil, err
+	}
+	return jConv.ToDomain(batch.Spans, batch.Process), nil
+}
+
+func unmarshalJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
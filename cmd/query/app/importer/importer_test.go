@@ -0,0 +1,170 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/export"
+	"github.com/jaegertracing/jaeger/model"
+	uiconv "github.com/jaegertracing/jaeger/model/converter/json"
+	jConv "github.com/jaegertracing/jaeger/model/converter/thrift/jaeger"
+	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+)
+
+var (
+	testTraceID   = model.NewTraceID(0, 0x42)
+	testStartTime = time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+)
+
+func testTrace() *model.Trace {
+	process := &model.Process{ServiceName: "frontend"}
+	return &model.Trace{Spans: []*model.Span{
+		{
+			TraceID:       testTraceID,
+			SpanID:        model.NewSpanID(1),
+			OperationName: "GET /",
+			StartTime:     testStartTime,
+			Duration:      time.Second,
+			Tags:          model.KeyValues{model.String("span.kind", "server")},
+			Process:       process,
+		},
+		{
+			TraceID:       testTraceID,
+			SpanID:        model.NewSpanID(2),
+			OperationName: "SELECT",
+			References:    []model.SpanRef{model.NewChildOfRef(testTraceID, model.NewSpanID(1))},
+			StartTime:     testStartTime.Add(time.Millisecond),
+			Duration:      time.Millisecond,
+			Tags:          model.KeyValues{model.String("span.kind", "client")},
+			Process:       process,
+		},
+	}}
+}
+
+func exported(t *testing.T, format export.Format) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, export.Export(&buf, testTrace(), format))
+	return buf.Bytes()
+}
+
+func uiJSON(t *testing.T, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
+func thriftBatch(t *testing.T) []byte {
+	data, err := thrift.NewTSerializer().Write(&jaeger.Batch{
+		Process: &jaeger.Process{ServiceName: "frontend"},
+		Spans:   jConv.FromDomain(testTrace().Spans),
+	})
+	require.NoError(t, err)
+	return data
+}
+
+func TestParse(t *testing.T) {
+	uiTrace := uiconv.FromDomain(testTrace())
+	testCases := []struct {
+		name   string
+		data   []byte
+		format Format
+	}{
+		{name: "ui trace", data: uiJSON(t, uiTrace)},
+		{name: "ui trace list", data: uiJSON(t, []*ui.Trace{uiTrace})},
+		{name: "ui response", data: uiJSON(t, map[string]interface{}{"data": []*ui.Trace{uiTrace}})},
+		{name: "ui explicit", data: uiJSON(t, uiTrace), format: FormatUI},
+		{name: "jaeger", data: exported(t, export.FormatJaeger)},
+		{name: "jaeger result", data: []byte(`{"result":` + string(exported(t, export.FormatJaeger)) + `}`)},
+		{name: "jaeger explicit", data: exported(t, export.FormatJaeger), format: FormatJaeger},
+		{name: "zipkin", data: exported(t, export.FormatZipkin)},
+		{name: "zipkin explicit", data: exported(t, export.FormatZipkin), format: FormatZipkin},
+		{name: "thrift", data: thriftBatch(t)},
+		{name: "thrift explicit", data: thriftBatch(t), format: FormatThrift},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spans, err := Parse(tc.data, tc.format)
+			require.NoError(t, err)
+			require.Len(t, spans, 2)
+			for _, span := range spans {
+				assert.Equal(t, testTraceID, span.TraceID)
+				assert.Equal(t, "frontend", span.Process.ServiceName)
+			}
+			assert.Equal(t, "GET /", spans[0].OperationName)
+			assert.Equal(t, testStartTime, spans[0].StartTime.UTC())
+			assert.Equal(t, time.Second, spans[0].Duration)
+			assert.Equal(t, model.NewSpanID(1), spans[1].ParentSpanID())
+		})
+	}
+}
+
+func TestParseStream(t *testing.T) {
+	chunk := exported(t, export.FormatJaeger)
+	data := []byte(`{"result":` + string(chunk) + "}\n" + `{"result":` + string(chunk) + "}\n")
+	spans, err := Parse(data, FormatAuto)
+	require.NoError(t, err)
+	assert.Len(t, spans, 4)
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		data   string
+		format Format
+		err    string
+	}{
+		{name: "unknown object", data: `{"foo":"bar"}`, err: errUnknownFormat.Error()},
+		{name: "unknown list", data: `[{"foo":"bar"}]`, err: errUnknownFormat.Error()},
+		{name: "invalid json", data: `{"data":`, err: "unexpected EOF"},
+		{name: "invalid ui", data: `{"data":{}}`, format: FormatUI},
+		{name: "invalid jaeger", data: `{"spans":{}}`, format: FormatJaeger},
+		{name: "jaeger list", data: `[]`, format: FormatJaeger},
+		{name: "invalid zipkin", data: `[{"traceId":"x"}]`, format: FormatZipkin},
+		{name: "invalid thrift", data: `not thrift`},
+		{name: "unknown ui process", data: `{"traceID":"1","spans":[{"traceID":"1","spanID":"1","processID":"p1"}]}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.data), tc.format)
+			require.Error(t, err)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range append(Formats(), FormatAuto) {
+		parsed, err := ParseFormat(string(format))
+		require.NoError(t, err)
+		assert.Equal(t, format, parsed)
+	}
+	parsed, err := ParseFormat("Zipkin")
+	require.NoError(t, err)
+	assert.Equal(t, FormatZipkin, parsed)
+
+	_, err = ParseFormat("otlp")
+	assert.EqualError(t, err, "unsupported import format 'otlp', expecting one of [ui jaeger zipkin thrift]")
+}
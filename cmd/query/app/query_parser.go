@@ -41,11 +41,39 @@ const (
 	spanKindParam    = "spanKind"
 	endTimeParam     = "end"
 	prettyPrintParam = "prettyPrint"
+	pageTokenParam   = "pageToken"
+	tagFilterParam   = "tagFilter"
+
+	minSpansParam      = "minSpans"
+	maxSpansParam      = "maxSpans"
+	minDepthParam      = "minDepth"
+	maxDepthParam      = "maxDepth"
+	minErrorsParam     = "minErrors"
+	maxErrorsParam     = "maxErrors"
+	traceServiceParam  = "traceService"
+	rootTagFilterParam = "rootTagFilter"
+
+	tagFilterOrSeparator = "||"
 )
 
 var (
 	errMaxDurationGreaterThanMin = fmt.Errorf("'%s' should be greater than '%s'", maxDurationParam, minDurationParam)
 
+	// tagFilterOperators are matched in order, so that two-character operators take precedence
+	tagFilterOperators = []struct {
+		token string
+		op    spanstore.TagOperator
+	}{
+		{token: "!=", op: spanstore.TagOpNotEqual},
+		{token: ">=", op: spanstore.TagOpGreaterOrEqual},
+		{token: "<=", op: spanstore.TagOpLessOrEqual},
+		{token: "=~", op: spanstore.TagOpRegex},
+		{token: "^=", op: spanstore.TagOpPrefix},
+		{token: "=", op: spanstore.TagOpEqual},
+		{token: ">", op: spanstore.TagOpGreater},
+		{token: "<", op: spanstore.TagOpLess},
+	}
+
 	// ErrServiceParameterRequired occurs when no service name is defined
 	ErrServiceParameterRequired = fmt.Errorf("parameter '%s' is required", serviceParam)
 )
@@ -64,9 +92,10 @@ type traceQueryParameters struct {
 // parse takes a request and constructs a model of parameters
 // Trace query syntax:
 //     query ::= param | param '&' query
-//     param ::= service | operation | limit | start | end | minDuration | maxDuration | tag | tags
-//     service ::= 'service=' strValue
-//     operation ::= 'operation=' strValue
+//     param ::= service | operation | limit | start | end | minDuration | maxDuration | tag | tags | tagFilter |
+//               minSpans | maxSpans | minDepth | maxDepth | minErrors | maxErrors | traceService | rootTagFilter | pageToken
+//     service ::= 'service=' strValue (repeated service parameters match any of the services)
+//     operation ::= 'operation=' strValue (repeated operation parameters match any of the operations)
 //     limit ::= 'limit=' intValue
 //     start ::= 'start=' intValue in unix microseconds
 //     end ::= 'end=' intValue in unix microseconds
@@ -76,9 +105,23 @@ type traceQueryParameters struct {
 //     key := strValue
 //     keyValue := strValue ':' strValue
 //     tags :== 'tags=' jsonMap
+//     tagFilter ::= 'tagFilter=' predicates (all tagFilter parameters must be satisfied by the same span)
+//     predicates ::= predicate | predicate '||' predicates (any of the predicates must be satisfied)
+//     predicate ::= key (tag exists) | '!' key (tag does not exist) | key operator strValue
+//     operator ::= '=' | '!=' | '>' | '>=' | '<' | '<=' (numeric) | '^=' (prefix) | '=~' (regular expression)
+//     minSpans ::= 'minSpans=' intValue (and likewise maxSpans, the number of spans in the trace)
+//     minDepth ::= 'minDepth=' intValue (and likewise maxDepth, the number of spans on the longest path from the root)
+//     minErrors ::= 'minErrors=' intValue (and likewise maxErrors, the number of spans with error=true)
+//     traceService ::= 'traceService=' strValue (the trace must involve all traceService parameters;
+//                      they make the service parameter optional)
+//     rootTagFilter ::= 'rootTagFilter=' predicates (all rootTagFilter parameters must be satisfied by the root span)
+//     pageToken ::= 'pageToken=' strValue (the opaque nextPageToken of the previous page)
 func (p *queryParser) parse(r *http.Request) (*traceQueryParameters, error) {
-	service := r.FormValue(serviceParam)
-	operation := r.FormValue(operationParam)
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	service, services := p.parseNames(r.Form[serviceParam])
+	operation, operations := p.parseNames(r.Form[operationParam])
 
 	startTime, err := p.parseTime(startTimeParam, r)
 	if err != nil {
@@ -94,6 +137,16 @@ func (p *queryParser) parse(r *http.Request) (*traceQueryParameters, error) {
 		return nil, err
 	}
 
+	tagPredicates, err := p.parseTagFilters(tagFilterParam, r.Form[tagFilterParam])
+	if err != nil {
+		return nil, err
+	}
+
+	tracePredicates, err := p.parseTracePredicates(r)
+	if err != nil {
+		return nil, err
+	}
+
 	limitParam := r.FormValue(limitParam)
 	limit := defaultQueryLimit
 	if limitParam != "" {
@@ -125,14 +178,19 @@ func (p *queryParser) parse(r *http.Request) (*traceQueryParameters, error) {
 
 	traceQuery := &traceQueryParameters{
 		TraceQueryParameters: spanstore.TraceQueryParameters{
-			ServiceName:   service,
-			OperationName: operation,
-			StartTimeMin:  startTime,
-			StartTimeMax:  endTime,
-			Tags:          tags,
-			NumTraces:     limit,
-			DurationMin:   minDuration,
-			DurationMax:   maxDuration,
+			ServiceName:     service,
+			ServiceNames:    services,
+			OperationName:   operation,
+			OperationNames:  operations,
+			StartTimeMin:    startTime,
+			StartTimeMax:    endTime,
+			Tags:            tags,
+			TagPredicates:   tagPredicates,
+			NumTraces:       limit,
+			DurationMin:     minDuration,
+			DurationMax:     maxDuration,
+			TracePredicates: tracePredicates,
+			PageToken:       r.FormValue(pageTokenParam),
 		},
 		traceIDs: traceIDs,
 	}
@@ -143,6 +201,17 @@ func (p *queryParser) parse(r *http.Request) (*traceQueryParameters, error) {
 	return traceQuery, nil
 }
 
+// parseNames returns a single value as the name and several values as the list of alternative names.
+func (p *queryParser) parseNames(values []string) (string, []string) {
+	if len(values) > 1 {
+		return "", values
+	}
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	return "", nil
+}
+
 func (p *queryParser) parseTime(param string, r *http.Request) (time.Time, error) {
 	value := r.FormValue(param)
 	if value == "" {
@@ -171,7 +240,8 @@ func (p *queryParser) parseDuration(durationParam string, r *http.Request) (time
 }
 
 func (p *queryParser) validateQuery(traceQuery *traceQueryParameters) error {
-	if len(traceQuery.traceIDs) == 0 && traceQuery.ServiceName == "" {
+	if len(traceQuery.traceIDs) == 0 && len(traceQuery.Services()) == 0 &&
+		(traceQuery.TracePredicates == nil || len(traceQuery.TracePredicates.Services) == 0) {
 		return ErrServiceParameterRequired
 	}
 	if traceQuery.DurationMin != 0 && traceQuery.DurationMax != 0 {
@@ -179,6 +249,9 @@ func (p *queryParser) validateQuery(traceQuery *traceQueryParameters) error {
 			return errMaxDurationGreaterThanMin
 		}
 	}
+	if _, err := spanstore.ParsePageToken(traceQuery.PageToken); err != nil {
+		return fmt.Errorf("malformed '%s' parameter: %w", pageTokenParam, err)
+	}
 	return nil
 }
 
@@ -203,3 +276,83 @@ func (p *queryParser) parseTags(simpleTags []string, jsonTags []string) (map[str
 	}
 	return retMe, nil
 }
+
+func (p *queryParser) parseTracePredicates(r *http.Request) (*spanstore.TracePredicates, error) {
+	predicates := &spanstore.TracePredicates{
+		Services: r.Form[traceServiceParam],
+	}
+	bounds := []struct {
+		minParam string
+		maxParam string
+		min      *int
+		max      **int
+	}{
+		{minParam: minSpansParam, maxParam: maxSpansParam, min: &predicates.MinSpanCount, max: &predicates.MaxSpanCount},
+		{minParam: minDepthParam, maxParam: maxDepthParam, min: &predicates.MinDepth, max: &predicates.MaxDepth},
+		{minParam: minErrorsParam, maxParam: maxErrorsParam, min: &predicates.MinErrorCount, max: &predicates.MaxErrorCount},
+	}
+	isSet := len(predicates.Services) > 0
+	for _, bound := range bounds {
+		for _, param := range []string{bound.minParam, bound.maxParam} {
+			input := r.FormValue(param)
+			if input == "" {
+				continue
+			}
+			parsed, err := strconv.Atoi(input)
+			if err != nil || parsed < 0 {
+				return nil, fmt.Errorf("malformed '%s' parameter, expecting a non-negative integer, received: %s", param, input)
+			}
+			if param == bound.minParam {
+				*bound.min = parsed
+			} else {
+				*bound.max = &parsed
+			}
+			isSet = true
+		}
+		if *bound.max != nil && **bound.max < *bound.min {
+			return nil, fmt.Errorf("'%s' should be greater than '%s'", bound.maxParam, bound.minParam)
+		}
+	}
+	rootTagPredicates, err := p.parseTagFilters(rootTagFilterParam, r.Form[rootTagFilterParam])
+	if err != nil {
+		return nil, err
+	}
+	if len(rootTagPredicates) > 0 {
+		predicates.RootTagPredicates = rootTagPredicates
+		isSet = true
+	}
+	if !isSet {
+		return nil, nil
+	}
+	return predicates, nil
+}
+
+func (p *queryParser) parseTagFilters(param string, filters []string) ([]spanstore.TagPredicateGroup, error) {
+	var groups []spanstore.TagPredicateGroup
+	for _, filter := range filters {
+		var group spanstore.TagPredicateGroup
+		for _, expr := range strings.Split(filter, tagFilterOrSeparator) {
+			predicate, err := parseTagPredicate(expr)
+			if err != nil {
+				return nil, fmt.Errorf("malformed '%s' parameter: %w", param, err)
+			}
+			group = append(group, predicate)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func parseTagPredicate(expr string) (spanstore.TagPredicate, error) {
+	for i := 0; i < len(expr); i++ {
+		for _, operator := range tagFilterOperators {
+			if strings.HasPrefix(expr[i:], operator.token) {
+				return spanstore.NewTagPredicate(expr[:i], operator.op, expr[i+len(operator.token):])
+			}
+		}
+	}
+	if strings.HasPrefix(expr, "!") {
+		return spanstore.NewTagPredicate(expr[1:], spanstore.TagOpNotExists, "")
+	}
+	return spanstore.NewTagPredicate(expr, spanstore.TagOpExists, "")
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/kr/pretty"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -57,6 +58,93 @@ func TestParseTraceQuery(t *testing.T) {
 				},
 			},
 		},
+		{"x?service=service&start=0&end=0&limit=20&pageToken=MTA6MDAwMDAwMDAwMDAwMDAwMQ", noErr,
+			&traceQueryParameters{
+				TraceQueryParameters: spanstore.TraceQueryParameters{
+					ServiceName:  "service",
+					StartTimeMin: time.Unix(0, 0),
+					StartTimeMax: time.Unix(0, 0),
+					NumTraces:    20,
+					Tags:         make(map[string]string),
+					PageToken:    "MTA6MDAwMDAwMDAwMDAwMDAwMQ",
+				},
+			},
+		},
+		{"x?service=frontend&service=api&operation=GET&operation=POST&start=0&end=0&limit=20", noErr,
+			&traceQueryParameters{
+				TraceQueryParameters: spanstore.TraceQueryParameters{
+					ServiceNames:   []string{"frontend", "api"},
+					OperationNames: []string{"GET", "POST"},
+					StartTimeMin:   time.Unix(0, 0),
+					StartTimeMax:   time.Unix(0, 0),
+					NumTraces:      20,
+					Tags:           make(map[string]string),
+				},
+			},
+		},
+		{"x?service=service&pageToken=!", "malformed 'pageToken' parameter: malformed page token", nil},
+		{"x?service=service&start=0&end=0&limit=20&tagFilter=http.status_code>=500||error&tagFilter=!sampler.type&tagFilter=http.url=~^/api&tagFilter=peer.service^=redis&tagFilter=span.kind!=client", noErr,
+			&traceQueryParameters{
+				TraceQueryParameters: spanstore.TraceQueryParameters{
+					ServiceName:  "service",
+					StartTimeMin: time.Unix(0, 0),
+					StartTimeMax: time.Unix(0, 0),
+					NumTraces:    20,
+					Tags:         make(map[string]string),
+					TagPredicates: []spanstore.TagPredicateGroup{
+						{
+							mustTagPredicate(t, "http.status_code", spanstore.TagOpGreaterOrEqual, "500"),
+							mustTagPredicate(t, "error", spanstore.TagOpExists, ""),
+						},
+						{mustTagPredicate(t, "sampler.type", spanstore.TagOpNotExists, "")},
+						{mustTagPredicate(t, "http.url", spanstore.TagOpRegex, "^/api")},
+						{mustTagPredicate(t, "peer.service", spanstore.TagOpPrefix, "redis")},
+						{mustTagPredicate(t, "span.kind", spanstore.TagOpNotEqual, "client")},
+					},
+				},
+			},
+		},
+		{"x?traceService=frontend&traceService=db&start=0&end=0&limit=20&minSpans=10&maxSpans=500&minDepth=3&maxErrors=2&minErrors=1&rootTagFilter=error=true", noErr,
+			&traceQueryParameters{
+				TraceQueryParameters: spanstore.TraceQueryParameters{
+					StartTimeMin: time.Unix(0, 0),
+					StartTimeMax: time.Unix(0, 0),
+					NumTraces:    20,
+					Tags:         make(map[string]string),
+					TracePredicates: &spanstore.TracePredicates{
+						MinSpanCount:  10,
+						MaxSpanCount:  intPtr(500),
+						MinDepth:      3,
+						MinErrorCount: 1,
+						MaxErrorCount: intPtr(2),
+						Services:      []string{"frontend", "db"},
+						RootTagPredicates: []spanstore.TagPredicateGroup{
+							{mustTagPredicate(t, "error", spanstore.TagOpEqual, "true")},
+						},
+					},
+				},
+			},
+		},
+		{"x?service=service&start=0&end=0&limit=20&maxErrors=0", noErr,
+			&traceQueryParameters{
+				TraceQueryParameters: spanstore.TraceQueryParameters{
+					ServiceName:     "service",
+					StartTimeMin:    time.Unix(0, 0),
+					StartTimeMax:    time.Unix(0, 0),
+					NumTraces:       20,
+					Tags:            make(map[string]string),
+					TracePredicates: &spanstore.TracePredicates{MaxErrorCount: intPtr(0)},
+				},
+			},
+		},
+		{"x?service=service&minErrors=1&maxErrors=0", "'maxErrors' should be greater than 'minErrors'", nil},
+		{"x?service=service&minSpans=ten", "malformed 'minSpans' parameter, expecting a non-negative integer, received: ten", nil},
+		{"x?service=service&maxDepth=-1", "malformed 'maxDepth' parameter, expecting a non-negative integer, received: -1", nil},
+		{"x?service=service&minErrors=3&maxErrors=2", "'maxErrors' should be greater than 'minErrors'", nil},
+		{"x?service=service&rootTagFilter=!", "malformed 'rootTagFilter' parameter: tag predicate key cannot be empty", nil},
+		{"x?minSpans=10", "parameter 'service' is required", nil},
+		{"x?service=service&tagFilter=http.status_code>abc", `malformed 'tagFilter' parameter: tag predicate http.status_code>abc requires a numeric value: strconv.ParseFloat: parsing "abc": invalid syntax`, nil},
+		{"x?service=service&tagFilter=k:v||=v", "malformed 'tagFilter' parameter: tag predicate key cannot be empty", nil},
 		// tags=JSON with a non-string value 123
 		{`x?service=service&start=0&end=0&operation=operation&limit=200&tag=k:v&tags={"x":123}`, "malformed 'tags' parameter, cannot unmarshal JSON: json: cannot unmarshal number into Go value of type string", nil},
 		// tags=JSON
@@ -166,3 +254,13 @@ func TestParseTraceQuery(t *testing.T) {
 		})
 	}
 }
+
+func mustTagPredicate(t *testing.T, key string, op spanstore.TagOperator, value string) spanstore.TagPredicate {
+	predicate, err := spanstore.NewTagPredicate(key, op, value)
+	require.NoError(t, err)
+	return predicate
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -16,9 +16,39 @@
 package querysvc
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/jaegertracing/jaeger/model/adjuster"
 )
 
+// The names of the standard adjusters, as used in the query.adjusters flag and the adjusters parameter of the query API.
+const (
+	SpanIDDeduperAdjuster  = "span-id-deduper"
+	ClockSkewAdjuster      = "clock-skew"
+	IPTagAdjuster          = "ip-tag"
+	SortLogFieldsAdjuster  = "sort-log-fields"
+	SpanReferencesAdjuster = "span-references"
+)
+
+// The names of the adjusters that are not among the standard adjusters.
+const (
+	// SyntheticRootAdjuster is the name of adjuster.SyntheticRoot.
+	SyntheticRootAdjuster = "synthetic-root"
+	// CollapseSiblingsAdjuster is the name of adjuster.CollapseSiblings with adjuster.DefaultMinSiblings.
+	CollapseSiblingsAdjuster = "collapse-siblings"
+)
+
+// StandardAdjusterNames are the names of StandardAdjusters, in the order they are applied.
+var StandardAdjusterNames = []string{
+	SpanIDDeduperAdjuster,
+	ClockSkewAdjuster,
+	IPTagAdjuster,
+	SortLogFieldsAdjuster,
+	SpanReferencesAdjuster,
+}
+
 // StandardAdjusters is a list of model adjusters applied by the query service
 // before returning the data to the API clients.
 var StandardAdjusters = []adjuster.Adjuster{
@@ -28,3 +58,45 @@ var StandardAdjusters = []adjuster.Adjuster{
 	adjuster.SortLogFields(),
 	adjuster.SpanReferences(),
 }
+
+// adjusters are the adjusters that can be selected by name.
+var adjusters = map[string]func() adjuster.Adjuster{
+	SpanIDDeduperAdjuster:  adjuster.SpanIDDeduper,
+	ClockSkewAdjuster:      adjuster.ClockSkew,
+	IPTagAdjuster:          adjuster.IPTagAdjuster,
+	SortLogFieldsAdjuster:  adjuster.SortLogFields,
+	SpanReferencesAdjuster: adjuster.SpanReferences,
+	SyntheticRootAdjuster:  adjuster.SyntheticRoot,
+	CollapseSiblingsAdjuster: func() adjuster.Adjuster {
+		return adjuster.CollapseSiblings(adjuster.DefaultMinSiblings)
+	},
+}
+
+// RegisterAdjuster makes the adjuster created by the factory selectable by name, replacing any adjuster
+// registered with the same name. It is not safe for concurrent use and is meant to be called from init.
+func RegisterAdjuster(name string, factory func() adjuster.Adjuster) {
+	adjusters[name] = factory
+}
+
+// AdjusterNames returns the sorted names of the registered adjusters.
+func AdjusterNames() []string {
+	names := make([]string, 0, len(adjusters))
+	for name := range adjusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AdjustersByName returns the registered adjusters with the given names, in the same order.
+func AdjustersByName(names []string) ([]adjuster.Adjuster, error) {
+	result := make([]adjuster.Adjuster, len(names))
+	for i, name := range names {
+		factory, ok := adjusters[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown adjuster '%s', expecting one of %s", name, strings.Join(AdjusterNames(), ", "))
+		}
+		result[i] = factory()
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querysvc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/adjuster"
+)
+
+func TestAdjustersByName(t *testing.T) {
+	standard, err := AdjustersByName(StandardAdjusterNames)
+	require.NoError(t, err)
+	assert.Len(t, standard, len(StandardAdjusters))
+
+	_, err = AdjustersByName([]string{ClockSkewAdjuster, "unknown"})
+	assert.EqualError(t, err, "unknown adjuster 'unknown', expecting one of clock-skew, collapse-siblings, ip-tag, sort-log-fields, span-id-deduper, span-references, synthetic-root")
+
+	none, err := AdjustersByName(nil)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestRegisterAdjuster(t *testing.T) {
+	defer delete(adjusters, "rename")
+	RegisterAdjuster("rename", func() adjuster.Adjuster {
+		return adjuster.Func(func(trace *model.Trace) (*model.Trace, error) {
+			for _, span := range trace.Spans {
+				span.OperationName = "renamed"
+			}
+			return trace, nil
+		})
+	})
+	assert.Contains(t, AdjusterNames(), "rename")
+
+	selected, err := AdjustersByName([]string{SortLogFieldsAdjuster, "rename"})
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	trace, err := adjuster.Sequence(selected...).Adjust(&model.Trace{Spans: []*model.Span{{OperationName: "op"}}})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", trace.Spans[0].OperationName)
+}
+
+func TestInitAdjusters(t *testing.T) {
+	opts := &QueryServiceOptions{}
+	assert.Error(t, opts.InitAdjusters([]string{"unknown"}))
+	assert.Nil(t, opts.Adjuster)
+
+	require.NoError(t, opts.InitAdjusters([]string{}))
+	qs := NewQueryService(nil, nil, *opts)
+	trace := &model.Trace{Spans: []*model.Span{
+		{SpanID: 1, References: []model.SpanRef{{}}},
+	}}
+	adjusted, err := qs.Adjust(trace)
+	require.NoError(t, err)
+	assert.Len(t, adjusted.Spans[0].References, 1)
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querysvc
+
+import (
+	"context"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// DefaultTraceLinkOptions are the default limits of GetLinkedTraces.
+var DefaultTraceLinkOptions = TraceLinkOptions{
+	MaxDepth:   3,
+	MaxSpans:   10000,
+	MaxFetches: 100,
+}
+
+// TraceLinkOptions limit how far GetLinkedTraces follows the references to other traces.
+type TraceLinkOptions struct {
+	// MaxDepth is the number of references followed from the requested trace to a linked trace.
+	MaxDepth int
+	// MaxSpans is the span budget of the linked traces: a trace that would exceed it is not loaded,
+	// and no reference is followed once it is used up.
+	MaxSpans int
+	// MaxFetches is the number of linked traces read from the span reader, whether they are loaded or not.
+	MaxFetches int
+}
+
+// TraceLink is a reference from a span to a span of another trace, e.g. a FOLLOWS_FROM reference
+// of a consumer span to the producer span of an asynchronous message.
+type TraceLink struct {
+	TraceID   model.TraceID
+	SpanID    model.SpanID
+	Reference model.SpanRef
+	// Loaded is true if the referenced trace is among the linked traces.
+	Loaded bool
+}
+
+// LinkedTraces are a trace and the traces reached by following the references of their spans to other traces.
+type LinkedTraces struct {
+	// Traces start with the requested trace, followed by the linked traces by increasing depth.
+	Traces []*model.Trace
+	// Links are the references of the spans of the traces to other traces.
+	Links []TraceLink
+	// Truncated is true if referenced traces were not loaded because of the depth, span or fetch limits.
+	Truncated bool
+}
+
+// GetLinkedTraces returns the trace and the traces referenced by its spans, and by theirs, within the limits.
+// Only the references from the loaded traces can be followed, not the references to them, which the span
+// reader cannot look up. Referenced traces that are not found are skipped.
+func (qs QueryService) GetLinkedTraces(ctx context.Context, traceID model.TraceID, opts TraceLinkOptions) (*LinkedTraces, error) {
+	trace, err := qs.GetTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+	result := &LinkedTraces{Traces: []*model.Trace{trace}}
+	loaded := map[model.TraceID]bool{traceID: true}
+	visited := map[model.TraceID]struct{}{traceID: {}}
+	spans := len(trace.Spans)
+	fetches := 0
+	level := []*model.Trace{trace}
+	for depth := 0; len(level) > 0; depth++ {
+		var next []*model.Trace
+		for _, t := range level {
+			for _, link := range traceLinks(t) {
+				target := link.Reference.TraceID
+				if _, ok := visited[target]; ok {
+					continue
+				}
+				if depth >= opts.MaxDepth || spans >= opts.MaxSpans || fetches >= opts.MaxFetches {
+					result.Truncated = true
+					continue
+				}
+				visited[target] = struct{}{}
+				fetches++
+				linked, err := qs.GetTrace(ctx, target)
+				if err == spanstore.ErrTraceNotFound {
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				if spans+len(linked.Spans) > opts.MaxSpans {
+					result.Truncated = true
+					continue
+				}
+				spans += len(linked.Spans)
+				loaded[target] = true
+				result.Traces = append(result.Traces, linked)
+				next = append(next, linked)
+			}
+		}
+		level = next
+	}
+	for _, t := range result.Traces {
+		for _, link := range traceLinks(t) {
+			link.Loaded = loaded[link.Reference.TraceID]
+			result.Links = append(result.Links, link)
+		}
+	}
+	return result, nil
+}
+
+// traceLinks returns the references of the spans of the trace to other traces.
+func traceLinks(trace *model.Trace) []TraceLink {
+	var links []TraceLink
+	for _, span := range trace.Spans {
+		for _, ref := range span.References {
+			if ref.TraceID == span.TraceID || ref.TraceID == (model.TraceID{}) {
+				continue
+			}
+			links = append(links, TraceLink{TraceID: span.TraceID, SpanID: span.SpanID, Reference: ref})
+		}
+	}
+	return links
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+var (
+	producerTraceID = model.NewTraceID(0, 1)
+	consumerTraceID = model.NewTraceID(0, 2)
+	workerTraceID   = model.NewTraceID(0, 3)
+	missingTraceID  = model.NewTraceID(0, 4)
+)
+
+// initializeLinkedTraces mocks a chain of asynchronous traces: the producer trace is followed by
+// the consumer trace, which is followed by the worker trace, which is followed by a missing trace.
+func initializeLinkedTraces() (*QueryService, *spanstoremocks.Reader) {
+	qs, readMock, _ := initializeTestService()
+	readMock.On("GetTrace", mock.Anything, producerTraceID).Return(&model.Trace{Spans: []*model.Span{
+		{TraceID: producerTraceID, SpanID: model.NewSpanID(1)},
+		{
+			TraceID:    producerTraceID,
+			SpanID:     model.NewSpanID(2),
+			References: []model.SpanRef{model.NewChildOfRef(producerTraceID, model.NewSpanID(1))},
+		},
+	}}, nil)
+	readMock.On("GetTrace", mock.Anything, consumerTraceID).Return(&model.Trace{Spans: []*model.Span{
+		{
+			TraceID:    consumerTraceID,
+			SpanID:     model.NewSpanID(10),
+			References: []model.SpanRef{model.NewFollowsFromRef(producerTraceID, model.NewSpanID(2))},
+		},
+	}}, nil)
+	readMock.On("GetTrace", mock.Anything, workerTraceID).Return(&model.Trace{Spans: []*model.Span{
+		{
+			TraceID: workerTraceID,
+			SpanID:  model.NewSpanID(20),
+			References: []model.SpanRef{
+				model.NewFollowsFromRef(consumerTraceID, model.NewSpanID(10)),
+				model.NewFollowsFromRef(missingTraceID, model.NewSpanID(30)),
+			},
+		},
+	}}, nil)
+	readMock.On("GetTrace", mock.Anything, missingTraceID).Return(nil, spanstore.ErrTraceNotFound)
+	return qs, readMock
+}
+
+func traceIDsOf(traces []*model.Trace) []model.TraceID {
+	traceIDs := make([]model.TraceID, len(traces))
+	for i, trace := range traces {
+		traceIDs[i] = trace.Spans[0].TraceID
+	}
+	return traceIDs
+}
+
+func TestGetLinkedTraces(t *testing.T) {
+	qs, _ := initializeLinkedTraces()
+
+	linked, err := qs.GetLinkedTraces(context.Background(), workerTraceID, TraceLinkOptions{MaxDepth: 3, MaxSpans: 10, MaxFetches: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []model.TraceID{workerTraceID, consumerTraceID, producerTraceID}, traceIDsOf(linked.Traces))
+	assert.False(t, linked.Truncated)
+	assert.Equal(t, []TraceLink{
+		{
+			TraceID:   workerTraceID,
+			SpanID:    model.NewSpanID(20),
+			Reference: model.NewFollowsFromRef(consumerTraceID, model.NewSpanID(10)),
+			Loaded:    true,
+		},
+		{
+			TraceID:   workerTraceID,
+			SpanID:    model.NewSpanID(20),
+			Reference: model.NewFollowsFromRef(missingTraceID, model.NewSpanID(30)),
+			Loaded:    false,
+		},
+		{
+			TraceID:   consumerTraceID,
+			SpanID:    model.NewSpanID(10),
+			Reference: model.NewFollowsFromRef(producerTraceID, model.NewSpanID(2)),
+			Loaded:    true,
+		},
+	}, linked.Links)
+}
+
+func TestGetLinkedTracesLimits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     TraceLinkOptions
+		expected []model.TraceID
+	}{
+		{
+			name:     "no depth",
+			opts:     TraceLinkOptions{MaxDepth: 0, MaxSpans: 10, MaxFetches: 10},
+			expected: []model.TraceID{workerTraceID},
+		},
+		{
+			name:     "depth",
+			opts:     TraceLinkOptions{MaxDepth: 1, MaxSpans: 10, MaxFetches: 10},
+			expected: []model.TraceID{workerTraceID, consumerTraceID},
+		},
+		{
+			name:     "span budget",
+			opts:     TraceLinkOptions{MaxDepth: 3, MaxSpans: 3, MaxFetches: 10},
+			expected: []model.TraceID{workerTraceID, consumerTraceID},
+		},
+		{
+			name:     "span budget used up",
+			opts:     TraceLinkOptions{MaxDepth: 3, MaxSpans: 1, MaxFetches: 10},
+			expected: []model.TraceID{workerTraceID},
+		},
+		{
+			name:     "fetches",
+			opts:     TraceLinkOptions{MaxDepth: 3, MaxSpans: 10, MaxFetches: 1},
+			expected: []model.TraceID{workerTraceID, consumerTraceID},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			qs, _ := initializeLinkedTraces()
+			linked, err := qs.GetLinkedTraces(context.Background(), workerTraceID, testCase.opts)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, traceIDsOf(linked.Traces))
+			assert.True(t, linked.Truncated)
+			assert.Len(t, linked.Links, len(testCase.expected)+1)
+		})
+	}
+}
+
+func TestGetLinkedTracesErrors(t *testing.T) {
+	errStorage := errors.New("storage error")
+
+	qs, readMock, _ := initializeTestService()
+	readMock.On("GetTrace", mock.Anything, workerTraceID).Return(nil, errStorage)
+	_, err := qs.GetLinkedTraces(context.Background(), workerTraceID, DefaultTraceLinkOptions)
+	assert.Equal(t, errStorage, err)
+
+	qs, readMock, _ = initializeTestService()
+	readMock.On("GetTrace", mock.Anything, consumerTraceID).Return(&model.Trace{Spans: []*model.Span{
+		{
+			TraceID:    consumerTraceID,
+			SpanID:     model.NewSpanID(10),
+			References: []model.SpanRef{model.NewFollowsFromRef(producerTraceID, model.NewSpanID(2))},
+		},
+	}}, nil)
+	readMock.On("GetTrace", mock.Anything, producerTraceID).Return(nil, errStorage)
+	_, err = qs.GetLinkedTraces(context.Background(), consumerTraceID, DefaultTraceLinkOptions)
+	assert.Equal(t, errStorage, err)
+}
@@ -17,20 +17,54 @@ package querysvc
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/analysis"
+	"github.com/jaegertracing/jaeger/cmd/query/app/redaction"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/pkg/catalog"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/pkg/multierror"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
+// maxCandidatePages is the number of pages of candidate traces scanned by a search with trace predicates
+// that the span reader does not support.
+const maxCandidatePages = 10
+
 var (
 	errNoArchiveSpanStorage = errors.New("archive span storage was not configured")
+
+	// ErrNoImportSpanStorage is returned when importing into the span storage is not enabled.
+	ErrNoImportSpanStorage = errors.New("importing spans into the span storage is not enabled")
+	// ErrNoUploadStore is returned when importing into the upload store is not enabled.
+	ErrNoUploadStore = errors.New("the upload store was not configured")
+	// ErrNoLiveTail is returned when no source of live spans is configured.
+	ErrNoLiveTail = errors.New("live tail is not configured")
+	// ErrNoCatalog is returned when no source of the service catalog is configured.
+	ErrNoCatalog = errors.New("service catalog is not configured")
+	// ErrNoSpanMetrics is returned when no source of span metrics is configured.
+	ErrNoSpanMetrics = errors.New("span metrics are not configured")
+)
+
+// ImportTarget is where QueryService.ImportSpans writes spans to.
+type ImportTarget string
+
+const (
+	// ImportTargetUploaded writes spans into the upload store, under the UploadedNamespace.
+	ImportTargetUploaded ImportTarget = "uploaded"
+	// ImportTargetStorage writes spans through the span writer of the configured storage.
+	ImportTargetStorage ImportTarget = "storage"
 )
 
 // QueryServiceOptions has optional members of QueryService
@@ -38,6 +72,20 @@ type QueryServiceOptions struct {
 	ArchiveSpanReader spanstore.Reader
 	ArchiveSpanWriter spanstore.Writer
 	Adjuster          adjuster.Adjuster
+	// ImportSpanWriter is the span writer of the configured storage, used to import spans into it.
+	ImportSpanWriter spanstore.Writer
+	// UploadStore is a scratch store for imported spans, searchable alongside the configured storage.
+	UploadStore UploadStore
+	// LiveTail is the source of the spans received by the collectors, e.g. their bus in all-in-one.
+	LiveTail livetail.Source
+	// Catalog returns the activity of the services seen by the collectors, e.g. their span writers in all-in-one.
+	Catalog spanstore.CatalogReader
+	// SpanMetrics returns the RED metrics computed by the collectors, e.g. their aggregator in all-in-one.
+	SpanMetrics spanmetrics.Reader
+	// Redactor redacts the spans returned to the callers, depending on their identity.
+	Redactor *redaction.Redactor
+	// TraceLimits limit the spans of the traces returned by GetLimitedTrace.
+	TraceLimits TraceLimitOptions
 }
 
 // QueryService contains span utils required by the query-service.
@@ -58,11 +106,36 @@ func NewQueryService(spanReader spanstore.Reader, dependencyReader dependencysto
 	if qsvc.options.Adjuster == nil {
 		qsvc.options.Adjuster = adjuster.Sequence(StandardAdjusters...)
 	}
+	if qsvc.options.UploadStore != nil {
+		qsvc.spanReader = newUploadedReader(spanReader, qsvc.options.UploadStore)
+	}
 	return qsvc
 }
 
 // GetTrace is the queryService implementation of spanstore.Reader.GetTrace
 func (qs QueryService) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	trace, err := qs.getTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+	return qs.Redact(ctx, trace), nil
+}
+
+// StreamTrace passes fn the redacted parts of the trace as they are read, see spanstore.TraceStreamer,
+// until fn returns false.
+func (qs QueryService) StreamTrace(ctx context.Context, traceID model.TraceID, fn func(part *model.Trace) bool) error {
+	redacted := func(part *model.Trace) bool {
+		return fn(qs.Redact(ctx, part))
+	}
+	err := spanstore.StreamTrace(ctx, qs.spanReader, traceID, redacted)
+	if err == spanstore.ErrTraceNotFound && qs.options.ArchiveSpanReader != nil {
+		return spanstore.StreamTrace(ctx, qs.options.ArchiveSpanReader, traceID, redacted)
+	}
+	return err
+}
+
+// getTrace returns the trace without redaction.
+func (qs QueryService) getTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
 	trace, err := qs.spanReader.GetTrace(ctx, traceID)
 	if err == spanstore.ErrTraceNotFound {
 		if qs.options.ArchiveSpanReader == nil {
@@ -88,7 +161,231 @@ func (qs QueryService) GetOperations(
 
 // FindTraces is the queryService implementation of spanstore.Reader.FindTraces
 func (qs QueryService) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
-	return qs.spanReader.FindTraces(ctx, query)
+	traces, _, err := qs.FindTracesPage(ctx, query)
+	return traces, err
+}
+
+// FindTracesPage returns a page of traces that match the query, in the order defined by spanstore.PageToken,
+// together with the continuation token for the next page. The token is empty if there are no more results.
+//
+// Tag predicates that the span reader cannot evaluate are applied to the traces it returns,
+// so a page can hold fewer traces than requested even when more results are available.
+// Trace predicates that the span reader cannot evaluate are applied to candidate traces,
+// found by the span-level parameters of the query and scanned page by page.
+//
+// The traces are redacted after they are matched against the raw values, so the queries searching values
+// that are redacted for the caller are rejected, see redaction.Redactor.CheckQuery.
+func (qs QueryService) FindTracesPage(
+	ctx context.Context,
+	query *spanstore.TraceQueryParameters,
+) ([]*model.Trace, string, error) {
+	if redactor := qs.options.Redactor; redactor != nil {
+		if err := redactor.CheckQuery(redactor.Identity(ctx), query); err != nil {
+			return nil, "", err
+		}
+	}
+	traces, nextPageToken, err := qs.findTraces(ctx, query)
+	if err != nil {
+		return nil, "", err
+	}
+	for i, trace := range traces {
+		traces[i] = qs.Redact(ctx, trace)
+	}
+	return traces, nextPageToken, nil
+}
+
+// findTraces returns a page of traces that match the query, without redaction.
+func (qs QueryService) findTraces(
+	ctx context.Context,
+	query *spanstore.TraceQueryParameters,
+) ([]*model.Trace, string, error) {
+	if _, err := spanstore.ParsePageToken(query.PageToken); err != nil {
+		return nil, "", err
+	}
+	readerQuery, residualQuery := qs.splitTagPredicates(query)
+	if readerQuery.TracePredicates != nil && !qs.supportsTracePredicates(readerQuery.TracePredicates) {
+		return qs.findTracesByCandidates(ctx, readerQuery, residualQuery)
+	}
+	traces, nextPageToken, err := qs.findTracesPage(ctx, readerQuery, qs.spanReader.FindTraces)
+	if err != nil {
+		return nil, "", err
+	}
+	if residualQuery != nil {
+		matching := traces[:0]
+		for _, trace := range traces {
+			if _, found := spanstore.LatestMatchingSpanTime(residualQuery, trace); found {
+				matching = append(matching, trace)
+			}
+		}
+		traces = matching
+	}
+	return traces, nextPageToken, nil
+}
+
+// findTracesPage returns the page of traces found by the find function, sorted for paging,
+// and the continuation token for the next page.
+func (qs QueryService) findTracesPage(
+	ctx context.Context,
+	query *spanstore.TraceQueryParameters,
+	find func(context.Context, *spanstore.TraceQueryParameters) ([]*model.Trace, error),
+) ([]*model.Trace, string, error) {
+	pageToken, err := spanstore.ParsePageToken(query.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	traces, err := find(ctx, query)
+	if err != nil {
+		return nil, "", err
+	}
+	// Backends narrow their index scans to the token, so a trace with matching spans on both sides
+	// of it can be found again, positioned by its matching spans up to the token.
+	scanQuery := pageToken.BoundQuery(query)
+	spanstore.SortTracesForPaging(scanQuery, traces)
+	var nextPageToken string
+	if query.NumTraces > 0 && len(traces) >= query.NumTraces {
+		// The next page follows the last trace found, even if it belongs to a previous page.
+		next := spanstore.NewPageToken(scanQuery, traces[len(traces)-1])
+		if pageToken.Admits(next.StartTime, next.TraceID) {
+			nextPageToken = next.String()
+		}
+	}
+	if pageToken != nil {
+		// Drop the traces that belong to a previous page. The others are positioned by all their matching spans.
+		admitted := traces[:0]
+		for _, trace := range traces {
+			if pageToken.AdmitsTrace(query, trace) {
+				admitted = append(admitted, trace)
+			}
+		}
+		traces = admitted
+	}
+	return traces, nextPageToken, nil
+}
+
+// findTracesByCandidates scans pages of traces matching the span-level parameters of the query
+// and keeps those that satisfy its trace predicates, until the requested number of traces is found,
+// the candidates are exhausted or maxCandidatePages pages have been scanned.
+func (qs QueryService) findTracesByCandidates(
+	ctx context.Context,
+	query *spanstore.TraceQueryParameters,
+	residualQuery *spanstore.TraceQueryParameters,
+) ([]*model.Trace, string, error) {
+	predicates := query.TracePredicates
+	candidateQuery := *query
+	candidateQuery.TracePredicates = nil
+	if len(candidateQuery.Services()) == 0 && len(predicates.Services) > 0 {
+		// storage backends require a service, any of the services involved narrows the search
+		candidateQuery.ServiceName = predicates.Services[0]
+	}
+	var traces []*model.Trace
+	for page := 0; page < maxCandidatePages; page++ {
+		candidates, nextPageToken, err := qs.findTracesPage(ctx, &candidateQuery, qs.findCandidateTraces)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, trace := range candidates {
+			if residualQuery != nil {
+				if _, found := spanstore.LatestMatchingSpanTime(residualQuery, trace); !found {
+					continue
+				}
+			}
+			if !predicates.MatchTrace(trace) {
+				continue
+			}
+			traces = append(traces, trace)
+			if len(traces) == query.NumTraces {
+				return traces, spanstore.NewPageToken(&candidateQuery, trace).String(), nil
+			}
+		}
+		if nextPageToken == "" {
+			return traces, "", nil
+		}
+		candidateQuery.PageToken = nextPageToken
+	}
+	return traces, candidateQuery.PageToken, nil
+}
+
+// findCandidateTraces loads the traces whose IDs match the query.
+func (qs QueryService) findCandidateTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	traceIDs, err := qs.spanReader.FindTraceIDs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	traces := make([]*model.Trace, 0, len(traceIDs))
+	for _, traceID := range traceIDs {
+		trace, err := qs.spanReader.GetTrace(ctx, traceID)
+		if err == spanstore.ErrTraceNotFound {
+			// the trace may have expired since its ID was found
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, trace)
+	}
+	return traces, nil
+}
+
+func (qs QueryService) supportsTracePredicates(predicates *spanstore.TracePredicates) bool {
+	support, ok := qs.spanReader.(spanstore.TracePredicateSupport)
+	return ok && support.SupportsTracePredicates(predicates)
+}
+
+// splitTagPredicates returns the query to pass to the span reader, without the tag predicate groups
+// that the reader does not support, and the query that the results must be filtered with,
+// which is nil if the reader supports all groups.
+func (qs QueryService) splitTagPredicates(
+	query *spanstore.TraceQueryParameters,
+) (*spanstore.TraceQueryParameters, *spanstore.TraceQueryParameters) {
+	if len(query.TagPredicates) == 0 {
+		return query, nil
+	}
+	support, _ := qs.spanReader.(spanstore.TagPredicateSupport)
+	readerQuery := *query
+	readerQuery.Tags = make(map[string]string, len(query.Tags))
+	for k, v := range query.Tags {
+		readerQuery.Tags[k] = v
+	}
+	readerQuery.TagPredicates = nil
+	var unsupported []spanstore.TagPredicateGroup
+	for _, group := range query.TagPredicates {
+		if support != nil && supportsGroup(support, group) {
+			readerQuery.TagPredicates = append(readerQuery.TagPredicates, group)
+			continue
+		}
+		unsupported = append(unsupported, group)
+		if len(group) == 1 && group[0].Operator == spanstore.TagOpEqual {
+			// every reader evaluates exact matches, this narrows the search without changing its results
+			if _, ok := readerQuery.Tags[group[0].Key]; !ok {
+				readerQuery.Tags[group[0].Key] = group[0].Value
+			}
+		}
+	}
+	if len(unsupported) == 0 {
+		return &readerQuery, nil
+	}
+	// the residual query keeps the tags, which must be satisfied by the same span as the predicates
+	return &readerQuery, &spanstore.TraceQueryParameters{
+		ServiceName:    query.ServiceName,
+		ServiceNames:   query.ServiceNames,
+		OperationName:  query.OperationName,
+		OperationNames: query.OperationNames,
+		StartTimeMin:   query.StartTimeMin,
+		StartTimeMax:   query.StartTimeMax,
+		DurationMin:    query.DurationMin,
+		DurationMax:    query.DurationMax,
+		Tags:           query.Tags,
+		TagPredicates:  unsupported,
+	}
+}
+
+func supportsGroup(support spanstore.TagPredicateSupport, group spanstore.TagPredicateGroup) bool {
+	for _, predicate := range group {
+		if !support.SupportsTagPredicate(predicate) {
+			return false
+		}
+	}
+	return true
 }
 
 // ArchiveTrace is the queryService utility to archive traces.
@@ -96,7 +393,7 @@ func (qs QueryService) ArchiveTrace(ctx context.Context, traceID model.TraceID)
 	if qs.options.ArchiveSpanWriter == nil {
 		return errNoArchiveSpanStorage
 	}
-	trace, err := qs.GetTrace(ctx, traceID)
+	trace, err := qs.getTrace(ctx, traceID)
 	if err != nil {
 		return err
 	}
@@ -111,16 +408,77 @@ func (qs QueryService) ArchiveTrace(ctx context.Context, traceID model.TraceID)
 	return multierror.Wrap(writeErrors)
 }
 
+// ImportSpans writes the spans to the import target and returns the IDs of their traces.
+// Spans imported into the upload store have their service names moved to the UploadedNamespace.
+func (qs QueryService) ImportSpans(ctx context.Context, spans []*model.Span, target ImportTarget) ([]model.TraceID, error) {
+	var writer spanstore.Writer
+	switch target {
+	case ImportTargetUploaded:
+		if qs.options.UploadStore == nil {
+			return nil, ErrNoUploadStore
+		}
+		moveToUploadedNamespace(spans)
+		writer = qs.options.UploadStore
+	case ImportTargetStorage:
+		if qs.options.ImportSpanWriter == nil {
+			return nil, ErrNoImportSpanStorage
+		}
+		writer = qs.options.ImportSpanWriter
+	default:
+		return nil, fmt.Errorf("unsupported import target '%s'", target)
+	}
+
+	var traceIDs []model.TraceID
+	seen := make(map[model.TraceID]struct{})
+	var writeErrors []error
+	for _, span := range spans {
+		if err := writer.WriteSpan(span); err != nil {
+			writeErrors = append(writeErrors, err)
+			continue
+		}
+		if _, ok := seen[span.TraceID]; !ok {
+			seen[span.TraceID] = struct{}{}
+			traceIDs = append(traceIDs, span.TraceID)
+		}
+	}
+	return traceIDs, multierror.Wrap(writeErrors)
+}
+
 // Adjust applies adjusters to the trace.
 func (qs QueryService) Adjust(trace *model.Trace) (*model.Trace, error) {
 	return qs.options.Adjuster.Adjust(trace)
 }
 
+// Redact redacts the trace for the caller identified by the context, if a Redactor is configured.
+func (qs QueryService) Redact(ctx context.Context, trace *model.Trace) *model.Trace {
+	if qs.options.Redactor == nil {
+		return trace
+	}
+	redacted, _ := qs.options.Redactor.Adjuster(qs.options.Redactor.Identity(ctx)).Adjust(trace)
+	return redacted
+}
+
+// TraceStats returns the statistics of the trace, optionally grouped by a tag, see analysis.ComputeStats.
+// The trace is expected to be adjusted.
+func (qs QueryService) TraceStats(trace *model.Trace, groupByTag string) *analysis.TraceStats {
+	return analysis.ComputeStats(trace, groupByTag)
+}
+
 // GetDependencies implements dependencystore.Reader.GetDependencies
 func (qs QueryService) GetDependencies(endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
 	return qs.dependencyReader.GetDependencies(endTs, lookback)
 }
 
+// GetDetailedDependencies implements dependencystore.DetailedReader.GetDetailedDependencies,
+// if the dependency reader supports it.
+func (qs QueryService) GetDetailedDependencies(endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	reader, ok := qs.dependencyReader.(dependencystore.DetailedReader)
+	if !ok {
+		return nil, dependencystore.ErrDetailedDependenciesNotSupported
+	}
+	return reader.GetDetailedDependencies(endTs, lookback)
+}
+
 // InitArchiveStorage tries to initialize archive storage reader/writer if storage factory supports them.
 func (opts *QueryServiceOptions) InitArchiveStorage(storageFactory storage.Factory, logger *zap.Logger) bool {
 	archiveFactory, ok := storageFactory.(storage.ArchiveFactory)
@@ -150,3 +508,173 @@ func (opts *QueryServiceOptions) InitArchiveStorage(storageFactory storage.Facto
 	opts.ArchiveSpanWriter = writer
 	return true
 }
+
+// TailSpans subscribes to the spans received by the collectors from now on that match the filter.
+// The subscription must be closed by the caller, who is responsible for redacting the spans.
+func (qs QueryService) TailSpans(filter livetail.Filter) (*livetail.Subscription, error) {
+	if qs.options.LiveTail == nil {
+		return nil, ErrNoLiveTail
+	}
+	return qs.options.LiveTail.Subscribe(filter)
+}
+
+// GetServiceCatalog returns the recent activity of each service, as seen by the collectors.
+// The process tag values of the services are redacted for the caller identified by the context.
+func (qs QueryService) GetServiceCatalog(ctx context.Context) ([]spanstore.ServiceActivity, error) {
+	if qs.options.Catalog == nil {
+		return nil, ErrNoCatalog
+	}
+	services, err := qs.options.Catalog.GetServiceCatalog(ctx)
+	if err != nil || qs.options.Redactor == nil {
+		return services, err
+	}
+	identity := qs.options.Redactor.Identity(ctx)
+	redacted := make([]spanstore.ServiceActivity, len(services))
+	for i, service := range services {
+		redacted[i] = service
+		redacted[i].ClientVersions = qs.redactValues(identity, service.ServiceName, catalog.ClientVersionTag, service.ClientVersions)
+		redacted[i].Hostnames = qs.redactValues(identity, service.ServiceName, catalog.HostnameTag, service.Hostnames)
+		redacted[i].ClientUUIDs = qs.redactValues(identity, service.ServiceName, catalog.ClientUUIDTag, service.ClientUUIDs)
+	}
+	return redacted, nil
+}
+
+// redactValues returns the distinct redacted values of the tag with the key, sorted.
+func (qs QueryService) redactValues(identity string, service string, key string, values []string) []string {
+	if len(values) == 0 {
+		return values
+	}
+	distinct := make(map[string]struct{}, len(values))
+	redacted := make([]string, 0, len(values))
+	for _, value := range values {
+		value = qs.options.Redactor.RedactValue(identity, service, key, value)
+		if _, ok := distinct[value]; !ok {
+			distinct[value] = struct{}{}
+			redacted = append(redacted, value)
+		}
+	}
+	sort.Strings(redacted)
+	return redacted
+}
+
+// GetSpanMetrics returns the request rate, error rate and latency of the operations of a service,
+// as computed by the collectors from the spans they received. The dimension values are redacted for
+// the caller identified by the context, and the series whose redacted values are equal are merged.
+func (qs QueryService) GetSpanMetrics(ctx context.Context, query spanmetrics.Query) ([]spanmetrics.Series, error) {
+	if qs.options.SpanMetrics == nil {
+		return nil, ErrNoSpanMetrics
+	}
+	series, err := qs.options.SpanMetrics.GetSpanMetrics(ctx, query)
+	if err != nil || qs.options.Redactor == nil {
+		return series, err
+	}
+	identity := qs.options.Redactor.Identity(ctx)
+	redacted := make([]spanmetrics.Series, len(series))
+	for i, s := range series {
+		redacted[i] = s
+		if len(s.Dimensions) == 0 {
+			continue
+		}
+		redacted[i].Dimensions = make(map[string]string, len(s.Dimensions))
+		for key, value := range s.Dimensions {
+			redacted[i].Dimensions[key] = qs.options.Redactor.RedactValue(identity, s.ServiceName, key, value)
+		}
+	}
+	return spanmetrics.Merge(redacted), nil
+}
+
+// InitImport sets the upload store for imported spans and, if storageWrites is true,
+// creates the span writer that imports spans into the configured storage.
+func (opts *QueryServiceOptions) InitImport(
+	storageFactory storage.Factory,
+	uploadStore UploadStore,
+	storageWrites bool,
+	logger *zap.Logger,
+) bool {
+	if storageWrites {
+		writer, err := storageFactory.CreateSpanWriter()
+		if err != nil {
+			logger.Error("Cannot init import span writer", zap.Error(err))
+			return false
+		}
+		opts.ImportSpanWriter = writer
+	}
+	opts.UploadStore = uploadStore
+	return true
+}
+
+// InitLiveTail subscribes to the live spans of the collectors at the given gRPC host:ports,
+// buffering up to bufferSize spans per subscriber.
+func (opts *QueryServiceOptions) InitLiveTail(hostPorts []string, bufferSize int, logger *zap.Logger) bool {
+	clients := make([]api_v2.SpanTailServiceClient, 0, len(hostPorts))
+	for _, hostPort := range hostPorts {
+		conn, err := grpc.Dial(hostPort, grpc.WithInsecure())
+		if err != nil {
+			logger.Error("Cannot connect to collector for live tail", zap.String("host-port", hostPort), zap.Error(err))
+			return false
+		}
+		clients = append(clients, api_v2.NewSpanTailServiceClient(conn))
+	}
+	opts.LiveTail = livetail.NewRemoteSource(clients, bufferSize)
+	return true
+}
+
+// InitCatalog merges the service catalogs of the collectors at the given gRPC host:ports.
+func (opts *QueryServiceOptions) InitCatalog(hostPorts []string, logger *zap.Logger) bool {
+	clients := make(map[string]api_v2.ServiceCatalogServiceClient, len(hostPorts))
+	for _, hostPort := range hostPorts {
+		conn, err := grpc.Dial(hostPort, grpc.WithInsecure())
+		if err != nil {
+			logger.Error("Cannot connect to collector for service catalog", zap.String("host-port", hostPort), zap.Error(err))
+			return false
+		}
+		clients[hostPort] = api_v2.NewServiceCatalogServiceClient(conn)
+	}
+	opts.Catalog = catalog.NewRemoteReader(clients, logger)
+	return true
+}
+
+// InitSpanMetrics adds up the span metrics of the collectors at the given gRPC host:ports.
+func (opts *QueryServiceOptions) InitSpanMetrics(hostPorts []string, logger *zap.Logger) bool {
+	clients := make(map[string]api_v2.SpanMetricsServiceClient, len(hostPorts))
+	for _, hostPort := range hostPorts {
+		conn, err := grpc.Dial(hostPort, grpc.WithInsecure())
+		if err != nil {
+			logger.Error("Cannot connect to collector for span metrics", zap.String("host-port", hostPort), zap.Error(err))
+			return false
+		}
+		clients[hostPort] = api_v2.NewSpanMetricsServiceClient(conn)
+	}
+	opts.SpanMetrics = spanmetrics.NewRemoteReader(clients, logger)
+	return true
+}
+
+// InitRedaction loads the redaction rules from the file, see redaction.LoadRules, and the secret key of the hashes
+// from hashKeyFile, if not empty, into the redactor options.
+func (opts *QueryServiceOptions) InitRedaction(rulesFile string, hashKeyFile string, options redaction.Options) error {
+	rules, err := redaction.LoadRules(rulesFile)
+	if err != nil {
+		return err
+	}
+	if hashKeyFile != "" {
+		if options.HashKey, err = redaction.LoadHashKey(hashKeyFile); err != nil {
+			return err
+		}
+	}
+	redactor, err := redaction.NewRedactor(rules, options)
+	if err != nil {
+		return err
+	}
+	opts.Redactor = redactor
+	return nil
+}
+
+// InitAdjusters replaces the standard adjusters with the registered adjusters of the given names, applied in order.
+func (opts *QueryServiceOptions) InitAdjusters(names []string) error {
+	adjusters, err := AdjustersByName(names)
+	if err != nil {
+		return err
+	}
+	opts.Adjuster = adjuster.Sequence(adjusters...)
+	return nil
+}
@@ -17,16 +17,23 @@ package querysvc
 import (
 	"context"
 	"errors"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/redaction"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
@@ -183,6 +190,246 @@ func TestFindTraces(t *testing.T) {
 	assert.Len(t, traces, 1)
 }
 
+// Test QueryService.FindTracesPage() ordering, de-duplication and continuation token.
+func TestFindTracesPage(t *testing.T) {
+	makeTrace := func(id uint64, startTimes ...int64) *model.Trace {
+		trace := &model.Trace{}
+		for i, startTime := range startTimes {
+			trace.Spans = append(trace.Spans, &model.Span{
+				TraceID:   model.NewTraceID(0, id),
+				SpanID:    model.NewSpanID(uint64(i + 1)),
+				StartTime: time.Unix(startTime, 0),
+				Process:   &model.Process{ServiceName: "service"},
+			})
+		}
+		return trace
+	}
+	query := &spanstore.TraceQueryParameters{
+		ServiceName: "service",
+		NumTraces:   2,
+	}
+
+	qs, readMock, _ := initializeTestService()
+	readMock.On("FindTraces", mock.Anything, mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{makeTrace(1, 10), makeTrace(2, 20)}, nil).Once()
+	traces, nextPageToken, err := qs.FindTracesPage(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.Trace{makeTrace(2, 20), makeTrace(1, 10)}, traces)
+	assert.Equal(t, spanstore.PageToken{StartTime: time.Unix(10, 0), TraceID: model.NewTraceID(0, 1)}.String(), nextPageToken)
+
+	// trace 2 has a span before the token and is returned again by the backend
+	query.PageToken = nextPageToken
+	readMock.On("FindTraces", mock.Anything, mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{makeTrace(2, 5, 20), makeTrace(3, 8)}, nil).Once()
+	traces, nextPageToken, err = qs.FindTracesPage(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.Trace{makeTrace(3, 8)}, traces)
+	// the next page follows trace 2, positioned by its span before the token
+	assert.Equal(t, spanstore.PageToken{StartTime: time.Unix(5, 0), TraceID: model.NewTraceID(0, 2)}.String(), nextPageToken)
+
+	// a full page of traces that were already returned does not end the search
+	query.PageToken = nextPageToken
+	readMock.On("FindTraces", mock.Anything, mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{makeTrace(1, 2, 10), makeTrace(2, 1, 20)}, nil).Once()
+	traces, nextPageToken, err = qs.FindTracesPage(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Empty(t, traces)
+	assert.Equal(t, spanstore.PageToken{StartTime: time.Unix(1, 0), TraceID: model.NewTraceID(0, 2)}.String(), nextPageToken)
+
+	readMock.On("FindTraces", mock.Anything, mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{}, nil).Once()
+	query.PageToken = nextPageToken
+	traces, nextPageToken, err = qs.FindTracesPage(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Empty(t, traces)
+	assert.Empty(t, nextPageToken)
+
+	query.PageToken = "?"
+	_, _, err = qs.FindTracesPage(context.Background(), query)
+	assert.Equal(t, spanstore.ErrMalformedPageToken, err)
+}
+
+type prefixOnlyReader struct {
+	spanstoremocks.Reader
+}
+
+func (r *prefixOnlyReader) SupportsTagPredicate(predicate spanstore.TagPredicate) bool {
+	return predicate.Operator == spanstore.TagOpPrefix
+}
+
+func TestFindTracesWithTagPredicates(t *testing.T) {
+	makeTrace := func(id uint64, tags ...model.KeyValue) *model.Trace {
+		return &model.Trace{Spans: []*model.Span{{
+			TraceID:   model.NewTraceID(0, id),
+			SpanID:    model.NewSpanID(1),
+			StartTime: time.Unix(int64(id), 0),
+			Tags:      append([]model.KeyValue{model.String("k", "v")}, tags...),
+			Process:   &model.Process{ServiceName: "service"},
+		}}}
+	}
+	serverError := spanstore.TagPredicateGroup{{Key: "http.status_code", Operator: spanstore.TagOpGreaterOrEqual, Value: "500"}}
+	apiURL := spanstore.TagPredicateGroup{{Key: "http.url", Operator: spanstore.TagOpPrefix, Value: "/api"}}
+	client := spanstore.TagPredicateGroup{{Key: "span.kind", Operator: spanstore.TagOpEqual, Value: "client"}}
+	query := &spanstore.TraceQueryParameters{
+		ServiceName:   "service",
+		Tags:          map[string]string{"k": "v"},
+		TagPredicates: []spanstore.TagPredicateGroup{serverError, apiURL, client},
+		NumTraces:     3,
+	}
+	backendTraces := func() []*model.Trace {
+		return []*model.Trace{
+			makeTrace(1, model.Int64("http.status_code", 503), model.String("http.url", "/api"), model.String("span.kind", "client")),
+			makeTrace(2, model.Int64("http.status_code", 200), model.String("http.url", "/api"), model.String("span.kind", "client")),
+			makeTrace(3, model.Int64("http.status_code", 500), model.String("http.url", "/ui"), model.String("span.kind", "client")),
+		}
+	}
+
+	// the reader does not evaluate tag predicates, exact matches are passed as tags
+	qs, readMock, _ := initializeTestService()
+	readMock.On("FindTraces", mock.Anything, &spanstore.TraceQueryParameters{
+		ServiceName: "service",
+		Tags:        map[string]string{"k": "v", "span.kind": "client"},
+		NumTraces:   3,
+	}).Return(backendTraces(), nil).Once()
+	traces, nextPageToken, err := qs.FindTracesPage(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+	assert.Equal(t, model.NewTraceID(0, 1), traces[0].Spans[0].TraceID)
+	// the next page starts after the last trace returned by the reader
+	assert.Equal(t, spanstore.PageToken{StartTime: time.Unix(1, 0), TraceID: model.NewTraceID(0, 1)}.String(), nextPageToken)
+	assert.Equal(t, map[string]string{"k": "v"}, query.Tags)
+
+	// the reader evaluates prefix predicates
+	prefixReader := &prefixOnlyReader{}
+	qs = NewQueryService(prefixReader, &depsmocks.Reader{}, QueryServiceOptions{})
+	prefixReader.On("FindTraces", mock.Anything, &spanstore.TraceQueryParameters{
+		ServiceName:   "service",
+		Tags:          map[string]string{"k": "v", "span.kind": "client"},
+		TagPredicates: []spanstore.TagPredicateGroup{apiURL},
+		NumTraces:     3,
+	}).Return(backendTraces()[:2], nil).Once()
+	traces, nextPageToken, err = qs.FindTracesPage(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+	assert.Equal(t, model.NewTraceID(0, 1), traces[0].Spans[0].TraceID)
+	assert.Empty(t, nextPageToken)
+}
+
+func TestFindTracesWithTagsAndTagPredicatesOnDifferentSpans(t *testing.T) {
+	makeSpan := func(spanID uint64, tag model.KeyValue) *model.Span {
+		return &model.Span{
+			TraceID:   model.NewTraceID(0, 1),
+			SpanID:    model.NewSpanID(spanID),
+			StartTime: time.Unix(1, 0),
+			Tags:      model.KeyValues{tag},
+			Process:   &model.Process{ServiceName: "service"},
+		}
+	}
+	query := &spanstore.TraceQueryParameters{
+		ServiceName:   "service",
+		Tags:          map[string]string{"http.method": "POST"},
+		TagPredicates: []spanstore.TagPredicateGroup{{{Key: "http.status_code", Operator: spanstore.TagOpGreaterOrEqual, Value: "500"}}},
+		NumTraces:     1,
+	}
+	qs, readMock, _ := initializeTestService()
+	readMock.On("FindTraces", mock.Anything, mock.Anything).Return([]*model.Trace{
+		// the server error is not on the span of the POST request
+		{Spans: []*model.Span{makeSpan(1, model.String("http.method", "POST")), makeSpan(2, model.Int64("http.status_code", 503))}},
+	}, nil).Once()
+	traces, _, err := qs.FindTracesPage(context.Background(), query)
+	require.NoError(t, err)
+	assert.Empty(t, traces)
+}
+
+func TestFindTracesWithTagPredicatesAndMultipleServices(t *testing.T) {
+	makeSpan := func(spanID uint64, service string, statusCode int64) *model.Span {
+		return &model.Span{
+			TraceID:   model.NewTraceID(0, 1),
+			SpanID:    model.NewSpanID(spanID),
+			StartTime: time.Unix(1, 0),
+			Tags:      model.KeyValues{model.Int64("http.status_code", statusCode)},
+			Process:   &model.Process{ServiceName: service},
+		}
+	}
+	query := &spanstore.TraceQueryParameters{
+		ServiceNames:  []string{"frontend", "api"},
+		TagPredicates: []spanstore.TagPredicateGroup{{{Key: "http.status_code", Operator: spanstore.TagOpGreaterOrEqual, Value: "500"}}},
+		NumTraces:     1,
+	}
+	qs, readMock, _ := initializeTestService()
+	readMock.On("FindTraces", mock.Anything, mock.Anything).Return([]*model.Trace{
+		// the server error comes from a service that is not searched for
+		{Spans: []*model.Span{makeSpan(1, "frontend", 200), makeSpan(2, "db", 503)}},
+	}, nil).Once()
+	traces, _, err := qs.FindTracesPage(context.Background(), query)
+	require.NoError(t, err)
+	assert.Empty(t, traces)
+}
+
+func TestFindTracesWithTracePredicates(t *testing.T) {
+	makeTrace := func(id uint64, spanCount int) *model.Trace {
+		trace := &model.Trace{}
+		for i := 0; i < spanCount; i++ {
+			trace.Spans = append(trace.Spans, &model.Span{
+				TraceID:   model.NewTraceID(0, id),
+				SpanID:    model.NewSpanID(uint64(i + 1)),
+				StartTime: time.Unix(int64(id), 0),
+				Process:   &model.Process{ServiceName: "frontend"},
+			})
+		}
+		return trace
+	}
+	query := &spanstore.TraceQueryParameters{
+		TracePredicates: &spanstore.TracePredicates{
+			MinSpanCount: 2,
+			Services:     []string{"frontend"},
+		},
+		NumTraces: 2,
+	}
+	qs, readMock, _ := initializeTestService()
+	candidateQuery := func(pageToken string) *spanstore.TraceQueryParameters {
+		return &spanstore.TraceQueryParameters{ServiceName: "frontend", NumTraces: 2, PageToken: pageToken}
+	}
+	// the first page of candidates has one trace with enough spans
+	readMock.On("FindTraceIDs", mock.Anything, candidateQuery("")).
+		Return([]model.TraceID{model.NewTraceID(0, 9), model.NewTraceID(0, 8)}, nil).Once()
+	readMock.On("GetTrace", mock.Anything, model.NewTraceID(0, 9)).Return(makeTrace(9, 1), nil).Once()
+	readMock.On("GetTrace", mock.Anything, model.NewTraceID(0, 8)).Return(makeTrace(8, 2), nil).Once()
+	// the second page has an expired trace and the trace that completes the results
+	secondPageToken := spanstore.PageToken{StartTime: time.Unix(8, 0), TraceID: model.NewTraceID(0, 8)}.String()
+	readMock.On("FindTraceIDs", mock.Anything, candidateQuery(secondPageToken)).
+		Return([]model.TraceID{model.NewTraceID(0, 7), model.NewTraceID(0, 6)}, nil).Once()
+	readMock.On("GetTrace", mock.Anything, model.NewTraceID(0, 7)).Return(nil, spanstore.ErrTraceNotFound).Once()
+	readMock.On("GetTrace", mock.Anything, model.NewTraceID(0, 6)).Return(makeTrace(6, 3), nil).Once()
+
+	traces, nextPageToken, err := qs.FindTracesPage(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Trace{makeTrace(8, 2), makeTrace(6, 3)}, traces)
+	assert.Equal(t, spanstore.PageToken{StartTime: time.Unix(6, 0), TraceID: model.NewTraceID(0, 6)}.String(), nextPageToken)
+
+	// the candidates are exhausted
+	query.PageToken = nextPageToken
+	readMock.On("FindTraceIDs", mock.Anything, candidateQuery(nextPageToken)).
+		Return([]model.TraceID{model.NewTraceID(0, 5)}, nil).Once()
+	readMock.On("GetTrace", mock.Anything, model.NewTraceID(0, 5)).Return(makeTrace(5, 1), nil).Once()
+	traces, nextPageToken, err = qs.FindTracesPage(context.Background(), query)
+	require.NoError(t, err)
+	assert.Empty(t, traces)
+	assert.Empty(t, nextPageToken)
+
+	query.PageToken = ""
+	readMock.On("FindTraceIDs", mock.Anything, candidateQuery("")).
+		Return([]model.TraceID{model.NewTraceID(0, 9)}, nil).Once()
+	readMock.On("GetTrace", mock.Anything, model.NewTraceID(0, 9)).Return(nil, errors.New("storage error")).Once()
+	_, _, err = qs.FindTracesPage(context.Background(), query)
+	assert.EqualError(t, err, "storage error")
+
+	readMock.On("FindTraceIDs", mock.Anything, candidateQuery("")).
+		Return(nil, errors.New("index error")).Once()
+	_, _, err = qs.FindTracesPage(context.Background(), query)
+	assert.EqualError(t, err, "index error")
+}
+
 // Test QueryService.ArchiveTrace() with no ArchiveSpanWriter.
 func TestArchiveTraceNoOptions(t *testing.T) {
 	qs, _, _ := initializeTestService()
@@ -247,6 +494,15 @@ func TestTraceAdjustmentFailure(t *testing.T) {
 }
 
 // Test QueryService.GetDependencies()
+func TestTraceStats(t *testing.T) {
+	qs, _, _ := initializeTestService()
+	stats := qs.TraceStats(mockTrace, "")
+	assert.Equal(t, 2, stats.Spans)
+	assert.Equal(t, 1, stats.Depth)
+	require.Len(t, stats.Groups, 1)
+	assert.Equal(t, 2, stats.Groups[0].Count)
+}
+
 func TestGetDependencies(t *testing.T) {
 	qs, _, depsMock := initializeTestService()
 	expectedDependencies := []model.DependencyLink{
@@ -264,6 +520,34 @@ func TestGetDependencies(t *testing.T) {
 	assert.Equal(t, expectedDependencies, actualDependencies)
 }
 
+type detailedReader struct {
+	depsmocks.Reader
+	links []model.DependencyLink
+}
+
+func (r *detailedReader) GetDetailedDependencies(endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	return r.links, nil
+}
+
+func TestGetDetailedDependencies(t *testing.T) {
+	qs, _, _ := initializeTestService()
+	_, err := qs.GetDetailedDependencies(time.Now(), defaultDependencyLookbackDuration)
+	assert.Equal(t, dependencystore.ErrDetailedDependenciesNotSupported, err)
+
+	expectedDependencies := []model.DependencyLink{{
+		Parent:          "killer",
+		Child:           "queen",
+		CallCount:       12,
+		ParentOperation: "send",
+		ChildOperation:  "receive",
+		Kind:            model.MessagingDependency,
+	}}
+	qs = NewQueryService(&spanstoremocks.Reader{}, &detailedReader{links: expectedDependencies}, QueryServiceOptions{})
+	actualDependencies, err := qs.GetDetailedDependencies(time.Now(), defaultDependencyLookbackDuration)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedDependencies, actualDependencies)
+}
+
 type fakeStorageFactory1 struct {
 }
 
@@ -323,3 +607,270 @@ func TestInitArchiveStorage(t *testing.T) {
 	assert.Equal(t, reader, opts.ArchiveSpanReader)
 	assert.Equal(t, writer, opts.ArchiveSpanWriter)
 }
+
+type fakeStorageFactory3 struct {
+	fakeStorageFactory1
+	w    spanstore.Writer
+	wErr error
+}
+
+func (f *fakeStorageFactory3) CreateSpanWriter() (spanstore.Writer, error) { return f.w, f.wErr }
+
+func TestInitImport(t *testing.T) {
+	logger := zap.NewNop()
+	writer := &spanstoremocks.Writer{}
+
+	uploadStore := memory.NewStore()
+
+	opts := &QueryServiceOptions{}
+	assert.True(t, opts.InitImport(&fakeStorageFactory3{w: writer}, uploadStore, false, logger))
+	assert.Equal(t, uploadStore, opts.UploadStore)
+	assert.Nil(t, opts.ImportSpanWriter)
+
+	opts = &QueryServiceOptions{}
+	assert.True(t, opts.InitImport(&fakeStorageFactory3{w: writer}, uploadStore, true, logger))
+	assert.Equal(t, uploadStore, opts.UploadStore)
+	assert.Equal(t, writer, opts.ImportSpanWriter)
+
+	opts = &QueryServiceOptions{}
+	assert.False(t, opts.InitImport(&fakeStorageFactory3{wErr: errors.New("error")}, uploadStore, true, logger))
+	assert.Nil(t, opts.UploadStore)
+}
+
+func TestInitLiveTail(t *testing.T) {
+	opts := &QueryServiceOptions{}
+	assert.True(t, opts.InitLiveTail([]string{"localhost:14250", "localhost:14251"}, 10, zap.NewNop()))
+	assert.NotNil(t, opts.LiveTail)
+}
+
+func TestTailSpans(t *testing.T) {
+	qs, _, _ := initializeTestService()
+	_, err := qs.TailSpans(livetail.Filter{})
+	assert.Equal(t, ErrNoLiveTail, err)
+
+	bus := livetail.NewBus(10)
+	qs = NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, QueryServiceOptions{LiveTail: bus})
+	sub, err := qs.TailSpans(livetail.Filter{ServiceName: "frontend"})
+	require.NoError(t, err)
+	defer sub.Close()
+	assert.Equal(t, 1, bus.Subscribers())
+}
+
+func TestInitCatalog(t *testing.T) {
+	opts := &QueryServiceOptions{}
+	assert.True(t, opts.InitCatalog([]string{"localhost:14250", "localhost:14251"}, zap.NewNop()))
+	assert.NotNil(t, opts.Catalog)
+}
+
+func TestGetServiceCatalog(t *testing.T) {
+	qs, _, _ := initializeTestService()
+	_, err := qs.GetServiceCatalog(context.Background())
+	assert.Equal(t, ErrNoCatalog, err)
+
+	store := memory.NewStore()
+	require.NoError(t, store.WriteSpan(&model.Span{OperationName: "GET /", Process: model.NewProcess("frontend", nil)}))
+	qs = NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, QueryServiceOptions{Catalog: store})
+	catalog, err := qs.GetServiceCatalog(context.Background())
+	require.NoError(t, err)
+	require.Len(t, catalog, 1)
+	assert.Equal(t, "frontend", catalog[0].ServiceName)
+}
+
+func TestGetServiceCatalogRedacted(t *testing.T) {
+	redactor, err := redaction.NewRedactor([]redaction.Rule{
+		{Keys: []string{"hostname"}, Exempt: []string{"admin"}},
+	}, redaction.Options{})
+	require.NoError(t, err)
+	store := memory.NewStore()
+	for _, hostname := range []string{"host-1", "host-2"} {
+		require.NoError(t, store.WriteSpan(&model.Span{
+			OperationName: "GET /",
+			Process: model.NewProcess("frontend", []model.KeyValue{
+				model.String("hostname", hostname),
+				model.String("client-uuid", "uuid-"+hostname),
+			}),
+		}))
+	}
+	qs := NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, QueryServiceOptions{Catalog: store, Redactor: redactor})
+
+	catalog, err := qs.GetServiceCatalog(context.Background())
+	require.NoError(t, err)
+	require.Len(t, catalog, 1)
+	assert.Equal(t, []string{redaction.Masked}, catalog[0].Hostnames)
+	assert.Equal(t, []string{"uuid-host-1", "uuid-host-2"}, catalog[0].ClientUUIDs)
+
+	catalog, err = qs.GetServiceCatalog(redaction.ContextWithIdentity(context.Background(), "admin"))
+	require.NoError(t, err)
+	require.Len(t, catalog, 1)
+	assert.Equal(t, []string{"host-1", "host-2"}, catalog[0].Hostnames)
+}
+
+func TestInitSpanMetrics(t *testing.T) {
+	opts := &QueryServiceOptions{}
+	assert.True(t, opts.InitSpanMetrics([]string{"localhost:14250", "localhost:14251"}, zap.NewNop()))
+	assert.NotNil(t, opts.SpanMetrics)
+}
+
+func TestGetSpanMetrics(t *testing.T) {
+	qs, _, _ := initializeTestService()
+	_, err := qs.GetSpanMetrics(context.Background(), spanmetrics.Query{ServiceName: "frontend"})
+	assert.Equal(t, ErrNoSpanMetrics, err)
+
+	aggregator := spanmetrics.NewAggregator(metrics.NullFactory, spanmetrics.Options{})
+	aggregator.ProcessSpan(&model.Span{OperationName: "GET /", Process: model.NewProcess("frontend", nil)})
+	qs = NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, QueryServiceOptions{SpanMetrics: aggregator})
+	series, err := qs.GetSpanMetrics(context.Background(), spanmetrics.Query{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, "GET /", series[0].OperationName)
+}
+
+func TestGetSpanMetricsRedacted(t *testing.T) {
+	redactor, err := redaction.NewRedactor([]redaction.Rule{
+		{Keys: []string{"customer"}, Exempt: []string{"admin"}},
+	}, redaction.Options{})
+	require.NoError(t, err)
+	aggregator := spanmetrics.NewAggregator(metrics.NullFactory, spanmetrics.Options{Dimensions: []string{"customer"}})
+	for _, customer := range []string{"acme", "globex"} {
+		aggregator.ProcessSpan(&model.Span{
+			OperationName: "GET /",
+			Tags:          []model.KeyValue{model.String("customer", customer)},
+			Process:       model.NewProcess("frontend", nil),
+		})
+	}
+	qs := NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, QueryServiceOptions{SpanMetrics: aggregator, Redactor: redactor})
+
+	// the series of the customers are merged once their values are redacted
+	series, err := qs.GetSpanMetrics(context.Background(), spanmetrics.Query{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, map[string]string{"customer": redaction.Masked}, series[0].Dimensions)
+	assert.Equal(t, uint64(2), series[0].Calls)
+
+	series, err = qs.GetSpanMetrics(redaction.ContextWithIdentity(context.Background(), "admin"), spanmetrics.Query{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, series, 2)
+	assert.Equal(t, map[string]string{"customer": "acme"}, series[0].Dimensions)
+}
+
+func TestImportSpansToStorage(t *testing.T) {
+	writer := &spanstoremocks.Writer{}
+	qs := NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, QueryServiceOptions{ImportSpanWriter: writer})
+	spans := []*model.Span{
+		{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(1), Process: &model.Process{ServiceName: "frontend"}},
+		{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(2), Process: &model.Process{ServiceName: "frontend"}},
+		{TraceID: model.NewTraceID(0, 2), SpanID: model.NewSpanID(3), Process: &model.Process{ServiceName: "frontend"}},
+	}
+	writer.On("WriteSpan", spans[0]).Return(nil).Once()
+	writer.On("WriteSpan", spans[1]).Return(nil).Once()
+	writer.On("WriteSpan", spans[2]).Return(errors.New("write error")).Once()
+
+	traceIDs, err := qs.ImportSpans(context.Background(), spans, ImportTargetStorage)
+	assert.EqualError(t, err, "write error")
+	assert.Equal(t, []model.TraceID{model.NewTraceID(0, 1)}, traceIDs)
+	assert.Equal(t, "frontend", spans[0].Process.ServiceName)
+	writer.AssertExpectations(t)
+}
+
+func TestImportSpansErrors(t *testing.T) {
+	qs, _, _ := initializeTestService()
+	_, err := qs.ImportSpans(context.Background(), nil, ImportTargetUploaded)
+	assert.Equal(t, ErrNoUploadStore, err)
+	_, err = qs.ImportSpans(context.Background(), nil, ImportTargetStorage)
+	assert.Equal(t, ErrNoImportSpanStorage, err)
+	_, err = qs.ImportSpans(context.Background(), nil, ImportTarget("archive"))
+	assert.EqualError(t, err, "unsupported import target 'archive'")
+}
+
+func initializeTestServiceWithRedactor(t *testing.T) (*QueryService, *spanstoremocks.Reader, *spanstoremocks.Writer, *model.Trace) {
+	redactor, err := redaction.NewRedactor([]redaction.Rule{
+		{Keys: []string{"user.email"}, Exempt: []string{"admin"}},
+	}, redaction.Options{})
+	require.NoError(t, err)
+	readStorage := &spanstoremocks.Reader{}
+	archiveWriter := &spanstoremocks.Writer{}
+	qs := NewQueryService(readStorage, &depsmocks.Reader{}, QueryServiceOptions{
+		Redactor:          redactor,
+		ArchiveSpanWriter: archiveWriter,
+	})
+	trace := &model.Trace{Spans: []*model.Span{
+		{
+			TraceID: mockTraceID,
+			SpanID:  model.NewSpanID(1),
+			Tags:    []model.KeyValue{model.String("user.email", "alice@example.com")},
+			Process: &model.Process{ServiceName: "frontend"},
+		},
+	}}
+	return qs, readStorage, archiveWriter, trace
+}
+
+func TestGetTraceRedacted(t *testing.T) {
+	qs, readMock, _, trace := initializeTestServiceWithRedactor(t)
+	readMock.On("GetTrace", mock.Anything, mockTraceID).Return(trace, nil)
+
+	redacted, err := qs.GetTrace(context.Background(), mockTraceID)
+	require.NoError(t, err)
+	assert.Equal(t, model.String("user.email", redaction.Masked), redacted.Spans[0].Tags[0])
+
+	raw, err := qs.GetTrace(redaction.ContextWithIdentity(context.Background(), "admin"), mockTraceID)
+	require.NoError(t, err)
+	assert.Equal(t, model.String("user.email", "alice@example.com"), raw.Spans[0].Tags[0])
+}
+
+func TestFindTracesRedacted(t *testing.T) {
+	qs, readMock, _, trace := initializeTestServiceWithRedactor(t)
+	readMock.On("FindTraces", mock.Anything, mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{trace}, nil)
+
+	traces, err := qs.FindTraces(context.Background(), &spanstore.TraceQueryParameters{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+	assert.Equal(t, model.String("user.email", redaction.Masked), traces[0].Spans[0].Tags[0])
+
+	// the reader matches the raw values, which the caller cannot search
+	_, err = qs.FindTraces(context.Background(), &spanstore.TraceQueryParameters{
+		ServiceName: "frontend",
+		Tags:        map[string]string{"user.email": "alice@example.com"},
+	})
+	assert.True(t, errors.Is(err, redaction.ErrRedactedSearch))
+	traces, err = qs.FindTraces(redaction.ContextWithIdentity(context.Background(), "admin"), &spanstore.TraceQueryParameters{
+		ServiceName: "frontend",
+		Tags:        map[string]string{"user.email": "alice@example.com"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, traces, 1)
+}
+
+func TestArchiveTraceNotRedacted(t *testing.T) {
+	qs, readMock, writeMock, trace := initializeTestServiceWithRedactor(t)
+	readMock.On("GetTrace", mock.Anything, mockTraceID).Return(trace, nil)
+	writeMock.On("WriteSpan", trace.Spans[0]).Return(nil).Once()
+
+	require.NoError(t, qs.ArchiveTrace(context.Background(), mockTraceID))
+	writeMock.AssertExpectations(t)
+}
+
+func TestInitRedaction(t *testing.T) {
+	file, err := ioutil.TempFile("", "redaction")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(`{"rules": [{"keys": ["user.email"], "action": "hash"}]}`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	keyFile, err := ioutil.TempFile("", "redaction-key")
+	require.NoError(t, err)
+	defer os.Remove(keyFile.Name())
+	_, err = keyFile.WriteString("secret\n")
+	require.NoError(t, err)
+	require.NoError(t, keyFile.Close())
+
+	opts := &QueryServiceOptions{}
+	require.NoError(t, opts.InitRedaction(file.Name(), keyFile.Name(), redaction.Options{IdentityHeader: "X-Forwarded-User"}))
+	assert.NotNil(t, opts.Redactor)
+	assert.EqualError(t, opts.InitRedaction(file.Name(), "", redaction.Options{}), "redaction rule 0 hashes the values but no hash key is configured")
+	assert.Error(t, opts.InitRedaction(file.Name(), keyFile.Name()+".missing", redaction.Options{}))
+
+	require.NoError(t, ioutil.WriteFile(file.Name(), []byte(`{"rules": [{}]}`), 0600))
+	assert.EqualError(t, opts.InitRedaction(file.Name(), "", redaction.Options{}), "redaction rule 0 has neither keys nor a value pattern")
+	assert.Error(t, opts.InitRedaction(file.Name()+".missing", "", redaction.Options{}))
+}
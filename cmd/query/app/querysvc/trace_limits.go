@@ -0,0 +1,208 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/adjuster"
+)
+
+// ErrTraceTooLarge is returned by GetBoundedTrace for a trace with more spans than can be read.
+var ErrTraceTooLarge = errors.New("trace has more spans than can be read")
+
+// DefaultTraceLimitOptions are the default limits of GetLimitedTrace, which does not limit the spans.
+var DefaultTraceLimitOptions = TraceLimitOptions{
+	MaxSpans:     0,
+	MaxReadSpans: 0,
+	MinSiblings:  adjuster.DefaultMinSiblings,
+}
+
+// TraceLimitOptions limit the spans of the traces returned by GetLimitedTrace.
+type TraceLimitOptions struct {
+	// MaxSpans is the number of spans above which a trace is reduced, 0 for no limit.
+	MaxSpans int
+	// MaxReadSpans is the number of spans read from the span reader for a trace exceeding MaxSpans, the rest
+	// of the trace is left unread; 0 defaults to 10 times MaxSpans. It is at least MaxSpans.
+	// GetBoundedTrace fails for the traces exceeding it.
+	MaxReadSpans int
+	// MinSiblings is the number of repetitive sibling spans from which they are collapsed into a placeholder
+	// span when a trace is reduced, see adjuster.CollapseSiblings; 0 disables collapsing.
+	MinSiblings int
+}
+
+// OmittedSpans counts the spans of an operation omitted from a reduced trace.
+type OmittedSpans struct {
+	ServiceName   string
+	OperationName string
+	Spans         int
+}
+
+// TraceSummary describes how a trace exceeding the span limit was reduced.
+type TraceSummary struct {
+	// TotalSpans is the number of spans read, i.e. of the trace unless it is Truncated.
+	TotalSpans    int
+	ReturnedSpans int
+	// Truncated is true if the trace has more spans than MaxReadSpans, which were left unread.
+	Truncated bool
+	// CollapsedSpans is the number of spans removed by collapsing repetitive sibling spans into placeholder spans.
+	CollapsedSpans int
+	// Omitted are the spans left out once the span limit was reached, by decreasing count.
+	Omitted []OmittedSpans
+}
+
+// GetLimitedTrace returns the trace, reduced to the configured span limit if it exceeds it, in which case
+// the summary of the reduction is not nil. The repetitive sibling spans are collapsed first, then the
+// spans are kept breadth-first from the roots, so that the parents of the returned spans are returned.
+// The trace is streamed from the span reader, which stops reading it at MaxReadSpans spans.
+func (qs QueryService) GetLimitedTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, *TraceSummary, error) {
+	opts := qs.options.TraceLimits
+	if opts.MaxSpans <= 0 {
+		trace, err := qs.GetTrace(ctx, traceID)
+		return trace, nil, err
+	}
+	trace, truncated, err := qs.readTrace(ctx, traceID, opts.maxReadSpans())
+	if err != nil {
+		return nil, nil, err
+	}
+	trace, summary := limitTrace(trace, opts, truncated)
+	return trace, summary, nil
+}
+
+// GetBoundedTrace returns the whole trace, e.g. to be analyzed, unless it has more spans than the MaxReadSpans
+// of the configured span limit, in which case the rest of the trace is left unread and ErrTraceTooLarge is returned.
+func (qs QueryService) GetBoundedTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	opts := qs.options.TraceLimits
+	if opts.MaxSpans <= 0 {
+		return qs.GetTrace(ctx, traceID)
+	}
+	trace, truncated, err := qs.readTrace(ctx, traceID, opts.maxReadSpans())
+	if err != nil {
+		return nil, err
+	}
+	if truncated {
+		return nil, ErrTraceTooLarge
+	}
+	return trace, nil
+}
+
+// readTrace streams the trace from the span reader until maxReadSpans spans are read,
+// and returns true if the trace has more spans, which were left unread.
+func (qs QueryService) readTrace(ctx context.Context, traceID model.TraceID, maxReadSpans int) (*model.Trace, bool, error) {
+	trace := &model.Trace{}
+	truncated := false
+	err := qs.StreamTrace(ctx, traceID, func(part *model.Trace) bool {
+		trace.Warnings = append(trace.Warnings, part.Warnings...)
+		if len(trace.Spans)+len(part.Spans) > maxReadSpans {
+			trace.Spans = append(trace.Spans, part.Spans[:maxReadSpans-len(trace.Spans)]...)
+			truncated = true
+			return false
+		}
+		trace.Spans = append(trace.Spans, part.Spans...)
+		return true
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return trace, truncated, nil
+}
+
+func (opts TraceLimitOptions) maxReadSpans() int {
+	if opts.MaxReadSpans <= 0 {
+		return 10 * opts.MaxSpans
+	}
+	if opts.MaxReadSpans < opts.MaxSpans {
+		return opts.MaxSpans
+	}
+	return opts.MaxReadSpans
+}
+
+// limitTrace reduces the trace, of which truncated tells if spans were left unread, to the span limit.
+func limitTrace(trace *model.Trace, opts TraceLimitOptions, truncated bool) (*model.Trace, *TraceSummary) {
+	if !truncated && len(trace.Spans) <= opts.MaxSpans {
+		return trace, nil
+	}
+	summary := &TraceSummary{TotalSpans: len(trace.Spans), Truncated: truncated}
+	if opts.MinSiblings > 0 {
+		// the adjuster never fails
+		trace, _ = adjuster.CollapseSiblings(opts.MinSiblings).Adjust(trace)
+		summary.CollapsedSpans = summary.TotalSpans - len(trace.Spans)
+	}
+	if len(trace.Spans) > opts.MaxSpans {
+		spans := breadthFirst(trace.Spans)
+		summary.Omitted = omittedSpans(spans[opts.MaxSpans:])
+		trace.Spans = spans[:opts.MaxSpans]
+	}
+	summary.ReturnedSpans = len(trace.Spans)
+	return trace, summary
+}
+
+// breadthFirst returns the spans ordered breadth-first from the roots of their model.SpanTree,
+// and by start time among siblings. The spans that cannot be reached, e.g. in a cycle, are last.
+func breadthFirst(spans []*model.Span) []*model.Span {
+	sorted := append([]*model.Span(nil), spans...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartTime.Before(sorted[j].StartTime)
+	})
+	tree := model.NewSpanTree(sorted)
+	result := make([]*model.Span, 0, len(spans))
+	visited := make(map[*model.Span]struct{}, len(spans))
+	queue := tree.Roots()
+	for len(queue) > 0 {
+		span := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[span]; ok {
+			continue
+		}
+		visited[span] = struct{}{}
+		result = append(result, span)
+		queue = append(queue, tree.Children(span.SpanID)...)
+	}
+	for _, span := range sorted {
+		if _, ok := visited[span]; !ok {
+			result = append(result, span)
+		}
+	}
+	return result
+}
+
+func omittedSpans(spans []*model.Span) []OmittedSpans {
+	type key struct{ service, operation string }
+	counts := make(map[key]int)
+	for _, span := range spans {
+		var service string
+		if span.Process != nil {
+			service = span.Process.ServiceName
+		}
+		counts[key{service: service, operation: span.OperationName}]++
+	}
+	omitted := make([]OmittedSpans, 0, len(counts))
+	for k, count := range counts {
+		omitted = append(omitted, OmittedSpans{ServiceName: k.service, OperationName: k.operation, Spans: count})
+	}
+	sort.Slice(omitted, func(i, j int) bool {
+		if omitted[i].Spans != omitted[j].Spans {
+			return omitted[i].Spans > omitted[j].Spans
+		}
+		if omitted[i].ServiceName != omitted[j].ServiceName {
+			return omitted[i].ServiceName < omitted[j].ServiceName
+		}
+		return omitted[i].OperationName < omitted[j].OperationName
+	})
+	return omitted
+}
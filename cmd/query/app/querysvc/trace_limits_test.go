@@ -0,0 +1,220 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+var batchTraceID = model.NewTraceID(0, 0xba7c4)
+
+// batchTrace returns a job span calling 5 queries, each connecting, and publishing once.
+func batchTrace() *model.Trace {
+	start := time.Unix(100, 0)
+	span := func(id, parent uint64, service, operation string, offset time.Duration) *model.Span {
+		span := &model.Span{
+			TraceID:       batchTraceID,
+			SpanID:        model.NewSpanID(id),
+			OperationName: operation,
+			StartTime:     start.Add(offset),
+			Duration:      time.Millisecond,
+			Process:       model.NewProcess(service, nil),
+		}
+		if parent != 0 {
+			span.References = []model.SpanRef{model.NewChildOfRef(batchTraceID, model.NewSpanID(parent))}
+		}
+		return span
+	}
+	trace := &model.Trace{Spans: []*model.Span{span(1, 0, "batch", "job", 0)}}
+	for i := uint64(0); i < 5; i++ {
+		trace.Spans = append(trace.Spans,
+			span(10+i, 1, "batch", "query", time.Duration(i)*time.Millisecond),
+			span(20+i, 10+i, "db", "connect", time.Duration(i)*time.Millisecond),
+		)
+	}
+	trace.Spans = append(trace.Spans, span(30, 1, "batch", "publish", 10*time.Millisecond))
+	return trace
+}
+
+func initializeLimitedTrace(limits TraceLimitOptions) (*QueryService, *spanstoremocks.Reader) {
+	readMock := &spanstoremocks.Reader{}
+	qs := NewQueryService(readMock, nil, QueryServiceOptions{TraceLimits: limits})
+	readMock.On("GetTrace", mock.Anything, batchTraceID).Return(batchTrace(), nil)
+	return qs, readMock
+}
+
+func TestGetLimitedTrace(t *testing.T) {
+	testCases := []struct {
+		name    string
+		limits  TraceLimitOptions
+		spans   int
+		summary *TraceSummary
+		spanIDs []model.SpanID
+	}{
+		{
+			name:   "no limit",
+			limits: TraceLimitOptions{MinSiblings: 3},
+			spans:  12,
+		},
+		{
+			name:   "within limit",
+			limits: TraceLimitOptions{MaxSpans: 12, MinSiblings: 3},
+			spans:  12,
+		},
+		{
+			name:    "collapsed",
+			limits:  TraceLimitOptions{MaxSpans: 5, MinSiblings: 3},
+			spans:   3,
+			summary: &TraceSummary{TotalSpans: 12, ReturnedSpans: 3, CollapsedSpans: 9},
+		},
+		{
+			name:   "truncated",
+			limits: TraceLimitOptions{MaxSpans: 4},
+			spans:  4,
+			summary: &TraceSummary{TotalSpans: 12, ReturnedSpans: 4, Omitted: []OmittedSpans{
+				{ServiceName: "db", OperationName: "connect", Spans: 5},
+				{ServiceName: "batch", OperationName: "query", Spans: 2},
+				{ServiceName: "batch", OperationName: "publish", Spans: 1},
+			}},
+			spanIDs: []model.SpanID{1, 10, 11, 12},
+		},
+		{
+			name:    "collapsed and truncated",
+			limits:  TraceLimitOptions{MaxSpans: 2, MinSiblings: 3},
+			spans:   2,
+			summary: &TraceSummary{TotalSpans: 12, ReturnedSpans: 2, CollapsedSpans: 9, Omitted: []OmittedSpans{{ServiceName: "batch", OperationName: "publish", Spans: 1}}},
+			spanIDs: []model.SpanID{1, 10},
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.name, func(t *testing.T) {
+			qs, _ := initializeLimitedTrace(testCase.limits)
+			trace, summary, err := qs.GetLimitedTrace(context.Background(), batchTraceID)
+			require.NoError(t, err)
+			assert.Len(t, trace.Spans, testCase.spans)
+			assert.Equal(t, testCase.summary, summary)
+			if testCase.spanIDs != nil {
+				spanIDs := make([]model.SpanID, len(trace.Spans))
+				for i, span := range trace.Spans {
+					spanIDs[i] = span.SpanID
+				}
+				assert.Equal(t, testCase.spanIDs, spanIDs)
+			}
+		})
+	}
+}
+
+// streamingReader streams the batch trace by parts of 4 spans, counting the parts read.
+type streamingReader struct {
+	spanstoremocks.Reader
+	parts int
+}
+
+func (r *streamingReader) StreamTrace(ctx context.Context, traceID model.TraceID, fn func(part *model.Trace) bool) error {
+	spans := batchTrace().Spans
+	for i := 0; i < len(spans); i += 4 {
+		r.parts++
+		if !fn(&model.Trace{Spans: spans[i : i+4]}) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestGetLimitedTraceStreamed(t *testing.T) {
+	reader := &streamingReader{}
+	qs := NewQueryService(reader, nil, QueryServiceOptions{TraceLimits: TraceLimitOptions{MaxSpans: 2, MaxReadSpans: 6}})
+	trace, summary, err := qs.GetLimitedTrace(context.Background(), batchTraceID)
+	require.NoError(t, err)
+	assert.Len(t, trace.Spans, 2)
+	assert.Equal(t, 2, reader.parts)
+	assert.True(t, summary.Truncated)
+	assert.Equal(t, 6, summary.TotalSpans)
+
+	// the trace is read up to 10 times the span limit by default
+	reader = &streamingReader{}
+	qs = NewQueryService(reader, nil, QueryServiceOptions{TraceLimits: TraceLimitOptions{MaxSpans: 2}})
+	_, summary, err = qs.GetLimitedTrace(context.Background(), batchTraceID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, reader.parts)
+	assert.False(t, summary.Truncated)
+	assert.Equal(t, 12, summary.TotalSpans)
+}
+
+func TestGetBoundedTrace(t *testing.T) {
+	qs, _ := initializeLimitedTrace(TraceLimitOptions{})
+	trace, err := qs.GetBoundedTrace(context.Background(), batchTraceID)
+	require.NoError(t, err)
+	assert.Len(t, trace.Spans, 12)
+
+	// the trace is not reduced within the read limit
+	reader := &streamingReader{}
+	qs = NewQueryService(reader, nil, QueryServiceOptions{TraceLimits: TraceLimitOptions{MaxSpans: 2, MaxReadSpans: 12}})
+	trace, err = qs.GetBoundedTrace(context.Background(), batchTraceID)
+	require.NoError(t, err)
+	assert.Len(t, trace.Spans, 12)
+
+	reader = &streamingReader{}
+	qs = NewQueryService(reader, nil, QueryServiceOptions{TraceLimits: TraceLimitOptions{MaxSpans: 2, MaxReadSpans: 6}})
+	_, err = qs.GetBoundedTrace(context.Background(), batchTraceID)
+	assert.Equal(t, ErrTraceTooLarge, err)
+	assert.Equal(t, 2, reader.parts)
+
+	errStorage := errors.New("storage error")
+	readMock := &spanstoremocks.Reader{}
+	readMock.On("GetTrace", mock.Anything, batchTraceID).Return(nil, errStorage)
+	qs = NewQueryService(readMock, nil, QueryServiceOptions{TraceLimits: TraceLimitOptions{MaxSpans: 2}})
+	_, err = qs.GetBoundedTrace(context.Background(), batchTraceID)
+	assert.Equal(t, errStorage, err)
+}
+
+func TestGetLimitedTraceFailure(t *testing.T) {
+	errStorage := errors.New("storage error")
+	qs, readMock, _ := initializeTestService()
+	readMock.On("GetTrace", mock.Anything, batchTraceID).Return(nil, errStorage)
+	_, _, err := qs.GetLimitedTrace(context.Background(), batchTraceID)
+	assert.Equal(t, errStorage, err)
+}
+
+func TestBreadthFirst(t *testing.T) {
+	traceID := model.NewTraceID(0, 1)
+	start := time.Unix(100, 0)
+	spans := []*model.Span{
+		// a cycle
+		{TraceID: traceID, SpanID: 5, References: []model.SpanRef{model.NewChildOfRef(traceID, 6)}},
+		{TraceID: traceID, SpanID: 6, References: []model.SpanRef{model.NewChildOfRef(traceID, 5)}},
+		{TraceID: traceID, SpanID: 3, StartTime: start.Add(2), References: []model.SpanRef{model.NewChildOfRef(traceID, 1)}},
+		{TraceID: traceID, SpanID: 4, StartTime: start.Add(1), References: []model.SpanRef{model.NewChildOfRef(traceID, 2)}},
+		{TraceID: traceID, SpanID: 2, StartTime: start.Add(1), References: []model.SpanRef{model.NewChildOfRef(traceID, 1)}},
+		{TraceID: traceID, SpanID: 1, StartTime: start},
+	}
+	ordered := breadthFirst(spans)
+	spanIDs := make([]model.SpanID, len(ordered))
+	for i, span := range ordered {
+		spanIDs[i] = span.SpanID
+	}
+	assert.Equal(t, []model.SpanID{1, 2, 3, 4, 5, 6}, spanIDs)
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querysvc
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// UploadedNamespace prefixes the service names of the spans imported into the upload store,
+// so that they can be told apart from the spans of the configured storage.
+const UploadedNamespace = "uploaded/"
+
+// IsUploadedService returns true if the service belongs to the spans imported into the upload store.
+func IsUploadedService(service string) bool {
+	return strings.HasPrefix(service, UploadedNamespace)
+}
+
+// UploadStore is the scratch store of the imported spans, e.g. a memory store bounded in traces.
+type UploadStore interface {
+	spanstore.Reader
+	spanstore.Writer
+}
+
+// uploadedReader serves the services of the uploaded namespace from the upload store
+// and all other services from the span reader.
+type uploadedReader struct {
+	spanReader spanstore.Reader
+	store      UploadStore
+}
+
+func newUploadedReader(spanReader spanstore.Reader, store UploadStore) *uploadedReader {
+	return &uploadedReader{spanReader: spanReader, store: store}
+}
+
+func (r *uploadedReader) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	trace, err := r.spanReader.GetTrace(ctx, traceID)
+	if err == spanstore.ErrTraceNotFound {
+		return r.store.GetTrace(ctx, traceID)
+	}
+	return trace, err
+}
+
+// StreamTrace implements spanstore.TraceStreamer, streaming the trace from the span reader or else from the upload store.
+func (r *uploadedReader) StreamTrace(ctx context.Context, traceID model.TraceID, fn func(part *model.Trace) bool) error {
+	err := spanstore.StreamTrace(ctx, r.spanReader, traceID, fn)
+	if err == spanstore.ErrTraceNotFound {
+		return spanstore.StreamTrace(ctx, r.store, traceID, fn)
+	}
+	return err
+}
+
+func (r *uploadedReader) GetServices(ctx context.Context) ([]string, error) {
+	services, err := r.spanReader.GetServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := r.store.GetServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	services = append(services, uploaded...)
+	sort.Strings(services)
+	return services, nil
+}
+
+func (r *uploadedReader) GetOperations(
+	ctx context.Context,
+	query spanstore.OperationQueryParameters,
+) ([]spanstore.Operation, error) {
+	if IsUploadedService(query.ServiceName) {
+		return r.store.GetOperations(ctx, query)
+	}
+	return r.spanReader.GetOperations(ctx, query)
+}
+
+func (r *uploadedReader) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	storageQuery, uploadedQuery := splitUploadedServices(query)
+	var traces []*model.Trace
+	if storageQuery != nil {
+		found, err := r.spanReader.FindTraces(ctx, storageQuery)
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, found...)
+	}
+	if uploadedQuery != nil {
+		found, err := r.store.FindTraces(ctx, uploadedQuery)
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, found...)
+	}
+	if storageQuery != nil && uploadedQuery != nil {
+		spanstore.SortTracesForPaging(query, traces)
+	}
+	if query.NumTraces > 0 && len(traces) > query.NumTraces {
+		traces = traces[:query.NumTraces]
+	}
+	return traces, nil
+}
+
+func (r *uploadedReader) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	storageQuery, uploadedQuery := splitUploadedServices(query)
+	var traceIDs []model.TraceID
+	if storageQuery != nil {
+		found, err := r.spanReader.FindTraceIDs(ctx, storageQuery)
+		if err != nil {
+			return nil, err
+		}
+		traceIDs = append(traceIDs, found...)
+	}
+	if uploadedQuery != nil {
+		found, err := r.store.FindTraceIDs(ctx, uploadedQuery)
+		if err != nil {
+			return nil, err
+		}
+		traceIDs = append(traceIDs, found...)
+	}
+	if query.NumTraces > 0 && len(traceIDs) > query.NumTraces {
+		traceIDs = traceIDs[:query.NumTraces]
+	}
+	return traceIDs, nil
+}
+
+// SupportsTagPredicate implements spanstore.TagPredicateSupport. The upload store evaluates all tag predicates,
+// so the support is that of the span reader.
+func (r *uploadedReader) SupportsTagPredicate(predicate spanstore.TagPredicate) bool {
+	support, ok := r.spanReader.(spanstore.TagPredicateSupport)
+	return ok && support.SupportsTagPredicate(predicate)
+}
+
+// SupportsTracePredicates implements spanstore.TracePredicateSupport. The upload store evaluates all trace predicates,
+// so the support is that of the span reader.
+func (r *uploadedReader) SupportsTracePredicates(predicates *spanstore.TracePredicates) bool {
+	support, ok := r.spanReader.(spanstore.TracePredicateSupport)
+	return ok && support.SupportsTracePredicates(predicates)
+}
+
+// splitUploadedServices returns the query for the span reader and the query for the upload store,
+// either of which is nil if none of the services of the query belong to it.
+// A query without services only searches the span reader.
+func splitUploadedServices(
+	query *spanstore.TraceQueryParameters,
+) (*spanstore.TraceQueryParameters, *spanstore.TraceQueryParameters) {
+	var storageServices, uploadedServices []string
+	for _, service := range query.Services() {
+		if IsUploadedService(service) {
+			uploadedServices = append(uploadedServices, service)
+		} else {
+			storageServices = append(storageServices, service)
+		}
+	}
+	if len(uploadedServices) == 0 {
+		return query, nil
+	}
+	if len(storageServices) == 0 {
+		return nil, query
+	}
+	return withServices(query, storageServices), withServices(query, uploadedServices)
+}
+
+func withServices(query *spanstore.TraceQueryParameters, services []string) *spanstore.TraceQueryParameters {
+	q := *query
+	q.ServiceName = ""
+	q.ServiceNames = services
+	return &q
+}
+
+// moveToUploadedNamespace prefixes the service names of the spans with UploadedNamespace.
+func moveToUploadedNamespace(spans []*model.Span) {
+	for _, span := range spans {
+		if span.Process == nil {
+			span.Process = &model.Process{}
+		}
+		// processes can be shared by the spans of a trace
+		if !IsUploadedService(span.Process.ServiceName) {
+			span.Process.ServiceName = UploadedNamespace + span.Process.ServiceName
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+func initializeTestServiceWithUploadStore(t *testing.T) (*QueryService, *spanstoremocks.Reader, model.TraceID) {
+	readStorage := &spanstoremocks.Reader{}
+	qs := NewQueryService(readStorage, &depsmocks.Reader{}, QueryServiceOptions{UploadStore: memory.NewStore()})
+
+	traceID := model.NewTraceID(0, 42)
+	process := &model.Process{ServiceName: "frontend"}
+	spans := []*model.Span{
+		{TraceID: traceID, SpanID: model.NewSpanID(1), OperationName: "GET", Process: process, StartTime: time.Now()},
+		{TraceID: traceID, SpanID: model.NewSpanID(2), OperationName: "SELECT", Process: process, StartTime: time.Now()},
+	}
+	traceIDs, err := qs.ImportSpans(context.Background(), spans, ImportTargetUploaded)
+	require.NoError(t, err)
+	assert.Equal(t, []model.TraceID{traceID}, traceIDs)
+	assert.Equal(t, "uploaded/frontend", process.ServiceName)
+	return qs, readStorage, traceID
+}
+
+func TestUploadedGetTrace(t *testing.T) {
+	qs, readMock, traceID := initializeTestServiceWithUploadStore(t)
+	readMock.On("GetTrace", mock.Anything, traceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+
+	trace, err := qs.GetTrace(context.Background(), traceID)
+	require.NoError(t, err)
+	assert.Len(t, trace.Spans, 2)
+}
+
+func TestUploadedGetServicesAndOperations(t *testing.T) {
+	qs, readMock, _ := initializeTestServiceWithUploadStore(t)
+	readMock.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil).Once()
+	readMock.On("GetOperations", mock.Anything, spanstore.OperationQueryParameters{ServiceName: "frontend"}).
+		Return([]spanstore.Operation{{Name: "POST"}}, nil).Once()
+
+	services, err := qs.GetServices(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frontend", "uploaded/frontend"}, services)
+
+	operations, err := qs.GetOperations(context.Background(), spanstore.OperationQueryParameters{ServiceName: "frontend"})
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.Operation{{Name: "POST"}}, operations)
+
+	operations, err = qs.GetOperations(context.Background(), spanstore.OperationQueryParameters{ServiceName: "uploaded/frontend"})
+	require.NoError(t, err)
+	assert.Len(t, operations, 2)
+}
+
+func TestUploadedFindTraces(t *testing.T) {
+	qs, readMock, traceID := initializeTestServiceWithUploadStore(t)
+	storageTrace := &model.Trace{Spans: []*model.Span{{
+		TraceID: model.NewTraceID(0, 7),
+		Process: &model.Process{ServiceName: "frontend"},
+	}}}
+	readMock.On("FindTraces", mock.Anything, mock.MatchedBy(func(query *spanstore.TraceQueryParameters) bool {
+		return assert.ObjectsAreEqual([]string{"frontend"}, query.Services())
+	})).Return([]*model.Trace{storageTrace}, nil)
+
+	uploadedQuery := &spanstore.TraceQueryParameters{
+		ServiceName:  "uploaded/frontend",
+		StartTimeMin: time.Now().Add(-time.Hour),
+		StartTimeMax: time.Now().Add(time.Hour),
+		NumTraces:    10,
+	}
+	traces, err := qs.FindTraces(context.Background(), uploadedQuery)
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+	assert.Equal(t, traceID, traces[0].Spans[0].TraceID)
+	readMock.AssertNotCalled(t, "FindTraces", mock.Anything, mock.Anything)
+
+	mixedQuery := *uploadedQuery
+	mixedQuery.ServiceNames = []string{"frontend"}
+	traces, err = qs.FindTraces(context.Background(), &mixedQuery)
+	require.NoError(t, err)
+	assert.Len(t, traces, 2)
+
+	// the merged traces are limited to the latest ones
+	mixedQuery.NumTraces = 1
+	traces, err = qs.FindTraces(context.Background(), &mixedQuery)
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+	assert.Equal(t, traceID, traces[0].Spans[0].TraceID)
+}
+
+func TestSplitUploadedServices(t *testing.T) {
+	query := &spanstore.TraceQueryParameters{ServiceName: "frontend"}
+	storageQuery, uploadedQuery := splitUploadedServices(query)
+	assert.Equal(t, query, storageQuery)
+	assert.Nil(t, uploadedQuery)
+
+	query = &spanstore.TraceQueryParameters{ServiceNames: []string{"uploaded/frontend", "uploaded/api"}}
+	storageQuery, uploadedQuery = splitUploadedServices(query)
+	assert.Nil(t, storageQuery)
+	assert.Equal(t, query, uploadedQuery)
+
+	query = &spanstore.TraceQueryParameters{ServiceName: "frontend", ServiceNames: []string{"uploaded/api"}, NumTraces: 5}
+	storageQuery, uploadedQuery = splitUploadedServices(query)
+	assert.Equal(t, &spanstore.TraceQueryParameters{ServiceNames: []string{"frontend"}, NumTraces: 5}, storageQuery)
+	assert.Equal(t, &spanstore.TraceQueryParameters{ServiceNames: []string{"uploaded/api"}, NumTraces: 5}, uploadedQuery)
+}
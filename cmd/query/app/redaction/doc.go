@@ -0,0 +1,21 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redaction masks or hashes sensitive tag and log field values of the spans served by the query service,
+// depending on the identity of the caller.
+//
+// The identity is taken from a header that a trusted authenticating proxy must set, and strip from or verify in
+// the requests it forwards. The gRPC metadata is only trusted if configured, as gRPC callers often bypass the proxy.
+// Since the span readers match the raw values, the searches of redacted tag values are rejected.
+package redaction
@@ -0,0 +1,55 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redaction
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+)
+
+type identityContextKey struct{}
+
+// ContextWithIdentity returns a context holding the identity of the caller.
+func ContextWithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityContextKey{}, identity)
+}
+
+// Identity returns the identity of the caller, set in the context by ContextWithIdentity,
+// or else given by the identity header in the gRPC metadata if it is trusted. It is empty if unknown.
+func (r *Redactor) Identity(ctx context.Context) string {
+	if identity, ok := ctx.Value(identityContextKey{}).(string); ok {
+		return identity
+	}
+	if r.identityHeader == "" || !r.trustGRPCIdentity {
+		return ""
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(r.identityHeader); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// IdentityHandler sets the identity of the caller given by the header in the context of the requests.
+// The header must be set by a trusted authenticating proxy, which must also strip it from, or verify it in,
+// the requests it forwards: otherwise any caller can claim the identity of an exempt caller.
+func IdentityHandler(header string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(ContextWithIdentity(r.Context(), r.Header.Get(header))))
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redaction
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestIdentity(t *testing.T) {
+	redactor, err := NewRedactor(nil, Options{IdentityHeader: "X-Forwarded-User", TrustGRPCIdentity: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "", redactor.Identity(context.Background()))
+	assert.Equal(t, "alice", redactor.Identity(ContextWithIdentity(context.Background(), "alice")))
+	grpcCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-user", "bob"))
+	assert.Equal(t, "bob", redactor.Identity(grpcCtx))
+
+	// the gRPC metadata is not trusted by default
+	redactor, err = NewRedactor(nil, Options{IdentityHeader: "X-Forwarded-User"})
+	require.NoError(t, err)
+	assert.Equal(t, "", redactor.Identity(grpcCtx))
+
+	redactor, err = NewRedactor(nil, Options{TrustGRPCIdentity: true})
+	require.NoError(t, err)
+	assert.Equal(t, "", redactor.Identity(grpcCtx))
+}
+
+func TestIdentityHandler(t *testing.T) {
+	redactor, err := NewRedactor(nil, Options{})
+	require.NoError(t, err)
+	var identity string
+	handler := IdentityHandler("X-Forwarded-User", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity = redactor.Identity(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/traces", nil)
+	req.Header.Set("X-Forwarded-User", "alice")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "alice", identity)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/traces", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "", identity)
+}
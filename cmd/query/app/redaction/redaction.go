@@ -0,0 +1,356 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redaction
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	// ActionMask replaces the values with Masked.
+	ActionMask = "mask"
+	// ActionHash replaces the values with their HMAC-SHA256 keyed by Options.HashKey, so that equal values
+	// can still be correlated, but guessed values cannot be confirmed without the key.
+	ActionHash = "hash"
+
+	// Masked is the value of the masked tags and log fields.
+	Masked = "[redacted]"
+)
+
+// Rule selects the tag and log field values to redact. A value is redacted if its key is one of the Keys
+// and its string representation matches the ValuePattern, either of which can be left empty.
+type Rule struct {
+	// Services limit the rule to the spans of these services, all if empty.
+	Services []string `json:"services"`
+	// Keys are the tag and log field keys whose values are redacted.
+	Keys []string `json:"keys"`
+	// ValuePattern is a regular expression matching the values to redact.
+	ValuePattern string `json:"valuePattern"`
+	// Action is ActionMask, the default, or ActionHash.
+	Action string `json:"action"`
+	// Exempt are the caller identities allowed to see the raw values.
+	Exempt []string `json:"exempt"`
+}
+
+// ErrRedactedSearch is returned by CheckQuery for a query searching values that are redacted for the caller.
+var ErrRedactedSearch = errors.New("cannot search the tag values redacted for the caller")
+
+// Options configure a Redactor.
+type Options struct {
+	// IdentityHeader is the HTTP header, or gRPC metadata, identifying the callers.
+	IdentityHeader string
+	// TrustGRPCIdentity takes the identity of gRPC callers from the IdentityHeader metadata, which any caller
+	// can set: it must only be enabled if a trusted authenticating proxy strips or overwrites it.
+	TrustGRPCIdentity bool
+	// HashKey is the secret key of the hashes of ActionHash, required if any rule hashes.
+	HashKey []byte
+}
+
+// rules is the format of the rules file.
+type rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadRules reads the rules from a JSON file of the form {"rules": [...]}.
+func LoadRules(path string) ([]Rule, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read redaction rules: %w", err)
+	}
+	var r rules
+	if err := json.Unmarshal(bytes, &r); err != nil {
+		return nil, fmt.Errorf("cannot parse redaction rules: %w", err)
+	}
+	return r.Rules, nil
+}
+
+// LoadHashKey reads the secret key of the hashes from a file, ignoring its trailing new lines.
+func LoadHashKey(path string) ([]byte, error) {
+	key, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read redaction hash key: %w", err)
+	}
+	key = bytes.TrimRight(key, "\r\n")
+	if len(key) == 0 {
+		return nil, fmt.Errorf("redaction hash key file %s is empty", path)
+	}
+	return key, nil
+}
+
+type compiledRule struct {
+	services map[string]struct{}
+	keys     map[string]struct{}
+	pattern  *regexp.Regexp
+	hash     bool
+	exempt   map[string]struct{}
+}
+
+// Redactor redacts the traces according to the rules that apply to the caller.
+type Redactor struct {
+	rules             []compiledRule
+	identityHeader    string
+	trustGRPCIdentity bool
+	hashKey           []byte
+}
+
+// NewRedactor creates a Redactor. The identity of HTTP callers is set in the context by IdentityHandler,
+// that of gRPC callers is taken from the identity header metadata only if options.TrustGRPCIdentity is set.
+func NewRedactor(rules []Rule, options Options) (*Redactor, error) {
+	r := &Redactor{
+		identityHeader:    options.IdentityHeader,
+		trustGRPCIdentity: options.TrustGRPCIdentity,
+		hashKey:           options.HashKey,
+	}
+	for i, rule := range rules {
+		if len(rule.Keys) == 0 && rule.ValuePattern == "" {
+			return nil, fmt.Errorf("redaction rule %d has neither keys nor a value pattern", i)
+		}
+		c := compiledRule{
+			services: toSet(rule.Services),
+			keys:     toSet(rule.Keys),
+			exempt:   toSet(rule.Exempt),
+		}
+		switch rule.Action {
+		case "", ActionMask:
+		case ActionHash:
+			if len(options.HashKey) == 0 {
+				return nil, fmt.Errorf("redaction rule %d hashes the values but no hash key is configured", i)
+			}
+			c.hash = true
+		default:
+			return nil, fmt.Errorf("redaction rule %d has an invalid action %q, expecting %q or %q", i, rule.Action, ActionMask, ActionHash)
+		}
+		if rule.ValuePattern != "" {
+			pattern, err := regexp.Compile(rule.ValuePattern)
+			if err != nil {
+				return nil, fmt.Errorf("redaction rule %d has an invalid value pattern: %w", i, err)
+			}
+			c.pattern = pattern
+		}
+		r.rules = append(r.rules, c)
+	}
+	return r, nil
+}
+
+// Adjuster returns an adjuster that redacts the traces for the caller identity, which is empty if unknown.
+// The spans with redacted values are replaced by copies rather than modified, since the span reader
+// can share them with its own storage, e.g. the memory store.
+func (r *Redactor) Adjuster(identity string) adjuster.Adjuster {
+	rules := r.rulesFor(identity)
+	return adjuster.Func(func(trace *model.Trace) (*model.Trace, error) {
+		if len(rules) == 0 {
+			return trace, nil
+		}
+		processes := make(map[*model.Process]*model.Process)
+		redacted := &model.Trace{
+			Spans:    make([]*model.Span, len(trace.Spans)),
+			Warnings: trace.Warnings,
+		}
+		for i, span := range trace.Spans {
+			redacted.Spans[i] = redactSpan(span, rules, r.hashKey, processes)
+		}
+		return redacted, nil
+	})
+}
+
+// CheckQuery returns an error wrapping ErrRedactedSearch if the query searches tag values that are redacted
+// for the caller identity, since the span reader matches the raw values: the traces it finds would reveal them.
+// The services of the rules are ignored, as a query can match the tags of any span of a trace.
+// The tags with a key covered by a rule can only be searched for their existence. If a rule without keys applies,
+// the values of the other tags can only be compared for equality to values that the rule does not redact.
+func (r *Redactor) CheckQuery(identity string, query *spanstore.TraceQueryParameters) error {
+	rules := r.rulesFor(identity)
+	if len(rules) == 0 {
+		return nil
+	}
+	for key, value := range query.Tags {
+		if !searchable(rules, key, spanstore.TagOpEqual, value) {
+			return fmt.Errorf("%w: %s", ErrRedactedSearch, key)
+		}
+	}
+	groups := query.TagPredicates
+	if query.TracePredicates != nil {
+		groups = append(groups[:len(groups):len(groups)], query.TracePredicates.RootTagPredicates...)
+	}
+	for _, group := range groups {
+		for _, predicate := range group {
+			if !searchable(rules, predicate.Key, predicate.Operator, predicate.Value) {
+				return fmt.Errorf("%w: %s", ErrRedactedSearch, predicate.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// RedactValue returns the value of the tag with the key of a span of the service, redacted for the caller identity,
+// e.g. for the tag values aggregated outside of the traces.
+func (r *Redactor) RedactValue(identity string, service string, key string, value string) string {
+	rules := rulesForService(r.rulesFor(identity), service)
+	if redacted, ok := redactValue(model.String(key, value), rules, r.hashKey); ok {
+		return redacted
+	}
+	return value
+}
+
+// rulesFor returns the rules that apply to the caller identity, i.e. those it is not exempt from.
+func (r *Redactor) rulesFor(identity string) []compiledRule {
+	var rules []compiledRule
+	for _, rule := range r.rules {
+		if _, ok := rule.exempt[identity]; !ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// searchable returns true if comparing the values of the tags with the key cannot reveal redacted values.
+func searchable(rules []compiledRule, key string, op spanstore.TagOperator, value string) bool {
+	if op == spanstore.TagOpExists || op == spanstore.TagOpNotExists {
+		return true
+	}
+	for _, rule := range rules {
+		if len(rule.keys) > 0 {
+			if _, ok := rule.keys[key]; ok {
+				return false
+			}
+			continue
+		}
+		if (op != spanstore.TagOpEqual && op != spanstore.TagOpNotEqual) || rule.pattern.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func redactSpan(span *model.Span, rules []compiledRule, hashKey []byte, processes map[*model.Process]*model.Process) *model.Span {
+	var service string
+	if span.Process != nil {
+		service = span.Process.ServiceName
+	}
+	applicable := rulesForService(rules, service)
+	if len(applicable) == 0 {
+		return span
+	}
+	copied := *span
+	changed := false
+	if tags, ok := redactKeyValues(span.Tags, applicable, hashKey); ok {
+		copied.Tags = tags
+		changed = true
+	}
+	logsCopied := false
+	for i, log := range span.Logs {
+		fields, ok := redactKeyValues(log.Fields, applicable, hashKey)
+		if !ok {
+			continue
+		}
+		if !logsCopied {
+			copied.Logs = append([]model.Log(nil), span.Logs...)
+			logsCopied = true
+		}
+		copied.Logs[i].Fields = fields
+		changed = true
+	}
+	if span.Process != nil {
+		process, ok := processes[span.Process]
+		if !ok {
+			process = span.Process
+			if tags, ok := redactKeyValues(span.Process.Tags, applicable, hashKey); ok {
+				process = &model.Process{ServiceName: span.Process.ServiceName, Tags: tags}
+			}
+			processes[span.Process] = process
+		}
+		if process != span.Process {
+			copied.Process = process
+			changed = true
+		}
+	}
+	if !changed {
+		return span
+	}
+	return &copied
+}
+
+// rulesForService returns the rules that apply to the spans of the service.
+func rulesForService(rules []compiledRule, service string) []compiledRule {
+	var applicable []compiledRule
+	for _, rule := range rules {
+		if _, ok := rule.services[service]; ok || len(rule.services) == 0 {
+			applicable = append(applicable, rule)
+		}
+	}
+	return applicable
+}
+
+// redactKeyValues returns a redacted copy of the key values and true if any of them is redacted.
+func redactKeyValues(kvs []model.KeyValue, rules []compiledRule, hashKey []byte) ([]model.KeyValue, bool) {
+	var redacted []model.KeyValue
+	for i, kv := range kvs {
+		value, ok := redactValue(kv, rules, hashKey)
+		if !ok {
+			continue
+		}
+		if redacted == nil {
+			redacted = append([]model.KeyValue(nil), kvs...)
+		}
+		redacted[i] = model.String(kv.Key, value)
+	}
+	return redacted, redacted != nil
+}
+
+// redactValue returns the redacted value of the key value, masked if any of the rules matching it masks.
+func redactValue(kv model.KeyValue, rules []compiledRule, hashKey []byte) (string, bool) {
+	matched, mask := false, false
+	for _, rule := range rules {
+		if len(rule.keys) > 0 {
+			if _, ok := rule.keys[kv.Key]; !ok {
+				continue
+			}
+		}
+		if rule.pattern != nil && !rule.pattern.MatchString(kv.AsString()) {
+			continue
+		}
+		matched = true
+		mask = mask || !rule.hash
+	}
+	if !matched {
+		return "", false
+	}
+	if mask {
+		return Masked, true
+	}
+	mac := hmac.New(sha256.New, hashKey)
+	mac.Write([]byte(kv.AsString()))
+	return hex.EncodeToString(mac.Sum(nil)), true
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redaction
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var testHashKey = []byte("secret")
+
+func testTrace() *model.Trace {
+	frontend := &model.Process{
+		ServiceName: "frontend",
+		Tags:        []model.KeyValue{model.String("hostname", "host-1")},
+	}
+	billing := &model.Process{ServiceName: "billing"}
+	return &model.Trace{
+		Spans: []*model.Span{
+			{
+				SpanID:  model.NewSpanID(1),
+				Process: frontend,
+				Tags: []model.KeyValue{
+					model.String("user.email", "alice@example.com"),
+					model.String("http.url", "/login?token=secret"),
+					model.Int64("http.status_code", 200),
+				},
+				Logs: []model.Log{
+					{Fields: []model.KeyValue{model.String("event", "login")}},
+					{Fields: []model.KeyValue{model.String("message", "welcome alice@example.com")}},
+				},
+			},
+			{
+				SpanID:  model.NewSpanID(2),
+				Process: frontend,
+				Tags:    []model.KeyValue{model.String("user.email", "bob@example.com")},
+			},
+			{
+				SpanID:  model.NewSpanID(3),
+				Process: billing,
+				Tags:    []model.KeyValue{model.String("card", "4111-1111-1111-1111")},
+			},
+		},
+		Warnings: []string{"warning"},
+	}
+}
+
+func hash(value string) string {
+	mac := hmac.New(sha256.New, testHashKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestRedactor(t *testing.T) {
+	redactor, err := NewRedactor([]Rule{
+		{Keys: []string{"user.email"}, Action: ActionHash, Exempt: []string{"admin"}},
+		{ValuePattern: `[\w.]+@[\w.]+`, Exempt: []string{"admin", "support"}},
+		{Services: []string{"billing"}, Keys: []string{"card"}},
+		{Keys: []string{"hostname"}, Action: ActionHash},
+	}, Options{HashKey: testHashKey})
+	require.NoError(t, err)
+
+	trace := testTrace()
+	redacted, err := redactor.Adjuster("").Adjust(trace)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"warning"}, redacted.Warnings)
+	span := redacted.Spans[0]
+	// the mask of the value pattern wins over the hash of the key
+	assert.Equal(t, []model.KeyValue{
+		model.String("user.email", Masked),
+		model.String("http.url", "/login?token=secret"),
+		model.Int64("http.status_code", 200),
+	}, span.Tags)
+	assert.Equal(t, model.String("event", "login"), span.Logs[0].Fields[0])
+	assert.Equal(t, model.String("message", Masked), span.Logs[1].Fields[0])
+	assert.Equal(t, []model.KeyValue{model.String("hostname", hash("host-1"))}, span.Process.Tags)
+	// the redacted process is shared like the original one
+	assert.Same(t, span.Process, redacted.Spans[1].Process)
+	assert.Equal(t, model.String("card", Masked), redacted.Spans[2].Tags[0])
+
+	// the original trace is left untouched
+	assert.Equal(t, testTrace(), trace)
+
+	redacted, err = redactor.Adjuster("support").Adjust(testTrace())
+	require.NoError(t, err)
+	assert.Equal(t, model.String("user.email", hash("alice@example.com")), redacted.Spans[0].Tags[0])
+	assert.Equal(t, model.String("message", "welcome alice@example.com"), redacted.Spans[0].Logs[1].Fields[0])
+
+	redacted, err = redactor.Adjuster("admin").Adjust(testTrace())
+	require.NoError(t, err)
+	assert.Equal(t, model.String("user.email", "alice@example.com"), redacted.Spans[0].Tags[0])
+	assert.Equal(t, model.String("card", Masked), redacted.Spans[2].Tags[0])
+}
+
+func TestRedactorUnchangedSpans(t *testing.T) {
+	redactor, err := NewRedactor([]Rule{
+		{Services: []string{"billing"}, Keys: []string{"card"}, Exempt: []string{"admin"}},
+	}, Options{})
+	require.NoError(t, err)
+
+	trace := testTrace()
+	redacted, err := redactor.Adjuster("").Adjust(trace)
+	require.NoError(t, err)
+	assert.Same(t, trace.Spans[0], redacted.Spans[0])
+	assert.NotSame(t, trace.Spans[2], redacted.Spans[2])
+
+	redacted, err = redactor.Adjuster("admin").Adjust(trace)
+	require.NoError(t, err)
+	assert.Same(t, trace, redacted)
+}
+
+func TestRedactValue(t *testing.T) {
+	redactor, err := NewRedactor([]Rule{
+		{Keys: []string{"hostname"}, Action: ActionHash, Exempt: []string{"admin"}},
+		{Services: []string{"billing"}, Keys: []string{"client-uuid"}},
+	}, Options{HashKey: testHashKey})
+	require.NoError(t, err)
+
+	assert.Equal(t, hash("host-1"), redactor.RedactValue("", "frontend", "hostname", "host-1"))
+	assert.Equal(t, "host-1", redactor.RedactValue("admin", "frontend", "hostname", "host-1"))
+	assert.Equal(t, Masked, redactor.RedactValue("admin", "billing", "client-uuid", "uuid-1"))
+	assert.Equal(t, "uuid-1", redactor.RedactValue("", "frontend", "client-uuid", "uuid-1"))
+}
+
+func TestNewRedactorErrors(t *testing.T) {
+	testCases := []struct {
+		rule Rule
+		err  string
+	}{
+		{rule: Rule{Services: []string{"frontend"}}, err: "redaction rule 0 has neither keys nor a value pattern"},
+		{rule: Rule{Keys: []string{"k"}, Action: "drop"}, err: `redaction rule 0 has an invalid action "drop", expecting "mask" or "hash"`},
+		{rule: Rule{ValuePattern: "("}, err: "redaction rule 0 has an invalid value pattern: error parsing regexp: missing closing ): `(`"},
+		{rule: Rule{Keys: []string{"k"}, Action: ActionHash}, err: "redaction rule 0 hashes the values but no hash key is configured"},
+	}
+	for _, testCase := range testCases {
+		_, err := NewRedactor([]Rule{testCase.rule}, Options{})
+		assert.EqualError(t, err, testCase.err)
+	}
+}
+
+func TestCheckQuery(t *testing.T) {
+	redactor, err := NewRedactor([]Rule{
+		{Keys: []string{"user.email"}, Exempt: []string{"admin"}},
+		{ValuePattern: `[\w.]+@[\w.]+`, Exempt: []string{"admin", "support"}},
+	}, Options{})
+	require.NoError(t, err)
+
+	predicate := func(key string, op spanstore.TagOperator, value string) *spanstore.TraceQueryParameters {
+		p, err := spanstore.NewTagPredicate(key, op, value)
+		require.NoError(t, err)
+		return &spanstore.TraceQueryParameters{TagPredicates: []spanstore.TagPredicateGroup{{p}}}
+	}
+	testCases := []struct {
+		name       string
+		identity   string
+		query      *spanstore.TraceQueryParameters
+		searchable bool
+	}{
+		{name: "other key", query: &spanstore.TraceQueryParameters{Tags: map[string]string{"http.method": "GET"}}, searchable: true},
+		{name: "redacted key", query: &spanstore.TraceQueryParameters{Tags: map[string]string{"user.email": "x"}}},
+		{name: "redacted value", query: &spanstore.TraceQueryParameters{Tags: map[string]string{"message": "alice@example.com"}}},
+		{name: "existence", query: predicate("user.email", spanstore.TagOpExists, ""), searchable: true},
+		{name: "predicate on redacted key", query: predicate("user.email", spanstore.TagOpNotEqual, "x")},
+		{name: "prefix of any key", query: predicate("message", spanstore.TagOpPrefix, "welcome")},
+		{name: "unredacted value", query: predicate("message", spanstore.TagOpNotEqual, "welcome"), searchable: true},
+		{
+			name: "root predicate",
+			query: &spanstore.TraceQueryParameters{TracePredicates: &spanstore.TracePredicates{
+				RootTagPredicates: predicate("user.email", spanstore.TagOpEqual, "x").TagPredicates,
+			}},
+		},
+		{name: "partly exempt", identity: "support", query: predicate("message", spanstore.TagOpPrefix, "welcome"), searchable: true},
+		{name: "exempt", identity: "admin", query: predicate("user.email", spanstore.TagOpEqual, "x"), searchable: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := redactor.CheckQuery(testCase.identity, testCase.query)
+			if testCase.searchable {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrRedactedSearch))
+			}
+		})
+	}
+}
+
+func TestLoadHashKey(t *testing.T) {
+	file, err := ioutil.TempFile("", "redaction")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("secret\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	key, err := LoadHashKey(file.Name())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), key)
+
+	require.NoError(t, ioutil.WriteFile(file.Name(), []byte("\n"), 0600))
+	_, err = LoadHashKey(file.Name())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is empty")
+
+	_, err = LoadHashKey(file.Name() + ".missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot read redaction hash key")
+}
+
+func TestLoadRules(t *testing.T) {
+	file, err := ioutil.TempFile("", "redaction")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(`{"rules": [{"services": ["frontend"], "keys": ["user.email"], "action": "hash", "exempt": ["admin"]}]}`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	rules, err := LoadRules(file.Name())
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Services: []string{"frontend"}, Keys: []string{"user.email"}, Action: ActionHash, Exempt: []string{"admin"}},
+	}, rules)
+
+	require.NoError(t, ioutil.WriteFile(file.Name(), []byte("{"), 0600))
+	_, err = LoadRules(file.Name())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot parse redaction rules")
+
+	_, err = LoadRules(file.Name() + ".missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot read redaction rules")
+}
@@ -28,6 +28,8 @@ import (
 
 	"github.com/jaegertracing/jaeger/cmd/flags"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/cmd/query/app/redaction"
+	"github.com/jaegertracing/jaeger/cmd/query/app/zipkin"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
@@ -70,6 +72,10 @@ func createHTTPServer(querySvc *querysvc.QueryService, queryOpts *QueryOptions,
 	apiHandlerOptions := []HandlerOption{
 		HandlerOptions.Logger(logger),
 		HandlerOptions.Tracer(tracer),
+		HandlerOptions.TraceLinks(queryOpts.TraceLinks),
+	}
+	if len(queryOpts.MinClientVersions) > 0 {
+		apiHandlerOptions = append(apiHandlerOptions, HandlerOptions.MinClientVersions(queryOpts.MinClientVersions))
 	}
 	apiHandler := NewAPIHandler(
 		querySvc,
@@ -80,12 +86,16 @@ func createHTTPServer(querySvc *querysvc.QueryService, queryOpts *QueryOptions,
 	}
 
 	apiHandler.RegisterRoutes(r)
+	zipkin.NewAPIHandler(querySvc, logger, tracer).RegisterRoutes(r)
 	RegisterStaticHandler(r, logger, queryOpts)
 	var handler http.Handler = r
 	handler = additionalHeadersHandler(handler, queryOpts.AdditionalHeaders)
 	if queryOpts.BearerTokenPropagation {
 		handler = bearerTokenPropagationHandler(logger, handler)
 	}
+	if queryOpts.RedactionIdentityHeader != "" {
+		handler = redaction.IdentityHandler(queryOpts.RedactionIdentityHeader, handler)
+	}
 	handler = handlers.CompressHandler(handler)
 	recoveryHandler := recoveryhandler.NewRecoveryHandler(logger, true)
 	return &http.Server{
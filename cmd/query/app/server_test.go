@@ -56,7 +56,7 @@ func TestServer(t *testing.T) {
 	querySvc := querysvc.NewQueryService(spanReader, dependencyReader, querysvc.QueryServiceOptions{})
 
 	server := NewServer(flagsSvc, querySvc,
-		&QueryOptions{Port: ports.QueryHTTP, BearerTokenPropagation: true},
+		&QueryOptions{Port: ports.QueryHTTP, BearerTokenPropagation: true, RedactionIdentityHeader: "X-Forwarded-User"},
 		opentracing.NoopTracer{})
 	assert.NoError(t, server.Start())
 
@@ -0,0 +1,74 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/spanmetrics"
+)
+
+// spanMetrics are the RED metrics of an operation and span kind, with latencies in microseconds like the UI model.
+type spanMetrics struct {
+	OperationName string            `json:"operationName"`
+	SpanKind      string            `json:"spanKind"`
+	Dimensions    map[string]string `json:"dimensions,omitempty"`
+	Calls         uint64            `json:"calls"`
+	Errors        uint64            `json:"errors"`
+	// RequestRate is the number of calls per second over the lookback.
+	RequestRate float64 `json:"requestRate"`
+	// ErrorRate is the ratio of calls that failed.
+	ErrorRate float64         `json:"errorRate"`
+	P50       uint64          `json:"p50"`
+	P95       uint64          `json:"p95"`
+	P99       uint64          `json:"p99"`
+	Latency   []latencyBucket `json:"latency"`
+}
+
+// latencyBucket is the number of calls whose latency is at most LessOrEqual and above that of the previous
+// bucket. The calls slower than all buckets are in a last bucket without LessOrEqual.
+type latencyBucket struct {
+	LessOrEqual uint64 `json:"le,omitempty"`
+	Count       uint64 `json:"count"`
+}
+
+func newSpanMetricsResponse(series []spanmetrics.Series, lookback time.Duration) []spanMetrics {
+	result := make([]spanMetrics, len(series))
+	for i, s := range series {
+		m := spanMetrics{
+			OperationName: s.OperationName,
+			SpanKind:      s.SpanKind,
+			Dimensions:    s.Dimensions,
+			Calls:         s.Calls,
+			Errors:        s.Errors,
+			RequestRate:   float64(s.Calls) / lookback.Seconds(),
+			P50:           uint64(s.Percentile(50) / time.Microsecond),
+			P95:           uint64(s.Percentile(95) / time.Microsecond),
+			P99:           uint64(s.Percentile(99) / time.Microsecond),
+			Latency:       make([]latencyBucket, len(s.LatencyCounts)),
+		}
+		if s.Calls > 0 {
+			m.ErrorRate = float64(s.Errors) / float64(s.Calls)
+		}
+		for j, n := range s.LatencyCounts {
+			m.Latency[j].Count = n
+			if j < len(s.LatencyBuckets) {
+				m.Latency[j].LessOrEqual = uint64(s.LatencyBuckets[j] / time.Microsecond)
+			}
+		}
+		result[i] = m
+	}
+	return result
+}
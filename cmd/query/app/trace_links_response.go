@@ -0,0 +1,60 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/model"
+	ui "github.com/jaegertracing/jaeger/model/json"
+)
+
+// traceLinks are the references between the traces of a getTrace response with the linkDepth parameter.
+type traceLinks struct {
+	Links []traceLink `json:"links"`
+	// Truncated is true if referenced traces were not loaded because of the depth or span limits.
+	Truncated bool `json:"truncated"`
+}
+
+// traceLink is a reference of a span to a span of another trace, which is in the response if Loaded.
+type traceLink struct {
+	TraceID       ui.TraceID       `json:"traceID"`
+	SpanID        ui.SpanID        `json:"spanID"`
+	RefType       ui.ReferenceType `json:"refType"`
+	TargetTraceID ui.TraceID       `json:"targetTraceID"`
+	TargetSpanID  ui.SpanID        `json:"targetSpanID"`
+	Loaded        bool             `json:"loaded"`
+}
+
+func newTraceLinks(linked *querysvc.LinkedTraces) *traceLinks {
+	result := &traceLinks{
+		Links:     make([]traceLink, len(linked.Links)),
+		Truncated: linked.Truncated,
+	}
+	for i, link := range linked.Links {
+		refType := ui.ChildOf
+		if link.Reference.RefType == model.FollowsFrom {
+			refType = ui.FollowsFrom
+		}
+		result.Links[i] = traceLink{
+			TraceID:       ui.TraceID(link.TraceID.String()),
+			SpanID:        ui.SpanID(link.SpanID.String()),
+			RefType:       refType,
+			TargetTraceID: ui.TraceID(link.Reference.TraceID.String()),
+			TargetSpanID:  ui.SpanID(link.Reference.SpanID.String()),
+			Loaded:        link.Loaded,
+		}
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+)
+
+// traceSummary describes how the trace of a getTrace response was reduced to the span limit.
+type traceSummary struct {
+	TotalSpans     int            `json:"totalSpans"`
+	ReturnedSpans  int            `json:"returnedSpans"`
+	Truncated      bool           `json:"truncated"`
+	CollapsedSpans int            `json:"collapsedSpans"`
+	Omitted        []omittedSpans `json:"omitted"`
+}
+
+type omittedSpans struct {
+	ServiceName   string `json:"serviceName"`
+	OperationName string `json:"operationName"`
+	Spans         int    `json:"spans"`
+}
+
+func newTraceSummary(summary *querysvc.TraceSummary) *traceSummary {
+	if summary == nil {
+		return nil
+	}
+	result := &traceSummary{
+		TotalSpans:     summary.TotalSpans,
+		ReturnedSpans:  summary.ReturnedSpans,
+		Truncated:      summary.Truncated,
+		CollapsedSpans: summary.CollapsedSpans,
+		Omitted:        make([]omittedSpans, len(summary.Omitted)),
+	}
+	for i, omitted := range summary.Omitted {
+		result.Omitted[i] = omittedSpans{
+			ServiceName:   omitted.ServiceName,
+			OperationName: omitted.OperationName,
+			Spans:         omitted.Spans,
+		}
+	}
+	return result
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"encoding/json"
+	"net"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/swagger-gen/models"
+)
+
+const (
+	// ipTagName is the process tag holding the IP address of the host, see model/converter/thrift/zipkin.
+	ipTagName = "ip"
+	// eventLogFieldKey is the log field that holds the value of a Zipkin annotation.
+	eventLogFieldKey = "event"
+)
+
+var spanKinds = map[string]string{
+	string(ext.SpanKindRPCClientEnum): "CLIENT",
+	string(ext.SpanKindRPCServerEnum): "SERVER",
+	string(ext.SpanKindProducerEnum):  "PRODUCER",
+	string(ext.SpanKindConsumerEnum):  "CONSUMER",
+}
+
+// FromDomain converts the spans of a trace into the Zipkin v2 model.
+func FromDomain(trace *model.Trace) models.ListOfSpans {
+	spans := make(models.ListOfSpans, 0, len(trace.Spans))
+	for _, span := range trace.Spans {
+		spans = append(spans, spanFromDomain(span))
+	}
+	return spans
+}
+
+func spanFromDomain(span *model.Span) *models.Span {
+	traceID := span.TraceID.String()
+	spanID := span.SpanID.String()
+	zSpan := &models.Span{
+		TraceID:   &traceID,
+		ID:        &spanID,
+		Name:      span.OperationName,
+		Timestamp: int64(model.TimeAsEpochMicroseconds(span.StartTime)),
+		Duration:  int64(model.DurationAsMicroseconds(span.Duration)),
+		Debug:     span.Flags.IsDebug(),
+	}
+	if parentID := span.ParentSpanID(); parentID != 0 {
+		zSpan.ParentID = parentID.String()
+	}
+	if span.Process != nil {
+		zSpan.LocalEndpoint = &models.Endpoint{ServiceName: span.Process.ServiceName}
+		if ip, ok := model.KeyValues(span.Process.Tags).FindByKey(ipTagName); ok {
+			setEndpointIP(zSpan.LocalEndpoint, ip)
+		}
+	}
+
+	var remoteEndpoint models.Endpoint
+	tags := make(models.Tags, len(span.Tags))
+	for _, tag := range span.Tags {
+		switch tag.Key {
+		case string(ext.SpanKind):
+			if kind, ok := spanKinds[tag.AsString()]; ok {
+				zSpan.Kind = kind
+				continue
+			}
+		case string(ext.PeerService):
+			remoteEndpoint.ServiceName = tag.AsString()
+			continue
+		case string(ext.PeerHostIPv4), string(ext.PeerHostIPv6):
+			if setEndpointIP(&remoteEndpoint, tag) {
+				continue
+			}
+		case string(ext.PeerPort):
+			if tag.VType == model.Int64Type {
+				remoteEndpoint.Port = tag.Int64()
+				continue
+			}
+		}
+		tags[tag.Key] = tag.AsString()
+	}
+	if len(tags) > 0 {
+		zSpan.Tags = tags
+	}
+	if remoteEndpoint != (models.Endpoint{}) {
+		zSpan.RemoteEndpoint = &remoteEndpoint
+	}
+
+	for _, log := range span.Logs {
+		zSpan.Annotations = append(zSpan.Annotations, &models.Annotation{
+			Timestamp: int64(model.TimeAsEpochMicroseconds(log.Timestamp)),
+			Value:     annotationValue(log.Fields),
+		})
+	}
+	return zSpan
+}
+
+// setEndpointIP sets the IPv4 or IPv6 address of the endpoint from a tag holding either
+// a packed IPv4 address as an integer, a packed IP address as bytes, or a textual IP address.
+func setEndpointIP(endpoint *models.Endpoint, tag model.KeyValue) bool {
+	var ip net.IP
+	switch tag.VType {
+	case model.Int64Type:
+		v := uint32(tag.Int64())
+		ip = net.IPv4(byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	case model.BinaryType:
+		ip = net.IP(tag.Binary())
+	case model.StringType:
+		ip = net.ParseIP(tag.VStr)
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		endpoint.IPV4 = strfmt.IPv4(ipv4.String())
+		return true
+	}
+	if ipv6 := ip.To16(); ipv6 != nil {
+		endpoint.IPV6 = strfmt.IPv6(ipv6.String())
+		return true
+	}
+	return false
+}
+
+// annotationValue returns the event of a log created from a Zipkin annotation as is,
+// and any other log fields as a JSON object.
+func annotationValue(fields []model.KeyValue) string {
+	if len(fields) == 1 && fields[0].Key == eventLogFieldKey {
+		return fields[0].AsString()
+	}
+	values := make(map[string]string, len(fields))
+	for _, field := range fields {
+		values[field.Key] = field.AsString()
+	}
+	value, _ := json.Marshal(values)
+	return string(value)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/swagger-gen/models"
+)
+
+var (
+	testStartTime = time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+	testTraceID   = model.NewTraceID(0x1, 0x2)
+)
+
+func makeTestSpan() *model.Span {
+	return &model.Span{
+		TraceID:       testTraceID,
+		SpanID:        model.NewSpanID(0xb),
+		OperationName: "GET /api",
+		References:    []model.SpanRef{model.NewChildOfRef(testTraceID, model.NewSpanID(0xa))},
+		Flags:         model.Flags(2),
+		StartTime:     testStartTime,
+		Duration:      1500 * time.Microsecond,
+		Tags: model.KeyValues{
+			model.String("span.kind", "client"),
+			model.String("peer.service", "db"),
+			model.Int64("peer.ipv4", 0x0a000001),
+			model.Int64("peer.port", 5432),
+			model.Bool("error", true),
+		},
+		Logs: []model.Log{
+			{Timestamp: testStartTime, Fields: model.KeyValues{model.String("event", "cs")}},
+			{Timestamp: testStartTime.Add(time.Millisecond), Fields: model.KeyValues{model.String("level", "info"), model.Int64("retries", 2)}},
+		},
+		Process: model.NewProcess("frontend", []model.KeyValue{model.String("ip", "192.168.0.1")}),
+	}
+}
+
+func TestFromDomain(t *testing.T) {
+	spans := FromDomain(&model.Trace{Spans: []*model.Span{makeTestSpan()}})
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "00000000000000010000000000000002", *span.TraceID)
+	assert.Equal(t, "000000000000000b", *span.ID)
+	assert.Equal(t, "000000000000000a", span.ParentID)
+	assert.Equal(t, "GET /api", span.Name)
+	assert.Equal(t, testStartTime.UnixNano()/1000, span.Timestamp)
+	assert.Equal(t, int64(1500), span.Duration)
+	assert.True(t, span.Debug)
+	assert.Equal(t, "CLIENT", span.Kind)
+	assert.Equal(t, &models.Endpoint{ServiceName: "frontend", IPV4: "192.168.0.1"}, span.LocalEndpoint)
+	assert.Equal(t, &models.Endpoint{ServiceName: "db", IPV4: "10.0.0.1", Port: 5432}, span.RemoteEndpoint)
+	assert.Equal(t, models.Tags{"error": "true"}, span.Tags)
+	assert.Equal(t, []*models.Annotation{
+		{Timestamp: testStartTime.UnixNano() / 1000, Value: "cs"},
+		{Timestamp: testStartTime.Add(time.Millisecond).UnixNano() / 1000, Value: `{"level":"info","retries":"2"}`},
+	}, span.Annotations)
+}
+
+func TestFromDomainRootSpan(t *testing.T) {
+	span := spanFromDomain(&model.Span{
+		TraceID: model.NewTraceID(0, 0x2),
+		SpanID:  model.NewSpanID(0xb),
+		Tags:    model.KeyValues{model.String("span.kind", "internal")},
+	})
+	assert.Equal(t, "0000000000000002", *span.TraceID)
+	assert.Empty(t, span.ParentID)
+	assert.Empty(t, span.Kind)
+	assert.Nil(t, span.LocalEndpoint)
+	assert.Nil(t, span.RemoteEndpoint)
+	assert.Equal(t, models.Tags{"span.kind": "internal"}, span.Tags)
+}
+
+func TestSetEndpointIP(t *testing.T) {
+	testCases := []struct {
+		tag      model.KeyValue
+		expected models.Endpoint
+		ok       bool
+	}{
+		{tag: model.Int64("ip", 0x7f000001), expected: models.Endpoint{IPV4: "127.0.0.1"}, ok: true},
+		{tag: model.String("ip", "10.0.0.1"), expected: models.Endpoint{IPV4: "10.0.0.1"}, ok: true},
+		{tag: model.String("ip", "::1"), expected: models.Endpoint{IPV6: "::1"}, ok: true},
+		{tag: model.Binary("ip", net.ParseIP("2001:db8::1")), expected: models.Endpoint{IPV6: "2001:db8::1"}, ok: true},
+		{tag: model.String("ip", "localhost"), ok: false},
+		{tag: model.Bool("ip", true), ok: false},
+	}
+	for _, testCase := range testCases {
+		var endpoint models.Endpoint
+		assert.Equal(t, testCase.ok, setEndpointIP(&endpoint, testCase.tag), testCase.tag.AsString())
+		assert.Equal(t, testCase.expected, endpoint)
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"github.com/opentracing/opentracing-go"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/cmd/query/app/redaction"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/swagger-gen/models"
+)
+
+const (
+	serviceNameParam     = "serviceName"
+	spanNameParam        = "spanName"
+	annotationQueryParam = "annotationQuery"
+	minDurationParam     = "minDuration"
+	maxDurationParam     = "maxDuration"
+	endTsParam           = "endTs"
+	lookbackParam        = "lookback"
+	limitParam           = "limit"
+	traceIDParam         = "traceId"
+
+	// allSpanNames is the spanName the Zipkin UI sends to search across all span names.
+	allSpanNames = "all"
+	// annotationQuerySeparator separates the terms of an annotationQuery, e.g. "error and http.method=GET".
+	annotationQuerySeparator = " and "
+
+	defaultLookback = 24 * time.Hour
+	defaultLimit    = 10
+)
+
+// ErrServiceNameRequired occurs when the serviceName parameter of a trace search is missing.
+var ErrServiceNameRequired = fmt.Errorf("parameter '%s' is required", serviceNameParam)
+
+// APIHandler serves the read side of the Zipkin v2 HTTP API, so that Zipkin clients can query Jaeger.
+// See https://zipkin.io/zipkin-api/#/default.
+type APIHandler struct {
+	queryService *querysvc.QueryService
+	logger       *zap.Logger
+	tracer       opentracing.Tracer
+	timeNow      func() time.Time
+}
+
+// dependencyLink is the Zipkin v2 representation of a model.DependencyLink.
+type dependencyLink struct {
+	Parent    string `json:"parent"`
+	Child     string `json:"child"`
+	CallCount uint64 `json:"callCount"`
+}
+
+// NewAPIHandler returns an APIHandler
+func NewAPIHandler(queryService *querysvc.QueryService, logger *zap.Logger, tracer opentracing.Tracer) *APIHandler {
+	return &APIHandler{
+		queryService: queryService,
+		logger:       logger,
+		tracer:       tracer,
+		timeNow:      time.Now,
+	}
+}
+
+// RegisterRoutes registers the Zipkin v2 routes on the given router
+func (aH *APIHandler) RegisterRoutes(router *mux.Router) {
+	aH.handleFunc(router, aH.getServices, "/api/v2/services")
+	aH.handleFunc(router, aH.getSpanNames, "/api/v2/spans")
+	aH.handleFunc(router, aH.search, "/api/v2/traces")
+	aH.handleFunc(router, aH.getTrace, fmt.Sprintf("/api/v2/trace/{%s}", traceIDParam))
+	aH.handleFunc(router, aH.getDependencies, "/api/v2/dependencies")
+}
+
+func (aH *APIHandler) handleFunc(router *mux.Router, f func(http.ResponseWriter, *http.Request), route string) {
+	traceMiddleware := nethttp.Middleware(
+		aH.tracer,
+		http.HandlerFunc(f),
+		nethttp.OperationNameFunc(func(r *http.Request) string {
+			return route
+		}))
+	router.HandleFunc(route, traceMiddleware.ServeHTTP).Methods(http.MethodGet)
+}
+
+func (aH *APIHandler) getServices(w http.ResponseWriter, r *http.Request) {
+	services, err := aH.queryService.GetServices(r.Context())
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	aH.writeJSON(w, services)
+}
+
+func (aH *APIHandler) getSpanNames(w http.ResponseWriter, r *http.Request) {
+	service := r.FormValue(serviceNameParam)
+	if service == "" {
+		aH.handleError(w, ErrServiceNameRequired, http.StatusBadRequest)
+		return
+	}
+	operations, err := aH.queryService.GetOperations(r.Context(), spanstore.OperationQueryParameters{ServiceName: service})
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	names := make([]string, 0, len(operations))
+	seen := make(map[string]struct{}, len(operations))
+	for _, operation := range operations {
+		if _, ok := seen[operation.Name]; !ok {
+			seen[operation.Name] = struct{}{}
+			names = append(names, operation.Name)
+		}
+	}
+	aH.writeJSON(w, names)
+}
+
+func (aH *APIHandler) search(w http.ResponseWriter, r *http.Request) {
+	query, err := aH.parseTraceQuery(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	traces, err := aH.queryService.FindTraces(r.Context(), query)
+	status := http.StatusInternalServerError
+	if errors.Is(err, redaction.ErrRedactedSearch) {
+		status = http.StatusBadRequest
+	}
+	if aH.handleError(w, err, status) {
+		return
+	}
+	zTraces := make([]models.ListOfSpans, 0, len(traces))
+	for _, trace := range traces {
+		zTraces = append(zTraces, FromDomain(aH.adjust(trace)))
+	}
+	aH.writeJSON(w, zTraces)
+}
+
+// parseTraceQuery maps the Zipkin v2 trace search parameters onto TraceQueryParameters:
+//     serviceName ::= strValue (required)
+//     spanName ::= strValue ('all' matches any span name)
+//     annotationQuery ::= term | term ' and ' annotationQuery (all terms must be satisfied by the same span)
+//     term ::= key '=' strValue (tag equals value) | key (tag or log field exists)
+//     minDuration, maxDuration ::= intValue in microseconds
+//     endTs ::= intValue in epoch milliseconds (defaults to now)
+//     lookback ::= intValue in milliseconds (defaults to one day)
+//     limit ::= intValue (defaults to 10)
+func (aH *APIHandler) parseTraceQuery(r *http.Request) (*spanstore.TraceQueryParameters, error) {
+	query := &spanstore.TraceQueryParameters{
+		ServiceName: r.FormValue(serviceNameParam),
+		Tags:        make(map[string]string),
+		NumTraces:   defaultLimit,
+	}
+	if query.ServiceName == "" {
+		return nil, ErrServiceNameRequired
+	}
+	if spanName := r.FormValue(spanNameParam); spanName != allSpanNames {
+		query.OperationName = spanName
+	}
+	if annotationQuery := r.FormValue(annotationQueryParam); annotationQuery != "" {
+		if err := parseAnnotationQuery(annotationQuery, query); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if query.DurationMin, err = parseMicros(r, minDurationParam); err != nil {
+		return nil, err
+	}
+	if query.DurationMax, err = parseMicros(r, maxDurationParam); err != nil {
+		return nil, err
+	}
+	if query.DurationMin != 0 && query.DurationMax != 0 && query.DurationMax < query.DurationMin {
+		return nil, fmt.Errorf("'%s' should be greater than '%s'", maxDurationParam, minDurationParam)
+	}
+	if query.StartTimeMax, query.StartTimeMin, err = aH.parseTimeRange(r); err != nil {
+		return nil, err
+	}
+	if limit := r.FormValue(limitParam); limit != "" {
+		if query.NumTraces, err = strconv.Atoi(limit); err != nil || query.NumTraces <= 0 {
+			return nil, fmt.Errorf("malformed '%s' parameter, expecting a positive integer, received: %s", limitParam, limit)
+		}
+	}
+	return query, nil
+}
+
+func parseAnnotationQuery(annotationQuery string, query *spanstore.TraceQueryParameters) error {
+	for _, term := range strings.Split(annotationQuery, annotationQuerySeparator) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		if i := strings.Index(term, "="); i >= 0 {
+			if i == 0 {
+				return fmt.Errorf("malformed '%s' parameter, expecting key=value, received: %s", annotationQueryParam, term)
+			}
+			query.Tags[term[:i]] = term[i+1:]
+			continue
+		}
+		predicate, err := spanstore.NewTagPredicate(term, spanstore.TagOpExists, "")
+		if err != nil {
+			return fmt.Errorf("malformed '%s' parameter: %w", annotationQueryParam, err)
+		}
+		query.TagPredicates = append(query.TagPredicates, spanstore.TagPredicateGroup{predicate})
+	}
+	return nil
+}
+
+func parseMicros(r *http.Request, param string) (time.Duration, error) {
+	value := r.FormValue(param)
+	if value == "" {
+		return 0, nil
+	}
+	micros, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || micros < 0 {
+		return 0, fmt.Errorf("malformed '%s' parameter, expecting a non-negative integer, received: %s", param, value)
+	}
+	return time.Duration(micros) * time.Microsecond, nil
+}
+
+// parseTimeRange returns the end of the time range given by endTs and its start given by lookback.
+func (aH *APIHandler) parseTimeRange(r *http.Request) (time.Time, time.Time, error) {
+	endTs := aH.timeNow()
+	if value := r.FormValue(endTsParam); value != "" {
+		millis, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("malformed '%s' parameter, expecting epoch milliseconds, received: %s", endTsParam, value)
+		}
+		endTs = time.Unix(0, 0).Add(time.Duration(millis) * time.Millisecond)
+	}
+	lookback := defaultLookback
+	if value := r.FormValue(lookbackParam); value != "" {
+		millis, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || millis <= 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("malformed '%s' parameter, expecting a positive number of milliseconds, received: %s", lookbackParam, value)
+		}
+		lookback = time.Duration(millis) * time.Millisecond
+	}
+	return endTs, endTs.Add(-lookback), nil
+}
+
+func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
+	traceID, err := model.TraceIDFromString(mux.Vars(r)[traceIDParam])
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	trace, err := aH.queryService.GetTrace(r.Context(), traceID)
+	if err == spanstore.ErrTraceNotFound {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	aH.writeJSON(w, FromDomain(aH.adjust(trace)))
+}
+
+func (aH *APIHandler) getDependencies(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue(endTsParam) == "" {
+		aH.handleError(w, fmt.Errorf("parameter '%s' is required", endTsParam), http.StatusBadRequest)
+		return
+	}
+	endTs, startTs, err := aH.parseTimeRange(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	dependencies, err := aH.queryService.GetDependencies(endTs, endTs.Sub(startTs))
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	type key struct {
+		parent string
+		child  string
+	}
+	indexes := make(map[key]int, len(dependencies))
+	links := make([]dependencyLink, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		k := key{parent: dependency.Parent, child: dependency.Child}
+		if i, ok := indexes[k]; ok {
+			links[i].CallCount += dependency.CallCount
+			continue
+		}
+		indexes[k] = len(links)
+		links = append(links, dependencyLink{
+			Parent:    dependency.Parent,
+			Child:     dependency.Child,
+			CallCount: dependency.CallCount,
+		})
+	}
+	aH.writeJSON(w, links)
+}
+
+// adjust applies the adjusters of the query service, returning the trace as adjusted so far on failure,
+// since Zipkin clients have no way to receive the adjustment errors.
+func (aH *APIHandler) adjust(trace *model.Trace) *model.Trace {
+	adjusted, err := aH.queryService.Adjust(trace)
+	if err != nil {
+		aH.logger.Debug("Failed to adjust trace", zap.Error(err))
+	}
+	return adjusted
+}
+
+func (aH *APIHandler) handleError(w http.ResponseWriter, err error, statusCode int) bool {
+	if err == nil {
+		return false
+	}
+	if statusCode == http.StatusInternalServerError {
+		aH.logger.Error("Zipkin HTTP handler, Internal Server Error", zap.Error(err))
+	}
+	http.Error(w, err.Error(), statusCode)
+	return true
+}
+
+func (aH *APIHandler) writeJSON(w http.ResponseWriter, response interface{}) {
+	resp, _ := json.Marshal(response)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/cmd/query/app/redaction"
+	"github.com/jaegertracing/jaeger/model"
+	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+	"github.com/jaegertracing/jaeger/swagger-gen/models"
+)
+
+type testServer struct {
+	spanReader       *spanstoremocks.Reader
+	dependencyReader *depsmocks.Reader
+	server           *httptest.Server
+}
+
+func withTestServer(t *testing.T, doTest func(s *testServer)) {
+	spanReader := &spanstoremocks.Reader{}
+	dependencyReader := &depsmocks.Reader{}
+	handler := NewAPIHandler(
+		querysvc.NewQueryService(spanReader, dependencyReader, querysvc.QueryServiceOptions{}),
+		zap.NewNop(),
+		opentracing.NoopTracer{},
+	)
+	handler.timeNow = func() time.Time { return testStartTime }
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+	doTest(&testServer{
+		spanReader:       spanReader,
+		dependencyReader: dependencyReader,
+		server:           server,
+	})
+	spanReader.AssertExpectations(t)
+	dependencyReader.AssertExpectations(t)
+}
+
+func getJSON(t *testing.T, url string, out interface{}) int {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	if resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.Unmarshal(body, out))
+	}
+	return resp.StatusCode
+}
+
+func TestGetServices(t *testing.T) {
+	withTestServer(t, func(s *testServer) {
+		s.spanReader.On("GetServices", mock.Anything).Return([]string{"frontend", "db"}, nil).Once()
+		var services []string
+		assert.Equal(t, http.StatusOK, getJSON(t, s.server.URL+"/api/v2/services", &services))
+		assert.Equal(t, []string{"frontend", "db"}, services)
+	})
+}
+
+func TestGetServicesError(t *testing.T) {
+	withTestServer(t, func(s *testServer) {
+		s.spanReader.On("GetServices", mock.Anything).Return(nil, errors.New("storage error")).Once()
+		assert.Equal(t, http.StatusInternalServerError, getJSON(t, s.server.URL+"/api/v2/services", nil))
+	})
+}
+
+func TestGetSpanNames(t *testing.T) {
+	withTestServer(t, func(s *testServer) {
+		s.spanReader.On("GetOperations", mock.Anything, spanstore.OperationQueryParameters{ServiceName: "frontend"}).
+			Return([]spanstore.Operation{
+				{Name: "GET /api", SpanKind: "server"},
+				{Name: "GET /api", SpanKind: "client"},
+				{Name: "SELECT", SpanKind: "client"},
+			}, nil).Once()
+		var names []string
+		assert.Equal(t, http.StatusOK, getJSON(t, s.server.URL+"/api/v2/spans?serviceName=frontend", &names))
+		assert.Equal(t, []string{"GET /api", "SELECT"}, names)
+
+		assert.Equal(t, http.StatusBadRequest, getJSON(t, s.server.URL+"/api/v2/spans", nil))
+	})
+}
+
+func TestSearch(t *testing.T) {
+	withTestServer(t, func(s *testServer) {
+		endTs := testStartTime.Add(30 * time.Second)
+		span := makeTestSpan()
+		span.Tags = append(span.Tags, model.String("http.method", "GET"))
+		s.spanReader.On("FindTraces", mock.Anything, mock.MatchedBy(func(query *spanstore.TraceQueryParameters) bool {
+			return assert.Equal(t, "frontend", query.ServiceName) &&
+				assert.Equal(t, "", query.OperationName) &&
+				assert.Equal(t, map[string]string{"http.method": "GET"}, query.Tags) &&
+				// the mock reader does not support the exists predicate, so the query service evaluates it
+				assert.Empty(t, query.TagPredicates) &&
+				assert.Equal(t, time.Millisecond, query.DurationMin) &&
+				assert.Equal(t, time.Second, query.DurationMax) &&
+				assert.True(t, endTs.Equal(query.StartTimeMax)) &&
+				assert.True(t, endTs.Add(-time.Minute).Equal(query.StartTimeMin)) &&
+				assert.Equal(t, 5, query.NumTraces)
+		})).Return([]*model.Trace{{Spans: []*model.Span{span}}}, nil).Once()
+
+		var traces []models.ListOfSpans
+		url := s.server.URL + "/api/v2/traces?serviceName=frontend&spanName=all&annotationQuery=error%20and%20http.method%3DGET" +
+			"&minDuration=1000&maxDuration=1000000&endTs=1585735230000&lookback=60000&limit=5"
+		assert.Equal(t, http.StatusOK, getJSON(t, url, &traces))
+		require.Len(t, traces, 1)
+		require.Len(t, traces[0], 1)
+		assert.Equal(t, "000000000000000b", *traces[0][0].ID)
+	})
+}
+
+func TestSearchDefaults(t *testing.T) {
+	withTestServer(t, func(s *testServer) {
+		s.spanReader.On("FindTraces", mock.Anything, &spanstore.TraceQueryParameters{
+			ServiceName:   "frontend",
+			OperationName: "GET /api",
+			Tags:          map[string]string{},
+			StartTimeMin:  testStartTime.Add(-defaultLookback),
+			StartTimeMax:  testStartTime,
+			NumTraces:     defaultLimit,
+		}).Return([]*model.Trace{}, nil).Once()
+
+		var traces []models.ListOfSpans
+		assert.Equal(t, http.StatusOK, getJSON(t, s.server.URL+"/api/v2/traces?serviceName=frontend&spanName=GET%20/api", &traces))
+		assert.Empty(t, traces)
+	})
+}
+
+func TestSearchErrors(t *testing.T) {
+	withTestServer(t, func(s *testServer) {
+		for _, params := range []string{
+			"",
+			"serviceName=frontend&annotationQuery=%3Dv",
+			"serviceName=frontend&minDuration=ten",
+			"serviceName=frontend&maxDuration=-1",
+			"serviceName=frontend&minDuration=20&maxDuration=10",
+			"serviceName=frontend&endTs=now",
+			"serviceName=frontend&lookback=0",
+			"serviceName=frontend&limit=0",
+		} {
+			assert.Equal(t, http.StatusBadRequest, getJSON(t, s.server.URL+"/api/v2/traces?"+params, nil), params)
+		}
+
+		s.spanReader.On("FindTraces", mock.Anything, mock.Anything).Return(nil, errors.New("storage error")).Once()
+		assert.Equal(t, http.StatusInternalServerError, getJSON(t, s.server.URL+"/api/v2/traces?serviceName=frontend", nil))
+
+		s.spanReader.On("FindTraces", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: user.email", redaction.ErrRedactedSearch)).Once()
+		assert.Equal(t, http.StatusBadRequest, getJSON(t, s.server.URL+"/api/v2/traces?serviceName=frontend", nil))
+	})
+}
+
+func TestParseAnnotationQuery(t *testing.T) {
+	query := &spanstore.TraceQueryParameters{Tags: make(map[string]string)}
+	require.NoError(t, parseAnnotationQuery("http.path=/a=b and  and retried", query))
+	assert.Equal(t, map[string]string{"http.path": "/a=b"}, query.Tags)
+	assert.Equal(t, []spanstore.TagPredicateGroup{{{Key: "retried", Operator: spanstore.TagOpExists}}}, query.TagPredicates)
+}
+
+func TestGetTrace(t *testing.T) {
+	withTestServer(t, func(s *testServer) {
+		s.spanReader.On("GetTrace", mock.Anything, testTraceID).
+			Return(&model.Trace{Spans: []*model.Span{makeTestSpan()}}, nil).Once()
+		var spans models.ListOfSpans
+		assert.Equal(t, http.StatusOK, getJSON(t, s.server.URL+"/api/v2/trace/00000000000000010000000000000002", &spans))
+		require.Len(t, spans, 1)
+		assert.Equal(t, "00000000000000010000000000000002", *spans[0].TraceID)
+	})
+}
+
+func TestGetTraceErrors(t *testing.T) {
+	withTestServer(t, func(s *testServer) {
+		assert.Equal(t, http.StatusBadRequest, getJSON(t, s.server.URL+"/api/v2/trace/xyz", nil))
+
+		s.spanReader.On("GetTrace", mock.Anything, model.NewTraceID(0, 1)).Return(nil, spanstore.ErrTraceNotFound).Once()
+		assert.Equal(t, http.StatusNotFound, getJSON(t, s.server.URL+"/api/v2/trace/1", nil))
+
+		s.spanReader.On("GetTrace", mock.Anything, model.NewTraceID(0, 2)).Return(nil, errors.New("storage error")).Once()
+		assert.Equal(t, http.StatusInternalServerError, getJSON(t, s.server.URL+"/api/v2/trace/2", nil))
+	})
+}
+
+func TestGetDependencies(t *testing.T) {
+	withTestServer(t, func(s *testServer) {
+		endTs := time.Unix(0, 0).Add(1585738800000 * time.Millisecond)
+		s.dependencyReader.On("GetDependencies", endTs, time.Hour).Return([]model.DependencyLink{
+			{Parent: "frontend", Child: "api", CallCount: 2},
+			{Parent: "api", Child: "db", CallCount: 3},
+			{Parent: "frontend", Child: "api", CallCount: 1, Source: "other"},
+		}, nil).Once()
+		var links []dependencyLink
+		assert.Equal(t, http.StatusOK, getJSON(t, s.server.URL+"/api/v2/dependencies?endTs=1585738800000&lookback=3600000", &links))
+		assert.Equal(t, []dependencyLink{
+			{Parent: "frontend", Child: "api", CallCount: 3},
+			{Parent: "api", Child: "db", CallCount: 3},
+		}, links)
+	})
+}
+
+func TestGetDependenciesErrors(t *testing.T) {
+	withTestServer(t, func(s *testServer) {
+		assert.Equal(t, http.StatusBadRequest, getJSON(t, s.server.URL+"/api/v2/dependencies", nil))
+		assert.Equal(t, http.StatusBadRequest, getJSON(t, s.server.URL+"/api/v2/dependencies?endTs=1&lookback=x", nil))
+
+		s.dependencyReader.On("GetDependencies", mock.Anything, defaultLookback).Return(nil, errors.New("storage error")).Once()
+		assert.Equal(t, http.StatusInternalServerError, getJSON(t, s.server.URL+"/api/v2/dependencies?endTs=1", nil))
+	})
+}
@@ -34,9 +34,12 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/flags"
 	"github.com/jaegertracing/jaeger/cmd/query/app"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/cmd/query/app/redaction"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	memoryConfig "github.com/jaegertracing/jaeger/pkg/memory/config"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	"github.com/jaegertracing/jaeger/plugin/storage"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/ports"
 	istorage "github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -102,6 +105,13 @@ func main() {
 				logger.Fatal("Failed to create dependency reader", zap.Error(err))
 			}
 			queryServiceOptions := archiveOptions(storageFactory, logger)
+			importOptions(queryServiceOptions, queryOpts, storageFactory, logger)
+			liveTailOptions(queryServiceOptions, queryOpts, logger)
+			catalogOptions(queryServiceOptions, queryOpts, logger)
+			spanMetricsOptions(queryServiceOptions, queryOpts, logger)
+			redactionOptions(queryServiceOptions, queryOpts, logger)
+			adjusterOptions(queryServiceOptions, queryOpts, logger)
+			queryServiceOptions.TraceLimits = queryOpts.TraceLimits
 			queryService := querysvc.NewQueryService(
 				spanReader,
 				dependencyReader,
@@ -145,3 +155,62 @@ func archiveOptions(storageFactory istorage.Factory, logger *zap.Logger) *querys
 	}
 	return opts
 }
+
+func importOptions(opts *querysvc.QueryServiceOptions, qOpts *app.QueryOptions, storageFactory istorage.Factory, logger *zap.Logger) {
+	if !qOpts.ImportEnabled {
+		return
+	}
+	uploadStore := memory.WithConfiguration(memoryConfig.Configuration{MaxTraces: qOpts.ImportMaxTraces})
+	if !opts.InitImport(storageFactory, uploadStore, qOpts.ImportStorageWrites, logger) {
+		logger.Info("Trace import not initialized")
+	}
+}
+
+func liveTailOptions(opts *querysvc.QueryServiceOptions, qOpts *app.QueryOptions, logger *zap.Logger) {
+	if len(qOpts.LiveTailCollectors) == 0 {
+		return
+	}
+	if !opts.InitLiveTail(qOpts.LiveTailCollectors, qOpts.LiveTailBufferSize, logger) {
+		logger.Info("Live tail not initialized")
+	}
+}
+
+func catalogOptions(opts *querysvc.QueryServiceOptions, qOpts *app.QueryOptions, logger *zap.Logger) {
+	if len(qOpts.CatalogCollectors) == 0 {
+		return
+	}
+	if !opts.InitCatalog(qOpts.CatalogCollectors, logger) {
+		logger.Info("Service catalog not initialized")
+	}
+}
+
+func spanMetricsOptions(opts *querysvc.QueryServiceOptions, qOpts *app.QueryOptions, logger *zap.Logger) {
+	if len(qOpts.SpanMetricsCollectors) == 0 {
+		return
+	}
+	if !opts.InitSpanMetrics(qOpts.SpanMetricsCollectors, logger) {
+		logger.Info("Span metrics not initialized")
+	}
+}
+
+func redactionOptions(opts *querysvc.QueryServiceOptions, qOpts *app.QueryOptions, logger *zap.Logger) {
+	if qOpts.RedactionRules == "" {
+		return
+	}
+	options := redaction.Options{
+		IdentityHeader:    qOpts.RedactionIdentityHeader,
+		TrustGRPCIdentity: qOpts.RedactionTrustGRPCIdentity,
+	}
+	if err := opts.InitRedaction(qOpts.RedactionRules, qOpts.RedactionHashKeyFile, options); err != nil {
+		logger.Fatal("Failed to initialize redaction", zap.Error(err))
+	}
+}
+
+func adjusterOptions(opts *querysvc.QueryServiceOptions, qOpts *app.QueryOptions, logger *zap.Logger) {
+	if qOpts.Adjusters == nil {
+		return
+	}
+	if err := opts.InitAdjusters(qOpts.Adjusters); err != nil {
+		logger.Fatal("Failed to initialize adjusters", zap.Error(err))
+	}
+}
@@ -49,6 +49,9 @@ func ClockSkew() Adjuster {
 	})
 }
 
+// ClockSkewWarningPrefix starts the warning that ClockSkew adds to the spans whose timestamps it adjusted.
+const ClockSkewWarningPrefix = "This span's timestamps were adjusted by"
+
 const (
 	warningDuplicateSpanID       = "duplicate span IDs; skipping clock skew adjustment"
 	warningFormatInvalidParentID = "invalid parent span IDs=%s; skipping clock skew adjustment"
@@ -178,7 +181,7 @@ func (a *clockSkewAdjuster) adjustTimestamps(n *node, skew clockSkew) {
 	}
 
 	n.span.StartTime = n.span.StartTime.Add(skew.delta)
-	n.span.Warnings = append(n.span.Warnings, fmt.Sprintf("This span's timestamps were adjusted by %v", skew.delta))
+	n.span.Warnings = append(n.span.Warnings, fmt.Sprintf("%s %v", ClockSkewWarningPrefix, skew.delta))
 
 	for i := range n.span.Logs {
 		n.span.Logs[i].Timestamp = n.span.Logs[i].Timestamp.Add(skew.delta)
@@ -0,0 +1,178 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adjuster
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// DefaultMinSiblings is the default number of sibling spans from which CollapseSiblings collapses them.
+const DefaultMinSiblings = 10
+
+// The tags of the placeholder spans added by the CollapseSiblings adjuster.
+const (
+	// CollapsedSpansTag is the number of sibling spans represented by the placeholder span.
+	CollapsedSpansTag = "jaeger.collapsed.spans"
+	// CollapsedDescendantsTag is the number of descendants of the sibling spans, which were removed.
+	CollapsedDescendantsTag = "jaeger.collapsed.descendants"
+	// CollapsedErrorsTag is the number of sibling spans with the error tag.
+	CollapsedErrorsTag = "jaeger.collapsed.errors"
+	// CollapsedMinDurationTag and CollapsedMaxDurationTag are the extreme durations of the sibling spans.
+	CollapsedMinDurationTag = "jaeger.collapsed.min-duration"
+	CollapsedMaxDurationTag = "jaeger.collapsed.max-duration"
+)
+
+// CollapseSiblings returns an adjuster that collapses repetitive sibling spans, i.e. at least minSiblings
+// spans of the same service and operation under the same parent in the model.SpanTree of the trace, or among
+// its roots, e.g. the queries of a batch job, into a placeholder span. The placeholder span takes the ID and the references of the earliest sibling,
+// covers the time range of the siblings, and has the Collapsed* tags and a warning instead of their
+// tags and logs. The descendants of the siblings are removed. The spans of the trace are not modified.
+//
+// This adjuster never returns any errors.
+func CollapseSiblings(minSiblings int) Adjuster {
+	return Func(func(trace *model.Trace) (*model.Trace, error) {
+		if minSiblings < 2 || len(trace.Spans) < minSiblings {
+			return trace, nil
+		}
+		tree := model.NewSpanTree(trace.Spans)
+		siblingGroups := [][]*model.Span{tree.Roots()}
+		parents := make(map[model.SpanID]struct{}, len(trace.Spans))
+		for _, span := range trace.Spans {
+			if _, ok := parents[span.SpanID]; !ok {
+				parents[span.SpanID] = struct{}{}
+				siblingGroups = append(siblingGroups, tree.Children(span.SpanID))
+			}
+		}
+		placeholders := make(map[*model.Span]*model.Span)
+		removed := make(map[*model.Span]struct{})
+		for _, siblings := range siblingGroups {
+			for _, group := range groupSiblings(siblings) {
+				if len(group) < minSiblings {
+					continue
+				}
+				descendants := 0
+				for _, span := range group[1:] {
+					removed[span] = struct{}{}
+				}
+				for _, span := range group {
+					descendants += removeDescendants(span, tree, removed)
+				}
+				placeholders[group[0]] = collapsedSpan(group, descendants)
+			}
+		}
+		if len(placeholders) == 0 {
+			return trace, nil
+		}
+		spans := make([]*model.Span, 0, len(trace.Spans)-len(removed))
+		for _, span := range trace.Spans {
+			if _, ok := removed[span]; ok {
+				continue
+			}
+			if placeholder, ok := placeholders[span]; ok {
+				span = placeholder
+			}
+			spans = append(spans, span)
+		}
+		trace.Spans = spans
+		return trace, nil
+	})
+}
+
+// groupSiblings groups the spans by service and operation, each group sorted by start time.
+func groupSiblings(siblings []*model.Span) [][]*model.Span {
+	type key struct{ service, operation string }
+	groups := make(map[key][]*model.Span)
+	for _, span := range siblings {
+		var service string
+		if span.Process != nil {
+			service = span.Process.ServiceName
+		}
+		k := key{service: service, operation: span.OperationName}
+		groups[k] = append(groups[k], span)
+	}
+	result := make([][]*model.Span, 0, len(groups))
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].StartTime.Before(group[j].StartTime)
+		})
+		result = append(result, group)
+	}
+	return result
+}
+
+// removeDescendants marks the descendants of the span as removed and returns their number.
+func removeDescendants(span *model.Span, tree *model.SpanTree, removed map[*model.Span]struct{}) int {
+	visited := map[*model.Span]struct{}{span: {}}
+	stack := append([]*model.Span(nil), tree.Children(span.SpanID)...)
+	for len(stack) > 0 {
+		descendant := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := visited[descendant]; ok {
+			continue
+		}
+		visited[descendant] = struct{}{}
+		removed[descendant] = struct{}{}
+		stack = append(stack, tree.Children(descendant.SpanID)...)
+	}
+	return len(visited) - 1
+}
+
+func collapsedSpan(group []*model.Span, descendants int) *model.Span {
+	first := group[0]
+	start, end := first.StartTime, first.StartTime.Add(first.Duration)
+	minDuration, maxDuration := first.Duration, first.Duration
+	errors := 0
+	for _, span := range group {
+		if spanEnd := span.StartTime.Add(span.Duration); spanEnd.After(end) {
+			end = spanEnd
+		}
+		if span.Duration < minDuration {
+			minDuration = span.Duration
+		}
+		if span.Duration > maxDuration {
+			maxDuration = span.Duration
+		}
+		if span.IsError() {
+			errors++
+		}
+	}
+	placeholder := &model.Span{
+		TraceID:       first.TraceID,
+		SpanID:        first.SpanID,
+		OperationName: first.OperationName,
+		References:    first.References,
+		Flags:         first.Flags,
+		StartTime:     start,
+		Duration:      end.Sub(start),
+		Tags: []model.KeyValue{
+			model.Int64(CollapsedSpansTag, int64(len(group))),
+			model.Int64(CollapsedDescendantsTag, int64(descendants)),
+			model.Int64(CollapsedErrorsTag, int64(errors)),
+			model.String(CollapsedMinDurationTag, minDuration.String()),
+			model.String(CollapsedMaxDurationTag, maxDuration.String()),
+		},
+		Process:   first.Process,
+		ProcessID: first.ProcessID,
+		Warnings: []string{fmt.Sprintf(
+			"Placeholder for %d sibling spans of this operation, whose %d descendants were removed", len(group), descendants)},
+	}
+	if errors > 0 {
+		placeholder.Tags = append(placeholder.Tags, model.Bool("error", true))
+	}
+	return placeholder
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adjuster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestCollapseSiblingsAdjuster(t *testing.T) {
+	traceID := model.NewTraceID(0, 42)
+	start := time.Unix(100, 0)
+	process := model.NewProcess("batch", nil)
+	span := func(id, parent uint64, operation string, offset, duration time.Duration) *model.Span {
+		span := &model.Span{
+			TraceID:       traceID,
+			SpanID:        model.NewSpanID(id),
+			OperationName: operation,
+			StartTime:     start.Add(offset),
+			Duration:      duration,
+			Process:       process,
+		}
+		if parent != 0 {
+			span.References = []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(parent))}
+		}
+		return span
+	}
+	trace := &model.Trace{Spans: []*model.Span{
+		span(1, 0, "job", 0, 100*time.Millisecond),
+		span(12, 1, "query", 20*time.Millisecond, 5*time.Millisecond),
+		span(10, 1, "query", 10*time.Millisecond, 2*time.Millisecond),
+		span(11, 1, "query", 15*time.Millisecond, 3*time.Millisecond),
+		span(20, 1, "publish", 30*time.Millisecond, time.Millisecond),
+		span(21, 11, "connect", 15*time.Millisecond, time.Millisecond),
+		span(22, 21, "dns", 15*time.Millisecond, time.Millisecond),
+	}}
+	trace.Spans[1].Tags = []model.KeyValue{model.Bool("error", true)}
+	// the error tag may be a string, e.g. in the spans converted from Zipkin
+	trace.Spans[3].Tags = []model.KeyValue{model.String("error", "true")}
+	originalSpan := trace.Spans[2]
+	original := *originalSpan
+
+	trace, err := CollapseSiblings(3).Adjust(trace)
+	require.NoError(t, err)
+	require.Len(t, trace.Spans, 3)
+	assert.Equal(t, model.NewSpanID(1), trace.Spans[0].SpanID)
+	assert.Equal(t, model.NewSpanID(20), trace.Spans[2].SpanID)
+
+	placeholder := trace.Spans[1]
+	assert.Equal(t, model.NewSpanID(10), placeholder.SpanID)
+	assert.Equal(t, "query", placeholder.OperationName)
+	assert.Equal(t, process, placeholder.Process)
+	assert.Equal(t, original.References, placeholder.References)
+	assert.Equal(t, start.Add(10*time.Millisecond), placeholder.StartTime)
+	assert.Equal(t, 15*time.Millisecond, placeholder.Duration)
+	assert.Equal(t, []model.KeyValue{
+		model.Int64(CollapsedSpansTag, 3),
+		model.Int64(CollapsedDescendantsTag, 2),
+		model.Int64(CollapsedErrorsTag, 2),
+		model.String(CollapsedMinDurationTag, "2ms"),
+		model.String(CollapsedMaxDurationTag, "5ms"),
+		model.Bool("error", true),
+	}, placeholder.Tags)
+	require.Len(t, placeholder.Warnings, 1)
+	assert.Equal(t, original, *originalSpan, "the original span is not modified")
+}
+
+func TestCollapseSiblingsAdjusterNoRepetition(t *testing.T) {
+	traceID := model.NewTraceID(0, 42)
+	newTrace := func() *model.Trace {
+		return &model.Trace{Spans: []*model.Span{
+			{TraceID: traceID, SpanID: model.NewSpanID(1), OperationName: "a"},
+			{TraceID: traceID, SpanID: model.NewSpanID(2), OperationName: "a"},
+			{TraceID: traceID, SpanID: model.NewSpanID(3), OperationName: "b", References: []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(1))}},
+		}}
+	}
+	adjusted, err := CollapseSiblings(3).Adjust(newTrace())
+	require.NoError(t, err)
+	assert.Len(t, adjusted.Spans, 3)
+
+	// two roots of the same operation are collapsed with their descendants
+	adjusted, err = CollapseSiblings(2).Adjust(newTrace())
+	require.NoError(t, err)
+	require.Len(t, adjusted.Spans, 1)
+	tag, ok := model.KeyValues(adjusted.Spans[0].Tags).FindByKey(CollapsedDescendantsTag)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), tag.Int64())
+
+	adjusted, err = CollapseSiblings(1).Adjust(newTrace())
+	require.NoError(t, err)
+	assert.Len(t, adjusted.Spans, 3)
+}
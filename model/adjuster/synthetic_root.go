@@ -0,0 +1,125 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adjuster
+
+import (
+	"fmt"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// The markers of the span added by the SyntheticRoot adjuster.
+const (
+	// SyntheticRootService is the service name of the synthetic root span.
+	SyntheticRootService = "[synthetic root]"
+	// SyntheticRootOperation is the operation name of the synthetic root span.
+	SyntheticRootOperation = "[missing root span]"
+	// SyntheticRootTag is a boolean tag set to true on the synthetic root span.
+	SyntheticRootTag = "jaeger.synthetic-root"
+)
+
+// SyntheticRoot returns an adjuster that joins the fragments of a trace whose root span is missing,
+// e.g. because it was dropped, is still in flight, or belongs to an uninstrumented gateway.
+// If the trace has several spans without a parent, or spans whose parent is not in the trace,
+// it adds a synthetic root span covering the time range of the trace and makes these spans its
+// children. The synthetic root span is marked with SyntheticRootTag, and a warning is added to
+// the trace and to the synthetic root span.
+//
+// The references to the missing parents are kept after the reference to the synthetic root.
+// The re-parented spans are copies, so that spans shared with the span reader are not modified.
+//
+// This adjuster never returns any errors.
+func SyntheticRoot() Adjuster {
+	return Func(func(trace *model.Trace) (*model.Trace, error) {
+		if len(trace.Spans) == 0 {
+			return trace, nil
+		}
+		spanIDs := make(map[model.SpanID]struct{}, len(trace.Spans))
+		for _, span := range trace.Spans {
+			spanIDs[span.SpanID] = struct{}{}
+		}
+		var roots, orphans []int
+		for i, span := range trace.Spans {
+			hasParent, hasMissingParent := false, false
+			for _, ref := range span.References {
+				if ref.TraceID != span.TraceID || ref.SpanID == span.SpanID {
+					continue
+				}
+				if _, ok := spanIDs[ref.SpanID]; ok {
+					hasParent = true
+				} else {
+					hasMissingParent = true
+				}
+			}
+			if hasMissingParent && !hasParent {
+				orphans = append(orphans, i)
+			} else if !hasParent {
+				roots = append(roots, i)
+			}
+		}
+		if len(orphans) == 0 && len(roots) < 2 {
+			return trace, nil
+		}
+
+		root := syntheticRootSpan(trace, spanIDs)
+		ref := model.NewChildOfRef(root.TraceID, root.SpanID)
+		for _, i := range append(roots, orphans...) {
+			span := *trace.Spans[i]
+			span.References = append([]model.SpanRef{ref}, span.References...)
+			trace.Spans[i] = &span
+		}
+		warning := fmt.Sprintf(
+			"The trace is incomplete: %d span(s) without a parent and %d span(s) whose parent is missing were put under a synthetic root span",
+			len(roots), len(orphans))
+		root.Warnings = []string{warning}
+		trace.Warnings = append(trace.Warnings, warning)
+		trace.Spans = append([]*model.Span{root}, trace.Spans...)
+		return trace, nil
+	})
+}
+
+// syntheticRootSpan returns a span covering the time range of the trace, with an unused span ID.
+func syntheticRootSpan(trace *model.Trace, spanIDs map[model.SpanID]struct{}) *model.Span {
+	first := trace.Spans[0]
+	start, end := first.StartTime, first.StartTime.Add(first.Duration)
+	var maxSpanID model.SpanID
+	for _, span := range trace.Spans {
+		if span.StartTime.Before(start) {
+			start = span.StartTime
+		}
+		if spanEnd := span.StartTime.Add(span.Duration); spanEnd.After(end) {
+			end = spanEnd
+		}
+		if span.SpanID > maxSpanID {
+			maxSpanID = span.SpanID
+		}
+	}
+	spanID := maxSpanID + 1
+	for {
+		if _, used := spanIDs[spanID]; !used && spanID != 0 {
+			break
+		}
+		spanID++
+	}
+	return &model.Span{
+		TraceID:       first.TraceID,
+		SpanID:        spanID,
+		OperationName: SyntheticRootOperation,
+		StartTime:     start,
+		Duration:      end.Sub(start),
+		Tags:          []model.KeyValue{model.Bool(SyntheticRootTag, true)},
+		Process:       model.NewProcess(SyntheticRootService, nil),
+	}
+}
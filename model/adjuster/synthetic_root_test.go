@@ -0,0 +1,96 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adjuster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestSyntheticRootAdjuster(t *testing.T) {
+	traceID := model.NewTraceID(0, 42)
+	otherTraceID := model.NewTraceID(0, 43)
+	start := time.Unix(100, 0)
+	span := func(id uint64, offset, duration time.Duration, refs ...model.SpanRef) *model.Span {
+		return &model.Span{
+			TraceID:    traceID,
+			SpanID:     model.NewSpanID(id),
+			StartTime:  start.Add(offset),
+			Duration:   duration,
+			References: refs,
+		}
+	}
+	// two fragments, one under a missing parent, one following a span of another trace
+	orphan := span(2, 0, 5*time.Millisecond, model.NewChildOfRef(traceID, model.NewSpanID(1)))
+	trace := &model.Trace{Spans: []*model.Span{
+		orphan,
+		span(3, time.Millisecond, time.Millisecond, model.NewChildOfRef(traceID, model.NewSpanID(2))),
+		span(4, 2*time.Millisecond, 8*time.Millisecond, model.NewFollowsFromRef(otherTraceID, model.NewSpanID(1))),
+	}}
+
+	trace, err := SyntheticRoot().Adjust(trace)
+	require.NoError(t, err)
+	require.Len(t, trace.Spans, 4)
+	root := trace.Spans[0]
+	assert.Equal(t, traceID, root.TraceID)
+	assert.Equal(t, model.NewSpanID(5), root.SpanID)
+	assert.Equal(t, SyntheticRootOperation, root.OperationName)
+	assert.Equal(t, SyntheticRootService, root.Process.ServiceName)
+	assert.Equal(t, []model.KeyValue{model.Bool(SyntheticRootTag, true)}, root.Tags)
+	assert.Equal(t, start, root.StartTime)
+	assert.Equal(t, 10*time.Millisecond, root.Duration)
+	require.Len(t, trace.Warnings, 1)
+	assert.Contains(t, trace.Warnings[0], "1 span(s) without a parent and 1 span(s) whose parent is missing")
+	assert.Equal(t, trace.Warnings, root.Warnings)
+
+	rootRef := model.NewChildOfRef(traceID, root.SpanID)
+	assert.Equal(t, []model.SpanRef{rootRef, model.NewChildOfRef(traceID, model.NewSpanID(1))}, trace.Spans[1].References)
+	assert.Equal(t, []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(2))}, trace.Spans[2].References)
+	assert.Equal(t, []model.SpanRef{rootRef, model.NewFollowsFromRef(otherTraceID, model.NewSpanID(1))}, trace.Spans[3].References)
+	assert.Len(t, orphan.References, 1, "the original span is not modified")
+}
+
+func TestSyntheticRootAdjusterCompleteTrace(t *testing.T) {
+	traceID := model.NewTraceID(0, 42)
+	trace := &model.Trace{Spans: []*model.Span{
+		{TraceID: traceID, SpanID: model.NewSpanID(1)},
+		{TraceID: traceID, SpanID: model.NewSpanID(2), References: []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(1))}},
+	}}
+	adjusted, err := SyntheticRoot().Adjust(trace)
+	require.NoError(t, err)
+	assert.Len(t, adjusted.Spans, 2)
+	assert.Empty(t, adjusted.Warnings)
+
+	adjusted, err = SyntheticRoot().Adjust(&model.Trace{})
+	require.NoError(t, err)
+	assert.Empty(t, adjusted.Spans)
+}
+
+func TestSyntheticRootSpanID(t *testing.T) {
+	traceID := model.NewTraceID(0, 42)
+	trace := &model.Trace{Spans: []*model.Span{
+		{TraceID: traceID, SpanID: model.NewSpanID(0xffffffffffffffff)},
+		{TraceID: traceID, SpanID: model.NewSpanID(1)},
+	}}
+	adjusted, err := SyntheticRoot().Adjust(trace)
+	require.NoError(t, err)
+	require.Len(t, adjusted.Spans, 3)
+	assert.Equal(t, model.NewSpanID(2), adjusted.Spans[0].SpanID)
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/base64"
+	ejson "encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/json"
+)
+
+// ToDomain converts json.Trace into the spans of the domain model. It is the inverse of FromDomain
+// and FromDomainEmbedProcess: spans may refer to the processes of the trace or embed their process,
+// and values of tags may be typed JSON values or their string representation.
+func ToDomain(trace *json.Trace) ([]*model.Span, error) {
+	processes := make(map[json.ProcessID]*model.Process, len(trace.Processes))
+	for id, process := range trace.Processes {
+		p, err := processToDomain(process)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert process %s: %w", id, err)
+		}
+		processes[id] = p
+	}
+	spans := make([]*model.Span, 0, len(trace.Spans))
+	for i := range trace.Spans {
+		span, err := spanToDomain(&trace.Spans[i], processes)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert span %s: %w", trace.Spans[i].SpanID, err)
+		}
+		spans = append(spans, span)
+	}
+	return spans, nil
+}
+
+func spanToDomain(jSpan *json.Span, processes map[json.ProcessID]*model.Process) (*model.Span, error) {
+	traceID, err := model.TraceIDFromString(string(jSpan.TraceID))
+	if err != nil {
+		return nil, err
+	}
+	spanID, err := model.SpanIDFromString(string(jSpan.SpanID))
+	if err != nil {
+		return nil, err
+	}
+	span := &model.Span{
+		TraceID:       traceID,
+		SpanID:        spanID,
+		OperationName: jSpan.OperationName,
+		Flags:         model.Flags(jSpan.Flags),
+		StartTime:     model.EpochMicrosecondsAsTime(jSpan.StartTime),
+		Duration:      model.MicrosecondsAsDuration(jSpan.Duration),
+		Warnings:      jSpan.Warnings,
+	}
+	for _, ref := range jSpan.References {
+		r, err := referenceToDomain(ref)
+		if err != nil {
+			return nil, err
+		}
+		span.References = append(span.References, r)
+	}
+	if len(span.References) == 0 && jSpan.ParentSpanID != "" {
+		parentID, err := model.SpanIDFromString(string(jSpan.ParentSpanID))
+		if err != nil {
+			return nil, err
+		}
+		span.References = []model.SpanRef{model.NewChildOfRef(traceID, parentID)}
+	}
+	if span.Tags, err = keyValuesToDomain(jSpan.Tags); err != nil {
+		return nil, err
+	}
+	for _, log := range jSpan.Logs {
+		fields, err := keyValuesToDomain(log.Fields)
+		if err != nil {
+			return nil, err
+		}
+		span.Logs = append(span.Logs, model.Log{Timestamp: model.EpochMicrosecondsAsTime(log.Timestamp), Fields: fields})
+	}
+	switch {
+	case jSpan.Process != nil:
+		if span.Process, err = processToDomain(*jSpan.Process); err != nil {
+			return nil, err
+		}
+	case processes[jSpan.ProcessID] != nil:
+		span.Process = processes[jSpan.ProcessID]
+	default:
+		return nil, fmt.Errorf("unknown process ID %s", jSpan.ProcessID)
+	}
+	return span, nil
+}
+
+func referenceToDomain(ref json.Reference) (model.SpanRef, error) {
+	traceID, err := model.TraceIDFromString(string(ref.TraceID))
+	if err != nil {
+		return model.SpanRef{}, err
+	}
+	spanID, err := model.SpanIDFromString(string(ref.SpanID))
+	if err != nil {
+		return model.SpanRef{}, err
+	}
+	switch ref.RefType {
+	case json.ChildOf:
+		return model.NewChildOfRef(traceID, spanID), nil
+	case json.FollowsFrom:
+		return model.NewFollowsFromRef(traceID, spanID), nil
+	}
+	return model.SpanRef{}, fmt.Errorf("unknown reference type %s", ref.RefType)
+}
+
+func processToDomain(process json.Process) (*model.Process, error) {
+	tags, err := keyValuesToDomain(process.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewProcess(process.ServiceName, tags), nil
+}
+
+func keyValuesToDomain(kvs []json.KeyValue) (model.KeyValues, error) {
+	if len(kvs) == 0 {
+		return nil, nil
+	}
+	out := make(model.KeyValues, 0, len(kvs))
+	for _, kv := range kvs {
+		v, err := keyValueToDomain(kv)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert tag %s: %w", kv.Key, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func keyValueToDomain(kv json.KeyValue) (model.KeyValue, error) {
+	switch value := kv.Value.(type) {
+	case string:
+		return stringToDomain(kv.Key, kv.Type, value)
+	case bool:
+		if kv.Type == json.BoolType || kv.Type == "" {
+			return model.Bool(kv.Key, value), nil
+		}
+	case float64:
+		switch kv.Type {
+		case json.Int64Type:
+			return model.Int64(kv.Key, int64(value)), nil
+		case json.Float64Type, "":
+			return model.Float64(kv.Key, value), nil
+		}
+	case ejson.Number:
+		// values decoded with json.Decoder.UseNumber keep the precision of large integers
+		return stringToDomain(kv.Key, kv.Type, value.String())
+	}
+	return model.KeyValue{}, fmt.Errorf("value %v is not of type %s", kv.Value, kv.Type)
+}
+
+func stringToDomain(key string, valueType json.ValueType, value string) (model.KeyValue, error) {
+	switch valueType {
+	case json.StringType, "":
+		return model.String(key, value), nil
+	case json.BoolType:
+		v, err := strconv.ParseBool(value)
+		return model.Bool(key, v), err
+	case json.Int64Type:
+		v, err := strconv.ParseInt(value, 10, 64)
+		return model.Int64(key, v), err
+	case json.Float64Type:
+		v, err := strconv.ParseFloat(value, 64)
+		return model.Float64(key, v), err
+	case json.BinaryType:
+		// encoding/json encodes byte slices in base64
+		v, err := base64.StdEncoding.DecodeString(value)
+		return model.Binary(key, v), err
+	}
+	return model.KeyValue{}, fmt.Errorf("unknown value type %s", valueType)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	jModel "github.com/jaegertracing/jaeger/model/json"
+)
+
+func TestToDomain(t *testing.T) {
+	for i := 1; i <= NumberOfFixtures; i++ {
+		domainStr, jsonStr := loadFixturesUI(t, i)
+
+		var uiTrace jModel.Trace
+		decoder := json.NewDecoder(bytes.NewReader(jsonStr))
+		decoder.UseNumber()
+		require.NoError(t, decoder.Decode(&uiTrace))
+		spans, err := ToDomain(&uiTrace)
+		require.NoError(t, err)
+
+		var trace model.Trace
+		require.NoError(t, jsonpb.Unmarshal(bytes.NewReader(domainStr), &trace))
+		// trace warnings are not carried by the spans
+		trace.Warnings = nil
+		actual, err := json.Marshal(FromDomain(&model.Trace{Spans: spans}))
+		require.NoError(t, err)
+		expected, err := json.Marshal(FromDomain(&trace))
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expected), string(actual))
+	}
+}
+
+func TestToDomainEmbeddedProcess(t *testing.T) {
+	span := &model.Span{
+		TraceID:    model.NewTraceID(0, 1),
+		SpanID:     model.NewSpanID(2),
+		References: []model.SpanRef{model.NewChildOfRef(model.NewTraceID(0, 1), model.NewSpanID(1))},
+		Tags: model.KeyValues{
+			model.String("s", "v"),
+			model.Bool("b", true),
+			model.Int64("i", 1<<60),
+			model.Float64("f", 1.5),
+			model.Binary("bin", []byte{1, 2}),
+		},
+		Process: model.NewProcess("service", nil),
+	}
+	jSpan := FromDomainEmbedProcess(span)
+	spans, err := ToDomain(&jModel.Trace{Spans: []jModel.Span{*jSpan}})
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+	assert.Equal(t, span.References, spans[0].References)
+	assert.Equal(t, span.Process, spans[0].Process)
+	// embedded spans render values as strings, and binary values in hex which cannot be told apart from base64
+	assert.Equal(t, span.Tags[:4], spans[0].Tags[:4])
+
+	// spans with the deprecated parent span ID and without references
+	jSpan.References = nil
+	jSpan.ParentSpanID = "1"
+	spans, err = ToDomain(&jModel.Trace{Spans: []jModel.Span{*jSpan}})
+	require.NoError(t, err)
+	assert.Equal(t, span.References, spans[0].References)
+}
+
+func TestKeyValueToDomain(t *testing.T) {
+	testCases := []struct {
+		kv       jModel.KeyValue
+		expected model.KeyValue
+		err      string
+	}{
+		{kv: jModel.KeyValue{Key: "k", Value: "v"}, expected: model.String("k", "v")},
+		{kv: jModel.KeyValue{Key: "k", Type: jModel.BoolType, Value: true}, expected: model.Bool("k", true)},
+		{kv: jModel.KeyValue{Key: "k", Type: jModel.Int64Type, Value: float64(3)}, expected: model.Int64("k", 3)},
+		{kv: jModel.KeyValue{Key: "k", Type: jModel.Int64Type, Value: json.Number("1152921504606846976")}, expected: model.Int64("k", 1<<60)},
+		{kv: jModel.KeyValue{Key: "k", Type: jModel.Float64Type, Value: float64(1.5)}, expected: model.Float64("k", 1.5)},
+		{kv: jModel.KeyValue{Key: "k", Type: jModel.BinaryType, Value: "AQI="}, expected: model.Binary("k", []byte{1, 2})},
+		{kv: jModel.KeyValue{Key: "k", Type: jModel.BoolType, Value: "x"}, err: `strconv.ParseBool: parsing "x": invalid syntax`},
+		{kv: jModel.KeyValue{Key: "k", Type: jModel.StringType, Value: true}, err: "value true is not of type string"},
+		{kv: jModel.KeyValue{Key: "k", Type: "map", Value: "v"}, err: "unknown value type map"},
+	}
+	for _, testCase := range testCases {
+		kv, err := keyValueToDomain(testCase.kv)
+		if testCase.err != "" {
+			assert.EqualError(t, err, testCase.err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, kv)
+	}
+}
+
+func TestToDomainErrors(t *testing.T) {
+	validSpan := jModel.Span{TraceID: "1", SpanID: "2", ProcessID: "p1"}
+	processes := map[jModel.ProcessID]jModel.Process{"p1": {ServiceName: "service"}}
+	testCases := []struct {
+		name   string
+		update func(trace *jModel.Trace)
+		err    string
+	}{
+		{name: "trace ID", update: func(trace *jModel.Trace) { trace.Spans[0].TraceID = "x" }, err: `cannot convert span 2: strconv.ParseUint: parsing "x": invalid syntax`},
+		{name: "span ID", update: func(trace *jModel.Trace) { trace.Spans[0].SpanID = "x" }, err: `cannot convert span x: strconv.ParseUint: parsing "x": invalid syntax`},
+		{name: "process ID", update: func(trace *jModel.Trace) { trace.Spans[0].ProcessID = "p2" }, err: "cannot convert span 2: unknown process ID p2"},
+		{name: "reference type", update: func(trace *jModel.Trace) {
+			trace.Spans[0].References = []jModel.Reference{{RefType: "PARENT", TraceID: "1", SpanID: "1"}}
+		}, err: "cannot convert span 2: unknown reference type PARENT"},
+		{name: "process tag", update: func(trace *jModel.Trace) {
+			trace.Processes["p1"] = jModel.Process{Tags: []jModel.KeyValue{{Key: "k", Type: "map", Value: "v"}}}
+		}, err: "cannot convert process p1: cannot convert tag k: unknown value type map"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			trace := &jModel.Trace{Spans: []jModel.Span{validSpan}, Processes: map[jModel.ProcessID]jModel.Process{}}
+			for k, v := range processes {
+				trace.Processes[k] = v
+			}
+			testCase.update(trace)
+			_, err := ToDomain(trace)
+			assert.EqualError(t, err, testCase.err)
+		})
+	}
+}
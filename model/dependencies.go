@@ -18,6 +18,14 @@ package model
 const (
 	// JaegerDependencyLinkSource describes a dependency diagram that was generated from Jaeger traces.
 	JaegerDependencyLinkSource = "jaeger"
+
+	// RPCDependency is the kind of a dependency link where the parent waits for the child,
+	// e.g. a remote procedure call.
+	RPCDependency = DependencyLinkKind_RPC
+
+	// MessagingDependency is the kind of a dependency link where the parent does not wait
+	// for the child, e.g. a message from a producer to a consumer.
+	MessagingDependency = DependencyLinkKind_MESSAGING
 )
 
 // ApplyDefaults applies defaults to the DependencyLink.
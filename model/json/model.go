@@ -103,11 +103,21 @@ type KeyValue struct {
 	Value interface{} `json:"value"`
 }
 
-// DependencyLink shows dependencies between services
+// DependencyLink shows dependencies between services.
+// The operations, kind, error count and latencies are only set on detailed links.
 type DependencyLink struct {
-	Parent    string `json:"parent"`
-	Child     string `json:"child"`
-	CallCount uint64 `json:"callCount"`
+	Parent          string `json:"parent"`
+	Child           string `json:"child"`
+	CallCount       uint64 `json:"callCount"`
+	ParentOperation string `json:"parentOperation,omitempty"`
+	ChildOperation  string `json:"childOperation,omitempty"`
+	// Kind is either "rpc" or "messaging"
+	Kind       string `json:"kind,omitempty"`
+	ErrorCount uint64 `json:"errorCount,omitempty"`
+	// LatencyP50, LatencyP95 and LatencyP99 are in microseconds
+	LatencyP50 uint64 `json:"latencyP50,omitempty"`
+	LatencyP95 uint64 `json:"latencyP95,omitempty"`
+	LatencyP99 uint64 `json:"latencyP99,omitempty"`
 }
 
 // Operation defines the data in the operation response when query operation by service and span kind
@@ -90,6 +90,31 @@ func (SpanRefType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_4c16552f9fdb66d8, []int{1}
 }
 
+type DependencyLinkKind int32
+
+const (
+	DependencyLinkKind_RPC       DependencyLinkKind = 0
+	DependencyLinkKind_MESSAGING DependencyLinkKind = 1
+)
+
+var DependencyLinkKind_name = map[int32]string{
+	0: "RPC",
+	1: "MESSAGING",
+}
+
+var DependencyLinkKind_value = map[string]int32{
+	"RPC":       0,
+	"MESSAGING": 1,
+}
+
+func (x DependencyLinkKind) String() string {
+	return proto.EnumName(DependencyLinkKind_name, int32(x))
+}
+
+func (DependencyLinkKind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{2}
+}
+
 type KeyValue struct {
 	Key                  string    `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	VType                ValueType `protobuf:"varint,2,opt,name=v_type,json=vType,proto3,enum=jaeger.api_v2.ValueType" json:"v_type,omitempty"`
@@ -632,13 +657,22 @@ func (m *Batch) GetProcess() *Process {
 }
 
 type DependencyLink struct {
-	Parent               string   `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
-	Child                string   `protobuf:"bytes,2,opt,name=child,proto3" json:"child,omitempty"`
-	CallCount            uint64   `protobuf:"varint,3,opt,name=call_count,json=callCount,proto3" json:"call_count,omitempty"`
-	Source               string   `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Parent    string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	Child     string `protobuf:"bytes,2,opt,name=child,proto3" json:"child,omitempty"`
+	CallCount uint64 `protobuf:"varint,3,opt,name=call_count,json=callCount,proto3" json:"call_count,omitempty"`
+	Source    string `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	// The fields below are only set on detailed dependency links, which are split
+	// by operation and kind, see dependencystore.DetailedReader.
+	ParentOperation      string             `protobuf:"bytes,5,opt,name=parent_operation,json=parentOperation,proto3" json:"parent_operation,omitempty"`
+	ChildOperation       string             `protobuf:"bytes,6,opt,name=child_operation,json=childOperation,proto3" json:"child_operation,omitempty"`
+	Kind                 DependencyLinkKind `protobuf:"varint,7,opt,name=kind,proto3,enum=jaeger.api_v2.DependencyLinkKind" json:"kind,omitempty"`
+	ErrorCount           uint64             `protobuf:"varint,8,opt,name=error_count,json=errorCount,proto3" json:"error_count,omitempty"`
+	LatencyP50           time.Duration      `protobuf:"bytes,9,opt,name=latency_p50,json=latencyP50,proto3,stdduration" json:"latency_p50"`
+	LatencyP95           time.Duration      `protobuf:"bytes,10,opt,name=latency_p95,json=latencyP95,proto3,stdduration" json:"latency_p95"`
+	LatencyP99           time.Duration      `protobuf:"bytes,11,opt,name=latency_p99,json=latencyP99,proto3,stdduration" json:"latency_p99"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *DependencyLink) Reset()         { *m = DependencyLink{} }
@@ -702,11 +736,62 @@ func (m *DependencyLink) GetSource() string {
 	return ""
 }
 
+func (m *DependencyLink) GetParentOperation() string {
+	if m != nil {
+		return m.ParentOperation
+	}
+	return ""
+}
+
+func (m *DependencyLink) GetChildOperation() string {
+	if m != nil {
+		return m.ChildOperation
+	}
+	return ""
+}
+
+func (m *DependencyLink) GetKind() DependencyLinkKind {
+	if m != nil {
+		return m.Kind
+	}
+	return DependencyLinkKind_RPC
+}
+
+func (m *DependencyLink) GetErrorCount() uint64 {
+	if m != nil {
+		return m.ErrorCount
+	}
+	return 0
+}
+
+func (m *DependencyLink) GetLatencyP50() time.Duration {
+	if m != nil {
+		return m.LatencyP50
+	}
+	return 0
+}
+
+func (m *DependencyLink) GetLatencyP95() time.Duration {
+	if m != nil {
+		return m.LatencyP95
+	}
+	return 0
+}
+
+func (m *DependencyLink) GetLatencyP99() time.Duration {
+	if m != nil {
+		return m.LatencyP99
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("jaeger.api_v2.ValueType", ValueType_name, ValueType_value)
 	golang_proto.RegisterEnum("jaeger.api_v2.ValueType", ValueType_name, ValueType_value)
 	proto.RegisterEnum("jaeger.api_v2.SpanRefType", SpanRefType_name, SpanRefType_value)
 	golang_proto.RegisterEnum("jaeger.api_v2.SpanRefType", SpanRefType_name, SpanRefType_value)
+	proto.RegisterEnum("jaeger.api_v2.DependencyLinkKind", DependencyLinkKind_name, DependencyLinkKind_value)
+	golang_proto.RegisterEnum("jaeger.api_v2.DependencyLinkKind", DependencyLinkKind_name, DependencyLinkKind_value)
 	proto.RegisterType((*KeyValue)(nil), "jaeger.api_v2.KeyValue")
 	golang_proto.RegisterType((*KeyValue)(nil), "jaeger.api_v2.KeyValue")
 	proto.RegisterType((*Log)(nil), "jaeger.api_v2.Log")
@@ -731,68 +816,77 @@ func init() { proto.RegisterFile("model.proto", fileDescriptor_4c16552f9fdb66d8)
 func init() { golang_proto.RegisterFile("model.proto", fileDescriptor_4c16552f9fdb66d8) }
 
 var fileDescriptor_4c16552f9fdb66d8 = []byte{
-	// 975 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x55, 0x41, 0x8f, 0xdb, 0x44,
-	0x14, 0xde, 0x49, 0xec, 0xd8, 0x7e, 0xc9, 0xae, 0xa2, 0x69, 0xe9, 0xba, 0x01, 0x36, 0x21, 0x15,
-	0x52, 0xa8, 0x4a, 0xb6, 0x5d, 0xda, 0x3d, 0x20, 0x24, 0x54, 0xef, 0x12, 0x08, 0x64, 0x37, 0x68,
-	0x76, 0x05, 0x82, 0x8b, 0x35, 0xeb, 0x4c, 0x5c, 0xb7, 0x8e, 0xc7, 0xb2, 0x1d, 0xa3, 0xdc, 0xf8,
-	0x09, 0x88, 0x13, 0x47, 0xb8, 0xf2, 0x2b, 0x38, 0xf6, 0xc8, 0x81, 0x13, 0x12, 0x0b, 0x0a, 0x97,
-	0xfe, 0x0c, 0x34, 0xe3, 0x71, 0xb6, 0x1b, 0x2a, 0xd8, 0x5e, 0x7a, 0xf2, 0xbc, 0x79, 0xdf, 0x7b,
-	0xf3, 0xde, 0xf7, 0xbe, 0xf1, 0x40, 0x7d, 0xc6, 0x27, 0x2c, 0xec, 0xc7, 0x09, 0xcf, 0x38, 0xde,
-	0x7c, 0x4c, 0x99, 0xcf, 0x92, 0x3e, 0x8d, 0x03, 0x37, 0xdf, 0x6b, 0x5d, 0xf7, 0xb9, 0xcf, 0xa5,
-	0x67, 0x57, 0xac, 0x0a, 0x50, 0xeb, 0x0d, 0x9f, 0x73, 0x3f, 0x64, 0xbb, 0x34, 0x0e, 0x76, 0x69,
-	0x14, 0xf1, 0x8c, 0x66, 0x01, 0x8f, 0x52, 0xe5, 0x6d, 0x2b, 0xaf, 0xb4, 0xce, 0xe6, 0xd3, 0xdd,
-	0x2c, 0x98, 0xb1, 0x34, 0xa3, 0xb3, 0x58, 0x01, 0x76, 0xd6, 0x01, 0x93, 0x79, 0x22, 0x33, 0x14,
-	0xfe, 0xee, 0x6f, 0x08, 0xcc, 0xcf, 0xd8, 0xe2, 0x0b, 0x1a, 0xce, 0x19, 0x6e, 0x42, 0xf5, 0x09,
-	0x5b, 0xd8, 0xa8, 0x83, 0x7a, 0x16, 0x11, 0x4b, 0xbc, 0x0b, 0xb5, 0xdc, 0xcd, 0x16, 0x31, 0xb3,
-	0x2b, 0x1d, 0xd4, 0xdb, 0xda, 0xb3, 0xfb, 0x97, 0x6a, 0xee, 0xcb, 0xb8, 0xd3, 0x45, 0xcc, 0x88,
-	0x9e, 0x8b, 0x0f, 0xbe, 0x06, 0x7a, 0xee, 0xa6, 0x59, 0x62, 0x57, 0x65, 0x12, 0x2d, 0x3f, 0xc9,
-	0x12, 0xfc, 0x9a, 0xc8, 0x72, 0xc6, 0x79, 0x68, 0x6b, 0x1d, 0xd4, 0x33, 0x89, 0x9e, 0x3b, 0x9c,
-	0x87, 0x78, 0x1b, 0x8c, 0xdc, 0x0d, 0xa2, 0x6c, 0xff, 0xbe, 0xad, 0x77, 0x50, 0xaf, 0x4a, 0x6a,
-	0xf9, 0x50, 0x58, 0xf8, 0x75, 0xb0, 0x72, 0x77, 0x1a, 0x72, 0x2a, 0x5c, 0xb5, 0x0e, 0xea, 0x21,
-	0x62, 0xe6, 0x83, 0xc2, 0xc6, 0x37, 0xc1, 0xcc, 0xdd, 0xb3, 0x20, 0xa2, 0xc9, 0xc2, 0x36, 0x3a,
-	0xa8, 0xd7, 0x20, 0x46, 0xee, 0x48, 0xf3, 0x7d, 0xf3, 0xd9, 0x8f, 0x6d, 0xf4, 0xec, 0xa7, 0x36,
-	0xea, 0x7e, 0x8b, 0xa0, 0x3a, 0xe2, 0x3e, 0x76, 0xc0, 0x5a, 0x31, 0x22, 0xfb, 0xaa, 0xef, 0xb5,
-	0xfa, 0x05, 0x25, 0xfd, 0x92, 0x92, 0xfe, 0x69, 0x89, 0x70, 0xcc, 0xa7, 0xe7, 0xed, 0x8d, 0xef,
-	0xfe, 0x6c, 0x23, 0x72, 0x11, 0x86, 0x1f, 0x40, 0x6d, 0x1a, 0xb0, 0x70, 0x92, 0xda, 0x95, 0x4e,
-	0xb5, 0x57, 0xdf, 0xdb, 0x5e, 0xe3, 0xa0, 0xa4, 0xcf, 0xd1, 0x44, 0x34, 0x51, 0xe0, 0xee, 0xcf,
-	0x08, 0x8c, 0x93, 0x98, 0x46, 0x84, 0x4d, 0xf1, 0x03, 0x30, 0xb3, 0x84, 0x7a, 0xcc, 0x0d, 0x26,
-	0xb2, 0x8a, 0x86, 0xd3, 0x12, 0xd8, 0xdf, 0xcf, 0xdb, 0xc6, 0xa9, 0xd8, 0x1f, 0x1e, 0x2e, 0x2f,
-	0x96, 0xc4, 0x90, 0xd8, 0xe1, 0x04, 0xdf, 0x03, 0x23, 0x8d, 0x69, 0x24, 0xa2, 0x2a, 0x32, 0xca,
-	0x56, 0x51, 0x35, 0x91, 0x58, 0x06, 0xa9, 0x15, 0xa9, 0x09, 0xe0, 0x70, 0x22, 0x4e, 0x4a, 0xd8,
-	0xb4, 0x18, 0x59, 0x55, 0x8e, 0xac, 0xb5, 0x56, 0xae, 0xaa, 0x49, 0x0e, 0xcd, 0x48, 0x8a, 0x45,
-	0xd7, 0x05, 0xe3, 0xf3, 0x84, 0x7b, 0x2c, 0x4d, 0xf1, 0x5b, 0xd0, 0x48, 0x59, 0x92, 0x07, 0x1e,
-	0x73, 0x23, 0x3a, 0x63, 0x4a, 0x0d, 0x75, 0xb5, 0x77, 0x4c, 0x67, 0x0c, 0xdf, 0x03, 0x2d, 0xa3,
-	0xfe, 0x15, 0xf9, 0x90, 0xd0, 0xee, 0x1f, 0x1a, 0x68, 0xe2, 0xe4, 0x57, 0x48, 0xc5, 0xdb, 0xb0,
-	0xc5, 0x63, 0x56, 0xa8, 0xbd, 0x68, 0xa5, 0xd0, 0xe4, 0xe6, 0x6a, 0x57, 0x36, 0xf3, 0x01, 0x40,
-	0xc2, 0xa6, 0x2c, 0x61, 0x91, 0xc7, 0x52, 0x5b, 0x93, 0x2d, 0xdd, 0x78, 0x31, 0x67, 0xaa, 0xa3,
-	0xe7, 0xf0, 0xf8, 0x16, 0xe8, 0xd3, 0x50, 0x70, 0x21, 0x14, 0xbc, 0xe9, 0x6c, 0xaa, 0xaa, 0xf4,
-	0x81, 0xd8, 0x24, 0x85, 0x0f, 0x1f, 0x00, 0xa4, 0x19, 0x4d, 0x32, 0x57, 0x88, 0x4a, 0x0a, 0xfa,
-	0xca, 0x32, 0x94, 0x71, 0xc2, 0x83, 0x3f, 0x04, 0xb3, 0xbc, 0xbb, 0x52, 0xf7, 0xf5, 0xbd, 0x9b,
-	0xff, 0x4a, 0x71, 0xa8, 0x00, 0x45, 0x86, 0x1f, 0x44, 0x86, 0x55, 0xd0, 0x6a, 0x6a, 0xe6, 0x95,
-	0xa7, 0x86, 0xef, 0x80, 0x16, 0x72, 0x3f, 0xb5, 0x2d, 0x19, 0x82, 0xd7, 0x42, 0x46, 0xdc, 0x2f,
-	0xd1, 0x02, 0x85, 0xef, 0x82, 0x11, 0x17, 0x22, 0xb2, 0x41, 0x16, 0xb8, 0x4e, 0xa3, 0x92, 0x18,
-	0x29, 0x61, 0xf8, 0x0e, 0x80, 0x5a, 0x8a, 0xc1, 0xd6, 0xc5, 0x78, 0x9c, 0xcd, 0xe5, 0x79, 0xdb,
-	0x52, 0xc8, 0xe1, 0x21, 0xb1, 0x14, 0x60, 0x38, 0xc1, 0x2d, 0x30, 0xbf, 0xa1, 0x49, 0x14, 0x44,
-	0x7e, 0x6a, 0x37, 0x3a, 0xd5, 0x9e, 0x45, 0x56, 0x76, 0xf7, 0xfb, 0x0a, 0xe8, 0x52, 0x34, 0xf8,
-	0x1d, 0xd0, 0x85, 0x00, 0x52, 0x1b, 0xc9, 0xa2, 0xaf, 0xbd, 0x68, 0x94, 0x05, 0x02, 0x7f, 0x0a,
-	0xf5, 0xf2, 0xf8, 0x19, 0x8d, 0x95, 0x9c, 0x6f, 0xad, 0x05, 0xc8, 0xac, 0x65, 0xe9, 0x47, 0x34,
-	0x8e, 0x83, 0xa8, 0x6c, 0xbb, 0x2c, 0xfe, 0x88, 0xc6, 0x97, 0x8a, 0xab, 0x5e, 0x2e, 0xae, 0x95,
-	0xc3, 0xd6, 0xe5, 0xf8, 0xb5, 0xc6, 0xd1, 0xff, 0x34, 0xbe, 0x7f, 0x41, 0x6c, 0xe5, 0xbf, 0x88,
-	0x55, 0x65, 0x95, 0xe0, 0xee, 0x63, 0xd0, 0x1d, 0x9a, 0x79, 0x8f, 0x5e, 0x86, 0x93, 0x97, 0x3a,
-	0x0b, 0x5d, 0x9c, 0x35, 0x87, 0xad, 0x43, 0x16, 0xb3, 0x68, 0xc2, 0x22, 0x6f, 0x31, 0x0a, 0xa2,
-	0x27, 0xf8, 0x06, 0xd4, 0x62, 0x9a, 0xb0, 0x28, 0x53, 0xbf, 0x10, 0x65, 0xe1, 0xeb, 0xa0, 0x7b,
-	0x8f, 0x82, 0xb0, 0xb8, 0xc8, 0x16, 0x29, 0x0c, 0xfc, 0x26, 0x80, 0x47, 0xc3, 0xd0, 0xf5, 0xf8,
-	0x3c, 0xca, 0xe4, 0x4d, 0xd5, 0x88, 0x25, 0x76, 0x0e, 0xc4, 0x86, 0x48, 0x96, 0xf2, 0x79, 0xe2,
-	0x31, 0xf9, 0x84, 0x58, 0x44, 0x59, 0xb7, 0x3f, 0x02, 0x6b, 0xf5, 0x06, 0x61, 0x80, 0xda, 0xc9,
-	0x29, 0x19, 0x1e, 0x7f, 0xdc, 0xdc, 0xc0, 0x26, 0x68, 0xce, 0x78, 0x3c, 0x6a, 0x22, 0x6c, 0x81,
-	0x3e, 0x3c, 0x3e, 0xdd, 0xbf, 0xdf, 0xac, 0xe0, 0x3a, 0x18, 0x83, 0xd1, 0xf8, 0xa1, 0x30, 0xaa,
-	0x02, 0xed, 0x0c, 0x8f, 0x1f, 0x92, 0xaf, 0x9a, 0xda, 0xed, 0x77, 0xa1, 0xfe, 0xdc, 0x7f, 0x11,
-	0x37, 0xc0, 0x3c, 0xf8, 0x64, 0x38, 0x3a, 0x74, 0xc7, 0x83, 0xe6, 0x06, 0x6e, 0x42, 0x63, 0x30,
-	0x1e, 0x8d, 0xc6, 0x5f, 0x9e, 0xb8, 0x03, 0x32, 0x3e, 0x6a, 0x22, 0xe7, 0xee, 0xd3, 0xe5, 0x0e,
-	0xfa, 0x75, 0xb9, 0x83, 0xfe, 0x5a, 0xee, 0xa0, 0x5f, 0xfe, 0xde, 0x41, 0xb0, 0x1d, 0x70, 0xc5,
-	0x93, 0xf8, 0x63, 0x05, 0x91, 0xaf, 0xe8, 0xfa, 0x5a, 0x97, 0x2f, 0xfe, 0x59, 0x4d, 0xde, 0xd1,
-	0xf7, 0xfe, 0x09, 0x00, 0x00, 0xff, 0xff, 0xff, 0xb8, 0x61, 0xb8, 0x01, 0x08, 0x00, 0x00,
+	// 1108 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0x41, 0x8f, 0xdb, 0x44,
+	0x14, 0xde, 0xd9, 0xd8, 0xb1, 0xfd, 0xbc, 0x49, 0xa3, 0x69, 0x69, 0xdd, 0x00, 0x9b, 0x34, 0x15,
+	0x22, 0xad, 0x4a, 0x76, 0x1b, 0x9a, 0x4a, 0x8b, 0x90, 0x50, 0xbd, 0x69, 0x4a, 0x68, 0x76, 0x53,
+	0x4d, 0x56, 0x20, 0xb8, 0x58, 0xb3, 0xc9, 0x24, 0x75, 0xeb, 0xd8, 0x96, 0xed, 0x35, 0xca, 0x8d,
+	0x9f, 0x80, 0x38, 0x71, 0x84, 0x2b, 0xbf, 0x82, 0x63, 0x8f, 0x1c, 0x38, 0x21, 0x51, 0xd0, 0x72,
+	0xe9, 0x9d, 0x3f, 0x80, 0x66, 0x3c, 0xce, 0x36, 0x69, 0x05, 0x6d, 0x0f, 0x9c, 0x76, 0xde, 0x9b,
+	0xef, 0x7b, 0xf3, 0xde, 0xf7, 0xde, 0x5b, 0x07, 0xcc, 0x79, 0x30, 0x61, 0x5e, 0x2b, 0x8c, 0x82,
+	0x24, 0xc0, 0xa5, 0x47, 0x94, 0xcd, 0x58, 0xd4, 0xa2, 0xa1, 0xeb, 0xa4, 0xed, 0xea, 0x85, 0x59,
+	0x30, 0x0b, 0xc4, 0xcd, 0x0e, 0x3f, 0x65, 0xa0, 0xea, 0x3b, 0xb3, 0x20, 0x98, 0x79, 0x6c, 0x87,
+	0x86, 0xee, 0x0e, 0xf5, 0xfd, 0x20, 0xa1, 0x89, 0x1b, 0xf8, 0xb1, 0xbc, 0xad, 0xc9, 0x5b, 0x61,
+	0x1d, 0x9f, 0x4c, 0x77, 0x12, 0x77, 0xce, 0xe2, 0x84, 0xce, 0x43, 0x09, 0xd8, 0x5e, 0x07, 0x4c,
+	0x4e, 0x22, 0x11, 0x21, 0xbb, 0x6f, 0xfc, 0x8a, 0x40, 0xbf, 0xcf, 0x16, 0x9f, 0x53, 0xef, 0x84,
+	0xe1, 0x0a, 0x14, 0x1e, 0xb3, 0x85, 0x85, 0xea, 0xa8, 0x69, 0x10, 0x7e, 0xc4, 0x3b, 0x50, 0x4c,
+	0x9d, 0x64, 0x11, 0x32, 0x6b, 0xb3, 0x8e, 0x9a, 0xe5, 0xb6, 0xd5, 0x5a, 0xc9, 0xb9, 0x25, 0x78,
+	0x47, 0x8b, 0x90, 0x11, 0x35, 0xe5, 0x7f, 0xf0, 0x79, 0x50, 0x53, 0x27, 0x4e, 0x22, 0xab, 0x20,
+	0x82, 0x28, 0xe9, 0x28, 0x89, 0xf0, 0x5b, 0x3c, 0xca, 0x71, 0x10, 0x78, 0x96, 0x52, 0x47, 0x4d,
+	0x9d, 0xa8, 0xa9, 0x1d, 0x04, 0x1e, 0xbe, 0x04, 0x5a, 0xea, 0xb8, 0x7e, 0x72, 0xfb, 0x96, 0xa5,
+	0xd6, 0x51, 0xb3, 0x40, 0x8a, 0x69, 0x9f, 0x5b, 0xf8, 0x6d, 0x30, 0x52, 0x67, 0xea, 0x05, 0x94,
+	0x5f, 0x15, 0xeb, 0xa8, 0x89, 0x88, 0x9e, 0xf6, 0x32, 0x1b, 0x5f, 0x06, 0x3d, 0x75, 0x8e, 0x5d,
+	0x9f, 0x46, 0x0b, 0x4b, 0xab, 0xa3, 0xe6, 0x16, 0xd1, 0x52, 0x5b, 0x98, 0x1f, 0xe9, 0xcf, 0x7e,
+	0xa8, 0xa1, 0x67, 0x3f, 0xd6, 0x50, 0xe3, 0x1b, 0x04, 0x85, 0x41, 0x30, 0xc3, 0x36, 0x18, 0x4b,
+	0x45, 0x44, 0x5d, 0x66, 0xbb, 0xda, 0xca, 0x24, 0x69, 0xe5, 0x92, 0xb4, 0x8e, 0x72, 0x84, 0xad,
+	0x3f, 0x79, 0x5a, 0xdb, 0xf8, 0xf6, 0x8f, 0x1a, 0x22, 0x67, 0x34, 0xdc, 0x81, 0xe2, 0xd4, 0x65,
+	0xde, 0x24, 0xb6, 0x36, 0xeb, 0x85, 0xa6, 0xd9, 0xbe, 0xb4, 0xa6, 0x41, 0x2e, 0x9f, 0xad, 0x70,
+	0x36, 0x91, 0xe0, 0xc6, 0x4f, 0x08, 0xb4, 0x51, 0x48, 0x7d, 0xc2, 0xa6, 0xb8, 0x03, 0x7a, 0x12,
+	0xd1, 0x31, 0x73, 0xdc, 0x89, 0xc8, 0x62, 0xcb, 0xae, 0x72, 0xec, 0x6f, 0x4f, 0x6b, 0xda, 0x11,
+	0xf7, 0xf7, 0xbb, 0xa7, 0x67, 0x47, 0xa2, 0x09, 0x6c, 0x7f, 0x82, 0x6f, 0x82, 0x16, 0x87, 0xd4,
+	0xe7, 0xac, 0x4d, 0xc1, 0xb2, 0x24, 0xab, 0xc8, 0x03, 0x0b, 0x92, 0x3c, 0x91, 0x22, 0x07, 0xf6,
+	0x27, 0xfc, 0xa5, 0x88, 0x4d, 0xb3, 0x96, 0x15, 0x44, 0xcb, 0xaa, 0x6b, 0xe9, 0xca, 0x9c, 0x44,
+	0xd3, 0xb4, 0x28, 0x3b, 0x34, 0x1c, 0xd0, 0x1e, 0x44, 0xc1, 0x98, 0xc5, 0x31, 0xbe, 0x02, 0x5b,
+	0x31, 0x8b, 0x52, 0x77, 0xcc, 0x1c, 0x9f, 0xce, 0x99, 0x9c, 0x06, 0x53, 0xfa, 0x0e, 0xe9, 0x9c,
+	0xe1, 0x9b, 0xa0, 0x24, 0x74, 0xf6, 0x8a, 0x7a, 0x08, 0x68, 0xe3, 0x77, 0x05, 0x14, 0xfe, 0xf2,
+	0xff, 0x28, 0xc5, 0x7b, 0x50, 0x0e, 0x42, 0x96, 0x4d, 0x7b, 0x56, 0x4a, 0x36, 0x93, 0xa5, 0xa5,
+	0x57, 0x14, 0xf3, 0x31, 0x40, 0xc4, 0xa6, 0x2c, 0x62, 0xfe, 0x98, 0xc5, 0x96, 0x22, 0x4a, 0xba,
+	0xf8, 0x72, 0xcd, 0x64, 0x45, 0xcf, 0xe1, 0xf1, 0x55, 0x50, 0xa7, 0x1e, 0xd7, 0x82, 0x4f, 0x70,
+	0xc9, 0x2e, 0xc9, 0xac, 0xd4, 0x1e, 0x77, 0x92, 0xec, 0x0e, 0xef, 0x03, 0xc4, 0x09, 0x8d, 0x12,
+	0x87, 0x0f, 0x95, 0x55, 0x7c, 0x9d, 0x31, 0x14, 0x3c, 0x7e, 0x83, 0x3f, 0x01, 0x3d, 0xdf, 0x5d,
+	0x31, 0xf7, 0x66, 0xfb, 0xf2, 0x0b, 0x21, 0xba, 0x12, 0x90, 0x45, 0xf8, 0x9e, 0x47, 0x58, 0x92,
+	0x96, 0x5d, 0xd3, 0x5f, 0xb9, 0x6b, 0xf8, 0x06, 0x28, 0x5e, 0x30, 0x8b, 0x2d, 0x43, 0x50, 0xf0,
+	0x1a, 0x65, 0x10, 0xcc, 0x72, 0x34, 0x47, 0xe1, 0x5d, 0xd0, 0xc2, 0x6c, 0x88, 0x2c, 0xa8, 0xa3,
+	0x97, 0xc8, 0x28, 0x47, 0x8c, 0xe4, 0x30, 0x7c, 0x03, 0x40, 0x1e, 0x79, 0x63, 0x4d, 0xde, 0x1e,
+	0xbb, 0x74, 0xfa, 0xb4, 0x66, 0x48, 0x64, 0xbf, 0x4b, 0x0c, 0x09, 0xe8, 0x4f, 0x70, 0x15, 0xf4,
+	0xaf, 0x69, 0xe4, 0xbb, 0xfe, 0x2c, 0xb6, 0xb6, 0xea, 0x85, 0xa6, 0x41, 0x96, 0x76, 0xe3, 0xbb,
+	0x4d, 0x50, 0xc5, 0xd0, 0xe0, 0x6b, 0xa0, 0xf2, 0x01, 0x88, 0x2d, 0x24, 0x92, 0x3e, 0xff, 0xb2,
+	0x56, 0x66, 0x08, 0xfc, 0x19, 0x98, 0xf9, 0xf3, 0x73, 0x1a, 0xca, 0x71, 0xbe, 0xba, 0x46, 0x10,
+	0x51, 0xf3, 0xd4, 0x0f, 0x68, 0x18, 0xba, 0x7e, 0x5e, 0x76, 0x9e, 0xfc, 0x01, 0x0d, 0x57, 0x92,
+	0x2b, 0xac, 0x26, 0x57, 0x4d, 0xa1, 0xbc, 0xca, 0x5f, 0x2b, 0x1c, 0xfd, 0x47, 0xe1, 0xb7, 0xcf,
+	0x84, 0xdd, 0xfc, 0x37, 0x61, 0x65, 0x5a, 0x39, 0xb8, 0xf1, 0x08, 0x54, 0x9b, 0x26, 0xe3, 0x87,
+	0xaf, 0xa3, 0xc9, 0x6b, 0xbd, 0x85, 0xce, 0xde, 0xfa, 0xbb, 0x00, 0xe5, 0x2e, 0x0b, 0x99, 0x3f,
+	0x61, 0xfe, 0x78, 0x31, 0x70, 0xfd, 0xc7, 0xf8, 0x22, 0x14, 0x43, 0x1a, 0x31, 0x3f, 0x91, 0xff,
+	0x43, 0xa4, 0x85, 0x2f, 0x80, 0x3a, 0x7e, 0xe8, 0x7a, 0xd9, 0x26, 0x1b, 0x24, 0x33, 0xf0, 0xbb,
+	0x00, 0x63, 0xea, 0x79, 0xce, 0x38, 0x38, 0xf1, 0x13, 0xb1, 0xaa, 0x0a, 0x31, 0xb8, 0x67, 0x9f,
+	0x3b, 0x78, 0xb0, 0x38, 0x38, 0x89, 0xc6, 0x4c, 0x7c, 0x43, 0x0c, 0x22, 0x2d, 0x7c, 0x0d, 0x2a,
+	0x59, 0x58, 0x67, 0xb9, 0xd6, 0x62, 0x17, 0x0d, 0x72, 0x2e, 0xf3, 0x0f, 0x73, 0x37, 0x7e, 0x1f,
+	0xce, 0x89, 0xa7, 0x9e, 0x43, 0x16, 0x05, 0xb2, 0x2c, 0xdc, 0x67, 0xc0, 0x0e, 0x28, 0x8f, 0x5d,
+	0x7f, 0x22, 0xd6, 0xac, 0xdc, 0xbe, 0xb2, 0x26, 0xc0, 0x6a, 0x95, 0xf7, 0x5d, 0x7f, 0x42, 0x04,
+	0x1c, 0xd7, 0xc0, 0x64, 0x51, 0x14, 0x44, 0xb2, 0x04, 0x5d, 0x94, 0x00, 0xc2, 0x95, 0xd5, 0xd0,
+	0x05, 0xd3, 0xa3, 0x09, 0x67, 0x3a, 0x61, 0x67, 0xd7, 0x32, 0x5e, 0x7d, 0x8b, 0x41, 0xf2, 0x1e,
+	0x74, 0x76, 0x57, 0xa2, 0xec, 0x75, 0x2c, 0x78, 0x83, 0x28, 0x7b, 0x9d, 0xd5, 0x28, 0x7b, 0x96,
+	0xf9, 0x26, 0x51, 0xf6, 0xae, 0xdf, 0x05, 0x63, 0xf9, 0x13, 0x00, 0x03, 0x14, 0x47, 0x47, 0xa4,
+	0x7f, 0x78, 0xaf, 0xb2, 0x81, 0x75, 0x50, 0xec, 0xe1, 0x70, 0x50, 0x41, 0xd8, 0x00, 0xb5, 0x7f,
+	0x78, 0x74, 0xfb, 0x56, 0x65, 0x13, 0x9b, 0xa0, 0xf5, 0x06, 0xc3, 0x3b, 0xdc, 0x28, 0x70, 0xb4,
+	0xdd, 0x3f, 0xbc, 0x43, 0xbe, 0xac, 0x28, 0xd7, 0x3f, 0x00, 0xf3, 0xb9, 0xcf, 0x12, 0xde, 0x02,
+	0x7d, 0xff, 0xd3, 0xfe, 0xa0, 0xeb, 0x0c, 0x7b, 0x95, 0x0d, 0x5c, 0x81, 0xad, 0xde, 0x70, 0x30,
+	0x18, 0x7e, 0x31, 0x72, 0x7a, 0x64, 0x78, 0x50, 0x41, 0xd7, 0x6f, 0x00, 0x7e, 0xb1, 0x09, 0x58,
+	0x83, 0x02, 0x79, 0xb0, 0x5f, 0xd9, 0xc0, 0x25, 0x30, 0x0e, 0xee, 0x8e, 0x46, 0x77, 0xee, 0xf1,
+	0x54, 0x90, 0xbd, 0xfb, 0xe4, 0x74, 0x1b, 0xfd, 0x72, 0xba, 0x8d, 0xfe, 0x3c, 0xdd, 0x46, 0x3f,
+	0xff, 0xb5, 0x8d, 0xe0, 0x92, 0x1b, 0xc8, 0x9e, 0xf2, 0xcf, 0x8b, 0xeb, 0xcf, 0x64, 0x6b, 0xbf,
+	0x52, 0xc5, 0xcf, 0xb3, 0xe3, 0xa2, 0x28, 0xff, 0xc3, 0x7f, 0x06, 0x00, 0x4a, 0x9a, 0x93, 0x90,
+	0xae, 0x09, 0x00, 0x00,
 }
 
 func (this *KeyValue) Compare(that interface{}) int {
@@ -1403,6 +1497,52 @@ func (m *DependencyLink) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintModel(dAtA, i, uint64(len(m.Source)))
 		i += copy(dAtA[i:], m.Source)
 	}
+	if len(m.ParentOperation) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.ParentOperation)))
+		i += copy(dAtA[i:], m.ParentOperation)
+	}
+	if len(m.ChildOperation) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.ChildOperation)))
+		i += copy(dAtA[i:], m.ChildOperation)
+	}
+	if m.Kind != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.Kind))
+	}
+	if m.ErrorCount != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.ErrorCount))
+	}
+	dAtA[i] = 0x4a
+	i++
+	i = encodeVarintModel(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.LatencyP50)))
+	n11, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.LatencyP50, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n11
+	dAtA[i] = 0x52
+	i++
+	i = encodeVarintModel(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.LatencyP95)))
+	n12, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.LatencyP95, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n12
+	dAtA[i] = 0x5a
+	i++
+	i = encodeVarintModel(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.LatencyP99)))
+	n13, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.LatencyP99, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n13
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -1665,6 +1805,26 @@ func (m *DependencyLink) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovModel(uint64(l))
 	}
+	l = len(m.ParentOperation)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.ChildOperation)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.Kind != 0 {
+		n += 1 + sovModel(uint64(m.Kind))
+	}
+	if m.ErrorCount != 0 {
+		n += 1 + sovModel(uint64(m.ErrorCount))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.LatencyP50)
+	n += 1 + l + sovModel(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.LatencyP95)
+	n += 1 + l + sovModel(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.LatencyP99)
+	n += 1 + l + sovModel(uint64(l))
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -3265,6 +3425,207 @@ func (m *DependencyLink) Unmarshal(dAtA []byte) error {
 			}
 			m.Source = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ParentOperation", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ParentOperation = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChildOperation", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChildOperation = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
+			}
+			m.Kind = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Kind |= DependencyLinkKind(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCount", wireType)
+			}
+			m.ErrorCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LatencyP50", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.LatencyP50, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LatencyP95", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.LatencyP95, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LatencyP99", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.LatencyP99, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
@@ -85,6 +85,17 @@ func (s *Span) IsRPCServer() bool {
 	return s.HasSpanKind(ext.SpanKindRPCServerEnum)
 }
 
+// IsError returns true if the span failed, as indicated by the `error` tag set to `true`,
+// whether as a bool or a string.
+func (s *Span) IsError() bool {
+	for _, tag := range s.Tags {
+		if tag.Key == string(ext.Error) && tag.AsString() == "true" {
+			return true
+		}
+	}
+	return false
+}
+
 // NormalizeTimestamps changes all timestamps in this span to UTC.
 func (s *Span) NormalizeTimestamps() {
 	s.StartTime = s.StartTime.UTC()
@@ -194,6 +194,14 @@ func TestIsRPCClientServer(t *testing.T) {
 	assert.False(t, span2.IsRPCServer())
 }
 
+func TestIsError(t *testing.T) {
+	assert.True(t, (&model.Span{Tags: model.KeyValues{model.Bool(string(ext.Error), true)}}).IsError())
+	assert.True(t, (&model.Span{Tags: model.KeyValues{model.String(string(ext.Error), "true")}}).IsError())
+	assert.False(t, (&model.Span{Tags: model.KeyValues{model.Bool(string(ext.Error), false)}}).IsError())
+	assert.False(t, (&model.Span{Tags: model.KeyValues{model.String(string(ext.Error), "1")}}).IsError())
+	assert.False(t, (&model.Span{}).IsError())
+}
+
 func TestIsDebug(t *testing.T) {
 	flags := model.Flags(0)
 	flags.SetDebug()
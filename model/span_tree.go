@@ -0,0 +1,91 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+// SpanTree links the spans of a trace to their parents within the trace.
+//
+// The parent of a span is the target of its first CHILD_OF reference within the trace, or else
+// of its first FOLLOWS_FROM reference. Spans whose parents are not in the trace are roots.
+// Spans that are only reachable through a reference cycle are neither roots nor, from the roots, descendants.
+// The roots and the children of each span are in the order of the spans given to NewSpanTree.
+type SpanTree struct {
+	roots       []*Span
+	children    map[SpanID][]*Span
+	followsFrom map[*Span]struct{}
+}
+
+// NewSpanTree returns the tree of the spans, which are expected to have unique IDs,
+// as ensured by adjuster.SpanIDDeduper.
+func NewSpanTree(spans []*Span) *SpanTree {
+	inTrace := make(map[SpanID]struct{}, len(spans))
+	for _, span := range spans {
+		inTrace[span.SpanID] = struct{}{}
+	}
+	tree := &SpanTree{
+		children:    make(map[SpanID][]*Span),
+		followsFrom: make(map[*Span]struct{}),
+	}
+	for _, span := range spans {
+		parentID, refType, ok := parentInTrace(span, inTrace)
+		if !ok {
+			tree.roots = append(tree.roots, span)
+			continue
+		}
+		if refType == FollowsFrom {
+			tree.followsFrom[span] = struct{}{}
+		}
+		tree.children[parentID] = append(tree.children[parentID], span)
+	}
+	return tree
+}
+
+func parentInTrace(span *Span, inTrace map[SpanID]struct{}) (SpanID, SpanRefType, bool) {
+	var followsFrom *SpanRef
+	for i := range span.References {
+		ref := &span.References[i]
+		if ref.TraceID != span.TraceID || ref.SpanID == span.SpanID {
+			continue
+		}
+		if _, ok := inTrace[ref.SpanID]; !ok {
+			continue
+		}
+		if ref.RefType == ChildOf {
+			return ref.SpanID, ChildOf, true
+		}
+		if followsFrom == nil {
+			followsFrom = ref
+		}
+	}
+	if followsFrom == nil {
+		return 0, ChildOf, false
+	}
+	return followsFrom.SpanID, FollowsFrom, true
+}
+
+// Roots returns the spans whose parents are not in the trace.
+func (t *SpanTree) Roots() []*Span {
+	return t.roots
+}
+
+// Children returns the spans whose parent is the span with the given ID.
+func (t *SpanTree) Children(spanID SpanID) []*Span {
+	return t.children[spanID]
+}
+
+// IsFollowsFrom returns true if the span is linked to its parent by a FOLLOWS_FROM reference.
+func (t *SpanTree) IsFollowsFrom(span *Span) bool {
+	_, ok := t.followsFrom[span]
+	return ok
+}
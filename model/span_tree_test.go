@@ -0,0 +1,54 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestSpanTree(t *testing.T) {
+	traceID := model.NewTraceID(0, 1)
+	otherTraceID := model.NewTraceID(0, 2)
+	span := func(spanID uint64, refs ...model.SpanRef) *model.Span {
+		return &model.Span{TraceID: traceID, SpanID: model.NewSpanID(spanID), References: refs}
+	}
+	root := span(1)
+	child := span(2, model.NewChildOfRef(traceID, model.NewSpanID(1)))
+	// the CHILD_OF reference within the trace wins over the earlier FOLLOWS_FROM one
+	preferChildOf := span(3,
+		model.NewFollowsFromRef(traceID, model.NewSpanID(2)),
+		model.NewChildOfRef(traceID, model.NewSpanID(99)),
+		model.NewChildOfRef(traceID, model.NewSpanID(1)))
+	followsFrom := span(4, model.NewFollowsFromRef(traceID, model.NewSpanID(2)))
+	orphan := span(5, model.NewChildOfRef(traceID, model.NewSpanID(99)))
+	otherTrace := span(6, model.NewChildOfRef(otherTraceID, model.NewSpanID(1)))
+	self := span(7, model.NewChildOfRef(traceID, model.NewSpanID(7)))
+	cycleA := span(8, model.NewChildOfRef(traceID, model.NewSpanID(9)))
+	cycleB := span(9, model.NewChildOfRef(traceID, model.NewSpanID(8)))
+
+	tree := model.NewSpanTree([]*model.Span{root, child, preferChildOf, followsFrom, orphan, otherTrace, self, cycleA, cycleB})
+	assert.Equal(t, []*model.Span{root, orphan, otherTrace, self}, tree.Roots())
+	assert.Equal(t, []*model.Span{child, preferChildOf}, tree.Children(root.SpanID))
+	assert.Equal(t, []*model.Span{followsFrom}, tree.Children(child.SpanID))
+	assert.Equal(t, []*model.Span{cycleB}, tree.Children(cycleA.SpanID))
+	assert.Empty(t, tree.Children(followsFrom.SpanID))
+	assert.True(t, tree.IsFollowsFrom(followsFrom))
+	assert.False(t, tree.IsFollowsFrom(preferChildOf))
+	assert.False(t, tree.IsFollowsFrom(root))
+}
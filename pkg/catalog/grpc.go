@@ -0,0 +1,161 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package catalog
+
+import (
+	"context"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/multierror"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// FromDomain converts the catalog to its gRPC representation.
+func FromDomain(catalog []spanstore.ServiceActivity) []api_v2.ServiceActivity {
+	services := make([]api_v2.ServiceActivity, len(catalog))
+	for i, a := range catalog {
+		services[i] = api_v2.ServiceActivity{
+			ServiceName:    a.ServiceName,
+			LastSeen:       a.LastSeen,
+			SpanRate1m:     a.SpanRate1m,
+			SpanRate5m:     a.SpanRate5m,
+			SpanRate15m:    a.SpanRate15m,
+			OperationCount: int64(a.OperationCount),
+			ClientVersions: a.ClientVersions,
+			Hostnames:      a.Hostnames,
+			ClientUUIDs:    a.ClientUUIDs,
+		}
+	}
+	return services
+}
+
+// ToDomain converts the gRPC representation of a catalog.
+func ToDomain(services []api_v2.ServiceActivity) []spanstore.ServiceActivity {
+	catalog := make([]spanstore.ServiceActivity, len(services))
+	for i, s := range services {
+		catalog[i] = spanstore.ServiceActivity{
+			ServiceName:    s.ServiceName,
+			LastSeen:       s.LastSeen,
+			SpanRate1m:     s.SpanRate1m,
+			SpanRate5m:     s.SpanRate5m,
+			SpanRate15m:    s.SpanRate15m,
+			OperationCount: int(s.OperationCount),
+			ClientVersions: s.ClientVersions,
+			Hostnames:      s.Hostnames,
+			ClientUUIDs:    s.ClientUUIDs,
+		}
+	}
+	return catalog
+}
+
+// GRPCHandler implements the gRPC ServiceCatalogService of a collector.
+type GRPCHandler struct {
+	reader spanstore.CatalogReader
+}
+
+// NewGRPCHandler creates a GRPCHandler returning the catalog of the reader.
+func NewGRPCHandler(reader spanstore.CatalogReader) *GRPCHandler {
+	return &GRPCHandler{reader: reader}
+}
+
+// GetServiceCatalog implements gRPC ServiceCatalogService.
+func (h *GRPCHandler) GetServiceCatalog(ctx context.Context, r *api_v2.GetServiceCatalogRequest) (*api_v2.GetServiceCatalogResponse, error) {
+	catalog, err := h.reader.GetServiceCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &api_v2.GetServiceCatalogResponse{Services: FromDomain(catalog)}, nil
+}
+
+// RemoteReader is a spanstore.CatalogReader that merges the catalogs of the ServiceCatalogService of collectors.
+// A service is last seen when any collector last saw it and its span rates are the sums of those of the
+// collectors. Its operation count is the largest one of the collectors, as they may see the same operations.
+// The collectors that fail are logged and left out of the catalog, which fails only if all of them fail.
+type RemoteReader struct {
+	hostPorts []string
+	clients   map[string]api_v2.ServiceCatalogServiceClient
+	logger    *zap.Logger
+}
+
+// NewRemoteReader creates a RemoteReader of the clients by host:port.
+func NewRemoteReader(clients map[string]api_v2.ServiceCatalogServiceClient, logger *zap.Logger) *RemoteReader {
+	hostPorts := make([]string, 0, len(clients))
+	for hostPort := range clients {
+		hostPorts = append(hostPorts, hostPort)
+	}
+	sort.Strings(hostPorts)
+	return &RemoteReader{hostPorts: hostPorts, clients: clients, logger: logger}
+}
+
+// GetServiceCatalog implements spanstore.CatalogReader.
+func (rr *RemoteReader) GetServiceCatalog(ctx context.Context) ([]spanstore.ServiceActivity, error) {
+	merged := make(map[string]*spanstore.ServiceActivity)
+	var errs []error
+	for _, hostPort := range rr.hostPorts {
+		resp, err := rr.clients[hostPort].GetServiceCatalog(ctx, &api_v2.GetServiceCatalogRequest{})
+		if err != nil {
+			rr.logger.Warn("Leaving the collector out of the service catalog", zap.String("host-port", hostPort), zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+		for _, a := range ToDomain(resp.Services) {
+			m, ok := merged[a.ServiceName]
+			if !ok {
+				a := a
+				merged[a.ServiceName] = &a
+				continue
+			}
+			if a.LastSeen.After(m.LastSeen) {
+				m.LastSeen = a.LastSeen
+			}
+			m.SpanRate1m += a.SpanRate1m
+			m.SpanRate5m += a.SpanRate5m
+			m.SpanRate15m += a.SpanRate15m
+			if a.OperationCount > m.OperationCount {
+				m.OperationCount = a.OperationCount
+			}
+			m.ClientVersions = union(m.ClientVersions, a.ClientVersions)
+			m.Hostnames = union(m.Hostnames, a.Hostnames)
+			m.ClientUUIDs = union(m.ClientUUIDs, a.ClientUUIDs)
+		}
+	}
+	if len(errs) > 0 && len(errs) == len(rr.hostPorts) {
+		return nil, multierror.Wrap(errs)
+	}
+	catalog := make([]spanstore.ServiceActivity, 0, len(merged))
+	for _, a := range merged {
+		catalog = append(catalog, *a)
+	}
+	Sort(catalog)
+	return catalog, nil
+}
+
+// union returns the sorted distinct values of a and b.
+func union(a, b []string) []string {
+	seen := make(map[string]struct{}, len(a)+len(b))
+	var values []string
+	for _, v := range append(append([]string(nil), a...), b...) {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package catalog
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+type fakeReader struct {
+	catalog []spanstore.ServiceActivity
+	err     error
+}
+
+func (r fakeReader) GetServiceCatalog(ctx context.Context) ([]spanstore.ServiceActivity, error) {
+	return r.catalog, r.err
+}
+
+func startGRPCServer(t *testing.T, reader spanstore.CatalogReader) (*grpc.Server, *grpc.ClientConn) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	api_v2.RegisterServiceCatalogServiceServer(server, NewGRPCHandler(reader))
+	go server.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	return server, conn
+}
+
+func TestRemoteReader(t *testing.T) {
+	t1 := time.Unix(1000, 0).UTC()
+	t2 := time.Unix(2000, 0).UTC()
+	server1, conn1 := startGRPCServer(t, fakeReader{catalog: []spanstore.ServiceActivity{
+		{
+			ServiceName:    "frontend",
+			LastSeen:       t2,
+			SpanRate1m:     1,
+			SpanRate5m:     2,
+			SpanRate15m:    3,
+			OperationCount: 2,
+			Hostnames:      []string{"host-1", "host-2"},
+			ClientVersions: []string{"Go-2.22.1"},
+		},
+		{ServiceName: "db", LastSeen: t1, OperationCount: 1},
+	}})
+	defer server1.Stop()
+	defer conn1.Close()
+	server2, conn2 := startGRPCServer(t, fakeReader{catalog: []spanstore.ServiceActivity{
+		{
+			ServiceName:    "frontend",
+			LastSeen:       t1,
+			SpanRate1m:     1,
+			SpanRate5m:     1,
+			SpanRate15m:    1,
+			OperationCount: 3,
+			Hostnames:      []string{"host-2", "host-3"},
+			ClientUUIDs:    []string{"uuid-1"},
+		},
+	}})
+	defer server2.Stop()
+	defer conn2.Close()
+
+	reader := NewRemoteReader(map[string]api_v2.ServiceCatalogServiceClient{
+		"collector-1": api_v2.NewServiceCatalogServiceClient(conn1),
+		"collector-2": api_v2.NewServiceCatalogServiceClient(conn2),
+	}, zap.NewNop())
+	catalog, err := reader.GetServiceCatalog(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.ServiceActivity{
+		{ServiceName: "db", LastSeen: t1, OperationCount: 1},
+		{
+			ServiceName:    "frontend",
+			LastSeen:       t2,
+			SpanRate1m:     2,
+			SpanRate5m:     3,
+			SpanRate15m:    4,
+			OperationCount: 3,
+			Hostnames:      []string{"host-1", "host-2", "host-3"},
+			ClientVersions: []string{"Go-2.22.1"},
+			ClientUUIDs:    []string{"uuid-1"},
+		},
+	}, catalog)
+}
+
+func TestRemoteReaderError(t *testing.T) {
+	server, conn := startGRPCServer(t, fakeReader{err: errors.New("catalog error")})
+	defer server.Stop()
+	defer conn.Close()
+
+	reader := NewRemoteReader(map[string]api_v2.ServiceCatalogServiceClient{
+		"collector": api_v2.NewServiceCatalogServiceClient(conn),
+	}, zap.NewNop())
+	_, err := reader.GetServiceCatalog(context.Background())
+	assert.Contains(t, err.Error(), "catalog error")
+}
+
+func TestRemoteReaderSkipsFailedCollector(t *testing.T) {
+	server1, conn1 := startGRPCServer(t, fakeReader{catalog: []spanstore.ServiceActivity{{ServiceName: "frontend", LastSeen: time.Unix(1000, 0).UTC()}}})
+	defer server1.Stop()
+	defer conn1.Close()
+	server2, conn2 := startGRPCServer(t, fakeReader{err: errors.New("catalog error")})
+	defer server2.Stop()
+	defer conn2.Close()
+
+	core, logs := observer.New(zap.WarnLevel)
+	reader := NewRemoteReader(map[string]api_v2.ServiceCatalogServiceClient{
+		"collector-1": api_v2.NewServiceCatalogServiceClient(conn1),
+		"collector-2": api_v2.NewServiceCatalogServiceClient(conn2),
+	}, zap.New(core))
+	catalog, err := reader.GetServiceCatalog(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.ServiceActivity{{ServiceName: "frontend", LastSeen: time.Unix(1000, 0).UTC()}}, catalog)
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "collector-2", logs.All()[0].ContextMap()["host-port"])
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package catalog
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	// ClientVersionTag is the process tag holding the version of the client library.
+	ClientVersionTag = "jaeger.version"
+	// HostnameTag is the process tag holding the host name of the client.
+	HostnameTag = "hostname"
+	// ClientUUIDTag is the process tag identifying an instance of the client.
+	ClientUUIDTag = "client-uuid"
+
+	// MaxTagValues is the number of distinct values remembered per process tag and service,
+	// beyond which the least recently added values are forgotten.
+	MaxTagValues = 100
+
+	// rateMinutes is the number of minutes over which the span rates are computed.
+	rateMinutes = 15
+)
+
+// Tracker records the activity of the services whose spans are written, alongside the service
+// and operation caches of the span writers. It only knows about the spans written by this process.
+type Tracker struct {
+	now func() time.Time
+
+	mux      sync.Mutex
+	services map[string]*serviceActivity
+}
+
+type serviceActivity struct {
+	lastSeen time.Time
+	// spans counts the spans per minute of the last rateMinutes minutes, indexed by minute modulo rateMinutes
+	spans [rateMinutes]uint64
+	// minutes are the minutes since the epoch counted by spans
+	minutes    [rateMinutes]int64
+	operations map[string]struct{}
+	tags       map[string]*valueSet
+}
+
+// valueSet is a set of strings that forgets the least recently added ones beyond MaxTagValues.
+type valueSet struct {
+	values map[string]struct{}
+	order  []string
+}
+
+func (s *valueSet) add(value string) {
+	if _, ok := s.values[value]; ok {
+		return
+	}
+	if len(s.order) == MaxTagValues {
+		delete(s.values, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.values[value] = struct{}{}
+	s.order = append(s.order, value)
+}
+
+func (s *valueSet) sorted() []string {
+	if s == nil || len(s.order) == 0 {
+		return nil
+	}
+	values := append([]string(nil), s.order...)
+	sort.Strings(values)
+	return values
+}
+
+// NewTracker creates an empty Tracker.
+func NewTracker() *Tracker {
+	return newTracker(time.Now)
+}
+
+func newTracker(now func() time.Time) *Tracker {
+	return &Tracker{
+		now:      now,
+		services: make(map[string]*serviceActivity),
+	}
+}
+
+// Record updates the activity of the service of the span.
+func (t *Tracker) Record(span *model.Span) {
+	if span.Process == nil {
+		return
+	}
+	now := t.now()
+	minute := now.Unix() / 60
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	activity, ok := t.services[span.Process.ServiceName]
+	if !ok {
+		activity = &serviceActivity{
+			operations: make(map[string]struct{}),
+			tags:       make(map[string]*valueSet),
+		}
+		t.services[span.Process.ServiceName] = activity
+	}
+	activity.lastSeen = now
+	i := minute % rateMinutes
+	if activity.minutes[i] != minute {
+		activity.minutes[i] = minute
+		activity.spans[i] = 0
+	}
+	activity.spans[i]++
+	activity.operations[span.OperationName] = struct{}{}
+	for _, tag := range span.Process.Tags {
+		switch tag.Key {
+		case ClientVersionTag, HostnameTag, ClientUUIDTag:
+			values, ok := activity.tags[tag.Key]
+			if !ok {
+				values = &valueSet{values: make(map[string]struct{})}
+				activity.tags[tag.Key] = values
+			}
+			values.add(tag.AsString())
+		}
+	}
+}
+
+// GetServiceCatalog implements spanstore.CatalogReader.
+func (t *Tracker) GetServiceCatalog(ctx context.Context) ([]spanstore.ServiceActivity, error) {
+	minute := t.now().Unix() / 60
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	catalog := make([]spanstore.ServiceActivity, 0, len(t.services))
+	for service, activity := range t.services {
+		catalog = append(catalog, spanstore.ServiceActivity{
+			ServiceName:    service,
+			LastSeen:       activity.lastSeen,
+			SpanRate1m:     activity.rate(minute, 1),
+			SpanRate5m:     activity.rate(minute, 5),
+			SpanRate15m:    activity.rate(minute, 15),
+			OperationCount: len(activity.operations),
+			ClientVersions: activity.tags[ClientVersionTag].sorted(),
+			Hostnames:      activity.tags[HostnameTag].sorted(),
+			ClientUUIDs:    activity.tags[ClientUUIDTag].sorted(),
+		})
+	}
+	Sort(catalog)
+	return catalog, nil
+}
+
+// rate returns the spans per second over the given number of minutes up to and including the current one.
+func (a *serviceActivity) rate(currentMinute int64, minutes int64) float64 {
+	var spans uint64
+	for i := range a.minutes {
+		if age := currentMinute - a.minutes[i]; age >= 0 && age < minutes {
+			spans += a.spans[i]
+		}
+	}
+	return float64(spans) / float64(minutes*60)
+}
+
+// Sort sorts the catalog by service name.
+func Sort(catalog []spanstore.ServiceActivity) {
+	sort.Slice(catalog, func(i, j int) bool {
+		return catalog[i].ServiceName < catalog[j].ServiceName
+	})
+}
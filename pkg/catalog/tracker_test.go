@@ -0,0 +1,114 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func testSpan(service, operation string, tags ...model.KeyValue) *model.Span {
+	return &model.Span{
+		OperationName: operation,
+		Process:       model.NewProcess(service, tags),
+	}
+}
+
+func TestTracker(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(6000, 0)}
+	tracker := newTracker(clock.Now)
+
+	tracker.Record(testSpan("frontend", "GET /",
+		model.String(ClientVersionTag, "Go-2.22.1"),
+		model.String(HostnameTag, "host-1"),
+		model.String(ClientUUIDTag, "uuid-1"),
+		model.String("ip", "10.0.0.1"),
+	))
+	for i := 0; i < 59; i++ {
+		tracker.Record(testSpan("frontend", "GET /", model.String(HostnameTag, "host-1")))
+	}
+	clock.now = clock.now.Add(2 * time.Minute)
+	for i := 0; i < 60; i++ {
+		tracker.Record(testSpan("frontend", "POST /", model.String(HostnameTag, "host-2")))
+	}
+	tracker.Record(testSpan("backend", "query"))
+	// a span without process is ignored
+	tracker.Record(&model.Span{OperationName: "ignored"})
+
+	catalog, err := tracker.GetServiceCatalog(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.ServiceActivity{
+		{
+			ServiceName:    "backend",
+			LastSeen:       time.Unix(6120, 0),
+			SpanRate1m:     1.0 / 60,
+			SpanRate5m:     1.0 / 300,
+			SpanRate15m:    1.0 / 900,
+			OperationCount: 1,
+		},
+		{
+			ServiceName:    "frontend",
+			LastSeen:       time.Unix(6120, 0),
+			SpanRate1m:     1,
+			SpanRate5m:     120.0 / 300,
+			SpanRate15m:    120.0 / 900,
+			OperationCount: 2,
+			ClientVersions: []string{"Go-2.22.1"},
+			Hostnames:      []string{"host-1", "host-2"},
+			ClientUUIDs:    []string{"uuid-1"},
+		},
+	}, catalog)
+
+	// old minutes no longer count, and their buckets are reused
+	clock.now = clock.now.Add(15 * time.Minute)
+	tracker.Record(testSpan("frontend", "GET /"))
+	catalog, err = tracker.GetServiceCatalog(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1.0/60, catalog[1].SpanRate1m)
+	assert.Equal(t, 1.0/900, catalog[1].SpanRate15m)
+	assert.Equal(t, 0.0, catalog[0].SpanRate15m)
+}
+
+func TestTrackerForgetsOldestTagValues(t *testing.T) {
+	tracker := NewTracker()
+	for i := 0; i < MaxTagValues+2; i++ {
+		tracker.Record(testSpan("svc", "op", model.String(ClientUUIDTag, fmt.Sprintf("uuid-%03d", i))))
+	}
+	// a known value is not added again
+	tracker.Record(testSpan("svc", "op", model.String(ClientUUIDTag, "uuid-101")))
+
+	catalog, err := tracker.GetServiceCatalog(context.Background())
+	require.NoError(t, err)
+	uuids := catalog[0].ClientUUIDs
+	require.Len(t, uuids, MaxTagValues)
+	assert.Equal(t, "uuid-002", uuids[0])
+	assert.Equal(t, "uuid-101", uuids[MaxTagValues-1])
+}
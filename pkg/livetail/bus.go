@@ -0,0 +1,85 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package livetail
+
+import (
+	"sync"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// Bus fans out published spans to the subscriptions whose filter they match.
+// Publishing never blocks: a subscriber whose buffer is full is dropped.
+type Bus struct {
+	bufferSize int
+
+	mux           sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+}
+
+// NewBus creates a Bus whose subscribers buffer up to bufferSize spans,
+// or DefaultBufferSize if bufferSize is not positive.
+func NewBus(bufferSize int) *Bus {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Bus{
+		bufferSize:    bufferSize,
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe implements Source.
+func (b *Bus) Subscribe(filter Filter) (*Subscription, error) {
+	var s *Subscription
+	s = newSubscription(filter, b.bufferSize, func() { b.remove(s) })
+	b.mux.Lock()
+	b.subscriptions[s] = struct{}{}
+	b.mux.Unlock()
+	return s, nil
+}
+
+// Publish sends the span to the matching subscribers.
+func (b *Bus) Publish(span *model.Span) {
+	b.mux.RLock()
+	if len(b.subscriptions) == 0 {
+		b.mux.RUnlock()
+		return
+	}
+	var dropped []*Subscription
+	for s := range b.subscriptions {
+		if s.filter.Matches(span) && !s.send(span) {
+			dropped = append(dropped, s)
+		}
+	}
+	b.mux.RUnlock()
+	// the dropped subscriptions are removed by their onClose, which needs the write lock
+	for _, s := range dropped {
+		s.onClose()
+	}
+}
+
+// Subscribers returns the number of active subscriptions.
+func (b *Bus) Subscribers() int {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return len(b.subscriptions)
+}
+
+func (b *Bus) remove(s *Subscription) {
+	b.mux.Lock()
+	delete(b.subscriptions, s)
+	b.mux.Unlock()
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package livetail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func testSpan(service, operation string, tags ...model.KeyValue) *model.Span {
+	return &model.Span{
+		OperationName: operation,
+		Tags:          tags,
+		Process:       model.NewProcess(service, []model.KeyValue{model.String("hostname", "host-1")}),
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	span := testSpan("svc", "op", model.String("http.method", "GET"), model.Int64("http.status_code", 200))
+	tests := []struct {
+		name    string
+		filter  Filter
+		matches bool
+	}{
+		{name: "empty", filter: Filter{}, matches: true},
+		{name: "service", filter: Filter{ServiceName: "svc"}, matches: true},
+		{name: "other service", filter: Filter{ServiceName: "other"}, matches: false},
+		{name: "operation", filter: Filter{ServiceName: "svc", OperationName: "op"}, matches: true},
+		{name: "other operation", filter: Filter{OperationName: "other"}, matches: false},
+		{name: "span tags", filter: Filter{Tags: map[string]string{"http.method": "GET", "http.status_code": "200"}}, matches: true},
+		{name: "process tag", filter: Filter{Tags: map[string]string{"hostname": "host-1"}}, matches: true},
+		{name: "other tag value", filter: Filter{Tags: map[string]string{"http.method": "POST"}}, matches: false},
+		{name: "missing tag", filter: Filter{Tags: map[string]string{"error": "true"}}, matches: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.matches, test.filter.Matches(span))
+		})
+	}
+	assert.False(t, Filter{ServiceName: "svc"}.Matches(&model.Span{}))
+}
+
+func TestFilterRequest(t *testing.T) {
+	filter := Filter{ServiceName: "svc", OperationName: "op", Tags: map[string]string{"k": "v"}}
+	assert.Equal(t, filter, FilterFromRequest(filter.Request()))
+}
+
+func TestBusPublish(t *testing.T) {
+	bus := NewBus(10)
+	svcSub, err := bus.Subscribe(Filter{ServiceName: "svc"})
+	require.NoError(t, err)
+	allSub, err := bus.Subscribe(Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, bus.Subscribers())
+
+	span1 := testSpan("svc", "op")
+	span2 := testSpan("other", "op")
+	bus.Publish(span1)
+	bus.Publish(span2)
+
+	assert.Equal(t, span1, <-svcSub.Spans())
+	assert.Len(t, svcSub.Spans(), 0)
+	assert.Equal(t, span1, <-allSub.Spans())
+	assert.Equal(t, span2, <-allSub.Spans())
+
+	svcSub.Close()
+	svcSub.Close()
+	_, ok := <-svcSub.Spans()
+	assert.False(t, ok)
+	assert.NoError(t, svcSub.Err())
+	assert.Equal(t, 1, bus.Subscribers())
+
+	// publishing to the remaining subscriber still works
+	bus.Publish(span1)
+	assert.Equal(t, span1, <-allSub.Spans())
+	allSub.Close()
+	assert.Equal(t, 0, bus.Subscribers())
+}
+
+func TestBusDropsSlowSubscriber(t *testing.T) {
+	bus := NewBus(2)
+	slowSub, err := bus.Subscribe(Filter{})
+	require.NoError(t, err)
+	fastSub, err := bus.Subscribe(Filter{})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		bus.Publish(testSpan("svc", "op"))
+		<-fastSub.Spans()
+	}
+
+	assert.Equal(t, ErrSlowSubscriber, slowSub.Err())
+	assert.Equal(t, 1, bus.Subscribers())
+	// the buffered spans are still delivered before the channel is closed
+	var received int
+	for range slowSub.Spans() {
+		received++
+	}
+	assert.Equal(t, 2, received)
+	slowSub.Close()
+	assert.Equal(t, ErrSlowSubscriber, slowSub.Err())
+	fastSub.Close()
+}
+
+func TestNewBusDefaultBufferSize(t *testing.T) {
+	bus := NewBus(0)
+	sub, err := bus.Subscribe(Filter{})
+	require.NoError(t, err)
+	defer sub.Close()
+	assert.Equal(t, DefaultBufferSize, cap(sub.spans))
+}
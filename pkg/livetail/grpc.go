@@ -0,0 +1,165 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package livetail
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+// maxSpansInBatch is the maximum number of buffered spans sent at once by Forward.
+const maxSpansInBatch = 10
+
+// errStreamClosed is the error of a remote subscription whose collector closed the stream.
+var errStreamClosed = errors.New("the collector closed the live tail stream")
+
+// Forward sends the spans of the subscription in batches until the context is done or the
+// subscription ends, in which case the reason is returned as a gRPC status error.
+func Forward(ctx context.Context, sub *Subscription, send func(spans []model.Span) error) error {
+	batch := make([]model.Span, 0, maxSpansInBatch)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case span, ok := <-sub.Spans():
+			if !ok {
+				return statusOf(sub.Err())
+			}
+			batch = append(batch[:0], *span)
+		}
+		// add the spans that are already buffered
+	drain:
+		for len(batch) < maxSpansInBatch {
+			select {
+			case span, ok := <-sub.Spans():
+				if !ok {
+					break drain
+				}
+				batch = append(batch, *span)
+			default:
+				break drain
+			}
+		}
+		if err := send(batch); err != nil {
+			return err
+		}
+	}
+}
+
+func statusOf(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case err == ErrSlowSubscriber:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Unavailable, err.Error())
+	}
+}
+
+// GRPCHandler implements the gRPC SpanTailService of a collector.
+type GRPCHandler struct {
+	source Source
+}
+
+// NewGRPCHandler creates a GRPCHandler streaming the spans of the source.
+func NewGRPCHandler(source Source) *GRPCHandler {
+	return &GRPCHandler{source: source}
+}
+
+// TailSpans implements gRPC SpanTailService.
+func (h *GRPCHandler) TailSpans(r *api_v2.TailSpansRequest, stream api_v2.SpanTailService_TailSpansServer) error {
+	sub, err := h.source.Subscribe(FilterFromRequest(r))
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+	return Forward(stream.Context(), sub, func(spans []model.Span) error {
+		return stream.Send(&api_v2.TailSpansResponse{Spans: spans})
+	})
+}
+
+// RemoteSource is a Source that subscribes to the SpanTailService of collectors.
+// A subscription merges the streams of all the collectors and ends when any of them does.
+type RemoteSource struct {
+	clients    []api_v2.SpanTailServiceClient
+	bufferSize int
+}
+
+// NewRemoteSource creates a RemoteSource whose subscribers buffer up to bufferSize spans,
+// or DefaultBufferSize if bufferSize is not positive.
+func NewRemoteSource(clients []api_v2.SpanTailServiceClient, bufferSize int) *RemoteSource {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &RemoteSource{
+		clients:    clients,
+		bufferSize: bufferSize,
+	}
+}
+
+// Subscribe implements Source.
+func (rs *RemoteSource) Subscribe(filter Filter) (*Subscription, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	streams := make([]api_v2.SpanTailService_TailSpansClient, 0, len(rs.clients))
+	for _, client := range rs.clients {
+		stream, err := client.TailSpans(ctx, filter.Request())
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		streams = append(streams, stream)
+	}
+	sub := newSubscription(filter, rs.bufferSize, cancel)
+	for _, stream := range streams {
+		go rs.receive(ctx, sub, stream)
+	}
+	return sub, nil
+}
+
+func (rs *RemoteSource) receive(ctx context.Context, sub *Subscription, stream api_v2.SpanTailService_TailSpansClient) {
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				// the subscription was closed
+				return
+			}
+			switch {
+			case err == io.EOF:
+				err = errStreamClosed
+			case status.Code(err) == codes.ResourceExhausted:
+				err = ErrSlowSubscriber
+			}
+			if sub.end(err) {
+				sub.onClose()
+			}
+			return
+		}
+		for i := range resp.Spans {
+			if !sub.send(&resp.Spans[i]) {
+				sub.onClose()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package livetail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+func startGRPCServer(t *testing.T, source Source) (*grpc.Server, *grpc.ClientConn) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	api_v2.RegisterSpanTailServiceServer(server, NewGRPCHandler(source))
+	go server.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	return server, conn
+}
+
+func waitForSubscribers(t *testing.T, bus *Bus, n int) {
+	for i := 0; i < 100 && bus.Subscribers() != n; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, n, bus.Subscribers())
+}
+
+func TestRemoteSource(t *testing.T) {
+	bus := NewBus(10)
+	server, conn := startGRPCServer(t, bus)
+	defer server.Stop()
+	defer conn.Close()
+
+	source := NewRemoteSource([]api_v2.SpanTailServiceClient{api_v2.NewSpanTailServiceClient(conn)}, 10)
+	sub, err := source.Subscribe(Filter{ServiceName: "svc"})
+	require.NoError(t, err)
+	waitForSubscribers(t, bus, 1)
+
+	span := testSpan("svc", "op")
+	span.TraceID = model.NewTraceID(0, 1)
+	span.SpanID = model.NewSpanID(2)
+	bus.Publish(testSpan("other", "op"))
+	bus.Publish(span)
+
+	select {
+	case received := <-sub.Spans():
+		assert.Equal(t, span.TraceID, received.TraceID)
+		assert.Equal(t, span.SpanID, received.SpanID)
+		assert.Equal(t, "svc", received.Process.ServiceName)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the span was not received")
+	}
+
+	// closing the subscription cancels the stream, which unsubscribes from the collector
+	sub.Close()
+	waitForSubscribers(t, bus, 0)
+	assert.NoError(t, sub.Err())
+}
+
+func TestRemoteSourceSubscribeError(t *testing.T) {
+	client := &fakeTailClient{err: errors.New("no connection")}
+	source := NewRemoteSource([]api_v2.SpanTailServiceClient{client}, 0)
+	_, err := source.Subscribe(Filter{})
+	assert.EqualError(t, err, "no connection")
+}
+
+func TestRemoteSourceStreamEnd(t *testing.T) {
+	tests := []struct {
+		name       string
+		bufferSize int
+		responses  []*api_v2.TailSpansResponse
+		err        error
+		expected   error
+	}{
+		{
+			name:     "collector closes the stream",
+			err:      io.EOF,
+			expected: errStreamClosed,
+		},
+		{
+			name:     "collector drops the subscriber",
+			err:      status.Error(codes.ResourceExhausted, "too slow"),
+			expected: ErrSlowSubscriber,
+		},
+		{
+			name:       "subscriber is too slow",
+			bufferSize: 1,
+			responses:  []*api_v2.TailSpansResponse{{Spans: make([]model.Span, 2)}},
+			err:        io.EOF,
+			expected:   ErrSlowSubscriber,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &fakeTailClient{stream: &fakeTailStream{responses: test.responses, err: test.err}}
+			source := NewRemoteSource([]api_v2.SpanTailServiceClient{client}, test.bufferSize)
+			sub, err := source.Subscribe(Filter{})
+			require.NoError(t, err)
+			// the spans are not consumed until the subscription ends
+			for i := 0; i < 100 && sub.Err() == nil; i++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+			assert.Equal(t, test.expected, sub.Err())
+		})
+	}
+}
+
+func TestForward(t *testing.T) {
+	bus := NewBus(2 * maxSpansInBatch)
+	sub, err := bus.Subscribe(Filter{})
+	require.NoError(t, err)
+	for i := 0; i < maxSpansInBatch+1; i++ {
+		bus.Publish(testSpan("svc", "op"))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var batches []int
+	err = Forward(ctx, sub, func(spans []model.Span) error {
+		batches = append(batches, len(spans))
+		if len(batches) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{maxSpansInBatch, 1}, batches)
+
+	bus.Publish(testSpan("svc", "op"))
+	err = Forward(context.Background(), sub, func(spans []model.Span) error {
+		return errors.New("send error")
+	})
+	assert.EqualError(t, err, "send error")
+
+	sub.Close()
+	assert.NoError(t, Forward(context.Background(), sub, nil))
+
+	sub, err = bus.Subscribe(Filter{})
+	require.NoError(t, err)
+	sub.end(ErrSlowSubscriber)
+	err = Forward(context.Background(), sub, nil)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	sub, err = bus.Subscribe(Filter{})
+	require.NoError(t, err)
+	sub.end(errStreamClosed)
+	err = Forward(context.Background(), sub, nil)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+type fakeTailClient struct {
+	stream *fakeTailStream
+	err    error
+}
+
+func (c *fakeTailClient) TailSpans(ctx context.Context, in *api_v2.TailSpansRequest, opts ...grpc.CallOption) (api_v2.SpanTailService_TailSpansClient, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.stream, nil
+}
+
+type fakeTailStream struct {
+	grpc.ClientStream
+	responses []*api_v2.TailSpansResponse
+	err       error
+}
+
+func (s *fakeTailStream) Recv() (*api_v2.TailSpansResponse, error) {
+	if len(s.responses) == 0 {
+		return nil, s.err
+	}
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	return resp, nil
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package livetail
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+// DefaultBufferSize is the default number of spans buffered for a subscriber.
+const DefaultBufferSize = 1000
+
+// ErrSlowSubscriber is the error of a subscription that was dropped because its buffer was full.
+var ErrSlowSubscriber = errors.New("live tail subscriber dropped because it did not keep up with the spans")
+
+// Source is a source of live spans.
+type Source interface {
+	// Subscribe returns a subscription to the spans matching the filter.
+	Subscribe(filter Filter) (*Subscription, error)
+}
+
+// Filter selects the spans of a subscription. Empty fields match any span.
+type Filter struct {
+	ServiceName   string
+	OperationName string
+	// Tags are matched against the span tags and the process tags.
+	Tags map[string]string
+}
+
+// FilterFromRequest returns the filter of a TailSpans request.
+func FilterFromRequest(r *api_v2.TailSpansRequest) Filter {
+	return Filter{
+		ServiceName:   r.ServiceName,
+		OperationName: r.OperationName,
+		Tags:          r.Tags,
+	}
+}
+
+// Request returns the TailSpans request of the filter.
+func (f Filter) Request() *api_v2.TailSpansRequest {
+	return &api_v2.TailSpansRequest{
+		ServiceName:   f.ServiceName,
+		OperationName: f.OperationName,
+		Tags:          f.Tags,
+	}
+}
+
+// Matches returns true if the span matches all the criteria of the filter.
+func (f Filter) Matches(span *model.Span) bool {
+	if f.ServiceName != "" && (span.Process == nil || span.Process.ServiceName != f.ServiceName) {
+		return false
+	}
+	if f.OperationName != "" && span.OperationName != f.OperationName {
+		return false
+	}
+	for key, value := range f.Tags {
+		if !hasTag(span, key, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTag(span *model.Span, key, value string) bool {
+	if tag, ok := model.KeyValues(span.Tags).FindByKey(key); ok && tag.AsString() == value {
+		return true
+	}
+	if span.Process == nil {
+		return false
+	}
+	tag, ok := model.KeyValues(span.Process.Tags).FindByKey(key)
+	return ok && tag.AsString() == value
+}
+
+// Subscription is a stream of live spans. The spans are shared with the other subscribers
+// and with the span writer, so they must not be modified.
+type Subscription struct {
+	filter  Filter
+	spans   chan *model.Span
+	onClose func()
+
+	mux    sync.Mutex
+	closed bool
+	err    error
+}
+
+func newSubscription(filter Filter, bufferSize int, onClose func()) *Subscription {
+	return &Subscription{
+		filter:  filter,
+		spans:   make(chan *model.Span, bufferSize),
+		onClose: onClose,
+	}
+}
+
+// Spans returns the channel of the spans, which is closed when the subscription ends.
+func (s *Subscription) Spans() <-chan *model.Span {
+	return s.spans
+}
+
+// Err returns the reason why the subscription ended, e.g. ErrSlowSubscriber,
+// or nil if it is still active or was closed by the subscriber.
+func (s *Subscription) Err() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.err
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	if s.end(nil) && s.onClose != nil {
+		s.onClose()
+	}
+}
+
+// send adds the span to the buffer without blocking. If the buffer is full, the subscription
+// ends with ErrSlowSubscriber and false is returned, leaving the call of onClose to the caller.
+func (s *Subscription) send(span *model.Span) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return true
+	}
+	select {
+	case s.spans <- span:
+		return true
+	default:
+		s.closeLocked(ErrSlowSubscriber)
+		return false
+	}
+}
+
+// end closes the channel of the spans and returns true, unless it is already closed.
+func (s *Subscription) end(err error) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return false
+	}
+	s.closeLocked(err)
+	return true
+}
+
+func (s *Subscription) closeLocked(err error) {
+	s.closed = true
+	s.err = err
+	close(s.spans)
+}
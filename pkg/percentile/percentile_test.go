@@ -0,0 +1,35 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package percentile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurations(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i))
+	}
+	assert.Equal(t, time.Duration(50), Durations(latencies, 50))
+	assert.Equal(t, time.Duration(95), Durations(latencies, 95))
+	assert.Equal(t, time.Duration(99), Durations(latencies, 99))
+	assert.Equal(t, time.Duration(1), Durations(latencies, 0))
+	assert.Equal(t, time.Duration(3), Durations([]time.Duration{1, 2, 3}, 99))
+	assert.Equal(t, time.Duration(0), Durations(nil, 50))
+}
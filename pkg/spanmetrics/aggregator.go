@@ -0,0 +1,417 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+const (
+	// DefaultMaxOperations is the default number of distinct operations per service with their own metrics.
+	DefaultMaxOperations = 500
+
+	// DefaultMaxSeries is the default number of distinct series per service.
+	DefaultMaxSeries = 2000
+
+	// DefaultMaxDimensionValues is the default number of distinct values per dimension and service.
+	DefaultMaxDimensionValues = 100
+
+	// OtherOperations is the operation name of the metrics of the operations beyond the maximum per service,
+	// and of the series beyond the maximum per service.
+	OtherOperations = "other-operations"
+
+	// OtherValue is the dimension value of the metrics of the values beyond the maximum per dimension and service,
+	// and of the series beyond the maximum per service.
+	OtherValue = "other"
+
+	// Window is the time during which the aggregates are kept in memory for Reader.
+	// The series without spans during the Window are evicted.
+	Window = 15 * time.Minute
+
+	windowMinutes = int64(Window / time.Minute)
+
+	serviceTag   = "service"
+	operationTag = "operation"
+	spanKindTag  = "span_kind"
+)
+
+// DefaultLatencyBuckets are the default upper bounds of the latency histogram buckets.
+var DefaultLatencyBuckets = []time.Duration{
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Options configures the Aggregator.
+type Options struct {
+	// MaxOperations is the number of distinct operations per service with their own metrics,
+	// the spans of the other operations are counted under OtherOperations.
+	MaxOperations int
+	// MaxSeries is the number of distinct series per service, i.e. combinations of operation,
+	// span kind and dimension values. The spans of the other series are counted under OtherOperations,
+	// with the OtherValue of the dimensions.
+	MaxSeries int
+	// Dimensions are span or process tag keys whose values are added to the metric tags.
+	Dimensions []string
+	// MaxDimensionValues is the number of distinct values per dimension and service,
+	// the other values are replaced by OtherValue.
+	MaxDimensionValues int
+	// LatencyBuckets are the upper bounds of the latency histogram buckets, in increasing order.
+	LatencyBuckets []time.Duration
+}
+
+// seriesKey identifies the metrics of a service, operation, span kind and dimension values.
+type seriesKey struct {
+	service    string
+	operation  string
+	spanKind   string
+	dimensions string
+}
+
+type series struct {
+	dimensions map[string]string
+	calls      metrics.Counter
+	errors     metrics.Counter
+	latency    metrics.Timer
+	// minutes are the minutes since the epoch aggregated by counts, indexed by minute modulo windowMinutes
+	minutes [windowMinutes]int64
+	counts  [windowMinutes]counts
+	// lastMinute is the latest minute since the epoch with spans
+	lastMinute int64
+}
+
+type counts struct {
+	calls   uint64
+	errors  uint64
+	latency []uint64
+}
+
+// Aggregator computes the request rate, error rate and latency of the spans per service, operation,
+// span kind and configured dimensions, i.e. RED metrics. They are reported to the metrics factory
+// and the aggregates of the last Window are kept in memory for Reader.
+type Aggregator struct {
+	metricsFactory     metrics.Factory
+	maxOperations      int
+	maxSeries          int
+	dimensions         []string
+	maxDimensionValues int
+	buckets            []time.Duration
+	now                func() time.Time
+
+	mux          sync.Mutex
+	services     map[string]*serviceLimits
+	series       map[seriesKey]*series
+	evictedUntil int64
+}
+
+// serviceLimits tracks the operations, dimension values and series of a service to limit their number.
+type serviceLimits struct {
+	operations map[string]struct{}
+	// dimensionValues are indexed like the dimensions of the Aggregator
+	dimensionValues []map[string]struct{}
+	series          int
+}
+
+// NewAggregator creates an Aggregator reporting to the metrics factory. Options left empty take their defaults.
+func NewAggregator(metricsFactory metrics.Factory, opts Options) *Aggregator {
+	return newAggregator(metricsFactory, opts, time.Now)
+}
+
+func newAggregator(metricsFactory metrics.Factory, opts Options, now func() time.Time) *Aggregator {
+	if opts.MaxOperations <= 0 {
+		opts.MaxOperations = DefaultMaxOperations
+	}
+	if opts.MaxSeries <= 0 {
+		opts.MaxSeries = DefaultMaxSeries
+	}
+	if opts.MaxDimensionValues <= 0 {
+		opts.MaxDimensionValues = DefaultMaxDimensionValues
+	}
+	if len(opts.LatencyBuckets) == 0 {
+		opts.LatencyBuckets = DefaultLatencyBuckets
+	}
+	return &Aggregator{
+		metricsFactory:     metricsFactory,
+		maxOperations:      opts.MaxOperations,
+		maxSeries:          opts.MaxSeries,
+		dimensions:         opts.Dimensions,
+		maxDimensionValues: opts.MaxDimensionValues,
+		buckets:            opts.LatencyBuckets,
+		now:                now,
+		services:           make(map[string]*serviceLimits),
+		series:             make(map[seriesKey]*series),
+	}
+}
+
+// ProcessSpan counts the span in the metrics of its service, operation, span kind and dimensions.
+func (a *Aggregator) ProcessSpan(span *model.Span) {
+	if span.Process == nil {
+		return
+	}
+	spanKind, _ := span.GetSpanKind()
+	failed := span.IsError()
+	minute := a.now().Unix() / 60
+	dimensions := a.dimensionValues(span)
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.evictStaleSeries(minute)
+	limits := a.serviceLimits(span.Process.ServiceName)
+	for i, dimension := range a.dimensions {
+		dimensions[dimension] = limitedValue(limits.dimensionValues[i], dimensions[dimension], a.maxDimensionValues, OtherValue)
+	}
+	key := seriesKey{
+		service:    span.Process.ServiceName,
+		operation:  limitedValue(limits.operations, span.OperationName, a.maxOperations, OtherOperations),
+		spanKind:   spanKind,
+		dimensions: encodeDimensions(a.dimensions, dimensions),
+	}
+	s, ok := a.series[key]
+	if !ok && limits.series >= a.maxSeries {
+		key, dimensions = a.otherSeries(key)
+		s, ok = a.series[key]
+	}
+	if !ok {
+		s = a.newSeries(key, dimensions)
+		a.series[key] = s
+		limits.series++
+	}
+	s.lastMinute = minute
+
+	s.calls.Inc(1)
+	if failed {
+		s.errors.Inc(1)
+	}
+	s.latency.Record(span.Duration)
+
+	c := &s.counts[minute%windowMinutes]
+	if s.minutes[minute%windowMinutes] != minute {
+		s.minutes[minute%windowMinutes] = minute
+		*c = counts{latency: make([]uint64, len(a.buckets)+1)}
+	}
+	c.calls++
+	if failed {
+		c.errors++
+	}
+	c.latency[bucketIndex(a.buckets, span.Duration)]++
+}
+
+func (a *Aggregator) serviceLimits(service string) *serviceLimits {
+	limits, ok := a.services[service]
+	if !ok {
+		limits = &serviceLimits{
+			operations:      make(map[string]struct{}),
+			dimensionValues: make([]map[string]struct{}, len(a.dimensions)),
+		}
+		for i := range limits.dimensionValues {
+			limits.dimensionValues[i] = make(map[string]struct{})
+		}
+		a.services[service] = limits
+	}
+	return limits
+}
+
+// limitedValue returns the value under which the span is counted, i.e. other once there are max distinct values.
+func limitedValue(values map[string]struct{}, value string, max int, other string) string {
+	if _, ok := values[value]; ok {
+		return value
+	}
+	if len(values) >= max {
+		return other
+	}
+	values[value] = struct{}{}
+	return value
+}
+
+// otherSeries returns the key and the dimension values of the series counting the spans
+// of a service beyond its maximum number of series.
+func (a *Aggregator) otherSeries(key seriesKey) (seriesKey, map[string]string) {
+	var dimensions map[string]string
+	if len(a.dimensions) > 0 {
+		dimensions = make(map[string]string, len(a.dimensions))
+		for _, dimension := range a.dimensions {
+			dimensions[dimension] = OtherValue
+		}
+	}
+	key.operation = OtherOperations
+	key.dimensions = encodeDimensions(a.dimensions, dimensions)
+	return key, dimensions
+}
+
+// evictStaleSeries drops the series without spans during the Window, at most once per minute,
+// and frees their operations and dimension values for new series.
+func (a *Aggregator) evictStaleSeries(minute int64) {
+	if minute <= a.evictedUntil {
+		return
+	}
+	a.evictedUntil = minute
+	evicted := false
+	for key, s := range a.series {
+		if minute-s.lastMinute >= windowMinutes {
+			delete(a.series, key)
+			evicted = true
+		}
+	}
+	if !evicted {
+		return
+	}
+	a.services = make(map[string]*serviceLimits)
+	for key, s := range a.series {
+		limits := a.serviceLimits(key.service)
+		if key.operation != OtherOperations {
+			limits.operations[key.operation] = struct{}{}
+		}
+		for i, dimension := range a.dimensions {
+			if value := s.dimensions[dimension]; value != OtherValue {
+				limits.dimensionValues[i][value] = struct{}{}
+			}
+		}
+		limits.series++
+	}
+}
+
+func (a *Aggregator) newSeries(key seriesKey, dimensions map[string]string) *series {
+	tags := map[string]string{
+		serviceTag:   key.service,
+		operationTag: key.operation,
+		spanKindTag:  key.spanKind,
+	}
+	for _, dimension := range a.dimensions {
+		tags[tagName(dimension)] = dimensions[dimension]
+	}
+	return &series{
+		dimensions: dimensions,
+		calls:      a.metricsFactory.Counter(metrics.Options{Name: "calls", Tags: tags}),
+		errors:     a.metricsFactory.Counter(metrics.Options{Name: "errors", Tags: tags}),
+		latency:    a.metricsFactory.Timer(metrics.TimerOptions{Name: "latency", Tags: tags, Buckets: a.buckets}),
+	}
+}
+
+// dimensionValues returns the values of the configured dimensions in the span tags, or else in its process tags.
+func (a *Aggregator) dimensionValues(span *model.Span) map[string]string {
+	if len(a.dimensions) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(a.dimensions))
+	for _, dimension := range a.dimensions {
+		if kv, ok := model.KeyValues(span.Tags).FindByKey(dimension); ok {
+			values[dimension] = kv.AsString()
+		} else if kv, ok := model.KeyValues(span.Process.Tags).FindByKey(dimension); ok {
+			values[dimension] = kv.AsString()
+		} else {
+			values[dimension] = ""
+		}
+	}
+	return values
+}
+
+// GetSpanMetrics implements Reader.
+func (a *Aggregator) GetSpanMetrics(ctx context.Context, query Query) ([]Series, error) {
+	minutes := lookbackMinutes(query.Lookback)
+	endTime := query.EndTime
+	if endTime.IsZero() {
+		endTime = a.now()
+	}
+	currentMinute := endTime.Unix() / 60
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	var result []Series
+	for key, s := range a.series {
+		if key.service != query.ServiceName {
+			continue
+		}
+		r := Series{
+			ServiceName:    key.service,
+			OperationName:  key.operation,
+			SpanKind:       key.spanKind,
+			Dimensions:     s.dimensions,
+			LatencyBuckets: a.buckets,
+			LatencyCounts:  make([]uint64, len(a.buckets)+1),
+		}
+		for i := range s.minutes {
+			if age := currentMinute - s.minutes[i]; age < 0 || age >= minutes || s.counts[i].latency == nil {
+				continue
+			}
+			r.Calls += s.counts[i].calls
+			r.Errors += s.counts[i].errors
+			for j, n := range s.counts[i].latency {
+				r.LatencyCounts[j] += n
+			}
+		}
+		if r.Calls > 0 {
+			result = append(result, r)
+		}
+	}
+	Sort(result)
+	return result, nil
+}
+
+// EffectiveLookback returns the lookback over which GetSpanMetrics aggregates, i.e. the whole minutes
+// up to and including the current one covering the lookback, at most the Window.
+func EffectiveLookback(lookback time.Duration) time.Duration {
+	return time.Duration(lookbackMinutes(lookback)) * time.Minute
+}
+
+func lookbackMinutes(lookback time.Duration) int64 {
+	minutes := int64((lookback + time.Minute - 1) / time.Minute)
+	if minutes <= 0 || minutes > windowMinutes {
+		return windowMinutes
+	}
+	return minutes
+}
+
+func bucketIndex(buckets []time.Duration, d time.Duration) int {
+	return sort.Search(len(buckets), func(i int) bool {
+		return d <= buckets[i]
+	})
+}
+
+// encodeDimensions encodes the dimension values in the order of the dimensions, to be used in a map key.
+func encodeDimensions(dimensions []string, values map[string]string) string {
+	var sb strings.Builder
+	for _, dimension := range dimensions {
+		sb.WriteString(values[dimension])
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+// tagName makes a dimension usable as a metric tag name, e.g. by Prometheus.
+func tagName(dimension string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, dimension)
+}
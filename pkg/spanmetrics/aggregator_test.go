@@ -0,0 +1,237 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func testSpan(service, operation string, duration time.Duration, tags ...model.KeyValue) *model.Span {
+	return &model.Span{
+		OperationName: operation,
+		Duration:      duration,
+		Tags:          tags,
+		Process:       model.NewProcess(service, []model.KeyValue{model.String("region", "eu")}),
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(6000, 0)}
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	buckets := []time.Duration{10 * time.Millisecond, 100 * time.Millisecond}
+	aggregator := newAggregator(metricsFactory, Options{LatencyBuckets: buckets}, clock.Now)
+
+	server := model.String("span.kind", "server")
+	aggregator.ProcessSpan(testSpan("frontend", "GET /", 5*time.Millisecond, server))
+	aggregator.ProcessSpan(testSpan("frontend", "GET /", 50*time.Millisecond, server, model.Bool("error", true)))
+	clock.now = clock.now.Add(2 * time.Minute)
+	aggregator.ProcessSpan(testSpan("frontend", "GET /", time.Second, server))
+	aggregator.ProcessSpan(testSpan("frontend", "query", time.Millisecond))
+	aggregator.ProcessSpan(testSpan("backend", "query", time.Millisecond))
+	// a span without process is ignored
+	aggregator.ProcessSpan(&model.Span{OperationName: "ignored"})
+
+	tags := map[string]string{"service": "frontend", "operation": "GET /", "span_kind": "server"}
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "calls", Tags: tags, Value: 3},
+		metricstest.ExpectedMetric{Name: "errors", Tags: tags, Value: 1},
+		metricstest.ExpectedMetric{
+			Name:  "calls",
+			Tags:  map[string]string{"service": "backend", "operation": "query", "span_kind": ""},
+			Value: 1,
+		},
+	)
+
+	series, err := aggregator.GetSpanMetrics(context.Background(), Query{ServiceName: "frontend"})
+	require.NoError(t, err)
+	assert.Equal(t, []Series{
+		{
+			ServiceName:    "frontend",
+			OperationName:  "GET /",
+			SpanKind:       "server",
+			Calls:          3,
+			Errors:         1,
+			LatencyBuckets: buckets,
+			LatencyCounts:  []uint64{1, 1, 1},
+		},
+		{
+			ServiceName:    "frontend",
+			OperationName:  "query",
+			Calls:          1,
+			LatencyBuckets: buckets,
+			LatencyCounts:  []uint64{1, 0, 0},
+		},
+	}, series)
+
+	series, err = aggregator.GetSpanMetrics(context.Background(), Query{ServiceName: "frontend", Lookback: time.Minute})
+	require.NoError(t, err)
+	require.Len(t, series, 2)
+	assert.EqualValues(t, 1, series[0].Calls)
+	assert.EqualValues(t, 0, series[0].Errors)
+
+	series, err = aggregator.GetSpanMetrics(context.Background(), Query{
+		ServiceName: "frontend",
+		EndTime:     time.Unix(6000, 0),
+		Lookback:    time.Minute,
+	})
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.EqualValues(t, 2, series[0].Calls)
+	assert.EqualValues(t, 1, series[0].Errors)
+
+	// the aggregates older than the window are forgotten
+	clock.now = clock.now.Add(Window)
+	series, err = aggregator.GetSpanMetrics(context.Background(), Query{ServiceName: "frontend"})
+	require.NoError(t, err)
+	assert.Empty(t, series)
+}
+
+func TestAggregatorMaxOperations(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	aggregator := NewAggregator(metricsFactory, Options{MaxOperations: 2})
+
+	for i := 0; i < 4; i++ {
+		aggregator.ProcessSpan(testSpan("frontend", fmt.Sprintf("op-%d", i), time.Millisecond))
+	}
+	aggregator.ProcessSpan(testSpan("frontend", "op-0", time.Millisecond))
+	aggregator.ProcessSpan(testSpan("backend", "op-3", time.Millisecond))
+
+	series, err := aggregator.GetSpanMetrics(context.Background(), Query{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, series, 3)
+	assert.Equal(t, "op-0", series[0].OperationName)
+	assert.EqualValues(t, 2, series[0].Calls)
+	assert.Equal(t, "op-1", series[1].OperationName)
+	assert.Equal(t, OtherOperations, series[2].OperationName)
+	assert.EqualValues(t, 2, series[2].Calls)
+
+	series, err = aggregator.GetSpanMetrics(context.Background(), Query{ServiceName: "backend"})
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, "op-3", series[0].OperationName)
+}
+
+func TestAggregatorMaxSeries(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	aggregator := NewAggregator(metricsFactory, Options{MaxSeries: 2, Dimensions: []string{"region"}})
+
+	for i := 0; i < 4; i++ {
+		aggregator.ProcessSpan(testSpan("frontend", fmt.Sprintf("op-%d", i), time.Millisecond))
+	}
+
+	series, err := aggregator.GetSpanMetrics(context.Background(), Query{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, series, 3)
+	assert.Equal(t, "op-0", series[0].OperationName)
+	assert.Equal(t, "op-1", series[1].OperationName)
+	assert.Equal(t, OtherOperations, series[2].OperationName)
+	assert.Equal(t, map[string]string{"region": OtherValue}, series[2].Dimensions)
+	assert.EqualValues(t, 2, series[2].Calls)
+}
+
+func TestAggregatorMaxDimensionValues(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	aggregator := NewAggregator(metricsFactory, Options{Dimensions: []string{"user"}, MaxDimensionValues: 2})
+
+	for i := 0; i < 4; i++ {
+		aggregator.ProcessSpan(testSpan("frontend", "GET /", time.Millisecond, model.String("user", fmt.Sprintf("user-%d", i))))
+	}
+
+	series, err := aggregator.GetSpanMetrics(context.Background(), Query{ServiceName: "frontend"})
+	require.NoError(t, err)
+	users := make(map[string]uint64)
+	for _, s := range series {
+		users[s.Dimensions["user"]] += s.Calls
+	}
+	assert.Equal(t, map[string]uint64{"user-0": 1, "user-1": 1, OtherValue: 2}, users)
+}
+
+func TestAggregatorEvictsStaleSeries(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(6000, 0)}
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	aggregator := newAggregator(metricsFactory, Options{MaxOperations: 1}, clock.Now)
+
+	aggregator.ProcessSpan(testSpan("frontend", "op-0", time.Millisecond))
+	aggregator.ProcessSpan(testSpan("frontend", "op-1", time.Millisecond))
+	assert.Len(t, aggregator.series, 2)
+
+	// the series without spans during the window are evicted, freeing their operations
+	clock.now = clock.now.Add(Window)
+	aggregator.ProcessSpan(testSpan("frontend", "op-2", time.Millisecond))
+	assert.Len(t, aggregator.series, 1)
+	series, err := aggregator.GetSpanMetrics(context.Background(), Query{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, "op-2", series[0].OperationName)
+}
+
+func TestAggregatorDimensions(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	aggregator := NewAggregator(metricsFactory, Options{Dimensions: []string{"http.method", "region", "missing"}})
+
+	aggregator.ProcessSpan(testSpan("frontend", "request", time.Millisecond, model.String("http.method", "GET")))
+	aggregator.ProcessSpan(testSpan("frontend", "request", time.Millisecond, model.String("http.method", "POST")))
+	aggregator.ProcessSpan(testSpan("frontend", "request", time.Millisecond, model.String("http.method", "POST")))
+
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "calls",
+		Tags: map[string]string{
+			"service":     "frontend",
+			"operation":   "request",
+			"span_kind":   "",
+			"http_method": "POST",
+			"region":      "eu",
+			"missing":     "",
+		},
+		Value: 2,
+	})
+
+	series, err := aggregator.GetSpanMetrics(context.Background(), Query{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, series, 2)
+	assert.Equal(t, map[string]string{"http.method": "GET", "region": "eu", "missing": ""}, series[0].Dimensions)
+	assert.EqualValues(t, 1, series[0].Calls)
+	assert.Equal(t, map[string]string{"http.method": "POST", "region": "eu", "missing": ""}, series[1].Dimensions)
+	assert.EqualValues(t, 2, series[1].Calls)
+}
+
+func TestEffectiveLookback(t *testing.T) {
+	assert.Equal(t, Window, EffectiveLookback(0))
+	assert.Equal(t, Window, EffectiveLookback(time.Hour))
+	assert.Equal(t, 2*time.Minute, EffectiveLookback(90*time.Second))
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"context"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/multierror"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+// FromDomain converts the series to their gRPC representation.
+func FromDomain(series []Series) []api_v2.SpanMetrics {
+	result := make([]api_v2.SpanMetrics, len(series))
+	for i, s := range series {
+		result[i] = api_v2.SpanMetrics{
+			ServiceName:    s.ServiceName,
+			OperationName:  s.OperationName,
+			SpanKind:       s.SpanKind,
+			Dimensions:     s.Dimensions,
+			Calls:          s.Calls,
+			Errors:         s.Errors,
+			LatencyBuckets: s.LatencyBuckets,
+			LatencyCounts:  s.LatencyCounts,
+		}
+	}
+	return result
+}
+
+// ToDomain converts the gRPC representation of series.
+func ToDomain(metrics []api_v2.SpanMetrics) []Series {
+	result := make([]Series, len(metrics))
+	for i, m := range metrics {
+		result[i] = Series{
+			ServiceName:    m.ServiceName,
+			OperationName:  m.OperationName,
+			SpanKind:       m.SpanKind,
+			Dimensions:     m.Dimensions,
+			Calls:          m.Calls,
+			Errors:         m.Errors,
+			LatencyBuckets: m.LatencyBuckets,
+			LatencyCounts:  m.LatencyCounts,
+		}
+	}
+	return result
+}
+
+// GRPCHandler implements the gRPC SpanMetricsService of a collector.
+type GRPCHandler struct {
+	reader Reader
+}
+
+// NewGRPCHandler creates a GRPCHandler returning the series of the reader.
+func NewGRPCHandler(reader Reader) *GRPCHandler {
+	return &GRPCHandler{reader: reader}
+}
+
+// GetSpanMetrics implements gRPC SpanMetricsService.
+func (h *GRPCHandler) GetSpanMetrics(ctx context.Context, r *api_v2.GetSpanMetricsRequest) (*api_v2.GetSpanMetricsResponse, error) {
+	series, err := h.reader.GetSpanMetrics(ctx, Query{ServiceName: r.ServiceName, EndTime: r.EndTime, Lookback: r.Lookback})
+	if err != nil {
+		return nil, err
+	}
+	return &api_v2.GetSpanMetricsResponse{Metrics: FromDomain(series)}, nil
+}
+
+// RemoteReader is a Reader that adds up the series of the SpanMetricsService of collectors,
+// each of them seeing a share of the spans. The collectors that fail are logged and left out of the sums,
+// which fail only if all of them fail.
+type RemoteReader struct {
+	hostPorts []string
+	clients   map[string]api_v2.SpanMetricsServiceClient
+	logger    *zap.Logger
+}
+
+// NewRemoteReader creates a RemoteReader of the clients by host:port.
+func NewRemoteReader(clients map[string]api_v2.SpanMetricsServiceClient, logger *zap.Logger) *RemoteReader {
+	hostPorts := make([]string, 0, len(clients))
+	for hostPort := range clients {
+		hostPorts = append(hostPorts, hostPort)
+	}
+	sort.Strings(hostPorts)
+	return &RemoteReader{hostPorts: hostPorts, clients: clients, logger: logger}
+}
+
+// GetSpanMetrics implements Reader.
+func (rr *RemoteReader) GetSpanMetrics(ctx context.Context, query Query) ([]Series, error) {
+	sources := make([][]Series, 0, len(rr.clients))
+	var errs []error
+	for _, hostPort := range rr.hostPorts {
+		resp, err := rr.clients[hostPort].GetSpanMetrics(ctx, &api_v2.GetSpanMetricsRequest{
+			ServiceName: query.ServiceName,
+			EndTime:     query.EndTime,
+			Lookback:    query.Lookback,
+		})
+		if err != nil {
+			rr.logger.Warn("Leaving the collector out of the span metrics", zap.String("host-port", hostPort), zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+		sources = append(sources, ToDomain(resp.Metrics))
+	}
+	if len(errs) > 0 && len(errs) == len(rr.hostPorts) {
+		return nil, multierror.Wrap(errs)
+	}
+	return Merge(sources...), nil
+}
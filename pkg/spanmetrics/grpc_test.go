@@ -0,0 +1,160 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+type fakeReader struct {
+	series []Series
+	err    error
+	query  *Query
+}
+
+func (r fakeReader) GetSpanMetrics(ctx context.Context, query Query) ([]Series, error) {
+	if r.query != nil {
+		*r.query = query
+	}
+	return r.series, r.err
+}
+
+func startGRPCServer(t *testing.T, reader Reader) (*grpc.Server, *grpc.ClientConn) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	api_v2.RegisterSpanMetricsServiceServer(server, NewGRPCHandler(reader))
+	go server.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	return server, conn
+}
+
+func TestRemoteReader(t *testing.T) {
+	buckets := []time.Duration{time.Millisecond, time.Second}
+	var query Query
+	server1, conn1 := startGRPCServer(t, fakeReader{query: &query, series: []Series{
+		{
+			ServiceName:    "frontend",
+			OperationName:  "GET /",
+			SpanKind:       "server",
+			Dimensions:     map[string]string{"region": "eu"},
+			Calls:          3,
+			Errors:         1,
+			LatencyBuckets: buckets,
+			LatencyCounts:  []uint64{1, 1, 1},
+		},
+	}})
+	defer server1.Stop()
+	defer conn1.Close()
+	server2, conn2 := startGRPCServer(t, fakeReader{series: []Series{
+		{
+			ServiceName:    "frontend",
+			OperationName:  "GET /",
+			SpanKind:       "server",
+			Dimensions:     map[string]string{"region": "eu"},
+			Calls:          2,
+			LatencyBuckets: buckets,
+			LatencyCounts:  []uint64{2, 0, 0},
+		},
+		{
+			ServiceName:    "frontend",
+			OperationName:  "GET /",
+			SpanKind:       "server",
+			Dimensions:     map[string]string{"region": "us"},
+			Calls:          1,
+			LatencyBuckets: buckets,
+			LatencyCounts:  []uint64{1, 0, 0},
+		},
+	}})
+	defer server2.Stop()
+	defer conn2.Close()
+
+	reader := NewRemoteReader(map[string]api_v2.SpanMetricsServiceClient{
+		"collector-1": api_v2.NewSpanMetricsServiceClient(conn1),
+		"collector-2": api_v2.NewSpanMetricsServiceClient(conn2),
+	}, zap.NewNop())
+	series, err := reader.GetSpanMetrics(context.Background(), Query{ServiceName: "frontend", Lookback: 5 * time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, Query{ServiceName: "frontend", Lookback: 5 * time.Minute}, query)
+	assert.Equal(t, []Series{
+		{
+			ServiceName:    "frontend",
+			OperationName:  "GET /",
+			SpanKind:       "server",
+			Dimensions:     map[string]string{"region": "eu"},
+			Calls:          5,
+			Errors:         1,
+			LatencyBuckets: buckets,
+			LatencyCounts:  []uint64{3, 1, 1},
+		},
+		{
+			ServiceName:    "frontend",
+			OperationName:  "GET /",
+			SpanKind:       "server",
+			Dimensions:     map[string]string{"region": "us"},
+			Calls:          1,
+			LatencyBuckets: buckets,
+			LatencyCounts:  []uint64{1, 0, 0},
+		},
+	}, series)
+}
+
+func TestRemoteReaderError(t *testing.T) {
+	server, conn := startGRPCServer(t, fakeReader{err: errors.New("span metrics error")})
+	defer server.Stop()
+	defer conn.Close()
+
+	reader := NewRemoteReader(map[string]api_v2.SpanMetricsServiceClient{
+		"collector": api_v2.NewSpanMetricsServiceClient(conn),
+	}, zap.NewNop())
+	_, err := reader.GetSpanMetrics(context.Background(), Query{ServiceName: "frontend"})
+	assert.Contains(t, err.Error(), "span metrics error")
+}
+
+func TestRemoteReaderSkipsFailedCollector(t *testing.T) {
+	series := []Series{{ServiceName: "frontend", OperationName: "GET /", Calls: 1}}
+	server1, conn1 := startGRPCServer(t, fakeReader{series: series})
+	defer server1.Stop()
+	defer conn1.Close()
+	server2, conn2 := startGRPCServer(t, fakeReader{err: errors.New("span metrics error")})
+	defer server2.Stop()
+	defer conn2.Close()
+
+	core, logs := observer.New(zap.WarnLevel)
+	reader := NewRemoteReader(map[string]api_v2.SpanMetricsServiceClient{
+		"collector-1": api_v2.NewSpanMetricsServiceClient(conn1),
+		"collector-2": api_v2.NewSpanMetricsServiceClient(conn2),
+	}, zap.New(core))
+	found, err := reader.GetSpanMetrics(context.Background(), Query{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, uint64(1), found[0].Calls)
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "collector-2", logs.All()[0].ContextMap()["host-port"])
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Query selects the aggregates of a service over the lookback, at most the Window, until the end time.
+type Query struct {
+	ServiceName string
+	// EndTime is the end of the lookback, now if zero. The aggregates older than the Window are not available.
+	EndTime  time.Time
+	Lookback time.Duration
+}
+
+// Series is the aggregate of the spans of a service, operation, span kind and dimension values.
+type Series struct {
+	ServiceName   string
+	OperationName string
+	SpanKind      string
+	Dimensions    map[string]string
+	Calls         uint64
+	Errors        uint64
+	// LatencyBuckets are the upper bounds of the latency histogram buckets.
+	LatencyBuckets []time.Duration
+	// LatencyCounts are the number of spans per latency bucket, followed by those above the last bucket.
+	LatencyCounts []uint64
+}
+
+// Reader returns the aggregates of a service.
+type Reader interface {
+	// GetSpanMetrics returns the aggregates of the service over the lookback, sorted by operation and span kind.
+	GetSpanMetrics(ctx context.Context, query Query) ([]Series, error)
+}
+
+// Percentile returns the upper bound of the latency bucket of the nearest-rank percentile p, between 0 and 100.
+// The latencies above the last bucket are reported as its upper bound.
+func (s *Series) Percentile(p int) time.Duration {
+	if s.Calls == 0 || len(s.LatencyBuckets) == 0 {
+		return 0
+	}
+	rank := (uint64(p)*s.Calls + 99) / 100
+	if rank == 0 {
+		rank = 1
+	}
+	var seen uint64
+	for i, n := range s.LatencyCounts {
+		seen += n
+		if seen >= rank && i < len(s.LatencyBuckets) {
+			return s.LatencyBuckets[i]
+		}
+	}
+	return s.LatencyBuckets[len(s.LatencyBuckets)-1]
+}
+
+// Sort sorts the series by operation, span kind and dimension values.
+func Sort(series []Series) {
+	sort.Slice(series, func(i, j int) bool {
+		a, b := series[i], series[j]
+		if a.OperationName != b.OperationName {
+			return a.OperationName < b.OperationName
+		}
+		if a.SpanKind != b.SpanKind {
+			return a.SpanKind < b.SpanKind
+		}
+		return dimensionsKey(a.Dimensions) < dimensionsKey(b.Dimensions)
+	})
+}
+
+// Merge adds up the series of several sources, e.g. collectors, that have the same operation, span kind
+// and dimension values. The series must use the same latency buckets.
+func Merge(sources ...[]Series) []Series {
+	merged := make(map[string]*Series)
+	var keys []string
+	for _, source := range sources {
+		for _, s := range source {
+			key := s.OperationName + "\x00" + s.SpanKind + "\x00" + dimensionsKey(s.Dimensions)
+			m, ok := merged[key]
+			if !ok {
+				s := s
+				s.LatencyCounts = append([]uint64(nil), s.LatencyCounts...)
+				merged[key] = &s
+				keys = append(keys, key)
+				continue
+			}
+			m.Calls += s.Calls
+			m.Errors += s.Errors
+			for i := range m.LatencyCounts {
+				if i < len(s.LatencyCounts) {
+					m.LatencyCounts[i] += s.LatencyCounts[i]
+				}
+			}
+		}
+	}
+	result := make([]Series, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *merged[key])
+	}
+	Sort(result)
+	return result
+}
+
+func dimensionsKey(dimensions map[string]string) string {
+	keys := make([]string, 0, len(dimensions))
+	for k := range dimensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var key string
+	for _, k := range keys {
+		key += k + "=" + dimensions[k] + "\x00"
+	}
+	return key
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	s := Series{
+		Calls:          100,
+		LatencyBuckets: []time.Duration{time.Millisecond, 10 * time.Millisecond},
+		LatencyCounts:  []uint64{50, 45, 5},
+	}
+	assert.Equal(t, time.Millisecond, s.Percentile(0))
+	assert.Equal(t, time.Millisecond, s.Percentile(50))
+	assert.Equal(t, 10*time.Millisecond, s.Percentile(95))
+	assert.Equal(t, 10*time.Millisecond, s.Percentile(99))
+	assert.Equal(t, time.Duration(0), (&Series{}).Percentile(50))
+}
+
+func TestMerge(t *testing.T) {
+	buckets := []time.Duration{time.Millisecond}
+	merged := Merge(
+		[]Series{
+			{OperationName: "b", Calls: 2, Errors: 1, LatencyBuckets: buckets, LatencyCounts: []uint64{1, 1}},
+			{OperationName: "a", SpanKind: "server", Calls: 1, LatencyBuckets: buckets, LatencyCounts: []uint64{1, 0}},
+		},
+		[]Series{
+			{OperationName: "b", Calls: 3, Errors: 2, LatencyBuckets: buckets, LatencyCounts: []uint64{0, 3}},
+			{OperationName: "b", Dimensions: map[string]string{"k": "v"}, Calls: 1, LatencyBuckets: buckets, LatencyCounts: []uint64{1, 0}},
+		},
+	)
+	assert.Equal(t, []Series{
+		{OperationName: "a", SpanKind: "server", Calls: 1, LatencyBuckets: buckets, LatencyCounts: []uint64{1, 0}},
+		{OperationName: "b", Calls: 5, Errors: 3, LatencyBuckets: buckets, LatencyCounts: []uint64{1, 4}},
+		{OperationName: "b", Dimensions: map[string]string{"k": "v"}, Calls: 1, LatencyBuckets: buckets, LatencyCounts: []uint64{1, 0}},
+	}, merged)
+}
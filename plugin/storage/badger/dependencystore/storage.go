@@ -15,47 +15,196 @@
 package dependencystore
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"time"
 
+	"github.com/dgraph-io/badger"
+
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
-// DependencyStore handles all queries and insertions to Cassandra dependencies
+const (
+	// dependencyKeyPrefix is the first byte of the keys of the written dependency links,
+	// outside of the key range of the span store, whose keys have the first bit set
+	dependencyKeyPrefix byte = 0x10
+	// sizeOfDependencyKey is the prefix followed by the timestamp in nanoseconds
+	sizeOfDependencyKey = 1 + 8
+	// tracesPageSize is the number of traces read at a time when deriving the detailed links from all the traces
+	tracesPageSize = 1000
+)
+
+// DependencyStore handles all queries and insertions to Badger dependencies
 type DependencyStore struct {
+	store  *badger.DB
 	reader spanstore.Reader
+	ttl    time.Duration
 }
 
-// NewDependencyStore returns a DependencyStore
-func NewDependencyStore(store spanstore.Reader) *DependencyStore {
+// writtenDependencies are the dependency links written for a timestamp, which cover the time range
+// from the timestamp to their writing.
+type writtenDependencies struct {
+	WrittenAt time.Time              `json:"writtenAt"`
+	Links     []model.DependencyLink `json:"links"`
+}
+
+// NewDependencyStore returns a DependencyStore. Dependency links written to it expire after the ttl.
+func NewDependencyStore(db *badger.DB, reader spanstore.Reader, ttl time.Duration) *DependencyStore {
 	return &DependencyStore{
-		reader: store,
+		store:  db,
+		reader: reader,
+		ttl:    ttl,
 	}
 }
 
-// GetDependencies returns all interservice dependencies, implements DependencyReader
+// WriteDependencies implements dependencystore.Writer#WriteDependencies.
+func (s *DependencyStore) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
+	value, err := json.Marshal(writtenDependencies{WrittenAt: time.Now(), Links: dependencies})
+	if err != nil {
+		return err
+	}
+	entry := &badger.Entry{
+		Key:       dependencyKey(ts),
+		Value:     value,
+		ExpiresAt: uint64(time.Now().Add(s.ttl).Unix()),
+	}
+	return s.store.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(entry)
+	})
+}
+
+func dependencyKey(ts time.Time) []byte {
+	key := make([]byte, sizeOfDependencyKey)
+	key[0] = dependencyKeyPrefix
+	binary.BigEndian.PutUint64(key[1:], uint64(ts.UnixNano()))
+	return key
+}
+
+// GetDependencies returns all interservice dependencies, implements DependencyReader.
+// The dependency links written to the store with a timestamp in the time range are summed up, and
+// the links of the parts of the time range that they do not cover are derived from the traces.
 func (s *DependencyStore) GetDependencies(endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
 	deps := map[string]*model.DependencyLink{}
+	startTs := endTs.Add(-1 * lookback)
 
-	params := &spanstore.TraceQueryParameters{
-		StartTimeMin: endTs.Add(-1 * lookback),
-		StartTimeMax: endTs,
+	covered, err := s.readWrittenDependencies(deps, startTs, endTs)
+	if err != nil {
+		return nil, err
+	}
+
+	// We need to do a full table scan - if this becomes a bottleneck, the collector can aggregate
+	// the dependencies and write them to this store, see readWrittenDependencies
+	for _, uncovered := range dependencystore.UncoveredRanges(startTs, endTs, covered) {
+		err := s.forEachTrace(uncovered.Start, uncovered.End, func(trace *model.Trace) {
+			processTrace(deps, trace)
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// We need to do a full table scan - if this becomes a bottleneck, we can write an index that describes
-	// dependencyKeyPrefix + timestamp + parent + child key and do a key-only seek (which is fast - but requires additional writes)
+	return depMapToSlice(deps), nil
+}
 
-	// GetDependencies is not shipped with a context like the SpanReader / SpanWriter
-	traces, err := s.reader.FindTraces(context.Background(), params)
+// GetDetailedDependencies implements dependencystore.DetailedReader. The detailed links are
+// always derived from all the traces in the time range, since the written links are not detailed.
+func (s *DependencyStore) GetDetailedDependencies(endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	links := dependencystore.NewDetailedLinks()
+	err := s.forEachTrace(endTs.Add(-1*lookback), endTs, links.AddTrace)
 	if err != nil {
 		return nil, err
 	}
-	for _, tr := range traces {
-		processTrace(deps, tr)
+	return links.Links(), nil
+}
+
+// forEachTrace calls fn with each trace in the time range, reading them a page at a time.
+func (s *DependencyStore) forEachTrace(startTs, endTs time.Time, fn func(*model.Trace)) error {
+	query := &spanstore.TraceQueryParameters{
+		StartTimeMin: startTs,
+		StartTimeMax: endTs,
+		NumTraces:    tracesPageSize,
+	}
+	var pageToken *spanstore.PageToken
+	seen := make(map[model.TraceID]struct{})
+	for {
+		traces, err := s.reader.FindTraces(context.Background(), query)
+		if err != nil {
+			return err
+		}
+		for _, trace := range traces {
+			if len(trace.Spans) == 0 {
+				continue
+			}
+			// a trace with spans on both sides of the page token is found again
+			traceID := trace.Spans[0].TraceID
+			if _, ok := seen[traceID]; !ok {
+				seen[traceID] = struct{}{}
+				fn(trace)
+			}
+		}
+		if len(traces) < tracesPageSize {
+			return nil
+		}
+		scanQuery := pageToken.BoundQuery(query)
+		spanstore.SortTracesForPaging(scanQuery, traces)
+		next := spanstore.NewPageToken(scanQuery, traces[len(traces)-1])
+		if !pageToken.Admits(next.StartTime, next.TraceID) {
+			return nil
+		}
+		pageToken = &next
+		query.PageToken = next.String()
 	}
+}
+
+// readWrittenDependencies adds up the written dependency links between startTs and endTs,
+// and returns the time ranges that they cover.
+func (s *DependencyStore) readWrittenDependencies(deps map[string]*model.DependencyLink, startTs, endTs time.Time) ([]dependencystore.TimeRange, error) {
+	var covered []dependencystore.TimeRange
+	err := s.store.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
 
-	return depMapToSlice(deps), err
+		prefix := []byte{dependencyKeyPrefix}
+		endKey := dependencyKey(endTs)
+		for it.Seek(dependencyKey(startTs)); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			if bytes.Compare(item.Key(), endKey) > 0 {
+				break
+			}
+			value, err := item.Value()
+			if err != nil {
+				return err
+			}
+			var written writtenDependencies
+			if err := json.Unmarshal(value, &written); err != nil {
+				return err
+			}
+			ts := time.Unix(0, int64(binary.BigEndian.Uint64(item.Key()[1:])))
+			covered = append(covered, dependencystore.TimeRange{Start: ts, End: written.WrittenAt})
+			for _, link := range written.Links {
+				addLink(deps, link.Parent, link.Child, link.CallCount)
+			}
+		}
+		return nil
+	})
+	return covered, err
+}
+
+func addLink(deps map[string]*model.DependencyLink, parent, child string, callCount uint64) {
+	depKey := parent + "&&&" + child
+	if dep, ok := deps[depKey]; ok {
+		dep.CallCount += callCount
+		return
+	}
+	deps[depKey] = &model.DependencyLink{
+		Parent:    parent,
+		Child:     child,
+		CallCount: callCount,
+	}
 }
 
 // depMapToSlice modifies the spans to DependencyLink in the same way as the memory storage plugin
@@ -75,16 +224,7 @@ func processTrace(deps map[string]*model.DependencyLink, trace *model.Trace) {
 			if parentSpan.Process.ServiceName == s.Process.ServiceName {
 				continue
 			}
-			depKey := parentSpan.Process.ServiceName + "&&&" + s.Process.ServiceName
-			if _, ok := deps[depKey]; !ok {
-				deps[depKey] = &model.DependencyLink{
-					Parent:    parentSpan.Process.ServiceName,
-					Child:     s.Process.ServiceName,
-					CallCount: 1,
-				}
-			} else {
-				deps[depKey].CallCount++
-			}
+			addLink(deps, parentSpan.Process.ServiceName, s.Process.ServiceName, 1)
 		}
 	}
 }
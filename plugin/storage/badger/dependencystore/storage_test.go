@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
@@ -101,3 +102,159 @@ func TestDependencyReader(t *testing.T) {
 		assert.Equal(t, uint64(traces), links[0].CallCount) // Each trace calls the same services
 	})
 }
+
+func TestDetailedDependencyReader(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, dr dependencystore.Reader) {
+		tid := time.Now()
+		detailed, ok := dr.(dependencystore.DetailedReader)
+		require.True(t, ok)
+
+		for i := 0; i < 10; i++ {
+			traceID := model.TraceID{Low: uint64(i), High: 1}
+			spans := []model.Span{
+				{
+					TraceID:       traceID,
+					SpanID:        model.SpanID(1),
+					OperationName: "send",
+					Tags:          []model.KeyValue{model.String("span.kind", "producer")},
+					Process:       &model.Process{ServiceName: "producer"},
+					StartTime:     tid,
+					Duration:      time.Millisecond,
+				},
+				{
+					TraceID:       traceID,
+					SpanID:        model.SpanID(2),
+					OperationName: "receive",
+					References:    []model.SpanRef{model.NewFollowsFromRef(traceID, model.SpanID(1))},
+					Process:       &model.Process{ServiceName: "consumer"},
+					StartTime:     tid,
+					Duration:      time.Duration(i+1) * time.Millisecond,
+				},
+			}
+			if i == 0 {
+				spans[1].Tags = []model.KeyValue{model.Bool("error", true)}
+			}
+			for j := range spans {
+				require.NoError(t, sw.WriteSpan(&spans[j]))
+			}
+		}
+
+		links, err := detailed.GetDetailedDependencies(time.Now(), time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []model.DependencyLink{{
+			Parent:          "producer",
+			Child:           "consumer",
+			CallCount:       10,
+			ParentOperation: "send",
+			ChildOperation:  "receive",
+			Kind:            model.MessagingDependency,
+			ErrorCount:      1,
+			LatencyP50:      5 * time.Millisecond,
+			LatencyP95:      10 * time.Millisecond,
+			LatencyP99:      10 * time.Millisecond,
+		}}, links)
+	})
+}
+
+func TestDetailedDependencyReaderReadsAllTraces(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, dr dependencystore.Reader) {
+		tid := time.Now().Add(-time.Minute)
+		// more traces than read at a time, with spans on both sides of the page boundaries
+		traces := 2500
+		for i := 0; i < traces; i++ {
+			traceID := model.TraceID{Low: uint64(i), High: 1}
+			parent := model.Span{
+				TraceID:       traceID,
+				SpanID:        model.SpanID(1),
+				OperationName: "GET",
+				Process:       &model.Process{ServiceName: "frontend"},
+				StartTime:     tid.Add(time.Duration(i) * time.Millisecond),
+			}
+			child := model.Span{
+				TraceID:       traceID,
+				SpanID:        model.SpanID(2),
+				OperationName: "SELECT",
+				References:    []model.SpanRef{model.NewChildOfRef(traceID, model.SpanID(1))},
+				Process:       &model.Process{ServiceName: "db"},
+				StartTime:     tid.Add(time.Duration(traces-i) * time.Millisecond),
+			}
+			require.NoError(t, sw.WriteSpan(&parent))
+			require.NoError(t, sw.WriteSpan(&child))
+		}
+
+		links, err := dr.(dependencystore.DetailedReader).GetDetailedDependencies(time.Now(), time.Hour)
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		assert.Equal(t, uint64(traces), links[0].CallCount)
+	})
+}
+
+func TestDependencyWriter(t *testing.T) {
+	f := badger.NewFactory()
+	opts := badger.NewOptions("badger")
+	v, command := config.Viperize(opts.AddFlags)
+	command.ParseFlags([]string{
+		"--badger.ephemeral=true",
+		"--badger.consistency=false",
+	})
+	f.InitFromViper(v)
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	defer f.Close()
+
+	sw, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	dw, err := f.CreateDependencyWriter()
+	require.NoError(t, err)
+	dr, err := f.CreateDependencyReader()
+	require.NoError(t, err)
+
+	ts := time.Now()
+	// the span would be a dependency derived from the traces
+	err = sw.WriteSpan(&model.Span{
+		TraceID:    model.NewTraceID(1, 1),
+		SpanID:     model.SpanID(2),
+		References: []model.SpanRef{model.NewChildOfRef(model.NewTraceID(1, 1), model.SpanID(1))},
+		Process:    &model.Process{ServiceName: "service-b"},
+		StartTime:  ts,
+	})
+	require.NoError(t, err)
+	require.NoError(t, dw.WriteDependencies(ts.Add(-time.Minute), []model.DependencyLink{
+		{Parent: "frontend", Child: "backend", CallCount: 2},
+		{Parent: "backend", Child: "db", CallCount: 1},
+	}))
+	require.NoError(t, dw.WriteDependencies(ts, []model.DependencyLink{
+		{Parent: "frontend", Child: "backend", CallCount: 3},
+	}))
+	require.NoError(t, dw.WriteDependencies(ts.Add(-2*time.Hour), []model.DependencyLink{
+		{Parent: "frontend", Child: "db", CallCount: 1},
+	}))
+
+	links, err := dr.GetDependencies(ts, time.Hour)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []model.DependencyLink{
+		{Parent: "frontend", Child: "backend", CallCount: 5},
+		{Parent: "backend", Child: "db", CallCount: 1},
+	}, links)
+
+	// the time range before the written dependencies is derived from the traces
+	traceID := model.NewTraceID(1, 2)
+	for _, span := range []*model.Span{
+		{TraceID: traceID, SpanID: model.SpanID(1), Process: &model.Process{ServiceName: "service-a"}, StartTime: ts.Add(-30 * time.Minute)},
+		{
+			TraceID:    traceID,
+			SpanID:     model.SpanID(2),
+			References: []model.SpanRef{model.NewChildOfRef(traceID, model.SpanID(1))},
+			Process:    &model.Process{ServiceName: "service-b"},
+			StartTime:  ts.Add(-30 * time.Minute),
+		},
+	} {
+		require.NoError(t, sw.WriteSpan(span))
+	}
+	links, err = dr.GetDependencies(ts, time.Hour)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []model.DependencyLink{
+		{Parent: "service-a", Child: "service-b", CallCount: 1},
+		{Parent: "frontend", Child: "backend", CallCount: 5},
+		{Parent: "backend", Child: "db", CallCount: 1},
+	}, links)
+}
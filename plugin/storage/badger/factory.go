@@ -28,6 +28,7 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/catalog"
 	depStore "github.com/jaegertracing/jaeger/plugin/storage/badger/dependencystore"
 	badgerStore "github.com/jaegertracing/jaeger/plugin/storage/badger/spanstore"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
@@ -46,6 +47,7 @@ type Factory struct {
 	Options *Options
 	store   *badger.DB
 	cache   *badgerStore.CacheStore
+	catalog *catalog.Tracker
 	logger  *zap.Logger
 
 	tmpDir          string
@@ -123,6 +125,7 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	f.store = store
 
 	f.cache = badgerStore.NewCacheStore(f.store, f.Options.primary.SpanStoreTTL, true)
+	f.catalog = catalog.NewTracker()
 
 	f.metrics.ValueLogSpaceAvailable = metricsFactory.Gauge(metrics.Options{Name: valueLogSpaceAvailableName})
 	f.metrics.KeyLogSpaceAvailable = metricsFactory.Gauge(metrics.Options{Name: keyLogSpaceAvailableName})
@@ -153,13 +156,24 @@ func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	return badgerStore.NewSpanWriter(f.store, f.cache, f.Options.primary.SpanStoreTTL, f), nil
+	return badgerStore.NewSpanWriter(f.store, f.cache, f.catalog, f.Options.primary.SpanStoreTTL, f), nil
 }
 
 // CreateDependencyReader implements storage.Factory
 func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	sr, _ := f.CreateSpanReader() // err is always nil
-	return depStore.NewDependencyStore(sr), nil
+	return depStore.NewDependencyStore(f.store, sr, f.Options.primary.SpanStoreTTL), nil
+}
+
+// CreateDependencyWriter implements storage.DependencyWriterFactory
+func (f *Factory) CreateDependencyWriter() (dependencystore.Writer, error) {
+	sr, _ := f.CreateSpanReader() // err is always nil
+	return depStore.NewDependencyStore(f.store, sr, f.Options.primary.SpanStoreTTL), nil
+}
+
+// CreateCatalogReader implements storage.CatalogFactory
+func (f *Factory) CreateCatalogReader() (spanstore.CatalogReader, error) {
+	return f.catalog, nil
 }
 
 // Close Implements io.Closer and closes the underlying storage
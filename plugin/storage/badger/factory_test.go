@@ -15,6 +15,7 @@
 package badger
 
 import (
+	"context"
 	"expvar"
 	"fmt"
 	"io"
@@ -27,6 +28,7 @@ import (
 	"github.com/uber/jaeger-lib/metrics/metricstest"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
 )
 
@@ -77,6 +79,32 @@ func TestForCodecov(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestCatalog(t *testing.T) {
+	f := NewFactory()
+	v, _ := config.Viperize(f.AddFlags)
+	f.InitFromViper(v)
+	assert.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	defer f.Close()
+
+	sw, err := f.CreateSpanWriter()
+	assert.NoError(t, err)
+	assert.NoError(t, sw.WriteSpan(&model.Span{
+		TraceID:       model.NewTraceID(0, 1),
+		SpanID:        model.NewSpanID(1),
+		OperationName: "op",
+		Process:       model.NewProcess("svc", nil),
+		StartTime:     time.Now(),
+	}))
+
+	cr, err := f.CreateCatalogReader()
+	assert.NoError(t, err)
+	services, err := cr.GetServiceCatalog(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, services, 1)
+	assert.Equal(t, "svc", services[0].ServiceName)
+	assert.Equal(t, 1, services[0].OperationCount)
+}
+
 func TestMaintenanceRun(t *testing.T) {
 	// For Codecov - this does not test anything
 	f := NewFactory()
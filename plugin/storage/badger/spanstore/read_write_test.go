@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
@@ -93,6 +94,44 @@ func TestWriteReadBack(t *testing.T) {
 	})
 }
 
+func TestStreamTrace(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		traceID := model.NewTraceID(1, 1)
+		for i := 0; i < 250; i++ {
+			require.NoError(t, sw.WriteSpan(&model.Span{
+				TraceID:       traceID,
+				SpanID:        model.NewSpanID(uint64(i + 1)),
+				OperationName: "operation",
+				Process:       model.NewProcess("service", nil),
+				StartTime:     time.Now(),
+			}))
+		}
+		streamer := sr.(spanstore.TraceStreamer)
+
+		var parts []int
+		err := streamer.StreamTrace(context.Background(), traceID, func(part *model.Trace) bool {
+			parts = append(parts, len(part.Spans))
+			return true
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{100, 100, 50}, parts)
+
+		parts = nil
+		err = streamer.StreamTrace(context.Background(), traceID, func(part *model.Trace) bool {
+			parts = append(parts, len(part.Spans))
+			return false
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{100}, parts)
+
+		err = streamer.StreamTrace(context.Background(), model.NewTraceID(2, 2), func(part *model.Trace) bool {
+			t.Fatal("no part expected")
+			return false
+		})
+		assert.Equal(t, spanstore.ErrTraceNotFound, err)
+	})
+}
+
 func TestValidation(t *testing.T) {
 	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
 		tid := time.Now()
@@ -311,6 +350,167 @@ func TestFindNothing(t *testing.T) {
 	})
 }
 
+func TestFindTracesPaging(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		tid := time.Now()
+		for i := 0; i < 5; i++ {
+			s := model.Span{
+				TraceID: model.TraceID{
+					Low:  uint64(i),
+					High: 1,
+				},
+				SpanID:        model.SpanID(1),
+				OperationName: "operation",
+				Process: &model.Process{
+					ServiceName: "service",
+				},
+				// two traces share each start time to exercise the trace ID tie-breaker
+				StartTime: tid.Add(time.Duration(i/2) * time.Millisecond),
+				Duration:  time.Millisecond,
+			}
+			require.NoError(t, sw.WriteSpan(&s))
+		}
+
+		params := &spanstore.TraceQueryParameters{
+			StartTimeMin: tid.Add(-time.Second),
+			StartTimeMax: tid.Add(time.Second),
+			ServiceName:  "service",
+			NumTraces:    2,
+		}
+		var gotIDs []uint64
+		for page := 0; page < 3; page++ {
+			trs, err := sr.FindTraces(context.Background(), params)
+			require.NoError(t, err)
+			for _, tr := range trs {
+				gotIDs = append(gotIDs, tr.Spans[0].TraceID.Low)
+			}
+			params.PageToken = spanstore.NextPageToken(params, trs)
+		}
+		assert.Empty(t, params.PageToken)
+		assert.Equal(t, []uint64{4, 3, 2, 1, 0}, gotIDs)
+
+		params.PageToken = "?"
+		_, err := sr.FindTraces(context.Background(), params)
+		assert.Equal(t, spanstore.ErrMalformedPageToken, err)
+	})
+}
+
+func TestFindTracesMultipleServices(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		tid := time.Now()
+		services := []string{"frontend", "backend", "db"}
+		operations := []string{"get", "put"}
+		for i := 0; i < 6; i++ {
+			s := model.Span{
+				TraceID: model.TraceID{
+					Low:  uint64(i),
+					High: 1,
+				},
+				SpanID:        model.SpanID(1),
+				OperationName: operations[i%2],
+				Process: &model.Process{
+					ServiceName: services[i%3],
+				},
+				Tags:      model.KeyValues{model.String("error", "true")},
+				StartTime: tid.Add(time.Duration(i) * time.Millisecond),
+				Duration:  time.Millisecond,
+			}
+			require.NoError(t, sw.WriteSpan(&s))
+		}
+
+		testCases := []struct {
+			params      *spanstore.TraceQueryParameters
+			expectedIDs []uint64
+		}{
+			{
+				params:      &spanstore.TraceQueryParameters{ServiceNames: []string{"frontend", "db"}},
+				expectedIDs: []uint64{5, 3, 2, 0},
+			},
+			{
+				params:      &spanstore.TraceQueryParameters{ServiceName: "frontend", ServiceNames: []string{"db"}, NumTraces: 3},
+				expectedIDs: []uint64{5, 3, 2},
+			},
+			{
+				params: &spanstore.TraceQueryParameters{
+					ServiceNames:   []string{"frontend", "backend"},
+					OperationNames: []string{"get"},
+					Tags:           map[string]string{"error": "true"},
+				},
+				expectedIDs: []uint64{4, 0},
+			},
+			{
+				params: &spanstore.TraceQueryParameters{
+					ServiceNames:   []string{"frontend", "backend", "db"},
+					OperationNames: []string{"get", "put"},
+					DurationMin:    time.Millisecond,
+					NumTraces:      4,
+				},
+				expectedIDs: []uint64{5, 4, 3, 2},
+			},
+		}
+		for _, testCase := range testCases {
+			params := testCase.params
+			params.StartTimeMin = tid.Add(-time.Second)
+			params.StartTimeMax = tid.Add(time.Second)
+			traceIDs, err := sr.FindTraceIDs(context.Background(), params)
+			require.NoError(t, err)
+			var gotIDs []uint64
+			for _, traceID := range traceIDs {
+				gotIDs = append(gotIDs, traceID.Low)
+			}
+			assert.Equal(t, testCase.expectedIDs, gotIDs)
+		}
+
+		_, err := sr.FindTraceIDs(context.Background(), &spanstore.TraceQueryParameters{
+			OperationNames: []string{"get"},
+			StartTimeMin:   tid.Add(-time.Second),
+			StartTimeMax:   tid.Add(time.Second),
+		})
+		assert.Equal(t, bss.ErrServiceNameNotSet, err)
+	})
+}
+
+func TestFindTracesWithoutServiceOrderedByLatestSpan(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		tid := time.Now()
+		for i, span := range []struct {
+			traceID uint64
+			offset  time.Duration
+		}{
+			// the first trace starts first but has the latest span
+			{traceID: 1, offset: 0},
+			{traceID: 1, offset: 3 * time.Millisecond},
+			{traceID: 2, offset: time.Millisecond},
+			{traceID: 3, offset: 2 * time.Millisecond},
+		} {
+			s := model.Span{
+				TraceID:       model.NewTraceID(0, span.traceID),
+				SpanID:        model.NewSpanID(uint64(i + 1)),
+				OperationName: "operation",
+				Process:       model.NewProcess("service", nil),
+				StartTime:     tid.Add(span.offset),
+				Duration:      time.Millisecond,
+			}
+			require.NoError(t, sw.WriteSpan(&s))
+		}
+
+		params := &spanstore.TraceQueryParameters{
+			StartTimeMin: tid.Add(-time.Second),
+			StartTimeMax: tid.Add(time.Second),
+			NumTraces:    2,
+		}
+		traceIDs, err := sr.FindTraceIDs(context.Background(), params)
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 3)}, traceIDs)
+
+		params.PageToken = spanstore.PageToken{StartTime: tid.Add(2 * time.Millisecond), TraceID: model.NewTraceID(0, 3)}.String()
+		traceIDs, err = sr.FindTraceIDs(context.Background(), params)
+		require.NoError(t, err)
+		// the first trace is found again by its span before the token
+		assert.Equal(t, []model.TraceID{model.NewTraceID(0, 2), model.NewTraceID(0, 1)}, traceIDs)
+	})
+}
+
 func TestWriteDuplicates(t *testing.T) {
 	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
 		tid := time.Now()
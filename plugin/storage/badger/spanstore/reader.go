@@ -60,6 +60,7 @@ const (
 	defaultNumTraces = 100
 	sizeOfTraceID    = 16
 	encodingTypeBits = 0x0F
+	streamBatchSize  = 100
 )
 
 // TraceReader reads traces from the local badger store
@@ -75,6 +76,9 @@ type executionPlan struct {
 
 	limit int
 
+	// pageToken is the continuation token of the previous page, if any
+	pageToken *spanstore.PageToken
+
 	// mergeOuter is the result of merge-join of inner and outer result sets
 	mergeOuter [][]byte
 
@@ -167,10 +171,51 @@ func (r *TraceReader) GetTrace(ctx context.Context, traceID model.TraceID) (*mod
 	return nil, nil
 }
 
-// scanTimeRange returns all the Traces found between startTs and endTs
+// StreamTrace implements spanstore.TraceStreamer, passing fn the spans of the trace by batches of streamBatchSize
+func (r *TraceReader) StreamTrace(ctx context.Context, traceID model.TraceID, fn func(part *model.Trace) bool) error {
+	prefix := createPrimaryKeySeekPrefix(traceID)
+	found := false
+	err := r.store.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		val := []byte{}
+		spans := make([]*model.Span, 0, streamBatchSize)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			val, err := item.ValueCopy(val)
+			if err != nil {
+				return err
+			}
+			sp, err := decodeValue(val, item.UserMeta()&encodingTypeBits)
+			if err != nil {
+				return err
+			}
+			found = true
+			spans = append(spans, sp)
+			if len(spans) == streamBatchSize {
+				if !fn(&model.Trace{Spans: spans}) {
+					return nil
+				}
+				spans = make([]*model.Span, 0, streamBatchSize)
+			}
+		}
+		if len(spans) > 0 {
+			fn(&model.Trace{Spans: spans})
+		}
+		return nil
+	})
+	if err == nil && !found {
+		return spanstore.ErrTraceNotFound
+	}
+	return err
+}
+
+// scanTimeRange returns the most recent Traces found between startTs and endTs, ordered by their latest span
+// in the time range, which is the sort key of spanstore.PageToken for queries without a service
 func (r *TraceReader) scanTimeRange(plan *executionPlan) ([]model.TraceID, error) {
 	// We need to do a full table scan
-	traceKeys := make([][]byte, 0)
+	entries := make([]indexEntry, 0)
 	err := r.store.View(func(txn *badger.Txn) error {
 		opts := badger.DefaultIteratorOptions
 		opts.PrefetchValues = false
@@ -178,50 +223,48 @@ func (r *TraceReader) scanTimeRange(plan *executionPlan) ([]model.TraceID, error
 		defer it.Close()
 
 		startIndex := []byte{spanKeyPrefix}
-		prevTraceID := []byte{}
 		for it.Seek(startIndex); it.ValidForPrefix(startIndex); it.Next() {
-			item := it.Item()
-
-			key := []byte{}
-			key = item.KeyCopy(key)
+			key := it.Item().Key()
 
 			timestamp := key[sizeOfTraceID+1 : sizeOfTraceID+1+8]
 			traceID := key[1 : sizeOfTraceID+1]
 
-			if bytes.Compare(timestamp, plan.startTimeMin) >= 0 && bytes.Compare(timestamp, plan.startTimeMax) <= 0 {
-				if !bytes.Equal(traceID, prevTraceID) {
-					if plan.hashOuter != nil {
-						trID := bytesToTraceID(traceID)
-						if _, exists := plan.hashOuter[trID]; exists {
-							traceKeys = append(traceKeys, key)
-						}
-					} else {
-						traceKeys = append(traceKeys, key)
-					}
-					prevTraceID = traceID
+			if bytes.Compare(timestamp, plan.startTimeMin) < 0 || bytes.Compare(timestamp, plan.startTimeMax) > 0 {
+				continue
+			}
+			if !plan.admits(timestamp, traceID) {
+				continue
+			}
+			trID := bytesToTraceID(traceID)
+			if plan.hashOuter != nil {
+				if _, exists := plan.hashOuter[trID]; !exists {
+					continue
 				}
 			}
+			// keys of the same trace are sorted by timestamp, so the last one is the latest
+			entry := indexEntry{traceID: trID, timestamp: binary.BigEndian.Uint64(timestamp)}
+			if n := len(entries); n > 0 && entries[n-1].traceID == trID {
+				entries[n-1] = entry
+			} else {
+				entries = append(entries, entry)
+			}
 		}
 
 		return nil
 	})
 
-	sort.Slice(traceKeys, func(k, h int) bool {
-		// This sorts by timestamp to descending order
-		return bytes.Compare(traceKeys[k][sizeOfTraceID+1:sizeOfTraceID+1+8], traceKeys[h][sizeOfTraceID+1:sizeOfTraceID+1+8]) > 0
-	})
-
-	sizeCount := len(traceKeys)
-	if plan.limit > 0 && plan.limit < sizeCount {
-		sizeCount = plan.limit
-	}
-	traceIDs := make([]model.TraceID, sizeCount)
+	return mergeIndexEntries([][]indexEntry{entries}, plan.limit), err
+}
 
-	for i := 0; i < sizeCount; i++ {
-		traceIDs[i] = bytesToTraceID(traceKeys[i][1 : sizeOfTraceID+1])
+// admits checks the index entry against the page token, which may share the timestamp with the entry
+func (plan *executionPlan) admits(timestamp []byte, traceID []byte) bool {
+	if plan.pageToken == nil {
+		return true
 	}
-
-	return traceIDs, err
+	return plan.pageToken.Admits(
+		model.EpochMicrosecondsAsTime(binary.BigEndian.Uint64(timestamp)),
+		bytesToTraceID(traceID),
+	)
 }
 
 func createPrimaryKeySeekPrefix(traceID model.TraceID) []byte {
@@ -281,8 +324,14 @@ func serviceQueries(query *spanstore.TraceQueryParameters, indexSeeks [][]byte)
 	return indexSeeks
 }
 
+// indexEntry is a trace ID found in an index, with the start time of the indexed span
+type indexEntry struct {
+	traceID   model.TraceID
+	timestamp uint64
+}
+
 // indexSeeksToTraceIDs does the index scanning against badger based on the parsed index queries
-func (r *TraceReader) indexSeeksToTraceIDs(plan *executionPlan, indexSeeks [][]byte) ([]model.TraceID, error) {
+func (r *TraceReader) indexSeeksToTraceIDs(plan *executionPlan, indexSeeks [][]byte) ([]indexEntry, error) {
 
 	for i := len(indexSeeks) - 1; i > 0; i-- {
 		indexResults, err := r.scanIndexKeys(indexSeeks[i], plan)
@@ -291,14 +340,14 @@ func (r *TraceReader) indexSeeksToTraceIDs(plan *executionPlan, indexSeeks [][]b
 		}
 
 		sort.Slice(indexResults, func(k, h int) bool {
-			return bytes.Compare(indexResults[k], indexResults[h]) < 0
+			return bytes.Compare(indexResults[k][8:], indexResults[h][8:]) < 0
 		})
 
 		// Same traceID can be returned multiple times, but always in sorted order so checking the previous key is enough
 		prevTraceID := []byte{}
 		innerIDs := make([][]byte, 0, len(indexSeeks))
 		for j := 0; j < len(indexResults); j++ {
-			traceID := indexResults[j]
+			traceID := indexResults[j][8:]
 			if !bytes.Equal(prevTraceID, traceID) {
 				innerIDs = append(innerIDs, traceID)
 				prevTraceID = traceID
@@ -314,7 +363,7 @@ func (r *TraceReader) indexSeeksToTraceIDs(plan *executionPlan, indexSeeks [][]b
 	}
 
 	// Last scan should get us in correct timestamp order
-	ids, err := r.scanIndexKeys(indexSeeks[0], plan)
+	entries, err := r.scanIndexKeys(indexSeeks[0], plan)
 	if err != nil {
 		return nil, err
 	}
@@ -325,22 +374,28 @@ func (r *TraceReader) indexSeeksToTraceIDs(plan *executionPlan, indexSeeks [][]b
 		plan.mergeOuter = nil
 	} else {
 		// We filter the last elements
+		ids := make([][]byte, len(entries))
+		for i, entry := range entries {
+			ids[i] = entry[8:]
+		}
 		plan.hashOuter = buildHash(plan, ids)
 	}
 
-	traceIDs := filterIDs(plan, ids)
-	return traceIDs, nil
+	return filterIDs(plan, entries), nil
 }
 
-func filterIDs(plan *executionPlan, innerIDs [][]byte) []model.TraceID {
-	traces := make([]model.TraceID, 0, plan.limit)
+func filterIDs(plan *executionPlan, entries [][]byte) []indexEntry {
+	traces := make([]indexEntry, 0, plan.limit)
 
 	items := 0
-	for i := 0; i < len(innerIDs); i++ {
-		trID := bytesToTraceID(innerIDs[i])
+	for i := 0; i < len(entries); i++ {
+		trID := bytesToTraceID(entries[i][8:])
 
 		if _, found := plan.hashOuter[trID]; found {
-			traces = append(traces, trID)
+			traces = append(traces, indexEntry{
+				traceID:   trID,
+				timestamp: binary.BigEndian.Uint64(entries[i][:8]),
+			})
 			delete(plan.hashOuter, trID) // Prevent duplicate add
 			items++
 		}
@@ -353,6 +408,39 @@ func filterIDs(plan *executionPlan, innerIDs [][]byte) []model.TraceID {
 	return traces
 }
 
+// mergeIndexEntries merges the entries found for several index seeks into the most recent limit trace IDs.
+func mergeIndexEntries(entryLists [][]indexEntry, limit int) []model.TraceID {
+	latest := make(map[model.TraceID]uint64)
+	for _, entries := range entryLists {
+		for _, entry := range entries {
+			if timestamp, ok := latest[entry.traceID]; !ok || entry.timestamp > timestamp {
+				latest[entry.traceID] = entry.timestamp
+			}
+		}
+	}
+	merged := make([]indexEntry, 0, len(latest))
+	for traceID, timestamp := range latest {
+		merged = append(merged, indexEntry{traceID: traceID, timestamp: timestamp})
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].timestamp != merged[j].timestamp {
+			return merged[i].timestamp > merged[j].timestamp
+		}
+		if merged[i].traceID.High != merged[j].traceID.High {
+			return merged[i].traceID.High > merged[j].traceID.High
+		}
+		return merged[i].traceID.Low > merged[j].traceID.Low
+	})
+	if limit > 0 && len(merged) > limit {
+		merged = merged[:limit]
+	}
+	traceIDs := make([]model.TraceID, len(merged))
+	for i, entry := range merged {
+		traceIDs[i] = entry.traceID
+	}
+	return traceIDs
+}
+
 func bytesToTraceID(key []byte) model.TraceID {
 	return model.TraceID{
 		High: binary.BigEndian.Uint64(key[:8]),
@@ -465,36 +553,68 @@ func (r *TraceReader) FindTraceIDs(ctx context.Context, query *spanstore.TraceQu
 
 	setQueryDefaults(query)
 
-	// Find matches using indexes that are using service as part of the key
-	indexSeeks := make([][]byte, 0, 1)
-	indexSeeks = serviceQueries(query, indexSeeks)
+	pageToken, err := spanstore.ParsePageToken(query.PageToken)
+	if err != nil {
+		return nil, err
+	}
 
 	startStampBytes := make([]byte, 8)
 	binary.BigEndian.PutUint64(startStampBytes, model.TimeAsEpochMicroseconds(query.StartTimeMin))
 
+	endStamp := model.TimeAsEpochMicroseconds(query.StartTimeMax)
+	if pageToken != nil {
+		// Results are sorted by timestamp descending, so the next page can never start after the token
+		if tokenStamp := model.TimeAsEpochMicroseconds(pageToken.StartTime); tokenStamp < endStamp {
+			endStamp = tokenStamp
+		}
+	}
 	endStampBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(endStampBytes, model.TimeAsEpochMicroseconds(query.StartTimeMax))
+	binary.BigEndian.PutUint64(endStampBytes, endStamp)
 
 	plan := &executionPlan{
 		startTimeMin: startStampBytes,
 		startTimeMax: endStampBytes,
 		limit:        query.NumTraces,
+		pageToken:    pageToken,
 	}
 
+	var durationHash map[model.TraceID]struct{}
 	if query.DurationMax != 0 || query.DurationMin != 0 {
-		plan.hashOuter = r.durationQueries(plan, query)
-	}
-
-	if len(indexSeeks) > 0 {
-		keys, err := r.indexSeeksToTraceIDs(plan, indexSeeks)
+		durationHash = r.durationQueries(plan, query)
+	}
+
+	// Find matches using indexes that are using service as part of the key,
+	// one service and operation at a time
+	serviceQueryList := query.SplitByServiceAndOperation()
+	entryLists := make([][]indexEntry, 0, len(serviceQueryList))
+	for _, serviceQuery := range serviceQueryList {
+		indexSeeks := make([][]byte, 0, 1)
+		indexSeeks = serviceQueries(serviceQuery, indexSeeks)
+		servicePlan := *plan
+		servicePlan.hashOuter = copyHash(durationHash)
+		if len(indexSeeks) == 0 {
+			return r.scanTimeRange(&servicePlan)
+		}
+		entries, err := r.indexSeeksToTraceIDs(&servicePlan, indexSeeks)
 		if err != nil {
 			return nil, err
 		}
-
-		return keys, nil
+		entryLists = append(entryLists, entries)
 	}
 
-	return r.scanTimeRange(plan)
+	return mergeIndexEntries(entryLists, plan.limit), nil
+}
+
+// copyHash copies the filter hash, which is consumed by each index scan
+func copyHash(hash map[model.TraceID]struct{}) map[model.TraceID]struct{} {
+	if hash == nil {
+		return nil
+	}
+	hashCopy := make(map[model.TraceID]struct{}, len(hash))
+	for k, v := range hash {
+		hashCopy[k] = v
+	}
+	return hashCopy
 }
 
 // validateQuery returns an error if certain restrictions are not met
@@ -502,10 +622,10 @@ func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if p == nil {
 		return ErrMalformedRequestObject
 	}
-	if p.ServiceName == "" && len(p.Tags) > 0 {
+	if len(p.Services()) == 0 && len(p.Tags) > 0 {
 		return ErrServiceNameNotSet
 	}
-	if p.ServiceName == "" && p.OperationName != "" {
+	if len(p.Services()) == 0 && len(p.Operations()) > 0 {
 		return ErrServiceNameNotSet
 	}
 	if p.StartTimeMin.IsZero() || p.StartTimeMax.IsZero() {
@@ -521,6 +641,7 @@ func validateQuery(p *spanstore.TraceQueryParameters) error {
 }
 
 // scanIndexKeys scans the time range for index keys matching the given prefix.
+// It returns the timestamp and trace ID suffixes of the keys, in descending timestamp order.
 func (r *TraceReader) scanIndexKeys(indexKeyValue []byte, plan *executionPlan) ([][]byte, error) {
 	indexResults := make([][]byte, 0)
 
@@ -545,10 +666,13 @@ func (r *TraceReader) scanIndexKeys(indexKeyValue []byte, plan *executionPlan) (
 			timestampStartIndex := len(it.Item().Key()) - (sizeOfTraceID + 8) // timestamp is stored with 8 bytes
 			if bytes.Equal(indexKeyValue, it.Item().Key()[:timestampStartIndex]) {
 				traceIDBytes := item.Key()[len(item.Key())-sizeOfTraceID:]
+				if !plan.admits(item.Key()[timestampStartIndex:timestampStartIndex+8], traceIDBytes) {
+					continue
+				}
 
-				traceIDCopy := make([]byte, sizeOfTraceID)
-				copy(traceIDCopy, traceIDBytes)
-				indexResults = append(indexResults, traceIDCopy)
+				entryCopy := make([]byte, 8+sizeOfTraceID)
+				copy(entryCopy, item.Key()[timestampStartIndex:])
+				indexResults = append(indexResults, entryCopy)
 			}
 		}
 		return nil
@@ -25,6 +25,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/catalog"
 )
 
 /*
@@ -51,16 +52,18 @@ type SpanWriter struct {
 	store        *badger.DB
 	ttl          time.Duration
 	cache        *CacheStore
+	catalog      *catalog.Tracker
 	closer       io.Closer
 	encodingType byte
 }
 
-// NewSpanWriter returns a SpawnWriter with cache
-func NewSpanWriter(db *badger.DB, c *CacheStore, ttl time.Duration, storageCloser io.Closer) *SpanWriter {
+// NewSpanWriter returns a SpawnWriter with cache, and tracking the service activity if the tracker is not nil
+func NewSpanWriter(db *badger.DB, c *CacheStore, tracker *catalog.Tracker, ttl time.Duration, storageCloser io.Closer) *SpanWriter {
 	return &SpanWriter{
 		store:        db,
 		ttl:          ttl,
 		cache:        c,
+		catalog:      tracker,
 		closer:       storageCloser,
 		encodingType: defaultEncoding, // TODO Make configurable
 	}
@@ -122,6 +125,9 @@ func (w *SpanWriter) WriteSpan(span *model.Span) error {
 
 	// Do cache refresh here to release the transaction earlier
 	w.cache.Update(span.Process.ServiceName, span.OperationName, expireTime)
+	if w.catalog != nil {
+		w.catalog.Record(span)
+	}
 
 	return err
 }
@@ -85,6 +85,10 @@ func TestCassandraFactory(t *testing.T) {
 	_, err = f.CreateDependencyReader()
 	assert.NoError(t, err)
 
+	catalogReader, err := f.CreateCatalogReader()
+	assert.NoError(t, err)
+	assert.Equal(t, f.catalog, catalogReader)
+
 	_, err = f.CreateArchiveSpanReader()
 	assert.EqualError(t, err, "archive storage not configured")
 
@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -41,33 +42,35 @@ const (
 		FROM traces
 		WHERE trace_id = ?`
 	queryByTag = `
-		SELECT trace_id
+		SELECT trace_id, start_time
 		FROM tag_index
 		WHERE service_name = ? AND tag_key = ? AND tag_value = ? and start_time > ? and start_time < ?
 		ORDER BY start_time DESC
 		LIMIT ?`
 	queryByServiceName = `
-		SELECT trace_id
+		SELECT trace_id, start_time
 		FROM service_name_index
 		WHERE bucket IN ` + bucketRange + ` AND service_name = ? AND start_time > ? AND start_time < ?
 		ORDER BY start_time DESC
 		LIMIT ?`
 	queryByServiceAndOperationName = `
-		SELECT trace_id
+		SELECT trace_id, start_time
 		FROM service_operation_index
 		WHERE service_name = ? AND operation_name = ? AND start_time > ? AND start_time < ?
 		ORDER BY start_time DESC
 		LIMIT ?`
 	queryByDuration = `
-		SELECT trace_id
+		SELECT trace_id, start_time
 		FROM duration_index
-		WHERE bucket = ? AND service_name = ? AND operation_name = ? AND duration > ? AND duration < ?
-		LIMIT ?`
+		WHERE bucket = ? AND service_name = ? AND operation_name = ? AND duration > ? AND duration < ?`
 
 	defaultNumTraces = 100
 	// limitMultiple exists because many spans that are returned from indices can have the same trace, limitMultiple increases
 	// the number of responses from the index, so we can respect the user's limit value they provided.
+	// It is the initial row limit of scanIndex, which doubles it until the rows hold the page.
 	limitMultiple = 3
+	// streamBatchSize is the number of spans passed at a time to the function of StreamTrace
+	streamBatchSize = 100
 )
 
 var (
@@ -162,6 +165,20 @@ func (s *SpanReader) readTrace(ctx context.Context, traceID dbmodel.TraceID) (*m
 }
 
 func (s *SpanReader) readTraceInSpan(ctx context.Context, traceID dbmodel.TraceID) (*model.Trace, error) {
+	retMe := &model.Trace{}
+	err := s.scanTrace(traceID, func(part *model.Trace) bool {
+		retMe.Spans = append(retMe.Spans, part.Spans...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return retMe, nil
+}
+
+// scanTrace passes fn the spans of the trace by batches of streamBatchSize as they are read,
+// until they are exhausted or fn returns false.
+func (s *SpanReader) scanTrace(traceID dbmodel.TraceID, fn func(part *model.Trace) bool) error {
 	start := time.Now()
 	q := s.session.Query(querySpanByTraceID, traceID)
 	i := q.Iter()
@@ -173,8 +190,9 @@ func (s *SpanReader) readTraceInSpan(ctx context.Context, traceID dbmodel.TraceI
 	var refs []dbmodel.SpanRef
 	var tags []dbmodel.KeyValue
 	var logs []dbmodel.Log
-	retMe := &model.Trace{}
-	for i.Scan(&traceIDFromSpan, &spanID, &parentID, &operationName, &flags, &startTime, &duration, &tags, &logs, &refs, &dbProcess) {
+	found, more := false, true
+	spans := make([]*model.Span, 0, streamBatchSize)
+	for more && i.Scan(&traceIDFromSpan, &spanID, &parentID, &operationName, &flags, &startTime, &duration, &tags, &logs, &refs, &dbProcess) {
 		dbSpan := dbmodel.Span{
 			TraceID:       traceIDFromSpan,
 			SpanID:        spanID,
@@ -191,21 +209,30 @@ func (s *SpanReader) readTraceInSpan(ctx context.Context, traceID dbmodel.TraceI
 		}
 		span, err := dbmodel.ToDomain(&dbSpan)
 		if err != nil {
+			i.Close()
 			s.metrics.readTraces.Emit(err, time.Since(start))
-			return nil, err
+			return err
+		}
+		found = true
+		spans = append(spans, span)
+		if len(spans) == streamBatchSize {
+			more = fn(&model.Trace{Spans: spans})
+			spans = make([]*model.Span, 0, streamBatchSize)
 		}
-		retMe.Spans = append(retMe.Spans, span)
 	}
 
 	err := i.Close()
 	s.metrics.readTraces.Emit(err, time.Since(start))
 	if err != nil {
-		return nil, fmt.Errorf("error reading traces from storage: %w", err)
+		return fmt.Errorf("error reading traces from storage: %w", err)
 	}
-	if len(retMe.Spans) == 0 {
-		return nil, spanstore.ErrTraceNotFound
+	if !found {
+		return spanstore.ErrTraceNotFound
 	}
-	return retMe, nil
+	if more && len(spans) > 0 {
+		fn(&model.Trace{Spans: spans})
+	}
+	return nil
 }
 
 // GetTrace takes a traceID and returns a Trace associated with that traceID
@@ -213,11 +240,24 @@ func (s *SpanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*mode
 	return s.readTrace(ctx, dbmodel.TraceIDFromDomain(traceID))
 }
 
+// StreamTrace implements spanstore.TraceStreamer, passing fn the spans of the trace by batches of streamBatchSize
+// as they are read, so that the rest of the trace is left unread once fn returns false.
+func (s *SpanReader) StreamTrace(ctx context.Context, traceID model.TraceID, fn func(part *model.Trace) bool) error {
+	span, _ := startSpanForQuery(ctx, "streamTrace", querySpanByTraceID)
+	defer span.Finish()
+	dbTraceID := dbmodel.TraceIDFromDomain(traceID)
+	span.LogFields(otlog.String("event", "searching"), otlog.Object("trace_id", dbTraceID))
+
+	err := s.scanTrace(dbTraceID, fn)
+	logErrorToSpan(span, err)
+	return err
+}
+
 func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if p == nil {
 		return ErrMalformedRequestObject
 	}
-	if p.ServiceName == "" && len(p.Tags) > 0 {
+	if len(p.Services()) == 0 && len(p.Tags) > 0 {
 		return ErrServiceNameNotSet
 	}
 	if p.StartTimeMin.IsZero() || p.StartTimeMax.IsZero() {
@@ -232,6 +272,9 @@ func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if (p.DurationMin != 0 || p.DurationMax != 0) && len(p.Tags) > 0 {
 		return ErrDurationAndTagQueryNotSupported
 	}
+	if _, err := spanstore.ParsePageToken(p.PageToken); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -262,37 +305,121 @@ func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 		traceQuery.NumTraces = defaultNumTraces
 	}
 
-	dbTraceIDs, err := s.findTraceIDs(ctx, traceQuery)
-	if err != nil {
-		return nil, err
+	// the token was validated by validateQuery
+	pageToken, _ := spanstore.ParsePageToken(traceQuery.PageToken)
+	indexQuery := traceQuery
+	if pageToken != nil {
+		// The next page starts at the token's time, the index scans read up to it and stop once they hold the
+		// page, see scanIndex and queryByDuration. The bound is exclusive, hence the extra microsecond.
+		if after := pageToken.StartTime.Add(time.Microsecond); after.Before(traceQuery.StartTimeMax) {
+			narrowed := *traceQuery
+			narrowed.StartTimeMax = after
+			indexQuery = &narrowed
+		}
 	}
 
-	var traceIDs []model.TraceID
-	for t := range dbTraceIDs {
-		if len(traceIDs) >= traceQuery.NumTraces {
-			break
+	// The index tables are keyed by a single service and operation, so each combination is looked up separately
+	dbTraceIDs := indexedTraceIDs{}
+	for _, serviceQuery := range indexQuery.SplitByServiceAndOperation() {
+		serviceTraceIDs, err := s.findTraceIDs(ctx, serviceQuery, pageToken)
+		if err != nil {
+			return nil, err
 		}
-		traceIDs = append(traceIDs, t.ToDomain())
+		for traceID, startTime := range serviceTraceIDs {
+			dbTraceIDs.add(traceID, startTime)
+		}
+	}
+
+	// Keep the most recent traces after the token, in the order of spanstore.PageToken
+	keys := make([]spanstore.PageToken, 0, len(dbTraceIDs))
+	for t, startTime := range dbTraceIDs {
+		key := spanstore.PageToken{StartTime: model.EpochMicrosecondsAsTime(uint64(startTime)), TraceID: t.ToDomain()}
+		if pageToken.Admits(key.StartTime, key.TraceID) {
+			keys = append(keys, key)
+		}
+	}
+	spanstore.SortPageTokens(keys)
+	if len(keys) > traceQuery.NumTraces {
+		keys = keys[:traceQuery.NumTraces]
+	}
+	traceIDs := make([]model.TraceID, len(keys))
+	for i, key := range keys {
+		traceIDs[i] = key.TraceID
 	}
 	return traceIDs, nil
 }
 
-func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) (dbmodel.UniqueTraceIDs, error) {
+// indexedTraceIDs maps the trace IDs found in the index tables to the latest start_time of their index rows.
+// For a single index, this is the latest span matching the query, the sort key of spanstore.PageToken.
+type indexedTraceIDs map[dbmodel.TraceID]int64
+
+func (ids indexedTraceIDs) add(traceID dbmodel.TraceID, startTime int64) {
+	if latest, ok := ids[traceID]; !ok || startTime > latest {
+		ids[traceID] = startTime
+	}
+}
+
+// intersectIndexedTraceIDs keeps the trace IDs found in all indices, with the earliest of their latest
+// start times, which is the closest to the latest span matching all indices.
+// hasPage returns true if the trace IDs include the numTraces most recent ones admitted by the page token,
+// given that the index rows left unread start no later than unreadMax. The start times of the trace IDs
+// are final then, since they are those of their latest rows.
+func (ids indexedTraceIDs) hasPage(numTraces int, pageToken *spanstore.PageToken, unreadMax int64) bool {
+	var startTimes []int64
+	for traceID, startTime := range ids {
+		if pageToken.Admits(model.EpochMicrosecondsAsTime(uint64(startTime)), traceID.ToDomain()) {
+			startTimes = append(startTimes, startTime)
+		}
+	}
+	if len(startTimes) < numTraces {
+		return false
+	}
+	sort.Slice(startTimes, func(i, j int) bool {
+		return startTimes[i] > startTimes[j]
+	})
+	return startTimes[numTraces-1] > unreadMax
+}
+
+func intersectIndexedTraceIDs(idsList []indexedTraceIDs) indexedTraceIDs {
+	result := indexedTraceIDs{}
+	for traceID, startTime := range idsList[0] {
+		for _, ids := range idsList[1:] {
+			otherStartTime, ok := ids[traceID]
+			if !ok {
+				startTime = -1
+				break
+			}
+			if otherStartTime < startTime {
+				startTime = otherStartTime
+			}
+		}
+		if startTime >= 0 {
+			result[traceID] = startTime
+		}
+	}
+	return result
+}
+
+func (s *SpanReader) findTraceIDs(
+	ctx context.Context,
+	traceQuery *spanstore.TraceQueryParameters,
+	pageToken *spanstore.PageToken,
+) (indexedTraceIDs, error) {
 	if traceQuery.DurationMin != 0 || traceQuery.DurationMax != 0 {
-		return s.queryByDuration(ctx, traceQuery)
+		return s.queryByDuration(ctx, traceQuery, pageToken)
 	}
 
 	if traceQuery.OperationName != "" {
-		traceIds, err := s.queryByServiceNameAndOperation(ctx, traceQuery)
+		traceIds, err := s.queryByServiceNameAndOperation(ctx, traceQuery, pageToken)
 		if err != nil {
 			return nil, err
 		}
 		if len(traceQuery.Tags) > 0 {
-			tagTraceIds, err := s.queryByTagsAndLogs(ctx, traceQuery)
+			tagTraceIds, err := s.queryByTagsAndLogs(ctx, traceQuery, pageToken)
 			if err != nil {
 				return nil, err
 			}
-			return dbmodel.IntersectTraceIDs([]dbmodel.UniqueTraceIDs{
+			return intersectIndexedTraceIDs([]indexedTraceIDs{
 				traceIds,
 				tagTraceIds,
 			}), nil
@@ -300,49 +427,63 @@ func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 		return traceIds, nil
 	}
 	if len(traceQuery.Tags) > 0 {
-		return s.queryByTagsAndLogs(ctx, traceQuery)
+		return s.queryByTagsAndLogs(ctx, traceQuery, pageToken)
 	}
-	return s.queryByService(ctx, traceQuery)
+	return s.queryByService(ctx, traceQuery, pageToken)
 }
 
-func (s *SpanReader) queryByTagsAndLogs(ctx context.Context, tq *spanstore.TraceQueryParameters) (dbmodel.UniqueTraceIDs, error) {
+func (s *SpanReader) queryByTagsAndLogs(
+	ctx context.Context,
+	tq *spanstore.TraceQueryParameters,
+	pageToken *spanstore.PageToken,
+) (indexedTraceIDs, error) {
 	span, ctx := startSpanForQuery(ctx, "queryByTagsAndLogs", queryByTag)
 	defer span.Finish()
 
-	results := make([]dbmodel.UniqueTraceIDs, 0, len(tq.Tags))
+	results := make([]indexedTraceIDs, 0, len(tq.Tags))
 	for k, v := range tq.Tags {
 		childSpan, _ := opentracing.StartSpanFromContext(ctx, "queryByTag")
 		childSpan.LogFields(otlog.String("tag.key", k), otlog.String("tag.value", v))
-		query := s.session.Query(
-			queryByTag,
-			tq.ServiceName,
-			k,
-			v,
-			model.TimeAsEpochMicroseconds(tq.StartTimeMin),
-			model.TimeAsEpochMicroseconds(tq.StartTimeMax),
-			tq.NumTraces*limitMultiple,
-		).PageSize(0)
-		t, err := s.executeQuery(childSpan, query, s.metrics.queryTagIndex)
+		t, err := s.scanIndex(childSpan, tq.NumTraces, pageToken, s.metrics.queryTagIndex, func(limit int) cassandra.Query {
+			return s.session.Query(
+				queryByTag,
+				tq.ServiceName,
+				k,
+				v,
+				model.TimeAsEpochMicroseconds(tq.StartTimeMin),
+				model.TimeAsEpochMicroseconds(tq.StartTimeMax),
+				limit,
+			).PageSize(0)
+		})
 		childSpan.Finish()
 		if err != nil {
 			return nil, err
 		}
 		results = append(results, t)
 	}
-	return dbmodel.IntersectTraceIDs(results), nil
+	return intersectIndexedTraceIDs(results), nil
 }
 
-func (s *SpanReader) queryByDuration(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) (dbmodel.UniqueTraceIDs, error) {
+// queryByDuration reads the duration index buckets from the most recent one. The rows of a bucket are ordered by
+// duration, so all of them are read, with the driver's paging, and filtered by start time. The older buckets are
+// left unread once the page of trace IDs is known.
+func (s *SpanReader) queryByDuration(
+	ctx context.Context,
+	traceQuery *spanstore.TraceQueryParameters,
+	pageToken *spanstore.PageToken,
+) (indexedTraceIDs, error) {
 	span, ctx := startSpanForQuery(ctx, "queryByDuration", queryByDuration)
 	defer span.Finish()
 
-	results := dbmodel.UniqueTraceIDs{}
+	results := indexedTraceIDs{}
 
 	minDurationMicros := traceQuery.DurationMin.Nanoseconds() / int64(time.Microsecond/time.Nanosecond)
 	maxDurationMicros := (time.Hour * 24).Nanoseconds() / int64(time.Microsecond/time.Nanosecond)
 	if traceQuery.DurationMax != 0 {
 		maxDurationMicros = traceQuery.DurationMax.Nanoseconds() / int64(time.Microsecond/time.Nanosecond)
 	}
+	minStartTime := int64(model.TimeAsEpochMicroseconds(traceQuery.StartTimeMin))
+	maxStartTime := int64(model.TimeAsEpochMicroseconds(traceQuery.StartTimeMax))
 
 	// See writer.go:indexByDuration  for how this is indexed
 	// This is indexed in hours since epoch
@@ -358,58 +499,104 @@ func (s *SpanReader) queryByDuration(ctx context.Context, traceQuery *spanstore.
 			traceQuery.ServiceName,
 			traceQuery.OperationName,
 			minDurationMicros,
-			maxDurationMicros,
-			traceQuery.NumTraces*limitMultiple)
-		t, err := s.executeQuery(childSpan, query, s.metrics.queryDurationIndex)
+			maxDurationMicros)
+		err := s.executeQuery(childSpan, query, s.metrics.queryDurationIndex, func(traceID dbmodel.TraceID, startTime int64) {
+			if startTime > minStartTime && startTime < maxStartTime {
+				results.add(traceID, startTime)
+			}
+		})
 		childSpan.Finish()
 		if err != nil {
 			return nil, err
 		}
-
-		for traceID := range t {
-			results.Add(traceID)
-			if len(results) == traceQuery.NumTraces {
-				break
-			}
+		// the spans of the older buckets started before the earliest start time rounded to this bucket
+		unreadMax := int64(model.TimeAsEpochMicroseconds(timeBucket.Add(-durationBucketSize/2))) - 1
+		if results.hasPage(traceQuery.NumTraces, pageToken, unreadMax) {
+			break
 		}
 	}
 	return results, nil
 }
 
-func (s *SpanReader) queryByServiceNameAndOperation(ctx context.Context, tq *spanstore.TraceQueryParameters) (dbmodel.UniqueTraceIDs, error) {
+func (s *SpanReader) queryByServiceNameAndOperation(
+	ctx context.Context,
+	tq *spanstore.TraceQueryParameters,
+	pageToken *spanstore.PageToken,
+) (indexedTraceIDs, error) {
 	span, _ := startSpanForQuery(ctx, "queryByServiceNameAndOperation", queryByServiceAndOperationName)
 	defer span.Finish()
-	query := s.session.Query(
-		queryByServiceAndOperationName,
-		tq.ServiceName,
-		tq.OperationName,
-		model.TimeAsEpochMicroseconds(tq.StartTimeMin),
-		model.TimeAsEpochMicroseconds(tq.StartTimeMax),
-		tq.NumTraces*limitMultiple,
-	).PageSize(0)
-	return s.executeQuery(span, query, s.metrics.queryServiceOperationIndex)
+	return s.scanIndex(span, tq.NumTraces, pageToken, s.metrics.queryServiceOperationIndex, func(limit int) cassandra.Query {
+		return s.session.Query(
+			queryByServiceAndOperationName,
+			tq.ServiceName,
+			tq.OperationName,
+			model.TimeAsEpochMicroseconds(tq.StartTimeMin),
+			model.TimeAsEpochMicroseconds(tq.StartTimeMax),
+			limit,
+		).PageSize(0)
+	})
 }
 
-func (s *SpanReader) queryByService(ctx context.Context, tq *spanstore.TraceQueryParameters) (dbmodel.UniqueTraceIDs, error) {
+func (s *SpanReader) queryByService(
+	ctx context.Context,
+	tq *spanstore.TraceQueryParameters,
+	pageToken *spanstore.PageToken,
+) (indexedTraceIDs, error) {
 	span, _ := startSpanForQuery(ctx, "queryByService", queryByServiceName)
 	defer span.Finish()
-	query := s.session.Query(
-		queryByServiceName,
-		tq.ServiceName,
-		model.TimeAsEpochMicroseconds(tq.StartTimeMin),
-		model.TimeAsEpochMicroseconds(tq.StartTimeMax),
-		tq.NumTraces*limitMultiple,
-	).PageSize(0)
-	return s.executeQuery(span, query, s.metrics.queryServiceNameIndex)
+	// the rows of the buckets are ordered by the IN query, which cannot be paged by the driver
+	return s.scanIndex(span, tq.NumTraces, pageToken, s.metrics.queryServiceNameIndex, func(limit int) cassandra.Query {
+		return s.session.Query(
+			queryByServiceName,
+			tq.ServiceName,
+			model.TimeAsEpochMicroseconds(tq.StartTimeMin),
+			model.TimeAsEpochMicroseconds(tq.StartTimeMax),
+			limit,
+		).PageSize(0)
+	})
 }
 
-func (s *SpanReader) executeQuery(span opentracing.Span, query cassandra.Query, tableMetrics *casMetrics.Table) (dbmodel.UniqueTraceIDs, error) {
+// scanIndex reads the rows of an index query ordered by start_time descending, built by newQuery with a row limit,
+// until they hold the numTraces most recent traces admitted by the page token and all the rows tied with the last
+// of them. The rows of a trace may use up the limit, so the query is run again with a doubled limit if needed.
+func (s *SpanReader) scanIndex(
+	span opentracing.Span,
+	numTraces int,
+	pageToken *spanstore.PageToken,
+	tableMetrics *casMetrics.Table,
+	newQuery func(limit int) cassandra.Query,
+) (indexedTraceIDs, error) {
+	for limit := numTraces * limitMultiple; ; limit *= 2 {
+		retMe := indexedTraceIDs{}
+		rows := 0
+		var lastStartTime int64
+		err := s.executeQuery(span, newQuery(limit), tableMetrics, func(traceID dbmodel.TraceID, startTime int64) {
+			retMe.add(traceID, startTime)
+			rows++
+			lastStartTime = startTime
+		})
+		if err != nil {
+			return nil, err
+		}
+		if rows < limit || retMe.hasPage(numTraces, pageToken, lastStartTime) {
+			return retMe, nil
+		}
+	}
+}
+
+// executeQuery passes the trace ID and start time of each row of the index query to fn.
+func (s *SpanReader) executeQuery(
+	span opentracing.Span,
+	query cassandra.Query,
+	tableMetrics *casMetrics.Table,
+	fn func(traceID dbmodel.TraceID, startTime int64),
+) error {
 	start := time.Now()
 	i := query.Iter()
-	retMe := dbmodel.UniqueTraceIDs{}
 	var traceID dbmodel.TraceID
-	for i.Scan(&traceID) {
-		retMe.Add(traceID)
+	var startTime int64
+	for i.Scan(&traceID, &startTime) {
+		fn(traceID, startTime)
 	}
 	err := i.Close()
 	tableMetrics.Emit(err, time.Since(start))
@@ -417,9 +604,9 @@ func (s *SpanReader) executeQuery(span opentracing.Span, query cassandra.Query,
 		logErrorToSpan(span, err)
 		span.LogFields(otlog.String("query", query.String()))
 		s.logger.Error("Failed to exec query", zap.Error(err), zap.String("query", query.String()))
-		return nil, err
+		return err
 	}
-	return retMe, nil
+	return nil
 }
 
 func startSpanForQuery(ctx context.Context, name, query string) (opentracing.Span, context.Context) {
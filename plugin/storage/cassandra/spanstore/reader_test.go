@@ -148,6 +148,55 @@ func TestSpanReaderGetTrace(t *testing.T) {
 	}
 }
 
+func TestSpanReaderStreamTrace(t *testing.T) {
+	withStreamedSpans := func(spans int, fn func(r *spanReaderTest, iter *mocks.Iterator)) {
+		withSpanReader(func(r *spanReaderTest) {
+			iter := &mocks.Iterator{}
+			scanned := 0
+			iter.On("Scan", matchEverything()).Return(func(...interface{}) bool {
+				scanned++
+				return scanned <= spans
+			})
+			iter.On("Close").Return(nil)
+
+			query := &mocks.Query{}
+			query.On("Iter").Return(iter)
+			r.session.On("Query", mock.AnythingOfType("string"), matchEverything()).Return(query)
+			fn(r, iter)
+		})
+	}
+
+	withStreamedSpans(250, func(r *spanReaderTest, iter *mocks.Iterator) {
+		var parts []int
+		err := r.reader.StreamTrace(context.Background(), model.TraceID{}, func(part *model.Trace) bool {
+			parts = append(parts, len(part.Spans))
+			return true
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{streamBatchSize, streamBatchSize, 50}, parts)
+	})
+
+	// the rest of the trace is left unread
+	withStreamedSpans(250, func(r *spanReaderTest, iter *mocks.Iterator) {
+		parts := 0
+		err := r.reader.StreamTrace(context.Background(), model.TraceID{}, func(part *model.Trace) bool {
+			parts++
+			return false
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, parts)
+		iter.AssertNumberOfCalls(t, "Scan", streamBatchSize)
+	})
+
+	withStreamedSpans(0, func(r *spanReaderTest, iter *mocks.Iterator) {
+		err := r.reader.StreamTrace(context.Background(), model.TraceID{}, func(part *model.Trace) bool {
+			assert.Fail(t, "no part expected")
+			return true
+		})
+		assert.Equal(t, spanstore.ErrTraceNotFound, err)
+	})
+}
+
 func TestSpanReaderGetTrace_TraceNotFound(t *testing.T) {
 	withSpanReader(func(r *spanReaderTest) {
 		iter := &mocks.Iterator{}
@@ -180,6 +229,8 @@ func TestSpanReaderFindTraces(t *testing.T) {
 		queryTags                         bool
 		queryOperation                    bool
 		queryDuration                     bool
+		queryServices                     bool
+		pageToken                         string
 		mainQueryError                    error
 		tagsQueryError                    error
 		serviceNameAndOperationQueryError error
@@ -203,6 +254,21 @@ func TestSpanReaderFindTraces(t *testing.T) {
 			numTraces:     1,
 			expectedCount: 1,
 		},
+		{
+			caption: "with page token",
+			// the mocked index rows have no start time, the second trace is the token's
+			pageToken: spanstore.PageToken{
+				StartTime: model.EpochMicrosecondsAsTime(0),
+				TraceID:   model.NewTraceID(0, 2),
+			}.String(),
+			expectedCount: 1,
+		},
+		{
+			caption:        "multiple services and operations",
+			queryServices:  true,
+			queryOperation: true,
+			expectedCount:  2,
+		},
 		{
 			caption:        "main query error",
 			mainQueryError: errors.New("main query error"),
@@ -288,8 +354,8 @@ func TestSpanReaderFindTraces(t *testing.T) {
 		testCase := tc // capture loop var
 		t.Run(testCase.caption, func(t *testing.T) {
 			withSpanReader(func(r *spanReaderTest) {
-				// scanMatcher can match Iter.Scan() parameters and set trace ID fields
-				scanMatcher := func(name string) interface{} {
+				// scanMatcher can match Iter.Scan() parameters and set trace ID fields, and the start time if not 0
+				scanMatcher := func(name string, startTime int64) interface{} {
 					traceIDs := []dbmodel.TraceID{
 						dbmodel.TraceIDFromDomain(model.NewTraceID(0, 1)),
 						dbmodel.TraceIDFromDomain(model.NewTraceID(0, 2)),
@@ -304,15 +370,21 @@ func TestSpanReaderFindTraces(t *testing.T) {
 								break
 							}
 						}
+						for _, arg := range args {
+							if ptr, ok := arg.(*int64); ok && startTime != 0 {
+								*ptr = startTime
+								break
+							}
+						}
 						traceIDs = traceIDs[1:]
 						return true
 					}
 					return mock.MatchedBy(scanFunc)
 				}
 
-				mockQuery := func(queryErr error) *mocks.Query {
+				mockQuery := func(queryErr error, startTime int64) *mocks.Query {
 					iter := &mocks.Iterator{}
-					iter.On("Scan", scanMatcher("queryIter")).Return(true)
+					iter.On("Scan", scanMatcher("queryIter", startTime)).Return(true)
 					iter.On("Scan", matchEverything()).Return(false)
 					iter.On("Close").Return(queryErr)
 
@@ -326,14 +398,15 @@ func TestSpanReaderFindTraces(t *testing.T) {
 					return query
 				}
 
-				mainQuery := mockQuery(testCase.mainQueryError)
-				tagsQuery := mockQuery(testCase.tagsQueryError)
-				operationQuery := mockQuery(testCase.serviceNameAndOperationQueryError)
-				durationQuery := mockQuery(testCase.durationQueryError)
+				mainQuery := mockQuery(testCase.mainQueryError, 0)
+				tagsQuery := mockQuery(testCase.tagsQueryError, 0)
+				operationQuery := mockQuery(testCase.serviceNameAndOperationQueryError, 0)
+				// the duration index rows are filtered by start time
+				durationQuery := mockQuery(testCase.durationQueryError, int64(model.TimeAsEpochMicroseconds(time.Now().Add(-time.Minute))))
 
 				makeLoadQuery := func() *mocks.Query {
 					loadQueryIter := &mocks.Iterator{}
-					loadQueryIter.On("Scan", scanMatcher("loadIter")).Return(true)
+					loadQueryIter.On("Scan", scanMatcher("loadIter", 0)).Return(true)
 					loadQueryIter.On("Scan", matchEverything()).Return(false)
 					loadQueryIter.On("Close").Return(testCase.loadQueryError)
 
@@ -371,6 +444,7 @@ func TestSpanReaderFindTraces(t *testing.T) {
 				}
 
 				queryParams.NumTraces = testCase.numTraces
+				queryParams.PageToken = testCase.pageToken
 				if testCase.queryTags {
 					queryParams.Tags = make(map[string]string)
 					queryParams.Tags["x"] = "y"
@@ -383,7 +457,20 @@ func TestSpanReaderFindTraces(t *testing.T) {
 					queryParams.DurationMax = time.Minute * 3
 
 				}
+				if testCase.queryServices {
+					queryParams.ServiceNames = []string{"service-c"}
+					queryParams.OperationNames = []string{"operation-d"}
+				}
 				res, err := r.reader.FindTraces(context.Background(), queryParams)
+				if testCase.queryServices {
+					for _, service := range []string{"service-a", "service-c"} {
+						for _, operation := range []string{"operation-b", "operation-d"} {
+							r.session.AssertCalled(t, "Query", queryByServiceAndOperationName, mock.MatchedBy(func(values []interface{}) bool {
+								return len(values) > 1 && values[0] == service && values[1] == operation
+							}))
+						}
+					}
+				}
 				if testCase.expectedError == "" {
 					assert.NoError(t, err)
 					assert.Len(t, res, testCase.expectedCount, "expecting certain number of traces")
@@ -402,6 +489,187 @@ func TestSpanReaderFindTraces(t *testing.T) {
 	}
 }
 
+type indexRow struct {
+	traceID   uint64
+	startTime int64
+}
+
+// mockIndexQuery returns a query of the trace_id and start_time of the index rows.
+func mockIndexQuery(rows []indexRow) *mocks.Query {
+	scanFunc := func(args []interface{}) bool {
+		if len(rows) == 0 {
+			return false
+		}
+		*args[0].(*dbmodel.TraceID) = dbmodel.TraceIDFromDomain(model.NewTraceID(0, rows[0].traceID))
+		*args[1].(*int64) = rows[0].startTime
+		rows = rows[1:]
+		return true
+	}
+	iter := &mocks.Iterator{}
+	iter.On("Scan", mock.MatchedBy(scanFunc)).Return(true)
+	iter.On("Scan", matchEverything()).Return(false)
+	iter.On("Close").Return(nil)
+
+	query := &mocks.Query{}
+	query.On("PageSize", 0).Return(query)
+	query.On("Iter").Return(iter)
+	return query
+}
+
+func TestSpanReaderFindTraceIDsOrder(t *testing.T) {
+	testCases := []struct {
+		caption   string
+		rows      []indexRow
+		numTraces int
+		pageToken *spanstore.PageToken
+		expected  []model.TraceID
+		queries   int
+	}{
+		{
+			caption: "first page",
+			// the first trace has an older span after a newer one
+			rows:      []indexRow{{3, 300}, {4, 200}, {1, 100}, {2, 100}, {3, 50}},
+			numTraces: 2,
+			expected:  []model.TraceID{model.NewTraceID(0, 3), model.NewTraceID(0, 4)},
+			queries:   1,
+		},
+		{
+			caption:   "next page",
+			rows:      []indexRow{{3, 300}, {4, 200}, {1, 100}, {2, 100}, {3, 50}},
+			numTraces: 2,
+			pageToken: &spanstore.PageToken{StartTime: model.EpochMicrosecondsAsTime(200), TraceID: model.NewTraceID(0, 4)},
+			expected:  []model.TraceID{model.NewTraceID(0, 2), model.NewTraceID(0, 1)},
+			queries:   1,
+		},
+		{
+			caption:   "a trace using up the row limit",
+			rows:      []indexRow{{3, 300}, {3, 290}, {3, 280}, {3, 270}, {3, 260}, {3, 250}, {4, 200}, {1, 100}},
+			numTraces: 2,
+			expected:  []model.TraceID{model.NewTraceID(0, 3), model.NewTraceID(0, 4)},
+			queries:   2,
+		},
+		{
+			caption:   "ties beyond the row limit",
+			rows:      []indexRow{{4, 200}, {4, 200}, {4, 200}, {7, 200}, {1, 100}},
+			numTraces: 1,
+			expected:  []model.TraceID{model.NewTraceID(0, 7)},
+			queries:   2,
+		},
+		{
+			caption:   "ties at the token",
+			rows:      []indexRow{{4, 200}, {4, 200}, {4, 200}, {7, 200}, {1, 100}},
+			numTraces: 1,
+			pageToken: &spanstore.PageToken{StartTime: model.EpochMicrosecondsAsTime(200), TraceID: model.NewTraceID(0, 7)},
+			expected:  []model.TraceID{model.NewTraceID(0, 4)},
+			queries:   2,
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSpanReader(func(r *spanReaderTest) {
+				queries := 0
+				r.session.On("Query", stringMatcher(queryByServiceName), matchEverything()).Return(
+					func(stmt string, values ...interface{}) cassandra.Query {
+						queries++
+						// the index query returns the rows up to its limit, its last value
+						rows := testCase.rows
+						if limit := values[len(values)-1].(int); limit < len(rows) {
+							rows = rows[:limit]
+						}
+						return mockIndexQuery(rows)
+					})
+
+				queryParams := &spanstore.TraceQueryParameters{
+					ServiceName:  "service-a",
+					NumTraces:    testCase.numTraces,
+					StartTimeMin: model.EpochMicrosecondsAsTime(0),
+					StartTimeMax: model.EpochMicrosecondsAsTime(1000),
+				}
+				if testCase.pageToken != nil {
+					queryParams.PageToken = testCase.pageToken.String()
+				}
+				traceIDs, err := r.reader.FindTraceIDs(context.Background(), queryParams)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expected, traceIDs)
+				assert.Equal(t, testCase.queries, queries)
+			})
+		})
+	}
+}
+
+func TestSpanReaderFindTraceIDsByDuration(t *testing.T) {
+	end := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	micros := func(t time.Time) int64 {
+		return int64(model.TimeAsEpochMicroseconds(t))
+	}
+	// the rows of the duration index buckets, ordered by duration
+	buckets := map[time.Time][]indexRow{
+		end: {
+			{9, micros(end.Add(20 * time.Minute))}, // after the end of the query
+			{2, micros(end.Add(-20 * time.Minute))},
+			{1, micros(end.Add(-10 * time.Minute))},
+		},
+		end.Add(-time.Hour):     {{3, micros(end.Add(-time.Hour))}},
+		end.Add(-2 * time.Hour): {{4, micros(end.Add(-2 * time.Hour))}},
+	}
+	testCases := []struct {
+		caption   string
+		numTraces int
+		pageToken *spanstore.PageToken
+		expected  []model.TraceID
+		buckets   int
+	}{
+		{
+			caption:   "page in the latest bucket",
+			numTraces: 2,
+			expected:  []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 2)},
+			buckets:   1,
+		},
+		{
+			caption:   "page across buckets",
+			numTraces: 3,
+			expected:  []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 2), model.NewTraceID(0, 3)},
+			buckets:   2,
+		},
+		{
+			caption:   "next page",
+			numTraces: 2,
+			pageToken: &spanstore.PageToken{StartTime: end.Add(-20 * time.Minute), TraceID: model.NewTraceID(0, 2)},
+			expected:  []model.TraceID{model.NewTraceID(0, 3), model.NewTraceID(0, 4)},
+			buckets:   3,
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSpanReader(func(r *spanReaderTest) {
+				queried := 0
+				r.session.On("Query", stringMatcher(queryByDuration), matchEverything()).Return(
+					func(stmt string, values ...interface{}) cassandra.Query {
+						queried++
+						return mockIndexQuery(buckets[values[0].(time.Time)])
+					})
+
+				queryParams := &spanstore.TraceQueryParameters{
+					ServiceName:  "service-a",
+					NumTraces:    testCase.numTraces,
+					DurationMin:  time.Millisecond,
+					StartTimeMin: end.Add(-3 * time.Hour),
+					StartTimeMax: end,
+				}
+				if testCase.pageToken != nil {
+					queryParams.PageToken = testCase.pageToken.String()
+				}
+				traceIDs, err := r.reader.FindTraceIDs(context.Background(), queryParams)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expected, traceIDs)
+				assert.Equal(t, testCase.buckets, queried)
+			})
+		})
+	}
+}
+
 func TestTraceQueryParameterValidation(t *testing.T) {
 	tsp := &spanstore.TraceQueryParameters{
 		ServiceName: "",
@@ -433,4 +701,11 @@ func TestTraceQueryParameterValidation(t *testing.T) {
 	tsp.StartTimeMax = time.Time{}
 	err = validateQuery(tsp)
 	assert.EqualError(t, err, ErrStartAndEndTimeNotSet.Error())
+
+	tsp.StartTimeMin = time.Now().Add(-12 * time.Hour)
+	tsp.StartTimeMax = time.Now()
+	tsp.Tags = nil
+	tsp.PageToken = "?"
+	err = validateQuery(tsp)
+	assert.Equal(t, spanstore.ErrMalformedPageToken, err)
 }
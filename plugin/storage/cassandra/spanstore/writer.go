@@ -28,6 +28,7 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/cassandra"
 	casMetrics "github.com/jaegertracing/jaeger/pkg/cassandra/metrics"
+	"github.com/jaegertracing/jaeger/pkg/catalog"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
 )
 
@@ -94,6 +95,7 @@ type SpanWriter struct {
 	tagFilter            dbmodel.TagFilter
 	storageMode          storageMode
 	indexFilter          dbmodel.IndexFilter
+	catalog              *catalog.Tracker
 }
 
 // NewSpanWriter returns a SpanWriter
@@ -124,6 +126,7 @@ func NewSpanWriter(
 		tagFilter:       opts.tagFilter,
 		storageMode:     opts.storageMode,
 		indexFilter:     opts.indexFilter,
+		catalog:         opts.catalog,
 	}
 }
 
@@ -181,6 +184,9 @@ func (s *SpanWriter) writeIndexes(span *model.Span, ds *dbmodel.Span) error {
 		// should this be a soft failure?
 		return s.logError(ds, err, "Failed to insert service name and operation name", s.logger)
 	}
+	if s.catalog != nil {
+		s.catalog.Record(span)
+	}
 
 	if err := s.indexByTags(span, ds); err != nil {
 		return s.logError(ds, err, "Failed to index tags", s.logger)
@@ -16,6 +16,7 @@
 package spanstore
 
 import (
+	"github.com/jaegertracing/jaeger/pkg/catalog"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
 )
 
@@ -27,6 +28,7 @@ type Options struct {
 	tagFilter   dbmodel.TagFilter
 	storageMode storageMode
 	indexFilter dbmodel.IndexFilter
+	catalog     *catalog.Tracker
 }
 
 // TagFilter can be provided to filter any tags that should not be indexed.
@@ -57,6 +59,13 @@ func IndexFilter(indexFilter dbmodel.IndexFilter) Option {
 	}
 }
 
+// Catalog can be provided to track the activity of the services whose spans are indexed.
+func Catalog(tracker *catalog.Tracker) Option {
+	return func(o *Options) {
+		o.catalog = tracker
+	}
+}
+
 func applyOptions(opts ...Option) Options {
 	o := Options{}
 	for _, opt := range opts {
@@ -16,6 +16,7 @@
 package spanstore
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -24,11 +25,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics/metricstest"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/cassandra/mocks"
+	"github.com/jaegertracing/jaeger/pkg/catalog"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -326,6 +329,36 @@ func TestStorageMode_IndexOnly(t *testing.T) {
 	}, StoreIndexesOnly())
 }
 
+func TestSpanWriterCatalog(t *testing.T) {
+	tracker := catalog.NewTracker()
+	withSpanWriter(0, func(w *spanWriterTest) {
+		w.writer.serviceNamesWriter = func(serviceName string) error { return nil }
+		w.writer.operationNamesWriter = func(operation dbmodel.Operation) error { return nil }
+		span := &model.Span{
+			TraceID:       model.NewTraceID(0, 1),
+			OperationName: "operation-a",
+			Process: &model.Process{
+				ServiceName: "service-a",
+				Tags:        model.KeyValues{model.String(catalog.HostnameTag, "host-a")},
+			},
+		}
+
+		query := &mocks.Query{}
+		query.On("Bind", matchEverything()).Return(query)
+		query.On("Exec").Return(nil)
+		w.session.On("Query", mock.Anything, matchEverything()).Return(query)
+
+		require.NoError(t, w.writer.WriteSpan(span))
+
+		services, err := tracker.GetServiceCatalog(context.Background())
+		require.NoError(t, err)
+		require.Len(t, services, 1)
+		assert.Equal(t, "service-a", services[0].ServiceName)
+		assert.Equal(t, 1, services[0].OperationCount)
+		assert.Equal(t, []string{"host-a"}, services[0].Hostnames)
+	}, StoreIndexesOnly(), Catalog(tracker))
+}
+
 var filterEverything = func(*dbmodel.Span, int) bool {
 	return false
 }
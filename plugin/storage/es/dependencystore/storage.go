@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/olivere/elastic"
@@ -69,19 +70,36 @@ func (s *DependencyStore) WriteDependencies(ts time.Time, dependencies []model.D
 
 func (s *DependencyStore) createIndex(indexName string) error {
 	_, err := s.client.CreateIndex(indexName).Body(getMapping(s.client.GetVersion())).Do(s.ctx)
-	if err != nil {
+	if err != nil && !isIndexExistsError(err) {
 		return fmt.Errorf("failed to create index: %w", err)
 	}
 	return nil
 }
 
+// isIndexExistsError returns true if the index was not created because it already exists,
+// e.g. when the collector writes the dependencies several times a day.
+func isIndexExistsError(err error) bool {
+	var esErr *elastic.Error
+	if !errors.As(err, &esErr) || esErr.Details == nil {
+		return false
+	}
+	return esErr.Details.Type == "resource_already_exists_exception" || esErr.Details.Type == "index_already_exists_exception"
+}
+
+// writeDependencies uses the timestamp as document ID, so that writing the dependencies
+// of the same timestamp again replaces them.
 func (s *DependencyStore) writeDependencies(indexName string, ts time.Time, dependencies []model.DependencyLink) {
-	s.client.Index().Index(indexName).Type(dependencyType).
+	s.client.Index().Index(indexName).Type(dependencyType).Id(strconv.FormatInt(ts.UnixNano(), 10)).
 		BodyJson(&dbmodel.TimeDependencies{Timestamp: ts,
 			Dependencies: dbmodel.FromDomainDependencies(dependencies),
 		}).Add()
 }
 
+// Close flushes the pending writes and closes the client.
+func (s *DependencyStore) Close() error {
+	return s.client.Close()
+}
+
 // GetDependencies returns all interservice dependencies
 func (s *DependencyStore) GetDependencies(endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
 	indices := getIndices(s.indexPrefix, endTs, lookback)
@@ -18,6 +18,7 @@ package dependencystore
 import (
 	"encoding/json"
 	"errors"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -88,6 +89,10 @@ func TestWriteDependencies(t *testing.T) {
 			expectedError:    "failed to create index: index not created",
 			esVersion:        7,
 		},
+		{
+			createIndexError: &elastic.Error{Status: 400, Details: &elastic.ErrorDetails{Type: "resource_already_exists_exception"}},
+			esVersion:        7,
+		},
 	}
 	for _, testCase := range testCases {
 		withDepStorage("", func(r *depStorageTest) {
@@ -109,6 +114,7 @@ func TestWriteDependencies(t *testing.T) {
 
 			writeService.On("Index", stringMatcher(indexName)).Return(writeService)
 			writeService.On("Type", stringMatcher(dependencyType)).Return(writeService)
+			writeService.On("Id", stringMatcher(strconv.FormatInt(fixedTime.UnixNano(), 10))).Return(writeService)
 			writeService.On("BodyJson", mock.Anything).Return(writeService)
 			writeService.On("Add", mock.Anything).Return(nil, testCase.writeError)
 
@@ -123,6 +129,13 @@ func TestWriteDependencies(t *testing.T) {
 	}
 }
 
+func TestClose(t *testing.T) {
+	withDepStorage("", func(r *depStorageTest) {
+		r.client.On("Close").Return(errors.New("close error"))
+		assert.EqualError(t, r.storage.Close(), "close error")
+	})
+}
+
 func TestGetDependencies(t *testing.T) {
 	goodDependencies :=
 		`{
@@ -28,6 +28,7 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/catalog"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/pkg/es/config"
 	esDepStore "github.com/jaegertracing/jaeger/plugin/storage/es/dependencystore"
@@ -53,12 +54,16 @@ type Factory struct {
 	primaryClient es.Client
 	archiveConfig config.ClientBuilder
 	archiveClient es.Client
+
+	// catalog tracks the activity of the services whose spans are written to the primary storage
+	catalog *catalog.Tracker
 }
 
 // NewFactory creates a new Factory.
 func NewFactory() *Factory {
 	return &Factory{
 		Options: NewOptions(primaryNamespace, archiveNamespace),
+		catalog: catalog.NewTracker(),
 	}
 }
 
@@ -99,7 +104,7 @@ func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	return createSpanWriter(f.metricsFactory, f.logger, f.primaryClient, f.primaryConfig, false)
+	return createSpanWriter(f.metricsFactory, f.logger, f.primaryClient, f.primaryConfig, false, f.catalog)
 }
 
 // CreateDependencyReader implements storage.Factory
@@ -107,6 +112,11 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return esDepStore.NewDependencyStore(f.primaryClient, f.logger, f.primaryConfig.GetIndexPrefix()), nil
 }
 
+// CreateDependencyWriter implements storage.DependencyWriterFactory
+func (f *Factory) CreateDependencyWriter() (dependencystore.Writer, error) {
+	return esDepStore.NewDependencyStore(f.primaryClient, f.logger, f.primaryConfig.GetIndexPrefix()), nil
+}
+
 func loadTagsFromFile(filePath string) ([]string, error) {
 	file, err := os.Open(filepath.Clean(filePath))
 	if err != nil {
@@ -125,6 +135,11 @@ func loadTagsFromFile(filePath string) ([]string, error) {
 	return tags, nil
 }
 
+// CreateCatalogReader implements storage.CatalogFactory
+func (f *Factory) CreateCatalogReader() (spanstore.CatalogReader, error) {
+	return f.catalog, nil
+}
+
 // CreateArchiveSpanReader implements storage.ArchiveFactory
 func (f *Factory) CreateArchiveSpanReader() (spanstore.Reader, error) {
 	if !f.archiveConfig.IsEnabled() {
@@ -138,7 +153,7 @@ func (f *Factory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
 	if !f.archiveConfig.IsEnabled() {
 		return nil, nil
 	}
-	return createSpanWriter(f.metricsFactory, f.logger, f.archiveClient, f.archiveConfig, true)
+	return createSpanWriter(f.metricsFactory, f.logger, f.archiveClient, f.archiveConfig, true, nil)
 }
 
 func createSpanReader(
@@ -167,6 +182,7 @@ func createSpanWriter(
 	client es.Client,
 	cfg config.ClientBuilder,
 	archive bool,
+	tracker *catalog.Tracker,
 ) (spanstore.Writer, error) {
 	var tags []string
 	if cfg.GetTagsFilePath() != "" {
@@ -188,6 +204,7 @@ func createSpanWriter(
 		TagDotReplacement:   cfg.GetTagDotReplacement(),
 		Archive:             archive,
 		UseReadWriteAliases: cfg.GetUseReadWriteAliases(),
+		Catalog:             tracker,
 	})
 	if cfg.IsCreateIndexTemplates() {
 		err := writer.CreateTemplates(spanMapping, serviceMapping)
@@ -60,6 +60,9 @@ const (
 
 	defaultDocCount  = 10000 // the default elasticsearch allowed limit
 	defaultNumTraces = 100
+
+	// traceIDSearchBatchSize is the number of matching spans fetched per request when searching the trace IDs after a page token
+	traceIDSearchBatchSize = 1000
 )
 
 var (
@@ -205,6 +208,23 @@ func (s *SpanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*mode
 	return traces[0], nil
 }
 
+// StreamTrace implements spanstore.TraceStreamer, passing fn the spans of the trace by the pages of the
+// searches that read them, so that the rest of the trace is left unread once fn returns false.
+func (s *SpanReader) StreamTrace(ctx context.Context, traceID model.TraceID, fn func(part *model.Trace) bool) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "StreamTrace")
+	defer span.Finish()
+	currentTime := time.Now()
+	found := false
+	err := s.readTraces(ctx, []model.TraceID{traceID}, currentTime.Add(-s.maxSpanAge), currentTime, func(spans []*model.Span) bool {
+		found = true
+		return fn(&model.Trace{Spans: spans})
+	})
+	if err == nil && !found {
+		return spanstore.ErrTraceNotFound
+	}
+	return err
+}
+
 func (s *SpanReader) collectSpans(esSpansRaw []*elastic.SearchHit) ([]*model.Span, error) {
 	spans := make([]*model.Span, len(esSpansRaw))
 
@@ -314,15 +334,38 @@ func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 }
 
 func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, startTime, endTime time.Time) ([]*model.Trace, error) {
+	if len(traceIDs) == 0 {
+		return []*model.Trace{}, nil
+	}
+	tracesMap := make(map[model.TraceID]*model.Trace)
+	err := s.readTraces(ctx, traceIDs, startTime, endTime, func(spans []*model.Span) bool {
+		lastSpan := spans[len(spans)-1]
+		if traceSpan, ok := tracesMap[lastSpan.TraceID]; ok {
+			traceSpan.Spans = append(traceSpan.Spans, spans...)
+		} else {
+			tracesMap[lastSpan.TraceID] = &model.Trace{Spans: spans}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var traces []*model.Trace
+	for _, trace := range tracesMap {
+		traces = append(traces, trace)
+	}
+	return traces, nil
+}
+
+// readTraces passes fn the spans of the traces by the pages of the searches that read them, each page
+// holding spans of a single trace. The rest of a trace is left unread once fn returns false for its spans.
+func (s *SpanReader) readTraces(ctx context.Context, traceIDs []model.TraceID, startTime, endTime time.Time, fn func(spans []*model.Span) bool) error {
 
 	childSpan, _ := opentracing.StartSpanFromContext(ctx, "multiRead")
 	childSpan.LogFields(otlog.Object("trace_ids", traceIDs))
 	defer childSpan.Finish()
 
-	if len(traceIDs) == 0 {
-		return []*model.Trace{}, nil
-	}
-
 	// Add an hour in both directions so that traces that straddle two indexes are retrieved.
 	// i.e starts in one and ends in another.
 	indices := s.timeRangeIndices(s.spanIndexPrefix, startTime.Add(-time.Hour), endTime.Add(time.Hour))
@@ -330,7 +373,6 @@ func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, st
 
 	searchAfterTime := make(map[model.TraceID]uint64)
 	totalDocumentsFetched := make(map[model.TraceID]int)
-	tracesMap := make(map[model.TraceID]*model.Trace)
 	for {
 		if len(traceIDs) == 0 {
 			break
@@ -354,7 +396,7 @@ func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, st
 
 		if err != nil {
 			logErrorToSpan(childSpan, err)
-			return nil, err
+			return err
 		}
 
 		if results.Responses == nil || len(results.Responses) == 0 {
@@ -368,14 +410,11 @@ func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, st
 			spans, err := s.collectSpans(result.Hits.Hits)
 			if err != nil {
 				logErrorToSpan(childSpan, err)
-				return nil, err
+				return err
 			}
 			lastSpan := spans[len(spans)-1]
-
-			if traceSpan, ok := tracesMap[lastSpan.TraceID]; ok {
-				traceSpan.Spans = append(traceSpan.Spans, spans...)
-			} else {
-				tracesMap[lastSpan.TraceID] = &model.Trace{Spans: spans}
+			if !fn(spans) {
+				continue
 			}
 
 			totalDocumentsFetched[lastSpan.TraceID] = totalDocumentsFetched[lastSpan.TraceID] + len(result.Hits.Hits)
@@ -385,12 +424,7 @@ func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, st
 			}
 		}
 	}
-
-	var traces []*model.Trace
-	for _, trace := range tracesMap {
-		traces = append(traces, trace)
-	}
-	return traces, nil
+	return nil
 }
 
 func buildTraceByIDQuery(traceID model.TraceID) elastic.Query {
@@ -438,7 +472,7 @@ func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if p == nil {
 		return ErrMalformedRequestObject
 	}
-	if p.ServiceName == "" && len(p.Tags) > 0 {
+	if len(p.Services()) == 0 && len(p.Tags) > 0 {
 		return ErrServiceNameNotSet
 	}
 	if p.StartTimeMin.IsZero() || p.StartTimeMax.IsZero() {
@@ -450,6 +484,9 @@ func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if p.DurationMin != 0 && p.DurationMax != 0 && p.DurationMin > p.DurationMax {
 		return ErrDurationMinGreaterThanMax
 	}
+	if _, err := spanstore.ParsePageToken(p.PageToken); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -509,6 +546,10 @@ func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	//      },
 	//      "aggs": { "traceIDs" : { "terms" : {"size": 100,"field": "traceID" }}}
 	//  }
+	// the token was validated by validateQuery
+	if pageToken, _ := spanstore.ParsePageToken(traceQuery.PageToken); pageToken != nil {
+		return s.findTraceIDsAfter(ctx, traceQuery, pageToken)
+	}
 	aggregation := s.buildTraceIDAggregation(traceQuery.NumTraces)
 	boolQuery := s.buildFindTraceIDsQuery(traceQuery)
 	jaegerIndices := s.timeRangeIndices(s.spanIndexPrefix, traceQuery.StartTimeMin, traceQuery.StartTimeMax)
@@ -535,11 +576,92 @@ func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	return bucketToStringArray(traceIDBuckets)
 }
 
+// findTraceIDsAfter returns the IDs of the traces that follow the page token, in the order of spanstore.PageToken.
+// The matching spans are searched newest first, then by descending trace ID, with search_after starting at
+// the token, so the first span found for a trace is its latest matching span, i.e. its sort key.
+// Trace IDs are compared by their padded form, see traceIDSortScript.
+func (s *SpanReader) findTraceIDsAfter(
+	ctx context.Context,
+	traceQuery *spanstore.TraceQueryParameters,
+	pageToken *spanstore.PageToken,
+) ([]string, error) {
+	childSpan, _ := opentracing.StartSpanFromContext(ctx, "findTraceIDsAfter")
+	defer childSpan.Finish()
+
+	boolQuery := s.buildFindTraceIDsQuery(traceQuery)
+	startTimeMax := traceQuery.StartTimeMax
+	if pageToken.StartTime.Before(startTimeMax) {
+		startTimeMax = pageToken.StartTime
+	}
+	jaegerIndices := s.timeRangeIndices(s.spanIndexPrefix, traceQuery.StartTimeMin, startTimeMax)
+
+	searchAfter := []interface{}{model.TimeAsEpochMicroseconds(pageToken.StartTime), paddedTraceID(pageToken.TraceID)}
+	seen := make(map[string]struct{})
+	traceIDs := []string{}
+	for len(traceIDs) < traceQuery.NumTraces {
+		source := elastic.NewSearchSource().
+			Query(boolQuery).
+			Size(traceIDSearchBatchSize).
+			Sort(startTimeField, false).
+			SortBy(elastic.NewScriptSort(elastic.NewScript(traceIDSortScript), "string").Desc()).
+			SearchAfter(searchAfter...).
+			FetchSource(false)
+		results, err := s.client.MultiSearch().
+			Add(elastic.NewSearchRequest().IgnoreUnavailable(true).Source(source)).
+			Index(jaegerIndices...).
+			Do(ctx)
+		if err != nil {
+			logErrorToSpan(childSpan, err)
+			return nil, fmt.Errorf("search trace IDs failed: %w", err)
+		}
+		if len(results.Responses) == 0 || results.Responses[0].Hits == nil {
+			break
+		}
+		hits := results.Responses[0].Hits.Hits
+		for _, hit := range hits {
+			if len(hit.Sort) != 2 {
+				return nil, fmt.Errorf("unexpected sort values %v of span %s", hit.Sort, hit.Id)
+			}
+			traceID, ok := hit.Sort[1].(string)
+			if !ok {
+				return nil, fmt.Errorf("non-string trace ID %v of span %s", hit.Sort[1], hit.Id)
+			}
+			if _, ok := seen[traceID]; ok {
+				continue
+			}
+			seen[traceID] = struct{}{}
+			traceIDs = append(traceIDs, traceID)
+			if len(traceIDs) == traceQuery.NumTraces {
+				break
+			}
+		}
+		if len(hits) < traceIDSearchBatchSize {
+			break
+		}
+		searchAfter = hits[len(hits)-1].Sort
+	}
+	return traceIDs, nil
+}
+
+// traceIDSortScript returns the trace ID left-padded with zeros to 32 hex digits, so that the trace IDs
+// of 64 and 128 bits, and those saved without leading zeros by older versions, sort as strings in the
+// numeric order of spanstore.PageToken.
+const traceIDSortScript = "String id = doc['" + traceIDField + "'].value; " +
+	"return id.length() >= 32 ? id : '00000000000000000000000000000000'.substring(id.length()) + id;"
+
+// paddedTraceID returns the trace ID in the form of traceIDSortScript.
+func paddedTraceID(traceID model.TraceID) string {
+	return fmt.Sprintf("%016x%016x", traceID.High, traceID.Low)
+}
+
+// buildTraceIDAggregation orders the trace IDs like spanstore.PageToken, by their latest matching span,
+// then by descending trace ID. The buckets are keyed by the padded trace IDs, see traceIDSortScript.
 func (s *SpanReader) buildTraceIDAggregation(numOfTraces int) elastic.Aggregation {
 	return elastic.NewTermsAggregation().
 		Size(numOfTraces).
-		Field(traceIDField).
+		Script(elastic.NewScript(traceIDSortScript)).
 		Order(startTimeField, false).
+		OrderByTerm(false).
 		SubAggregation(startTimeField, s.buildTraceIDSubAggregation())
 }
 
@@ -562,21 +684,23 @@ func (s *SpanReader) buildFindTraceIDsQuery(traceQuery *spanstore.TraceQueryPara
 	boolQuery.Must(startTimeQuery)
 
 	//add process.serviceName query
-	if traceQuery.ServiceName != "" {
-		serviceNameQuery := s.buildServiceNameQuery(traceQuery.ServiceName)
-		boolQuery.Must(serviceNameQuery)
+	if services := traceQuery.Services(); len(services) > 0 {
+		boolQuery.Must(s.buildAnyOfQuery(services, s.buildServiceNameQuery))
 	}
 
 	//add operationName query
-	if traceQuery.OperationName != "" {
-		operationNameQuery := s.buildOperationNameQuery(traceQuery.OperationName)
-		boolQuery.Must(operationNameQuery)
+	if operations := traceQuery.Operations(); len(operations) > 0 {
+		boolQuery.Must(s.buildAnyOfQuery(operations, s.buildOperationNameQuery))
 	}
 
 	for k, v := range traceQuery.Tags {
 		tagQuery := s.buildTagQuery(k, v)
 		boolQuery.Must(tagQuery)
 	}
+
+	for _, group := range traceQuery.TagPredicates {
+		boolQuery.Must(s.buildTagPredicateGroupQuery(group))
+	}
 	return boolQuery
 }
 
@@ -603,6 +727,18 @@ func (s *SpanReader) buildOperationNameQuery(operationName string) elastic.Query
 	return elastic.NewMatchQuery(operationNameField, operationName)
 }
 
+// buildAnyOfQuery matches any of the values, with a single value query if there is only one
+func (s *SpanReader) buildAnyOfQuery(values []string, buildQuery func(string) elastic.Query) elastic.Query {
+	if len(values) == 1 {
+		return buildQuery(values[0])
+	}
+	queries := make([]elastic.Query, len(values))
+	for i, value := range values {
+		queries[i] = buildQuery(value)
+	}
+	return elastic.NewBoolQuery().Should(queries...)
+}
+
 func (s *SpanReader) buildTagQuery(k string, v string) elastic.Query {
 	objectTagListLen := len(objectTagFieldList)
 	queries := make([]elastic.Query, len(nestedTagFieldList)+objectTagListLen)
@@ -633,6 +769,65 @@ func (s *SpanReader) buildObjectQuery(field string, k string, v string) elastic.
 	return elastic.NewBoolQuery().Must(keyQuery)
 }
 
+// SupportsTagPredicate implements spanstore.TagPredicateSupport. Tag values are indexed as keywords,
+// so numeric comparisons and regular expressions with Go syntax are left to the query service.
+func (s *SpanReader) SupportsTagPredicate(predicate spanstore.TagPredicate) bool {
+	switch predicate.Operator {
+	case spanstore.TagOpEqual, spanstore.TagOpNotEqual, spanstore.TagOpExists, spanstore.TagOpNotExists, spanstore.TagOpPrefix:
+		return true
+	}
+	return false
+}
+
+func (s *SpanReader) buildTagPredicateGroupQuery(group spanstore.TagPredicateGroup) elastic.Query {
+	queries := make([]elastic.Query, len(group))
+	for i, predicate := range group {
+		queries[i] = s.buildTagPredicateQuery(predicate)
+	}
+	return elastic.NewBoolQuery().Should(queries...)
+}
+
+func (s *SpanReader) buildTagPredicateQuery(predicate spanstore.TagPredicate) elastic.Query {
+	switch predicate.Operator {
+	case spanstore.TagOpNotEqual:
+		return elastic.NewBoolQuery().MustNot(s.buildTagQuery(predicate.Key, predicate.Value))
+	case spanstore.TagOpExists:
+		return s.buildTagExistsQuery(predicate.Key)
+	case spanstore.TagOpNotExists:
+		return elastic.NewBoolQuery().MustNot(s.buildTagExistsQuery(predicate.Key))
+	case spanstore.TagOpPrefix:
+		return s.buildTagPrefixQuery(predicate.Key, predicate.Value)
+	}
+	return s.buildTagQuery(predicate.Key, predicate.Value)
+}
+
+func (s *SpanReader) buildTagExistsQuery(k string) elastic.Query {
+	var queries []elastic.Query
+	kd := s.spanConverter.ReplaceDot(k)
+	for _, field := range objectTagFieldList {
+		queries = append(queries, elastic.NewExistsQuery(fmt.Sprintf("%s.%s", field, kd)))
+	}
+	for _, field := range nestedTagFieldList {
+		keyQuery := elastic.NewMatchQuery(fmt.Sprintf("%s.%s", field, tagKeyField), k)
+		queries = append(queries, elastic.NewNestedQuery(field, elastic.NewBoolQuery().Must(keyQuery)))
+	}
+	return elastic.NewBoolQuery().Should(queries...)
+}
+
+func (s *SpanReader) buildTagPrefixQuery(k string, prefix string) elastic.Query {
+	var queries []elastic.Query
+	kd := s.spanConverter.ReplaceDot(k)
+	for _, field := range objectTagFieldList {
+		queries = append(queries, elastic.NewPrefixQuery(fmt.Sprintf("%s.%s", field, kd), prefix))
+	}
+	for _, field := range nestedTagFieldList {
+		keyQuery := elastic.NewMatchQuery(fmt.Sprintf("%s.%s", field, tagKeyField), k)
+		valueQuery := elastic.NewPrefixQuery(fmt.Sprintf("%s.%s", field, tagValueField), prefix)
+		queries = append(queries, elastic.NewNestedQuery(field, elastic.NewBoolQuery().Must(keyQuery, valueQuery)))
+	}
+	return elastic.NewBoolQuery().Should(queries...)
+}
+
 func logErrorToSpan(span opentracing.Span, err error) {
 	ottag.Error.Set(span, true)
 	span.LogFields(otlog.Error(err))
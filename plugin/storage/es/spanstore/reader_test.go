@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
@@ -316,6 +317,69 @@ func TestSpanReader_SearchAfter(t *testing.T) {
 	})
 }
 
+func TestSpanReader_StreamTrace(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		hits := []*elastic.SearchHit{
+			{Source: (*json.RawMessage)(&exampleESSpan)},
+			{Source: (*json.RawMessage)(&exampleESSpan)},
+		}
+		// the trace has more spans than the first search reads
+		searchHits := &elastic.SearchHits{Hits: hits, TotalHits: 4}
+		mockMultiSearchService(r).
+			Return(&elastic.MultiSearchResult{
+				Responses: []*elastic.SearchResult{
+					{Hits: searchHits},
+				},
+			}, nil).Times(2)
+
+		var parts []int
+		err := r.reader.StreamTrace(context.Background(), model.NewTraceID(0, 1), func(part *model.Trace) bool {
+			parts = append(parts, len(part.Spans))
+			return true
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 2}, parts)
+	})
+
+	// the rest of the trace is left unread
+	withSpanReader(func(r *spanReaderTest) {
+		searchHits := &elastic.SearchHits{Hits: []*elastic.SearchHit{
+			{Source: (*json.RawMessage)(&exampleESSpan)},
+			{Source: (*json.RawMessage)(&exampleESSpan)},
+		}, TotalHits: 4}
+		mockMultiSearchService(r).
+			Return(&elastic.MultiSearchResult{
+				Responses: []*elastic.SearchResult{
+					{Hits: searchHits},
+				},
+			}, nil).Once()
+		var parts []int
+		err := r.reader.StreamTrace(context.Background(), model.NewTraceID(0, 1), func(part *model.Trace) bool {
+			parts = append(parts, len(part.Spans))
+			return false
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{2}, parts)
+	})
+}
+
+func TestSpanReader_StreamTraceNotFound(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		mockMultiSearchService(r).
+			Return(&elastic.MultiSearchResult{
+				Responses: []*elastic.SearchResult{
+					{Hits: nil},
+				},
+			}, nil)
+
+		err := r.reader.StreamTrace(context.Background(), model.NewTraceID(0, 1), func(part *model.Trace) bool {
+			assert.Fail(t, "no part expected")
+			return true
+		})
+		assert.Equal(t, spanstore.ErrTraceNotFound, err)
+	})
+}
+
 func TestSpanReader_GetTraceQueryError(t *testing.T) {
 	withSpanReader(func(r *spanReaderTest) {
 		mockSearchService(r).
@@ -887,29 +951,32 @@ func TestTraceQueryParameterValidation(t *testing.T) {
 	tqp.DurationMax = time.Minute
 	err = validateQuery(tqp)
 	assert.EqualError(t, err, ErrDurationMinGreaterThanMax.Error())
+
+	tqp.DurationMin = 0
+	tqp.PageToken = "?"
+	err = validateQuery(tqp)
+	assert.Equal(t, spanstore.ErrMalformedPageToken, err)
 }
 
 func TestSpanReader_buildTraceIDAggregation(t *testing.T) {
 	expectedStr := `{ "terms":{
-            "field":"traceID",
+            "script":{"source":` + strconv.Quote(traceIDSortScript) + `},
             "size":123,
-            "order":{
-               "startTime":"desc"
-            }
+            "order":[
+               {"startTime":"desc"},
+               {"_term":"desc"}
+            ]
          },
          "aggregations": {
             "startTime" : { "max": {"field": "startTime"}}
          }}`
 	withSpanReader(func(r *spanReaderTest) {
 		traceIDAggregation := r.reader.buildTraceIDAggregation(123)
-		actual, err := traceIDAggregation.Source()
+		source, err := traceIDAggregation.Source()
 		require.NoError(t, err)
-
-		expected := make(map[string]interface{})
-		json.Unmarshal([]byte(expectedStr), &expected)
-		expected["terms"].(map[string]interface{})["size"] = 123
-		expected["terms"].(map[string]interface{})["order"] = []interface{}{map[string]string{"startTime": "desc"}}
-		assert.EqualValues(t, expected, actual)
+		actual, err := json.Marshal(source)
+		require.NoError(t, err)
+		assert.JSONEq(t, expectedStr, string(actual))
 	})
 }
 
@@ -944,6 +1011,132 @@ func TestSpanReader_buildFindTraceIDsQuery(t *testing.T) {
 	})
 }
 
+func TestSpanReader_buildFindTraceIDsQueryWithMultipleServices(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		traceQuery := &spanstore.TraceQueryParameters{
+			StartTimeMin:   time.Time{},
+			StartTimeMax:   time.Time{}.Add(time.Second),
+			ServiceName:    "s1",
+			ServiceNames:   []string{"s1", "s2"},
+			OperationNames: []string{"o"},
+		}
+
+		actualQuery := r.reader.buildFindTraceIDsQuery(traceQuery)
+		actual, err := actualQuery.Source()
+		require.NoError(t, err)
+		expectedQuery := elastic.NewBoolQuery().
+			Must(
+				r.reader.buildStartTimeQuery(time.Time{}, time.Time{}.Add(time.Second)),
+				elastic.NewBoolQuery().Should(
+					r.reader.buildServiceNameQuery("s1"),
+					r.reader.buildServiceNameQuery("s2"),
+				),
+				r.reader.buildOperationNameQuery("o"),
+			)
+		expected, err := expectedQuery.Source()
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+}
+
+func TestSpanReader_FindTraceIDsWithPageToken(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		pageToken := spanstore.PageToken{
+			StartTime: time.Unix(50, 0),
+			TraceID:   model.NewTraceID(0, 9),
+		}
+		traceQuery := &spanstore.TraceQueryParameters{
+			ServiceName:  "svc",
+			StartTimeMin: time.Unix(0, 0),
+			StartTimeMax: time.Unix(100, 0),
+			PageToken:    pageToken.String(),
+			NumTraces:    2,
+		}
+		hit := func(micros float64, traceID string) *elastic.SearchHit {
+			return &elastic.SearchHit{Sort: []interface{}{micros, traceID}}
+		}
+		firstBatch := make([]*elastic.SearchHit, traceIDSearchBatchSize)
+		for i := range firstBatch {
+			// many spans of the same trace
+			firstBatch[i] = hit(50000000, "00000000000000000000000000000008")
+		}
+		secondBatch := []*elastic.SearchHit{
+			hit(40000000, "00000000000000000000000000000008"),
+			hit(40000000, "00000000000000000000000000000007"),
+			hit(30000000, "00000000000000000000000000000006"),
+		}
+
+		var searchAfter [][]interface{}
+		multiSearchService := &mocks.MultiSearchService{}
+		multiSearchService.On("Add", mock.MatchedBy(func(request *elastic.SearchRequest) bool {
+			body, err := request.Body()
+			require.NoError(t, err)
+			var source map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(body), &source))
+			searchAfter = append(searchAfter, source["search_after"].([]interface{}))
+			return true
+		})).Return(multiSearchService)
+		multiSearchService.On("Index", mock.Anything).Return(multiSearchService)
+		r.client.On("MultiSearch").Return(multiSearchService)
+		multiSearchService.On("Do", mock.Anything).Return(&elastic.MultiSearchResult{
+			Responses: []*elastic.SearchResult{{Hits: &elastic.SearchHits{Hits: firstBatch}}},
+		}, nil).Once()
+		multiSearchService.On("Do", mock.Anything).Return(&elastic.MultiSearchResult{
+			Responses: []*elastic.SearchResult{{Hits: &elastic.SearchHits{Hits: secondBatch}}},
+		}, nil).Once()
+
+		traceIDs, err := r.reader.FindTraceIDs(context.Background(), traceQuery)
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{model.NewTraceID(0, 8), model.NewTraceID(0, 7)}, traceIDs)
+		assert.Equal(t, [][]interface{}{
+			{float64(50000000), "00000000000000000000000000000009"},
+			{float64(50000000), "00000000000000000000000000000008"},
+		}, searchAfter)
+	})
+}
+
+func TestPaddedTraceIDOrder(t *testing.T) {
+	// in descending order
+	traceIDs := []model.TraceID{
+		model.NewTraceID(1, 0),
+		model.NewTraceID(0, 0xffffffffffffffff),
+		model.NewTraceID(0, 0x10),
+		model.NewTraceID(0, 0x9),
+	}
+	for i := 1; i < len(traceIDs); i++ {
+		assert.Greater(t, paddedTraceID(traceIDs[i-1]), paddedTraceID(traceIDs[i]))
+	}
+	parsed, err := model.TraceIDFromString(paddedTraceID(traceIDs[2]))
+	require.NoError(t, err)
+	assert.Equal(t, traceIDs[2], parsed)
+}
+
+func TestSpanReader_FindTraceIDsWithPageTokenError(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		traceQuery := &spanstore.TraceQueryParameters{
+			ServiceName:  "svc",
+			StartTimeMin: time.Unix(0, 0),
+			StartTimeMax: time.Unix(100, 0),
+			PageToken:    spanstore.PageToken{StartTime: time.Unix(50, 0), TraceID: model.NewTraceID(0, 9)}.String(),
+		}
+		multiSearchService := &mocks.MultiSearchService{}
+		multiSearchService.On("Add", mock.Anything).Return(multiSearchService)
+		multiSearchService.On("Index", mock.Anything).Return(multiSearchService)
+		r.client.On("MultiSearch").Return(multiSearchService)
+		call := multiSearchService.On("Do", mock.Anything)
+
+		call.Return(nil, errors.New("search failure")).Once()
+		_, err := r.reader.FindTraceIDs(context.Background(), traceQuery)
+		assert.EqualError(t, err, "search trace IDs failed: search failure")
+
+		call.Return(&elastic.MultiSearchResult{Responses: []*elastic.SearchResult{{Hits: &elastic.SearchHits{
+			Hits: []*elastic.SearchHit{{Id: "span", Sort: []interface{}{float64(1)}}},
+		}}}}, nil).Once()
+		_, err = r.reader.FindTraceIDs(context.Background(), traceQuery)
+		assert.EqualError(t, err, "unexpected sort values [1] of span span")
+	})
+}
+
 func TestSpanReader_buildDurationQuery(t *testing.T) {
 	expectedStr :=
 		`{ "range":
@@ -1039,6 +1232,75 @@ func TestSpanReader_buildTagQuery(t *testing.T) {
 	})
 }
 
+func TestSpanReader_buildTagPrefixQuery(t *testing.T) {
+	inStr, err := ioutil.ReadFile("fixtures/query_02.json")
+	require.NoError(t, err)
+	withSpanReader(func(r *spanReaderTest) {
+		tagQuery := r.reader.buildTagPrefixQuery("bat.foo", "spo")
+		actual, err := tagQuery.Source()
+		require.NoError(t, err)
+
+		expected := make(map[string]interface{})
+		json.Unmarshal(inStr, &expected)
+
+		assert.EqualValues(t, expected, actual)
+	})
+}
+
+func TestSpanReader_buildTagPredicateGroupQuery(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		group := spanstore.TagPredicateGroup{
+			{Key: "error", Operator: spanstore.TagOpExists},
+			{Key: "http.method", Operator: spanstore.TagOpNotEqual, Value: "GET"},
+			{Key: "sampler.type", Operator: spanstore.TagOpNotExists},
+			{Key: "http.url", Operator: spanstore.TagOpPrefix, Value: "/api"},
+			{Key: "peer.service", Operator: spanstore.TagOpEqual, Value: "redis"},
+		}
+		actualQuery := r.reader.buildTagPredicateGroupQuery(group)
+		actual, err := actualQuery.Source()
+		require.NoError(t, err)
+
+		expectedQuery := elastic.NewBoolQuery().Should(
+			r.reader.buildTagExistsQuery("error"),
+			elastic.NewBoolQuery().MustNot(r.reader.buildTagQuery("http.method", "GET")),
+			elastic.NewBoolQuery().MustNot(r.reader.buildTagExistsQuery("sampler.type")),
+			r.reader.buildTagPrefixQuery("http.url", "/api"),
+			r.reader.buildTagQuery("peer.service", "redis"),
+		)
+		expected, err := expectedQuery.Source()
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+
+		existsQuery, err := r.reader.buildTagExistsQuery("bat.foo").Source()
+		require.NoError(t, err)
+		expected, err = elastic.NewBoolQuery().Should(
+			elastic.NewExistsQuery("tag.bat@foo"),
+			elastic.NewExistsQuery("process.tag.bat@foo"),
+			elastic.NewNestedQuery("tags", elastic.NewBoolQuery().Must(elastic.NewMatchQuery("tags.key", "bat.foo"))),
+			elastic.NewNestedQuery("process.tags", elastic.NewBoolQuery().Must(elastic.NewMatchQuery("process.tags.key", "bat.foo"))),
+			elastic.NewNestedQuery("logs.fields", elastic.NewBoolQuery().Must(elastic.NewMatchQuery("logs.fields.key", "bat.foo"))),
+		).Source()
+		require.NoError(t, err)
+		assert.Equal(t, expected, existsQuery)
+	})
+}
+
+func TestSpanReader_SupportsTagPredicate(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		for op, expected := range map[spanstore.TagOperator]bool{
+			spanstore.TagOpEqual:          true,
+			spanstore.TagOpNotEqual:       true,
+			spanstore.TagOpExists:         true,
+			spanstore.TagOpNotExists:      true,
+			spanstore.TagOpPrefix:         true,
+			spanstore.TagOpGreaterOrEqual: false,
+			spanstore.TagOpRegex:          false,
+		} {
+			assert.Equal(t, expected, r.reader.SupportsTagPredicate(spanstore.TagPredicate{Key: "k", Operator: op}), op.String())
+		}
+	})
+}
+
 func TestSpanReader_GetEmptyIndex(t *testing.T) {
 	withSpanReader(func(r *spanReaderTest) {
 		mockSearchService(r).
@@ -24,6 +24,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/jaegertracing/jaeger/pkg/catalog"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
 	storageMetrics "github.com/jaegertracing/jaeger/storage/spanstore/metrics"
@@ -50,6 +51,7 @@ type SpanWriter struct {
 	serviceWriter    serviceWriter
 	spanConverter    dbmodel.FromDomain
 	spanServiceIndex spanAndServiceIndexFn
+	catalog          *catalog.Tracker
 }
 
 // SpanWriterParams holds constructor parameters for NewSpanWriter
@@ -63,6 +65,8 @@ type SpanWriterParams struct {
 	TagDotReplacement   string
 	Archive             bool
 	UseReadWriteAliases bool
+	// Catalog, if set, tracks the activity of the services whose spans are written to the service index.
+	Catalog *catalog.Tracker
 }
 
 // NewSpanWriter creates a new SpanWriter for use
@@ -87,6 +91,7 @@ func NewSpanWriter(p SpanWriterParams) *SpanWriter {
 		),
 		spanConverter:    dbmodel.NewFromDomain(p.AllTagsAsFields, p.TagKeysAsFields, p.TagDotReplacement),
 		spanServiceIndex: getSpanAndServiceIndexFn(p.Archive, p.UseReadWriteAliases, p.IndexPrefix),
+		catalog:          p.Catalog,
 	}
 }
 
@@ -137,6 +142,9 @@ func (s *SpanWriter) WriteSpan(span *model.Span) error {
 	jsonSpan := s.spanConverter.FromDomainEmbedProcess(span)
 	if serviceIndexName != "" {
 		s.writeService(serviceIndexName, jsonSpan)
+		if s.catalog != nil {
+			s.catalog.Record(span)
+		}
 	}
 	s.writeSpan(spanIndexName, jsonSpan)
 	return nil
@@ -29,6 +29,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/catalog"
 	"github.com/jaegertracing/jaeger/pkg/es/mocks"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
@@ -162,6 +163,7 @@ func TestSpanWriter_WriteSpan(t *testing.T) {
 				indexSpanPut.On("Add")
 
 				w.client.On("Index").Return(indexService)
+				w.writer.catalog = catalog.NewTracker()
 
 				err = w.writer.WriteSpan(span)
 
@@ -169,6 +171,10 @@ func TestSpanWriter_WriteSpan(t *testing.T) {
 					require.NoError(t, err)
 					indexServicePut.AssertNumberOfCalls(t, "Add", 1)
 					indexSpanPut.AssertNumberOfCalls(t, "Add", 1)
+					services, err := w.writer.catalog.GetServiceCatalog(context.Background())
+					require.NoError(t, err)
+					require.Len(t, services, 1)
+					assert.Equal(t, "service", services[0].ServiceName)
 				} else {
 					assert.EqualError(t, err, testCase.expectedError)
 				}
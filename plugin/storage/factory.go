@@ -161,6 +161,34 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return factory.CreateDependencyReader()
 }
 
+// CreateDependencyWriter implements storage.DependencyWriterFactory
+func (f *Factory) CreateDependencyWriter() (dependencystore.Writer, error) {
+	factory, ok := f.factories[f.DependenciesStorageType]
+	if !ok {
+		return nil, fmt.Errorf("no %s backend registered for span store", f.DependenciesStorageType)
+	}
+	writerFactory, ok := factory.(storage.DependencyWriterFactory)
+	if !ok {
+		return nil, storage.ErrDependencyWriterNotSupported
+	}
+	return writerFactory.CreateDependencyWriter()
+}
+
+// CreateCatalogReader implements storage.CatalogFactory.
+// The catalog is tracked by the span writer of the first of the span writer types that supports it.
+func (f *Factory) CreateCatalogReader() (spanstore.CatalogReader, error) {
+	for _, storageType := range f.SpanWriterTypes {
+		factory, ok := f.factories[storageType]
+		if !ok {
+			return nil, fmt.Errorf("no %s backend registered for span store", storageType)
+		}
+		if catalogFactory, ok := factory.(storage.CatalogFactory); ok {
+			return catalogFactory.CreateCatalogReader()
+		}
+	}
+	return nil, storage.ErrCatalogNotSupported
+}
+
 // AddFlags implements plugin.Configurable
 func (f *Factory) AddFlags(flagSet *flag.FlagSet) {
 	for _, factory := range f.factories {
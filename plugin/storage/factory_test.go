@@ -28,7 +28,9 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/catalog"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/storage"
 	depStoreMocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
 	"github.com/jaegertracing/jaeger/storage/mocks"
@@ -38,6 +40,7 @@ import (
 
 var _ storage.Factory = new(Factory)
 var _ storage.ArchiveFactory = new(Factory)
+var _ storage.DependencyWriterFactory = new(Factory)
 
 func defaultCfg() FactoryConfig {
 	return FactoryConfig{
@@ -236,6 +239,61 @@ func TestCreateArchive(t *testing.T) {
 	assert.EqualError(t, err, "archive-span-writer-error")
 }
 
+func TestCreateDependencyWriter(t *testing.T) {
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+	assert.NotEmpty(t, f.factories[cassandraStorageType])
+
+	f.factories[cassandraStorageType] = new(mocks.Factory)
+	_, err = f.CreateDependencyWriter()
+	assert.Equal(t, storage.ErrDependencyWriterNotSupported, err)
+
+	mock := &struct {
+		mocks.Factory
+		mocks.DependencyWriterFactory
+	}{}
+	f.factories[cassandraStorageType] = mock
+
+	depWriter := memory.NewStore()
+	mock.DependencyWriterFactory.On("CreateDependencyWriter").Return(depWriter, errors.New("dep-writer-error"))
+
+	dw, err := f.CreateDependencyWriter()
+	assert.Equal(t, depWriter, dw)
+	assert.EqualError(t, err, "dep-writer-error")
+
+	f.DependenciesStorageType = "foo"
+	_, err = f.CreateDependencyWriter()
+	assert.EqualError(t, err, "no foo backend registered for span store")
+}
+
+func TestCreateCatalogReader(t *testing.T) {
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+	assert.NotEmpty(t, f.factories[cassandraStorageType])
+
+	f.factories[cassandraStorageType] = new(mocks.Factory)
+	_, err = f.CreateCatalogReader()
+	assert.Equal(t, storage.ErrCatalogNotSupported, err)
+
+	mock := &struct {
+		mocks.Factory
+		mocks.CatalogFactory
+	}{}
+	f.factories[elasticsearchStorageType] = mock
+	f.SpanWriterTypes = []string{cassandraStorageType, elasticsearchStorageType}
+
+	catalogReader := catalog.NewTracker()
+	mock.CatalogFactory.On("CreateCatalogReader").Return(catalogReader, errors.New("catalog-error"))
+
+	cr, err := f.CreateCatalogReader()
+	assert.Equal(t, catalogReader, cr)
+	assert.EqualError(t, err, "catalog-error")
+
+	f.SpanWriterTypes = []string{"foo"}
+	_, err = f.CreateCatalogReader()
+	assert.EqualError(t, err, "no foo backend registered for span store")
+}
+
 func TestCreateError(t *testing.T) {
 	f, err := NewFactory(defaultCfg())
 	require.NoError(t, err)
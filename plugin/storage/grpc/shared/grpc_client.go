@@ -138,14 +138,17 @@ func (c *grpcClient) GetOperations(
 func (c *grpcClient) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
 	stream, err := c.readerClient.FindTraces(upgradeContextWithBearerToken(ctx), &storage_v1.FindTracesRequest{
 		Query: &storage_v1.TraceQueryParameters{
-			ServiceName:   query.ServiceName,
-			OperationName: query.OperationName,
-			Tags:          query.Tags,
-			StartTimeMin:  query.StartTimeMin,
-			StartTimeMax:  query.StartTimeMax,
-			DurationMin:   query.DurationMin,
-			DurationMax:   query.DurationMax,
-			NumTraces:     int32(query.NumTraces),
+			ServiceName:    query.ServiceName,
+			OperationName:  query.OperationName,
+			Tags:           query.Tags,
+			StartTimeMin:   query.StartTimeMin,
+			StartTimeMax:   query.StartTimeMax,
+			DurationMin:    query.DurationMin,
+			DurationMax:    query.DurationMax,
+			NumTraces:      int32(query.NumTraces),
+			PageToken:      query.PageToken,
+			ServiceNames:   query.ServiceNames,
+			OperationNames: query.OperationNames,
 		},
 	})
 	if err != nil {
@@ -176,14 +179,17 @@ func (c *grpcClient) FindTraces(ctx context.Context, query *spanstore.TraceQuery
 func (c *grpcClient) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
 	resp, err := c.readerClient.FindTraceIDs(upgradeContextWithBearerToken(ctx), &storage_v1.FindTraceIDsRequest{
 		Query: &storage_v1.TraceQueryParameters{
-			ServiceName:   query.ServiceName,
-			OperationName: query.OperationName,
-			Tags:          query.Tags,
-			StartTimeMin:  query.StartTimeMin,
-			StartTimeMax:  query.StartTimeMax,
-			DurationMin:   query.DurationMin,
-			DurationMax:   query.DurationMax,
-			NumTraces:     int32(query.NumTraces),
+			ServiceName:    query.ServiceName,
+			OperationName:  query.OperationName,
+			Tags:           query.Tags,
+			StartTimeMin:   query.StartTimeMin,
+			StartTimeMax:   query.StartTimeMax,
+			DurationMin:    query.DurationMin,
+			DurationMax:    query.DurationMax,
+			NumTraces:      int32(query.NumTraces),
+			PageToken:      query.PageToken,
+			ServiceNames:   query.ServiceNames,
+			OperationNames: query.OperationNames,
 		},
 	})
 	if err != nil {
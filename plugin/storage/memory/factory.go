@@ -71,3 +71,13 @@ func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
 func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return f.store, nil
 }
+
+// CreateDependencyWriter implements storage.DependencyWriterFactory
+func (f *Factory) CreateDependencyWriter() (dependencystore.Writer, error) {
+	return f.store, nil
+}
+
+// CreateCatalogReader implements storage.CatalogFactory
+func (f *Factory) CreateCatalogReader() (spanstore.CatalogReader, error) {
+	return f.store, nil
+}
@@ -18,16 +18,23 @@ package memory
 import (
 	"context"
 	"errors"
-	"sort"
 	"sync"
 	"time"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/pkg/catalog"
 	"github.com/jaegertracing/jaeger/pkg/memory/config"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
+var errMalformedRequestObject = errors.New("malformed request object")
+
+// maxDependenciesLookback is the longest lookback of the dependency queries of the UI. The dependencies
+// written further back than that from the latest written ones are dropped.
+const maxDependenciesLookback = 7 * 24 * time.Hour
+
 // Store is an in-memory store of traces
 type Store struct {
 	sync.RWMutex
@@ -38,6 +45,17 @@ type Store struct {
 	deduper    adjuster.Adjuster
 	config     config.Configuration
 	index      int
+	// dependencies are the dependency links written to the store, in the order of writing
+	dependencies []timeDependencies
+	catalog      *catalog.Tracker
+	now          func() time.Time
+}
+
+// timeDependencies are the links written for a timestamp, which cover the time range from it to their writing.
+type timeDependencies struct {
+	ts        time.Time
+	writtenAt time.Time
+	links     []model.DependencyLink
 }
 
 // NewStore creates an unbounded in-memory store
@@ -54,16 +72,80 @@ func WithConfiguration(configuration config.Configuration) *Store {
 		operations: map[string]map[spanstore.Operation]struct{}{},
 		deduper:    adjuster.SpanIDDeduper(),
 		config:     configuration,
+		catalog:    catalog.NewTracker(),
+		now:        time.Now,
 	}
 }
 
-// GetDependencies returns dependencies between services
+// WriteDependencies implements dependencystore.Writer
+func (m *Store) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
+	m.Lock()
+	defer m.Unlock()
+	links := make([]model.DependencyLink, len(dependencies))
+	copy(links, dependencies)
+	// the links written again for the same timestamp replace the previous ones
+	for i := range m.dependencies {
+		if m.dependencies[i].ts.Equal(ts) {
+			m.dependencies[i].links = links
+			m.dependencies[i].writtenAt = m.now()
+			return nil
+		}
+	}
+	m.dependencies = append(m.dependencies, timeDependencies{ts: ts, writtenAt: m.now(), links: links})
+	m.dropOldDependencies()
+	return nil
+}
+
+func (m *Store) dropOldDependencies() {
+	latest := m.dependencies[0].ts
+	for _, written := range m.dependencies[1:] {
+		if written.ts.After(latest) {
+			latest = written.ts
+		}
+	}
+	oldest := latest.Add(-maxDependenciesLookback)
+	kept := m.dependencies[:0]
+	for _, written := range m.dependencies {
+		if !written.ts.Before(oldest) {
+			kept = append(kept, written)
+		}
+	}
+	m.dependencies = kept
+}
+
+// GetDependencies returns dependencies between services. The dependency links written to the store
+// with a timestamp in the time range are summed up, and the links of the parts of the time range that
+// they do not cover, see timeDependencies, are derived from the traces.
 func (m *Store) GetDependencies(endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
 	// deduper used below can modify the spans, so we take an exclusive lock
 	m.Lock()
 	defer m.Unlock()
 	deps := map[string]*model.DependencyLink{}
 	startTs := endTs.Add(-1 * lookback)
+	var covered []dependencystore.TimeRange
+	for _, written := range m.dependencies {
+		if written.ts.Before(startTs) || written.ts.After(endTs) {
+			continue
+		}
+		covered = append(covered, dependencystore.TimeRange{Start: written.ts, End: written.writtenAt})
+		for _, link := range written.links {
+			depKey := link.Parent + "&&&" + link.Child
+			if dep, ok := deps[depKey]; ok {
+				dep.CallCount += link.CallCount
+			} else {
+				dep := link
+				deps[depKey] = &dep
+			}
+		}
+	}
+	for _, uncovered := range dependencystore.UncoveredRanges(startTs, endTs, covered) {
+		m.addTraceDependencies(deps, uncovered.Start, uncovered.End)
+	}
+	return dependencyMapToSlice(deps), nil
+}
+
+// addTraceDependencies adds the dependency links derived from the traces in the time range.
+func (m *Store) addTraceDependencies(deps map[string]*model.DependencyLink, startTs, endTs time.Time) {
 	for _, orig := range m.traces {
 		// SpanIDDeduper never returns an err
 		trace, _ := m.deduper.Adjust(orig)
@@ -88,11 +170,32 @@ func (m *Store) GetDependencies(endTs time.Time, lookback time.Duration) ([]mode
 			}
 		}
 	}
+}
+
+// GetDetailedDependencies implements dependencystore.DetailedReader. The detailed links are
+// always derived from the traces in the time range, since the written links are not detailed.
+func (m *Store) GetDetailedDependencies(endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	// deduper used below can modify the spans, so we take an exclusive lock
+	m.Lock()
+	defer m.Unlock()
+	links := dependencystore.NewDetailedLinks()
+	startTs := endTs.Add(-1 * lookback)
+	for _, orig := range m.traces {
+		// SpanIDDeduper never returns an err
+		trace, _ := m.deduper.Adjust(orig)
+		if m.traceIsBetweenStartAndEnd(startTs, endTs, trace) {
+			links.AddTrace(trace)
+		}
+	}
+	return links.Links(), nil
+}
+
+func dependencyMapToSlice(deps map[string]*model.DependencyLink) []model.DependencyLink {
 	retMe := make([]model.DependencyLink, 0, len(deps))
 	for _, dep := range deps {
 		retMe = append(retMe, *dep)
 	}
-	return retMe, nil
+	return retMe
 }
 
 func (m *Store) findSpan(trace *model.Trace, spanID model.SpanID) *model.Span {
@@ -132,6 +235,7 @@ func (m *Store) WriteSpan(span *model.Span) error {
 	}
 
 	m.services[span.Process.ServiceName] = struct{}{}
+	m.catalog.Record(span)
 	if _, ok := m.traces[span.TraceID]; !ok {
 		m.traces[span.TraceID] = &model.Trace{}
 
@@ -175,6 +279,11 @@ func (m *Store) copyTrace(trace *model.Trace) *model.Trace {
 	}
 }
 
+// GetServiceCatalog implements spanstore.CatalogReader
+func (m *Store) GetServiceCatalog(ctx context.Context) ([]spanstore.ServiceActivity, error) {
+	return m.catalog.GetServiceCatalog(ctx)
+}
+
 // GetServices returns a list of all known services
 func (m *Store) GetServices(ctx context.Context) ([]string, error) {
 	m.RLock()
@@ -204,86 +313,70 @@ func (m *Store) GetOperations(
 	return retMe, nil
 }
 
-// FindTraces returns all traces in the query parameters are satisfied by a trace's span
+// FindTraces returns all traces in the query parameters are satisfied by a trace's span.
+// Traces are returned newest first, in the order defined by spanstore.PageToken.
 func (m *Store) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
 	m.RLock()
 	defer m.RUnlock()
-	var retMe []*model.Trace
-	for _, trace := range m.traces {
-		if m.validTrace(trace, query) {
-			retMe = append(retMe, m.copyTrace(trace))
-		}
-	}
-
-	// Query result order doesn't matter, as the query frontend will sort them anyway.
-	// However, if query.NumTraces < results, then we should return the newest traces.
-	if query.NumTraces > 0 && len(retMe) > query.NumTraces {
-		sort.Slice(retMe, func(i, j int) bool {
-			return retMe[i].Spans[0].StartTime.Before(retMe[j].Spans[0].StartTime)
-		})
-		retMe = retMe[len(retMe)-query.NumTraces:]
-	}
-
-	return retMe, nil
+	return m.findTraces(query)
 }
 
-// FindTraceIDs is not implemented.
-func (m *Store) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
-	return nil, errors.New("not implemented")
+// SupportsTagPredicate implements spanstore.TagPredicateSupport, the store evaluates all tag predicates.
+func (m *Store) SupportsTagPredicate(predicate spanstore.TagPredicate) bool {
+	return true
 }
 
-func (m *Store) validTrace(trace *model.Trace, query *spanstore.TraceQueryParameters) bool {
-	for _, span := range trace.Spans {
-		if m.validSpan(span, query) {
-			return true
-		}
-	}
-	return false
+// SupportsTracePredicates implements spanstore.TracePredicateSupport, the store evaluates all trace predicates.
+func (m *Store) SupportsTracePredicates(predicates *spanstore.TracePredicates) bool {
+	return true
 }
 
-func findKeyValueMatch(kvs model.KeyValues, key, value string) (model.KeyValue, bool) {
-	for _, kv := range kvs {
-		if kv.Key == key && kv.AsString() == value {
-			return kv, true
-		}
+// FindTraceIDs returns the IDs of the traces FindTraces would return.
+func (m *Store) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	m.RLock()
+	defer m.RUnlock()
+	traces, err := m.findTraces(query)
+	if err != nil {
+		return nil, err
 	}
-	return model.KeyValue{}, false
+	traceIDs := make([]model.TraceID, len(traces))
+	for i, trace := range traces {
+		traceIDs[i] = trace.Spans[0].TraceID
+	}
+	return traceIDs, nil
 }
 
-func (m *Store) validSpan(span *model.Span, query *spanstore.TraceQueryParameters) bool {
-	if query.ServiceName != span.Process.ServiceName {
-		return false
-	}
-	if query.OperationName != "" && query.OperationName != span.OperationName {
-		return false
-	}
-	if query.DurationMin != 0 && span.Duration < query.DurationMin {
-		return false
-	}
-	if query.DurationMax != 0 && span.Duration > query.DurationMax {
-		return false
-	}
-	if !query.StartTimeMin.IsZero() && span.StartTime.Before(query.StartTimeMin) {
-		return false
+func (m *Store) findTraces(query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	if query == nil {
+		return nil, errMalformedRequestObject
 	}
-	if !query.StartTimeMax.IsZero() && span.StartTime.After(query.StartTimeMax) {
-		return false
+	pageToken, err := spanstore.ParsePageToken(query.PageToken)
+	if err != nil {
+		return nil, err
 	}
-	spanKVs := m.flattenTags(span)
-	for queryK, queryV := range query.Tags {
-		// (NB): we cannot use the KeyValues.FindKey function because there can be multiple tags with the same key
-		if _, ok := findKeyValueMatch(spanKVs, queryK, queryV); !ok {
-			return false
+	var retMe []*model.Trace
+	for traceID, trace := range m.traces {
+		if m.validTrace(trace, query, pageToken, traceID) {
+			retMe = append(retMe, m.copyTrace(trace))
 		}
 	}
-	return true
+
+	spanstore.SortTracesForPaging(query, retMe)
+	if query.NumTraces > 0 && len(retMe) > query.NumTraces {
+		retMe = retMe[:query.NumTraces]
+	}
+	return retMe, nil
 }
 
-func (m *Store) flattenTags(span *model.Span) model.KeyValues {
-	retMe := span.Tags
-	retMe = append(retMe, span.Process.Tags...)
-	for _, l := range span.Logs {
-		retMe = append(retMe, l.Fields...)
+func (m *Store) validTrace(
+	trace *model.Trace,
+	query *spanstore.TraceQueryParameters,
+	pageToken *spanstore.PageToken,
+	traceID model.TraceID,
+) bool {
+	if len(query.Services()) == 0 && (query.TracePredicates == nil || len(query.TracePredicates.Services) == 0) {
+		return false
 	}
-	return retMe
+	latest, found := spanstore.LatestMatchingSpanTime(query, trace)
+	return found && pageToken.Admits(latest, traceID) && query.TracePredicates.MatchTrace(trace)
 }
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/memory/config"
@@ -165,6 +166,129 @@ func TestStoreGetDependencies(t *testing.T) {
 	})
 }
 
+func TestStoreGetDetailedDependencies(t *testing.T) {
+	withMemoryStore(func(store *Store) {
+		assert.NoError(t, store.WriteSpan(testingSpan))
+		assert.NoError(t, store.WriteSpan(childSpan1))
+		assert.NoError(t, store.WriteSpan(childSpan2))
+		assert.NoError(t, store.WriteSpan(childSpan2_1))
+		// the written links are not detailed, so they are ignored
+		assert.NoError(t, store.WriteDependencies(time.Unix(0, 0), []model.DependencyLink{{Parent: "a", Child: "b", CallCount: 1}}))
+
+		links, err := store.GetDetailedDependencies(time.Now(), time.Hour)
+		assert.NoError(t, err)
+		assert.Empty(t, links)
+
+		links, err = store.GetDetailedDependencies(time.Unix(0, 0).Add(time.Hour), time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.DependencyLink{{
+			Parent:          "serviceName",
+			Child:           "childService",
+			CallCount:       2,
+			ParentOperation: "operationName",
+			ChildOperation:  "childOperationName",
+			Kind:            model.RPCDependency,
+			LatencyP50:      5 * time.Second,
+			LatencyP95:      5 * time.Second,
+			LatencyP99:      5 * time.Second,
+		}}, links)
+	})
+}
+
+func TestStoreWriteDependencies(t *testing.T) {
+	withMemoryStore(func(store *Store) {
+		assert.NoError(t, store.WriteSpan(testingSpan))
+		assert.NoError(t, store.WriteSpan(childSpan1))
+		ts := time.Unix(1000, 0)
+		assert.NoError(t, store.WriteDependencies(ts, []model.DependencyLink{
+			{Parent: "frontend", Child: "backend", CallCount: 2},
+			{Parent: "backend", Child: "db", CallCount: 1},
+		}))
+		assert.NoError(t, store.WriteDependencies(ts.Add(time.Minute), []model.DependencyLink{
+			{Parent: "frontend", Child: "backend", CallCount: 3},
+		}))
+		assert.NoError(t, store.WriteDependencies(ts.Add(2*time.Hour), []model.DependencyLink{
+			{Parent: "frontend", Child: "db", CallCount: 1},
+		}))
+
+		// the written dependencies cover the time range, so they replace the ones derived from the traces
+		links, err := store.GetDependencies(ts.Add(time.Hour), time.Hour)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []model.DependencyLink{
+			{Parent: "frontend", Child: "backend", CallCount: 5},
+			{Parent: "backend", Child: "db", CallCount: 1},
+		}, links)
+
+		links, err = store.GetDependencies(ts.Add(4*time.Hour), time.Hour)
+		assert.NoError(t, err)
+		assert.Empty(t, links)
+
+		// writing the same timestamp again replaces the links
+		assert.NoError(t, store.WriteDependencies(ts, []model.DependencyLink{
+			{Parent: "backend", Child: "db", CallCount: 4},
+		}))
+		links, err = store.GetDependencies(ts.Add(time.Hour), time.Hour)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []model.DependencyLink{
+			{Parent: "frontend", Child: "backend", CallCount: 3},
+			{Parent: "backend", Child: "db", CallCount: 4},
+		}, links)
+
+		// the dependencies older than the longest lookback are dropped
+		assert.NoError(t, store.WriteDependencies(ts.Add(maxDependenciesLookback+2*time.Minute), []model.DependencyLink{
+			{Parent: "frontend", Child: "cache", CallCount: 1},
+		}))
+		assert.Len(t, store.dependencies, 2)
+		// the time range before the earliest written dependencies is derived from the traces
+		links, err = store.GetDependencies(ts.Add(maxDependenciesLookback+2*time.Minute), 2*maxDependenciesLookback)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []model.DependencyLink{
+			{Parent: "serviceName", Child: "childService", CallCount: 1},
+			{Parent: "frontend", Child: "db", CallCount: 1},
+			{Parent: "frontend", Child: "cache", CallCount: 1},
+		}, links)
+	})
+}
+
+func TestStoreGetDependenciesWithGap(t *testing.T) {
+	withMemoryStore(func(store *Store) {
+		assert.NoError(t, store.WriteSpan(testingSpan))
+		assert.NoError(t, store.WriteSpan(childSpan1))
+		assert.NoError(t, store.WriteSpan(childSpan2))
+		now := time.Unix(600, 0)
+		store.now = func() time.Time { return now }
+		// the links written at 600s cover the time range until their writing, i.e. the traces at 300s are not covered
+		assert.NoError(t, store.WriteDependencies(now, []model.DependencyLink{
+			{Parent: "frontend", Child: "backend", CallCount: 2},
+		}))
+
+		links, err := store.GetDependencies(time.Unix(900, 0), 15*time.Minute)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []model.DependencyLink{
+			{Parent: "serviceName", Child: "childService", CallCount: 2},
+			{Parent: "frontend", Child: "backend", CallCount: 2},
+		}, links)
+
+		// the links written at 200s until 400s cover the traces
+		now = time.Unix(400, 0)
+		assert.NoError(t, store.WriteDependencies(time.Unix(200, 0), []model.DependencyLink{
+			{Parent: "frontend", Child: "backend", CallCount: 1},
+		}))
+		links, err = store.GetDependencies(time.Unix(900, 0), 15*time.Minute)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []model.DependencyLink{
+			{Parent: "frontend", Child: "backend", CallCount: 3},
+		}, links)
+
+		// no links are written in the time range
+		links, err = store.GetDependencies(time.Unix(350, 0), 100*time.Second)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []model.DependencyLink{
+			{Parent: "serviceName", Child: "childService", CallCount: 2},
+		}, links)
+	})
+}
+
 func TestStoreWriteSpan(t *testing.T) {
 	withMemoryStore(func(store *Store) {
 		err := store.WriteSpan(testingSpan)
@@ -227,6 +351,17 @@ func TestStoreGetServices(t *testing.T) {
 	})
 }
 
+func TestStoreGetServiceCatalog(t *testing.T) {
+	withPopulatedMemoryStore(func(store *Store) {
+		catalog, err := store.GetServiceCatalog(context.Background())
+		assert.NoError(t, err)
+		require.Len(t, catalog, 1)
+		assert.Equal(t, testingSpan.Process.ServiceName, catalog[0].ServiceName)
+		assert.Equal(t, 1, catalog[0].OperationCount)
+		assert.False(t, catalog[0].LastSeen.IsZero())
+	})
+}
+
 func TestStoreGetAllOperationsFound(t *testing.T) {
 	withPopulatedMemoryStore(func(store *Store) {
 		assert.NoError(t, store.WriteSpan(testingSpan))
@@ -304,11 +439,11 @@ func TestStoreFindTracesLimitGetsMostRecent(t *testing.T) {
 			})
 	}
 
-	// Want the two most recent spans, not any two spans
+	// Want the most recent spans, not any spans, newest first
 	var expectedTraces []*model.Trace
-	for _, span := range spans[storeSize-querySize:] {
+	for i := storeSize - 1; i >= storeSize-querySize; i-- {
 		trace := &model.Trace{
-			Spans: []*model.Span{span},
+			Spans: []*model.Span{spans[i]},
 		}
 		expectedTraces = append(expectedTraces, trace)
 	}
@@ -410,10 +545,142 @@ func TestStoreGetTrace(t *testing.T) {
 	}
 }
 
+func TestStoreFindTracesPaging(t *testing.T) {
+	memStore := NewStore()
+	for i := 0; i < 5; i++ {
+		memStore.WriteSpan(&model.Span{
+			TraceID:       model.NewTraceID(1, uint64(i)),
+			SpanID:        model.NewSpanID(1),
+			OperationName: "operationName",
+			// two traces share each start time to exercise the trace ID tie-breaker
+			StartTime: time.Unix(int64(i/2), 0),
+			Process: &model.Process{
+				ServiceName: "serviceName",
+			},
+		})
+	}
+
+	query := &spanstore.TraceQueryParameters{
+		ServiceName: "serviceName",
+		NumTraces:   2,
+	}
+	var gotIDs []model.TraceID
+	for page := 0; page < 3; page++ {
+		traces, err := memStore.FindTraces(context.Background(), query)
+		require.NoError(t, err)
+		for _, trace := range traces {
+			gotIDs = append(gotIDs, trace.Spans[0].TraceID)
+		}
+		query.PageToken = spanstore.NextPageToken(query, traces)
+	}
+	assert.Empty(t, query.PageToken)
+	assert.Equal(t, []model.TraceID{
+		model.NewTraceID(1, 4),
+		model.NewTraceID(1, 3),
+		model.NewTraceID(1, 2),
+		model.NewTraceID(1, 1),
+		model.NewTraceID(1, 0),
+	}, gotIDs)
+}
+
+func TestStoreFindTracesMalformedPageToken(t *testing.T) {
+	withPopulatedMemoryStore(func(store *Store) {
+		traces, err := store.FindTraces(context.Background(), &spanstore.TraceQueryParameters{
+			ServiceName: testingSpan.Process.ServiceName,
+			PageToken:   "!",
+		})
+		assert.Nil(t, traces)
+		assert.Equal(t, spanstore.ErrMalformedPageToken, err)
+	})
+}
+
+func TestStoreFindTracesWithTagPredicates(t *testing.T) {
+	withPopulatedMemoryStore(func(store *Store) {
+		testCases := []struct {
+			predicates []spanstore.TagPredicateGroup
+			expected   int
+		}{
+			{
+				predicates: []spanstore.TagPredicateGroup{
+					{{Key: "tagKey", Operator: spanstore.TagOpPrefix, Value: "tag"}},
+					{{Key: "logKey", Operator: spanstore.TagOpExists}},
+				},
+				expected: 1,
+			},
+			{
+				predicates: []spanstore.TagPredicateGroup{
+					{{Key: "span.kind", Operator: spanstore.TagOpNotEqual, Value: "client"}},
+				},
+				expected: 0,
+			},
+			{
+				predicates: []spanstore.TagPredicateGroup{{
+					{Key: "span.kind", Operator: spanstore.TagOpEqual, Value: "server"},
+					{Key: "tagKey", Operator: spanstore.TagOpRegex, Value: "^tag"},
+				}},
+				expected: 1,
+			},
+		}
+		for i, testCase := range testCases {
+			traces, err := store.FindTraces(context.Background(), &spanstore.TraceQueryParameters{
+				ServiceName:   "serviceName",
+				TagPredicates: testCase.predicates,
+			})
+			require.NoError(t, err)
+			assert.Len(t, traces, testCase.expected, "test case %d", i)
+		}
+		assert.True(t, store.SupportsTagPredicate(spanstore.TagPredicate{Key: "k", Operator: spanstore.TagOpRegex, Value: "v"}))
+	})
+}
+
+func TestStoreFindTracesWithTracePredicates(t *testing.T) {
+	withPopulatedMemoryStore(func(store *Store) {
+		query := &spanstore.TraceQueryParameters{
+			ServiceName:     "serviceName",
+			TracePredicates: &spanstore.TracePredicates{MinSpanCount: 1, MaxSpanCount: intPtr(1)},
+		}
+		traces, err := store.FindTraces(context.Background(), query)
+		require.NoError(t, err)
+		assert.Len(t, traces, 1)
+
+		query.TracePredicates.MinSpanCount = 2
+		query.TracePredicates.MaxSpanCount = nil
+		traces, err = store.FindTraces(context.Background(), query)
+		require.NoError(t, err)
+		assert.Empty(t, traces)
+		assert.True(t, store.SupportsTracePredicates(query.TracePredicates))
+
+		// an explicit zero maximum is a condition
+		query.TracePredicates.MinSpanCount = 0
+		query.TracePredicates.MaxErrorCount = intPtr(0)
+		traces, err = store.FindTraces(context.Background(), query)
+		require.NoError(t, err)
+		assert.Len(t, traces, 1)
+
+		// the services of the trace predicates are enough to find traces
+		query.ServiceName = ""
+		query.TracePredicates.Services = []string{"serviceName"}
+		traces, err = store.FindTraces(context.Background(), query)
+		require.NoError(t, err)
+		assert.Len(t, traces, 1)
+	})
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
 func TestStore_FindTraceIDs(t *testing.T) {
 	withMemoryStore(func(store *Store) {
 		traceIDs, err := store.FindTraceIDs(context.Background(), nil)
 		assert.Nil(t, traceIDs)
-		assert.EqualError(t, err, "not implemented")
+		assert.EqualError(t, err, "malformed request object")
+	})
+	withPopulatedMemoryStore(func(store *Store) {
+		traceIDs, err := store.FindTraceIDs(context.Background(), &spanstore.TraceQueryParameters{
+			ServiceName: testingSpan.Process.ServiceName,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []model.TraceID{testingSpan.TraceID}, traceIDs)
 	})
 }
@@ -5,16 +5,20 @@ package api_v2
 
 import (
 	context "context"
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	_ "github.com/gogo/googleapis/google/api"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
+	_ "github.com/gogo/protobuf/types"
+	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
 	golang_proto "github.com/golang/protobuf/proto"
 	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger/options"
 	model "github.com/jaegertracing/jaeger/model"
 	grpc "google.golang.org/grpc"
 	io "io"
 	math "math"
+	time "time"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -22,6 +26,7 @@ var _ = proto.Marshal
 var _ = golang_proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
+var _ = time.Kitchen
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the proto package it is being compiled against.
@@ -115,212 +120,2685 @@ func (m *PostSpansResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PostSpansResponse proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterType((*PostSpansRequest)(nil), "jaeger.api_v2.PostSpansRequest")
-	golang_proto.RegisterType((*PostSpansRequest)(nil), "jaeger.api_v2.PostSpansRequest")
-	proto.RegisterType((*PostSpansResponse)(nil), "jaeger.api_v2.PostSpansResponse")
-	golang_proto.RegisterType((*PostSpansResponse)(nil), "jaeger.api_v2.PostSpansResponse")
+// TailSpansRequest subscribes to the spans processed from now on that match all of
+// the given criteria. Empty criteria match any span.
+type TailSpansRequest struct {
+	ServiceName          string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	OperationName        string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
+	Tags                 map[string]string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
-func init() { proto.RegisterFile("api_v2/collector.proto", fileDescriptor_495529cb13d121cf) }
-func init() { golang_proto.RegisterFile("api_v2/collector.proto", fileDescriptor_495529cb13d121cf) }
-
-var fileDescriptor_495529cb13d121cf = []byte{
-	// 336 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x90, 0x31, 0x4f, 0xc2, 0x40,
-	0x14, 0xc7, 0x3d, 0x14, 0x12, 0x8f, 0x90, 0x60, 0x25, 0x4a, 0x88, 0x29, 0xa4, 0x8b, 0x86, 0x48,
-	0x0f, 0x6b, 0x5c, 0xd8, 0xa8, 0x2e, 0x3a, 0x11, 0xd8, 0x5c, 0xcc, 0x51, 0x2f, 0xd7, 0x9a, 0x72,
-	0xef, 0xbc, 0x3b, 0xea, 0xe2, 0xe4, 0x47, 0xd0, 0x2f, 0xe4, 0xc8, 0x68, 0xe2, 0x6e, 0x0c, 0xfa,
-	0x41, 0x0c, 0x6d, 0x35, 0x82, 0x71, 0x7a, 0xef, 0xee, 0xff, 0xcb, 0xff, 0xfd, 0xdf, 0xc3, 0x3b,
-	0x54, 0x46, 0x57, 0x89, 0x47, 0x02, 0x88, 0x63, 0x16, 0x18, 0x50, 0xae, 0x54, 0x60, 0xc0, 0xaa,
-	0xdc, 0x50, 0xc6, 0x99, 0x72, 0x33, 0xb9, 0x51, 0x9e, 0xc0, 0x35, 0x8b, 0x33, 0xad, 0x51, 0xe3,
-	0xc0, 0x21, 0x6d, 0xc9, 0xa2, 0xcb, 0x7f, 0xf7, 0x38, 0x00, 0x8f, 0x19, 0xa1, 0x32, 0x22, 0x54,
-	0x08, 0x30, 0xd4, 0x44, 0x20, 0x74, 0xae, 0x1e, 0xa6, 0x25, 0xe8, 0x70, 0x26, 0x3a, 0xfa, 0x8e,
-	0x72, 0xce, 0x14, 0x01, 0x99, 0x12, 0x7f, 0x69, 0xe7, 0x0c, 0x57, 0x07, 0xa0, 0xcd, 0x48, 0x52,
-	0xa1, 0x87, 0xec, 0x76, 0xca, 0xb4, 0xb1, 0xba, 0xb8, 0x38, 0xa6, 0x26, 0x08, 0xeb, 0xa8, 0x85,
-	0x0e, 0xca, 0x5e, 0xcd, 0x5d, 0x4a, 0xe8, 0xfa, 0x0b, 0xcd, 0xdf, 0x98, 0xbd, 0x35, 0xd7, 0x86,
-	0x19, 0xe8, 0x6c, 0xe3, 0xad, 0x5f, 0x2e, 0x5a, 0x82, 0xd0, 0xcc, 0xbb, 0xc7, 0xd5, 0xd3, 0xef,
-	0x5d, 0x47, 0x4c, 0x25, 0x51, 0xc0, 0xac, 0x10, 0x6f, 0xfe, 0x80, 0x56, 0x73, 0xc5, 0x78, 0x35,
-	0x48, 0xa3, 0xf5, 0x3f, 0x90, 0xcd, 0x70, 0xea, 0x0f, 0xaf, 0x9f, 0x4f, 0x05, 0xcb, 0xa9, 0xa4,
-	0xc7, 0x48, 0x3c, 0xa2, 0x17, 0x72, 0x0f, 0xb5, 0xfd, 0xe4, 0xb1, 0xef, 0x5b, 0x45, 0x6f, 0xfd,
-	0xc8, 0xed, 0xb6, 0x0b, 0xa8, 0xa0, 0x4e, 0x30, 0xbe, 0x48, 0xcd, 0x5a, 0xfd, 0xc1, 0xb9, 0xb5,
-	0x1f, 0x1a, 0x23, 0x75, 0x8f, 0x10, 0x1e, 0x99, 0x70, 0x3a, 0x76, 0x03, 0x98, 0x90, 0x6c, 0x96,
-	0x51, 0x34, 0x88, 0x04, 0xcf, 0x5f, 0xb3, 0xb9, 0x8d, 0x5e, 0xe6, 0x36, 0x7a, 0x9f, 0xdb, 0xe8,
-	0xf9, 0xc3, 0x46, 0x78, 0x37, 0x02, 0x77, 0x09, 0xcc, 0xb3, 0x5d, 0x96, 0xb2, 0x3a, 0x2e, 0xa5,
-	0x77, 0x3d, 0xfe, 0x0a, 0x00, 0x00, 0xff, 0xff, 0x5b, 0xe8, 0xa6, 0x4c, 0xef, 0x01, 0x00, 0x00,
+func (m *TailSpansRequest) Reset()         { *m = TailSpansRequest{} }
+func (m *TailSpansRequest) String() string { return proto.CompactTextString(m) }
+func (*TailSpansRequest) ProtoMessage()    {}
+func (*TailSpansRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_495529cb13d121cf, []int{2}
+}
+func (m *TailSpansRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TailSpansRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TailSpansRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TailSpansRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailSpansRequest.Merge(m, src)
+}
+func (m *TailSpansRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TailSpansRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailSpansRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_TailSpansRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *TailSpansRequest) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
+	}
+	return ""
+}
 
-// CollectorServiceClient is the client API for CollectorService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type CollectorServiceClient interface {
-	PostSpans(ctx context.Context, in *PostSpansRequest, opts ...grpc.CallOption) (*PostSpansResponse, error)
+func (m *TailSpansRequest) GetOperationName() string {
+	if m != nil {
+		return m.OperationName
+	}
+	return ""
 }
 
-type collectorServiceClient struct {
-	cc *grpc.ClientConn
+func (m *TailSpansRequest) GetTags() map[string]string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
 }
 
-func NewCollectorServiceClient(cc *grpc.ClientConn) CollectorServiceClient {
-	return &collectorServiceClient{cc}
+type TailSpansResponse struct {
+	Spans                []model.Span `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
-func (c *collectorServiceClient) PostSpans(ctx context.Context, in *PostSpansRequest, opts ...grpc.CallOption) (*PostSpansResponse, error) {
-	out := new(PostSpansResponse)
-	err := c.cc.Invoke(ctx, "/jaeger.api_v2.CollectorService/PostSpans", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *TailSpansResponse) Reset()         { *m = TailSpansResponse{} }
+func (m *TailSpansResponse) String() string { return proto.CompactTextString(m) }
+func (*TailSpansResponse) ProtoMessage()    {}
+func (*TailSpansResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_495529cb13d121cf, []int{3}
+}
+func (m *TailSpansResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TailSpansResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TailSpansResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return out, nil
+}
+func (m *TailSpansResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailSpansResponse.Merge(m, src)
+}
+func (m *TailSpansResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TailSpansResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailSpansResponse.DiscardUnknown(m)
 }
 
-// CollectorServiceServer is the server API for CollectorService service.
-type CollectorServiceServer interface {
-	PostSpans(context.Context, *PostSpansRequest) (*PostSpansResponse, error)
+var xxx_messageInfo_TailSpansResponse proto.InternalMessageInfo
+
+func (m *TailSpansResponse) GetSpans() []model.Span {
+	if m != nil {
+		return m.Spans
+	}
+	return nil
 }
 
-func RegisterCollectorServiceServer(s *grpc.Server, srv CollectorServiceServer) {
-	s.RegisterService(&_CollectorService_serviceDesc, srv)
+type GetServiceCatalogRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func _CollectorService_PostSpans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PostSpansRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *GetServiceCatalogRequest) Reset()         { *m = GetServiceCatalogRequest{} }
+func (m *GetServiceCatalogRequest) String() string { return proto.CompactTextString(m) }
+func (*GetServiceCatalogRequest) ProtoMessage()    {}
+func (*GetServiceCatalogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_495529cb13d121cf, []int{4}
+}
+func (m *GetServiceCatalogRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetServiceCatalogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetServiceCatalogRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if interceptor == nil {
-		return srv.(CollectorServiceServer).PostSpans(ctx, in)
+}
+func (m *GetServiceCatalogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetServiceCatalogRequest.Merge(m, src)
+}
+func (m *GetServiceCatalogRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetServiceCatalogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetServiceCatalogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetServiceCatalogRequest proto.InternalMessageInfo
+
+// ServiceActivity describes the recent activity of a service, as seen by the span writers.
+type ServiceActivity struct {
+	ServiceName string    `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	LastSeen    time.Time `protobuf:"bytes,2,opt,name=last_seen,json=lastSeen,proto3,stdtime" json:"last_seen"`
+	// The number of spans per second averaged over the last 1, 5 and 15 minutes.
+	SpanRate1m     float64 `protobuf:"fixed64,3,opt,name=span_rate_1m,json=spanRate1m,proto3" json:"span_rate_1m,omitempty"`
+	SpanRate5m     float64 `protobuf:"fixed64,4,opt,name=span_rate_5m,json=spanRate5m,proto3" json:"span_rate_5m,omitempty"`
+	SpanRate15m    float64 `protobuf:"fixed64,5,opt,name=span_rate_15m,json=spanRate15m,proto3" json:"span_rate_15m,omitempty"`
+	OperationCount int64   `protobuf:"varint,6,opt,name=operation_count,json=operationCount,proto3" json:"operation_count,omitempty"`
+	// The distinct values of the jaeger.version, hostname and client-uuid process tags.
+	ClientVersions       []string `protobuf:"bytes,7,rep,name=client_versions,json=clientVersions,proto3" json:"client_versions,omitempty"`
+	Hostnames            []string `protobuf:"bytes,8,rep,name=hostnames,proto3" json:"hostnames,omitempty"`
+	ClientUUIDs          []string `protobuf:"bytes,9,rep,name=client_uuids,json=clientUuids,proto3" json:"client_uuids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServiceActivity) Reset()         { *m = ServiceActivity{} }
+func (m *ServiceActivity) String() string { return proto.CompactTextString(m) }
+func (*ServiceActivity) ProtoMessage()    {}
+func (*ServiceActivity) Descriptor() ([]byte, []int) {
+	return fileDescriptor_495529cb13d121cf, []int{5}
+}
+func (m *ServiceActivity) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ServiceActivity) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ServiceActivity.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/jaeger.api_v2.CollectorService/PostSpans",
+}
+func (m *ServiceActivity) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceActivity.Merge(m, src)
+}
+func (m *ServiceActivity) XXX_Size() int {
+	return m.Size()
+}
+func (m *ServiceActivity) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceActivity.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceActivity proto.InternalMessageInfo
+
+func (m *ServiceActivity) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CollectorServiceServer).PostSpans(ctx, req.(*PostSpansRequest))
+	return ""
+}
+
+func (m *ServiceActivity) GetLastSeen() time.Time {
+	if m != nil {
+		return m.LastSeen
 	}
-	return interceptor(ctx, in, info, handler)
+	return time.Time{}
 }
 
-var _CollectorService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "jaeger.api_v2.CollectorService",
-	HandlerType: (*CollectorServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "PostSpans",
-			Handler:    _CollectorService_PostSpans_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "api_v2/collector.proto",
+func (m *ServiceActivity) GetSpanRate1m() float64 {
+	if m != nil {
+		return m.SpanRate1m
+	}
+	return 0
 }
 
-func (m *PostSpansRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *ServiceActivity) GetSpanRate5m() float64 {
+	if m != nil {
+		return m.SpanRate5m
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *PostSpansRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	dAtA[i] = 0xa
-	i++
-	i = encodeVarintCollector(dAtA, i, uint64(m.Batch.Size()))
-	n1, err := m.Batch.MarshalTo(dAtA[i:])
-	if err != nil {
-		return 0, err
+func (m *ServiceActivity) GetSpanRate15m() float64 {
+	if m != nil {
+		return m.SpanRate15m
 	}
-	i += n1
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+	return 0
+}
+
+func (m *ServiceActivity) GetOperationCount() int64 {
+	if m != nil {
+		return m.OperationCount
 	}
-	return i, nil
+	return 0
 }
 
-func (m *PostSpansResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *ServiceActivity) GetClientVersions() []string {
+	if m != nil {
+		return m.ClientVersions
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *PostSpansResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+func (m *ServiceActivity) GetHostnames() []string {
+	if m != nil {
+		return m.Hostnames
 	}
-	return i, nil
+	return nil
 }
 
-func encodeVarintCollector(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *ServiceActivity) GetClientUUIDs() []string {
+	if m != nil {
+		return m.ClientUUIDs
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return nil
 }
-func (m *PostSpansRequest) Size() (n int) {
-	if m == nil {
-		return 0
+
+type GetServiceCatalogResponse struct {
+	Services             []ServiceActivity `protobuf:"bytes,1,rep,name=services,proto3" json:"services"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetServiceCatalogResponse) Reset()         { *m = GetServiceCatalogResponse{} }
+func (m *GetServiceCatalogResponse) String() string { return proto.CompactTextString(m) }
+func (*GetServiceCatalogResponse) ProtoMessage()    {}
+func (*GetServiceCatalogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_495529cb13d121cf, []int{6}
+}
+func (m *GetServiceCatalogResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetServiceCatalogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetServiceCatalogResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	var l int
-	_ = l
-	l = m.Batch.Size()
-	n += 1 + l + sovCollector(uint64(l))
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+}
+func (m *GetServiceCatalogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetServiceCatalogResponse.Merge(m, src)
+}
+func (m *GetServiceCatalogResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetServiceCatalogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetServiceCatalogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetServiceCatalogResponse proto.InternalMessageInfo
+
+func (m *GetServiceCatalogResponse) GetServices() []ServiceActivity {
+	if m != nil {
+		return m.Services
 	}
-	return n
+	return nil
 }
 
-func (m *PostSpansResponse) Size() (n int) {
-	if m == nil {
-		return 0
+// GetSpanMetricsRequest selects the RED metrics of a service over the lookback until the end time,
+// now if not set.
+type GetSpanMetricsRequest struct {
+	ServiceName          string        `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Lookback             time.Duration `protobuf:"bytes,2,opt,name=lookback,proto3,stdduration" json:"lookback"`
+	EndTime              time.Time     `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3,stdtime" json:"end_time"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetSpanMetricsRequest) Reset()         { *m = GetSpanMetricsRequest{} }
+func (m *GetSpanMetricsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetSpanMetricsRequest) ProtoMessage()    {}
+func (*GetSpanMetricsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_495529cb13d121cf, []int{7}
+}
+func (m *GetSpanMetricsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetSpanMetricsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetSpanMetricsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	var l int
+}
+func (m *GetSpanMetricsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSpanMetricsRequest.Merge(m, src)
+}
+func (m *GetSpanMetricsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetSpanMetricsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSpanMetricsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSpanMetricsRequest proto.InternalMessageInfo
+
+func (m *GetSpanMetricsRequest) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
+	}
+	return ""
+}
+
+func (m *GetSpanMetricsRequest) GetLookback() time.Duration {
+	if m != nil {
+		return m.Lookback
+	}
+	return 0
+}
+
+func (m *GetSpanMetricsRequest) GetEndTime() time.Time {
+	if m != nil {
+		return m.EndTime
+	}
+	return time.Time{}
+}
+
+// SpanMetrics are the number of calls, errors and the latency histogram of the spans of a service,
+// operation, span kind and dimension values.
+type SpanMetrics struct {
+	ServiceName   string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	OperationName string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
+	SpanKind      string            `protobuf:"bytes,3,opt,name=span_kind,json=spanKind,proto3" json:"span_kind,omitempty"`
+	Dimensions    map[string]string `protobuf:"bytes,4,rep,name=dimensions,proto3" json:"dimensions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Calls         uint64            `protobuf:"varint,5,opt,name=calls,proto3" json:"calls,omitempty"`
+	Errors        uint64            `protobuf:"varint,6,opt,name=errors,proto3" json:"errors,omitempty"`
+	// The upper bounds of the latency histogram buckets.
+	LatencyBuckets []time.Duration `protobuf:"bytes,7,rep,name=latency_buckets,json=latencyBuckets,proto3,stdduration" json:"latency_buckets"`
+	// The number of spans per latency bucket, followed by those above the last bucket.
+	LatencyCounts        []uint64 `protobuf:"varint,8,rep,packed,name=latency_counts,json=latencyCounts,proto3" json:"latency_counts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SpanMetrics) Reset()         { *m = SpanMetrics{} }
+func (m *SpanMetrics) String() string { return proto.CompactTextString(m) }
+func (*SpanMetrics) ProtoMessage()    {}
+func (*SpanMetrics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_495529cb13d121cf, []int{8}
+}
+func (m *SpanMetrics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SpanMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SpanMetrics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SpanMetrics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpanMetrics.Merge(m, src)
+}
+func (m *SpanMetrics) XXX_Size() int {
+	return m.Size()
+}
+func (m *SpanMetrics) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpanMetrics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpanMetrics proto.InternalMessageInfo
+
+func (m *SpanMetrics) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
+	}
+	return ""
+}
+
+func (m *SpanMetrics) GetOperationName() string {
+	if m != nil {
+		return m.OperationName
+	}
+	return ""
+}
+
+func (m *SpanMetrics) GetSpanKind() string {
+	if m != nil {
+		return m.SpanKind
+	}
+	return ""
+}
+
+func (m *SpanMetrics) GetDimensions() map[string]string {
+	if m != nil {
+		return m.Dimensions
+	}
+	return nil
+}
+
+func (m *SpanMetrics) GetCalls() uint64 {
+	if m != nil {
+		return m.Calls
+	}
+	return 0
+}
+
+func (m *SpanMetrics) GetErrors() uint64 {
+	if m != nil {
+		return m.Errors
+	}
+	return 0
+}
+
+func (m *SpanMetrics) GetLatencyBuckets() []time.Duration {
+	if m != nil {
+		return m.LatencyBuckets
+	}
+	return nil
+}
+
+func (m *SpanMetrics) GetLatencyCounts() []uint64 {
+	if m != nil {
+		return m.LatencyCounts
+	}
+	return nil
+}
+
+type GetSpanMetricsResponse struct {
+	Metrics              []SpanMetrics `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetSpanMetricsResponse) Reset()         { *m = GetSpanMetricsResponse{} }
+func (m *GetSpanMetricsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetSpanMetricsResponse) ProtoMessage()    {}
+func (*GetSpanMetricsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_495529cb13d121cf, []int{9}
+}
+func (m *GetSpanMetricsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetSpanMetricsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetSpanMetricsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetSpanMetricsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSpanMetricsResponse.Merge(m, src)
+}
+func (m *GetSpanMetricsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetSpanMetricsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSpanMetricsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSpanMetricsResponse proto.InternalMessageInfo
+
+func (m *GetSpanMetricsResponse) GetMetrics() []SpanMetrics {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*PostSpansRequest)(nil), "jaeger.api_v2.PostSpansRequest")
+	golang_proto.RegisterType((*PostSpansRequest)(nil), "jaeger.api_v2.PostSpansRequest")
+	proto.RegisterType((*PostSpansResponse)(nil), "jaeger.api_v2.PostSpansResponse")
+	golang_proto.RegisterType((*PostSpansResponse)(nil), "jaeger.api_v2.PostSpansResponse")
+	proto.RegisterType((*TailSpansRequest)(nil), "jaeger.api_v2.TailSpansRequest")
+	golang_proto.RegisterType((*TailSpansRequest)(nil), "jaeger.api_v2.TailSpansRequest")
+	proto.RegisterMapType((map[string]string)(nil), "jaeger.api_v2.TailSpansRequest.TagsEntry")
+	golang_proto.RegisterMapType((map[string]string)(nil), "jaeger.api_v2.TailSpansRequest.TagsEntry")
+	proto.RegisterType((*TailSpansResponse)(nil), "jaeger.api_v2.TailSpansResponse")
+	golang_proto.RegisterType((*TailSpansResponse)(nil), "jaeger.api_v2.TailSpansResponse")
+	proto.RegisterType((*GetServiceCatalogRequest)(nil), "jaeger.api_v2.GetServiceCatalogRequest")
+	golang_proto.RegisterType((*GetServiceCatalogRequest)(nil), "jaeger.api_v2.GetServiceCatalogRequest")
+	proto.RegisterType((*ServiceActivity)(nil), "jaeger.api_v2.ServiceActivity")
+	golang_proto.RegisterType((*ServiceActivity)(nil), "jaeger.api_v2.ServiceActivity")
+	proto.RegisterType((*GetServiceCatalogResponse)(nil), "jaeger.api_v2.GetServiceCatalogResponse")
+	golang_proto.RegisterType((*GetServiceCatalogResponse)(nil), "jaeger.api_v2.GetServiceCatalogResponse")
+	proto.RegisterType((*GetSpanMetricsRequest)(nil), "jaeger.api_v2.GetSpanMetricsRequest")
+	golang_proto.RegisterType((*GetSpanMetricsRequest)(nil), "jaeger.api_v2.GetSpanMetricsRequest")
+	proto.RegisterType((*SpanMetrics)(nil), "jaeger.api_v2.SpanMetrics")
+	golang_proto.RegisterType((*SpanMetrics)(nil), "jaeger.api_v2.SpanMetrics")
+	proto.RegisterMapType((map[string]string)(nil), "jaeger.api_v2.SpanMetrics.DimensionsEntry")
+	golang_proto.RegisterMapType((map[string]string)(nil), "jaeger.api_v2.SpanMetrics.DimensionsEntry")
+	proto.RegisterType((*GetSpanMetricsResponse)(nil), "jaeger.api_v2.GetSpanMetricsResponse")
+	golang_proto.RegisterType((*GetSpanMetricsResponse)(nil), "jaeger.api_v2.GetSpanMetricsResponse")
+}
+
+func init() { proto.RegisterFile("api_v2/collector.proto", fileDescriptor_495529cb13d121cf) }
+func init() { golang_proto.RegisterFile("api_v2/collector.proto", fileDescriptor_495529cb13d121cf) }
+
+var fileDescriptor_495529cb13d121cf = []byte{
+	// 1038 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x95, 0xcd, 0x6f, 0xe3, 0xc4,
+	0x1b, 0xc7, 0xd7, 0x4d, 0xda, 0x26, 0x4f, 0xda, 0xa6, 0x9d, 0xed, 0xf6, 0xe7, 0xf5, 0x6f, 0x95,
+	0x84, 0x88, 0x55, 0x43, 0xc5, 0xda, 0xad, 0x57, 0x11, 0xa8, 0xd2, 0x0a, 0x9a, 0x16, 0xa1, 0x5d,
+	0x5e, 0xb4, 0x72, 0x5b, 0x0e, 0x48, 0x28, 0x9a, 0x38, 0x83, 0x6b, 0x6a, 0x7b, 0x82, 0x67, 0x12,
+	0x54, 0x89, 0x0b, 0xfc, 0x05, 0x08, 0x2e, 0x9c, 0xf9, 0x1b, 0x38, 0x72, 0xe0, 0xb8, 0x47, 0x24,
+	0x38, 0x17, 0x54, 0xf8, 0x43, 0xd0, 0xbc, 0x38, 0x2f, 0x4e, 0xab, 0xed, 0x4a, 0x9c, 0xec, 0x79,
+	0xe6, 0xfb, 0xbc, 0xcc, 0xcc, 0x67, 0x9e, 0x81, 0x2d, 0x3c, 0x08, 0xbb, 0x23, 0xd7, 0xf1, 0x69,
+	0x14, 0x11, 0x9f, 0xd3, 0xd4, 0x1e, 0xa4, 0x94, 0x53, 0xb4, 0xfa, 0x05, 0x26, 0x01, 0x49, 0x6d,
+	0x35, 0x6d, 0x55, 0x62, 0xda, 0x27, 0x91, 0x9a, 0xb3, 0x36, 0x03, 0x1a, 0x50, 0xf9, 0xeb, 0x88,
+	0x3f, 0x6d, 0x7d, 0x10, 0x50, 0x1a, 0x44, 0xc4, 0xc1, 0x83, 0xd0, 0xc1, 0x49, 0x42, 0x39, 0xe6,
+	0x21, 0x4d, 0x98, 0x9e, 0xad, 0xeb, 0x59, 0x39, 0xea, 0x0d, 0x3f, 0x77, 0x78, 0x18, 0x13, 0xc6,
+	0x71, 0x3c, 0xd0, 0x82, 0x5a, 0x5e, 0xd0, 0x1f, 0xa6, 0x32, 0x82, 0x9e, 0x7f, 0x53, 0x7e, 0xfc,
+	0x47, 0x01, 0x49, 0x1e, 0xb1, 0xaf, 0x70, 0x10, 0x90, 0xd4, 0xa1, 0x03, 0x99, 0x62, 0x3e, 0x5d,
+	0xf3, 0x08, 0xd6, 0x9f, 0x53, 0xc6, 0x8f, 0x07, 0x38, 0x61, 0x1e, 0xf9, 0x72, 0x48, 0x18, 0x47,
+	0xbb, 0xb0, 0xd8, 0xc3, 0xdc, 0x3f, 0x33, 0x8d, 0x86, 0xd1, 0xaa, 0xb8, 0x9b, 0xf6, 0xcc, 0x12,
+	0xed, 0x8e, 0x98, 0xeb, 0x14, 0x5f, 0x5c, 0xd6, 0xef, 0x78, 0x4a, 0xd8, 0xbc, 0x0b, 0x1b, 0x53,
+	0x51, 0xd8, 0x80, 0x26, 0x8c, 0x34, 0xff, 0x30, 0x60, 0xfd, 0x04, 0x87, 0xd1, 0x4c, 0xec, 0xd7,
+	0x60, 0x85, 0x91, 0x74, 0x14, 0xfa, 0xa4, 0x9b, 0xe0, 0x98, 0xc8, 0x14, 0x65, 0xaf, 0xa2, 0x6d,
+	0x1f, 0xe3, 0x98, 0xa0, 0x87, 0xb0, 0x46, 0x07, 0x44, 0xad, 0x49, 0x89, 0x16, 0xa4, 0x68, 0x75,
+	0x6c, 0x95, 0xb2, 0x27, 0x50, 0xe4, 0x38, 0x60, 0x66, 0xa1, 0x51, 0x68, 0x55, 0xdc, 0x37, 0x72,
+	0x45, 0xe6, 0x13, 0xdb, 0x27, 0x38, 0x60, 0xef, 0x25, 0x3c, 0xbd, 0xf0, 0xa4, 0x9b, 0xf5, 0x16,
+	0x94, 0xc7, 0x26, 0xb4, 0x0e, 0x85, 0x73, 0x72, 0xa1, 0x8b, 0x11, 0xbf, 0x68, 0x13, 0x16, 0x47,
+	0x38, 0x1a, 0x66, 0xb9, 0xd5, 0x60, 0x7f, 0xe1, 0x6d, 0xa3, 0x79, 0x04, 0x1b, 0x53, 0xc1, 0xd5,
+	0x5a, 0x91, 0x03, 0x8b, 0x4c, 0x18, 0x4c, 0x43, 0x56, 0x73, 0x37, 0x57, 0x8d, 0x10, 0x67, 0x3b,
+	0x26, 0x75, 0x4d, 0x0b, 0xcc, 0xf7, 0x09, 0x3f, 0x56, 0xcb, 0x3e, 0xc4, 0x1c, 0x47, 0x34, 0xd0,
+	0xa5, 0x36, 0x7f, 0x2e, 0x40, 0x55, 0xcf, 0x1c, 0xf8, 0x3c, 0x1c, 0x85, 0xfc, 0xe2, 0x36, 0xfb,
+	0x76, 0x00, 0xe5, 0x08, 0x33, 0xde, 0x65, 0x84, 0x24, 0xb2, 0xec, 0x8a, 0x6b, 0xd9, 0x0a, 0x16,
+	0x3b, 0x83, 0xc5, 0x3e, 0xc9, 0x68, 0xea, 0x94, 0x44, 0x39, 0xdf, 0xfd, 0x59, 0x37, 0xbc, 0x92,
+	0x70, 0x3b, 0x26, 0x24, 0x41, 0xbb, 0xb0, 0x22, 0xca, 0xeb, 0xa6, 0x98, 0x93, 0xee, 0x5e, 0x6c,
+	0x16, 0x1a, 0x46, 0xcb, 0xe8, 0xac, 0x5d, 0x5d, 0xd6, 0x41, 0x2c, 0xc1, 0xc3, 0x9c, 0xec, 0xc5,
+	0x1e, 0xb0, 0xf1, 0xff, 0xac, 0x47, 0x3b, 0x36, 0x8b, 0xf3, 0x1e, 0xed, 0x29, 0x8f, 0x76, 0x8c,
+	0x1e, 0xc3, 0xea, 0x54, 0x8e, 0x76, 0x6c, 0x2e, 0x4a, 0x97, 0xea, 0xd5, 0x65, 0xbd, 0x32, 0x4e,
+	0xd2, 0x8e, 0xbd, 0x0a, 0x9b, 0x0c, 0xd0, 0x36, 0x54, 0x27, 0x4c, 0xf8, 0x74, 0x98, 0x70, 0x73,
+	0xa9, 0x61, 0xb4, 0x0a, 0xde, 0x04, 0x95, 0x43, 0x61, 0x15, 0x42, 0x3f, 0x0a, 0x49, 0xc2, 0xbb,
+	0x23, 0x92, 0x32, 0x01, 0xba, 0xb9, 0xdc, 0x28, 0xb4, 0xca, 0xde, 0x9a, 0x32, 0x7f, 0xa2, 0xad,
+	0xe8, 0x01, 0x94, 0xcf, 0x28, 0xe3, 0x62, 0x33, 0x99, 0x59, 0x92, 0x92, 0x89, 0x01, 0xb9, 0xb0,
+	0xa2, 0xc3, 0x0c, 0x87, 0x61, 0x9f, 0x99, 0x65, 0x21, 0x50, 0x35, 0x1e, 0x4a, 0xfb, 0xe9, 0xe9,
+	0xd3, 0x23, 0xe6, 0x55, 0x94, 0xe8, 0x54, 0x68, 0x9a, 0x9f, 0xc1, 0xfd, 0x6b, 0x8e, 0x54, 0x03,
+	0xf2, 0x2e, 0x94, 0xf4, 0x59, 0x65, 0x8c, 0xd4, 0xf2, 0x8c, 0xcc, 0x9e, 0xb8, 0xc6, 0x65, 0xec,
+	0xd5, 0xfc, 0xc5, 0x80, 0x7b, 0x22, 0xfe, 0x00, 0x27, 0x1f, 0x11, 0x9e, 0x86, 0xfe, 0xab, 0xdc,
+	0xa9, 0x77, 0xa0, 0x14, 0x51, 0x7a, 0xde, 0xc3, 0xfe, 0xb9, 0x46, 0xe3, 0xfe, 0x1c, 0x1a, 0x47,
+	0xba, 0x8f, 0x28, 0x32, 0x7e, 0x54, 0x64, 0x68, 0x27, 0x11, 0x80, 0x24, 0xfd, 0xae, 0x68, 0x46,
+	0x92, 0x8a, 0xdb, 0xb2, 0xb5, 0x4c, 0x92, 0xbe, 0xb0, 0x37, 0x7f, 0x2a, 0x40, 0x65, 0xaa, 0xf6,
+	0xff, 0xb0, 0x11, 0xfc, 0x1f, 0xca, 0x12, 0xa8, 0xf3, 0x30, 0xe9, 0xcb, 0xda, 0xca, 0x5e, 0x49,
+	0x18, 0x3e, 0x08, 0x93, 0x3e, 0x7a, 0x06, 0xd0, 0x0f, 0x63, 0x92, 0x28, 0x14, 0x8a, 0x72, 0xe7,
+	0x77, 0xae, 0xb9, 0x9d, 0xba, 0x2c, 0xfb, 0x68, 0x2c, 0x56, 0xcd, 0x62, 0xca, 0x5b, 0xf4, 0x04,
+	0x1f, 0x47, 0x11, 0x93, 0xc4, 0x16, 0x3d, 0x35, 0x40, 0x5b, 0xb0, 0x44, 0xd2, 0x94, 0xa6, 0x4c,
+	0x12, 0x59, 0xf4, 0xf4, 0x08, 0x7d, 0x08, 0xd5, 0x08, 0x73, 0x92, 0xf8, 0x17, 0xdd, 0xde, 0xd0,
+	0x3f, 0x27, 0x5c, 0x91, 0x78, 0xcb, 0x9d, 0x5f, 0xd3, 0xbe, 0x1d, 0xe5, 0x2a, 0xf6, 0x22, 0x8b,
+	0x26, 0xf1, 0x57, 0xcc, 0x16, 0xbd, 0x55, 0x6d, 0x95, 0xf4, 0x33, 0xeb, 0x09, 0x54, 0x73, 0x2b,
+	0x78, 0xa5, 0xde, 0x76, 0x02, 0x5b, 0x79, 0xc4, 0x34, 0xbf, 0xfb, 0xb0, 0x1c, 0x2b, 0x93, 0xc6,
+	0xd7, 0xba, 0x79, 0x13, 0x35, 0xba, 0x99, 0x83, 0xfb, 0x35, 0xac, 0x1f, 0x66, 0xaf, 0xa6, 0xa6,
+	0x1c, 0x9d, 0x41, 0x79, 0xfc, 0x62, 0xa0, 0x7a, 0x2e, 0x56, 0xfe, 0x45, 0xb2, 0x1a, 0x37, 0x0b,
+	0xf4, 0x63, 0x63, 0x7e, 0xfb, 0xfb, 0x3f, 0x3f, 0x2c, 0xa0, 0xe6, 0xaa, 0x7c, 0x56, 0x47, 0xae,
+	0x23, 0xdb, 0xec, 0xbe, 0xb1, 0xe3, 0x12, 0xa8, 0x0a, 0xa9, 0xec, 0xd9, 0x3a, 0xb9, 0x07, 0xe5,
+	0x71, 0x0b, 0x9f, 0x4b, 0x9e, 0x7f, 0x39, 0xac, 0xc6, 0xcd, 0x02, 0x9d, 0xfc, 0xce, 0xae, 0xe1,
+	0x7e, 0x63, 0xc0, 0xbd, 0xd9, 0xbb, 0x3f, 0x59, 0xea, 0xc6, 0x5c, 0x5f, 0x40, 0xdb, 0xb9, 0xa0,
+	0x37, 0x3d, 0x06, 0x56, 0xeb, 0xe5, 0xc2, 0xac, 0x0a, 0x77, 0x08, 0x68, 0xea, 0x18, 0xb2, 0xfc,
+	0x5d, 0x58, 0x9b, 0x3d, 0x54, 0xf4, 0xfa, 0x35, 0x31, 0xe7, 0xda, 0x8a, 0xf5, 0xf0, 0x25, 0xaa,
+	0x2c, 0x6d, 0x67, 0xf4, 0xfd, 0x41, 0x07, 0x2d, 0xba, 0x85, 0x3d, 0x7b, 0x77, 0x67, 0xc1, 0x58,
+	0x48, 0xdb, 0x00, 0xcf, 0xa4, 0x67, 0xe3, 0xe0, 0xf9, 0x53, 0xb4, 0x7d, 0xc6, 0xf9, 0x80, 0xed,
+	0x3b, 0x4e, 0x10, 0xf2, 0xb3, 0x61, 0xcf, 0xf6, 0x69, 0xec, 0xa8, 0xc0, 0x3c, 0xc5, 0x7e, 0x98,
+	0x04, 0x7a, 0xf4, 0xe2, 0xaa, 0x66, 0xfc, 0x76, 0x55, 0x33, 0xfe, 0xba, 0xaa, 0x19, 0xbf, 0xfe,
+	0x5d, 0x33, 0xe0, 0x7f, 0x21, 0xb5, 0x67, 0x84, 0xba, 0x90, 0x4f, 0x97, 0xd4, 0xb7, 0xb7, 0x24,
+	0x2f, 0xd0, 0xe3, 0x7f, 0x07, 0x00, 0x48, 0x07, 0x91, 0xb6, 0x9b, 0x09, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// CollectorServiceClient is the client API for CollectorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CollectorServiceClient interface {
+	PostSpans(ctx context.Context, in *PostSpansRequest, opts ...grpc.CallOption) (*PostSpansResponse, error)
+}
+
+type collectorServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCollectorServiceClient(cc *grpc.ClientConn) CollectorServiceClient {
+	return &collectorServiceClient{cc}
+}
+
+func (c *collectorServiceClient) PostSpans(ctx context.Context, in *PostSpansRequest, opts ...grpc.CallOption) (*PostSpansResponse, error) {
+	out := new(PostSpansResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.CollectorService/PostSpans", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CollectorServiceServer is the server API for CollectorService service.
+type CollectorServiceServer interface {
+	PostSpans(context.Context, *PostSpansRequest) (*PostSpansResponse, error)
+}
+
+func RegisterCollectorServiceServer(s *grpc.Server, srv CollectorServiceServer) {
+	s.RegisterService(&_CollectorService_serviceDesc, srv)
+}
+
+func _CollectorService_PostSpans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostSpansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollectorServiceServer).PostSpans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.CollectorService/PostSpans",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollectorServiceServer).PostSpans(ctx, req.(*PostSpansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _CollectorService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "jaeger.api_v2.CollectorService",
+	HandlerType: (*CollectorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PostSpans",
+			Handler:    _CollectorService_PostSpans_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api_v2/collector.proto",
+}
+
+// SpanTailServiceClient is the client API for SpanTailService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SpanTailServiceClient interface {
+	TailSpans(ctx context.Context, in *TailSpansRequest, opts ...grpc.CallOption) (SpanTailService_TailSpansClient, error)
+}
+
+type spanTailServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSpanTailServiceClient(cc *grpc.ClientConn) SpanTailServiceClient {
+	return &spanTailServiceClient{cc}
+}
+
+func (c *spanTailServiceClient) TailSpans(ctx context.Context, in *TailSpansRequest, opts ...grpc.CallOption) (SpanTailService_TailSpansClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_SpanTailService_serviceDesc.Streams[0], "/jaeger.api_v2.SpanTailService/TailSpans", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &spanTailServiceTailSpansClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SpanTailService_TailSpansClient interface {
+	Recv() (*TailSpansResponse, error)
+	grpc.ClientStream
+}
+
+type spanTailServiceTailSpansClient struct {
+	grpc.ClientStream
+}
+
+func (x *spanTailServiceTailSpansClient) Recv() (*TailSpansResponse, error) {
+	m := new(TailSpansResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SpanTailServiceServer is the server API for SpanTailService service.
+type SpanTailServiceServer interface {
+	TailSpans(*TailSpansRequest, SpanTailService_TailSpansServer) error
+}
+
+func RegisterSpanTailServiceServer(s *grpc.Server, srv SpanTailServiceServer) {
+	s.RegisterService(&_SpanTailService_serviceDesc, srv)
+}
+
+func _SpanTailService_TailSpans_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailSpansRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SpanTailServiceServer).TailSpans(m, &spanTailServiceTailSpansServer{stream})
+}
+
+type SpanTailService_TailSpansServer interface {
+	Send(*TailSpansResponse) error
+	grpc.ServerStream
+}
+
+type spanTailServiceTailSpansServer struct {
+	grpc.ServerStream
+}
+
+func (x *spanTailServiceTailSpansServer) Send(m *TailSpansResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _SpanTailService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "jaeger.api_v2.SpanTailService",
+	HandlerType: (*SpanTailServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TailSpans",
+			Handler:       _SpanTailService_TailSpans_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api_v2/collector.proto",
+}
+
+// ServiceCatalogServiceClient is the client API for ServiceCatalogService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ServiceCatalogServiceClient interface {
+	GetServiceCatalog(ctx context.Context, in *GetServiceCatalogRequest, opts ...grpc.CallOption) (*GetServiceCatalogResponse, error)
+}
+
+type serviceCatalogServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewServiceCatalogServiceClient(cc *grpc.ClientConn) ServiceCatalogServiceClient {
+	return &serviceCatalogServiceClient{cc}
+}
+
+func (c *serviceCatalogServiceClient) GetServiceCatalog(ctx context.Context, in *GetServiceCatalogRequest, opts ...grpc.CallOption) (*GetServiceCatalogResponse, error) {
+	out := new(GetServiceCatalogResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.ServiceCatalogService/GetServiceCatalog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ServiceCatalogServiceServer is the server API for ServiceCatalogService service.
+type ServiceCatalogServiceServer interface {
+	GetServiceCatalog(context.Context, *GetServiceCatalogRequest) (*GetServiceCatalogResponse, error)
+}
+
+func RegisterServiceCatalogServiceServer(s *grpc.Server, srv ServiceCatalogServiceServer) {
+	s.RegisterService(&_ServiceCatalogService_serviceDesc, srv)
+}
+
+func _ServiceCatalogService_GetServiceCatalog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceCatalogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceCatalogServiceServer).GetServiceCatalog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.ServiceCatalogService/GetServiceCatalog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceCatalogServiceServer).GetServiceCatalog(ctx, req.(*GetServiceCatalogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ServiceCatalogService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "jaeger.api_v2.ServiceCatalogService",
+	HandlerType: (*ServiceCatalogServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetServiceCatalog",
+			Handler:    _ServiceCatalogService_GetServiceCatalog_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api_v2/collector.proto",
+}
+
+// SpanMetricsServiceClient is the client API for SpanMetricsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SpanMetricsServiceClient interface {
+	GetSpanMetrics(ctx context.Context, in *GetSpanMetricsRequest, opts ...grpc.CallOption) (*GetSpanMetricsResponse, error)
+}
+
+type spanMetricsServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSpanMetricsServiceClient(cc *grpc.ClientConn) SpanMetricsServiceClient {
+	return &spanMetricsServiceClient{cc}
+}
+
+func (c *spanMetricsServiceClient) GetSpanMetrics(ctx context.Context, in *GetSpanMetricsRequest, opts ...grpc.CallOption) (*GetSpanMetricsResponse, error) {
+	out := new(GetSpanMetricsResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.SpanMetricsService/GetSpanMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SpanMetricsServiceServer is the server API for SpanMetricsService service.
+type SpanMetricsServiceServer interface {
+	GetSpanMetrics(context.Context, *GetSpanMetricsRequest) (*GetSpanMetricsResponse, error)
+}
+
+func RegisterSpanMetricsServiceServer(s *grpc.Server, srv SpanMetricsServiceServer) {
+	s.RegisterService(&_SpanMetricsService_serviceDesc, srv)
+}
+
+func _SpanMetricsService_GetSpanMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSpanMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpanMetricsServiceServer).GetSpanMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.SpanMetricsService/GetSpanMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpanMetricsServiceServer).GetSpanMetrics(ctx, req.(*GetSpanMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _SpanMetricsService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "jaeger.api_v2.SpanMetricsService",
+	HandlerType: (*SpanMetricsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSpanMetrics",
+			Handler:    _SpanMetricsService_GetSpanMetrics_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api_v2/collector.proto",
+}
+
+func (m *PostSpansRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PostSpansRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintCollector(dAtA, i, uint64(m.Batch.Size()))
+	n1, err := m.Batch.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n1
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *PostSpansResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PostSpansResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TailSpansRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TailSpansRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ServiceName) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCollector(dAtA, i, uint64(len(m.ServiceName)))
+		i += copy(dAtA[i:], m.ServiceName)
+	}
+	if len(m.OperationName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCollector(dAtA, i, uint64(len(m.OperationName)))
+		i += copy(dAtA[i:], m.OperationName)
+	}
+	if len(m.Tags) > 0 {
+		for k, _ := range m.Tags {
+			dAtA[i] = 0x1a
+			i++
+			v := m.Tags[k]
+			mapSize := 1 + len(k) + sovCollector(uint64(len(k))) + 1 + len(v) + sovCollector(uint64(len(v)))
+			i = encodeVarintCollector(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintCollector(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintCollector(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TailSpansResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TailSpansResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Spans) > 0 {
+		for _, msg := range m.Spans {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintCollector(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *GetServiceCatalogRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetServiceCatalogRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ServiceActivity) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ServiceActivity) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ServiceName) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCollector(dAtA, i, uint64(len(m.ServiceName)))
+		i += copy(dAtA[i:], m.ServiceName)
+	}
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintCollector(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.LastSeen)))
+	n2, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.LastSeen, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n2
+	if m.SpanRate1m != 0 {
+		dAtA[i] = 0x19
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.SpanRate1m))))
+		i += 8
+	}
+	if m.SpanRate5m != 0 {
+		dAtA[i] = 0x21
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.SpanRate5m))))
+		i += 8
+	}
+	if m.SpanRate15m != 0 {
+		dAtA[i] = 0x29
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.SpanRate15m))))
+		i += 8
+	}
+	if m.OperationCount != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintCollector(dAtA, i, uint64(m.OperationCount))
+	}
+	if len(m.ClientVersions) > 0 {
+		for _, s := range m.ClientVersions {
+			dAtA[i] = 0x3a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Hostnames) > 0 {
+		for _, s := range m.Hostnames {
+			dAtA[i] = 0x42
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.ClientUUIDs) > 0 {
+		for _, s := range m.ClientUUIDs {
+			dAtA[i] = 0x4a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *GetServiceCatalogResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetServiceCatalogResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Services) > 0 {
+		for _, msg := range m.Services {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintCollector(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *GetSpanMetricsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetSpanMetricsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ServiceName) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCollector(dAtA, i, uint64(len(m.ServiceName)))
+		i += copy(dAtA[i:], m.ServiceName)
+	}
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintCollector(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.Lookback)))
+	n3, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.Lookback, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n3
+	dAtA[i] = 0x1a
+	i++
+	i = encodeVarintCollector(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime)))
+	n4, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EndTime, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n4
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *SpanMetrics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetrics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ServiceName) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCollector(dAtA, i, uint64(len(m.ServiceName)))
+		i += copy(dAtA[i:], m.ServiceName)
+	}
+	if len(m.OperationName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCollector(dAtA, i, uint64(len(m.OperationName)))
+		i += copy(dAtA[i:], m.OperationName)
+	}
+	if len(m.SpanKind) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCollector(dAtA, i, uint64(len(m.SpanKind)))
+		i += copy(dAtA[i:], m.SpanKind)
+	}
+	if len(m.Dimensions) > 0 {
+		for k, _ := range m.Dimensions {
+			dAtA[i] = 0x22
+			i++
+			v := m.Dimensions[k]
+			mapSize := 1 + len(k) + sovCollector(uint64(len(k))) + 1 + len(v) + sovCollector(uint64(len(v)))
+			i = encodeVarintCollector(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintCollector(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintCollector(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	if m.Calls != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintCollector(dAtA, i, uint64(m.Calls))
+	}
+	if m.Errors != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintCollector(dAtA, i, uint64(m.Errors))
+	}
+	if len(m.LatencyBuckets) > 0 {
+		for _, msg := range m.LatencyBuckets {
+			dAtA[i] = 0x3a
+			i++
+			i = encodeVarintCollector(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(msg)))
+			n, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(msg, dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.LatencyCounts) > 0 {
+		dAtA6 := make([]byte, len(m.LatencyCounts)*10)
+		var j5 int
+		for _, num := range m.LatencyCounts {
+			for num >= 1<<7 {
+				dAtA6[j5] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j5++
+			}
+			dAtA6[j5] = uint8(num)
+			j5++
+		}
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintCollector(dAtA, i, uint64(j5))
+		i += copy(dAtA[i:], dAtA6[:j5])
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *GetSpanMetricsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetSpanMetricsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Metrics) > 0 {
+		for _, msg := range m.Metrics {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintCollector(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func encodeVarintCollector(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *PostSpansRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Batch.Size()
+	n += 1 + l + sovCollector(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *PostSpansResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TailSpansRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovCollector(uint64(l))
+	}
+	l = len(m.OperationName)
+	if l > 0 {
+		n += 1 + l + sovCollector(uint64(l))
+	}
+	if len(m.Tags) > 0 {
+		for k, v := range m.Tags {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovCollector(uint64(len(k))) + 1 + len(v) + sovCollector(uint64(len(v)))
+			n += mapEntrySize + 1 + sovCollector(uint64(mapEntrySize))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TailSpansResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Spans) > 0 {
+		for _, e := range m.Spans {
+			l = e.Size()
+			n += 1 + l + sovCollector(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GetServiceCatalogRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ServiceActivity) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovCollector(uint64(l))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.LastSeen)
+	n += 1 + l + sovCollector(uint64(l))
+	if m.SpanRate1m != 0 {
+		n += 9
+	}
+	if m.SpanRate5m != 0 {
+		n += 9
+	}
+	if m.SpanRate15m != 0 {
+		n += 9
+	}
+	if m.OperationCount != 0 {
+		n += 1 + sovCollector(uint64(m.OperationCount))
+	}
+	if len(m.ClientVersions) > 0 {
+		for _, s := range m.ClientVersions {
+			l = len(s)
+			n += 1 + l + sovCollector(uint64(l))
+		}
+	}
+	if len(m.Hostnames) > 0 {
+		for _, s := range m.Hostnames {
+			l = len(s)
+			n += 1 + l + sovCollector(uint64(l))
+		}
+	}
+	if len(m.ClientUUIDs) > 0 {
+		for _, s := range m.ClientUUIDs {
+			l = len(s)
+			n += 1 + l + sovCollector(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GetServiceCatalogResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Services) > 0 {
+		for _, e := range m.Services {
+			l = e.Size()
+			n += 1 + l + sovCollector(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GetSpanMetricsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovCollector(uint64(l))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.Lookback)
+	n += 1 + l + sovCollector(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime)
+	n += 1 + l + sovCollector(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SpanMetrics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovCollector(uint64(l))
+	}
+	l = len(m.OperationName)
+	if l > 0 {
+		n += 1 + l + sovCollector(uint64(l))
+	}
+	l = len(m.SpanKind)
+	if l > 0 {
+		n += 1 + l + sovCollector(uint64(l))
+	}
+	if len(m.Dimensions) > 0 {
+		for k, v := range m.Dimensions {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovCollector(uint64(len(k))) + 1 + len(v) + sovCollector(uint64(len(v)))
+			n += mapEntrySize + 1 + sovCollector(uint64(mapEntrySize))
+		}
+	}
+	if m.Calls != 0 {
+		n += 1 + sovCollector(uint64(m.Calls))
+	}
+	if m.Errors != 0 {
+		n += 1 + sovCollector(uint64(m.Errors))
+	}
+	if len(m.LatencyBuckets) > 0 {
+		for _, e := range m.LatencyBuckets {
+			l = github_com_gogo_protobuf_types.SizeOfStdDuration(e)
+			n += 1 + l + sovCollector(uint64(l))
+		}
+	}
+	if len(m.LatencyCounts) > 0 {
+		l = 0
+		for _, e := range m.LatencyCounts {
+			l += sovCollector(uint64(e))
+		}
+		n += 1 + sovCollector(uint64(l)) + l
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GetSpanMetricsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
 	_ = l
+	if len(m.Metrics) > 0 {
+		for _, e := range m.Metrics {
+			l = e.Size()
+			n += 1 + l + sovCollector(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
-	return n
-}
+	return n
+}
+
+func sovCollector(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozCollector(x uint64) (n int) {
+	return sovCollector(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *PostSpansRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCollector
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PostSpansRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PostSpansRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Batch", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Batch.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCollector(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PostSpansResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCollector
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PostSpansResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PostSpansResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCollector(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TailSpansRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCollector
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TailSpansRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TailSpansRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OperationName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Tags == nil {
+				m.Tags = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowCollector
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowCollector
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthCollector
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthCollector
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowCollector
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthCollector
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthCollector
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipCollector(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthCollector
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Tags[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCollector(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TailSpansResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCollector
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TailSpansResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TailSpansResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Spans = append(m.Spans, model.Span{})
+			if err := m.Spans[len(m.Spans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCollector(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetServiceCatalogRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCollector
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetServiceCatalogRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetServiceCatalogRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCollector(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ServiceActivity) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCollector
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ServiceActivity: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ServiceActivity: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastSeen", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.LastSeen, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanRate1m", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.SpanRate1m = float64(math.Float64frombits(v))
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanRate5m", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.SpanRate5m = float64(math.Float64frombits(v))
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanRate15m", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.SpanRate15m = float64(math.Float64frombits(v))
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationCount", wireType)
+			}
+			m.OperationCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.OperationCount |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientVersions", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientVersions = append(m.ClientVersions, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hostnames", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hostnames = append(m.Hostnames, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientUUIDs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientUUIDs = append(m.ClientUUIDs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCollector(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetServiceCatalogResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCollector
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetServiceCatalogResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetServiceCatalogResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Services", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Services = append(m.Services, ServiceActivity{})
+			if err := m.Services[len(m.Services)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCollector(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetSpanMetricsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCollector
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetSpanMetricsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetSpanMetricsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Lookback", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.Lookback, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.EndTime, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCollector(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
 
-func sovCollector(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozCollector(x uint64) (n int) {
-	return sovCollector(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *PostSpansRequest) Unmarshal(dAtA []byte) error {
+func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -343,15 +2821,111 @@ func (m *PostSpansRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PostSpansRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanMetrics: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PostSpansRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Batch", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OperationName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanKind", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpanKind = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dimensions", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -378,10 +2952,252 @@ func (m *PostSpansRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Batch.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Dimensions == nil {
+				m.Dimensions = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowCollector
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowCollector
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthCollector
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthCollector
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowCollector
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthCollector
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthCollector
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipCollector(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthCollector
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Dimensions[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Calls", wireType)
+			}
+			m.Calls = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Calls |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
+			}
+			m.Errors = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Errors |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LatencyBuckets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LatencyBuckets = append(m.LatencyBuckets, time.Duration(0))
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&(m.LatencyBuckets[len(m.LatencyBuckets)-1]), dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 8:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowCollector
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.LatencyCounts = append(m.LatencyCounts, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowCollector
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthCollector
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthCollector
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.LatencyCounts) == 0 {
+					m.LatencyCounts = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowCollector
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.LatencyCounts = append(m.LatencyCounts, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field LatencyCounts", wireType)
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCollector(dAtA[iNdEx:])
@@ -407,7 +3223,7 @@ func (m *PostSpansRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PostSpansResponse) Unmarshal(dAtA []byte) error {
+func (m *GetSpanMetricsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -430,12 +3246,46 @@ func (m *PostSpansResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PostSpansResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: GetSpanMetricsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PostSpansResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: GetSpanMetricsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCollector
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCollector
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCollector
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metrics = append(m.Metrics, SpanMetrics{})
+			if err := m.Metrics[len(m.Metrics)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCollector(dAtA[iNdEx:])
@@ -35,10 +35,12 @@ var _ = time.Kitchen
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
 type GetTraceRequest struct {
-	TraceID              github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_id"`
-	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
-	XXX_unrecognized     []byte                                        `json:"-"`
-	XXX_sizecache        int32                                         `json:"-"`
+	TraceID github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_id"`
+	// full requests all the spans of the trace, regardless of the span limit of the query service.
+	Full                 bool     `protobuf:"varint,2,opt,name=full,proto3" json:"full,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *GetTraceRequest) Reset()         { *m = GetTraceRequest{} }
@@ -74,18 +76,174 @@ func (m *GetTraceRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_GetTraceRequest proto.InternalMessageInfo
 
+func (m *GetTraceRequest) GetFull() bool {
+	if m != nil {
+		return m.Full
+	}
+	return false
+}
+
+// OmittedSpans counts the spans of an operation omitted from a trace.
+type OmittedSpans struct {
+	ServiceName          string   `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	OperationName        string   `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
+	Spans                int64    `protobuf:"varint,3,opt,name=spans,proto3" json:"spans,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OmittedSpans) Reset()         { *m = OmittedSpans{} }
+func (m *OmittedSpans) String() string { return proto.CompactTextString(m) }
+func (*OmittedSpans) ProtoMessage()    {}
+func (*OmittedSpans) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{1}
+}
+func (m *OmittedSpans) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OmittedSpans) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_OmittedSpans.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *OmittedSpans) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OmittedSpans.Merge(m, src)
+}
+func (m *OmittedSpans) XXX_Size() int {
+	return m.Size()
+}
+func (m *OmittedSpans) XXX_DiscardUnknown() {
+	xxx_messageInfo_OmittedSpans.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OmittedSpans proto.InternalMessageInfo
+
+func (m *OmittedSpans) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
+	}
+	return ""
+}
+
+func (m *OmittedSpans) GetOperationName() string {
+	if m != nil {
+		return m.OperationName
+	}
+	return ""
+}
+
+func (m *OmittedSpans) GetSpans() int64 {
+	if m != nil {
+		return m.Spans
+	}
+	return 0
+}
+
+// TraceSummary describes how a trace exceeding the span limit of the query service was reduced.
+type TraceSummary struct {
+	TotalSpans    int64 `protobuf:"varint,1,opt,name=total_spans,json=totalSpans,proto3" json:"total_spans,omitempty"`
+	ReturnedSpans int64 `protobuf:"varint,2,opt,name=returned_spans,json=returnedSpans,proto3" json:"returned_spans,omitempty"`
+	// collapsed_spans is the number of spans removed by collapsing repetitive sibling spans into placeholder spans.
+	CollapsedSpans int64 `protobuf:"varint,3,opt,name=collapsed_spans,json=collapsedSpans,proto3" json:"collapsed_spans,omitempty"`
+	// omitted are the spans left out once the span limit was reached, by decreasing count.
+	Omitted []OmittedSpans `protobuf:"bytes,4,rep,name=omitted,proto3" json:"omitted"`
+	// truncated is true if the trace has more spans than the query service reads, which total_spans does not count.
+	Truncated            bool     `protobuf:"varint,5,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TraceSummary) Reset()         { *m = TraceSummary{} }
+func (m *TraceSummary) String() string { return proto.CompactTextString(m) }
+func (*TraceSummary) ProtoMessage()    {}
+func (*TraceSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{2}
+}
+func (m *TraceSummary) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TraceSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceSummary.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TraceSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSummary.Merge(m, src)
+}
+func (m *TraceSummary) XXX_Size() int {
+	return m.Size()
+}
+func (m *TraceSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceSummary proto.InternalMessageInfo
+
+func (m *TraceSummary) GetTotalSpans() int64 {
+	if m != nil {
+		return m.TotalSpans
+	}
+	return 0
+}
+
+func (m *TraceSummary) GetReturnedSpans() int64 {
+	if m != nil {
+		return m.ReturnedSpans
+	}
+	return 0
+}
+
+func (m *TraceSummary) GetCollapsedSpans() int64 {
+	if m != nil {
+		return m.CollapsedSpans
+	}
+	return 0
+}
+
+func (m *TraceSummary) GetOmitted() []OmittedSpans {
+	if m != nil {
+		return m.Omitted
+	}
+	return nil
+}
+
+func (m *TraceSummary) GetTruncated() bool {
+	if m != nil {
+		return m.Truncated
+	}
+	return false
+}
+
 type SpansResponseChunk struct {
-	Spans                []model.Span `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Spans []model.Span `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans"`
+	// summary is set in the first chunk of GetTrace if the trace was reduced to the span limit.
+	Summary              *TraceSummary `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *SpansResponseChunk) Reset()         { *m = SpansResponseChunk{} }
 func (m *SpansResponseChunk) String() string { return proto.CompactTextString(m) }
 func (*SpansResponseChunk) ProtoMessage()    {}
 func (*SpansResponseChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{1}
+	return fileDescriptor_26651706f9f8a4f0, []int{3}
 }
 func (m *SpansResponseChunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -121,6 +279,13 @@ func (m *SpansResponseChunk) GetSpans() []model.Span {
 	return nil
 }
 
+func (m *SpansResponseChunk) GetSummary() *TraceSummary {
+	if m != nil {
+		return m.Summary
+	}
+	return nil
+}
+
 type ArchiveTraceRequest struct {
 	TraceID              github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_id"`
 	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
@@ -132,7 +297,7 @@ func (m *ArchiveTraceRequest) Reset()         { *m = ArchiveTraceRequest{} }
 func (m *ArchiveTraceRequest) String() string { return proto.CompactTextString(m) }
 func (*ArchiveTraceRequest) ProtoMessage()    {}
 func (*ArchiveTraceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{2}
+	return fileDescriptor_26651706f9f8a4f0, []int{4}
 }
 func (m *ArchiveTraceRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -171,7 +336,7 @@ func (m *ArchiveTraceResponse) Reset()         { *m = ArchiveTraceResponse{} }
 func (m *ArchiveTraceResponse) String() string { return proto.CompactTextString(m) }
 func (*ArchiveTraceResponse) ProtoMessage()    {}
 func (*ArchiveTraceResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{3}
+	return fileDescriptor_26651706f9f8a4f0, []int{5}
 }
 func (m *ArchiveTraceResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -201,24 +366,31 @@ func (m *ArchiveTraceResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_ArchiveTraceResponse proto.InternalMessageInfo
 
 type TraceQueryParameters struct {
-	ServiceName          string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
-	OperationName        string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
-	Tags                 map[string]string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	StartTimeMin         time.Time         `protobuf:"bytes,4,opt,name=start_time_min,json=startTimeMin,proto3,stdtime" json:"start_time_min"`
-	StartTimeMax         time.Time         `protobuf:"bytes,5,opt,name=start_time_max,json=startTimeMax,proto3,stdtime" json:"start_time_max"`
-	DurationMin          time.Duration     `protobuf:"bytes,6,opt,name=duration_min,json=durationMin,proto3,stdduration" json:"duration_min"`
-	DurationMax          time.Duration     `protobuf:"bytes,7,opt,name=duration_max,json=durationMax,proto3,stdduration" json:"duration_max"`
-	SearchDepth          int32             `protobuf:"varint,8,opt,name=search_depth,json=searchDepth,proto3" json:"search_depth,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	ServiceName   string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	OperationName string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
+	Tags          map[string]string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	StartTimeMin  time.Time         `protobuf:"bytes,4,opt,name=start_time_min,json=startTimeMin,proto3,stdtime" json:"start_time_min"`
+	StartTimeMax  time.Time         `protobuf:"bytes,5,opt,name=start_time_max,json=startTimeMax,proto3,stdtime" json:"start_time_max"`
+	DurationMin   time.Duration     `protobuf:"bytes,6,opt,name=duration_min,json=durationMin,proto3,stdduration" json:"duration_min"`
+	DurationMax   time.Duration     `protobuf:"bytes,7,opt,name=duration_max,json=durationMax,proto3,stdduration" json:"duration_max"`
+	SearchDepth   int32             `protobuf:"varint,8,opt,name=search_depth,json=searchDepth,proto3" json:"search_depth,omitempty"`
+	// Opaque continuation token of the previous page of results. The token for the
+	// next page is returned in the "next-page-token" header of the FindTraces response.
+	PageToken string `protobuf:"bytes,9,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Alternatives to service_name and operation_name, a span matches if its service
+	// and its operation are each any of the given ones.
+	ServiceNames         []string `protobuf:"bytes,10,rep,name=service_names,json=serviceNames,proto3" json:"service_names,omitempty"`
+	OperationNames       []string `protobuf:"bytes,11,rep,name=operation_names,json=operationNames,proto3" json:"operation_names,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *TraceQueryParameters) Reset()         { *m = TraceQueryParameters{} }
 func (m *TraceQueryParameters) String() string { return proto.CompactTextString(m) }
 func (*TraceQueryParameters) ProtoMessage()    {}
 func (*TraceQueryParameters) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{4}
+	return fileDescriptor_26651706f9f8a4f0, []int{6}
 }
 func (m *TraceQueryParameters) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -303,6 +475,27 @@ func (m *TraceQueryParameters) GetSearchDepth() int32 {
 	return 0
 }
 
+func (m *TraceQueryParameters) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *TraceQueryParameters) GetServiceNames() []string {
+	if m != nil {
+		return m.ServiceNames
+	}
+	return nil
+}
+
+func (m *TraceQueryParameters) GetOperationNames() []string {
+	if m != nil {
+		return m.OperationNames
+	}
+	return nil
+}
+
 type FindTracesRequest struct {
 	Query                *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
@@ -314,7 +507,7 @@ func (m *FindTracesRequest) Reset()         { *m = FindTracesRequest{} }
 func (m *FindTracesRequest) String() string { return proto.CompactTextString(m) }
 func (*FindTracesRequest) ProtoMessage()    {}
 func (*FindTracesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{5}
+	return fileDescriptor_26651706f9f8a4f0, []int{7}
 }
 func (m *FindTracesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -360,7 +553,7 @@ func (m *GetServicesRequest) Reset()         { *m = GetServicesRequest{} }
 func (m *GetServicesRequest) String() string { return proto.CompactTextString(m) }
 func (*GetServicesRequest) ProtoMessage()    {}
 func (*GetServicesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{6}
+	return fileDescriptor_26651706f9f8a4f0, []int{8}
 }
 func (m *GetServicesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -400,7 +593,7 @@ func (m *GetServicesResponse) Reset()         { *m = GetServicesResponse{} }
 func (m *GetServicesResponse) String() string { return proto.CompactTextString(m) }
 func (*GetServicesResponse) ProtoMessage()    {}
 func (*GetServicesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{7}
+	return fileDescriptor_26651706f9f8a4f0, []int{9}
 }
 func (m *GetServicesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -448,7 +641,7 @@ func (m *GetOperationsRequest) Reset()         { *m = GetOperationsRequest{} }
 func (m *GetOperationsRequest) String() string { return proto.CompactTextString(m) }
 func (*GetOperationsRequest) ProtoMessage()    {}
 func (*GetOperationsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{8}
+	return fileDescriptor_26651706f9f8a4f0, []int{10}
 }
 func (m *GetOperationsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -503,7 +696,7 @@ func (m *Operation) Reset()         { *m = Operation{} }
 func (m *Operation) String() string { return proto.CompactTextString(m) }
 func (*Operation) ProtoMessage()    {}
 func (*Operation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{9}
+	return fileDescriptor_26651706f9f8a4f0, []int{11}
 }
 func (m *Operation) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -558,7 +751,7 @@ func (m *GetOperationsResponse) Reset()         { *m = GetOperationsResponse{} }
 func (m *GetOperationsResponse) String() string { return proto.CompactTextString(m) }
 func (*GetOperationsResponse) ProtoMessage()    {}
 func (*GetOperationsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{10}
+	return fileDescriptor_26651706f9f8a4f0, []int{12}
 }
 func (m *GetOperationsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -602,18 +795,20 @@ func (m *GetOperationsResponse) GetOperations() []*Operation {
 }
 
 type GetDependenciesRequest struct {
-	StartTime            time.Time `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3,stdtime" json:"start_time"`
-	EndTime              time.Time `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3,stdtime" json:"end_time"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	StartTime time.Time `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3,stdtime" json:"start_time"`
+	EndTime   time.Time `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3,stdtime" json:"end_time"`
+	// detailed requests the links split by operation and kind, with their error counts and latency percentiles.
+	Detailed             bool     `protobuf:"varint,3,opt,name=detailed,proto3" json:"detailed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *GetDependenciesRequest) Reset()         { *m = GetDependenciesRequest{} }
 func (m *GetDependenciesRequest) String() string { return proto.CompactTextString(m) }
 func (*GetDependenciesRequest) ProtoMessage()    {}
 func (*GetDependenciesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{11}
+	return fileDescriptor_26651706f9f8a4f0, []int{13}
 }
 func (m *GetDependenciesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -656,6 +851,13 @@ func (m *GetDependenciesRequest) GetEndTime() time.Time {
 	return time.Time{}
 }
 
+func (m *GetDependenciesRequest) GetDetailed() bool {
+	if m != nil {
+		return m.Detailed
+	}
+	return false
+}
+
 type GetDependenciesResponse struct {
 	Dependencies         []model.DependencyLink `protobuf:"bytes,1,rep,name=dependencies,proto3" json:"dependencies"`
 	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
@@ -667,7 +869,7 @@ func (m *GetDependenciesResponse) Reset()         { *m = GetDependenciesResponse
 func (m *GetDependenciesResponse) String() string { return proto.CompactTextString(m) }
 func (*GetDependenciesResponse) ProtoMessage()    {}
 func (*GetDependenciesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{12}
+	return fileDescriptor_26651706f9f8a4f0, []int{14}
 }
 func (m *GetDependenciesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -706,6 +908,10 @@ func (m *GetDependenciesResponse) GetDependencies() []model.DependencyLink {
 func init() {
 	proto.RegisterType((*GetTraceRequest)(nil), "jaeger.api_v2.GetTraceRequest")
 	golang_proto.RegisterType((*GetTraceRequest)(nil), "jaeger.api_v2.GetTraceRequest")
+	proto.RegisterType((*OmittedSpans)(nil), "jaeger.api_v2.OmittedSpans")
+	golang_proto.RegisterType((*OmittedSpans)(nil), "jaeger.api_v2.OmittedSpans")
+	proto.RegisterType((*TraceSummary)(nil), "jaeger.api_v2.TraceSummary")
+	golang_proto.RegisterType((*TraceSummary)(nil), "jaeger.api_v2.TraceSummary")
 	proto.RegisterType((*SpansResponseChunk)(nil), "jaeger.api_v2.SpansResponseChunk")
 	golang_proto.RegisterType((*SpansResponseChunk)(nil), "jaeger.api_v2.SpansResponseChunk")
 	proto.RegisterType((*ArchiveTraceRequest)(nil), "jaeger.api_v2.ArchiveTraceRequest")
@@ -738,71 +944,90 @@ func init() { proto.RegisterFile("api_v2/query.proto", fileDescriptor_26651706f9
 func init() { golang_proto.RegisterFile("api_v2/query.proto", fileDescriptor_26651706f9f8a4f0) }
 
 var fileDescriptor_26651706f9f8a4f0 = []byte{
-	// 1017 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0x4f, 0x73, 0xdb, 0x44,
-	0x14, 0x47, 0x4e, 0x1c, 0xdb, 0xcf, 0x76, 0x4b, 0xd7, 0x4e, 0x2b, 0x54, 0xb0, 0x1d, 0x85, 0x96,
-	0x4c, 0x87, 0x48, 0xa9, 0x19, 0x86, 0x92, 0x61, 0x06, 0xe2, 0xa6, 0xf5, 0xa4, 0xd0, 0x52, 0xd4,
-	0x9c, 0xe0, 0xe0, 0xd9, 0x58, 0x8b, 0x2c, 0x1c, 0xaf, 0x54, 0x69, 0xed, 0xc6, 0xc3, 0x70, 0xe1,
-	0x13, 0x30, 0x70, 0xe1, 0xc4, 0x95, 0x13, 0xdf, 0x81, 0x63, 0x8f, 0xcc, 0x70, 0xe3, 0x10, 0x98,
-	0xc0, 0x07, 0x61, 0xf6, 0x8f, 0x1c, 0x59, 0xce, 0xa4, 0xa1, 0x07, 0x4e, 0xde, 0x7d, 0xfb, 0xde,
-	0xef, 0xfd, 0xfb, 0xbd, 0x67, 0x01, 0xc2, 0xa1, 0xdf, 0x9b, 0xb4, 0xed, 0xa7, 0x63, 0x12, 0x4d,
-	0xad, 0x30, 0x0a, 0x58, 0x80, 0xaa, 0x5f, 0x61, 0xe2, 0x91, 0xc8, 0x92, 0x4f, 0x46, 0x79, 0x14,
-	0xb8, 0xe4, 0x50, 0xbe, 0x19, 0x75, 0x2f, 0xf0, 0x02, 0x71, 0xb4, 0xf9, 0x49, 0x49, 0x5f, 0xf7,
-	0x82, 0xc0, 0x3b, 0x24, 0x36, 0x0e, 0x7d, 0x1b, 0x53, 0x1a, 0x30, 0xcc, 0xfc, 0x80, 0xc6, 0xea,
-	0xb5, 0xa9, 0x5e, 0xc5, 0xed, 0x60, 0xfc, 0xa5, 0xcd, 0xfc, 0x11, 0x89, 0x19, 0x1e, 0x85, 0x4a,
-	0xa1, 0x91, 0x55, 0x70, 0xc7, 0x91, 0x40, 0x50, 0xef, 0x6f, 0x8b, 0x9f, 0xfe, 0xa6, 0x47, 0xe8,
-	0x66, 0xfc, 0x0c, 0x7b, 0x1e, 0x89, 0xec, 0x20, 0x14, 0x2e, 0x16, 0xdd, 0x99, 0x14, 0x2e, 0x77,
-	0x09, 0xdb, 0x8f, 0x70, 0x9f, 0x38, 0xe4, 0xe9, 0x98, 0xc4, 0x0c, 0x7d, 0x01, 0x45, 0xc6, 0xef,
-	0x3d, 0xdf, 0xd5, 0xb5, 0x96, 0xb6, 0x51, 0xe9, 0x7c, 0xf4, 0xfc, 0xb8, 0xf9, 0xca, 0x1f, 0xc7,
-	0xcd, 0x4d, 0xcf, 0x67, 0x83, 0xf1, 0x81, 0xd5, 0x0f, 0x46, 0xb6, 0x4c, 0x9b, 0x2b, 0xfa, 0xd4,
-	0x53, 0x37, 0x5b, 0x26, 0x2f, 0xd0, 0xf6, 0x76, 0x4f, 0x8e, 0x9b, 0x05, 0x75, 0x74, 0x0a, 0x02,
-	0x71, 0xcf, 0x35, 0xef, 0x01, 0x7a, 0x12, 0x62, 0x1a, 0x3b, 0x24, 0x0e, 0x03, 0x1a, 0x93, 0xbb,
-	0x83, 0x31, 0x1d, 0x22, 0x1b, 0xf2, 0x31, 0x97, 0xea, 0x5a, 0x6b, 0x69, 0xa3, 0xdc, 0xae, 0x59,
-	0x73, 0x45, 0xb5, 0xb8, 0x45, 0x67, 0x99, 0x07, 0xe1, 0x48, 0x3d, 0x33, 0x82, 0xda, 0x4e, 0xd4,
-	0x1f, 0xf8, 0x13, 0xf2, 0xff, 0x85, 0x7e, 0x15, 0xea, 0xf3, 0x3e, 0x65, 0x06, 0xe6, 0xcf, 0xcb,
-	0x50, 0x17, 0x92, 0xcf, 0x38, 0x2d, 0x1e, 0xe3, 0x08, 0x8f, 0x08, 0x23, 0x51, 0x8c, 0xd6, 0xa0,
-	0x12, 0x93, 0x68, 0xe2, 0xf7, 0x49, 0x8f, 0xe2, 0x11, 0x11, 0x11, 0x95, 0x9c, 0xb2, 0x92, 0x3d,
-	0xc2, 0x23, 0x82, 0x6e, 0xc0, 0xa5, 0x20, 0x24, 0xb2, 0x7f, 0x52, 0x29, 0x27, 0x94, 0xaa, 0x33,
-	0xa9, 0x50, 0xdb, 0x81, 0x65, 0x86, 0xbd, 0x58, 0x5f, 0x12, 0xe5, 0xd9, 0xcc, 0x94, 0xe7, 0x2c,
-	0xe7, 0xd6, 0x3e, 0xf6, 0xe2, 0x7b, 0x94, 0x45, 0x53, 0x47, 0x98, 0xa2, 0x07, 0x70, 0x29, 0x66,
-	0x38, 0x62, 0x3d, 0xce, 0xa7, 0xde, 0xc8, 0xa7, 0xfa, 0x72, 0x4b, 0xdb, 0x28, 0xb7, 0x0d, 0x4b,
-	0xf2, 0xc9, 0x4a, 0xf8, 0x64, 0xed, 0x27, 0x84, 0xeb, 0x14, 0x79, 0xf1, 0xbe, 0xfb, 0xb3, 0xa9,
-	0x39, 0x15, 0x61, 0xcb, 0x5f, 0x1e, 0xfa, 0x34, 0x8b, 0x85, 0x8f, 0xf4, 0xfc, 0xcb, 0x61, 0xe1,
-	0x23, 0x74, 0x1f, 0x2a, 0x09, 0x81, 0x45, 0x54, 0x2b, 0x02, 0xe9, 0xb5, 0x05, 0xa4, 0x5d, 0xa5,
-	0x24, 0x81, 0x7e, 0xe4, 0x40, 0xe5, 0xc4, 0x90, 0xc7, 0x34, 0x87, 0x83, 0x8f, 0xf4, 0xc2, 0xcb,
-	0xe0, 0xe0, 0x23, 0xd9, 0x34, 0x1c, 0xf5, 0x07, 0x3d, 0x97, 0x84, 0x6c, 0xa0, 0x17, 0x5b, 0xda,
-	0x46, 0x9e, 0x37, 0x8d, 0xcb, 0x76, 0xb9, 0xc8, 0x78, 0x0f, 0x4a, 0xb3, 0xea, 0xa2, 0x57, 0x61,
-	0x69, 0x48, 0xa6, 0xaa, 0xb7, 0xfc, 0x88, 0xea, 0x90, 0x9f, 0xe0, 0xc3, 0x71, 0xd2, 0x4a, 0x79,
-	0xd9, 0xce, 0xdd, 0xd1, 0xcc, 0x47, 0x70, 0xe5, 0xbe, 0x4f, 0x5d, 0xd1, 0xaf, 0x38, 0xe1, 0xec,
-	0xfb, 0x90, 0x17, 0xfb, 0x44, 0x40, 0x94, 0xdb, 0xeb, 0x17, 0x68, 0xae, 0x23, 0x2d, 0xcc, 0x3a,
-	0xa0, 0x2e, 0x61, 0x4f, 0x24, 0x9f, 0x12, 0x40, 0xf3, 0x36, 0xd4, 0xe6, 0xa4, 0x92, 0xa6, 0xc8,
-	0x80, 0xa2, 0x62, 0x9e, 0x1c, 0xb3, 0x92, 0x33, 0xbb, 0x9b, 0x0f, 0xa1, 0xde, 0x25, 0xec, 0xd3,
-	0x84, 0x73, 0xb3, 0xd8, 0x74, 0x28, 0x28, 0x1d, 0x95, 0x60, 0x72, 0x45, 0xd7, 0xa1, 0xc4, 0x27,
-	0xb1, 0x37, 0xf4, 0xa9, 0xab, 0x12, 0x2d, 0x72, 0xc1, 0xc7, 0x3e, 0x75, 0xcd, 0x0f, 0xa0, 0x34,
-	0xc3, 0x42, 0x08, 0x96, 0x53, 0xec, 0x17, 0xe7, 0xf3, 0xad, 0xa7, 0xb0, 0x9a, 0x09, 0x46, 0x65,
-	0x70, 0x33, 0x35, 0x2c, 0x7c, 0x2c, 0x92, 0x3c, 0x32, 0x52, 0x74, 0x07, 0x60, 0x26, 0x89, 0xf5,
-	0x9c, 0x98, 0x19, 0x3d, 0x53, 0xd6, 0x19, 0xbc, 0x93, 0xd2, 0x35, 0x7f, 0xd2, 0xe0, 0x6a, 0x97,
-	0xb0, 0x5d, 0x12, 0x12, 0xea, 0x12, 0xda, 0xf7, 0x4f, 0xdb, 0x74, 0x17, 0xe0, 0x94, 0xf3, 0xaa,
-	0x57, 0x17, 0xe3, 0x7b, 0x69, 0xc6, 0x77, 0xf4, 0x21, 0x14, 0x09, 0x75, 0x25, 0x44, 0xee, 0x3f,
-	0x40, 0x14, 0x08, 0x75, 0xb9, 0xdc, 0x3c, 0x80, 0x6b, 0x0b, 0xf1, 0xa9, 0xea, 0x74, 0xa1, 0xe2,
-	0xa6, 0xe4, 0x6a, 0x95, 0xbe, 0x91, 0xc9, 0x7b, 0x66, 0x3a, 0xfd, 0xc4, 0xa7, 0x43, 0xb5, 0x54,
-	0xe7, 0x0c, 0xdb, 0xbf, 0xe4, 0xa1, 0x22, 0x08, 0xa7, 0x28, 0x84, 0x86, 0x50, 0x4c, 0xfe, 0x23,
-	0x50, 0x23, 0x83, 0x97, 0xf9, 0xf3, 0x30, 0xd6, 0xce, 0x58, 0xdd, 0xf3, 0xcb, 0xde, 0x34, 0xbe,
-	0xfd, 0xfd, 0x9f, 0x1f, 0x72, 0x75, 0x84, 0x6c, 0xb1, 0x59, 0x63, 0xfb, 0xeb, 0x64, 0x67, 0x7f,
-	0xb3, 0xa5, 0x21, 0x06, 0x95, 0xf4, 0x96, 0x45, 0x66, 0x06, 0xf0, 0x8c, 0xb5, 0x6f, 0xac, 0x9f,
-	0xab, 0xa3, 0xd6, 0xf4, 0x75, 0xe1, 0x76, 0xd5, 0xac, 0xd9, 0x58, 0x3e, 0xa7, 0xfc, 0x22, 0x0f,
-	0xe0, 0x74, 0x32, 0x51, 0x2b, 0x83, 0xb7, 0x30, 0xb4, 0x17, 0x49, 0x13, 0x09, 0x7f, 0x15, 0xb3,
-	0x60, 0xcb, 0xdd, 0xb1, 0xad, 0xdd, 0xda, 0xd2, 0x90, 0x07, 0xe5, 0xd4, 0x70, 0xa2, 0xb5, 0xc5,
-	0x72, 0x66, 0xc6, 0xd9, 0x30, 0xcf, 0x53, 0x51, 0xb9, 0x5d, 0x11, 0xbe, 0xca, 0xa8, 0x64, 0x27,
-	0x23, 0x8d, 0x02, 0xa8, 0xce, 0x4d, 0x11, 0x5a, 0x5f, 0xc4, 0x59, 0x18, 0x78, 0xe3, 0xcd, 0xf3,
-	0x95, 0x94, 0xbb, 0x9a, 0x70, 0x57, 0x45, 0x65, 0xfb, 0x74, 0x76, 0xd0, 0x33, 0xf1, 0x25, 0x91,
-	0xa6, 0x26, 0xba, 0xb1, 0x88, 0x76, 0xc6, 0x68, 0x19, 0x37, 0x5f, 0xa4, 0xa6, 0xdc, 0xae, 0x0a,
-	0xb7, 0x97, 0x51, 0xd5, 0x4e, 0xf3, 0xb5, 0x33, 0xf9, 0x7e, 0xa7, 0x83, 0xf2, 0xed, 0xa5, 0xdb,
-	0xd6, 0xd6, 0xad, 0x9c, 0x96, 0x8b, 0xde, 0x05, 0x78, 0x20, 0xf0, 0x5a, 0x3b, 0x8f, 0xf7, 0xd0,
-	0x5b, 0x03, 0xc6, 0xc2, 0x78, 0xdb, 0xb6, 0x5f, 0xf0, 0x01, 0xf0, 0xfc, 0xa4, 0xa1, 0xfd, 0x76,
-	0xd2, 0xd0, 0xfe, 0x3a, 0x69, 0x68, 0xbf, 0xfe, 0xdd, 0xd0, 0xe0, 0x9a, 0x1f, 0x58, 0x73, 0x8a,
-	0x2a, 0xbc, 0xcf, 0x57, 0xe4, 0xef, 0xc1, 0x8a, 0x18, 0xd9, 0x77, 0xfe, 0x0d, 0x00, 0x00, 0xff,
-	0xff, 0x26, 0xf0, 0x4d, 0x32, 0x16, 0x0a, 0x00, 0x00,
+	// 1318 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0xcd, 0x73, 0xdb, 0x44,
+	0x14, 0xef, 0x3a, 0x71, 0x6d, 0x3f, 0xdb, 0x29, 0xd9, 0xb8, 0xad, 0xea, 0xb6, 0xb1, 0xab, 0x90,
+	0xd6, 0xd3, 0x21, 0x56, 0x1a, 0xa6, 0x43, 0x29, 0xcc, 0x40, 0xd2, 0xd0, 0x4c, 0x0b, 0xfd, 0x40,
+	0xc9, 0x09, 0x0e, 0x9e, 0x8d, 0xb5, 0x55, 0x44, 0xec, 0x95, 0x2a, 0xad, 0xd3, 0x64, 0x4a, 0x0f,
+	0xf4, 0xc2, 0xb5, 0x03, 0x1c, 0xf8, 0x5b, 0x38, 0x71, 0xec, 0x91, 0x19, 0x6e, 0x3d, 0x14, 0x26,
+	0xf0, 0x87, 0x30, 0xfb, 0x21, 0x59, 0x96, 0x43, 0xd2, 0x74, 0x18, 0x4e, 0xda, 0x7d, 0xfb, 0x7b,
+	0xef, 0xb7, 0xef, 0x73, 0x05, 0x98, 0x04, 0x5e, 0x67, 0x67, 0xc9, 0x7a, 0x3c, 0xa0, 0xe1, 0x5e,
+	0x3b, 0x08, 0x7d, 0xee, 0xe3, 0xea, 0x37, 0x84, 0xba, 0x34, 0x6c, 0xab, 0xa3, 0x7a, 0xb9, 0xef,
+	0x3b, 0xb4, 0xa7, 0xce, 0xea, 0x67, 0x34, 0xbe, 0xeb, 0xf7, 0x7a, 0xb4, 0xcb, 0xfd, 0x50, 0xcb,
+	0x6b, 0xae, 0xef, 0xfa, 0x72, 0x69, 0x89, 0x95, 0x96, 0x5e, 0x70, 0x7d, 0xdf, 0xed, 0x51, 0x8b,
+	0x04, 0x9e, 0x45, 0x18, 0xf3, 0x39, 0xe1, 0x9e, 0xcf, 0x22, 0x7d, 0xda, 0xd0, 0xa7, 0x72, 0xb7,
+	0x39, 0x78, 0x64, 0x71, 0xaf, 0x4f, 0x23, 0x4e, 0xfa, 0x81, 0x06, 0xcc, 0x66, 0x01, 0xce, 0x20,
+	0x94, 0x16, 0xf4, 0xf9, 0x7b, 0xf2, 0xd3, 0x5d, 0x70, 0x29, 0x5b, 0x88, 0x9e, 0x10, 0xd7, 0xa5,
+	0xa1, 0xe5, 0x07, 0x92, 0x62, 0x9c, 0xce, 0x7c, 0x8e, 0xe0, 0xd4, 0x1a, 0xe5, 0x1b, 0x21, 0xe9,
+	0x52, 0x9b, 0x3e, 0x1e, 0xd0, 0x88, 0xe3, 0xaf, 0xa1, 0xc8, 0xc5, 0xbe, 0xe3, 0x39, 0x06, 0x6a,
+	0xa2, 0x56, 0x65, 0xe5, 0xd3, 0x97, 0xaf, 0x1b, 0x27, 0x5e, 0xbd, 0x6e, 0x2c, 0xb8, 0x1e, 0xdf,
+	0x1a, 0x6c, 0xb6, 0xbb, 0x7e, 0xdf, 0x52, 0xf1, 0x10, 0x40, 0x8f, 0xb9, 0x7a, 0x67, 0xa9, 0xa8,
+	0x48, 0x6b, 0x77, 0x56, 0xf7, 0x5f, 0x37, 0x0a, 0x7a, 0x69, 0x17, 0xa4, 0xc5, 0x3b, 0x0e, 0xc6,
+	0x30, 0xf9, 0x68, 0xd0, 0xeb, 0x19, 0xb9, 0x26, 0x6a, 0x15, 0x6d, 0xb9, 0x36, 0x19, 0x54, 0x1e,
+	0xf4, 0x3d, 0xce, 0xa9, 0xb3, 0x1e, 0x10, 0x16, 0xe1, 0x4b, 0x50, 0x89, 0x68, 0xb8, 0xe3, 0x75,
+	0x69, 0x87, 0x91, 0x3e, 0x95, 0x97, 0x28, 0xd9, 0x65, 0x2d, 0xbb, 0x4f, 0xfa, 0x14, 0xcf, 0xc3,
+	0x94, 0x1f, 0x50, 0xe5, 0xb8, 0x02, 0xe5, 0x24, 0xa8, 0x9a, 0x48, 0x25, 0xac, 0x06, 0xf9, 0x48,
+	0x98, 0x34, 0x26, 0x9a, 0xa8, 0x35, 0x61, 0xab, 0x8d, 0xf9, 0x0a, 0x41, 0x45, 0x5e, 0x6c, 0x7d,
+	0xd0, 0xef, 0x93, 0x70, 0x0f, 0x37, 0xa0, 0xcc, 0x7d, 0x4e, 0x7a, 0x1d, 0x05, 0x46, 0x12, 0x0c,
+	0x52, 0xa4, 0x6e, 0x34, 0x0f, 0x53, 0x21, 0xe5, 0x83, 0x90, 0x51, 0x47, 0x63, 0x72, 0x12, 0x53,
+	0x8d, 0xa5, 0x0a, 0x76, 0x05, 0x4e, 0x89, 0x1a, 0x20, 0x41, 0x94, 0xe0, 0x14, 0xf1, 0x54, 0x22,
+	0x56, 0xc0, 0x8f, 0xa0, 0xe0, 0x2b, 0x8f, 0x8d, 0xc9, 0xe6, 0x44, 0xab, 0xbc, 0x74, 0xbe, 0x3d,
+	0x52, 0x5f, 0xed, 0x74, 0x3c, 0x56, 0x26, 0x45, 0xf8, 0xed, 0x58, 0x03, 0x5f, 0x80, 0x12, 0x0f,
+	0x07, 0xac, 0x4b, 0x84, 0x7a, 0x5e, 0xc6, 0x71, 0x28, 0x30, 0xbf, 0x05, 0x2c, 0xb5, 0x6c, 0x1a,
+	0x05, 0x3e, 0x8b, 0xe8, 0xad, 0xad, 0x01, 0xdb, 0xc6, 0x56, 0x1c, 0x08, 0x24, 0xe9, 0x66, 0x32,
+	0x74, 0x42, 0x43, 0xd3, 0x28, 0x1c, 0xbe, 0x0e, 0x85, 0x48, 0x45, 0x47, 0xba, 0x3a, 0x7e, 0xc3,
+	0x74, 0x00, 0xed, 0x18, 0x6b, 0x86, 0x30, 0xb3, 0x1c, 0x76, 0xb7, 0xbc, 0x1d, 0xfa, 0xbf, 0x95,
+	0x94, 0x79, 0x06, 0x6a, 0xa3, 0x9c, 0xca, 0x71, 0xf3, 0xa7, 0x3c, 0xd4, 0xa4, 0xe4, 0x4b, 0xd1,
+	0xc7, 0x0f, 0x49, 0x48, 0xfa, 0x94, 0xd3, 0xf0, 0xbf, 0xac, 0xaf, 0x65, 0x98, 0xe4, 0xc4, 0x15,
+	0x59, 0x16, 0x51, 0x5d, 0x38, 0x28, 0x44, 0x19, 0xf2, 0xf6, 0x06, 0x71, 0xa3, 0xcf, 0x18, 0x0f,
+	0xf7, 0x6c, 0xa9, 0x8a, 0xef, 0xc2, 0x54, 0xc4, 0x49, 0xc8, 0x3b, 0xa2, 0xd1, 0x3b, 0x7d, 0x8f,
+	0x19, 0x93, 0x32, 0xde, 0xf5, 0xb6, 0x6a, 0xf4, 0x76, 0xdc, 0xe8, 0xed, 0x8d, 0x78, 0x12, 0xac,
+	0x14, 0x45, 0xf0, 0x5e, 0xfc, 0xd1, 0x40, 0x76, 0x45, 0xea, 0x8a, 0x93, 0x7b, 0x1e, 0xcb, 0xda,
+	0x22, 0xbb, 0x46, 0xfe, 0xed, 0x6c, 0x91, 0x5d, 0x7c, 0x1b, 0x2a, 0xf1, 0x64, 0x91, 0xb7, 0x3a,
+	0x29, 0x2d, 0x9d, 0x1b, 0xb3, 0xb4, 0xaa, 0x41, 0xca, 0xd0, 0xcf, 0xc2, 0x50, 0x39, 0x56, 0x14,
+	0x77, 0x1a, 0xb1, 0x43, 0x76, 0x8d, 0xc2, 0xdb, 0xd8, 0x21, 0xbb, 0x2a, 0x69, 0x24, 0xec, 0x6e,
+	0x75, 0x1c, 0x1a, 0xf0, 0x2d, 0xa3, 0xd8, 0x44, 0xad, 0xbc, 0x5d, 0x56, 0xb2, 0x55, 0x21, 0xc2,
+	0x17, 0x01, 0x02, 0xe2, 0xd2, 0x0e, 0xf7, 0xb7, 0x29, 0x33, 0x4a, 0x32, 0x61, 0x25, 0x21, 0xd9,
+	0x10, 0x02, 0x3c, 0x07, 0xd5, 0x74, 0xda, 0x23, 0x03, 0x9a, 0x13, 0xad, 0x92, 0x5d, 0x49, 0xe5,
+	0x5d, 0xb6, 0xf0, 0x68, 0xe2, 0x23, 0xa3, 0x2c, 0x61, 0x53, 0x23, 0x99, 0x8f, 0xea, 0x1f, 0x40,
+	0x29, 0x49, 0x25, 0x7e, 0x07, 0x26, 0xb6, 0xe9, 0x9e, 0x2e, 0x24, 0xb1, 0x14, 0x93, 0x67, 0x87,
+	0xf4, 0x06, 0x71, 0xdd, 0xa8, 0xcd, 0xcd, 0xdc, 0x0d, 0x64, 0xde, 0x87, 0xe9, 0xdb, 0x1e, 0x73,
+	0x64, 0x71, 0x44, 0x71, 0x83, 0x7c, 0x08, 0x79, 0xf9, 0xda, 0x48, 0x13, 0xe5, 0xa5, 0xb9, 0x37,
+	0xa8, 0x24, 0x5b, 0x69, 0x98, 0x35, 0xc0, 0x6b, 0x94, 0xaf, 0x2b, 0x27, 0x62, 0x83, 0xe6, 0x35,
+	0x98, 0x19, 0x91, 0xaa, 0x9e, 0xc0, 0x75, 0x28, 0x6a, 0x77, 0xd5, 0x28, 0x28, 0xd9, 0xc9, 0xde,
+	0xbc, 0x07, 0xb5, 0x35, 0xca, 0x1f, 0xc4, 0x6e, 0x26, 0x77, 0x33, 0xa0, 0xa0, 0x31, 0xda, 0xc1,
+	0x78, 0x8b, 0xcf, 0x43, 0x49, 0x4c, 0x8b, 0xce, 0xb6, 0xc7, 0x1c, 0xed, 0x68, 0x51, 0x08, 0x3e,
+	0xf7, 0x98, 0x63, 0x7e, 0x0c, 0xa5, 0xc4, 0x96, 0x18, 0xfb, 0xa9, 0x56, 0x93, 0xeb, 0xc3, 0xb5,
+	0xf7, 0xe0, 0x74, 0xe6, 0x32, 0xda, 0x83, 0xcb, 0x90, 0xc9, 0x84, 0xf6, 0x23, 0x23, 0xc5, 0x37,
+	0x00, 0x12, 0x89, 0x18, 0xd7, 0xa2, 0x41, 0x8d, 0xec, 0x94, 0x8d, 0x01, 0x76, 0x0a, 0x6b, 0xfe,
+	0x82, 0xe0, 0xcc, 0x1a, 0xe5, 0xab, 0x34, 0xa0, 0xcc, 0xa1, 0xac, 0xeb, 0x0d, 0xd3, 0x74, 0x0b,
+	0x60, 0xd8, 0x60, 0x06, 0x3a, 0x46, 0x73, 0x95, 0x92, 0xe6, 0xc2, 0x9f, 0x40, 0x91, 0x32, 0x47,
+	0x99, 0xc8, 0x1d, 0xc3, 0x44, 0x81, 0x32, 0x47, 0x1a, 0xa8, 0x43, 0xd1, 0xa1, 0x9c, 0x78, 0x3d,
+	0xea, 0xc8, 0xf7, 0xa5, 0x68, 0x27, 0x7b, 0x73, 0x13, 0xce, 0x8e, 0xdd, 0x5d, 0x47, 0x6e, 0x0d,
+	0x2a, 0x4e, 0x4a, 0xae, 0x9f, 0x82, 0x8b, 0x99, 0x98, 0x24, 0xaa, 0x7b, 0x5f, 0x78, 0x6c, 0x5b,
+	0x3f, 0x0a, 0x23, 0x8a, 0x4b, 0x2f, 0x8a, 0x50, 0x91, 0xc5, 0xa8, 0xcb, 0x0b, 0x6f, 0x43, 0x31,
+	0xfe, 0x89, 0xc0, 0xb3, 0x19, 0x7b, 0x99, 0xbf, 0x8b, 0xfa, 0xa5, 0x03, 0x9e, 0x9e, 0xd1, 0xc7,
+	0xca, 0xac, 0x3f, 0xff, 0xfd, 0xef, 0x1f, 0x73, 0x35, 0x8c, 0x2d, 0x39, 0xe2, 0x23, 0xeb, 0x69,
+	0xfc, 0x78, 0x3c, 0x5b, 0x44, 0x98, 0x43, 0x25, 0x3d, 0xee, 0xb1, 0x99, 0x31, 0x78, 0xc0, 0xfb,
+	0x53, 0x9f, 0x3b, 0x14, 0xa3, 0xdf, 0x8b, 0xf3, 0x92, 0xf6, 0xb4, 0x39, 0x63, 0x11, 0x75, 0x9c,
+	0xe2, 0xc5, 0x2e, 0xc0, 0xb0, 0x6b, 0x71, 0x33, 0x63, 0x6f, 0xac, 0xa1, 0xdf, 0xc4, 0x4d, 0x2c,
+	0xf9, 0x2a, 0x66, 0xc1, 0x52, 0x43, 0xec, 0x26, 0xba, 0xba, 0x88, 0xb0, 0x0b, 0xe5, 0x54, 0xe3,
+	0xe2, 0x4b, 0xe3, 0xe1, 0xcc, 0xb4, 0x7a, 0xdd, 0x3c, 0x0c, 0xa2, 0x7d, 0x9b, 0x96, 0x5c, 0x65,
+	0x5c, 0xb2, 0xe2, 0x76, 0xc7, 0x3e, 0x54, 0x47, 0x3a, 0x0c, 0xcf, 0x8d, 0xdb, 0x19, 0x1b, 0x06,
+	0xf5, 0x77, 0x0f, 0x07, 0x69, 0xba, 0x19, 0x49, 0x57, 0xc5, 0x65, 0x6b, 0xd8, 0x57, 0xf8, 0x89,
+	0xfc, 0xd5, 0x4c, 0x97, 0x26, 0x9e, 0x1f, 0xb7, 0x76, 0x40, 0xdb, 0xd5, 0x2f, 0x1f, 0x05, 0xd3,
+	0xb4, 0xa7, 0x25, 0xed, 0x29, 0x5c, 0xb5, 0xd2, 0xf5, 0x8a, 0xd7, 0xc5, 0xa8, 0xf6, 0xf4, 0xaf,
+	0x5c, 0x23, 0x3b, 0x5a, 0xe3, 0x93, 0x63, 0x64, 0xee, 0xc4, 0x22, 0xc2, 0xdf, 0x21, 0x98, 0x1e,
+	0x46, 0xfa, 0x16, 0xe1, 0xa4, 0xe7, 0xbb, 0xf8, 0xca, 0xbf, 0xe6, 0x42, 0x23, 0x62, 0x96, 0xd6,
+	0xd1, 0x40, 0xed, 0xd4, 0x39, 0xe9, 0xd4, 0x0c, 0x9e, 0x4e, 0x52, 0x67, 0x75, 0x35, 0xdb, 0xf7,
+	0x08, 0xa6, 0x84, 0x62, 0x40, 0xd8, 0x3d, 0xca, 0x43, 0xaf, 0x1b, 0xe1, 0x03, 0xf2, 0x93, 0x3a,
+	0x8e, 0xd9, 0xe7, 0x8f, 0x40, 0x69, 0xea, 0x96, 0xa4, 0x36, 0x71, 0x73, 0x48, 0xfd, 0x34, 0xfd,
+	0x84, 0x3e, 0xb3, 0xfa, 0x4a, 0x63, 0x65, 0xe7, 0x87, 0xe5, 0x15, 0x9c, 0x5f, 0x9a, 0xb8, 0xd6,
+	0x5e, 0xbc, 0x9a, 0x43, 0xb9, 0xf0, 0x3a, 0xc0, 0x5d, 0xc9, 0xd0, 0x5c, 0x7e, 0x78, 0x07, 0x5f,
+	0xd9, 0xe2, 0x3c, 0x88, 0x6e, 0x5a, 0xd6, 0x11, 0x3f, 0x7b, 0x2f, 0xf7, 0x67, 0xd1, 0x6f, 0xfb,
+	0xb3, 0xe8, 0xcf, 0xfd, 0x59, 0xf4, 0xeb, 0x5f, 0xb3, 0x08, 0xce, 0x7a, 0x7e, 0x7b, 0x04, 0xa8,
+	0x2f, 0xfc, 0xd5, 0x49, 0xf5, 0xdd, 0x3c, 0x29, 0x27, 0xe6, 0xfb, 0xff, 0x0c, 0x00, 0x26, 0xe5,
+	0xa0, 0xfc, 0xb3, 0x0d, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -823,6 +1048,13 @@ type QueryServiceClient interface {
 	GetServices(ctx context.Context, in *GetServicesRequest, opts ...grpc.CallOption) (*GetServicesResponse, error)
 	GetOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (*GetOperationsResponse, error)
 	GetDependencies(ctx context.Context, in *GetDependenciesRequest, opts ...grpc.CallOption) (*GetDependenciesResponse, error)
+	// TailSpans streams the spans received by the collectors from now on that match the request.
+	// A subscriber that does not keep up with the spans is dropped with RESOURCE_EXHAUSTED.
+	TailSpans(ctx context.Context, in *TailSpansRequest, opts ...grpc.CallOption) (QueryService_TailSpansClient, error)
+	// GetServiceCatalog returns the recent activity of each service, as seen by the collectors.
+	GetServiceCatalog(ctx context.Context, in *GetServiceCatalogRequest, opts ...grpc.CallOption) (*GetServiceCatalogResponse, error)
+	// GetSpanMetrics returns the RED metrics of a service computed by the collectors from the spans they received.
+	GetSpanMetrics(ctx context.Context, in *GetSpanMetricsRequest, opts ...grpc.CallOption) (*GetSpanMetricsResponse, error)
 }
 
 type queryServiceClient struct {
@@ -933,6 +1165,56 @@ func (c *queryServiceClient) GetDependencies(ctx context.Context, in *GetDepende
 	return out, nil
 }
 
+func (c *queryServiceClient) TailSpans(ctx context.Context, in *TailSpansRequest, opts ...grpc.CallOption) (QueryService_TailSpansClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_QueryService_serviceDesc.Streams[2], "/jaeger.api_v2.QueryService/TailSpans", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &queryServiceTailSpansClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type QueryService_TailSpansClient interface {
+	Recv() (*SpansResponseChunk, error)
+	grpc.ClientStream
+}
+
+type queryServiceTailSpansClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryServiceTailSpansClient) Recv() (*SpansResponseChunk, error) {
+	m := new(SpansResponseChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *queryServiceClient) GetServiceCatalog(ctx context.Context, in *GetServiceCatalogRequest, opts ...grpc.CallOption) (*GetServiceCatalogResponse, error) {
+	out := new(GetServiceCatalogResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/GetServiceCatalog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) GetSpanMetrics(ctx context.Context, in *GetSpanMetricsRequest, opts ...grpc.CallOption) (*GetSpanMetricsResponse, error) {
+	out := new(GetSpanMetricsResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/GetSpanMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QueryServiceServer is the server API for QueryService service.
 type QueryServiceServer interface {
 	GetTrace(*GetTraceRequest, QueryService_GetTraceServer) error
@@ -941,6 +1223,13 @@ type QueryServiceServer interface {
 	GetServices(context.Context, *GetServicesRequest) (*GetServicesResponse, error)
 	GetOperations(context.Context, *GetOperationsRequest) (*GetOperationsResponse, error)
 	GetDependencies(context.Context, *GetDependenciesRequest) (*GetDependenciesResponse, error)
+	// TailSpans streams the spans received by the collectors from now on that match the request.
+	// A subscriber that does not keep up with the spans is dropped with RESOURCE_EXHAUSTED.
+	TailSpans(*TailSpansRequest, QueryService_TailSpansServer) error
+	// GetServiceCatalog returns the recent activity of each service, as seen by the collectors.
+	GetServiceCatalog(context.Context, *GetServiceCatalogRequest) (*GetServiceCatalogResponse, error)
+	// GetSpanMetrics returns the RED metrics of a service computed by the collectors from the spans they received.
+	GetSpanMetrics(context.Context, *GetSpanMetricsRequest) (*GetSpanMetricsResponse, error)
 }
 
 func RegisterQueryServiceServer(s *grpc.Server, srv QueryServiceServer) {
@@ -1061,6 +1350,63 @@ func _QueryService_GetDependencies_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _QueryService_TailSpans_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailSpansRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).TailSpans(m, &queryServiceTailSpansServer{stream})
+}
+
+type QueryService_TailSpansServer interface {
+	Send(*SpansResponseChunk) error
+	grpc.ServerStream
+}
+
+type queryServiceTailSpansServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryServiceTailSpansServer) Send(m *SpansResponseChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _QueryService_GetServiceCatalog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceCatalogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetServiceCatalog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.QueryService/GetServiceCatalog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetServiceCatalog(ctx, req.(*GetServiceCatalogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_GetSpanMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSpanMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetSpanMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.QueryService/GetSpanMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetSpanMetrics(ctx, req.(*GetSpanMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _QueryService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "jaeger.api_v2.QueryService",
 	HandlerType: (*QueryServiceServer)(nil),
@@ -1081,6 +1427,14 @@ var _QueryService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetDependencies",
 			Handler:    _QueryService_GetDependencies_Handler,
 		},
+		{
+			MethodName: "GetServiceCatalog",
+			Handler:    _QueryService_GetServiceCatalog_Handler,
+		},
+		{
+			MethodName: "GetSpanMetrics",
+			Handler:    _QueryService_GetSpanMetrics_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1093,6 +1447,11 @@ var _QueryService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _QueryService_FindTraces_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "TailSpans",
+			Handler:       _QueryService_TailSpans_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api_v2/query.proto",
 }
@@ -1120,13 +1479,23 @@ func (m *GetTraceRequest) MarshalTo(dAtA []byte) (int, error) {
 		return 0, err
 	}
 	i += n1
+	if m.Full {
+		dAtA[i] = 0x10
+		i++
+		if m.Full {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
 	return i, nil
 }
 
-func (m *SpansResponseChunk) Marshal() (dAtA []byte, err error) {
+func (m *OmittedSpans) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -1136,16 +1505,112 @@ func (m *SpansResponseChunk) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SpansResponseChunk) MarshalTo(dAtA []byte) (int, error) {
+func (m *OmittedSpans) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.Spans) > 0 {
-		for _, msg := range m.Spans {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
+	if len(m.ServiceName) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.ServiceName)))
+		i += copy(dAtA[i:], m.ServiceName)
+	}
+	if len(m.OperationName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.OperationName)))
+		i += copy(dAtA[i:], m.OperationName)
+	}
+	if m.Spans != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.Spans))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TraceSummary) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceSummary) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.TotalSpans != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.TotalSpans))
+	}
+	if m.ReturnedSpans != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.ReturnedSpans))
+	}
+	if m.CollapsedSpans != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.CollapsedSpans))
+	}
+	if len(m.Omitted) > 0 {
+		for _, msg := range m.Omitted {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Truncated {
+		dAtA[i] = 0x28
+		i++
+		if m.Truncated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *SpansResponseChunk) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpansResponseChunk) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Spans) > 0 {
+		for _, msg := range m.Spans {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
 			n, err := msg.MarshalTo(dAtA[i:])
 			if err != nil {
 				return 0, err
@@ -1153,6 +1618,16 @@ func (m *SpansResponseChunk) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.Summary != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.Summary.Size()))
+		n2, err := m.Summary.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -1177,11 +1652,11 @@ func (m *ArchiveTraceRequest) MarshalTo(dAtA []byte) (int, error) {
 	dAtA[i] = 0xa
 	i++
 	i = encodeVarintQuery(dAtA, i, uint64(m.TraceID.Size()))
-	n2, err := m.TraceID.MarshalTo(dAtA[i:])
+	n3, err := m.TraceID.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n2
+	i += n3
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -1256,40 +1731,76 @@ func (m *TraceQueryParameters) MarshalTo(dAtA []byte) (int, error) {
 	dAtA[i] = 0x22
 	i++
 	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMin)))
-	n3, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMin, dAtA[i:])
+	n4, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMin, dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n3
+	i += n4
 	dAtA[i] = 0x2a
 	i++
 	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMax)))
-	n4, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMax, dAtA[i:])
+	n5, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMax, dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n4
+	i += n5
 	dAtA[i] = 0x32
 	i++
 	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMin)))
-	n5, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMin, dAtA[i:])
+	n6, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMin, dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n5
+	i += n6
 	dAtA[i] = 0x3a
 	i++
 	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMax)))
-	n6, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMax, dAtA[i:])
+	n7, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMax, dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n6
+	i += n7
 	if m.SearchDepth != 0 {
 		dAtA[i] = 0x40
 		i++
 		i = encodeVarintQuery(dAtA, i, uint64(m.SearchDepth))
 	}
+	if len(m.PageToken) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.PageToken)))
+		i += copy(dAtA[i:], m.PageToken)
+	}
+	if len(m.ServiceNames) > 0 {
+		for _, s := range m.ServiceNames {
+			dAtA[i] = 0x52
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.OperationNames) > 0 {
+		for _, s := range m.OperationNames {
+			dAtA[i] = 0x5a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -1315,11 +1826,11 @@ func (m *FindTracesRequest) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintQuery(dAtA, i, uint64(m.Query.Size()))
-		n7, err := m.Query.MarshalTo(dAtA[i:])
+		n8, err := m.Query.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n7
+		i += n8
 	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
@@ -1516,19 +2027,29 @@ func (m *GetDependenciesRequest) MarshalTo(dAtA []byte) (int, error) {
 	dAtA[i] = 0xa
 	i++
 	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTime)))
-	n8, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTime, dAtA[i:])
+	n9, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTime, dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n8
+	i += n9
 	dAtA[i] = 0x12
 	i++
 	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime)))
-	n9, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EndTime, dAtA[i:])
+	n10, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EndTime, dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n9
+	i += n10
+	if m.Detailed {
+		dAtA[i] = 0x18
+		i++
+		if m.Detailed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -1585,6 +2106,62 @@ func (m *GetTraceRequest) Size() (n int) {
 	_ = l
 	l = m.TraceID.Size()
 	n += 1 + l + sovQuery(uint64(l))
+	if m.Full {
+		n += 2
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *OmittedSpans) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.OperationName)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Spans != 0 {
+		n += 1 + sovQuery(uint64(m.Spans))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TraceSummary) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TotalSpans != 0 {
+		n += 1 + sovQuery(uint64(m.TotalSpans))
+	}
+	if m.ReturnedSpans != 0 {
+		n += 1 + sovQuery(uint64(m.ReturnedSpans))
+	}
+	if m.CollapsedSpans != 0 {
+		n += 1 + sovQuery(uint64(m.CollapsedSpans))
+	}
+	if len(m.Omitted) > 0 {
+		for _, e := range m.Omitted {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.Truncated {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1603,6 +2180,10 @@ func (m *SpansResponseChunk) Size() (n int) {
 			n += 1 + l + sovQuery(uint64(l))
 		}
 	}
+	if m.Summary != nil {
+		l = m.Summary.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1668,6 +2249,22 @@ func (m *TraceQueryParameters) Size() (n int) {
 	if m.SearchDepth != 0 {
 		n += 1 + sovQuery(uint64(m.SearchDepth))
 	}
+	l = len(m.PageToken)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if len(m.ServiceNames) > 0 {
+		for _, s := range m.ServiceNames {
+			l = len(s)
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if len(m.OperationNames) > 0 {
+		for _, s := range m.OperationNames {
+			l = len(s)
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1794,6 +2391,9 @@ func (m *GetDependenciesRequest) Size() (n int) {
 	n += 1 + l + sovQuery(uint64(l))
 	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime)
 	n += 1 + l + sovQuery(uint64(l))
+	if m.Detailed {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1812,26 +2412,270 @@ func (m *GetDependenciesResponse) Size() (n int) {
 			n += 1 + l + sovQuery(uint64(l))
 		}
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
-}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovQuery(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozQuery(x uint64) (n int) {
+	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *GetTraceRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetTraceRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetTraceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.TraceID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Full", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Full = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OmittedSpans) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OmittedSpans: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OmittedSpans: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OperationName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
+			}
+			m.Spans = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Spans |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
 
-func sovQuery(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozQuery(x uint64) (n int) {
-	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *GetTraceRequest) Unmarshal(dAtA []byte) error {
+func (m *TraceSummary) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1854,17 +2698,74 @@ func (m *GetTraceRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetTraceRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceSummary: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetTraceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceSummary: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalSpans", wireType)
+			}
+			m.TotalSpans = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalSpans |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReturnedSpans", wireType)
+			}
+			m.ReturnedSpans = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReturnedSpans |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CollapsedSpans", wireType)
+			}
+			m.CollapsedSpans = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CollapsedSpans |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Omitted", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQuery
@@ -1874,25 +2775,46 @@ func (m *GetTraceRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthQuery
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.TraceID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Omitted = append(m.Omitted, OmittedSpans{})
+			if err := m.Omitted[len(m.Omitted)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Truncated", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Truncated = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -1981,6 +2903,42 @@ func (m *SpansResponseChunk) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Summary", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Summary == nil {
+				m.Summary = &TraceSummary{}
+			}
+			if err := m.Summary.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -2518,6 +3476,102 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PageToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PageToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceNames", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceNames = append(m.ServiceNames, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationNames", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OperationNames = append(m.OperationNames, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -3224,6 +4278,26 @@ func (m *GetDependenciesRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Detailed", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Detailed = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
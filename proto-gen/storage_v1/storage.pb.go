@@ -513,17 +513,23 @@ func (m *GetOperationsResponse) GetOperations() []*Operation {
 }
 
 type TraceQueryParameters struct {
-	ServiceName          string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
-	OperationName        string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
-	Tags                 map[string]string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	StartTimeMin         time.Time         `protobuf:"bytes,4,opt,name=start_time_min,json=startTimeMin,proto3,stdtime" json:"start_time_min"`
-	StartTimeMax         time.Time         `protobuf:"bytes,5,opt,name=start_time_max,json=startTimeMax,proto3,stdtime" json:"start_time_max"`
-	DurationMin          time.Duration     `protobuf:"bytes,6,opt,name=duration_min,json=durationMin,proto3,stdduration" json:"duration_min"`
-	DurationMax          time.Duration     `protobuf:"bytes,7,opt,name=duration_max,json=durationMax,proto3,stdduration" json:"duration_max"`
-	NumTraces            int32             `protobuf:"varint,8,opt,name=num_traces,json=numTraces,proto3" json:"num_traces,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	ServiceName   string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	OperationName string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
+	Tags          map[string]string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	StartTimeMin  time.Time         `protobuf:"bytes,4,opt,name=start_time_min,json=startTimeMin,proto3,stdtime" json:"start_time_min"`
+	StartTimeMax  time.Time         `protobuf:"bytes,5,opt,name=start_time_max,json=startTimeMax,proto3,stdtime" json:"start_time_max"`
+	DurationMin   time.Duration     `protobuf:"bytes,6,opt,name=duration_min,json=durationMin,proto3,stdduration" json:"duration_min"`
+	DurationMax   time.Duration     `protobuf:"bytes,7,opt,name=duration_max,json=durationMax,proto3,stdduration" json:"duration_max"`
+	NumTraces     int32             `protobuf:"varint,8,opt,name=num_traces,json=numTraces,proto3" json:"num_traces,omitempty"`
+	// Opaque continuation token returned with the previous page of results.
+	PageToken string `protobuf:"bytes,9,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Alternatives to service_name and operation_name, a span matches if its service
+	// and its operation are each any of the given ones.
+	ServiceNames         []string `protobuf:"bytes,10,rep,name=service_names,json=serviceNames,proto3" json:"service_names,omitempty"`
+	OperationNames       []string `protobuf:"bytes,11,rep,name=operation_names,json=operationNames,proto3" json:"operation_names,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *TraceQueryParameters) Reset()         { *m = TraceQueryParameters{} }
@@ -615,6 +621,27 @@ func (m *TraceQueryParameters) GetNumTraces() int32 {
 	return 0
 }
 
+func (m *TraceQueryParameters) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *TraceQueryParameters) GetServiceNames() []string {
+	if m != nil {
+		return m.ServiceNames
+	}
+	return nil
+}
+
+func (m *TraceQueryParameters) GetOperationNames() []string {
+	if m != nil {
+		return m.OperationNames
+	}
+	return nil
+}
+
 type FindTracesRequest struct {
 	Query                *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
@@ -835,67 +862,70 @@ func init() { proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffd
 func init() { golang_proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffdb) }
 
 var fileDescriptor_0d2c4ccf1453ffdb = []byte{
-	// 953 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4d, 0x73, 0xdb, 0x44,
-	0x18, 0x46, 0x89, 0x5d, 0xdb, 0xaf, 0x9d, 0x92, 0x6c, 0x0c, 0x08, 0xd1, 0xda, 0x41, 0x90, 0x0f,
-	0x98, 0x41, 0x26, 0xe6, 0x00, 0x03, 0x65, 0x00, 0x37, 0xa9, 0x27, 0x40, 0xa1, 0xa8, 0x19, 0x3a,
-	0x43, 0x19, 0x34, 0xeb, 0x68, 0x51, 0xd4, 0x44, 0x2b, 0x55, 0x5a, 0x79, 0x92, 0x03, 0x37, 0x7e,
-	0x00, 0x47, 0x4e, 0x5c, 0xf9, 0x1b, 0x1c, 0x7b, 0xe4, 0xcc, 0x21, 0x30, 0xe1, 0xc8, 0x9f, 0x60,
-	0xf6, 0x43, 0x8a, 0x6c, 0x6b, 0xf2, 0x35, 0xbd, 0x69, 0xdf, 0x7d, 0xde, 0xe7, 0xfd, 0xda, 0xf7,
-	0xb1, 0x61, 0x21, 0x61, 0x61, 0x8c, 0x3d, 0x62, 0x45, 0x71, 0xc8, 0x42, 0xb4, 0xf4, 0x04, 0x13,
-	0x8f, 0xc4, 0x56, 0x66, 0x1d, 0x6f, 0x1a, 0x6d, 0x2f, 0xf4, 0x42, 0x71, 0xdb, 0xe3, 0x5f, 0x12,
-	0x68, 0x74, 0xbd, 0x30, 0xf4, 0x0e, 0x49, 0x4f, 0x9c, 0x46, 0xe9, 0x8f, 0x3d, 0xe6, 0x07, 0x24,
-	0x61, 0x38, 0x88, 0x14, 0xa0, 0x33, 0x0d, 0x70, 0xd3, 0x18, 0x33, 0x3f, 0xa4, 0xea, 0xbe, 0x19,
-	0x84, 0x2e, 0x39, 0x94, 0x07, 0xf3, 0x37, 0x0d, 0x5e, 0x1e, 0x12, 0xb6, 0x45, 0x22, 0x42, 0x5d,
-	0x42, 0xf7, 0x7c, 0x92, 0xd8, 0xe4, 0x69, 0x4a, 0x12, 0x86, 0xee, 0x02, 0x24, 0x0c, 0xc7, 0xcc,
-	0xe1, 0x01, 0x74, 0x6d, 0x45, 0xdb, 0x68, 0xf6, 0x0d, 0x4b, 0x92, 0x5b, 0x19, 0xb9, 0xb5, 0x9b,
-	0x45, 0x1f, 0xd4, 0x9f, 0x9d, 0x74, 0x5f, 0xf8, 0xe5, 0xef, 0xae, 0x66, 0x37, 0x84, 0x1f, 0xbf,
-	0x41, 0x9f, 0x40, 0x9d, 0x50, 0x57, 0x52, 0xcc, 0x5d, 0x81, 0xa2, 0x46, 0xa8, 0xcb, 0xed, 0xe6,
-	0x08, 0x5e, 0x99, 0xc9, 0x2f, 0x89, 0x42, 0x9a, 0x10, 0x34, 0x84, 0x96, 0x5b, 0xb0, 0xeb, 0xda,
-	0xca, 0xfc, 0x46, 0xb3, 0x7f, 0xdb, 0x52, 0x9d, 0xc4, 0x91, 0xef, 0x8c, 0xfb, 0x56, 0xee, 0x7a,
-	0xfc, 0xa5, 0x4f, 0x0f, 0x06, 0x15, 0x1e, 0xc2, 0x9e, 0x70, 0x34, 0x3f, 0x82, 0xc5, 0x47, 0xb1,
-	0xcf, 0xc8, 0xc3, 0x08, 0xd3, 0xac, 0xfa, 0x75, 0xa8, 0x24, 0x11, 0xa6, 0xaa, 0xee, 0xe5, 0x29,
-	0x52, 0x81, 0x14, 0x00, 0x73, 0x19, 0x96, 0x0a, 0xce, 0x32, 0x35, 0x93, 0xc2, 0x8b, 0x43, 0xc2,
-	0x76, 0x63, 0xbc, 0x47, 0x32, 0xc2, 0xc7, 0x50, 0x67, 0xfc, 0xec, 0xf8, 0xae, 0x20, 0x6d, 0x0d,
-	0x3e, 0xe5, 0xa9, 0xfc, 0x75, 0xd2, 0x7d, 0xc7, 0xf3, 0xd9, 0x7e, 0x3a, 0xb2, 0xf6, 0xc2, 0xa0,
-	0x27, 0xc3, 0x70, 0xa0, 0x4f, 0x3d, 0x75, 0xea, 0xc9, 0x81, 0x09, 0xb6, 0x9d, 0xad, 0xd3, 0x93,
-	0x6e, 0x4d, 0x7d, 0xda, 0x35, 0xc1, 0xb8, 0xe3, 0x9a, 0x6d, 0x40, 0x43, 0xc2, 0x1e, 0x92, 0x78,
-	0xec, 0xef, 0xe5, 0x13, 0x34, 0x37, 0x61, 0x79, 0xc2, 0xaa, 0xfa, 0x66, 0x40, 0x3d, 0x51, 0x36,
-	0xd1, 0xb3, 0x86, 0x9d, 0x9f, 0xcd, 0xfb, 0xd0, 0x1e, 0x12, 0xf6, 0x75, 0x44, 0xe4, 0x93, 0xc9,
-	0x1f, 0x83, 0x0e, 0x35, 0x85, 0x11, 0xc9, 0x37, 0xec, 0xec, 0x88, 0x5e, 0x83, 0x06, 0xef, 0x83,
-	0x73, 0xe0, 0x53, 0x57, 0x8c, 0x98, 0xd3, 0x45, 0x98, 0x7e, 0xe1, 0x53, 0xd7, 0xbc, 0x03, 0x8d,
-	0x9c, 0x0b, 0x21, 0xa8, 0x50, 0x1c, 0x64, 0x04, 0xe2, 0xfb, 0x7c, 0xef, 0x9f, 0xe0, 0xa5, 0xa9,
-	0x64, 0x54, 0x05, 0x6b, 0x70, 0x33, 0xcc, 0xac, 0x5f, 0xe1, 0x20, 0xaf, 0x63, 0xca, 0x8a, 0xee,
-	0x00, 0xe4, 0x96, 0x44, 0x9f, 0x13, 0xef, 0xe3, 0x96, 0x35, 0xb3, 0x69, 0x56, 0x1e, 0xc2, 0x2e,
-	0xe0, 0xcd, 0xdf, 0x2b, 0xd0, 0x16, 0x9d, 0xfe, 0x26, 0x25, 0xf1, 0xf1, 0x03, 0x1c, 0xe3, 0x80,
-	0x30, 0x12, 0x27, 0xe8, 0x75, 0x68, 0xa9, 0xea, 0x9d, 0x42, 0x41, 0x4d, 0x65, 0xe3, 0xa1, 0xd1,
-	0x6a, 0x21, 0x43, 0x09, 0x92, 0xc5, 0x2d, 0x4c, 0x64, 0x88, 0xb6, 0xa1, 0xc2, 0xb0, 0x97, 0xe8,
-	0xf3, 0x22, 0xb5, 0xcd, 0x92, 0xd4, 0xca, 0x12, 0xb0, 0x76, 0xb1, 0x97, 0x6c, 0x53, 0x16, 0x1f,
-	0xdb, 0xc2, 0x1d, 0x7d, 0x0e, 0x37, 0xcf, 0x56, 0xd5, 0x09, 0x7c, 0xaa, 0x57, 0xae, 0xb0, 0x6b,
-	0xad, 0x7c, 0x5d, 0xef, 0xfb, 0x74, 0x9a, 0x0b, 0x1f, 0xe9, 0xd5, 0xeb, 0x71, 0xe1, 0x23, 0x74,
-	0x0f, 0x5a, 0x99, 0xf8, 0x88, 0xac, 0x6e, 0x08, 0xa6, 0x57, 0x67, 0x98, 0xb6, 0x14, 0x48, 0x12,
-	0xfd, 0xca, 0x89, 0x9a, 0x99, 0x23, 0xcf, 0x69, 0x82, 0x07, 0x1f, 0xe9, 0xb5, 0xeb, 0xf0, 0xe0,
-	0x23, 0x74, 0x1b, 0x80, 0xa6, 0x81, 0x23, 0xb6, 0x26, 0xd1, 0xeb, 0x2b, 0xda, 0x46, 0xd5, 0x6e,
-	0xd0, 0x34, 0x10, 0x4d, 0x4e, 0x8c, 0xf7, 0xa1, 0x91, 0x77, 0x16, 0x2d, 0xc2, 0xfc, 0x01, 0x39,
-	0x56, 0xb3, 0xe5, 0x9f, 0xa8, 0x0d, 0xd5, 0x31, 0x3e, 0x4c, 0xb3, 0x51, 0xca, 0xc3, 0x87, 0x73,
-	0x1f, 0x68, 0xa6, 0x0d, 0x4b, 0xf7, 0x7c, 0xea, 0x4a, 0x9a, 0x6c, 0x65, 0x3e, 0x86, 0xea, 0x53,
-	0x3e, 0x37, 0x25, 0x21, 0xeb, 0x97, 0x1c, 0xae, 0x2d, 0xbd, 0xcc, 0x6d, 0x40, 0x5c, 0x52, 0xf2,
-	0x47, 0x7f, 0x77, 0x3f, 0xa5, 0x07, 0xa8, 0x07, 0x55, 0xbe, 0x1e, 0x99, 0xd8, 0x95, 0xe9, 0x92,
-	0x92, 0x38, 0x89, 0x33, 0x77, 0x61, 0x39, 0x4f, 0x6d, 0x67, 0xeb, 0x79, 0x25, 0x37, 0x86, 0xf6,
-	0x24, 0xab, 0x5a, 0xcc, 0x1f, 0xa0, 0x91, 0x89, 0x9c, 0x4c, 0xb1, 0x35, 0xf8, 0xec, 0xba, 0x2a,
-	0x57, 0xcf, 0xd9, 0xeb, 0x4a, 0xe6, 0x92, 0xfe, 0x13, 0x58, 0xe4, 0x25, 0x0a, 0xc1, 0x8d, 0x1f,
-	0x1c, 0xa6, 0x9e, 0x4f, 0xd1, 0xb7, 0xd0, 0xc8, 0x05, 0x18, 0xbd, 0x51, 0x52, 0xc8, 0xb4, 0xb6,
-	0x1b, 0x6f, 0x9e, 0x0f, 0x92, 0xb5, 0xf4, 0xff, 0x9b, 0x97, 0xc1, 0x6c, 0x82, 0xdd, 0x3c, 0xd8,
-	0x23, 0xa8, 0x67, 0xc2, 0x8e, 0xcc, 0x12, 0x9a, 0x29, 0xd5, 0x37, 0x56, 0x4b, 0x30, 0xb3, 0x63,
-	0x7d, 0x57, 0x43, 0xdf, 0x43, 0xb3, 0xa0, 0xd5, 0x68, 0xb5, 0x9c, 0x7b, 0x4a, 0xe1, 0x8d, 0xb5,
-	0x8b, 0x60, 0x6a, 0x2e, 0x23, 0x58, 0x98, 0x50, 0x52, 0xb4, 0x5e, 0xee, 0x38, 0x23, 0xfc, 0xc6,
-	0xc6, 0xc5, 0x40, 0x15, 0xe3, 0x31, 0xc0, 0xd9, 0x12, 0xa0, 0xb2, 0x1e, 0xcf, 0xec, 0xc8, 0xe5,
-	0xdb, 0xe3, 0x40, 0xab, 0xf8, 0xe0, 0xd0, 0xda, 0x79, 0xf4, 0x67, 0xef, 0xdc, 0x58, 0xbf, 0x10,
-	0xa7, 0xa6, 0xfd, 0xb3, 0x06, 0xfa, 0xe4, 0xbf, 0x8c, 0xc2, 0xd4, 0xf7, 0xc5, 0xcf, 0x79, 0xf1,
-	0x1a, 0xbd, 0x55, 0xde, 0x97, 0x92, 0x3f, 0x52, 0xc6, 0xdb, 0x97, 0x81, 0xca, 0x34, 0x06, 0xb7,
-	0x9e, 0x9d, 0x76, 0xb4, 0x3f, 0x4f, 0x3b, 0xda, 0x3f, 0xa7, 0x1d, 0xed, 0x8f, 0x7f, 0x3b, 0xda,
-	0x77, 0xa0, 0xbc, 0x9c, 0xf1, 0xe6, 0xe8, 0x86, 0x50, 0xba, 0xf7, 0xfe, 0x0f, 0x00, 0x00, 0xff,
-	0xff, 0x22, 0xdc, 0xa9, 0x3a, 0x3c, 0x0a, 0x00, 0x00,
+	// 994 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4f, 0x73, 0xdb, 0x44,
+	0x14, 0x47, 0x49, 0x5c, 0x5b, 0xcf, 0x4e, 0x9b, 0x6c, 0x0c, 0x08, 0xd1, 0xda, 0x41, 0x25, 0x71,
+	0x60, 0x06, 0x99, 0x98, 0x03, 0x0c, 0x94, 0x01, 0xdc, 0xa4, 0x9e, 0x00, 0x85, 0xa2, 0x7a, 0xe8,
+	0x0c, 0x65, 0xd0, 0xac, 0xa3, 0x45, 0x51, 0x1d, 0xad, 0x54, 0xfd, 0xf1, 0x24, 0x07, 0x6e, 0x7c,
+	0x00, 0x0e, 0x1c, 0x38, 0xf1, 0x59, 0x38, 0xf6, 0xc8, 0x99, 0x43, 0x60, 0xc2, 0x91, 0x2f, 0xc1,
+	0xec, 0x1f, 0x29, 0xb2, 0xad, 0x49, 0xd2, 0x4c, 0x6f, 0xda, 0xb7, 0xef, 0xfd, 0xde, 0xef, 0xfd,
+	0x5d, 0xc1, 0x72, 0x9c, 0x04, 0x11, 0x76, 0x89, 0x19, 0x46, 0x41, 0x12, 0xa0, 0xd5, 0x27, 0x98,
+	0xb8, 0x24, 0x32, 0x33, 0xe9, 0x64, 0x5b, 0x6f, 0xba, 0x81, 0x1b, 0xf0, 0xdb, 0x2e, 0xfb, 0x12,
+	0x8a, 0x7a, 0xdb, 0x0d, 0x02, 0xf7, 0x90, 0x74, 0xf9, 0x69, 0x94, 0xfe, 0xd8, 0x4d, 0x3c, 0x9f,
+	0xc4, 0x09, 0xf6, 0x43, 0xa9, 0xd0, 0x9a, 0x55, 0x70, 0xd2, 0x08, 0x27, 0x5e, 0x40, 0xe5, 0x7d,
+	0xdd, 0x0f, 0x1c, 0x72, 0x28, 0x0e, 0xc6, 0xef, 0x0a, 0xbc, 0x32, 0x20, 0xc9, 0x0e, 0x09, 0x09,
+	0x75, 0x08, 0xdd, 0xf7, 0x48, 0x6c, 0x91, 0xa7, 0x29, 0x89, 0x13, 0x74, 0x17, 0x20, 0x4e, 0x70,
+	0x94, 0xd8, 0xcc, 0x81, 0xa6, 0xac, 0x2b, 0x5b, 0xf5, 0x9e, 0x6e, 0x0a, 0x70, 0x33, 0x03, 0x37,
+	0x87, 0x99, 0xf7, 0x7e, 0xed, 0xd9, 0x49, 0xfb, 0xa5, 0x5f, 0xfe, 0x6e, 0x2b, 0x96, 0xca, 0xed,
+	0xd8, 0x0d, 0xfa, 0x04, 0x6a, 0x84, 0x3a, 0x02, 0x62, 0xe1, 0x39, 0x20, 0xaa, 0x84, 0x3a, 0x4c,
+	0x6e, 0x8c, 0xe0, 0xd5, 0x39, 0x7e, 0x71, 0x18, 0xd0, 0x98, 0xa0, 0x01, 0x34, 0x9c, 0x82, 0x5c,
+	0x53, 0xd6, 0x17, 0xb7, 0xea, 0xbd, 0x5b, 0xa6, 0xcc, 0x24, 0x0e, 0x3d, 0x7b, 0xd2, 0x33, 0x73,
+	0xd3, 0xe3, 0x2f, 0x3d, 0x3a, 0xee, 0x2f, 0x31, 0x17, 0xd6, 0x94, 0xa1, 0xf1, 0x11, 0xac, 0x3c,
+	0x8a, 0xbc, 0x84, 0x3c, 0x0c, 0x31, 0xcd, 0xa2, 0xef, 0xc0, 0x52, 0x1c, 0x62, 0x2a, 0xe3, 0x5e,
+	0x9b, 0x01, 0xe5, 0x9a, 0x5c, 0xc1, 0x58, 0x83, 0xd5, 0x82, 0xb1, 0xa0, 0x66, 0x50, 0xb8, 0x31,
+	0x20, 0xc9, 0x30, 0xc2, 0xfb, 0x24, 0x03, 0x7c, 0x0c, 0xb5, 0x84, 0x9d, 0x6d, 0xcf, 0xe1, 0xa0,
+	0x8d, 0xfe, 0xa7, 0x8c, 0xca, 0x5f, 0x27, 0xed, 0x77, 0x5c, 0x2f, 0x39, 0x48, 0x47, 0xe6, 0x7e,
+	0xe0, 0x77, 0x85, 0x1b, 0xa6, 0xe8, 0x51, 0x57, 0x9e, 0xba, 0xa2, 0x60, 0x1c, 0x6d, 0x6f, 0xe7,
+	0xf4, 0xa4, 0x5d, 0x95, 0x9f, 0x56, 0x95, 0x23, 0xee, 0x39, 0x46, 0x13, 0xd0, 0x80, 0x24, 0x0f,
+	0x49, 0x34, 0xf1, 0xf6, 0xf3, 0x0a, 0x1a, 0xdb, 0xb0, 0x36, 0x25, 0x95, 0x79, 0xd3, 0xa1, 0x16,
+	0x4b, 0x19, 0xcf, 0x99, 0x6a, 0xe5, 0x67, 0xe3, 0x3e, 0x34, 0x07, 0x24, 0xf9, 0x3a, 0x24, 0xa2,
+	0x65, 0xf2, 0x66, 0xd0, 0xa0, 0x2a, 0x75, 0x38, 0x79, 0xd5, 0xca, 0x8e, 0xe8, 0x75, 0x50, 0x59,
+	0x1e, 0xec, 0xb1, 0x47, 0x1d, 0x5e, 0x62, 0x06, 0x17, 0x62, 0xfa, 0x85, 0x47, 0x1d, 0xe3, 0x0e,
+	0xa8, 0x39, 0x16, 0x42, 0xb0, 0x44, 0xb1, 0x9f, 0x01, 0xf0, 0xef, 0xf3, 0xad, 0x7f, 0x82, 0x97,
+	0x67, 0xc8, 0xc8, 0x08, 0x36, 0xe1, 0x7a, 0x90, 0x49, 0xbf, 0xc2, 0x7e, 0x1e, 0xc7, 0x8c, 0x14,
+	0xdd, 0x01, 0xc8, 0x25, 0xb1, 0xb6, 0xc0, 0xfb, 0xe3, 0xa6, 0x39, 0x37, 0x69, 0x66, 0xee, 0xc2,
+	0x2a, 0xe8, 0x1b, 0xbf, 0x56, 0xa0, 0xc9, 0x33, 0xfd, 0x4d, 0x4a, 0xa2, 0xe3, 0x07, 0x38, 0xc2,
+	0x3e, 0x49, 0x48, 0x14, 0xa3, 0x37, 0xa0, 0x21, 0xa3, 0xb7, 0x0b, 0x01, 0xd5, 0xa5, 0x8c, 0xb9,
+	0x46, 0x1b, 0x05, 0x86, 0x42, 0x49, 0x04, 0xb7, 0x3c, 0xc5, 0x10, 0xed, 0xc2, 0x52, 0x82, 0xdd,
+	0x58, 0x5b, 0xe4, 0xd4, 0xb6, 0x4b, 0xa8, 0x95, 0x11, 0x30, 0x87, 0xd8, 0x8d, 0x77, 0x69, 0x12,
+	0x1d, 0x5b, 0xdc, 0x1c, 0x7d, 0x0e, 0xd7, 0xcf, 0x46, 0xd5, 0xf6, 0x3d, 0xaa, 0x2d, 0x3d, 0xc7,
+	0xac, 0x35, 0xf2, 0x71, 0xbd, 0xef, 0xd1, 0x59, 0x2c, 0x7c, 0xa4, 0x55, 0xae, 0x86, 0x85, 0x8f,
+	0xd0, 0x3d, 0x68, 0x64, 0xcb, 0x87, 0xb3, 0xba, 0xc6, 0x91, 0x5e, 0x9b, 0x43, 0xda, 0x91, 0x4a,
+	0x02, 0xe8, 0x37, 0x06, 0x54, 0xcf, 0x0c, 0x19, 0xa7, 0x29, 0x1c, 0x7c, 0xa4, 0x55, 0xaf, 0x82,
+	0x83, 0x8f, 0xd0, 0x2d, 0x00, 0x9a, 0xfa, 0x36, 0x9f, 0x9a, 0x58, 0xab, 0xad, 0x2b, 0x5b, 0x15,
+	0x4b, 0xa5, 0xa9, 0xcf, 0x93, 0x1c, 0xb3, 0xeb, 0x10, 0xbb, 0xc4, 0x4e, 0x82, 0x31, 0xa1, 0x9a,
+	0xca, 0x0b, 0xa6, 0x32, 0xc9, 0x90, 0x09, 0xd0, 0x6d, 0x58, 0x2e, 0x96, 0x3d, 0xd6, 0x80, 0x37,
+	0x5d, 0xa3, 0x50, 0xf7, 0x18, 0x75, 0xe0, 0xc6, 0x74, 0xe1, 0x63, 0xad, 0x5e, 0xd6, 0x9b, 0xfa,
+	0xfb, 0xa0, 0xe6, 0x65, 0x44, 0x2b, 0xb0, 0x38, 0x26, 0xc7, 0xb2, 0x91, 0xd8, 0x27, 0x6a, 0x42,
+	0x65, 0x82, 0x0f, 0xd3, 0xac, 0x6f, 0xc4, 0xe1, 0xc3, 0x85, 0x0f, 0x14, 0xc3, 0x82, 0xd5, 0x7b,
+	0x1e, 0x75, 0x04, 0xe7, 0x6c, 0x3e, 0x3f, 0x86, 0xca, 0x53, 0xd6, 0x24, 0x72, 0x5f, 0x75, 0x2e,
+	0xd9, 0x49, 0x96, 0xb0, 0x32, 0x76, 0x01, 0xb1, 0xfd, 0x95, 0x4f, 0xd8, 0xdd, 0x83, 0x94, 0x8e,
+	0x51, 0x17, 0x2a, 0x6c, 0x16, 0xb3, 0xcd, 0x5a, 0xb6, 0x04, 0xe5, 0x3e, 0x15, 0x7a, 0xc6, 0x10,
+	0xd6, 0x72, 0x6a, 0x7b, 0x3b, 0x2f, 0x8a, 0xdc, 0x04, 0x9a, 0xd3, 0xa8, 0x72, 0x0b, 0xfc, 0x00,
+	0x6a, 0xb6, 0x51, 0x05, 0xc5, 0x46, 0xff, 0xb3, 0xab, 0xae, 0xd4, 0x5a, 0x8e, 0x5e, 0x93, 0x3b,
+	0x35, 0xee, 0x3d, 0x81, 0x15, 0x16, 0x22, 0xdf, 0xee, 0xd1, 0x83, 0xc3, 0xd4, 0xf5, 0x28, 0xfa,
+	0x16, 0xd4, 0x7c, 0xdb, 0xa3, 0xdb, 0x25, 0x81, 0xcc, 0x3e, 0x24, 0xfa, 0x9b, 0xe7, 0x2b, 0x89,
+	0x58, 0x7a, 0xff, 0x2d, 0x0a, 0x67, 0x16, 0xc1, 0x4e, 0xee, 0xec, 0x11, 0xd4, 0xb2, 0x57, 0x04,
+	0x19, 0x25, 0x30, 0x33, 0x4f, 0x8c, 0xbe, 0x51, 0xa2, 0x33, 0x5f, 0xd6, 0x77, 0x15, 0xf4, 0x3d,
+	0xd4, 0x0b, 0x0f, 0x03, 0xda, 0x28, 0xc7, 0x9e, 0x79, 0x4e, 0xf4, 0xcd, 0x8b, 0xd4, 0x64, 0x5d,
+	0x46, 0xb0, 0x3c, 0xb5, 0xb6, 0x51, 0xa7, 0xdc, 0x70, 0xee, 0x95, 0xd1, 0xb7, 0x2e, 0x56, 0x94,
+	0x3e, 0x1e, 0x03, 0x9c, 0x0d, 0x01, 0x2a, 0xcb, 0xf1, 0xdc, 0x8c, 0x5c, 0x3e, 0x3d, 0x36, 0x34,
+	0x8a, 0x0d, 0x87, 0x36, 0xcf, 0x83, 0x3f, 0xeb, 0x73, 0xbd, 0x73, 0xa1, 0x9e, 0xac, 0xf6, 0xcf,
+	0x0a, 0x68, 0xd3, 0xbf, 0x34, 0x85, 0xaa, 0x1f, 0xf0, 0x7f, 0x87, 0xe2, 0x35, 0x7a, 0xab, 0x3c,
+	0x2f, 0x25, 0x7f, 0x6d, 0xfa, 0xdb, 0x97, 0x51, 0x15, 0x34, 0xfa, 0x37, 0x9f, 0x9d, 0xb6, 0x94,
+	0x3f, 0x4f, 0x5b, 0xca, 0x3f, 0xa7, 0x2d, 0xe5, 0x8f, 0x7f, 0x5b, 0xca, 0x77, 0x20, 0xad, 0xec,
+	0xc9, 0xf6, 0xe8, 0x1a, 0x5f, 0xab, 0xef, 0xfd, 0x3f, 0x00, 0xab, 0x79, 0x4d, 0xed, 0xa9, 0x0a,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1694,6 +1724,42 @@ func (m *TraceQueryParameters) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintStorage(dAtA, i, uint64(m.NumTraces))
 	}
+	if len(m.PageToken) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.PageToken)))
+		i += copy(dAtA[i:], m.PageToken)
+	}
+	if len(m.ServiceNames) > 0 {
+		for _, s := range m.ServiceNames {
+			dAtA[i] = 0x52
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.OperationNames) > 0 {
+		for _, s := range m.OperationNames {
+			dAtA[i] = 0x5a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -2040,6 +2106,22 @@ func (m *TraceQueryParameters) Size() (n int) {
 	if m.NumTraces != 0 {
 		n += 1 + sovStorage(uint64(m.NumTraces))
 	}
+	l = len(m.PageToken)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	if len(m.ServiceNames) > 0 {
+		for _, s := range m.ServiceNames {
+			l = len(s)
+			n += 1 + l + sovStorage(uint64(l))
+		}
+	}
+	if len(m.OperationNames) > 0 {
+		for _, s := range m.OperationNames {
+			l = len(s)
+			n += 1 + l + sovStorage(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -3433,6 +3515,102 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PageToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PageToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceNames", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceNames = append(m.ServiceNames, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationNames", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OperationNames = append(m.OperationNames, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
@@ -0,0 +1,152 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencystore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/percentile"
+)
+
+type detailedKey struct {
+	parent          string
+	parentOperation string
+	child           string
+	childOperation  string
+	kind            model.DependencyLinkKind
+}
+
+type detailedLink struct {
+	callCount  uint64
+	errorCount uint64
+	latencies  []time.Duration
+}
+
+// DetailedLinks derives detailed dependency links from traces, for the readers implementing DetailedReader.
+//
+// A call is counted between a span and the span it references in another service, its CHILD_OF parent
+// or else the span it follows from. The call is a messaging dependency if the reference is FOLLOWS_FROM,
+// the parent is a producer or the child is a consumer, and an RPC dependency otherwise. Its error and
+// latency are the ones of the child span.
+type DetailedLinks struct {
+	links map[detailedKey]*detailedLink
+}
+
+// NewDetailedLinks creates an empty DetailedLinks.
+func NewDetailedLinks() *DetailedLinks {
+	return &DetailedLinks{links: make(map[detailedKey]*detailedLink)}
+}
+
+// AddTrace counts the calls between the spans of the trace.
+func (d *DetailedLinks) AddTrace(trace *model.Trace) {
+	spans := make(map[model.SpanID]*model.Span, len(trace.Spans))
+	for _, span := range trace.Spans {
+		spans[span.SpanID] = span
+	}
+	for _, span := range trace.Spans {
+		ref, ok := parentRef(span)
+		if !ok {
+			continue
+		}
+		parent, ok := spans[ref.SpanID]
+		if !ok || parent.Process == nil || span.Process == nil || parent.Process.ServiceName == span.Process.ServiceName {
+			continue
+		}
+		kind := model.RPCDependency
+		if ref.RefType == model.FollowsFrom ||
+			parent.HasSpanKind(ext.SpanKindProducerEnum) ||
+			span.HasSpanKind(ext.SpanKindConsumerEnum) {
+			kind = model.MessagingDependency
+		}
+		key := detailedKey{
+			parent:          parent.Process.ServiceName,
+			parentOperation: parent.OperationName,
+			child:           span.Process.ServiceName,
+			childOperation:  span.OperationName,
+			kind:            kind,
+		}
+		link, ok := d.links[key]
+		if !ok {
+			link = &detailedLink{}
+			d.links[key] = link
+		}
+		link.callCount++
+		if span.IsError() {
+			link.errorCount++
+		}
+		link.latencies = append(link.latencies, span.Duration)
+	}
+}
+
+// Links returns the dependency links of the added traces, sorted by parent and child.
+func (d *DetailedLinks) Links() []model.DependencyLink {
+	links := make([]model.DependencyLink, 0, len(d.links))
+	for key, link := range d.links {
+		sort.Slice(link.latencies, func(i, j int) bool { return link.latencies[i] < link.latencies[j] })
+		links = append(links, model.DependencyLink{
+			Parent:          key.parent,
+			Child:           key.child,
+			CallCount:       link.callCount,
+			ParentOperation: key.parentOperation,
+			ChildOperation:  key.childOperation,
+			Kind:            key.kind,
+			ErrorCount:      link.errorCount,
+			LatencyP50:      percentile.Durations(link.latencies, 50),
+			LatencyP95:      percentile.Durations(link.latencies, 95),
+			LatencyP99:      percentile.Durations(link.latencies, 99),
+		})
+	}
+	sort.Slice(links, func(i, j int) bool {
+		a, b := &links[i], &links[j]
+		switch {
+		case a.Parent != b.Parent:
+			return a.Parent < b.Parent
+		case a.Child != b.Child:
+			return a.Child < b.Child
+		case a.ParentOperation != b.ParentOperation:
+			return a.ParentOperation < b.ParentOperation
+		case a.ChildOperation != b.ChildOperation:
+			return a.ChildOperation < b.ChildOperation
+		default:
+			return a.Kind < b.Kind
+		}
+	})
+	return links
+}
+
+// parentRef returns the CHILD_OF reference of the span within its trace, or else its first FOLLOWS_FROM one.
+func parentRef(span *model.Span) (model.SpanRef, bool) {
+	var followsFrom *model.SpanRef
+	for i := range span.References {
+		ref := &span.References[i]
+		if ref.TraceID != span.TraceID {
+			continue
+		}
+		if ref.RefType == model.ChildOf {
+			return *ref, true
+		}
+		if followsFrom == nil {
+			followsFrom = ref
+		}
+	}
+	if followsFrom == nil {
+		return model.SpanRef{}, false
+	}
+	return *followsFrom, true
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencystore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var traceID = model.NewTraceID(0, 1)
+
+func span(spanID uint64, service, operation string, duration time.Duration, refs []model.SpanRef, tags ...model.KeyValue) *model.Span {
+	return &model.Span{
+		TraceID:       traceID,
+		SpanID:        model.NewSpanID(spanID),
+		OperationName: operation,
+		References:    refs,
+		Tags:          tags,
+		Duration:      duration,
+		Process:       model.NewProcess(service, nil),
+	}
+}
+
+func childOf(spanID uint64) []model.SpanRef {
+	return []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(spanID))}
+}
+
+func TestDetailedLinks(t *testing.T) {
+	links := NewDetailedLinks()
+	links.AddTrace(&model.Trace{Spans: []*model.Span{
+		span(1, "frontend", "GET /dispatch", 100*time.Millisecond, nil),
+		span(2, "frontend", "GET /customer", 50*time.Millisecond, childOf(1), model.String("span.kind", "client")),
+		span(3, "customer", "/customer", 40*time.Millisecond, childOf(2), model.Bool("error", true)),
+		span(4, "frontend", "GET /customer", 30*time.Millisecond, childOf(1), model.String("span.kind", "client")),
+		span(5, "customer", "/customer", 20*time.Millisecond, childOf(4)),
+		// producer -> consumer
+		span(6, "frontend", "send", time.Millisecond, childOf(1), model.String("span.kind", "producer")),
+		span(7, "driver", "receive", 10*time.Millisecond, childOf(6), model.String("span.kind", "consumer")),
+		// follows from
+		span(8, "driver", "process", 5*time.Millisecond, []model.SpanRef{model.NewFollowsFromRef(traceID, model.NewSpanID(1))}),
+		// references to another trace or to missing spans are ignored
+		span(9, "driver", "other", time.Millisecond, []model.SpanRef{model.NewChildOfRef(model.NewTraceID(0, 2), model.NewSpanID(1))}),
+		span(10, "driver", "missing", time.Millisecond, childOf(42)),
+	}})
+
+	assert.Equal(t, []model.DependencyLink{
+		{
+			Parent:          "frontend",
+			Child:           "customer",
+			CallCount:       2,
+			ParentOperation: "GET /customer",
+			ChildOperation:  "/customer",
+			Kind:            model.RPCDependency,
+			ErrorCount:      1,
+			LatencyP50:      20 * time.Millisecond,
+			LatencyP95:      40 * time.Millisecond,
+			LatencyP99:      40 * time.Millisecond,
+		},
+		{
+			Parent:          "frontend",
+			Child:           "driver",
+			CallCount:       1,
+			ParentOperation: "GET /dispatch",
+			ChildOperation:  "process",
+			Kind:            model.MessagingDependency,
+			LatencyP50:      5 * time.Millisecond,
+			LatencyP95:      5 * time.Millisecond,
+			LatencyP99:      5 * time.Millisecond,
+		},
+		{
+			Parent:          "frontend",
+			Child:           "driver",
+			CallCount:       1,
+			ParentOperation: "send",
+			ChildOperation:  "receive",
+			Kind:            model.MessagingDependency,
+			LatencyP50:      10 * time.Millisecond,
+			LatencyP95:      10 * time.Millisecond,
+			LatencyP99:      10 * time.Millisecond,
+		},
+	}, links.Links())
+}
@@ -16,6 +16,7 @@
 package dependencystore
 
 import (
+	"errors"
 	"time"
 
 	"github.com/jaegertracing/jaeger/model"
@@ -30,3 +31,13 @@ type Writer interface {
 type Reader interface {
 	GetDependencies(endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error)
 }
+
+// ErrDetailedDependenciesNotSupported is returned when the dependency reader does not implement DetailedReader.
+var ErrDetailedDependenciesNotSupported = errors.New("detailed dependencies not supported")
+
+// DetailedReader is an additional interface that can be implemented by a Reader to return
+// dependency links split by parent and child operation and by kind, with their error count
+// and latency percentiles.
+type DetailedReader interface {
+	GetDetailedDependencies(endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error)
+}
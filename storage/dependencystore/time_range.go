@@ -0,0 +1,56 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencystore
+
+import (
+	"sort"
+	"time"
+)
+
+// TimeRange is the time range from Start to End, e.g. covered by written dependency links.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// UncoveredRanges returns the parts of the time range from start to end that are not covered by any of the
+// ranges, in chronological order. The stores derive the dependency links of these parts from the traces.
+func UncoveredRanges(start, end time.Time, covered []TimeRange) []TimeRange {
+	sorted := append([]TimeRange(nil), covered...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+	var uncovered []TimeRange
+	cursor := start
+	for _, r := range sorted {
+		if !cursor.Before(end) {
+			break
+		}
+		if r.Start.After(cursor) {
+			gapEnd := r.Start
+			if gapEnd.After(end) {
+				gapEnd = end
+			}
+			uncovered = append(uncovered, TimeRange{Start: cursor, End: gapEnd})
+		}
+		if r.End.After(cursor) {
+			cursor = r.End
+		}
+	}
+	if cursor.Before(end) {
+		uncovered = append(uncovered, TimeRange{Start: cursor, End: end})
+	}
+	return uncovered
+}
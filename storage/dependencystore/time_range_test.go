@@ -0,0 +1,61 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencystore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUncoveredRanges(t *testing.T) {
+	at := func(minutes int) time.Time {
+		return time.Unix(0, 0).Add(time.Duration(minutes) * time.Minute)
+	}
+	tr := func(start, end int) TimeRange {
+		return TimeRange{Start: at(start), End: at(end)}
+	}
+	testCases := []struct {
+		caption  string
+		covered  []TimeRange
+		expected []TimeRange
+	}{
+		{
+			caption:  "nothing covered",
+			expected: []TimeRange{tr(10, 20)},
+		},
+		{
+			caption: "all covered",
+			covered: []TimeRange{tr(5, 15), tr(15, 25)},
+		},
+		{
+			caption:  "gaps before, between and after",
+			covered:  []TimeRange{tr(16, 18), tr(12, 14)},
+			expected: []TimeRange{tr(10, 12), tr(14, 16), tr(18, 20)},
+		},
+		{
+			caption:  "overlapping and outside ranges",
+			covered:  []TimeRange{tr(0, 5), tr(11, 15), tr(12, 13), tr(25, 30)},
+			expected: []TimeRange{tr(10, 11), tr(15, 20)},
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, UncoveredRanges(at(10), at(20), testCase.covered))
+		})
+	}
+}
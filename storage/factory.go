@@ -62,3 +62,23 @@ type ArchiveFactory interface {
 	// CreateArchiveSpanWriter creates a spanstore.Writer.
 	CreateArchiveSpanWriter() (spanstore.Writer, error)
 }
+
+// ErrDependencyWriterNotSupported can be returned by the DependencyWriterFactory when the backend cannot store dependencies.
+var ErrDependencyWriterNotSupported = errors.New("writing dependencies not supported")
+
+// DependencyWriterFactory is an additional interface that can be implemented by a factory
+// to support storing the dependencies derived by the collector.
+type DependencyWriterFactory interface {
+	// CreateDependencyWriter creates a dependencystore.Writer.
+	CreateDependencyWriter() (dependencystore.Writer, error)
+}
+
+// ErrCatalogNotSupported can be returned by the CatalogFactory when the backend does not track the service catalog.
+var ErrCatalogNotSupported = errors.New("service catalog not supported")
+
+// CatalogFactory is an additional interface that can be implemented by a factory
+// whose span writers track the activity of the services.
+type CatalogFactory interface {
+	// CreateCatalogReader creates a spanstore.CatalogReader of the spans written by the span writers of the factory.
+	CreateCatalogReader() (spanstore.CatalogReader, error)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+	"time"
+)
+
+// ServiceActivity describes the recent activity of a service, as seen by the span writers.
+type ServiceActivity struct {
+	ServiceName string
+	// LastSeen is the time the last span of the service was written.
+	LastSeen time.Time
+	// SpanRate1m, SpanRate5m and SpanRate15m are the number of spans written per second,
+	// averaged over the last 1, 5 and 15 minutes.
+	SpanRate1m  float64
+	SpanRate5m  float64
+	SpanRate15m float64
+	// OperationCount is the number of distinct operations of the service.
+	OperationCount int
+	// ClientVersions, Hostnames and ClientUUIDs are the distinct values of the jaeger.version,
+	// hostname and client-uuid process tags of the service, sorted.
+	ClientVersions []string
+	Hostnames      []string
+	ClientUUIDs    []string
+}
+
+// CatalogReader returns the activity of the services whose spans were written.
+type CatalogReader interface {
+	// GetServiceCatalog returns the activity of each service, sorted by service name.
+	GetServiceCatalog(ctx context.Context) ([]ServiceActivity, error)
+}
@@ -46,12 +46,79 @@ type Reader interface {
 type TraceQueryParameters struct {
 	ServiceName   string
 	OperationName string
-	Tags          map[string]string
+	// ServiceNames and OperationNames extend ServiceName and OperationName with alternatives,
+	// a span matches if its service and its operation are each any of the given ones.
+	ServiceNames   []string
+	OperationNames []string
+	Tags           map[string]string
+	// TagPredicates are conditions on span tags beyond exact matches, each group must be
+	// satisfied by the same span that satisfies the other span-level parameters.
+	TagPredicates []TagPredicateGroup
 	StartTimeMin  time.Time
 	StartTimeMax  time.Time
 	DurationMin   time.Duration
 	DurationMax   time.Duration
 	NumTraces     int
+	// TracePredicates are conditions on the whole trace, evaluated in addition to the span-level parameters.
+	TracePredicates *TracePredicates
+	// PageToken is the opaque continuation token returned with the previous page of results,
+	// see PageToken for the ordering it relies on.
+	PageToken string
+}
+
+// Services returns ServiceName and ServiceNames without duplicates.
+func (p *TraceQueryParameters) Services() []string {
+	return uniqueNames(p.ServiceName, p.ServiceNames)
+}
+
+// Operations returns OperationName and OperationNames without duplicates.
+func (p *TraceQueryParameters) Operations() []string {
+	return uniqueNames(p.OperationName, p.OperationNames)
+}
+
+// SplitByServiceAndOperation returns a copy of the query for each combination of its services
+// and operations, each with a single ServiceName and OperationName, for backends that
+// look up their indexes one service and operation at a time.
+func (p *TraceQueryParameters) SplitByServiceAndOperation() []*TraceQueryParameters {
+	services := p.Services()
+	if len(services) == 0 {
+		services = []string{""}
+	}
+	operations := p.Operations()
+	if len(operations) == 0 {
+		operations = []string{""}
+	}
+	queries := make([]*TraceQueryParameters, 0, len(services)*len(operations))
+	for _, service := range services {
+		for _, operation := range operations {
+			query := *p
+			query.ServiceName = service
+			query.OperationName = operation
+			query.ServiceNames = nil
+			query.OperationNames = nil
+			queries = append(queries, &query)
+		}
+	}
+	return queries
+}
+
+func uniqueNames(name string, names []string) []string {
+	if len(names) == 0 {
+		if name == "" {
+			return nil
+		}
+		return []string{name}
+	}
+	var unique []string
+	seen := make(map[string]struct{}, len(names)+1)
+	for _, n := range append([]string{name}, names...) {
+		if _, ok := seen[n]; ok || n == "" {
+			continue
+		}
+		seen[n] = struct{}{}
+		unique = append(unique, n)
+	}
+	return unique
 }
 
 // OperationQueryParameters contains parameters of query operations, empty spanKind means get operations for all kinds of span.
@@ -0,0 +1,102 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// MatchSpan returns true if the span satisfies the span-level parameters of the query,
+// i.e. service, operation, duration, start time, tags and tag predicates. No services match any service, and likewise for operations.
+func MatchSpan(query *TraceQueryParameters, span *model.Span) bool {
+	if services := query.Services(); len(services) > 0 && !containsName(services, span.Process.ServiceName) {
+		return false
+	}
+	if operations := query.Operations(); len(operations) > 0 && !containsName(operations, span.OperationName) {
+		return false
+	}
+	if query.DurationMin != 0 && span.Duration < query.DurationMin {
+		return false
+	}
+	if query.DurationMax != 0 && span.Duration > query.DurationMax {
+		return false
+	}
+	if !query.StartTimeMin.IsZero() && span.StartTime.Before(query.StartTimeMin) {
+		return false
+	}
+	if !query.StartTimeMax.IsZero() && span.StartTime.After(query.StartTimeMax) {
+		return false
+	}
+	spanKVs := flattenTags(span)
+	for queryK, queryV := range query.Tags {
+		// (NB): we cannot use the KeyValues.FindKey function because there can be multiple tags with the same key
+		if !findKeyValueMatch(spanKVs, queryK, queryV) {
+			return false
+		}
+	}
+	for _, group := range query.TagPredicates {
+		if !group.matchKeyValues(spanKVs) {
+			return false
+		}
+	}
+	return true
+}
+
+// LatestMatchingSpanTime returns the start time of the most recent span in the trace
+// that satisfies the query, and false if no span matches. This is the sort key
+// that the storage backends use to order trace search results.
+func LatestMatchingSpanTime(query *TraceQueryParameters, trace *model.Trace) (time.Time, bool) {
+	var latest time.Time
+	found := false
+	for _, span := range trace.Spans {
+		if !MatchSpan(query, span) {
+			continue
+		}
+		if !found || span.StartTime.After(latest) {
+			latest = span.StartTime
+			found = true
+		}
+	}
+	return latest, found
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func findKeyValueMatch(kvs model.KeyValues, key, value string) bool {
+	for _, kv := range kvs {
+		if kv.Key == key && kv.AsString() == value {
+			return true
+		}
+	}
+	return false
+}
+
+func flattenTags(span *model.Span) model.KeyValues {
+	retMe := append(model.KeyValues(nil), span.Tags...)
+	retMe = append(retMe, span.Process.Tags...)
+	for _, l := range span.Logs {
+		retMe = append(retMe, l.Fields...)
+	}
+	return retMe
+}
@@ -97,6 +97,15 @@ func (m *ReadMetricsDecorator) GetTrace(ctx context.Context, traceID model.Trace
 	return retMe, err
 }
 
+// StreamTrace implements spanstore.TraceStreamer, streaming the trace from the wrapped reader if it supports it.
+// The latency recorded as that of GetTrace includes the time spent in fn.
+func (m *ReadMetricsDecorator) StreamTrace(ctx context.Context, traceID model.TraceID, fn func(part *model.Trace) bool) error {
+	start := time.Now()
+	err := spanstore.StreamTrace(ctx, m.spanReader, traceID, fn)
+	m.getTraceMetrics.emit(err, time.Since(start), 1)
+	return err
+}
+
 // GetServices implements spanstore.Reader#GetServices
 func (m *ReadMetricsDecorator) GetServices(ctx context.Context) ([]string, error) {
 	start := time.Now()
@@ -115,3 +124,19 @@ func (m *ReadMetricsDecorator) GetOperations(
 	m.getOperationsMetrics.emit(err, time.Since(start), len(retMe))
 	return retMe, err
 }
+
+// SupportsTagPredicate implements spanstore.TagPredicateSupport by delegating to the wrapped reader.
+func (m *ReadMetricsDecorator) SupportsTagPredicate(predicate spanstore.TagPredicate) bool {
+	if support, ok := m.spanReader.(spanstore.TagPredicateSupport); ok {
+		return support.SupportsTagPredicate(predicate)
+	}
+	return false
+}
+
+// SupportsTracePredicates implements spanstore.TracePredicateSupport by delegating to the wrapped reader.
+func (m *ReadMetricsDecorator) SupportsTracePredicates(predicates *spanstore.TracePredicates) bool {
+	if support, ok := m.spanReader.(spanstore.TracePredicateSupport); ok {
+		return support.SupportsTracePredicates(predicates)
+	}
+	return false
+}
@@ -142,3 +142,81 @@ func TestFailingUnderlyingCalls(t *testing.T) {
 
 	checkExpectedExistingAndNonExistentCounters(t, counters, expecteds, gauges, existingKeys, nonExistentKeys)
 }
+
+type predicateReader struct {
+	mocks.Reader
+}
+
+func (r *predicateReader) SupportsTagPredicate(predicate spanstore.TagPredicate) bool {
+	return predicate.Operator == spanstore.TagOpEqual
+}
+
+func (r *predicateReader) SupportsTracePredicates(predicates *spanstore.TracePredicates) bool {
+	return len(predicates.Services) == 0
+}
+
+func TestSupportsTagPredicate(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	equal := spanstore.TagPredicate{Key: "k", Operator: spanstore.TagOpEqual, Value: "v"}
+	prefix := spanstore.TagPredicate{Key: "k", Operator: spanstore.TagOpPrefix, Value: "v"}
+
+	mrs := NewReadMetricsDecorator(&mocks.Reader{}, mf)
+	assert.False(t, mrs.SupportsTagPredicate(equal))
+
+	mrs = NewReadMetricsDecorator(&predicateReader{}, mf)
+	assert.True(t, mrs.SupportsTagPredicate(equal))
+	assert.False(t, mrs.SupportsTagPredicate(prefix))
+}
+
+func TestSupportsTracePredicates(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	spanCount := &spanstore.TracePredicates{MinSpanCount: 10}
+	services := &spanstore.TracePredicates{Services: []string{"a", "b"}}
+
+	mrs := NewReadMetricsDecorator(&mocks.Reader{}, mf)
+	assert.False(t, mrs.SupportsTracePredicates(spanCount))
+
+	mrs = NewReadMetricsDecorator(&predicateReader{}, mf)
+	assert.True(t, mrs.SupportsTracePredicates(spanCount))
+	assert.False(t, mrs.SupportsTracePredicates(services))
+}
+
+type streamingReader struct {
+	mocks.Reader
+}
+
+func (r *streamingReader) StreamTrace(ctx context.Context, traceID model.TraceID, fn func(part *model.Trace) bool) error {
+	for i := 0; i < 2; i++ {
+		if !fn(&model.Trace{Spans: []*model.Span{{TraceID: traceID}}}) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestStreamTrace(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	mockReader := &mocks.Reader{}
+	mockReader.On("GetTrace", context.Background(), model.NewTraceID(0, 1)).
+		Return(&model.Trace{Spans: []*model.Span{{}, {}}}, nil)
+	mrs := NewReadMetricsDecorator(mockReader, mf)
+
+	var parts []int
+	err := mrs.StreamTrace(context.Background(), model.NewTraceID(0, 1), func(part *model.Trace) bool {
+		parts = append(parts, len(part.Spans))
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, parts)
+
+	mrs = NewReadMetricsDecorator(&streamingReader{}, mf)
+	parts = nil
+	err = mrs.StreamTrace(context.Background(), model.NewTraceID(0, 1), func(part *model.Trace) bool {
+		parts = append(parts, len(part.Spans))
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 1}, parts)
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 2, counters["requests|operation=get_trace|result=ok"])
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"context"
+)
+import "github.com/stretchr/testify/mock"
+import "github.com/jaegertracing/jaeger/storage/spanstore"
+
+// CatalogReader is an autogenerated mock type for the CatalogReader type
+type CatalogReader struct {
+	mock.Mock
+}
+
+// GetServiceCatalog provides a mock function with given fields: ctx
+func (_m *CatalogReader) GetServiceCatalog(ctx context.Context) ([]spanstore.ServiceActivity, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []spanstore.ServiceActivity
+	if rf, ok := ret.Get(0).(func(context.Context) []spanstore.ServiceActivity); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]spanstore.ServiceActivity)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// ErrMalformedPageToken is returned when a page token cannot be decoded.
+var ErrMalformedPageToken = errors.New("malformed page token")
+
+// PageToken is the decoded form of the opaque continuation token of a trace search.
+//
+// Trace search results are ordered by the start time of the most recent span matching
+// the query, newest first, with ties broken by trace ID in descending order.
+// A PageToken holds the sort key of the last trace of a page, and the next page
+// contains the traces that sort strictly after it.
+type PageToken struct {
+	StartTime time.Time
+	TraceID   model.TraceID
+}
+
+// String encodes the token into its opaque URL-safe form.
+func (t PageToken) String() string {
+	raw := strconv.FormatUint(model.TimeAsEpochMicroseconds(t.StartTime), 10) + ":" + t.TraceID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParsePageToken decodes an opaque page token. It returns nil without error for an empty token.
+func ParsePageToken(token string) (*PageToken, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrMalformedPageToken
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 2 {
+		return nil, ErrMalformedPageToken
+	}
+	micros, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", ErrMalformedPageToken, err)
+	}
+	traceID, err := model.TraceIDFromString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", ErrMalformedPageToken, err)
+	}
+	return &PageToken{
+		StartTime: model.EpochMicrosecondsAsTime(micros),
+		TraceID:   traceID,
+	}, nil
+}
+
+// NewPageToken returns the token positioned at the given trace of the query results.
+func NewPageToken(query *TraceQueryParameters, trace *model.Trace) PageToken {
+	return PageToken{StartTime: pageSortKey(query, trace), TraceID: traceIDOf(trace)}
+}
+
+// Admits returns true if a trace with the given sort key belongs to a page after the token.
+// A nil token admits everything.
+func (t *PageToken) Admits(startTime time.Time, traceID model.TraceID) bool {
+	if t == nil {
+		return true
+	}
+	tokenMicros := model.TimeAsEpochMicroseconds(t.StartTime)
+	micros := model.TimeAsEpochMicroseconds(startTime)
+	if micros != tokenMicros {
+		return micros < tokenMicros
+	}
+	return compareTraceIDs(traceID, t.TraceID) < 0
+}
+
+// AdmitsTrace returns true if the trace of the query results belongs to a page after the token.
+func (t *PageToken) AdmitsTrace(query *TraceQueryParameters, trace *model.Trace) bool {
+	return t.Admits(pageSortKey(query, trace), traceIDOf(trace))
+}
+
+// BoundQuery returns the query restricted to the spans that are not newer than the token, like the index scans
+// of the backends for the page after the token. A trace found again by such a scan, because it has matching
+// spans on both sides of the token, sorts by its latest matching span up to the token for the bounded query.
+func (t *PageToken) BoundQuery(query *TraceQueryParameters) *TraceQueryParameters {
+	if t == nil || (!query.StartTimeMax.IsZero() && query.StartTimeMax.Before(t.StartTime)) {
+		return query
+	}
+	bounded := *query
+	bounded.StartTimeMax = t.StartTime
+	return &bounded
+}
+
+// SortPageTokens sorts the sort keys of traces, held in tokens, in the order used by paged searches.
+func SortPageTokens(tokens []PageToken) {
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Admits(tokens[j].StartTime, tokens[j].TraceID)
+	})
+}
+
+// SortTracesForPaging sorts traces in the order used by paged searches.
+func SortTracesForPaging(query *TraceQueryParameters, traces []*model.Trace) {
+	keys := make(map[*model.Trace]time.Time, len(traces))
+	for _, trace := range traces {
+		keys[trace] = pageSortKey(query, trace)
+	}
+	sort.SliceStable(traces, func(i, j int) bool {
+		ki, kj := keys[traces[i]], keys[traces[j]]
+		if !ki.Equal(kj) {
+			return ki.After(kj)
+		}
+		return compareTraceIDs(traceIDOf(traces[i]), traceIDOf(traces[j])) > 0
+	})
+}
+
+// NextPageToken returns the token for the page following the given traces, which must be
+// sorted with SortTracesForPaging. It returns an empty string if the page is not full,
+// meaning there are no more results.
+func NextPageToken(query *TraceQueryParameters, traces []*model.Trace) string {
+	if query.NumTraces <= 0 || len(traces) < query.NumTraces {
+		return ""
+	}
+	return NewPageToken(query, traces[len(traces)-1]).String()
+}
+
+// pageSortKey falls back to the latest span of the trace when no span matches the query,
+// which can happen when a backend evaluates the query differently, e.g. with analyzed tag values.
+func pageSortKey(query *TraceQueryParameters, trace *model.Trace) time.Time {
+	if latest, found := LatestMatchingSpanTime(query, trace); found {
+		return latest
+	}
+	var latest time.Time
+	for _, span := range trace.Spans {
+		if span.StartTime.After(latest) {
+			latest = span.StartTime
+		}
+	}
+	return latest
+}
+
+func traceIDOf(trace *model.Trace) model.TraceID {
+	if len(trace.Spans) == 0 {
+		return model.TraceID{}
+	}
+	return trace.Spans[0].TraceID
+}
+
+func compareTraceIDs(a, b model.TraceID) int {
+	switch {
+	case a.High != b.High:
+		if a.High < b.High {
+			return -1
+		}
+		return 1
+	case a.Low != b.Low:
+		if a.Low < b.Low {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
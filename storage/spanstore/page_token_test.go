@@ -0,0 +1,67 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	token := PageToken{StartTime: time.Unix(100, 1000), TraceID: model.NewTraceID(1, 2)}
+	parsed, err := ParsePageToken(token.String())
+	require.NoError(t, err)
+	assert.Equal(t, token.TraceID, parsed.TraceID)
+	assert.True(t, token.StartTime.Equal(parsed.StartTime))
+
+	parsed, err = ParsePageToken("")
+	require.NoError(t, err)
+	assert.Nil(t, parsed)
+
+	_, err = ParsePageToken("?")
+	assert.Equal(t, ErrMalformedPageToken, err)
+}
+
+func TestPageTokenBoundQuery(t *testing.T) {
+	query := &TraceQueryParameters{StartTimeMin: time.Unix(0, 0), StartTimeMax: time.Unix(100, 0)}
+	var token *PageToken
+	assert.Equal(t, query, token.BoundQuery(query))
+
+	token = &PageToken{StartTime: time.Unix(50, 0)}
+	assert.Equal(t, time.Unix(50, 0), token.BoundQuery(query).StartTimeMax)
+	assert.Equal(t, time.Unix(100, 0), query.StartTimeMax, "the query is not modified")
+
+	token = &PageToken{StartTime: time.Unix(150, 0)}
+	assert.Equal(t, query, token.BoundQuery(query))
+}
+
+func TestSortPageTokens(t *testing.T) {
+	tokens := []PageToken{
+		{StartTime: time.Unix(1, 0), TraceID: model.NewTraceID(0, 3)},
+		{StartTime: time.Unix(2, 0), TraceID: model.NewTraceID(0, 1)},
+		{StartTime: time.Unix(1, 0), TraceID: model.NewTraceID(1, 0)},
+	}
+	SortPageTokens(tokens)
+	assert.Equal(t, []PageToken{
+		{StartTime: time.Unix(2, 0), TraceID: model.NewTraceID(0, 1)},
+		{StartTime: time.Unix(1, 0), TraceID: model.NewTraceID(1, 0)},
+		{StartTime: time.Unix(1, 0), TraceID: model.NewTraceID(0, 3)},
+	}, tokens)
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// TagOperator is the comparison applied by a TagPredicate.
+type TagOperator int
+
+const (
+	// TagOpEqual matches a tag with the given key and value.
+	TagOpEqual TagOperator = iota
+	// TagOpNotEqual matches a span that has no tag with the given key and value.
+	TagOpNotEqual
+	// TagOpExists matches a tag with the given key and any value.
+	TagOpExists
+	// TagOpNotExists matches a span that has no tag with the given key.
+	TagOpNotExists
+	// TagOpGreater matches a tag with the given key and a numeric value greater than the given one.
+	TagOpGreater
+	// TagOpGreaterOrEqual matches a tag with the given key and a numeric value greater than or equal to the given one.
+	TagOpGreaterOrEqual
+	// TagOpLess matches a tag with the given key and a numeric value less than the given one.
+	TagOpLess
+	// TagOpLessOrEqual matches a tag with the given key and a numeric value less than or equal to the given one.
+	TagOpLessOrEqual
+	// TagOpPrefix matches a tag with the given key and a value starting with the given one.
+	TagOpPrefix
+	// TagOpRegex matches a tag with the given key and a value matching the given regular expression.
+	TagOpRegex
+)
+
+var tagOperatorNames = map[TagOperator]string{
+	TagOpEqual:          "=",
+	TagOpNotEqual:       "!=",
+	TagOpExists:         "exists",
+	TagOpNotExists:      "!exists",
+	TagOpGreater:        ">",
+	TagOpGreaterOrEqual: ">=",
+	TagOpLess:           "<",
+	TagOpLessOrEqual:    "<=",
+	TagOpPrefix:         "^=",
+	TagOpRegex:          "=~",
+}
+
+func (op TagOperator) String() string {
+	if name, ok := tagOperatorNames[op]; ok {
+		return name
+	}
+	return fmt.Sprintf("TagOperator(%d)", int(op))
+}
+
+// IsNumeric returns true if the operator compares tag values as numbers.
+func (op TagOperator) IsNumeric() bool {
+	return op == TagOpGreater || op == TagOpGreaterOrEqual || op == TagOpLess || op == TagOpLessOrEqual
+}
+
+var errEmptyTagPredicateKey = errors.New("tag predicate key cannot be empty")
+
+// TagPredicate is a condition on the tags of a span. Like the exact matches of
+// TraceQueryParameters.Tags, it is evaluated against span tags, process tags and log fields,
+// with tag values compared in their string form, except for numeric operators.
+type TagPredicate struct {
+	Key      string
+	Operator TagOperator
+	// Value is ignored by TagOpExists and TagOpNotExists.
+	Value string
+
+	pattern *regexp.Regexp
+}
+
+// NewTagPredicate returns a validated TagPredicate, i.e. one with a numeric value for
+// numeric operators and a valid regular expression for TagOpRegex.
+func NewTagPredicate(key string, op TagOperator, value string) (TagPredicate, error) {
+	p := TagPredicate{Key: key, Operator: op, Value: value}
+	if key == "" {
+		return p, errEmptyTagPredicateKey
+	}
+	if _, ok := tagOperatorNames[op]; !ok {
+		return p, fmt.Errorf("unknown tag operator %v", op)
+	}
+	if op.IsNumeric() {
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return p, fmt.Errorf("tag predicate %s%s%s requires a numeric value: %w", key, op, value, err)
+		}
+	}
+	if op == TagOpRegex {
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return p, fmt.Errorf("tag predicate %s%s%s requires a valid regular expression: %w", key, op, value, err)
+		}
+		p.pattern = pattern
+	}
+	return p, nil
+}
+
+// TagPredicateGroup is a disjunction of tag predicates, it matches a span if any of its predicates does.
+type TagPredicateGroup []TagPredicate
+
+// TagPredicateSupport is implemented by Readers that evaluate some tag predicates natively.
+// QueryService evaluates the predicates that a Reader does not support, or all of them
+// if the Reader does not implement this interface, by filtering the search results.
+type TagPredicateSupport interface {
+	SupportsTagPredicate(predicate TagPredicate) bool
+}
+
+// MatchSpan returns true if the span satisfies the predicate.
+func (p TagPredicate) MatchSpan(span *model.Span) bool {
+	return p.matchKeyValues(flattenTags(span))
+}
+
+func (p TagPredicate) matchKeyValues(kvs model.KeyValues) bool {
+	switch p.Operator {
+	case TagOpNotEqual:
+		return !p.anyKeyValue(kvs, func(kv model.KeyValue) bool { return kv.AsString() == p.Value })
+	case TagOpNotExists:
+		return !p.anyKeyValue(kvs, func(kv model.KeyValue) bool { return true })
+	}
+	return p.anyKeyValue(kvs, p.matchValue)
+}
+
+func (p TagPredicate) anyKeyValue(kvs model.KeyValues, match func(kv model.KeyValue) bool) bool {
+	// (NB): we cannot use the KeyValues.FindKey function because there can be multiple tags with the same key
+	for _, kv := range kvs {
+		if kv.Key == p.Key && match(kv) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p TagPredicate) matchValue(kv model.KeyValue) bool {
+	switch p.Operator {
+	case TagOpEqual:
+		return kv.AsString() == p.Value
+	case TagOpExists:
+		return true
+	case TagOpPrefix:
+		return strings.HasPrefix(kv.AsString(), p.Value)
+	case TagOpRegex:
+		pattern := p.pattern
+		if pattern == nil {
+			// the predicate was not built with NewTagPredicate
+			var err error
+			if pattern, err = regexp.Compile(p.Value); err != nil {
+				return false
+			}
+		}
+		return pattern.MatchString(kv.AsString())
+	}
+	if !p.Operator.IsNumeric() {
+		return false
+	}
+	value, ok := numericValue(kv)
+	if !ok {
+		return false
+	}
+	bound, err := strconv.ParseFloat(p.Value, 64)
+	if err != nil {
+		return false
+	}
+	switch p.Operator {
+	case TagOpGreater:
+		return value > bound
+	case TagOpGreaterOrEqual:
+		return value >= bound
+	case TagOpLess:
+		return value < bound
+	default:
+		return value <= bound
+	}
+}
+
+func numericValue(kv model.KeyValue) (float64, bool) {
+	switch kv.VType {
+	case model.Int64Type:
+		return float64(kv.Int64()), true
+	case model.Float64Type:
+		return kv.Float64(), true
+	case model.StringType:
+		value, err := strconv.ParseFloat(kv.VStr, 64)
+		return value, err == nil
+	}
+	return 0, false
+}
+
+// MatchSpan returns true if the span satisfies any predicate of the group.
+func (g TagPredicateGroup) MatchSpan(span *model.Span) bool {
+	return g.matchKeyValues(flattenTags(span))
+}
+
+func (g TagPredicateGroup) matchKeyValues(kvs model.KeyValues) bool {
+	for _, p := range g {
+		if p.matchKeyValues(kvs) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestNewTagPredicate(t *testing.T) {
+	testCases := []struct {
+		key      string
+		op       TagOperator
+		value    string
+		errorMsg string
+	}{
+		{key: "k", op: TagOpEqual, value: "v"},
+		{key: "k", op: TagOpExists},
+		{key: "k", op: TagOpGreaterOrEqual, value: "500"},
+		{key: "k", op: TagOpRegex, value: "^/api/.*"},
+		{key: "", op: TagOpEqual, value: "v", errorMsg: "tag predicate key cannot be empty"},
+		{key: "k", op: TagOperator(100), value: "v", errorMsg: "unknown tag operator TagOperator(100)"},
+		{key: "k", op: TagOpLess, value: "abc", errorMsg: `tag predicate k<abc requires a numeric value: strconv.ParseFloat: parsing "abc": invalid syntax`},
+		{key: "k", op: TagOpRegex, value: "(", errorMsg: "tag predicate k=~( requires a valid regular expression: error parsing regexp: missing closing ): `(`"},
+	}
+	for _, testCase := range testCases {
+		p, err := NewTagPredicate(testCase.key, testCase.op, testCase.value)
+		if testCase.errorMsg != "" {
+			assert.EqualError(t, err, testCase.errorMsg)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, testCase.key, p.Key)
+		assert.Equal(t, testCase.op, p.Operator)
+		assert.Equal(t, testCase.value, p.Value)
+	}
+}
+
+func TestTagPredicateMatchSpan(t *testing.T) {
+	span := &model.Span{
+		Tags: model.KeyValues{
+			model.String("http.url", "/api/traces"),
+			model.Int64("http.status_code", 503),
+			model.String("peer.port", "8080"),
+			model.Bool("error", true),
+		},
+		Process: &model.Process{
+			Tags: model.KeyValues{model.String("hostname", "host-1")},
+		},
+		Logs: []model.Log{
+			{Fields: model.KeyValues{model.Float64("retry.delay", 1.5)}},
+		},
+	}
+	testCases := []struct {
+		predicate TagPredicate
+		expected  bool
+	}{
+		{TagPredicate{Key: "http.url", Operator: TagOpEqual, Value: "/api/traces"}, true},
+		{TagPredicate{Key: "http.url", Operator: TagOpEqual, Value: "/api"}, false},
+		{TagPredicate{Key: "error", Operator: TagOpEqual, Value: "true"}, true},
+		{TagPredicate{Key: "hostname", Operator: TagOpNotEqual, Value: "host-1"}, false},
+		{TagPredicate{Key: "hostname", Operator: TagOpNotEqual, Value: "host-2"}, true},
+		{TagPredicate{Key: "missing", Operator: TagOpNotEqual, Value: "v"}, true},
+		{TagPredicate{Key: "retry.delay", Operator: TagOpExists}, true},
+		{TagPredicate{Key: "missing", Operator: TagOpExists}, false},
+		{TagPredicate{Key: "missing", Operator: TagOpNotExists}, true},
+		{TagPredicate{Key: "error", Operator: TagOpNotExists}, false},
+		{TagPredicate{Key: "http.status_code", Operator: TagOpGreaterOrEqual, Value: "500"}, true},
+		{TagPredicate{Key: "http.status_code", Operator: TagOpGreater, Value: "503"}, false},
+		{TagPredicate{Key: "http.status_code", Operator: TagOpLess, Value: "503.5"}, true},
+		{TagPredicate{Key: "http.status_code", Operator: TagOpLessOrEqual, Value: "400"}, false},
+		{TagPredicate{Key: "peer.port", Operator: TagOpGreater, Value: "1024"}, true},
+		{TagPredicate{Key: "retry.delay", Operator: TagOpLess, Value: "2"}, true},
+		{TagPredicate{Key: "http.url", Operator: TagOpGreater, Value: "0"}, false},
+		{TagPredicate{Key: "error", Operator: TagOpGreater, Value: "0"}, false},
+		{TagPredicate{Key: "http.status_code", Operator: TagOpGreater, Value: "abc"}, false},
+		{TagPredicate{Key: "http.url", Operator: TagOpPrefix, Value: "/api/"}, true},
+		{TagPredicate{Key: "http.url", Operator: TagOpPrefix, Value: "/ui/"}, false},
+		{TagPredicate{Key: "http.url", Operator: TagOpRegex, Value: "^/api/(traces|services)$"}, true},
+		{TagPredicate{Key: "http.url", Operator: TagOpRegex, Value: "^/ui"}, false},
+		{TagPredicate{Key: "http.url", Operator: TagOpRegex, Value: "("}, false},
+		{TagPredicate{Key: "http.url", Operator: TagOperator(100), Value: "/api/traces"}, false},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, testCase.predicate.MatchSpan(span), "%s %s %s", testCase.predicate.Key, testCase.predicate.Operator, testCase.predicate.Value)
+	}
+
+	compiled, err := NewTagPredicate("http.url", TagOpRegex, "traces$")
+	require.NoError(t, err)
+	assert.True(t, compiled.MatchSpan(span))
+}
+
+func TestTagPredicateGroupMatchSpan(t *testing.T) {
+	span := &model.Span{
+		Tags:    model.KeyValues{model.Int64("http.status_code", 503)},
+		Process: &model.Process{},
+	}
+	serverError := TagPredicate{Key: "http.status_code", Operator: TagOpGreaterOrEqual, Value: "500"}
+	hasError := TagPredicate{Key: "error", Operator: TagOpExists}
+
+	assert.True(t, TagPredicateGroup{hasError, serverError}.MatchSpan(span))
+	assert.False(t, TagPredicateGroup{hasError}.MatchSpan(span))
+	assert.False(t, TagPredicateGroup{}.MatchSpan(span))
+
+	query := &TraceQueryParameters{TagPredicates: []TagPredicateGroup{{hasError, serverError}}}
+	assert.True(t, MatchSpan(query, span))
+	query.TagPredicates = append(query.TagPredicates, TagPredicateGroup{hasError})
+	assert.False(t, MatchSpan(query, span))
+}
+
+func TestTagOperatorString(t *testing.T) {
+	assert.Equal(t, ">=", TagOpGreaterOrEqual.String())
+	assert.Equal(t, "TagOperator(-1)", TagOperator(-1).String())
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// TracePredicates are conditions on a trace as a whole, as opposed to the span-level parameters
+// of TraceQueryParameters that a single span must satisfy. Zero minimums and nil maximums mean no condition,
+// while a zero maximum, e.g. MaxErrorCount, requires none.
+type TracePredicates struct {
+	MinSpanCount int
+	MaxSpanCount *int
+	// MinDepth and MaxDepth bound the number of spans on the longest path from a root span to a leaf.
+	MinDepth int
+	MaxDepth *int
+	// MinErrorCount and MaxErrorCount bound the number of spans with the tag error=true.
+	MinErrorCount int
+	MaxErrorCount *int
+	// Services must all have emitted at least one span of the trace.
+	Services []string
+	// RootTagPredicates must all be satisfied by the same root span, i.e. a span whose parent is not in the trace.
+	RootTagPredicates []TagPredicateGroup
+}
+
+// TracePredicateSupport is implemented by Readers that evaluate TracePredicates natively.
+// QueryService evaluates the predicates that a Reader does not support over the traces
+// found by the span-level parameters of the query.
+type TracePredicateSupport interface {
+	SupportsTracePredicates(predicates *TracePredicates) bool
+}
+
+// MatchTrace returns true if the trace satisfies all predicates. Nil predicates match any trace.
+func (p *TracePredicates) MatchTrace(trace *model.Trace) bool {
+	if p == nil {
+		return true
+	}
+	spanCount := len(trace.Spans)
+	if p.MinSpanCount != 0 && spanCount < p.MinSpanCount {
+		return false
+	}
+	if p.MaxSpanCount != nil && spanCount > *p.MaxSpanCount {
+		return false
+	}
+	if p.MinErrorCount != 0 || p.MaxErrorCount != nil {
+		errorCount := countErrors(trace)
+		if p.MinErrorCount != 0 && errorCount < p.MinErrorCount {
+			return false
+		}
+		if p.MaxErrorCount != nil && errorCount > *p.MaxErrorCount {
+			return false
+		}
+	}
+	if len(p.Services) > 0 && !involvesServices(trace, p.Services) {
+		return false
+	}
+	if p.MinDepth == 0 && p.MaxDepth == nil && len(p.RootTagPredicates) == 0 {
+		return true
+	}
+	tree := model.NewSpanTree(trace.Spans)
+	if p.MinDepth != 0 || p.MaxDepth != nil {
+		depth := treeDepth(tree)
+		if p.MinDepth != 0 && depth < p.MinDepth {
+			return false
+		}
+		if p.MaxDepth != nil && depth > *p.MaxDepth {
+			return false
+		}
+	}
+	if len(p.RootTagPredicates) > 0 {
+		for _, root := range tree.Roots() {
+			if matchAllGroups(flattenTags(root), p.RootTagPredicates) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchAllGroups(kvs model.KeyValues, groups []TagPredicateGroup) bool {
+	for _, group := range groups {
+		if !group.matchKeyValues(kvs) {
+			return false
+		}
+	}
+	return true
+}
+
+func countErrors(trace *model.Trace) int {
+	count := 0
+	for _, span := range trace.Spans {
+		if span.IsError() {
+			count++
+		}
+	}
+	return count
+}
+
+func involvesServices(trace *model.Trace, services []string) bool {
+	involved := make(map[string]struct{})
+	for _, span := range trace.Spans {
+		if span.Process != nil {
+			involved[span.Process.ServiceName] = struct{}{}
+		}
+	}
+	for _, service := range services {
+		if _, ok := involved[service]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// treeDepth returns the number of spans on the longest path from a root to a leaf.
+// Spans that are only reachable through a cycle of parent references are ignored.
+func treeDepth(tree *model.SpanTree) int {
+	maxDepth := 0
+	visited := make(map[model.SpanID]bool)
+	type entry struct {
+		spanID model.SpanID
+		depth  int
+	}
+	var stack []entry
+	for _, root := range tree.Roots() {
+		stack = append(stack, entry{spanID: root.SpanID, depth: 1})
+	}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[current.spanID] {
+			continue
+		}
+		visited[current.spanID] = true
+		if current.depth > maxDepth {
+			maxDepth = current.depth
+		}
+		for _, child := range tree.Children(current.spanID) {
+			stack = append(stack, entry{spanID: child.SpanID, depth: current.depth + 1})
+		}
+	}
+	return maxDepth
+}
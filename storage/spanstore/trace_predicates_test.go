@@ -0,0 +1,102 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestTracePredicatesMatchTrace(t *testing.T) {
+	traceID := model.NewTraceID(0, 1)
+	makeSpan := func(spanID, parentID uint64, service string, tags ...model.KeyValue) *model.Span {
+		span := &model.Span{
+			TraceID: traceID,
+			SpanID:  model.NewSpanID(spanID),
+			Tags:    tags,
+			Process: &model.Process{ServiceName: service},
+		}
+		if parentID != 0 {
+			span.References = []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(parentID))}
+		}
+		return span
+	}
+	// frontend(1, error) -> api(2) -> db(3, error)
+	//                    -> api(4)
+	trace := &model.Trace{Spans: []*model.Span{
+		makeSpan(1, 0, "frontend", model.Bool("error", true), model.String("http.method", "GET")),
+		makeSpan(2, 1, "api"),
+		makeSpan(3, 2, "db", model.String("error", "true")),
+		makeSpan(4, 1, "api", model.Bool("error", false)),
+	}}
+	getMethod := TagPredicateGroup{{Key: "http.method", Operator: TagOpEqual, Value: "GET"}}
+	hasError := TagPredicateGroup{{Key: "error", Operator: TagOpEqual, Value: "true"}}
+	postMethod := TagPredicateGroup{{Key: "http.method", Operator: TagOpEqual, Value: "POST"}}
+
+	testCases := []struct {
+		name       string
+		predicates *TracePredicates
+		expected   bool
+	}{
+		{name: "nil", predicates: nil, expected: true},
+		{name: "empty", predicates: &TracePredicates{}, expected: true},
+		{name: "min spans", predicates: &TracePredicates{MinSpanCount: 4}, expected: true},
+		{name: "min spans not met", predicates: &TracePredicates{MinSpanCount: 5}, expected: false},
+		{name: "max spans exceeded", predicates: &TracePredicates{MaxSpanCount: intPtr(3)}, expected: false},
+		{name: "depth", predicates: &TracePredicates{MinDepth: 3, MaxDepth: intPtr(3)}, expected: true},
+		{name: "min depth not met", predicates: &TracePredicates{MinDepth: 4}, expected: false},
+		{name: "max depth exceeded", predicates: &TracePredicates{MaxDepth: intPtr(2)}, expected: false},
+		{name: "errors", predicates: &TracePredicates{MinErrorCount: 2, MaxErrorCount: intPtr(2)}, expected: true},
+		{name: "min errors not met", predicates: &TracePredicates{MinErrorCount: 3}, expected: false},
+		{name: "max errors exceeded", predicates: &TracePredicates{MaxErrorCount: intPtr(1)}, expected: false},
+		{name: "no errors", predicates: &TracePredicates{MaxErrorCount: intPtr(0)}, expected: false},
+		{name: "services", predicates: &TracePredicates{Services: []string{"frontend", "db"}}, expected: true},
+		{name: "missing service", predicates: &TracePredicates{Services: []string{"frontend", "cache"}}, expected: false},
+		{name: "root tags", predicates: &TracePredicates{RootTagPredicates: []TagPredicateGroup{getMethod, hasError}}, expected: true},
+		{name: "root tags not matched", predicates: &TracePredicates{RootTagPredicates: []TagPredicateGroup{hasError, postMethod}}, expected: false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.predicates.MatchTrace(trace))
+		})
+	}
+}
+
+func TestTracePredicatesDepthWithCycle(t *testing.T) {
+	traceID := model.NewTraceID(0, 1)
+	trace := &model.Trace{Spans: []*model.Span{
+		{TraceID: traceID, SpanID: model.NewSpanID(1), Process: &model.Process{}},
+		{
+			TraceID:    traceID,
+			SpanID:     model.NewSpanID(2),
+			References: []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(3))},
+			Process:    &model.Process{},
+		},
+		{
+			TraceID:    traceID,
+			SpanID:     model.NewSpanID(3),
+			References: []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(2))},
+			Process:    &model.Process{},
+		},
+	}}
+	assert.Equal(t, 1, treeDepth(model.NewSpanTree(trace.Spans)))
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2020 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// TraceStreamer is implemented by Readers that read the spans of a trace in batches, so that a large trace
+// does not have to be held in memory to be sent, and can be read partially.
+type TraceStreamer interface {
+	// StreamTrace calls fn with successive parts of the trace, until its spans are exhausted or fn returns false.
+	// It returns ErrTraceNotFound, without calling fn, if the trace has no spans.
+	StreamTrace(ctx context.Context, traceID model.TraceID, fn func(part *model.Trace) bool) error
+}
+
+// StreamTrace streams the trace from the reader if it is a TraceStreamer, or else passes fn the whole trace.
+func StreamTrace(ctx context.Context, reader Reader, traceID model.TraceID, fn func(part *model.Trace) bool) error {
+	if streamer, ok := reader.(TraceStreamer); ok {
+		return streamer.StreamTrace(ctx, traceID, fn)
+	}
+	trace, err := reader.GetTrace(ctx, traceID)
+	if err != nil {
+		return err
+	}
+	if trace == nil || len(trace.Spans) == 0 {
+		return ErrTraceNotFound
+	}
+	fn(trace)
+	return nil
+}